@@ -25,6 +25,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	"knative.dev/client/pkg/i18n"
+	kncommands "knative.dev/client/pkg/kn/commands"
 	"knative.dev/client/pkg/kn/config"
 	"knative.dev/client/pkg/kn/plugin"
 	"knative.dev/client/pkg/kn/root"
@@ -64,10 +66,17 @@ func run(args []string) error {
 		return err
 	}
 
+	// Select the language of the messages before any of them is printed
+	err = i18n.SetLanguage(config.GlobalConfig.Language())
+	if err != nil {
+		return err
+	}
+
 	pluginManager := plugin.NewManager(config.GlobalConfig.PluginsDir(), config.GlobalConfig.LookupPluginsInPath())
 
 	// Create kn root command and all sub-commands
-	rootCmd, err := root.NewRootCommand(pluginManager.HelpTemplateFuncs())
+	params := &kncommands.KnParams{}
+	rootCmd, err := root.NewRootCommand(pluginManager.HelpTemplateFuncs(), root.WithParams(params))
 	if err != nil {
 		return err
 	}
@@ -94,6 +103,12 @@ func run(args []string) error {
 		if err != nil {
 			return err
 		}
+		if kncommands.IsNonInteractive(rootCmd.Flags()) {
+			err = kncommands.DisableStdin()
+			if err != nil {
+				return err
+			}
+		}
 
 		err := plugin.Execute(argsWithoutCommands(args, plugin.CommandParts()))
 		if err != nil {
@@ -107,7 +122,14 @@ func run(args []string) error {
 			return err
 		}
 		// Execute kn root command, args are taken from os.Args directly
-		return rootCmd.Execute()
+		err = rootCmd.Execute()
+		// Report the API server's warnings also when the command failed, as they often
+		// explain the failure. Cobra doesn't run post-run hooks of failed commands.
+		warningsErr := params.ReportWarnings(rootCmd.ErrOrStderr())
+		if err != nil {
+			return err
+		}
+		return warningsErr
 	}
 }
 
@@ -17,7 +17,11 @@ package main
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -301,3 +305,41 @@ func TestRun(t *testing.T) {
 	assert.NilError(t, err)
 	assert.Assert(t, util.ContainsAllIgnoreCase(out, "version", "build", "git"))
 }
+
+func TestRunReportsWarningsOfFailedCommand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Add("Warning", `299 - "policy: services of team-a must not be deleted"`)
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Forbidden","code":403,`+
+			`"message":"admission webhook denied the request"}`)
+	}))
+	defer server.Close()
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	assert.NilError(t, ioutil.WriteFile(kubeconfig, []byte(fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: %s
+  name: test
+contexts:
+- context:
+    cluster: test
+    namespace: default
+  name: test
+current-context: test
+`, server.URL)), 0600))
+
+	oldArgs := os.Args
+	os.Args = []string{"kn", "--config", "/no/config/please.yaml", "--kubeconfig", kubeconfig, "service", "delete", "foo"}
+	defer (func() {
+		os.Args = oldArgs
+	})()
+
+	capture := test.CaptureOutput(t)
+	err := run(os.Args[1:])
+	_, errOut := capture.Close()
+
+	assert.ErrorContains(t, err, "admission webhook denied the request")
+	assert.Assert(t, util.ContainsAll(errOut, "WARNING: the API server returned a warning: policy: services of team-a must not be deleted"))
+}
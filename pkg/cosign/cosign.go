@@ -0,0 +1,100 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cosign wraps the cosign CLI for verifying image signatures
+// and fetching attestations. There is no cosign library in kn's
+// dependencies, so the cosign binary needs to be installed.
+package cosign
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Command is the name of the cosign binary which is looked up in the PATH
+const Command = "cosign"
+
+// Payload type of attestations which hold an in-toto statement
+const inTotoPayloadType = "application/vnd.in-toto+json"
+
+// Run runs cosign with the given arguments and returns its standard output,
+// can be replaced in tests
+var Run = func(args ...string) ([]byte, error) {
+	cmd := exec.Command(Command, args...)
+	output, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return nil, fmt.Errorf("%v: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot run %s (it needs to be installed): %v", Command, err)
+	}
+	return output, nil
+}
+
+// Statement is the in-toto statement of an attestation
+type Statement struct {
+	Type          string          `json:"_type"`
+	PredicateType string          `json:"predicateType"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+// envelope is the DSSE envelope in which cosign stores attestations
+type envelope struct {
+	PayloadType string `json:"payloadType"`
+	Payload     string `json:"payload"`
+}
+
+// DownloadAttestations returns the statements of all attestations attached to the given image.
+// The signatures of the attestations are not verified.
+func DownloadAttestations(image string) ([]Statement, error) {
+	output, err := Run("download", "attestation", image)
+	if err != nil {
+		return nil, err
+	}
+
+	var statements []Statement
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	// SBOMs can get big
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var env envelope
+		err := json.Unmarshal(line, &env)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse attestation of image %s: %v", image, err)
+		}
+		if env.PayloadType != inTotoPayloadType {
+			continue
+		}
+		payload, err := base64.StdEncoding.DecodeString(env.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode attestation of image %s: %v", image, err)
+		}
+		var statement Statement
+		err = json.Unmarshal(payload, &statement)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse attestation statement of image %s: %v", image, err)
+		}
+		statements = append(statements, statement)
+	}
+	return statements, scanner.Err()
+}
@@ -22,6 +22,7 @@ import (
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
+	"knative.dev/eventing/pkg/apis/eventing"
 	v1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	"knative.dev/eventing/pkg/client/clientset/versioned/scheme"
 	client_v1beta1 "knative.dev/eventing/pkg/client/clientset/versioned/typed/eventing/v1beta1"
@@ -244,22 +245,16 @@ func (c *knEventingClient) DeleteBroker(name string, timeout time.Duration) erro
 	if timeout == 0 {
 		return c.deleteBroker(name, apis_v1.DeletePropagationBackground)
 	}
-	waitC := make(chan error)
 	watcher, err := c.WatchBroker(name, timeout)
 	if err != nil {
-		return nil
+		return err
 	}
 	defer watcher.Stop()
-	go func() {
-		waitForEvent := wait.NewWaitForEvent("broker", func(evt *watch.Event) bool { return evt.Type == watch.Deleted })
-		err, _ := waitForEvent.Wait(watcher, name, wait.Options{Timeout: &timeout}, wait.NoopMessageCallback())
-		waitC <- err
-	}()
 	err = c.deleteBroker(name, apis_v1.DeletePropagationForeground)
 	if err != nil {
 		return err
 	}
-	return <-waitC
+	return wait.WaitForDeletion(watcher, "broker", name, timeout)
 }
 
 // deleteBroker is used to delete an instance of broker
@@ -315,6 +310,24 @@ func (b *BrokerBuilder) Namespace(ns string) *BrokerBuilder {
 	return b
 }
 
+// Class sets the broker class annotation, selecting the broker implementation
+func (b *BrokerBuilder) Class(class string) *BrokerBuilder {
+	if class == "" {
+		return b
+	}
+	if b.broker.Annotations == nil {
+		b.broker.Annotations = make(map[string]string)
+	}
+	b.broker.Annotations[eventing.BrokerClassKey] = class
+	return b
+}
+
+// Config sets the reference to the configuration backing the broker
+func (b *BrokerBuilder) Config(config *duckv1.KReference) *BrokerBuilder {
+	b.broker.Spec.Config = config
+	return b
+}
+
 // Build to return an instance of broker object
 func (b *BrokerBuilder) Build() *v1beta1.Broker {
 	return b.broker
@@ -191,6 +191,16 @@ func TestTriggerBuilder(t *testing.T) {
 
 }
 
+func TestBrokerBuilder(t *testing.T) {
+	config := &duckv1.KReference{Kind: "ConfigMap", APIVersion: "v1", Name: "kafka-broker-config", Namespace: "knative-eventing"}
+	broker := NewBrokerBuilder("foo").Class("Kafka").Config(config).Build()
+	assert.Equal(t, broker.Annotations["eventing.knative.dev/broker.class"], "Kafka")
+	assert.DeepEqual(t, broker.Spec.Config, config)
+
+	broker = NewBrokerBuilder("foo").Class("").Build()
+	assert.Assert(t, broker.Annotations == nil)
+}
+
 func TestBrokerCreate(t *testing.T) {
 	var name = "broker"
 	server, client := setup()
@@ -0,0 +1,48 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+// catalogDe holds the German translations
+var catalogDe = map[string]string{
+	// Verbs inserted into the messages of the service commands
+	"Creating":  "Erstelle",
+	"created":   "erstellt",
+	"Replacing": "Ersetze",
+	"replaced":  "ersetzt",
+	"Importing": "Importiere",
+	"imported":  "importiert",
+	"Applying":  "Übernehme",
+	"applied":   "übernommen",
+	"updated":   "aktualisiert",
+
+	// Service commands
+	"%s service '%s' in namespace '%s':\n":                                          "%s Service '%s' im Namespace '%s':\n",
+	"Service '%s' %s in namespace '%s'.\n":                                          "Service '%[1]s' im Namespace '%[3]s' %[2]s.\n",
+	"Service '%s' %s in namespace '%s' of context '%s'.\n":                          "Service '%[1]s' im Namespace '%[3]s' des Kontexts '%[4]s' %[2]s.\n",
+	"Updating Service '%s' in namespace '%s':\n":                                    "Aktualisiere Service '%s' im Namespace '%s':\n",
+	"Service '%s' updated in namespace '%s'.\n":                                     "Service '%s' im Namespace '%s' aktualisiert.\n",
+	"Service '%s' successfully deleted in namespace '%s'.\n":                        "Service '%s' im Namespace '%s' erfolgreich gelöscht.\n",
+	"Service '%s' with latest revision '%s' (unchanged) is available at URL:\n%s\n": "Service '%s' mit der neuesten Revision '%s' (unverändert) ist erreichbar unter der URL:\n%s\n",
+	"Service '%s' %s to latest revision '%s' is available at URL:\n%s\n":            "Service '%[1]s' wurde auf die neueste Revision '%[3]s' %[2]s und ist erreichbar unter der URL:\n%[4]s\n",
+	"\nConditions of service '%s' in namespace '%s':\n":                             "\nConditions des Service '%s' im Namespace '%s':\n",
+
+	// Waiting for resources
+	"Condition %s is True.":                                       "Condition %s ist erfüllt.",
+	"Ready to serve.":                                             "Bereit.",
+	"timeout: %s '%s' not ready after %d seconds":                 "Zeitüberschreitung: %s '%s' ist nach %d Sekunden nicht bereit",
+	"timeout: %s '%s' not deleted after %d seconds":               "Zeitüberschreitung: %s '%s' ist nach %d Sekunden nicht gelöscht",
+	"timeout: %s '%s' not in the expected state after %d seconds": "Zeitüberschreitung: %s '%s' ist nach %d Sekunden nicht im erwarteten Zustand",
+	"timeout: condition %s of %s '%s' not true after %d seconds":  "Zeitüberschreitung: Condition %s von %s '%s' ist nach %d Sekunden nicht erfüllt",
+}
@@ -0,0 +1,48 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+// catalogZh holds the Chinese (simplified) translations
+var catalogZh = map[string]string{
+	// Verbs inserted into the messages of the service commands
+	"Creating":  "创建",
+	"created":   "创建",
+	"Replacing": "替换",
+	"replaced":  "替换",
+	"Importing": "导入",
+	"imported":  "导入",
+	"Applying":  "应用",
+	"applied":   "应用",
+	"updated":   "更新",
+
+	// Service commands
+	"%s service '%s' in namespace '%s':\n":                                          "正在%[1]s命名空间 '%[3]s' 中的服务 '%[2]s'：\n",
+	"Service '%s' %s in namespace '%s'.\n":                                          "命名空间 '%[3]s' 中的服务 '%[1]s' 已%[2]s。\n",
+	"Service '%s' %s in namespace '%s' of context '%s'.\n":                          "上下文 '%[4]s' 的命名空间 '%[3]s' 中的服务 '%[1]s' 已%[2]s。\n",
+	"Updating Service '%s' in namespace '%s':\n":                                    "正在更新命名空间 '%[2]s' 中的服务 '%[1]s'：\n",
+	"Service '%s' updated in namespace '%s'.\n":                                     "命名空间 '%[2]s' 中的服务 '%[1]s' 已更新。\n",
+	"Service '%s' successfully deleted in namespace '%s'.\n":                        "已成功删除命名空间 '%[2]s' 中的服务 '%[1]s'。\n",
+	"Service '%s' with latest revision '%s' (unchanged) is available at URL:\n%s\n": "服务 '%s' 的最新修订版本 '%s'（未更改）可通过以下 URL 访问：\n%s\n",
+	"Service '%s' %s to latest revision '%s' is available at URL:\n%s\n":            "服务 '%[1]s' 已%[2]s到最新修订版本 '%[3]s'，可通过以下 URL 访问：\n%[4]s\n",
+	"\nConditions of service '%s' in namespace '%s':\n":                             "\n命名空间 '%[2]s' 中服务 '%[1]s' 的状态条件：\n",
+
+	// Waiting for resources
+	"Condition %s is True.":                                       "条件 %s 已满足。",
+	"Ready to serve.":                                             "已就绪。",
+	"timeout: %s '%s' not ready after %d seconds":                 "超时：%s '%s' 在 %d 秒后仍未就绪",
+	"timeout: %s '%s' not deleted after %d seconds":               "超时：%s '%s' 在 %d 秒后仍未删除",
+	"timeout: %s '%s' not in the expected state after %d seconds": "超时：%s '%s' 在 %d 秒后仍未达到预期状态",
+	"timeout: condition %s of %s '%s' not true after %d seconds":  "超时：%[2]s '%[3]s' 的条件 %[1]s 在 %[4]d 秒后仍未满足",
+}
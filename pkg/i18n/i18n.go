@@ -0,0 +1,113 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package i18n translates the user-facing messages of kn.
+//
+// Messages are looked up by their English text, so that a message without
+// a translation is printed in English. Translated formats can reorder their
+// arguments with explicit indexes like "%[2]s".
+package i18n
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Languages with a message catalog
+const (
+	// English is the language of the messages in the source and the default
+	English = "en"
+	German  = "de"
+	Chinese = "zh"
+)
+
+// catalogs maps a language to its translations, keyed by the English message
+var catalogs = map[string]map[string]string{
+	English: {},
+	German:  catalogDe,
+	Chinese: catalogZh,
+}
+
+// current is the catalog of the selected language
+var current = catalogs[English]
+
+// language is the selected language
+var language = English
+
+// Languages returns the supported languages, sorted
+func Languages() []string {
+	ret := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		ret = append(ret, lang)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// SetLanguage selects the language of the messages. Locale names like
+// "de_DE.UTF-8" or "zh-CN" select their language, an empty string selects
+// English. An unsupported language is an error.
+func SetLanguage(lang string) error {
+	normalized := normalize(lang)
+	catalog, ok := catalogs[normalized]
+	if !ok {
+		return fmt.Errorf("unsupported language '%s', must be one of %v", lang, Languages())
+	}
+	language = normalized
+	current = catalog
+	return nil
+}
+
+// Language returns the selected language
+func Language() string {
+	return language
+}
+
+// T returns the translation of the given English message, or the message
+// itself if it has no translation in the selected language
+func T(message string) string {
+	if translated, ok := current[message]; ok {
+		return translated
+	}
+	return message
+}
+
+// Sprintf formats the translation of the given English format
+func Sprintf(format string, a ...interface{}) string {
+	return fmt.Sprintf(T(format), a...)
+}
+
+// Errorf returns an error with the translation of the given English format
+func Errorf(format string, a ...interface{}) error {
+	return fmt.Errorf(T(format), a...)
+}
+
+// Fprintf writes the translation of the given English format to w
+func Fprintf(w io.Writer, format string, a ...interface{}) (int, error) {
+	return fmt.Fprintf(w, T(format), a...)
+}
+
+// normalize reduces a locale name to its language
+func normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if lang == "" || lang == "c" || lang == "posix" {
+		return English
+	}
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
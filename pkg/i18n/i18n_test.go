@@ -0,0 +1,101 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestSetLanguage(t *testing.T) {
+	defer SetLanguage(English)
+
+	for _, tc := range []struct {
+		lang     string
+		expected string
+	}{
+		{"", English},
+		{"C", English},
+		{"en", English},
+		{"de", German},
+		{"de_DE.UTF-8", German},
+		{"ZH", Chinese},
+		{"zh-CN", Chinese},
+	} {
+		assert.NilError(t, SetLanguage(tc.lang))
+		assert.Equal(t, Language(), tc.expected, "language %q", tc.lang)
+	}
+
+	err := SetLanguage("fr_FR")
+	assert.ErrorContains(t, err, "unsupported language 'fr_FR'")
+	assert.ErrorContains(t, err, "[de en zh]")
+	assert.Equal(t, Language(), Chinese)
+}
+
+func TestTranslate(t *testing.T) {
+	defer SetLanguage(English)
+
+	assert.Equal(t, T("Ready to serve."), "Ready to serve.")
+
+	assert.NilError(t, SetLanguage(German))
+	assert.Equal(t, T("Ready to serve."), "Bereit.")
+	assert.Equal(t, T("no translation"), "no translation")
+	assert.Equal(t, Sprintf("Service '%s' %s in namespace '%s'.\n", "foo", T("created"), "bar"),
+		"Service 'foo' im Namespace 'bar' erstellt.\n")
+
+	assert.NilError(t, SetLanguage(Chinese))
+	out := &bytes.Buffer{}
+	_, err := Fprintf(out, "Service '%s' updated in namespace '%s'.\n", "foo", "bar")
+	assert.NilError(t, err)
+	assert.Equal(t, out.String(), "命名空间 'bar' 中的服务 'foo' 已更新。\n")
+}
+
+var verbRegexp = regexp.MustCompile(`%[sd]`)
+
+// TestCatalogs checks that all catalogs translate the same messages and
+// that every translation consumes the arguments of its English format
+func TestCatalogs(t *testing.T) {
+	for lang, catalog := range catalogs {
+		if lang == English {
+			continue
+		}
+		for other, otherCatalog := range catalogs {
+			if other == English {
+				continue
+			}
+			for message := range catalog {
+				_, ok := otherCatalog[message]
+				assert.Assert(t, ok, "message %q of %s missing in %s", message, lang, other)
+			}
+		}
+		for message, translated := range catalog {
+			args := []interface{}{}
+			for _, verb := range verbRegexp.FindAllString(message, -1) {
+				if verb == "%d" {
+					args = append(args, 42)
+				} else {
+					args = append(args, "arg")
+				}
+			}
+			result := fmt.Sprintf(translated, args...)
+			assert.Assert(t, !strings.Contains(result, "%!"), "%s translation of %q: %s", lang, message, result)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/cli-runtime/pkg/kustomize"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/kustomize/pkg/fs"
+
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/kn/commands/service"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+)
+
+var applyExample = `
+  # Apply the Knative services of the kustomization in directory 'overlays/prod'
+  kn apply -k overlays/prod
+
+  # Apply them to namespace 'staging' without waiting for them to become ready
+  kn apply -k overlays/staging -n staging --no-wait`
+
+// NewApplyCommand returns a new command for applying the Knative resources of a kustomization
+func NewApplyCommand(p *commands.KnParams) *cobra.Command {
+	var kustomization string
+	var waitFlags commands.WaitFlags
+
+	applyCmd := &cobra.Command{
+		Use:   "apply -k DIRECTORY",
+		Short: "Apply the Knative services of a kustomization",
+		Long: `Apply the Knative services of a kustomization
+
+The kustomization is rendered like with 'kustomize build', the services in the rendered
+manifests are applied like with 'kn service apply' and waited for one after the other.
+Other resources, like ConfigMaps, are skipped and have to be applied with kubectl.
+Services without namespace are applied to the namespace given with --namespace.`,
+		Example: applyExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return errors.New("'kn apply' takes no arguments, the kustomization directory is given with --kustomize")
+			}
+			if kustomization == "" {
+				return errors.New("'kn apply' requires a kustomization directory given with --kustomize")
+			}
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+
+			services, err := renderServices(kustomization, cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+			if len(services) == 0 {
+				return fmt.Errorf("no Knative services found in kustomization '%s'", kustomization)
+			}
+
+			clients := map[string]clientservingv1.KnServingClient{}
+			for _, svc := range services {
+				if svc.Namespace == "" {
+					svc.Namespace = namespace
+				}
+				client, ok := clients[svc.Namespace]
+				if !ok {
+					client, err = p.NewServingClient(svc.Namespace)
+					if err != nil {
+						return err
+					}
+					clients[svc.Namespace] = client
+				}
+				err = service.ApplyDeclaration(cmd, client, svc, waitFlags)
+				if err != nil {
+					return fmt.Errorf("cannot apply service '%s' in namespace '%s': %v", svc.Name, svc.Namespace, err)
+				}
+			}
+			return nil
+		},
+	}
+	commands.AddNamespaceFlags(applyCmd.Flags(), false)
+	applyCmd.Flags().StringVarP(&kustomization, "kustomize", "k", "", "Directory with the kustomization.yaml to render and apply.")
+	applyCmd.MarkFlagDirname("kustomize")
+	waitFlags.AddConditionWaitFlags(applyCmd, commands.WaitDefaultTimeout, "apply", "service", "ready")
+	waitFlags.AddRoutePropagationFlags(applyCmd)
+	waitFlags.AddProgressFlags(applyCmd)
+	waitFlags.AddVerboseErrorsFlag(applyCmd, p)
+	return applyCmd
+}
+
+// renderServices runs the kustomization in the given directory and returns the Knative services of the
+// rendered manifests. A note is printed for every other resource, which is skipped.
+func renderServices(dir string, out io.Writer) ([]*servingv1.Service, error) {
+	var rendered bytes.Buffer
+	err := kustomize.RunKustomizeBuild(&rendered, fs.MakeRealFS(), dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot render kustomization '%s': %v", dir, err)
+	}
+
+	var services []*servingv1.Service
+	decoder := yaml.NewYAMLOrJSONDecoder(&rendered, 4096)
+	for {
+		var obj unstructured.Unstructured
+		err := decoder.Decode(&obj.Object)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if obj.Object == nil {
+			continue
+		}
+		gvk := obj.GroupVersionKind()
+		if gvk.Group != servingv1.SchemeGroupVersion.Group || gvk.Kind != "Service" {
+			fmt.Fprintf(out, "Skipping %s '%s', only Knative services are applied.\n", gvk.Kind, obj.GetName())
+			continue
+		}
+		if gvk.Version != servingv1.SchemeGroupVersion.Version {
+			return nil, fmt.Errorf("service '%s' has the unsupported API version '%s', use '%s'", obj.GetName(), obj.GetAPIVersion(), servingv1.SchemeGroupVersion)
+		}
+		var svc servingv1.Service
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &svc)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read service '%s': %v", obj.GetName(), err)
+		}
+		services = append(services, &svc)
+	}
+	return services, nil
+}
@@ -0,0 +1,156 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/clientcmd"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+	"knative.dev/client/pkg/util/mock"
+)
+
+var blankConfig clientcmd.ClientConfig
+
+func init() {
+	var err error
+	blankConfig, err = clientcmd.NewClientConfigFromBytes([]byte(`kind: Config
+version: v1
+users:
+- name: u
+clusters:
+- name: c
+  cluster:
+    server: example.com
+contexts:
+- name: x
+  context:
+    user: u
+    cluster: c
+current-context: x
+`))
+	if err != nil {
+		panic(err)
+	}
+}
+
+var kustomizationFiles = map[string]string{
+	"kustomization.yaml": `
+namePrefix: prod-
+commonLabels:
+  env: prod
+resources:
+- service.yaml
+- configmap.yaml
+`,
+	"service.yaml": `
+apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: hello
+spec:
+  template:
+    spec:
+      containers:
+      - image: knativesamples/helloworld
+`,
+	"configmap.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  key: value
+`,
+}
+
+func executeApplyCommand(client clientservingv1.KnServingClient, args ...string) (string, error) {
+	p := &commands.KnParams{}
+	p.ClientConfig = blankConfig
+	p.NewServingClient = func(namespace string) (clientservingv1.KnServingClient, error) {
+		return client, nil
+	}
+	output := new(bytes.Buffer)
+	cmd := NewApplyCommand(p)
+	cmd.SetArgs(args)
+	cmd.SetOutput(output)
+	err := cmd.Execute()
+	return output.String(), err
+}
+
+func writeKustomization(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "kn-apply")
+	assert.NilError(t, err)
+	for name, content := range files {
+		err = ioutil.WriteFile(filepath.Join(dir, name), []byte(content), os.FileMode(0666))
+		assert.NilError(t, err)
+	}
+	return dir
+}
+
+func TestApplyKustomization(t *testing.T) {
+	dir := writeKustomization(t, kustomizationFiles)
+	defer os.RemoveAll(dir)
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("prod-hello", nil, apierrors.NewNotFound(schema.GroupResource{}, "prod-hello"))
+	r.ApplyService(func(t *testing.T, a interface{}) {
+		service := a.(*servingv1.Service)
+		assert.Equal(t, service.Name, "prod-hello")
+		assert.Equal(t, service.Namespace, "default")
+		assert.Equal(t, service.Labels["env"], "prod")
+		assert.Equal(t, service.Spec.Template.Spec.Containers[0].Image, "knativesamples/helloworld")
+	}, true, nil)
+	r.WaitForService("prod-hello", mock.Any(), mock.Any(), nil, time.Second)
+	r.GetService("prod-hello", &servingv1.Service{}, nil)
+
+	output, err := executeApplyCommand(client, "-k", dir)
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Skipping ConfigMap 'prod-settings'", "only Knative services"))
+	assert.Assert(t, util.ContainsAll(output, "Creating service 'prod-hello' in namespace 'default'", "Service 'prod-hello' created"))
+	r.Validate()
+}
+
+func TestApplyKustomizationErrors(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+
+	_, err := executeApplyCommand(client)
+	assert.ErrorContains(t, err, "requires a kustomization directory")
+
+	_, err = executeApplyCommand(client, "-k", "does-not-exist")
+	assert.ErrorContains(t, err, "cannot render kustomization 'does-not-exist'")
+
+	dir := writeKustomization(t, map[string]string{
+		"kustomization.yaml": "resources:\n- configmap.yaml\n",
+		"configmap.yaml":     kustomizationFiles["configmap.yaml"],
+	})
+	defer os.RemoveAll(dir)
+	_, err = executeApplyCommand(client, "-k", dir)
+	assert.ErrorContains(t, err, "no Knative services found")
+	client.Recorder().Validate()
+}
@@ -18,6 +18,7 @@ package broker
 import (
 	"bytes"
 
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 
 	clientv1beta1 "knative.dev/client/pkg/eventing/v1beta1"
@@ -51,7 +52,14 @@ current-context: x
 }
 
 func executeBrokerCommand(brokerClient clientv1beta1.KnEventingClient, args ...string) (string, error) {
+	return executeBrokerCommandWithKubeClient(brokerClient, commands.NewFakeKubeClient(), args...)
+}
+
+func executeBrokerCommandWithKubeClient(brokerClient clientv1beta1.KnEventingClient, kubeClient kubernetes.Interface, args ...string) (string, error) {
 	knParams := &commands.KnParams{}
+	knParams.NewKubeClient = func() (kubernetes.Interface, error) {
+		return kubeClient, nil
+	}
 	knParams.ClientConfig = blankConfig
 
 	output := new(bytes.Buffer)
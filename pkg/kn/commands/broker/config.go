@@ -0,0 +1,198 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+const (
+	// kafkaBrokerClass is the class of brokers backed by Apache Kafka
+	kafkaBrokerClass = "Kafka"
+	// Default configuration shared by Kafka brokers, created when installing Knative Kafka
+	kafkaDefaultConfigName      = "kafka-broker-config"
+	kafkaDefaultConfigNamespace = "knative-eventing"
+	// Keys of a Kafka broker configuration
+	kafkaBootstrapServersKey      = "bootstrap.servers"
+	kafkaPartitionsKey            = "default.topic.partitions"
+	kafkaReplicationFactorKey     = "default.topic.replication.factor"
+	kafkaDefaultPartitions        = 10
+	kafkaDefaultReplicationFactor = 3
+)
+
+// configFlags holds the flags selecting the configuration which backs a broker
+type configFlags struct {
+	class  string
+	config string
+
+	kafkaBootstrapServers  string
+	kafkaPartitions        int
+	kafkaReplicationFactor int
+}
+
+// add registers the broker configuration flags
+func (f *configFlags) add(flags *pflag.FlagSet) {
+	flags.StringVar(&f.class, "class", "",
+		"Broker class which selects the broker implementation, e.g. 'MTChannelBasedBroker' or 'Kafka'. "+
+			"Uses the cluster's default class if not given.")
+	flags.StringVar(&f.config, "config", "",
+		"Reference to the configuration of the broker as KIND:NAME[:NAMESPACE], with KIND being 'cm' (or 'config-map') "+
+			"for a ConfigMap and 'sc' (or 'secret') for a Secret. The namespace defaults to the broker's namespace. "+
+			"Brokers of class 'Kafka' use the ConfigMap 'knative-eventing/kafka-broker-config' if not given.")
+	flags.StringVar(&f.kafkaBootstrapServers, "kafka-bootstrap-servers", "",
+		"Comma separated list of Kafka bootstrap servers. Creates a ConfigMap '<broker>-kafka-config' "+
+			"for the broker instead of referencing an existing configuration. Requires --class Kafka.")
+	flags.IntVar(&f.kafkaPartitions, "kafka-partitions", 0,
+		fmt.Sprintf("Number of partitions of the broker's Kafka topic (default %d). Requires --kafka-bootstrap-servers.", kafkaDefaultPartitions))
+	flags.IntVar(&f.kafkaReplicationFactor, "kafka-replication-factor", 0,
+		fmt.Sprintf("Replication factor of the broker's Kafka topic (default %d). Requires --kafka-bootstrap-servers.", kafkaDefaultReplicationFactor))
+}
+
+// brokerClass returns the class given by --class, with 'kafka' normalized to the name of the Kafka class
+func (f *configFlags) brokerClass() string {
+	if strings.EqualFold(f.class, kafkaBrokerClass) {
+		return kafkaBrokerClass
+	}
+	return f.class
+}
+
+// validate checks the flag combination, without accessing the cluster
+func (f *configFlags) validate() error {
+	kafkaFlags := f.kafkaBootstrapServers != "" || f.kafkaPartitions != 0 || f.kafkaReplicationFactor != 0
+	if !kafkaFlags {
+		return nil
+	}
+	if f.brokerClass() != kafkaBrokerClass {
+		return fmt.Errorf("the --kafka-* flags can only be used with --class %s", kafkaBrokerClass)
+	}
+	if f.config != "" {
+		return fmt.Errorf("--config cannot be combined with the --kafka-* flags")
+	}
+	if f.kafkaBootstrapServers == "" {
+		return fmt.Errorf("--kafka-partitions and --kafka-replication-factor require --kafka-bootstrap-servers")
+	}
+	if f.kafkaPartitions < 0 || f.kafkaReplicationFactor < 0 {
+		return fmt.Errorf("--kafka-partitions and --kafka-replication-factor must be positive")
+	}
+	return nil
+}
+
+// reference returns the reference to the broker configuration, or nil if the broker has none
+func (f *configFlags) reference(brokerName, namespace string) (*duckv1.KReference, error) {
+	if f.kafkaBootstrapServers != "" {
+		return configMapReference(brokerName+"-kafka-config", namespace), nil
+	}
+	if f.config == "" {
+		if f.brokerClass() == kafkaBrokerClass {
+			return configMapReference(kafkaDefaultConfigName, kafkaDefaultConfigNamespace), nil
+		}
+		return nil, nil
+	}
+	return parseConfigReference(f.config, namespace)
+}
+
+// kafkaConfigMap returns the ConfigMap to create for --kafka-bootstrap-servers, or nil if not requested
+func (f *configFlags) kafkaConfigMap(ref *duckv1.KReference) *corev1.ConfigMap {
+	if f.kafkaBootstrapServers == "" {
+		return nil
+	}
+	partitions := f.kafkaPartitions
+	if partitions == 0 {
+		partitions = kafkaDefaultPartitions
+	}
+	replicationFactor := f.kafkaReplicationFactor
+	if replicationFactor == 0 {
+		replicationFactor = kafkaDefaultReplicationFactor
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ref.Name,
+			Namespace: ref.Namespace,
+		},
+		Data: map[string]string{
+			kafkaBootstrapServersKey:  f.kafkaBootstrapServers,
+			kafkaPartitionsKey:        fmt.Sprint(partitions),
+			kafkaReplicationFactorKey: fmt.Sprint(replicationFactor),
+		},
+	}
+}
+
+// parseConfigReference parses a KIND:NAME[:NAMESPACE] reference as given with --config
+func parseConfigReference(config, namespace string) (*duckv1.KReference, error) {
+	parts := strings.Split(config, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[1] == "" {
+		return nil, fmt.Errorf("invalid --config '%s', expected KIND:NAME[:NAMESPACE]", config)
+	}
+	if len(parts) == 3 && parts[2] != "" {
+		namespace = parts[2]
+	}
+	switch strings.ToLower(parts[0]) {
+	case "cm", "config-map", "configmap":
+		return configMapReference(parts[1], namespace), nil
+	case "sc", "secret":
+		return &duckv1.KReference{Kind: "Secret", APIVersion: "v1", Name: parts[1], Namespace: namespace}, nil
+	default:
+		return nil, fmt.Errorf("invalid --config '%s', kind must be 'cm', 'config-map', 'sc' or 'secret'", config)
+	}
+}
+
+func configMapReference(name, namespace string) *duckv1.KReference {
+	return &duckv1.KReference{Kind: "ConfigMap", APIVersion: "v1", Name: name, Namespace: namespace}
+}
+
+// verifyConfig checks that the referenced configuration exists, and that a Kafka broker's
+// ConfigMap names the bootstrap servers. A configuration which can't be read
+// because of missing permissions is not checked.
+func verifyConfig(client kubernetes.Interface, ref *duckv1.KReference, class string) error {
+	if class == kafkaBrokerClass && ref.Kind != "ConfigMap" {
+		return fmt.Errorf("brokers of class %s need a ConfigMap as config, not a %s", kafkaBrokerClass, ref.Kind)
+	}
+	var data map[string]string
+	var err error
+	switch ref.Kind {
+	case "ConfigMap":
+		var configMap *corev1.ConfigMap
+		configMap, err = client.CoreV1().ConfigMaps(ref.Namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+		if err == nil {
+			data = configMap.Data
+		}
+	case "Secret":
+		_, err = client.CoreV1().Secrets(ref.Namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+	}
+	switch {
+	case apierrors.IsNotFound(err):
+		return fmt.Errorf("broker config %s '%s' not found in namespace '%s'", ref.Kind, ref.Name, ref.Namespace)
+	case apierrors.IsForbidden(err):
+		return nil
+	case err != nil:
+		return err
+	}
+	if class != kafkaBrokerClass {
+		return nil
+	}
+	if data[kafkaBootstrapServersKey] == "" {
+		return fmt.Errorf("broker config ConfigMap '%s' in namespace '%s' has no '%s'", ref.Name, ref.Namespace, kafkaBootstrapServersKey)
+	}
+	return nil
+}
@@ -17,10 +17,12 @@ limitations under the License.
 package broker
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	clientv1beta1 "knative.dev/client/pkg/eventing/v1beta1"
 	"knative.dev/client/pkg/kn/commands"
@@ -31,10 +33,20 @@ var createExample = `
   kn broker create mybroker
 
   # Create a broker 'mybroker' in the 'myproject' namespace
-  kn broker create mybroker --namespace myproject`
+  kn broker create mybroker --namespace myproject
+
+  # Create a Kafka broker 'mybroker' using the cluster wide Kafka configuration
+  kn broker create mybroker --class Kafka
+
+  # Create a Kafka broker 'mybroker' with its own configuration
+  kn broker create mybroker --class Kafka --kafka-bootstrap-servers my-cluster-kafka-bootstrap.kafka:9092 --kafka-replication-factor 1
+
+  # Create a broker 'mybroker' whose configuration is in the ConfigMap 'config-br' of namespace 'knative-eventing'
+  kn broker create mybroker --config cm:config-br:knative-eventing`
 
 // NewBrokerCreateCommand represents command to create new broker instance
 func NewBrokerCreateCommand(p *commands.KnParams) *cobra.Command {
+	var configFlags configFlags
 
 	cmd := &cobra.Command{
 		Use:     "create NAME",
@@ -45,6 +57,9 @@ func NewBrokerCreateCommand(p *commands.KnParams) *cobra.Command {
 				return errors.New("'broker create' requires the broker name given as single argument")
 			}
 			name := args[0]
+			if err := configFlags.validate(); err != nil {
+				return err
+			}
 
 			namespace, err := p.GetNamespace(cmd)
 			if err != nil {
@@ -56,9 +71,31 @@ func NewBrokerCreateCommand(p *commands.KnParams) *cobra.Command {
 				return err
 			}
 
+			class := configFlags.brokerClass()
+			config, err := configFlags.reference(name, namespace)
+			if err != nil {
+				return err
+			}
+			if config != nil {
+				kubeClient, err := p.NewKubeClient()
+				if err != nil {
+					return err
+				}
+				if configMap := configFlags.kafkaConfigMap(config); configMap != nil {
+					_, err = kubeClient.CoreV1().ConfigMaps(namespace).Create(context.TODO(), configMap, metav1.CreateOptions{})
+					if err != nil {
+						return fmt.Errorf("cannot create Kafka config for broker '%s' in namespace '%s' because: %s", name, namespace, err)
+					}
+				} else if err := verifyConfig(kubeClient, config, class); err != nil {
+					return err
+				}
+			}
+
 			brokerBuilder := clientv1beta1.
 				NewBrokerBuilder(name).
-				Namespace(namespace)
+				Namespace(namespace).
+				Class(class).
+				Config(config)
 
 			err = eventingClient.CreateBroker(brokerBuilder.Build())
 			if err != nil {
@@ -71,5 +108,6 @@ func NewBrokerCreateCommand(p *commands.KnParams) *cobra.Command {
 		},
 	}
 	commands.AddNamespaceFlags(cmd.Flags(), false)
+	configFlags.add(cmd.Flags())
 	return cmd
 }
@@ -17,11 +17,17 @@ limitations under the License.
 package broker
 
 import (
+	"context"
 	"testing"
 
 	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 
 	clienteventingv1beta1 "knative.dev/client/pkg/eventing/v1beta1"
+	"knative.dev/client/pkg/kn/commands"
 	"knative.dev/client/pkg/util"
 )
 
@@ -49,3 +55,100 @@ func TestBrokerCreateWithError(t *testing.T) {
 	assert.ErrorContains(t, err, "broker create")
 	assert.Assert(t, util.ContainsAll(err.Error(), "broker create", "requires", "name", "argument"))
 }
+
+func TestBrokerCreateWithClassAndConfig(t *testing.T) {
+	kubeClient := commands.NewFakeKubeClient(
+		newConfigMap("config-br", "knative-eventing", nil),
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "broker-secret", Namespace: "default"}})
+
+	eventingClient := clienteventingv1beta1.NewMockKnEventingClient(t)
+	eventingRecorder := eventingClient.Recorder()
+	eventingRecorder.CreateBroker(clienteventingv1beta1.NewBrokerBuilder(brokerName).Namespace("default").
+		Class("MTChannelBasedBroker").
+		Config(&duckv1.KReference{Kind: "ConfigMap", APIVersion: "v1", Name: "config-br", Namespace: "knative-eventing"}).Build(), nil)
+	eventingRecorder.CreateBroker(clienteventingv1beta1.NewBrokerBuilder(brokerName).Namespace("default").
+		Config(&duckv1.KReference{Kind: "Secret", APIVersion: "v1", Name: "broker-secret", Namespace: "default"}).Build(), nil)
+
+	out, err := executeBrokerCommandWithKubeClient(eventingClient, kubeClient, "create", brokerName,
+		"--class", "MTChannelBasedBroker", "--config", "cm:config-br:knative-eventing")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(out, "Broker", brokerName, "created"))
+
+	_, err = executeBrokerCommandWithKubeClient(eventingClient, kubeClient, "create", brokerName, "--config", "secret:broker-secret")
+	assert.NilError(t, err)
+
+	eventingRecorder.Validate()
+}
+
+func TestBrokerCreateKafka(t *testing.T) {
+	kubeClient := commands.NewFakeKubeClient(newConfigMap("kafka-broker-config", "knative-eventing",
+		map[string]string{"bootstrap.servers": "my-cluster-kafka-bootstrap.kafka:9092"}))
+
+	eventingClient := clienteventingv1beta1.NewMockKnEventingClient(t)
+	eventingRecorder := eventingClient.Recorder()
+	eventingRecorder.CreateBroker(clienteventingv1beta1.NewBrokerBuilder(brokerName).Namespace("default").Class("Kafka").
+		Config(&duckv1.KReference{Kind: "ConfigMap", APIVersion: "v1", Name: "kafka-broker-config", Namespace: "knative-eventing"}).Build(), nil)
+
+	_, err := executeBrokerCommandWithKubeClient(eventingClient, kubeClient, "create", brokerName, "--class", "kafka")
+	assert.NilError(t, err)
+
+	eventingRecorder.Validate()
+}
+
+func TestBrokerCreateKafkaWithOwnConfig(t *testing.T) {
+	kubeClient := commands.NewFakeKubeClient()
+
+	eventingClient := clienteventingv1beta1.NewMockKnEventingClient(t)
+	eventingRecorder := eventingClient.Recorder()
+	eventingRecorder.CreateBroker(clienteventingv1beta1.NewBrokerBuilder(brokerName).Namespace("default").Class("Kafka").
+		Config(&duckv1.KReference{Kind: "ConfigMap", APIVersion: "v1", Name: brokerName + "-kafka-config", Namespace: "default"}).Build(), nil)
+
+	_, err := executeBrokerCommandWithKubeClient(eventingClient, kubeClient, "create", brokerName,
+		"--class", "Kafka", "--kafka-bootstrap-servers", "kafka:9092", "--kafka-replication-factor", "1")
+	assert.NilError(t, err)
+
+	configMap, err := kubeClient.CoreV1().ConfigMaps("default").Get(context.TODO(), brokerName+"-kafka-config", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, configMap.Data, map[string]string{
+		"bootstrap.servers":                "kafka:9092",
+		"default.topic.partitions":         "10",
+		"default.topic.replication.factor": "1",
+	})
+
+	eventingRecorder.Validate()
+}
+
+func TestBrokerCreateConfigErrors(t *testing.T) {
+	objects := []runtime.Object{
+		newConfigMap("no-servers", "default", map[string]string{"default.topic.partitions": "3"}),
+	}
+	for _, tc := range []struct {
+		args        []string
+		errContents string
+	}{
+		{[]string{"--config", "config-br"}, "expected KIND:NAME[:NAMESPACE]"},
+		{[]string{"--config", "cm:"}, "expected KIND:NAME[:NAMESPACE]"},
+		{[]string{"--config", "svc:config-br"}, "kind must be 'cm', 'config-map', 'sc' or 'secret'"},
+		{[]string{"--config", "cm:config-br"}, "broker config ConfigMap 'config-br' not found in namespace 'default'"},
+		{[]string{"--config", "sc:creds:other"}, "broker config Secret 'creds' not found in namespace 'other'"},
+		{[]string{"--class", "Kafka"}, "broker config ConfigMap 'kafka-broker-config' not found in namespace 'knative-eventing'"},
+		{[]string{"--class", "Kafka", "--config", "cm:no-servers"}, "has no 'bootstrap.servers'"},
+		{[]string{"--class", "Kafka", "--config", "sc:creds"}, "need a ConfigMap as config, not a Secret"},
+		{[]string{"--kafka-bootstrap-servers", "kafka:9092"}, "can only be used with --class Kafka"},
+		{[]string{"--class", "Kafka", "--kafka-partitions", "3"}, "require --kafka-bootstrap-servers"},
+		{[]string{"--class", "Kafka", "--kafka-bootstrap-servers", "kafka:9092", "--config", "cm:config-br"}, "cannot be combined"},
+	} {
+		eventingClient := clienteventingv1beta1.NewMockKnEventingClient(t)
+		args := append([]string{"create", brokerName}, tc.args...)
+		_, err := executeBrokerCommandWithKubeClient(eventingClient, commands.NewFakeKubeClient(objects...), args...)
+		assert.ErrorContains(t, err, tc.errContents)
+		eventingClient.Recorder().Validate()
+	}
+}
+
+func newConfigMap(name, namespace string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Data:       data,
+	}
+}
@@ -35,6 +35,7 @@ func NewChannelCommand(p *commands.KnParams) *cobra.Command {
 	channelCmd.AddCommand(NewChannelDeleteCommand(p))
 	channelCmd.AddCommand(NewChannelDescribeCommand(p))
 	channelCmd.AddCommand(NewChannelListTypesCommand(p))
+	channelCmd.AddCommand(NewChannelPipeCommand(p))
 	return channelCmd
 }
 
@@ -0,0 +1,198 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/clientcmd"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
+	clientv1beta1 "knative.dev/eventing/pkg/client/clientset/versioned/typed/messaging/v1beta1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	knerrors "knative.dev/client/pkg/errors"
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/kn/commands/flags"
+	knflags "knative.dev/client/pkg/kn/flags"
+	messagingv1beta1 "knative.dev/client/pkg/messaging/v1beta1"
+)
+
+var pipeExample = `
+  # Deliver the events of channel 'orders' to the Knative service 'billing', as subscription 'orders-billing'
+  kn channel pipe orders ksvc:billing
+
+  # Deliver the events of InMemoryChannel 'orders' to 'billing', send its replies to broker 'default'
+  # and the events which couldn't be delivered after 5 retries to service 'dead-letters'
+  kn channel pipe imc:orders billing --reply broker:default --retry 5 --dead-letter-sink ksvc:dead-letters
+
+  # Deliver the events of channel 'orders' to an URI without retries
+  kn channel pipe orders https://example.com/events --name orders-audit --retry 0`
+
+// Characters which aren't allowed in the name of a subscription
+var invalidNameChars = regexp.MustCompile("[^a-z0-9-]+")
+
+// NewChannelPipeCommand returns a new command for subscribing a sink to a channel
+func NewChannelPipeCommand(p *commands.KnParams) *cobra.Command {
+	var (
+		name               string
+		replyFlag, dlsFlag flags.SinkFlags
+		retry              int32
+		backoffPolicy      string
+		backoffDelay       string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "pipe CHANNEL SINK",
+		Short: "Deliver the events of a channel to a sink",
+		Long: "Deliver the events of a channel to a sink by creating a subscription in one step. " +
+			"The subscription is named CHANNEL-SINK unless --name is given, and retries failed deliveries " +
+			"with an exponential backoff. CHANNEL is given like --channel of 'kn subscription create', " +
+			"SINK like its --sink.",
+		Example: pipeExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("'kn channel pipe' requires the channel and the sink given as arguments")
+			}
+			channelRef := knflags.ChannelRef{Cref: args[0]}
+			channel, err := channelRef.Parse()
+			if err != nil {
+				return err
+			}
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			dynamicClient, err := p.NewDynamicClient(namespace)
+			if err != nil {
+				return err
+			}
+			gv, err := schema.ParseGroupVersion(channel.APIVersion)
+			if err != nil {
+				return err
+			}
+			channelResource := gv.WithResource(strings.ToLower(channel.Kind) + "s")
+			_, err = dynamicClient.RawClient().Resource(channelResource).Namespace(namespace).Get(context.TODO(), channel.Name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				return fmt.Errorf("%s '%s' not found in namespace '%s'", channel.Kind, channel.Name, namespace)
+			}
+			if err != nil {
+				return knerrors.GetError(err)
+			}
+
+			var subscriberFlag flags.SinkFlags
+			subscriberFlag.Set(args[1])
+			subscriber, err := subscriberFlag.ResolveSink(dynamicClient, namespace)
+			if err != nil {
+				return err
+			}
+			reply, err := replyFlag.ResolveSink(dynamicClient, namespace)
+			if err != nil {
+				return err
+			}
+			deadLetterSink, err := dlsFlag.ResolveSink(dynamicClient, namespace)
+			if err != nil {
+				return err
+			}
+
+			if name == "" {
+				name = subscriptionName(channel.Name, subscriber)
+			}
+			subscription := messagingv1beta1.NewSubscriptionBuilder(name).
+				Channel(channel).
+				Subscriber(subscriber).
+				Reply(reply).
+				DeadLetterSink(deadLetterSink).
+				Retry(retry, eventingduckv1beta1.BackoffPolicyType(backoffPolicy), backoffDelay).
+				Build()
+			if err := subscription.Spec.Delivery.Validate(context.TODO()); err != nil {
+				return fmt.Errorf("invalid delivery options: %v", err)
+			}
+
+			client, err := newSubscriptionClient(p, cmd)
+			if err != nil {
+				return err
+			}
+			err = client.CreateSubscription(subscription)
+			if err != nil {
+				return knerrors.GetError(err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Subscription '%s' from %s '%s' to %s created in namespace '%s'.\n",
+				name, channel.Kind, channel.Name, args[1], namespace)
+			return nil
+		},
+	}
+	commands.AddNamespaceFlags(cmd.Flags(), false)
+	cmd.Flags().StringVar(&name, "name", "", "Name of the subscription (default CHANNEL-SINK).")
+	replyFlag.AddWithFlagName(cmd, "reply", "")
+	dlsFlag.AddWithFlagName(cmd, "dead-letter-sink", "")
+	cmd.Flags().Int32Var(&retry, "retry", 3, "Number of retries of a failed delivery before the event is given to the dead letter sink, 0 for none.")
+	cmd.Flags().StringVar(&backoffPolicy, "backoff-policy", string(eventingduckv1beta1.BackoffPolicyExponential),
+		"Backoff policy between retries, 'linear' or 'exponential'.")
+	cmd.Flags().StringVar(&backoffDelay, "backoff-delay", "PT0.2S", "Delay before the first retry as ISO 8601 duration.")
+	return cmd
+}
+
+// subscriptionName returns the default name of a subscription from the channel to the subscriber
+func subscriptionName(channel string, subscriber *duckv1.Destination) string {
+	sink := ""
+	if subscriber.Ref != nil {
+		sink = subscriber.Ref.Name
+	} else if subscriber.URI != nil {
+		sink = strings.Split(subscriber.URI.Host, ".")[0]
+	}
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(channel+"-"+sink), "-")
+	if len(name) > 63 {
+		name = name[:63]
+	}
+	return strings.Trim(name, "-")
+}
+
+var subscriptionClientFactory func(config clientcmd.ClientConfig, namespace string) (messagingv1beta1.KnSubscriptionsClient, error)
+
+func newSubscriptionClient(p *commands.KnParams, cmd *cobra.Command) (messagingv1beta1.KnSubscriptionsClient, error) {
+	namespace, err := p.GetNamespace(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscriptionClientFactory != nil {
+		config, err := p.GetClientConfig()
+		if err != nil {
+			return nil, err
+		}
+		return subscriptionClientFactory(config, namespace)
+	}
+
+	clientConfig, err := p.RestConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := clientv1beta1.NewForConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return messagingv1beta1.NewKnMessagingClient(client, namespace).SubscriptionsClient(), nil
+}
@@ -0,0 +1,155 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	messagingv1beta1 "knative.dev/eventing/pkg/apis/messaging/v1beta1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	kndynamic "knative.dev/client/pkg/dynamic"
+	dynamicfake "knative.dev/client/pkg/dynamic/fake"
+	"knative.dev/client/pkg/kn/commands"
+	clientv1beta1 "knative.dev/client/pkg/messaging/v1beta1"
+	"knative.dev/client/pkg/util"
+)
+
+func TestChannelPipe(t *testing.T) {
+	subscriptionClient := clientv1beta1.NewMockKnSubscriptionsClient(t)
+	dynamicClient := dynamicfake.CreateFakeKnDynamicClient("default",
+		newPipeChannel("orders"), newPipeService("billing"), newPipeService("dead-letters"), newPipeBroker("default"))
+
+	recorder := subscriptionClient.Recorder()
+	recorder.CreateSubscription(clientv1beta1.NewSubscriptionBuilder("orders-billing").
+		Channel(channelReference("orders")).
+		Subscriber(pipeDestination("Service", "serving.knative.dev/v1", "billing")).
+		Retry(3, eventingduckv1beta1.BackoffPolicyExponential, "PT0.2S").
+		Build(), nil)
+	recorder.CreateSubscription(clientv1beta1.NewSubscriptionBuilder("audit").
+		Channel(channelReference("orders")).
+		Subscriber(pipeDestination("Service", "serving.knative.dev/v1", "billing")).
+		Reply(pipeDestination("Broker", "eventing.knative.dev/v1beta1", "default")).
+		DeadLetterSink(pipeDestination("Service", "serving.knative.dev/v1", "dead-letters")).
+		Retry(5, eventingduckv1beta1.BackoffPolicyLinear, "PT1S").
+		Build(), nil)
+	recorder.CreateSubscription(clientv1beta1.NewSubscriptionBuilder("orders-example").
+		Channel(channelReference("orders")).
+		Subscriber(&duckv1.Destination{URI: &apis.URL{Scheme: "https", Host: "example.com", Path: "/events"}}).
+		Build(), nil)
+
+	out, err := executeChannelPipeCommand(subscriptionClient, dynamicClient, "pipe", "orders", "ksvc:billing")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(out, "Subscription 'orders-billing' from Channel 'orders' to ksvc:billing created in namespace 'default'"))
+
+	_, err = executeChannelPipeCommand(subscriptionClient, dynamicClient, "pipe", "orders", "billing", "--name", "audit",
+		"--reply", "broker:default", "--dead-letter-sink", "dead-letters",
+		"--retry", "5", "--backoff-policy", "linear", "--backoff-delay", "PT1S")
+	assert.NilError(t, err)
+
+	_, err = executeChannelPipeCommand(subscriptionClient, dynamicClient, "pipe", "orders", "https://example.com/events", "--retry", "0")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+}
+
+func TestChannelPipeErrors(t *testing.T) {
+	subscriptionClient := clientv1beta1.NewMockKnSubscriptionsClient(t)
+	dynamicClient := dynamicfake.CreateFakeKnDynamicClient("default", newPipeChannel("orders"), newPipeService("billing"))
+
+	for _, tc := range []struct {
+		args        []string
+		errContents string
+	}{
+		{[]string{"pipe", "orders"}, "requires the channel and the sink given as arguments"},
+		{[]string{"pipe", "foo::bar", "billing"}, "incorrect value 'foo::bar'"},
+		{[]string{"pipe", "payments", "billing"}, "Channel 'payments' not found in namespace 'default'"},
+		{[]string{"pipe", "orders", "svc:billing"}, "please use prefix 'ksvc' for knative service"},
+		{[]string{"pipe", "orders", "billing", "--backoff-policy", "random"}, "invalid delivery options"},
+		{[]string{"pipe", "orders", "billing", "--backoff-delay", "1s"}, "invalid delivery options"},
+	} {
+		_, err := executeChannelPipeCommand(subscriptionClient, dynamicClient, tc.args...)
+		assert.ErrorContains(t, err, tc.errContents)
+	}
+	subscriptionClient.Recorder().Validate()
+}
+
+func TestSubscriptionName(t *testing.T) {
+	assert.Equal(t, subscriptionName("orders", pipeDestination("Service", "serving.knative.dev/v1", "billing")), "orders-billing")
+	assert.Equal(t, subscriptionName("Orders", &duckv1.Destination{URI: &apis.URL{Host: "audit.example.com:8080"}}), "orders-audit")
+	long := "a-very-long-channel-name-which-is-used-for-orders"
+	assert.Equal(t, subscriptionName(long, pipeDestination("Service", "serving.knative.dev/v1", long)), (long + "-" + long)[:63])
+}
+
+func executeChannelPipeCommand(subscriptionClient clientv1beta1.KnSubscriptionsClient, dynamicClient kndynamic.KnDynamicClient, args ...string) (string, error) {
+	knParams := &commands.KnParams{}
+	knParams.ClientConfig = blankConfig
+
+	output := new(bytes.Buffer)
+	knParams.Output = output
+	knParams.NewDynamicClient = func(namespace string) (kndynamic.KnDynamicClient, error) {
+		return dynamicClient, nil
+	}
+
+	cmd := NewChannelCommand(knParams)
+	cmd.SetArgs(args)
+	cmd.SetOutput(output)
+
+	subscriptionClientFactory = func(config clientcmd.ClientConfig, namespace string) (clientv1beta1.KnSubscriptionsClient, error) {
+		return subscriptionClient, nil
+	}
+	defer func() { subscriptionClientFactory = nil }()
+
+	err := cmd.Execute()
+	return output.String(), err
+}
+
+func channelReference(name string) *corev1.ObjectReference {
+	return &corev1.ObjectReference{APIVersion: "messaging.knative.dev/v1beta1", Kind: "Channel", Name: name}
+}
+
+func pipeDestination(kind, apiVersion, name string) *duckv1.Destination {
+	return &duckv1.Destination{Ref: &duckv1.KReference{Kind: kind, APIVersion: apiVersion, Name: name, Namespace: "default"}}
+}
+
+func newPipeChannel(name string) *messagingv1beta1.Channel {
+	return &messagingv1beta1.Channel{
+		TypeMeta:   metav1.TypeMeta{Kind: "Channel", APIVersion: "messaging.knative.dev/v1beta1"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+	}
+}
+
+func newPipeService(name string) *servingv1.Service {
+	return &servingv1.Service{
+		TypeMeta:   metav1.TypeMeta{Kind: "Service", APIVersion: "serving.knative.dev/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+	}
+}
+
+func newPipeBroker(name string) *eventingv1beta1.Broker {
+	return &eventingv1beta1.Broker{
+		TypeMeta:   metav1.TypeMeta{Kind: "Broker", APIVersion: "eventing.knative.dev/v1beta1"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+	}
+}
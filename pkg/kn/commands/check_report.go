@@ -0,0 +1,68 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"knative.dev/client/pkg/printers"
+)
+
+// CheckResult is the outcome of a single check of a readiness or diagnostic report
+type CheckResult struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+// CheckReport collects the results of a sequence of checks
+type CheckReport struct {
+	Checks []CheckResult `json:"checks"`
+}
+
+// Pass records a successful check
+func (r *CheckReport) Pass(name string, message string) {
+	r.Checks = append(r.Checks, CheckResult{Name: name, Passed: true, Message: message})
+}
+
+// Fail records a failed check with the error causing the failure
+func (r *CheckReport) Fail(name string, err error) {
+	r.Checks = append(r.Checks, CheckResult{Name: name, Passed: false, Message: err.Error()})
+}
+
+// Passed returns true if all recorded checks have passed
+func (r *CheckReport) Passed() bool {
+	for _, check := range r.Checks {
+		if !check.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Print writes the report as a table with a line per check
+func (r *CheckReport) Print(out io.Writer) error {
+	w := printers.NewTabWriter(out)
+	fmt.Fprintln(w, "CHECK\tRESULT\tMESSAGE")
+	for _, check := range r.Checks {
+		result := "FAIL"
+		if check.Passed {
+			result = "PASS"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", check.Name, result, check.Message)
+	}
+	return w.Flush()
+}
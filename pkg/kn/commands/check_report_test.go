@@ -0,0 +1,41 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"knative.dev/client/pkg/util"
+)
+
+func TestCheckReport(t *testing.T) {
+	report := &CheckReport{}
+	report.Pass("first", "all good")
+	assert.Assert(t, report.Passed())
+	report.Fail("second", errors.New("boom"))
+	assert.Assert(t, !report.Passed())
+
+	out := new(bytes.Buffer)
+	assert.NilError(t, report.Print(out))
+	lines := strings.Split(out.String(), "\n")
+	assert.Assert(t, util.ContainsAll(lines[0], "CHECK", "RESULT", "MESSAGE"))
+	assert.Assert(t, util.ContainsAll(lines[1], "first", "PASS", "all good"))
+	assert.Assert(t, util.ContainsAll(lines[2], "second", "FAIL", "boom"))
+}
@@ -0,0 +1,138 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/mod/semver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"knative.dev/client/pkg/util"
+)
+
+// Flag annotation with the minimal versions of Knative components which a flag requires,
+// each as "<component>=<version>", e.g. "serving=v0.20.0"
+const minVersionAnnotation = "knative.dev/min-version"
+
+// MarkFlagRequiresVersion records that the flag takes effect only with at least the given version
+// of a Knative component ("serving" or "eventing") in the cluster. Older clusters silently ignore it.
+func MarkFlagRequiresVersion(flags *pflag.FlagSet, name string, component string, version string) {
+	flag := flags.Lookup(name)
+	if flag == nil {
+		return
+	}
+	if flag.Annotations == nil {
+		flag.Annotations = map[string][]string{}
+	}
+	flag.Annotations[minVersionAnnotation] = append(flag.Annotations[minVersionAnnotation], component+"="+version)
+}
+
+// versionRequirement is a minimal version of a Knative component required by a flag
+type versionRequirement struct {
+	flag      string
+	component string
+	version   string
+}
+
+// CheckCompatibility compares the versions of the Knative components required by the flags given
+// on the command line with the versions installed in the cluster. A warning is printed for each flag
+// which requires a newer version. With strict set, an error is returned instead, also when the version
+// in the cluster can't be determined. The cluster is only contacted if such a flag has been given.
+func (params *KnParams) CheckCompatibility(cmd *cobra.Command) error {
+	requirements := collectVersionRequirements(cmd.Flags())
+	for _, req := range requirements {
+		serverVersion, err := params.serverVersion(req.component)
+		if err != nil {
+			if params.StrictCompat {
+				return fmt.Errorf("cannot check that the cluster supports flag --%s: %v", req.flag, err)
+			}
+			klog.V(util.LogLevelClient).InfoS("Skipping compatibility check", "flag", req.flag, "component", req.component, "err", err)
+			continue
+		}
+		if semver.Compare(serverVersion, req.version) >= 0 {
+			continue
+		}
+		msg := fmt.Sprintf("flag --%s requires Knative %s %s or newer, but the cluster runs %s",
+			req.flag, strings.Title(req.component), req.version, serverVersion)
+		if params.StrictCompat {
+			return fmt.Errorf("%s. Remove the flag or run without --strict-compat", msg)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "WARNING: %s. The setting is probably ignored by the cluster.\n", msg)
+	}
+	return nil
+}
+
+// collectVersionRequirements returns the version requirements of all flags given on the command line
+func collectVersionRequirements(flags *pflag.FlagSet) []versionRequirement {
+	var requirements []versionRequirement
+	flags.Visit(func(flag *pflag.Flag) {
+		for _, value := range flag.Annotations[minVersionAnnotation] {
+			parts := strings.SplitN(value, "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			requirements = append(requirements, versionRequirement{flag: flag.Name, component: parts[0], version: parts[1]})
+		}
+	})
+	sort.SliceStable(requirements, func(i, j int) bool {
+		return requirements[i].flag < requirements[j].flag
+	})
+	return requirements
+}
+
+// serverVersion returns the version of a Knative component in the cluster, as given by the
+// release label of its CRDs. The version is looked up only once per invocation.
+func (params *KnParams) serverVersion(component string) (string, error) {
+	if params.serverVersions == nil {
+		params.serverVersions = map[string]serverVersionLookup{}
+	}
+	lookup, ok := params.serverVersions[component]
+	if !ok {
+		lookup.version, lookup.err = params.lookupServerVersion(component)
+		params.serverVersions[component] = lookup
+	}
+	return lookup.version, lookup.err
+}
+
+// serverVersionLookup is the cached result of looking up the version of a Knative component
+type serverVersionLookup struct {
+	version string
+	err     error
+}
+
+func (params *KnParams) lookupServerVersion(component string) (string, error) {
+	client, err := params.NewDynamicClient("")
+	if err != nil {
+		return "", err
+	}
+	releaseLabel := component + ".knative.dev/release"
+	crds, err := client.ListCRDs(metav1.ListOptions{LabelSelector: releaseLabel})
+	if err != nil {
+		return "", err
+	}
+	for _, crd := range crds.Items {
+		version := crd.GetLabels()[releaseLabel]
+		if semver.IsValid(version) {
+			return version, nil
+		}
+	}
+	return "", fmt.Errorf("no release version of Knative %s found in the cluster", strings.Title(component))
+}
@@ -0,0 +1,120 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	clientdynamic "knative.dev/client/pkg/dynamic"
+	"knative.dev/client/pkg/util"
+	"knative.dev/client/pkg/util/mock"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	crds := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{
+		newReleasedCRD("services.serving.knative.dev", "serving.knative.dev/release", "v0.19.0"),
+	}}
+
+	for _, tc := range []struct {
+		name   string
+		args   []string
+		strict bool
+		// Whether the versions of the cluster are looked up
+		lookup    bool
+		lookupErr error
+		warning   string
+		err       string
+	}{{
+		name: "no flag with requirements",
+		args: []string{"--plain", "foo"},
+	}, {
+		name:   "supported flag",
+		args:   []string{"--old"},
+		lookup: true,
+	}, {
+		name:    "unsupported flag",
+		args:    []string{"--new", "--old"},
+		lookup:  true,
+		warning: "WARNING: flag --new requires Knative Serving v0.20.0 or newer, but the cluster runs v0.19.0",
+	}, {
+		name:   "unsupported flag strict",
+		args:   []string{"--new"},
+		strict: true,
+		lookup: true,
+		err:    "flag --new requires Knative Serving v0.20.0 or newer, but the cluster runs v0.19.0. Remove the flag",
+	}, {
+		name:      "unknown version",
+		args:      []string{"--new"},
+		lookup:    true,
+		lookupErr: errors.New("forbidden"),
+	}, {
+		name:      "unknown version strict",
+		args:      []string{"--new"},
+		strict:    true,
+		lookup:    true,
+		lookupErr: errors.New("forbidden"),
+		err:       "cannot check that the cluster supports flag --new: forbidden",
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			dynamicClient := clientdynamic.NewMockKnDynamicClient(t)
+			if tc.lookup {
+				// The version is looked up once, even when required by several flags
+				dynamicClient.Recorder().ListCRDs(mock.Any(), crds, tc.lookupErr)
+			}
+			p := &KnParams{
+				StrictCompat: tc.strict,
+				NewDynamicClient: func(namespace string) (clientdynamic.KnDynamicClient, error) {
+					return dynamicClient, nil
+				},
+			}
+
+			cmd := &cobra.Command{Use: "test"}
+			cmd.Flags().String("plain", "", "")
+			cmd.Flags().Bool("old", false, "")
+			cmd.Flags().Bool("new", false, "")
+			MarkFlagRequiresVersion(cmd.Flags(), "old", "serving", "v0.17.0")
+			MarkFlagRequiresVersion(cmd.Flags(), "new", "serving", "v0.20.0")
+			assert.NilError(t, cmd.ParseFlags(tc.args))
+			errOut := &bytes.Buffer{}
+			cmd.SetErr(errOut)
+
+			err := p.CheckCompatibility(cmd)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+			} else {
+				assert.NilError(t, err)
+			}
+			if tc.warning != "" {
+				assert.Assert(t, util.ContainsAll(errOut.String(), tc.warning))
+			} else {
+				assert.Equal(t, errOut.String(), "")
+			}
+			dynamicClient.Recorder().Validate()
+		})
+	}
+}
+
+func newReleasedCRD(name string, releaseLabel string, version string) unstructured.Unstructured {
+	crd := unstructured.Unstructured{}
+	crd.SetName(name)
+	crd.SetLabels(map[string]string{releaseLabel: version})
+	return crd
+}
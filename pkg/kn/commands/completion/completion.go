@@ -31,7 +31,12 @@ to provide interactive completion
 
 Supported Shells:
  - bash
- - zsh`
+ - zsh
+
+Besides command names and flags, the values of some flags are completed,
+like namespaces for --namespace, revisions for --traffic and brokers for
+--sink broker:. zsh shows a description for each completion, unless
+--no-descriptions is given.`
 	eg = `
  # Generate completion code for bash
  source <(kn completion bash)
@@ -43,7 +48,8 @@ Supported Shells:
 
 // NewCompletionCommand implements shell auto-completion feature for Bash and Zsh
 func NewCompletionCommand(p *commands.KnParams) *cobra.Command {
-	return &cobra.Command{
+	var noDescriptions bool
+	completionCmd := &cobra.Command{
 		Use:       "completion SHELL",
 		Short:     "Output shell completion code",
 		Long:      desc,
@@ -55,6 +61,9 @@ func NewCompletionCommand(p *commands.KnParams) *cobra.Command {
 				case "bash":
 					return cmd.Root().GenBashCompletion(os.Stdout)
 				case "zsh":
+					if noDescriptions {
+						return cmd.Root().GenZshCompletionNoDesc(os.Stdout)
+					}
 					return cmd.Root().GenZshCompletion(os.Stdout)
 				default:
 					return errors.New("'bash' or 'zsh' shell completion is supported")
@@ -64,4 +73,6 @@ func NewCompletionCommand(p *commands.KnParams) *cobra.Command {
 			}
 		},
 	}
+	completionCmd.Flags().BoolVar(&noDescriptions, "no-descriptions", false, "Don't show descriptions for completions (zsh only)")
+	return completionCmd
 }
@@ -0,0 +1,196 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package completion
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/serving/pkg/apis/serving"
+
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/kn/commands/flags"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+)
+
+// Prefix of a sink which refers to a broker
+const brokerSinkPrefix = "broker:"
+
+// Descriptions of the built-in sink prefixes
+var sinkPrefixDescriptions = map[string]string{
+	"broker":  "Broker",
+	"channel": "Channel",
+	"ksvc":    "Knative service",
+}
+
+// flagCompletionFunc provides completions for the value of a flag
+type flagCompletionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// RegisterFlagCompletions registers value completions for the flags of the given
+// command and all its sub commands: namespaces for --namespace, revisions of the
+// service given as argument for --traffic and sink prefixes and brokers for --sink.
+// Completions carry a description which is shown by shells which support it.
+func RegisterFlagCompletions(root *cobra.Command, p *commands.KnParams) error {
+	completions := map[string]flagCompletionFunc{
+		"namespace": completeNamespaces(p),
+		"traffic":   completeTraffic(p),
+		"sink":      completeSink(p),
+	}
+	return registerFlagCompletions(root, completions)
+}
+
+func registerFlagCompletions(cmd *cobra.Command, completions map[string]flagCompletionFunc) error {
+	for name, fn := range completions {
+		// Only local flags, a persistent flag would be registered once for every sub command otherwise
+		if cmd.LocalFlags().Lookup(name) == nil {
+			continue
+		}
+		err := cmd.RegisterFlagCompletionFunc(name, fn)
+		if err != nil {
+			return err
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		err := registerFlagCompletions(sub, completions)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func completeNamespaces(p *commands.KnParams) flagCompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		client, err := p.NewKubeClient()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		namespaces, err := client.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		var completions []string
+		for _, ns := range namespaces.Items {
+			if strings.HasPrefix(ns.Name, toComplete) {
+				completions = append(completions, ns.Name+"\t"+string(ns.Status.Phase))
+			}
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// Complete the revision part of '--traffic REVISION=PERCENT', for the service
+// given as argument if any
+func completeTraffic(p *commands.KnParams) flagCompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if strings.Contains(toComplete, "=") {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		namespace, err := completionNamespace(p, cmd)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		client, err := p.NewServingClient(namespace)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		var listConfig []clientservingv1.ListConfig
+		if len(args) > 0 {
+			listConfig = append(listConfig, clientservingv1.WithService(args[0]))
+		}
+		revisions, err := client.ListRevisions(listConfig...)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		var completions []string
+		if strings.HasPrefix("@latest", toComplete) {
+			completions = append(completions, "@latest=\tlatest ready revision")
+		}
+		for _, revision := range revisions.Items {
+			if !strings.HasPrefix(revision.Name, toComplete) {
+				continue
+			}
+			desc := "revision"
+			if gen := revision.Labels[serving.ConfigurationGenerationLabelKey]; gen != "" {
+				desc = fmt.Sprintf("revision of generation %s", gen)
+			}
+			completions = append(completions, revision.Name+"=\t"+desc)
+		}
+		return completions, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// Complete the sink prefixes and broker names after 'broker:'
+func completeSink(p *commands.KnParams) flagCompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if !strings.HasPrefix(toComplete, brokerSinkPrefix) {
+			var completions []string
+			for _, prefix := range flags.SinkPrefixes() {
+				if strings.HasPrefix(prefix+":", toComplete) {
+					completions = append(completions, prefix+":\t"+withDefault(sinkPrefixDescriptions[prefix], prefix))
+				}
+			}
+			// URIs and plain service names are valid, too
+			return completions, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+		}
+
+		namespace, err := completionNamespace(p, cmd)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		client, err := p.NewEventingClient(namespace)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		brokers, err := client.ListBrokers()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		name := strings.TrimPrefix(toComplete, brokerSinkPrefix)
+		var completions []string
+		for _, broker := range brokers.Items {
+			if !strings.HasPrefix(broker.Name, name) {
+				continue
+			}
+			desc := "broker"
+			if broker.Status.Address.URL != nil {
+				desc = "broker at " + broker.Status.Address.URL.String()
+			}
+			completions = append(completions, brokerSinkPrefix+broker.Name+"\t"+desc)
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// Namespace to use for looking up completions, the root's PersistentPreRunE
+// which binds the environment is not called during completion
+func completionNamespace(p *commands.KnParams, cmd *cobra.Command) (string, error) {
+	err := p.BindEnvironment(cmd.Flags())
+	if err != nil {
+		return "", err
+	}
+	return p.GetNamespace(cmd)
+}
+
+func withDefault(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,115 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package completion
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	clienteventingv1beta1 "knative.dev/client/pkg/eventing/v1beta1"
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/kn/commands/flags"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util/mock"
+)
+
+// Command tree with the flags which get completions
+func newFlagCompletionTestCommand(t *testing.T, p *commands.KnParams) *cobra.Command {
+	root := &cobra.Command{Use: "kn"}
+	create := &cobra.Command{Use: "create", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	commands.AddNamespaceFlags(create.Flags(), false)
+	create.Flags().StringArray("traffic", nil, "")
+	(&flags.SinkFlags{}).Add(create)
+	root.AddCommand(create)
+	assert.NilError(t, RegisterFlagCompletions(root, p))
+	return root
+}
+
+func complete(t *testing.T, p *commands.KnParams, args ...string) []string {
+	root := newFlagCompletionTestCommand(t, p)
+	out := new(bytes.Buffer)
+	root.SetOut(out)
+	root.SetErr(new(bytes.Buffer))
+	root.SetArgs(append([]string{cobra.ShellCompRequestCmd}, args...))
+	assert.NilError(t, root.Execute())
+	return strings.Split(strings.TrimSpace(out.String()), "\n")
+}
+
+func TestCompleteNamespaces(t *testing.T) {
+	p := &commands.KnParams{
+		NewKubeClient: func() (kubernetes.Interface, error) {
+			return commands.NewFakeKubeClient(
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}, Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive}},
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "dev"}, Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive}},
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod"}, Status: corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating}},
+			), nil
+		},
+	}
+	assert.DeepEqual(t, complete(t, p, "create", "--namespace", "d"), []string{"default\tActive", "dev\tActive", ":4"})
+}
+
+func TestCompleteTraffic(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	revisions := &servingv1.RevisionList{Items: []servingv1.Revision{
+		{ObjectMeta: metav1.ObjectMeta{Name: "foo-00001", Labels: map[string]string{serving.ConfigurationGenerationLabelKey: "1"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "foo-00002"}},
+	}}
+	r.ListRevisions(mock.Any(), revisions, nil)
+	r.ListRevisions(mock.Any(), revisions, nil)
+	p := &commands.KnParams{
+		NewServingClient: func(namespace string) (clientservingv1.KnServingClient, error) {
+			assert.Equal(t, namespace, "ns")
+			return client, nil
+		},
+	}
+
+	assert.DeepEqual(t, complete(t, p, "create", "foo", "-n", "ns", "--traffic", ""),
+		[]string{"@latest=\tlatest ready revision", "foo-00001=\trevision of generation 1", "foo-00002=\trevision", ":6"})
+	assert.DeepEqual(t, complete(t, p, "create", "foo", "-n", "ns", "--traffic", "foo-00001"),
+		[]string{"foo-00001=\trevision of generation 1", ":6"})
+	// Percentages are not completed
+	assert.DeepEqual(t, complete(t, p, "create", "foo", "-n", "ns", "--traffic", "foo-00001="), []string{":4"})
+	r.Validate()
+}
+
+func TestCompleteSink(t *testing.T) {
+	client := clienteventingv1beta1.NewMockKnEventingClient(t)
+	r := client.Recorder()
+	r.ListBrokers(&v1beta1.BrokerList{Items: []v1beta1.Broker{
+		{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "nest"}},
+	}}, nil)
+	p := &commands.KnParams{
+		NewEventingClient: func(namespace string) (clienteventingv1beta1.KnEventingClient, error) {
+			return client, nil
+		},
+	}
+
+	assert.DeepEqual(t, complete(t, p, "create", "-n", "ns", "--sink", ""),
+		[]string{"broker:\tBroker", "channel:\tChannel", "ksvc:\tKnative service", ":6"})
+	assert.DeepEqual(t, complete(t, p, "create", "-n", "ns", "--sink", "broker:n"), []string{"broker:nest\tbroker", ":4"})
+	r.Validate()
+}
@@ -86,6 +86,9 @@ func Age(t time.Time) string {
 	if t.IsZero() {
 		return ""
 	}
+	if printers.OmitTimestamps() {
+		return printers.TimestampPlaceholder
+	}
 	return duration.ShortHumanDuration(time.Since(t))
 }
 
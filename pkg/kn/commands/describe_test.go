@@ -20,8 +20,10 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/client/pkg/printers"
 	"knative.dev/pkg/apis"
 )
@@ -108,3 +110,19 @@ OK TYPE AGE REASON
  I Ccc Eh.`))
 	}
 }
+
+func TestAgeWithoutTimestamps(t *testing.T) {
+	defer func(noTimestamps bool) {
+		printers.NoTimestamps = noTimestamps
+	}(printers.NoTimestamps)
+
+	created := time.Now().Add(-2 * time.Hour)
+	assert.Equal(t, Age(created), "2h")
+	assert.Equal(t, TranslateTimestampSince(metav1.NewTime(created)), "120m")
+
+	printers.NoTimestamps = true
+	assert.Equal(t, Age(created), "-")
+	assert.Equal(t, TranslateTimestampSince(metav1.NewTime(created)), "-")
+	assert.Equal(t, Age(time.Time{}), "")
+	assert.Equal(t, TranslateTimestampSince(metav1.Time{}), "<unknown>")
+}
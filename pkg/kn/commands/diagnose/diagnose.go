@@ -0,0 +1,206 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/printers"
+)
+
+// Interval for polling the pods of the diagnose service when waiting for scale-to-zero
+var scaleToZeroPollInterval = 5 * time.Second
+
+type diagnoseFlags struct {
+	name               string
+	image              string
+	timeout            int
+	requestTimeout     int
+	scaleToZeroTimeout int
+}
+
+// NewDiagnoseCommand returns a new command for validating the Knative installation of a cluster
+func NewDiagnoseCommand(p *commands.KnParams) *cobra.Command {
+	var flags diagnoseFlags
+	machineReadablePrintFlags := genericclioptions.NewPrintFlags("")
+
+	command := &cobra.Command{
+		Use:   "diagnose",
+		Short: "Validate the Knative installation with a smoke test",
+		Long: "Validate the Knative installation of the cluster by deploying a known-good service, waiting for it " +
+			"to become ready, sending a request to its URL, checking that it scales to zero and deleting it again. " +
+			"The result is printed as a pass/fail report.",
+		Example: `
+  # Run the diagnose flow in the current namespace
+  kn diagnose
+
+  # Run the diagnose flow in namespace 'myns' without the scale-to-zero check and print the report as JSON
+  kn diagnose -n myns --scale-to-zero-timeout 0 -o json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return errors.New("'kn diagnose' accepts no arguments")
+			}
+			// Check the output format before deploying anything
+			if machineReadablePrintFlags.OutputFlagSpecified() {
+				_, err := machineReadablePrintFlags.ToPrinter()
+				if err != nil {
+					return err
+				}
+			}
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := p.NewServingClient(namespace)
+			if err != nil {
+				return err
+			}
+			kubeClient, err := p.NewKubeClient()
+			if err != nil {
+				return err
+			}
+
+			report := &commands.CheckReport{}
+			commands.SmokeTestService(client, flags.name, flags.image, time.Duration(flags.timeout)*time.Second, report,
+				func(service *servingv1.Service) {
+					checkURL(service, time.Duration(flags.requestTimeout)*time.Second, report)
+					if flags.scaleToZeroTimeout > 0 {
+						checkScaleToZero(kubeClient, service, time.Duration(flags.scaleToZeroTimeout)*time.Second, report)
+					}
+				})
+
+			out := cmd.OutOrStdout()
+			if machineReadablePrintFlags.OutputFlagSpecified() {
+				printer, err := machineReadablePrintFlags.ToPrinter()
+				if err != nil {
+					return err
+				}
+				err = printer.PrintObj(reportObject(namespace, flags.name, report), out)
+				if err != nil {
+					return err
+				}
+			} else {
+				fmt.Fprintf(out, "Diagnose report for namespace '%s':\n\n", namespace)
+				err = report.Print(out)
+				if err != nil {
+					return err
+				}
+			}
+			// The failure is signalled by the exit code and an error message on stderr only,
+			// so that the report on stdout stays machine readable
+			if !report.Passed() {
+				return fmt.Errorf("diagnose failed in namespace '%s'", namespace)
+			}
+			return nil
+		},
+	}
+	commands.AddNamespaceFlags(command.Flags(), false)
+	command.Flags().StringVar(&flags.name, "name", "kn-diagnose", "Name of the service deployed for the diagnose flow.")
+	command.Flags().StringVar(&flags.image, "image", commands.DefaultSmokeTestImage, "Image of the service deployed for the diagnose flow.")
+	command.Flags().IntVar(&flags.timeout, "wait-timeout", commands.WaitDefaultTimeout,
+		"Seconds to wait for the service to become ready and to be deleted.")
+	command.Flags().IntVar(&flags.requestTimeout, "request-timeout", 30, "Seconds to wait for the response when sending a request to the service URL.")
+	command.Flags().IntVar(&flags.scaleToZeroTimeout, "scale-to-zero-timeout", 180,
+		"Seconds to wait for the service to scale to zero after the request. Use 0 to skip the scale-to-zero check.")
+	machineReadablePrintFlags.AddFlags(command)
+	return command
+}
+
+// checkURL sends a request to the URL of the service and expects a successful response
+func checkURL(service *servingv1.Service, timeout time.Duration, report *commands.CheckReport) {
+	if service.Status.URL == nil {
+		report.Fail("request", errors.New("service has no URL"))
+		return
+	}
+	url := service.Status.URL.String()
+	httpClient := &http.Client{Timeout: timeout}
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		report.Fail("request", err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		report.Fail("request", fmt.Errorf("%s returned status %s", url, resp.Status))
+		return
+	}
+	report.Pass("request", fmt.Sprintf("%s returned status %s", url, resp.Status))
+}
+
+// checkScaleToZero waits until no running pods are left for the service
+func checkScaleToZero(client kubernetes.Interface, service *servingv1.Service, timeout time.Duration, report *commands.CheckReport) {
+	selector := fmt.Sprintf("%s=%s", serving.ServiceLabelKey, service.Name)
+	start := time.Now()
+	err := wait.PollImmediate(scaleToZeroPollInterval, timeout, func() (bool, error) {
+		pods, err := client.CoreV1().Pods(service.Namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return false, err
+		}
+		for _, pod := range pods.Items {
+			if pod.DeletionTimestamp == nil {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		report.Fail("scale-to-zero", fmt.Errorf("service still has pods after %s", timeout))
+		return
+	}
+	if err != nil {
+		report.Fail("scale-to-zero", err)
+		return
+	}
+	if printers.OmitTimestamps() {
+		report.Pass("scale-to-zero", "scaled to zero")
+		return
+	}
+	report.Pass("scale-to-zero", fmt.Sprintf("scaled to zero after %s", time.Since(start).Round(time.Second)))
+}
+
+// reportObject converts the report to an object which can be printed by the machine readable printers
+func reportObject(namespace string, name string, report *commands.CheckReport) *unstructured.Unstructured {
+	checks := make([]interface{}, 0, len(report.Checks))
+	for _, check := range report.Checks {
+		checks = append(checks, map[string]interface{}{
+			"name":    check.Name,
+			"passed":  check.Passed,
+			"message": check.Message,
+		})
+	}
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"passed": report.Passed(),
+		"checks": checks,
+	}}
+	obj.SetAPIVersion("client.knative.dev/v1alpha1")
+	obj.SetKind("DiagnoseReport")
+	obj.SetName(name)
+	obj.SetNamespace(namespace)
+	return obj
+}
@@ -0,0 +1,139 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnose
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/apis"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+	"knative.dev/client/pkg/util/mock"
+	"knative.dev/client/pkg/wait"
+)
+
+func init() {
+	scaleToZeroPollInterval = 10 * time.Millisecond
+}
+
+func executeDiagnoseCommand(client clientservingv1.KnServingClient, kubeClient kubernetes.Interface, args ...string) (string, error) {
+	knParams := &commands.KnParams{}
+	output := new(bytes.Buffer)
+	knParams.Output = output
+	knParams.NewServingClient = func(namespace string) (clientservingv1.KnServingClient, error) {
+		return client, nil
+	}
+	knParams.NewKubeClient = func() (kubernetes.Interface, error) {
+		return kubeClient, nil
+	}
+	cmd := NewDiagnoseCommand(knParams)
+	cmd.SetArgs(args)
+	// Like for the root command, errors are printed by main on stderr and not by cobra
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetOutput(output)
+	err := cmd.Execute()
+	return output.String(), err
+}
+
+func recordDiagnoseService(t *testing.T, url string) *clientservingv1.MockKnServingClient {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	service := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "kn-diagnose", Namespace: "default"}}
+	service.Status.URL, _ = apis.ParseURL(url)
+
+	r := client.Recorder()
+	r.CreateService(mock.Any(), nil)
+	r.WaitForService("kn-diagnose", mock.Any(), wait.NoopMessageCallback(), nil, time.Second)
+	r.GetService("kn-diagnose", service, nil)
+	r.DeleteService("kn-diagnose", mock.Any(), nil)
+	return client
+}
+
+func TestDiagnose(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Hello World!"))
+	}))
+	defer server.Close()
+
+	client := recordDiagnoseService(t, server.URL)
+	output, err := executeDiagnoseCommand(client, commands.NewFakeKubeClient(), "-n", "default")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "deploy", "ready", "request", "200 OK", "scale-to-zero", "delete"))
+	assert.Assert(t, util.ContainsNone(output, "FAIL"))
+	client.Recorder().Validate()
+}
+
+func TestDiagnoseJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := recordDiagnoseService(t, server.URL)
+	output, err := executeDiagnoseCommand(client, commands.NewFakeKubeClient(), "-n", "default", "--scale-to-zero-timeout", "0", "-o", "json")
+	assert.ErrorContains(t, err, "diagnose failed")
+
+	// The whole output is the report, the error is not mixed into it
+	report := &unstructured.Unstructured{}
+	assert.NilError(t, json.Unmarshal([]byte(output), &report.Object))
+	assert.Equal(t, report.GetKind(), "DiagnoseReport")
+	assert.Equal(t, report.GetNamespace(), "default")
+	passed, _, _ := unstructured.NestedBool(report.Object, "passed")
+	assert.Equal(t, passed, false)
+	checks, _, _ := unstructured.NestedSlice(report.Object, "checks")
+	assert.Equal(t, len(checks), 4)
+	request := checks[2].(map[string]interface{})
+	assert.Equal(t, request["name"], "request")
+	assert.Equal(t, request["passed"], false)
+	assert.Assert(t, util.ContainsAll(request["message"].(string), "503"))
+	client.Recorder().Validate()
+}
+
+func TestDiagnoseNoScaleToZero(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	kubeClient := commands.NewFakeKubeClient(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kn-diagnose-00001-deployment-abc",
+			Namespace: "default",
+			Labels:    map[string]string{"serving.knative.dev/service": "kn-diagnose"},
+		},
+	})
+	client := recordDiagnoseService(t, server.URL)
+	output, err := executeDiagnoseCommand(client, kubeClient, "-n", "default", "--scale-to-zero-timeout", "1")
+	assert.ErrorContains(t, err, "diagnose failed")
+	assert.Assert(t, util.ContainsAll(output, "scale-to-zero", "FAIL", "still has pods"))
+	client.Recorder().Validate()
+}
+
+func TestDiagnoseInvalidOutput(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	_, err := executeDiagnoseCommand(client, commands.NewFakeKubeClient(), "-o", "xml")
+	assert.ErrorContains(t, err, "unable to match a printer")
+}
@@ -0,0 +1,71 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/pflag"
+)
+
+// EnvVarsForFlags maps the names of common flags to the environment variables
+// which provide a value when the flag is not given on the command line.
+// The precedence is: environment < configuration file < command line flags.
+var EnvVarsForFlags = map[string]string{
+	"namespace":  "KN_NAMESPACE",
+	"output":     "KN_OUTPUT",
+	"kubeconfig": "KN_KUBECONFIG",
+	"log-http":   "KN_LOG_HTTP",
+
+	"no-timestamps": "KN_NO_TIMESTAMPS",
+	"stable-output": "KN_STABLE_OUTPUT",
+
+	"non-interactive": NonInteractiveEnvVar,
+	"strict-compat":   "KN_STRICT_COMPAT",
+
+	"namespace-required": "KN_NAMESPACE_REQUIRED",
+	"server-side-apply":  "KN_SERVER_SIDE_APPLY",
+}
+
+// BindEnvironment sets flags which have not been given on the command line from their
+// environment variables. The flags are not marked as changed, so that an environment
+// variable counts like a default value.
+func (params *KnParams) BindEnvironment(flags *pflag.FlagSet) error {
+	names := make([]string, 0, len(EnvVarsForFlags))
+	for name := range EnvVarsForFlags {
+		names = append(names, name)
+	}
+	// Check in a stable order so that the same error is reported for multiple invalid values
+	sort.Strings(names)
+
+	for _, name := range names {
+		flag := flags.Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		envVar := EnvVarsForFlags[name]
+		value, ok := os.LookupEnv(envVar)
+		if !ok || value == "" {
+			continue
+		}
+		err := flag.Value.Set(value)
+		if err != nil {
+			return fmt.Errorf("invalid value '%s' of environment variable %s for flag --%s: %v", value, envVar, name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"gotest.tools/assert"
+
+	"knative.dev/client/pkg/kn/flags"
+)
+
+func TestBindEnvironment(t *testing.T) {
+	defer setEnv(t, "KN_NAMESPACE", "env-ns")()
+	defer setEnv(t, "KN_OUTPUT", "yaml")()
+	defer setEnv(t, "KN_LOG_HTTP", "true")()
+
+	p := &KnParams{}
+	flagSet := newEnvTestFlagSet(p)
+	assert.NilError(t, flagSet.Parse([]string{"--output", "json"}))
+
+	err := p.BindEnvironment(flagSet)
+	assert.NilError(t, err)
+	assert.Equal(t, flagSet.Lookup("namespace").Value.String(), "env-ns")
+	assert.Assert(t, !flagSet.Changed("namespace"))
+	// Flags given on the command line take precedence
+	assert.Equal(t, flagSet.Lookup("output").Value.String(), "json")
+	assert.Equal(t, p.LogHTTP, true)
+}
+
+func TestBindEnvironmentNegatedBoolFlag(t *testing.T) {
+	defer setEnv(t, "KN_LOG_HTTP", "true")()
+
+	p := &KnParams{}
+	flagSet := newEnvTestFlagSet(p)
+	assert.NilError(t, flagSet.Parse([]string{"--no-log-http"}))
+
+	assert.NilError(t, p.BindEnvironment(flagSet))
+	assert.NilError(t, flags.ReconcileBoolFlags(flagSet))
+	assert.Equal(t, p.LogHTTP, false)
+}
+
+func TestBindEnvironmentInvalidValue(t *testing.T) {
+	defer setEnv(t, "KN_LOG_HTTP", "maybe")()
+
+	p := &KnParams{}
+	flagSet := newEnvTestFlagSet(p)
+	assert.NilError(t, flagSet.Parse([]string{}))
+
+	err := p.BindEnvironment(flagSet)
+	assert.ErrorContains(t, err, "KN_LOG_HTTP")
+	assert.ErrorContains(t, err, "--log-http")
+}
+
+func TestBindEnvironmentUnknownFlag(t *testing.T) {
+	defer setEnv(t, "KN_OUTPUT", "yaml")()
+
+	// Commands without an --output flag are not affected
+	p := &KnParams{}
+	flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	AddNamespaceFlags(flagSet, false)
+	assert.NilError(t, p.BindEnvironment(flagSet))
+}
+
+func newEnvTestFlagSet(p *KnParams) *pflag.FlagSet {
+	flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	AddNamespaceFlags(flagSet, false)
+	flagSet.StringP("output", "o", "", "output format")
+	flags.AddBothBoolFlags(flagSet, &p.LogHTTP, "log-http", "", false, "log http traffic")
+	return flagSet
+}
+
+func setEnv(t *testing.T, key, value string) func() {
+	oldValue, isSet := os.LookupEnv(key)
+	assert.NilError(t, os.Setenv(key, value))
+	return func() {
+		if isSet {
+			os.Setenv(key, oldValue)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/metrics/metricskey"
+
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/printers"
+)
+
+const (
+	// Label of the pods of the multi-tenant channel based broker which tells the role of a pod
+	brokerRoleLabelKey = "eventing.knative.dev/brokerRole"
+	brokerRoleIngress  = "ingress"
+	brokerRoleFilter   = "filter"
+
+	// Port on which the broker pods expose their Prometheus metrics
+	brokerMetricsPort = "9092"
+
+	// Suffix of the event counters, the prefix is the component (e.g. "mt_broker_filter")
+	eventCountMetricSuffix = "_event_count"
+
+	// Trigger name shown for the events received by the ingress of a broker
+	ingressRow = "(ingress)"
+)
+
+// activitySleep waits between the two scrapes of --interval, can be replaced in tests
+var activitySleep = time.Sleep
+
+// fetchPodMetrics fetches the metrics of a pod through the proxy of the API server,
+// can be replaced in tests
+var fetchPodMetrics = func(client kubernetes.Interface, namespace string, name string, port string) ([]byte, error) {
+	return client.CoreV1().RESTClient().Get().
+		Namespace(namespace).
+		Resource("pods").
+		Name(name + ":" + port).
+		SubResource("proxy").
+		Suffix("metrics").
+		DoRaw(context.TODO())
+}
+
+// deliveryKey identifies a row of the activity table
+type deliveryKey struct {
+	broker  string
+	trigger string
+}
+
+// deliveryCounts are the events delivered by a broker ingress or for a trigger,
+// and how many of them got a response other than 2xx
+type deliveryCounts struct {
+	events int64
+	failed int64
+}
+
+type activityFlags struct {
+	systemNamespace string
+	interval        time.Duration
+}
+
+// NewEventingActivityCommand returns a new command summarizing the events delivered by the brokers of a namespace
+func NewEventingActivityCommand(p *commands.KnParams) *cobra.Command {
+	var flags activityFlags
+
+	command := &cobra.Command{
+		Use:   "activity",
+		Short: "Show the event deliveries of the brokers and triggers of a namespace",
+		Long: "Show how many events the brokers of a namespace received and delivered per trigger, and how many " +
+			"deliveries failed, i.e. got a response other than 2xx. The counts are read from the metrics of the " +
+			"multi-tenant channel based broker through the API server, so no access to Prometheus is needed. " +
+			"They are counted since the start of the broker pods, or during the --interval. Events sent to a " +
+			"dead letter sink are not included, as the broker doesn't count them per trigger.",
+		Example: `
+  # Show the event deliveries in namespace 'myns' since the broker pods started
+  kn eventing activity -n myns
+
+  # Show the event deliveries of the last minute
+  kn eventing activity --interval 1m`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return errors.New("'kn eventing activity' accepts no arguments")
+			}
+			if flags.interval < 0 {
+				return fmt.Errorf("--interval must not be negative, got %s", flags.interval)
+			}
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			kubeClient, err := p.NewKubeClient()
+			if err != nil {
+				return err
+			}
+			eventingClient, err := p.NewEventingClient(namespace)
+			if err != nil {
+				return err
+			}
+
+			counts, err := scrapeDeliveryCounts(kubeClient, flags.systemNamespace, namespace)
+			if err != nil {
+				return err
+			}
+			if flags.interval > 0 {
+				activitySleep(flags.interval)
+				later, err := scrapeDeliveryCounts(kubeClient, flags.systemNamespace, namespace)
+				if err != nil {
+					return err
+				}
+				counts = subtractDeliveryCounts(later, counts)
+			}
+
+			// Triggers without any delivery are shown, too
+			triggerList, err := eventingClient.ListTriggers()
+			if err != nil {
+				return err
+			}
+			for _, trigger := range triggerList.Items {
+				key := deliveryKey{broker: trigger.Spec.Broker, trigger: trigger.Name}
+				if _, ok := counts[key]; !ok {
+					counts[key] = deliveryCounts{}
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			if len(counts) == 0 {
+				fmt.Fprintf(out, "No brokers or triggers with events found in namespace '%s'.\n", namespace)
+				return nil
+			}
+			if flags.interval > 0 {
+				fmt.Fprintf(out, "Event deliveries in namespace '%s' during %s:\n\n", namespace, flags.interval)
+			} else {
+				fmt.Fprintf(out, "Event deliveries in namespace '%s' since the broker pods started:\n\n", namespace)
+			}
+			return printDeliveryCounts(out, counts)
+		},
+	}
+	commands.AddNamespaceFlags(command.Flags(), false)
+	command.Flags().StringVar(&flags.systemNamespace, "system-namespace", "knative-eventing",
+		"Namespace in which Knative eventing and its broker pods are installed.")
+	command.Flags().DurationVar(&flags.interval, "interval", 0,
+		"Count only the events delivered during this interval, e.g. '1m'. kn waits for the interval. "+
+			"By default the events since the start of the broker pods are counted.")
+	return command
+}
+
+// scrapeDeliveryCounts sums the event counters of all running broker pods for the given namespace
+func scrapeDeliveryCounts(client kubernetes.Interface, systemNamespace string, namespace string) (map[deliveryKey]deliveryCounts, error) {
+	pods, err := client.CoreV1().Pods(systemNamespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: brokerRoleLabelKey + " in (" + brokerRoleIngress + "," + brokerRoleFilter + ")",
+	})
+	if err != nil {
+		return nil, err
+	}
+	counts := map[deliveryKey]deliveryCounts{}
+	scraped := 0
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		raw, err := fetchPodMetrics(client, systemNamespace, pod.Name, brokerMetricsPort)
+		if err != nil {
+			return nil, fmt.Errorf("cannot fetch the metrics of broker pod '%s' in namespace '%s': %v", pod.Name, systemNamespace, err)
+		}
+		var parser expfmt.TextParser
+		families, err := parser.TextToMetricFamilies(bytes.NewReader(raw))
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse the metrics of broker pod '%s' in namespace '%s': %v", pod.Name, systemNamespace, err)
+		}
+		addDeliveryCounts(counts, families, pod.Labels[brokerRoleLabelKey] == brokerRoleIngress, namespace)
+		scraped++
+	}
+	if scraped == 0 {
+		return nil, fmt.Errorf("no running broker pods found in namespace '%s', "+
+			"only the multi-tenant channel based broker is supported (use --system-namespace if eventing is installed elsewhere)",
+			systemNamespace)
+	}
+	return counts, nil
+}
+
+// addDeliveryCounts adds the event counters of the given namespace to counts.
+// The counters of the ingress are counted per broker, the ones of the filter per trigger.
+func addDeliveryCounts(counts map[deliveryKey]deliveryCounts, families map[string]*dto.MetricFamily, ingress bool, namespace string) {
+	for name, family := range families {
+		if !strings.HasSuffix(name, eventCountMetricSuffix) || family.GetType() != dto.MetricType_COUNTER {
+			continue
+		}
+		for _, metric := range family.Metric {
+			metricLabels := map[string]string{}
+			for _, pair := range metric.Label {
+				metricLabels[pair.GetName()] = pair.GetValue()
+			}
+			if metricLabels[metricskey.LabelNamespaceName] != namespace {
+				continue
+			}
+			key := deliveryKey{broker: metricLabels[metricskey.LabelBrokerName], trigger: ingressRow}
+			if !ingress {
+				key.trigger = metricLabels[metricskey.LabelTriggerName]
+			}
+			value := int64(metric.GetCounter().GetValue())
+			c := counts[key]
+			c.events += value
+			if metricLabels[metricskey.LabelResponseCodeClass] != "2xx" {
+				c.failed += value
+			}
+			counts[key] = c
+		}
+	}
+}
+
+// subtractDeliveryCounts returns the deliveries between two scrapes. Counters which
+// went down because a broker pod restarted are taken as they are.
+func subtractDeliveryCounts(later map[deliveryKey]deliveryCounts, earlier map[deliveryKey]deliveryCounts) map[deliveryKey]deliveryCounts {
+	ret := map[deliveryKey]deliveryCounts{}
+	for key, c := range later {
+		before := earlier[key]
+		if c.events >= before.events && c.failed >= before.failed {
+			c.events -= before.events
+			c.failed -= before.failed
+		}
+		ret[key] = c
+	}
+	return ret
+}
+
+// printDeliveryCounts prints the counts sorted by broker, with the ingress first
+func printDeliveryCounts(out io.Writer, counts map[deliveryKey]deliveryCounts) error {
+	keys := make([]deliveryKey, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].broker != keys[j].broker {
+			return keys[i].broker < keys[j].broker
+		}
+		if (keys[i].trigger == ingressRow) != (keys[j].trigger == ingressRow) {
+			return keys[i].trigger == ingressRow
+		}
+		return keys[i].trigger < keys[j].trigger
+	})
+
+	tw := printers.NewTabWriter(out)
+	fmt.Fprintln(tw, "BROKER\tTRIGGER\tEVENTS\tFAILED")
+	for _, key := range keys {
+		c := counts[key]
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\n", key.broker, key.trigger, c.events, c.failed)
+	}
+	return tw.Flush()
+}
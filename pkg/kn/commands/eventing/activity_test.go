@@ -0,0 +1,152 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventing
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+
+	clienteventingv1beta1 "knative.dev/client/pkg/eventing/v1beta1"
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/util"
+)
+
+const filterMetrics = `# HELP mt_broker_filter_event_count Number of events received by a Trigger
+# TYPE mt_broker_filter_event_count counter
+mt_broker_filter_event_count{broker_name="default",namespace_name="default",response_code="202",response_code_class="2xx",trigger_name="t1"} %d
+mt_broker_filter_event_count{broker_name="default",namespace_name="default",response_code="500",response_code_class="5xx",trigger_name="t1"} 3
+mt_broker_filter_event_count{broker_name="default",namespace_name="other",response_code="202",response_code_class="2xx",trigger_name="t1"} 50
+# HELP mt_broker_filter_event_dispatch_latencies The time spent dispatching an event to a Trigger subscriber
+# TYPE mt_broker_filter_event_dispatch_latencies histogram
+mt_broker_filter_event_dispatch_latencies_bucket{broker_name="default",namespace_name="default",le="+Inf"} 10
+mt_broker_filter_event_dispatch_latencies_sum{broker_name="default",namespace_name="default"} 1
+mt_broker_filter_event_dispatch_latencies_count{broker_name="default",namespace_name="default"} 10
+`
+
+const ingressMetrics = `# TYPE mt_broker_ingress_event_count counter
+mt_broker_ingress_event_count{broker_name="default",event_type="dev.example",namespace_name="default",response_code="202",response_code_class="2xx"} 20
+mt_broker_ingress_event_count{broker_name="default",event_type="dev.example",namespace_name="default",response_code="400",response_code_class="4xx"} 1
+`
+
+func brokerPod(name string, role string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "knative-eventing", Labels: map[string]string{brokerRoleLabelKey: role}},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+}
+
+func executeActivityCommand(t *testing.T, pods []runtime.Object, metrics func(pod string) string, args ...string) (string, error) {
+	eventingClient := clienteventingv1beta1.NewMockKnEventingClient(t)
+	eventingRecorder := eventingClient.Recorder()
+	eventingRecorder.ListTriggers(&eventingv1beta1.TriggerList{Items: []eventingv1beta1.Trigger{
+		{ObjectMeta: metav1.ObjectMeta{Name: "t1", Namespace: "default"}, Spec: eventingv1beta1.TriggerSpec{Broker: "default"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "idle", Namespace: "default"}, Spec: eventingv1beta1.TriggerSpec{Broker: "default"}},
+	}}, nil)
+
+	oldFetch := fetchPodMetrics
+	defer func() { fetchPodMetrics = oldFetch }()
+	fetchPodMetrics = func(client kubernetes.Interface, namespace string, name string, port string) ([]byte, error) {
+		assert.Equal(t, namespace, "knative-eventing")
+		assert.Equal(t, port, "9092")
+		return []byte(metrics(name)), nil
+	}
+
+	knParams := &commands.KnParams{}
+	output := new(bytes.Buffer)
+	knParams.Output = output
+	knParams.NewEventingClient = func(namespace string) (clienteventingv1beta1.KnEventingClient, error) {
+		return eventingClient, nil
+	}
+	knParams.NewKubeClient = func() (kubernetes.Interface, error) {
+		return commands.NewFakeKubeClient(pods...), nil
+	}
+
+	cmd := NewEventingCommand(knParams)
+	cmd.SetArgs(append([]string{"activity"}, args...))
+	cmd.SetOutput(output)
+	err := cmd.Execute()
+	if err == nil {
+		eventingRecorder.Validate()
+	}
+	return output.String(), err
+}
+
+func TestEventingActivity(t *testing.T) {
+	pods := []runtime.Object{
+		brokerPod("filter", brokerRoleFilter, corev1.PodRunning),
+		brokerPod("ingress", brokerRoleIngress, corev1.PodRunning),
+		brokerPod("starting", brokerRoleFilter, corev1.PodPending),
+	}
+	output, err := executeActivityCommand(t, pods, func(pod string) string {
+		switch pod {
+		case "filter":
+			return fmt.Sprintf(filterMetrics, 17)
+		case "ingress":
+			return ingressMetrics
+		}
+		t.Fatalf("unexpected scrape of pod %s", pod)
+		return ""
+	}, "-n", "default")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Event deliveries in namespace 'default' since the broker pods started"))
+	lines := strings.Split(output, "\n")
+	assert.Assert(t, util.ContainsAll(lines[2], "BROKER", "TRIGGER", "EVENTS", "FAILED"))
+	assert.Assert(t, util.ContainsAll(lines[3], "default", "(ingress)", "21", "1"))
+	assert.Assert(t, util.ContainsAll(lines[4], "default", "idle", "0", "0"))
+	assert.Assert(t, util.ContainsAll(lines[5], "default", "t1", "20", "3"))
+}
+
+func TestEventingActivityInterval(t *testing.T) {
+	oldSleep := activitySleep
+	defer func() { activitySleep = oldSleep }()
+	var slept time.Duration
+	activitySleep = func(d time.Duration) { slept = d }
+
+	delivered := 10
+	pods := []runtime.Object{brokerPod("filter", brokerRoleFilter, corev1.PodRunning)}
+	output, err := executeActivityCommand(t, pods, func(pod string) string {
+		metrics := fmt.Sprintf(filterMetrics, delivered)
+		delivered += 5
+		return metrics
+	}, "--interval", "1m", "-n", "default")
+	assert.NilError(t, err)
+	assert.Equal(t, slept, time.Minute)
+	assert.Assert(t, util.ContainsAll(output, "during 1m0s"))
+	lines := strings.Split(output, "\n")
+	assert.Assert(t, util.ContainsAll(lines[4], "t1", "5", "0"))
+}
+
+func TestEventingActivityNoBrokerPods(t *testing.T) {
+	_, err := executeActivityCommand(t, nil, func(string) string { return "" })
+	assert.ErrorContains(t, err, "no running broker pods found in namespace 'knative-eventing'")
+}
+
+func TestSubtractDeliveryCountsRestart(t *testing.T) {
+	key := deliveryKey{broker: "default", trigger: "t1"}
+	counts := subtractDeliveryCounts(
+		map[deliveryKey]deliveryCounts{key: {events: 4, failed: 1}},
+		map[deliveryKey]deliveryCounts{key: {events: 100, failed: 0}})
+	assert.Equal(t, counts[key], deliveryCounts{events: 4, failed: 1})
+}
@@ -0,0 +1,32 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventing
+
+import (
+	"github.com/spf13/cobra"
+
+	"knative.dev/client/pkg/kn/commands"
+)
+
+// NewEventingCommand is the command group for operations spanning all eventing resources
+func NewEventingCommand(p *commands.KnParams) *cobra.Command {
+	eventingCmd := &cobra.Command{
+		Use:   "eventing",
+		Short: "Manage the eventing topology of a namespace",
+	}
+	eventingCmd.AddCommand(NewEventingExportCommand(p))
+	eventingCmd.AddCommand(NewEventingActivityCommand(p))
+	return eventingCmd
+}
@@ -0,0 +1,202 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventing
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"knative.dev/client/pkg/kn/commands"
+)
+
+// Annotations which are set by the cluster and must not be part of an export
+var ignoredAnnotations = []string{
+	"eventing.knative.dev/creator",
+	"eventing.knative.dev/lastModifier",
+	"messaging.knative.dev/creator",
+	"messaging.knative.dev/lastModifier",
+	"sources.knative.dev/creator",
+	"sources.knative.dev/lastModifier",
+	"kubectl.kubernetes.io/last-applied-configuration",
+}
+
+// NewEventingExportCommand returns a new command for exporting the eventing topology of a namespace
+func NewEventingExportCommand(p *commands.KnParams) *cobra.Command {
+	machineReadablePrintFlags := genericclioptions.NewPrintFlags("")
+
+	command := &cobra.Command{
+		Use:   "export",
+		Short: "Export brokers, triggers, sources, channels and subscriptions of a namespace",
+		Long: "Export all eventing resources of a namespace as a list which can be applied to re-create them, " +
+			"e.g. with 'kubectl apply -f'. Resources are ordered so that channels and brokers are created " +
+			"before the sources, subscriptions and triggers referring to them.",
+		Example: `
+  # Export the eventing topology of namespace 'myns' in YAML format
+  kn eventing export -n myns -o yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return errors.New("'kn eventing export' accepts no arguments")
+			}
+			if !machineReadablePrintFlags.OutputFlagSpecified() {
+				return errors.New("'kn eventing export' requires output format")
+			}
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+
+			objects, err := collectEventingObjects(p, namespace)
+			if err != nil {
+				return err
+			}
+			exportList := &unstructured.UnstructuredList{}
+			exportList.SetAPIVersion("v1")
+			exportList.SetKind("List")
+			for _, obj := range objects {
+				exported, err := exportObject(obj)
+				if err != nil {
+					return err
+				}
+				exportList.Items = append(exportList.Items, *exported)
+			}
+
+			printer, err := machineReadablePrintFlags.ToPrinter()
+			if err != nil {
+				return err
+			}
+			return printer.PrintObj(exportList, cmd.OutOrStdout())
+		},
+	}
+	commands.AddNamespaceFlags(command.Flags(), false)
+	machineReadablePrintFlags.AddFlags(command)
+	return command
+}
+
+// collectEventingObjects returns all eventing resources in the order in which they should be created
+func collectEventingObjects(p *commands.KnParams, namespace string) ([]runtime.Object, error) {
+	var objects []runtime.Object
+	// Objects with a controller (e.g. the channels and subscriptions of a channel based broker)
+	// are re-created by their controller and would conflict with it when being applied
+	add := func(obj runtime.Object) {
+		accessor, err := meta.Accessor(obj)
+		if err == nil && metav1.GetControllerOf(accessor) != nil {
+			return
+		}
+		objects = append(objects, obj)
+	}
+
+	messagingClient, err := p.NewMessagingClient(namespace)
+	if err != nil {
+		return nil, err
+	}
+	channelList, err := messagingClient.ChannelsClient().ListChannel()
+	if err != nil {
+		return nil, err
+	}
+	for i := range channelList.Items {
+		add(&channelList.Items[i])
+	}
+
+	eventingClient, err := p.NewEventingClient(namespace)
+	if err != nil {
+		return nil, err
+	}
+	brokerList, err := eventingClient.ListBrokers()
+	if err != nil {
+		return nil, err
+	}
+	for i := range brokerList.Items {
+		add(&brokerList.Items[i])
+	}
+
+	dynamicClient, err := p.NewDynamicClient(namespace)
+	if err != nil {
+		return nil, err
+	}
+	sourceTypes, err := dynamicClient.ListSourcesTypes()
+	if err != nil {
+		return nil, err
+	}
+	// Sources are optional, ListSources fails if no source type is installed
+	if sourceTypes != nil && len(sourceTypes.Items) > 0 {
+		sourceList, err := dynamicClient.ListSources()
+		if err != nil {
+			return nil, err
+		}
+		sources := sourceList.Items
+		sort.SliceStable(sources, func(i, j int) bool {
+			if sources[i].GetKind() != sources[j].GetKind() {
+				return sources[i].GetKind() < sources[j].GetKind()
+			}
+			return sources[i].GetName() < sources[j].GetName()
+		})
+		for i := range sources {
+			add(&sources[i])
+		}
+	}
+
+	subscriptionList, err := messagingClient.SubscriptionsClient().ListSubscription()
+	if err != nil {
+		return nil, err
+	}
+	for i := range subscriptionList.Items {
+		add(&subscriptionList.Items[i])
+	}
+
+	triggerList, err := eventingClient.ListTriggers()
+	if err != nil {
+		return nil, err
+	}
+	for i := range triggerList.Items {
+		add(&triggerList.Items[i])
+	}
+	return objects, nil
+}
+
+// exportObject keeps only the parts of an object which are needed for re-creating it:
+// type, name, labels, annotations not set by the cluster and the spec
+func exportObject(obj runtime.Object) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	source := &unstructured.Unstructured{Object: content}
+
+	exported := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	exported.SetAPIVersion(source.GetAPIVersion())
+	exported.SetKind(source.GetKind())
+	exported.SetName(source.GetName())
+	if labels := source.GetLabels(); len(labels) > 0 {
+		exported.SetLabels(labels)
+	}
+	annotations := source.GetAnnotations()
+	for _, key := range ignoredAnnotations {
+		delete(annotations, key)
+	}
+	if len(annotations) > 0 {
+		exported.SetAnnotations(annotations)
+	}
+	if spec, ok := content["spec"]; ok {
+		exported.Object["spec"] = spec
+	}
+	return exported, nil
+}
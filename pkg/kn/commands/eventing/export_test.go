@@ -0,0 +1,183 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventing
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	messagingv1beta1 "knative.dev/eventing/pkg/apis/messaging/v1beta1"
+	"knative.dev/pkg/ptr"
+	"sigs.k8s.io/yaml"
+
+	clientdynamic "knative.dev/client/pkg/dynamic"
+	dynamicfake "knative.dev/client/pkg/dynamic/fake"
+	clienteventingv1beta1 "knative.dev/client/pkg/eventing/v1beta1"
+	"knative.dev/client/pkg/kn/commands"
+	clientmessagingv1beta1 "knative.dev/client/pkg/messaging/v1beta1"
+)
+
+// messagingClient combines the mocked channels and subscriptions clients
+type messagingClient struct {
+	channels      clientmessagingv1beta1.KnChannelsClient
+	subscriptions clientmessagingv1beta1.KnSubscriptionsClient
+}
+
+func (c *messagingClient) ChannelsClient() clientmessagingv1beta1.KnChannelsClient {
+	return c.channels
+}
+
+func (c *messagingClient) SubscriptionsClient() clientmessagingv1beta1.KnSubscriptionsClient {
+	return c.subscriptions
+}
+
+func TestEventingExport(t *testing.T) {
+	eventingClient := clienteventingv1beta1.NewMockKnEventingClient(t)
+	eventingRecorder := eventingClient.Recorder()
+	channelsClient := clientmessagingv1beta1.NewMockKnChannelsClient(t)
+	channelsRecorder := channelsClient.Recorder()
+	subscriptionsClient := clientmessagingv1beta1.NewMockKnSubscriptionsClient(t)
+	subscriptionsRecorder := subscriptionsClient.Recorder()
+
+	channelsRecorder.ListChannel(&messagingv1beta1.ChannelList{Items: []messagingv1beta1.Channel{{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "messaging.knative.dev/v1beta1", Kind: "Channel"},
+		ObjectMeta: metav1.ObjectMeta{Name: "ch", Namespace: "default", ResourceVersion: "12"},
+	}}}, nil)
+	eventingRecorder.ListBrokers(&eventingv1beta1.BrokerList{Items: []eventingv1beta1.Broker{{
+		TypeMeta: metav1.TypeMeta{APIVersion: "eventing.knative.dev/v1beta1", Kind: "Broker"},
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "default", Annotations: map[string]string{
+			"eventing.knative.dev/creator":      "admin",
+			"eventing.knative.dev/broker.class": "MTChannelBasedBroker",
+		}},
+	}}}, nil)
+	subscriptionsRecorder.ListSubscription(&messagingv1beta1.SubscriptionList{Items: []messagingv1beta1.Subscription{{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "messaging.knative.dev/v1beta1", Kind: "Subscription"},
+		ObjectMeta: metav1.ObjectMeta{Name: "sub", Namespace: "default"},
+	}, {
+		// Created by the broker for the trigger, must not be exported
+		TypeMeta: metav1.TypeMeta{APIVersion: "messaging.knative.dev/v1beta1", Kind: "Subscription"},
+		ObjectMeta: metav1.ObjectMeta{Name: "default-trigger-1234", Namespace: "default", OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: "eventing.knative.dev/v1beta1",
+			Kind:       "Trigger",
+			Name:       "trigger",
+			Controller: ptr.Bool(true),
+		}}},
+	}}}, nil)
+	eventingRecorder.ListTriggers(&eventingv1beta1.TriggerList{Items: []eventingv1beta1.Trigger{{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "eventing.knative.dev/v1beta1", Kind: "Trigger"},
+		ObjectMeta: metav1.ObjectMeta{Name: "trigger", Namespace: "default"},
+		Spec:       eventingv1beta1.TriggerSpec{Broker: "default"},
+	}}}, nil)
+
+	dynamicClient := dynamicfake.CreateFakeKnDynamicClient("default",
+		newSourceCRD("pingsources", "PingSource"),
+		newSource("ping", "PingSource"))
+
+	knParams := &commands.KnParams{}
+	output := new(bytes.Buffer)
+	knParams.Output = output
+	knParams.NewEventingClient = func(namespace string) (clienteventingv1beta1.KnEventingClient, error) {
+		return eventingClient, nil
+	}
+	knParams.NewMessagingClient = func(namespace string) (clientmessagingv1beta1.KnMessagingClient, error) {
+		return &messagingClient{channels: channelsClient, subscriptions: subscriptionsClient}, nil
+	}
+	knParams.NewDynamicClient = func(namespace string) (clientdynamic.KnDynamicClient, error) {
+		return dynamicClient, nil
+	}
+
+	cmd := NewEventingCommand(knParams)
+	cmd.SetArgs([]string{"export", "-n", "default", "-o", "yaml"})
+	cmd.SetOutput(output)
+	assert.NilError(t, cmd.Execute())
+
+	exported := &unstructured.UnstructuredList{}
+	assert.NilError(t, yaml.Unmarshal(output.Bytes(), &exported.Object))
+	items, _, err := unstructured.NestedSlice(exported.Object, "items")
+	assert.NilError(t, err)
+
+	var kinds []string
+	for _, item := range items {
+		obj := unstructured.Unstructured{Object: item.(map[string]interface{})}
+		kinds = append(kinds, obj.GetKind()+"/"+obj.GetName())
+		assert.Equal(t, obj.GetNamespace(), "")
+		assert.Equal(t, obj.GetResourceVersion(), "")
+		_, found := obj.Object["status"]
+		assert.Assert(t, !found)
+		if obj.GetKind() == "Broker" {
+			assert.DeepEqual(t, obj.GetAnnotations(), map[string]string{"eventing.knative.dev/broker.class": "MTChannelBasedBroker"})
+		}
+	}
+	assert.DeepEqual(t, kinds, []string{"Channel/ch", "Broker/default", "PingSource/ping", "Subscription/sub", "Trigger/trigger"})
+
+	eventingRecorder.Validate()
+	channelsRecorder.Validate()
+	subscriptionsRecorder.Validate()
+}
+
+func TestEventingExportRequiresOutput(t *testing.T) {
+	knParams := &commands.KnParams{}
+	cmd := NewEventingCommand(knParams)
+	cmd.SetArgs([]string{"export"})
+	cmd.SetOutput(new(bytes.Buffer))
+	err := cmd.Execute()
+	assert.ErrorContains(t, err, "requires output format")
+}
+
+func newSourceCRD(name, kind string) runtime.Object {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apiextensions.k8s.io/v1beta1",
+			"kind":       "CustomResourceDefinition",
+			"metadata": map[string]interface{}{
+				"name":   name,
+				"labels": map[string]interface{}{"duck.knative.dev/source": "true"},
+			},
+			"spec": map[string]interface{}{
+				"group":   "sources.knative.dev",
+				"version": "v1alpha2",
+				"names": map[string]interface{}{
+					"kind":   kind,
+					"plural": name,
+				},
+			},
+		},
+	}
+	return obj
+}
+
+func newSource(name, kind string) runtime.Object {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "sources.knative.dev/v1alpha2",
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"namespace": "default",
+				"name":      name,
+			},
+			"spec": map[string]interface{}{
+				"schedule": "* * * * *",
+			},
+			"status": map[string]interface{}{
+				"sinkUri": "http://foo",
+			},
+		},
+	}
+}
@@ -15,7 +15,9 @@
 package flags
 
 import (
+	"fmt"
 	"io"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -32,20 +34,48 @@ type ListPrintFlags struct {
 	GenericPrintFlags  *genericclioptions.PrintFlags
 	HumanReadableFlags *commands.HumanPrintFlags
 	PrinterHandler     func(h hprinters.PrintHandler)
+	// SortBy is the JSONPath expression of the field to sort lists by, if set
+	SortBy string
 }
 
+const customColumnsFormat = "custom-columns"
+
 // AllowedFormats is the list of formats in which data can be displayed
 func (f *ListPrintFlags) AllowedFormats() []string {
 	formats := f.GenericPrintFlags.AllowedFormats()
+	formats = append(formats, customColumnsFormat)
 	formats = append(formats, f.HumanReadableFlags.AllowedFormats()...)
 	return formats
 }
 
+// outputFormat returns the format given with --output, empty for the default table
+func (f *ListPrintFlags) outputFormat() string {
+	if f.GenericPrintFlags.OutputFormat == nil || !f.GenericPrintFlags.OutputFlagSpecified() {
+		return ""
+	}
+	return *f.GenericPrintFlags.OutputFormat
+}
+
+// HumanReadable returns true if the output is a table with the columns of the PrinterHandler,
+// which is also the case for "-o wide"
+func (f *ListPrintFlags) HumanReadable() bool {
+	format := f.outputFormat()
+	return format == "" || format == "wide"
+}
+
 // ToPrinter attempts to find a composed set of ListTypesFlags suitable for
 // returning a printer based on current flag values.
 func (f *ListPrintFlags) ToPrinter() (hprinters.ResourcePrinter, error) {
+	format := f.outputFormat()
+	if format == "wide" {
+		f.HumanReadableFlags.Wide = true
+	}
+	if strings.HasPrefix(format, customColumnsFormat) {
+		spec := strings.TrimPrefix(strings.TrimPrefix(format, customColumnsFormat), "=")
+		return hprinters.NewCustomColumnsPrinter(spec, f.HumanReadableFlags.NoHeaders)
+	}
 	// if there are flags specified for generic printing
-	if f.GenericPrintFlags.OutputFlagSpecified() {
+	if !f.HumanReadable() {
 		p, err := f.GenericPrintFlags.ToPrinter()
 		if err != nil {
 			return nil, err
@@ -67,7 +97,14 @@ func (f *ListPrintFlags) Print(obj runtime.Object, w io.Writer) error {
 		return err
 	}
 
-	if f.GenericPrintFlags.OutputFlagSpecified() {
+	if obj != nil && f.SortBy != "" {
+		err = hprinters.SortObjects(obj, f.SortBy)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !f.HumanReadable() {
 		unstructuredList, err := util.ToUnstructuredList(obj)
 		if err != nil {
 			return err
@@ -83,6 +120,14 @@ func (f *ListPrintFlags) Print(obj runtime.Object, w io.Writer) error {
 func (f *ListPrintFlags) AddFlags(cmd *cobra.Command) {
 	f.GenericPrintFlags.AddFlags(cmd)
 	f.HumanReadableFlags.AddFlags(cmd)
+	formats := append(f.GenericPrintFlags.AllowedFormats(), "wide", customColumnsFormat+"=HEADER:JSONPATH,...")
+	cmd.Flag("output").Usage = fmt.Sprintf("Output format. One of: %s.", strings.Join(formats, "|"))
+}
+
+// AddSortFlag adds the flag '--sort-by' for sorting the list by a field given as JSONPath
+func (f *ListPrintFlags) AddSortFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.SortBy, "sort-by", "",
+		"Sort the list by the field given as JSONPath expression, e.g. '.metadata.creationTimestamp'. Numbers are sorted by value.")
 }
 
 // NewListFlags returns flags associated with humanreadable,
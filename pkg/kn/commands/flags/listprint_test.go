@@ -15,17 +15,23 @@
 package flags
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/spf13/cobra"
 	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+
 	hprinters "knative.dev/client/pkg/printers"
+	"knative.dev/client/pkg/util"
 )
 
 func TestListPrintFlagsFormats(t *testing.T) {
 	flags := NewListPrintFlags(nil)
 	formats := flags.AllowedFormats()
-	expected := []string{"json", "yaml", "name", "go-template", "go-template-file", "template", "templatefile", "jsonpath", "jsonpath-file", "no-headers"}
+	expected := []string{"json", "yaml", "name", "go-template", "go-template-file", "template", "templatefile", "jsonpath", "jsonpath-file", "custom-columns", "wide", "no-headers"}
 	assert.DeepEqual(t, formats, expected)
 }
 
@@ -63,3 +69,45 @@ func TestListPrintFlagsPrint(t *testing.T) {
 	err = flags.Print(nil, cmd.OutOrStdout())
 	assert.NilError(t, err)
 }
+
+func TestListPrintFlagsWideCustomColumnsAndSort(t *testing.T) {
+	flags := NewListPrintFlags(func(h hprinters.PrintHandler) {
+		columns := []metav1beta1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Priority: 1},
+			{Name: "Node", Type: "string", Priority: hprinters.WidePriority},
+		}
+		h.TableHandler(columns, func(list *corev1.PodList, options hprinters.PrintOptions) ([]metav1beta1.TableRow, error) {
+			var rows []metav1beta1.TableRow
+			for _, pod := range list.Items {
+				row := metav1beta1.TableRow{Cells: []interface{}{pod.Name}}
+				if options.Wide {
+					row.Cells = append(row.Cells, pod.Spec.NodeName)
+				}
+				rows = append(rows, row)
+			}
+			return rows, nil
+		})
+	})
+	cmd := &cobra.Command{}
+	flags.AddFlags(cmd)
+	flags.AddSortFlag(cmd)
+	assert.Assert(t, util.ContainsAll(cmd.Flag("output").Usage, "json|yaml", "|wide|", "custom-columns=HEADER:JSONPATH,..."))
+
+	podType := metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"}
+	newList := func() *corev1.PodList {
+		return &corev1.PodList{Items: []corev1.Pod{
+			{TypeMeta: podType, ObjectMeta: metav1.ObjectMeta{Name: "b"}, Spec: corev1.PodSpec{NodeName: "node-1"}},
+			{TypeMeta: podType, ObjectMeta: metav1.ObjectMeta{Name: "a"}, Spec: corev1.PodSpec{NodeName: "node-2"}},
+		}}
+	}
+	out := new(bytes.Buffer)
+	assert.NilError(t, cmd.Flags().Set("output", "wide"))
+	assert.NilError(t, flags.Print(newList(), out))
+	assert.Equal(t, out.String(), "NAME   NODE\nb      node-1\na      node-2\n")
+
+	out.Reset()
+	assert.NilError(t, cmd.Flags().Set("output", "custom-columns=POD:.metadata.name"))
+	assert.NilError(t, cmd.Flags().Set("sort-by", ".metadata.name"))
+	assert.NilError(t, flags.Print(newList(), out))
+	assert.Equal(t, out.String(), "POD\na\nb\n")
+}
@@ -16,11 +16,16 @@ package flags
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
@@ -30,7 +35,8 @@ import (
 )
 
 type SinkFlags struct {
-	sink string
+	sink     string
+	validate bool
 }
 
 // AddWithFlagName configures sink flag with given flag name and a short flag name
@@ -65,6 +71,19 @@ func (i *SinkFlags) Add(cmd *cobra.Command) {
 	i.AddWithFlagName(cmd, "sink", "s")
 }
 
+// Set sets the sink, for commands which take the sink as argument instead of as flag
+func (i *SinkFlags) Set(sink string) {
+	i.sink = sink
+}
+
+// AddValidateFlag adds the flag '--validate-sink' for checking that the sink is addressable
+// before the referring object gets created
+func (i *SinkFlags) AddValidateFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&i.validate, "validate-sink", false,
+		"Verify that the sink exists and is addressable before creating the object. "+
+			"Without this check, an object with a missing sink is created but never becomes ready.")
+}
+
 // sinkPrefixes maps prefixes used for sinks to their GroupVersionResources.
 var sinkMappings = map[string]schema.GroupVersionResource{
 	"broker": {
@@ -85,6 +104,16 @@ var sinkMappings = map[string]schema.GroupVersionResource{
 	},
 }
 
+// SinkPrefixes returns the prefixes which can be used for a sink, sorted by name
+func SinkPrefixes() []string {
+	prefixes := make([]string, 0, len(sinkMappings))
+	for prefix := range sinkMappings {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	return prefixes
+}
+
 // ResolveSink returns the Destination referred to by the flags in the acceptor.
 // It validates that any object the user is referring to exists.
 func (i *SinkFlags) ResolveSink(knclient clientdynamic.KnDynamicClient, namespace string) (*duckv1.Destination, error) {
@@ -111,8 +140,17 @@ func (i *SinkFlags) ResolveSink(knclient clientdynamic.KnDynamicClient, namespac
 	}
 	obj, err := client.Resource(typ).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
 	if err != nil {
+		if i.validate && apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("sink %s '%s' (%s) not found in namespace '%s'", prefix, name, typ.GroupResource(), namespace)
+		}
 		return nil, err
 	}
+	if i.validate {
+		err = validateAddressable(obj, prefix)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	destination := &duckv1.Destination{
 		Ref: &duckv1.KReference{
@@ -125,6 +163,32 @@ func (i *SinkFlags) ResolveSink(knclient clientdynamic.KnDynamicClient, namespac
 	return destination, nil
 }
 
+// validateAddressable checks that the sink object has published an address
+func validateAddressable(obj *unstructured.Unstructured, prefix string) error {
+	url, _, err := unstructured.NestedString(obj.Object, "status", "address", "url")
+	if err != nil {
+		return err
+	}
+	if url != "" {
+		return nil
+	}
+	msg := fmt.Sprintf("sink %s '%s' in namespace '%s' is not addressable: it has no address in its status", prefix, obj.GetName(), obj.GetNamespace())
+	resource := &duckv1.KResource{}
+	if runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, resource) == nil {
+		if ready := resource.Status.GetCondition(apis.ConditionReady); ready != nil {
+			msg += fmt.Sprintf(" (Ready=%s", ready.Status)
+			if ready.Reason != "" {
+				msg += ", reason: " + ready.Reason
+			}
+			if ready.Message != "" {
+				msg += ", message: " + ready.Message
+			}
+			msg += ")"
+		}
+	}
+	return errors.New(msg)
+}
+
 // parseSink takes the string given by the user into the prefix and the name of
 // the object. If the user put a URI instead, the prefix is empty and the name
 // is the whole URI.
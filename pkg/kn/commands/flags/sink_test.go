@@ -122,7 +122,7 @@ func TestResolve(t *testing.T) {
 	}
 	dynamicClient := dynamicfake.CreateFakeKnDynamicClient("default", mysvc, defaultBroker, pipeChannel)
 	for _, c := range cases {
-		i := &SinkFlags{c.sink}
+		i := &SinkFlags{sink: c.sink}
 		result, err := i.ResolveSink(dynamicClient, "default")
 		if c.destination != nil {
 			assert.DeepEqual(t, result, c.destination)
@@ -132,3 +132,54 @@ func TestResolve(t *testing.T) {
 		}
 	}
 }
+
+func TestResolveWithValidation(t *testing.T) {
+	readyURL, err := apis.ParseURL("http://mysvc.default.example.com")
+	assert.NilError(t, err)
+
+	readySvc := &servingv1.Service{
+		TypeMeta:   metav1.TypeMeta{Kind: "Service", APIVersion: "serving.knative.dev/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "ready", Namespace: "default"},
+	}
+	readySvc.Status.Address = &duckv1.Addressable{URL: readyURL}
+	failingSvc := &servingv1.Service{
+		TypeMeta:   metav1.TypeMeta{Kind: "Service", APIVersion: "serving.knative.dev/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "failing", Namespace: "default"},
+	}
+	failingSvc.Status.Conditions = duckv1.Conditions{{
+		Type:    apis.ConditionReady,
+		Status:  "False",
+		Reason:  "RevisionFailed",
+		Message: "image pull failed",
+	}}
+	pendingBroker := &eventingv1beta1.Broker{
+		TypeMeta:   metav1.TypeMeta{Kind: "Broker", APIVersion: "eventing.knative.dev/v1beta1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "default"},
+	}
+
+	cases := []resolveCase{
+		{"ksvc:ready", &duckv1.Destination{
+			Ref: &duckv1.KReference{Kind: "Service",
+				APIVersion: "serving.knative.dev/v1",
+				Namespace:  "default",
+				Name:       "ready"}}, ""},
+		{"http://target.example.com", &duckv1.Destination{
+			URI: &apis.URL{Scheme: "http", Host: "target.example.com"},
+		}, ""},
+		{"ksvc:absent", nil, "sink ksvc 'absent' (services.serving.knative.dev) not found in namespace 'default'"},
+		{"ksvc:failing", nil, "sink ksvc 'failing' in namespace 'default' is not addressable: it has no address in its status " +
+			"(Ready=False, reason: RevisionFailed, message: image pull failed)"},
+		{"broker:pending", nil, "sink broker 'pending' in namespace 'default' is not addressable: it has no address in its status"},
+	}
+	dynamicClient := dynamicfake.CreateFakeKnDynamicClient("default", readySvc, failingSvc, pendingBroker)
+	for _, c := range cases {
+		i := &SinkFlags{sink: c.sink, validate: true}
+		result, err := i.ResolveSink(dynamicClient, "default")
+		if c.destination != nil {
+			assert.NilError(t, err)
+			assert.DeepEqual(t, result, c.destination)
+		} else {
+			assert.ErrorContains(t, err, c.errContents)
+		}
+	}
+}
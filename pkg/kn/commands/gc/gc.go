@@ -0,0 +1,205 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/yaml"
+
+	"knative.dev/client/pkg/kn/commands"
+	servinglib "knative.dev/client/pkg/serving"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+)
+
+// DefaultImage is the kn image run by the generated CronJob
+const DefaultImage = "gcr.io/knative-releases/knative.dev/client/cmd/kn"
+
+type gcFlags struct {
+	expired        bool
+	dryRun         bool
+	cronJob        bool
+	schedule       string
+	image          string
+	serviceAccount string
+}
+
+var gcExample = `
+  # Delete the expired ephemeral services in the current namespace
+  kn gc --expired
+
+  # Show the expired ephemeral services of all namespaces without deleting them
+  kn gc --expired -A --dry-run
+
+  # Install a CronJob in namespace 'tools' which deletes expired services of all namespaces every 15 minutes
+  kn gc --expired -A --cronjob --service-account kn-gc | kubectl apply -n tools -f -`
+
+// NewGcCommand returns a new command for deleting expired ephemeral services
+func NewGcCommand(p *commands.KnParams) *cobra.Command {
+	var flags gcFlags
+
+	command := &cobra.Command{
+		Use:   "gc --expired",
+		Short: "Delete expired ephemeral services",
+		Long: "Delete the services created with 'kn service create --ephemeral' whose time to live is over. " +
+			"The command can be run manually, or regularly by a CronJob generated with --cronjob. " +
+			"The service account of the CronJob must be allowed to list and delete Knative services.",
+		Example: gcExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return errors.New("'kn gc' accepts no arguments")
+			}
+			if !flags.expired {
+				return errors.New("'kn gc' requires --expired, which is the only kind of garbage collected so far")
+			}
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			if flags.cronJob {
+				return printCronJob(flags, namespace, cmd.Flags().Changed("namespace"), cmd.OutOrStdout())
+			}
+			return deleteExpired(p, namespace, flags.dryRun, time.Now(), cmd.OutOrStdout())
+		},
+	}
+	commands.AddNamespaceFlags(command.Flags(), true)
+	command.Flags().BoolVar(&flags.expired, "expired", false, "Delete the ephemeral services which are expired.")
+	command.Flags().BoolVar(&flags.dryRun, "dry-run", false, "Only print the services which would be deleted.")
+	command.Flags().BoolVar(&flags.cronJob, "cronjob", false,
+		"Print a CronJob running 'kn gc' with the given options regularly instead of deleting services.")
+	command.Flags().StringVar(&flags.schedule, "schedule", "*/15 * * * *", "Cron schedule of the CronJob printed with --cronjob.")
+	command.Flags().StringVar(&flags.image, "image", DefaultImage, "kn image run by the CronJob printed with --cronjob.")
+	command.Flags().StringVar(&flags.serviceAccount, "service-account", "",
+		"Service account of the CronJob printed with --cronjob. It must be allowed to list and delete Knative services.")
+	return command
+}
+
+// deleteExpired deletes the ephemeral services expired at the given time. An
+// empty namespace stands for all namespaces.
+func deleteExpired(p *commands.KnParams, namespace string, dryRun bool, now time.Time, out io.Writer) error {
+	client, err := p.NewServingClient(namespace)
+	if err != nil {
+		return err
+	}
+	services, err := client.ListServices(clientservingv1.WithLabel(servinglib.EphemeralLabelKey, "true"))
+	if err != nil {
+		return err
+	}
+
+	errs := []string{}
+	deleted := 0
+	for i := range services.Items {
+		service := &services.Items[i]
+		expiry, ephemeral, err := servinglib.EphemeralExpiry(service)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if !ephemeral || expiry.After(now) {
+			continue
+		}
+		deleted++
+		if dryRun {
+			fmt.Fprintf(out, "Service '%s' in namespace '%s' expired at %s and would be deleted.\n",
+				service.Name, service.Namespace, expiry.Format(time.RFC3339))
+			continue
+		}
+		err = deleteService(p, client, namespace, service)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		fmt.Fprintf(out, "Service '%s' in namespace '%s' expired at %s and was deleted.\n",
+			service.Name, service.Namespace, expiry.Format(time.RFC3339))
+	}
+	if deleted == 0 && len(errs) == 0 {
+		fmt.Fprintln(out, "No expired services found.")
+	}
+	if len(errs) > 0 {
+		return errors.New("Error: " + strings.Join(errs, "\nError: "))
+	}
+	return nil
+}
+
+func deleteService(p *commands.KnParams, client clientservingv1.KnServingClient, namespace string, service *servingv1.Service) error {
+	// The client of all namespaces can't delete, a client for the namespace of the service is needed
+	if namespace == "" {
+		var err error
+		client, err = p.NewServingClient(service.Namespace)
+		if err != nil {
+			return err
+		}
+	}
+	return client.DeleteService(service.Name, 0)
+}
+
+// printCronJob prints a CronJob running 'kn gc' for the given namespace, or all
+// namespaces if it is empty. The namespace is passed on only if it was given
+// explicitly, otherwise the namespace of the CronJob is used.
+func printCronJob(flags gcFlags, namespace string, namespaceGiven bool, out io.Writer) error {
+	args := []string{"gc", "--expired"}
+	switch {
+	case namespace == "":
+		args = append(args, "--all-namespaces")
+	case namespaceGiven:
+		args = append(args, "--namespace", namespace)
+	}
+	if flags.dryRun {
+		args = append(args, "--dry-run")
+	}
+
+	cronJob := &batchv1beta1.CronJob{
+		TypeMeta: metav1.TypeMeta{APIVersion: "batch/v1beta1", Kind: "CronJob"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "kn-gc",
+			Labels: map[string]string{"app.kubernetes.io/name": "kn-gc"},
+		},
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule:          flags.schedule,
+			ConcurrencyPolicy: batchv1beta1.ForbidConcurrent,
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							ServiceAccountName: flags.serviceAccount,
+							RestartPolicy:      corev1.RestartPolicyOnFailure,
+							Containers: []corev1.Container{{
+								Name:  "kn",
+								Image: flags.image,
+								Args:  args,
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+	content, err := yaml.Marshal(cronJob)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(content)
+	return err
+}
@@ -0,0 +1,135 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gc
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	servinglib "knative.dev/client/pkg/serving"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+	"knative.dev/client/pkg/util/mock"
+)
+
+var blankConfig clientcmd.ClientConfig
+
+func init() {
+	var err error
+	blankConfig, err = clientcmd.NewClientConfigFromBytes([]byte(`kind: Config
+version: v1
+users:
+- name: u
+clusters:
+- name: c
+  cluster:
+    server: example.com
+contexts:
+- name: x
+  context:
+    user: u
+    cluster: c
+current-context: x
+`))
+	if err != nil {
+		panic(err)
+	}
+}
+
+func executeGcCommand(client clientservingv1.KnServingClient, args ...string) (string, error) {
+	p := &commands.KnParams{}
+	p.ClientConfig = blankConfig
+	p.NewServingClient = func(namespace string) (clientservingv1.KnServingClient, error) {
+		return client, nil
+	}
+	output := new(bytes.Buffer)
+	cmd := NewGcCommand(p)
+	cmd.SetArgs(args)
+	cmd.SetOutput(output)
+	err := cmd.Execute()
+	return output.String(), err
+}
+
+func ephemeralService(name string, namespace string, expiry time.Time) servingv1.Service {
+	service := servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	servinglib.MarkEphemeral(&service, expiry)
+	return service
+}
+
+func TestGcExpired(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.ListServices(mock.Any(), &servingv1.ServiceList{Items: []servingv1.Service{
+		ephemeralService("old", "ns1", time.Now().Add(-time.Hour)),
+		ephemeralService("new", "ns1", time.Now().Add(time.Hour)),
+		ephemeralService("failing", "ns2", time.Now().Add(-time.Minute)),
+	}}, nil)
+	r.DeleteService("old", time.Duration(0), nil)
+	r.DeleteService("failing", time.Duration(0), errors.New("forbidden"))
+
+	output, err := executeGcCommand(client, "--expired", "-A")
+	assert.ErrorContains(t, err, "forbidden")
+	assert.Assert(t, util.ContainsAll(output, "Service 'old' in namespace 'ns1' expired at", "was deleted"))
+	assert.Assert(t, util.ContainsNone(output, "'new'"))
+	r.Validate()
+}
+
+func TestGcExpiredDryRun(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.ListServices(mock.Any(), &servingv1.ServiceList{Items: []servingv1.Service{
+		ephemeralService("old", "ns1", time.Now().Add(-time.Hour)),
+	}}, nil)
+
+	output, err := executeGcCommand(client, "--expired", "-n", "ns1", "--dry-run")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "'old'", "would be deleted"))
+	r.Validate()
+
+	r.ListServices(mock.Any(), &servingv1.ServiceList{}, nil)
+	output, err = executeGcCommand(client, "--expired", "-n", "ns1")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "No expired services found"))
+	r.Validate()
+}
+
+func TestGcCronJob(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	output, err := executeGcCommand(client, "--expired", "-A", "--cronjob", "--service-account", "kn-gc", "--schedule", "0 * * * *")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output,
+		"kind: CronJob", "schedule: 0 * * * *", "serviceAccountName: kn-gc", "image: "+DefaultImage,
+		"- gc", "- --expired", "- --all-namespaces"))
+
+	output, err = executeGcCommand(client, "--expired", "-n", "previews", "--cronjob")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "- --namespace", "- previews"))
+}
+
+func TestGcErrors(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	_, err := executeGcCommand(client)
+	assert.ErrorContains(t, err, "requires --expired")
+	_, err = executeGcCommand(client, "--expired", "foo")
+	assert.ErrorContains(t, err, "accepts no arguments")
+}
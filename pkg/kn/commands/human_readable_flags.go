@@ -32,19 +32,20 @@ import (
 type HumanPrintFlags struct {
 	WithNamespace bool
 	NoHeaders     bool
-	//TODO: Add more flags as required
+	NoTruncate    bool
+	// Wide adds the additional columns shown with '-o wide'
+	Wide bool
 }
 
 // AllowedFormats returns more customized formating options
 func (f *HumanPrintFlags) AllowedFormats() []string {
-	// TODO: Add more formats eg: wide
-	return []string{"no-headers"}
+	return []string{"wide", "no-headers"}
 }
 
 // ToPrinter receives returns a printer capable of
 // handling human-readable output.
 func (f *HumanPrintFlags) ToPrinter(getHandlerFunc func(h hprinters.PrintHandler)) (hprinters.ResourcePrinter, error) {
-	p := hprinters.NewTablePrinter(hprinters.PrintOptions{AllNamespaces: f.WithNamespace, NoHeaders: f.NoHeaders})
+	p := hprinters.NewTablePrinter(hprinters.PrintOptions{AllNamespaces: f.WithNamespace, NoHeaders: f.NoHeaders, NoTruncate: f.NoTruncate, Wide: f.Wide})
 	getHandlerFunc(p)
 	return p, nil
 }
@@ -53,6 +54,7 @@ func (f *HumanPrintFlags) ToPrinter(getHandlerFunc func(h hprinters.PrintHandler
 // flags related to human-readable printing to it
 func (f *HumanPrintFlags) AddFlags(c *cobra.Command) {
 	c.Flags().BoolVar(&f.NoHeaders, "no-headers", false, "When using the default output format, don't print headers (default: print headers).")
+	c.Flags().BoolVar(&f.NoTruncate, "no-truncate", false, "When using the default output format, don't truncate columns to fit the terminal width (default: truncate).")
 	//TODO: Add more flags as required
 }
 
@@ -111,5 +113,8 @@ func TranslateTimestampSince(timestamp metav1.Time) string {
 	if timestamp.IsZero() {
 		return "<unknown>"
 	}
+	if hprinters.OmitTimestamps() {
+		return hprinters.TimestampPlaceholder
+	}
 	return duration.HumanDuration(time.Since(timestamp.Time))
 }
@@ -0,0 +1,52 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/spf13/pflag"
+)
+
+// NonInteractiveEnvVar is set for plugins when kn runs in non-interactive mode,
+// so that they can behave the same
+const NonInteractiveEnvVar = "KN_NON_INTERACTIVE"
+
+// IsNonInteractive returns true if non-interactive mode has been requested with
+// --non-interactive in the given (parsed) flags or with KN_NON_INTERACTIVE.
+// It's meant for the time before the environment is bound to the flags, e.g.
+// before a plugin is executed.
+func IsNonInteractive(flags *pflag.FlagSet) bool {
+	flag := flags.Lookup("non-interactive")
+	if flag != nil && flag.Changed {
+		value, _ := strconv.ParseBool(flag.Value.String())
+		return value
+	}
+	value, _ := strconv.ParseBool(os.Getenv(NonInteractiveEnvVar))
+	return value
+}
+
+// DisableStdin replaces stdin with the null device, so that neither kn nor any
+// process started by kn (plugins, credential plugins for the kubeconfig) can
+// wait for input from a terminal
+func DisableStdin() error {
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		return err
+	}
+	os.Stdin = devNull
+	return os.Setenv(NonInteractiveEnvVar, "true")
+}
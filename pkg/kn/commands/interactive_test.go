@@ -0,0 +1,55 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"gotest.tools/assert"
+)
+
+func TestIsNonInteractive(t *testing.T) {
+	defer setEnv(t, NonInteractiveEnvVar, "")()
+
+	newFlagSet := func(args ...string) *pflag.FlagSet {
+		flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flagSet.Bool("non-interactive", false, "")
+		assert.NilError(t, flagSet.Parse(args))
+		return flagSet
+	}
+	assert.Assert(t, !IsNonInteractive(newFlagSet()))
+	assert.Assert(t, IsNonInteractive(newFlagSet("--non-interactive")))
+
+	os.Setenv(NonInteractiveEnvVar, "true")
+	assert.Assert(t, IsNonInteractive(newFlagSet()))
+	// The command line takes precedence
+	assert.Assert(t, !IsNonInteractive(newFlagSet("--non-interactive=false")))
+}
+
+func TestDisableStdin(t *testing.T) {
+	defer setEnv(t, NonInteractiveEnvVar, "")()
+	oldStdin := os.Stdin
+	defer func() { os.Stdin = oldStdin }()
+
+	assert.NilError(t, DisableStdin())
+	input, err := ioutil.ReadAll(os.Stdin)
+	assert.NilError(t, err)
+	assert.Equal(t, len(input), 0)
+	// Plugins are told about the non-interactive mode
+	assert.Equal(t, os.Getenv(NonInteractiveEnvVar), "true")
+}
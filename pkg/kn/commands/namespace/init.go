@@ -0,0 +1,263 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/util"
+)
+
+const (
+	// BrokerInjectionLabelKey is the namespace label which lets eventing create a default broker
+	BrokerInjectionLabelKey = "eventing.knative.dev/injection"
+
+	// ManagedByLabelKey is set on namespaces created by 'kn namespace init'
+	ManagedByLabelKey = "app.kubernetes.io/managed-by"
+
+	// Name of the service which is deployed for a smoke test
+	smokeTestServiceName = "kn-smoke-test"
+
+	// Service account to which the image pull secret is added
+	defaultServiceAccount = "default"
+)
+
+type initFlags struct {
+	labels           []string
+	brokerInjection  bool
+	imagePullSecret  string
+	dockerConfig     string
+	smokeTest        bool
+	smokeTestImage   string
+	smokeTestTimeout int
+}
+
+// NewNamespaceInitCommand returns a new command for preparing a namespace for Knative
+func NewNamespaceInitCommand(p *commands.KnParams) *cobra.Command {
+	var flags initFlags
+
+	command := &cobra.Command{
+		Use:   "init NAME",
+		Short: "Prepare a namespace for Knative",
+		Long: "Prepare a namespace for Knative. The namespace is created if it does not exist yet, labels are added, " +
+			"and optionally a default broker is injected, an image pull secret is configured for the default service account " +
+			"and a smoke test service is deployed, verified and deleted again. A readiness report is printed at the end.",
+		Example: `
+  # Create namespace 'myns' with a default broker
+  kn namespace init myns --broker-injection
+
+  # Prepare namespace 'myns' for pulling from a private registry and verify it with a smoke test
+  kn namespace init myns --image-pull-secret regcred --docker-config ~/.docker/config.json --smoke-test`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("'namespace init' requires the namespace name given as single argument")
+			}
+			name := args[0]
+			if flags.dockerConfig != "" && flags.imagePullSecret == "" {
+				return errors.New("--docker-config requires --image-pull-secret for the name of the secret to create")
+			}
+			labels, err := util.MapFromArray(flags.labels, "=")
+			if err != nil {
+				return fmt.Errorf("Invalid --label: %s", err.Error())
+			}
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			if flags.brokerInjection {
+				labels[BrokerInjectionLabelKey] = "enabled"
+			}
+
+			kubeClient, err := p.NewKubeClient()
+			if err != nil {
+				return err
+			}
+
+			report := &commands.CheckReport{}
+			err = ensureNamespace(kubeClient, name, labels, report)
+			if err != nil {
+				// All other steps work within the namespace, so there is nothing more to check
+				report.Fail("namespace", err)
+			} else {
+				initNamespace(p, kubeClient, name, flags, report)
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Readiness report for namespace '%s':\n\n", name)
+			err = report.Print(out)
+			if err != nil {
+				return err
+			}
+			if !report.Passed() {
+				return fmt.Errorf("namespace '%s' is not ready for Knative", name)
+			}
+			return nil
+		},
+	}
+	command.Flags().StringArrayVarP(&flags.labels, "label", "l", []string{},
+		"Label to set on the namespace. You may provide this flag any number of times to set multiple labels.")
+	command.Flags().BoolVar(&flags.brokerInjection, "broker-injection", false,
+		"Label the namespace so that eventing injects a default broker.")
+	command.Flags().StringVar(&flags.imagePullSecret, "image-pull-secret", "",
+		"Image pull secret to add to the namespace's default service account.")
+	command.Flags().StringVar(&flags.dockerConfig, "docker-config", "",
+		"Docker config file (e.g. ~/.docker/config.json) from which the image pull secret is created or updated.")
+	command.Flags().BoolVar(&flags.smokeTest, "smoke-test", false,
+		"Deploy a service to the namespace, wait until it is ready and delete it again.")
+	command.Flags().StringVar(&flags.smokeTestImage, "smoke-test-image", commands.DefaultSmokeTestImage,
+		"Image to use for the smoke test service.")
+	command.Flags().IntVar(&flags.smokeTestTimeout, "smoke-test-timeout", commands.WaitDefaultTimeout,
+		"Seconds to wait for the smoke test service to become ready.")
+	return command
+}
+
+// initNamespace runs the optional steps for preparing an existing namespace and records their results
+func initNamespace(p *commands.KnParams, kubeClient kubernetes.Interface, name string, flags initFlags, report *commands.CheckReport) {
+	if flags.brokerInjection {
+		report.Pass("broker-injection", "default broker injection enabled")
+	}
+	if flags.imagePullSecret != "" {
+		err := ensureImagePullSecret(kubeClient, name, flags.imagePullSecret, flags.dockerConfig)
+		if err != nil {
+			report.Fail("image-pull-secret", err)
+		} else {
+			report.Pass("image-pull-secret", fmt.Sprintf("secret '%s' used by service account '%s'", flags.imagePullSecret, defaultServiceAccount))
+		}
+	}
+	if flags.smokeTest {
+		client, err := p.NewServingClient(name)
+		if err != nil {
+			report.Fail("deploy", err)
+			return
+		}
+		commands.SmokeTestService(client, smokeTestServiceName, flags.smokeTestImage,
+			time.Duration(flags.smokeTestTimeout)*time.Second, report, nil)
+	}
+}
+
+// ensureNamespace creates the namespace with the given labels or adds the labels to an existing namespace
+func ensureNamespace(client kubernetes.Interface, name string, labels map[string]string, report *commands.CheckReport) error {
+	namespaces := client.CoreV1().Namespaces()
+	ns, err := namespaces.Get(context.TODO(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		ns = &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{ManagedByLabelKey: "kn"},
+			},
+		}
+		for key, value := range labels {
+			ns.Labels[key] = value
+		}
+		_, err = namespaces.Create(context.TODO(), ns, metav1.CreateOptions{})
+		if err != nil {
+			return err
+		}
+		report.Pass("namespace", "created")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for key, value := range labels {
+		if ns.Labels[key] != value {
+			if ns.Labels == nil {
+				ns.Labels = map[string]string{}
+			}
+			ns.Labels[key] = value
+			changed = true
+		}
+	}
+	if !changed {
+		report.Pass("namespace", "exists")
+		return nil
+	}
+	_, err = namespaces.Update(context.TODO(), ns, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	report.Pass("namespace", "exists, labels updated")
+	return nil
+}
+
+// ensureImagePullSecret creates or updates the image pull secret when a docker config file is given,
+// and adds the secret to the default service account
+func ensureImagePullSecret(client kubernetes.Interface, namespace string, secretName string, dockerConfig string) error {
+	secrets := client.CoreV1().Secrets(namespace)
+	if dockerConfig != "" {
+		data, err := ioutil.ReadFile(dockerConfig)
+		if err != nil {
+			return err
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: namespace,
+			},
+			Type: corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{corev1.DockerConfigJsonKey: data},
+		}
+		_, err = secrets.Create(context.TODO(), secret, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			_, err = secrets.Update(context.TODO(), secret, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			return err
+		}
+	} else {
+		_, err := secrets.Get(context.TODO(), secretName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+	}
+
+	// The default service account is created asynchronously for new namespaces, so create it if missing
+	serviceAccounts := client.CoreV1().ServiceAccounts(namespace)
+	sa, err := serviceAccounts.Get(context.TODO(), defaultServiceAccount, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		sa = &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      defaultServiceAccount,
+				Namespace: namespace,
+			},
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: secretName}},
+		}
+		_, err = serviceAccounts.Create(context.TODO(), sa, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	for _, ref := range sa.ImagePullSecrets {
+		if ref.Name == secretName {
+			return nil
+		}
+	}
+	sa.ImagePullSecrets = append(sa.ImagePullSecrets, corev1.LocalObjectReference{Name: secretName})
+	_, err = serviceAccounts.Update(context.TODO(), sa, metav1.UpdateOptions{})
+	return err
+}
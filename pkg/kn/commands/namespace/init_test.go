@@ -0,0 +1,168 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	clienttesting "k8s.io/client-go/testing"
+
+	"knative.dev/client/pkg/kn/commands"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+	"knative.dev/client/pkg/util/mock"
+	"knative.dev/client/pkg/wait"
+)
+
+func executeNamespaceCommand(kubeClient kubernetes.Interface, servingClient clientservingv1.KnServingClient, args ...string) (string, error) {
+	knParams := &commands.KnParams{}
+	output := new(bytes.Buffer)
+	knParams.Output = output
+	knParams.NewKubeClient = func() (kubernetes.Interface, error) {
+		return kubeClient, nil
+	}
+	knParams.NewServingClient = func(namespace string) (clientservingv1.KnServingClient, error) {
+		return servingClient, nil
+	}
+	cmd := NewNamespaceCommand(knParams)
+	cmd.SetArgs(args)
+	cmd.SetOutput(output)
+	err := cmd.Execute()
+	return output.String(), err
+}
+
+func TestNamespaceInitCreate(t *testing.T) {
+	kubeClient := commands.NewFakeKubeClient()
+	output, err := executeNamespaceCommand(kubeClient, nil, "init", "myns", "--broker-injection", "--label", "team=a")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "myns", "namespace", "PASS", "created", "broker-injection"))
+
+	ns, err := kubeClient.CoreV1().Namespaces().Get(context.TODO(), "myns", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, ns.Labels, map[string]string{
+		ManagedByLabelKey:       "kn",
+		BrokerInjectionLabelKey: "enabled",
+		"team":                  "a",
+	})
+}
+
+func TestNamespaceInitExisting(t *testing.T) {
+	kubeClient := commands.NewFakeKubeClient(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "myns"},
+	})
+	output, err := executeNamespaceCommand(kubeClient, nil, "init", "myns", "--label", "team=a")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "labels updated"))
+
+	ns, err := kubeClient.CoreV1().Namespaces().Get(context.TODO(), "myns", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, ns.Labels, map[string]string{"team": "a"})
+}
+
+func TestNamespaceInitImagePullSecret(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "kn-namespace")
+	assert.NilError(t, err)
+	defer os.RemoveAll(tempDir)
+	dockerConfig := filepath.Join(tempDir, "config.json")
+	assert.NilError(t, ioutil.WriteFile(dockerConfig, []byte(`{"auths":{}}`), 0600))
+
+	kubeClient := commands.NewFakeKubeClient(&corev1.ServiceAccount{
+		ObjectMeta:       metav1.ObjectMeta{Name: "default", Namespace: "myns"},
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "other"}},
+	})
+	output, err := executeNamespaceCommand(kubeClient, nil, "init", "myns", "--image-pull-secret", "regcred", "--docker-config", dockerConfig)
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "image-pull-secret", "PASS", "regcred"))
+
+	secret, err := kubeClient.CoreV1().Secrets("myns").Get(context.TODO(), "regcred", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, secret.Type, corev1.SecretTypeDockerConfigJson)
+	assert.Equal(t, string(secret.Data[corev1.DockerConfigJsonKey]), `{"auths":{}}`)
+
+	sa, err := kubeClient.CoreV1().ServiceAccounts("myns").Get(context.TODO(), "default", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, sa.ImagePullSecrets, []corev1.LocalObjectReference{{Name: "other"}, {Name: "regcred"}})
+}
+
+func TestNamespaceInitMissingImagePullSecret(t *testing.T) {
+	kubeClient := commands.NewFakeKubeClient()
+	output, err := executeNamespaceCommand(kubeClient, nil, "init", "myns", "--image-pull-secret", "regcred")
+	assert.ErrorContains(t, err, "not ready")
+	assert.Assert(t, util.ContainsAll(output, "image-pull-secret", "FAIL", "not found"))
+}
+
+func TestNamespaceInitSmokeTest(t *testing.T) {
+	kubeClient := commands.NewFakeKubeClient()
+	client := clientservingv1.NewMockKnServiceClient(t, "myns")
+	r := client.Recorder()
+	r.CreateService(mock.Any(), nil)
+	r.WaitForService(smokeTestServiceName, 30*time.Second, wait.NoopMessageCallback(), nil, time.Second)
+	r.DeleteService(smokeTestServiceName, 30*time.Second, nil)
+
+	output, err := executeNamespaceCommand(kubeClient, client, "init", "myns", "--smoke-test", "--smoke-test-timeout", "30")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "deploy", "ready", "delete"))
+	assert.Assert(t, util.ContainsNone(output, "FAIL"))
+	r.Validate()
+}
+
+func TestNamespaceInitSmokeTestNotReady(t *testing.T) {
+	kubeClient := commands.NewFakeKubeClient()
+	client := clientservingv1.NewMockKnServiceClient(t, "myns")
+	r := client.Recorder()
+	r.CreateService(mock.Any(), nil)
+	r.WaitForService(smokeTestServiceName, mock.Any(), wait.NoopMessageCallback(), errors.New("revision failed"), time.Second)
+	r.DeleteService(smokeTestServiceName, mock.Any(), nil)
+
+	output, err := executeNamespaceCommand(kubeClient, client, "init", "myns", "--smoke-test")
+	assert.ErrorContains(t, err, "not ready")
+	assert.Assert(t, util.ContainsAll(output, "ready", "FAIL", "revision failed"))
+	r.Validate()
+}
+
+func TestNamespaceInitErrors(t *testing.T) {
+	kubeClient := commands.NewFakeKubeClient()
+	_, err := executeNamespaceCommand(kubeClient, nil, "init")
+	assert.ErrorContains(t, err, "single argument")
+	_, err = executeNamespaceCommand(kubeClient, nil, "init", "myns", "--docker-config", "config.json")
+	assert.ErrorContains(t, err, "--image-pull-secret")
+	_, err = executeNamespaceCommand(kubeClient, nil, "init", "myns", "--label", "novalue")
+	assert.ErrorContains(t, err, "--label")
+}
+
+func TestNamespaceInitNamespaceFailure(t *testing.T) {
+	kubeClient := commands.NewFakeKubeClient()
+	kubeClient.PrependReactor("create", "namespaces", func(a clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("namespaces is forbidden")
+	})
+	output, err := executeNamespaceCommand(kubeClient, nil, "init", "myns", "--broker-injection")
+	assert.ErrorContains(t, err, "not ready")
+	report := strings.Split(output, "Error:")[0]
+	assert.Assert(t, util.ContainsAll(report, "namespace", "FAIL", "namespaces is forbidden"))
+	assert.Assert(t, util.ContainsNone(report, "broker-injection"))
+}
@@ -0,0 +1,31 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"github.com/spf13/cobra"
+
+	"knative.dev/client/pkg/kn/commands"
+)
+
+// NewNamespaceCommand is the command group for preparing namespaces for Knative
+func NewNamespaceCommand(p *commands.KnParams) *cobra.Command {
+	namespaceCmd := &cobra.Command{
+		Use:   "namespace",
+		Short: "Prepare namespaces for Knative",
+	}
+	namespaceCmd.AddCommand(NewNamespaceInitCommand(p))
+	return namespaceCmd
+}
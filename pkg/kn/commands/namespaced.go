@@ -15,9 +15,16 @@
 package commands
 
 import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"k8s.io/client-go/tools/clientcmd"
+
+	"knative.dev/client/pkg/kn/config"
 )
 
 // AddNamespaceFlags adds the namespace-related flags:
@@ -65,8 +72,63 @@ func (params *KnParams) GetNamespace(cmd *cobra.Command) (string, error) {
 			// If no current namespace is set use "default"
 			namespace = "default"
 		}
+		if params.namespaceRequired() && !params.hasContextNamespace() {
+			return "", errors.New("no namespace given and falling back to namespace 'default' is forbidden: " +
+				"use --namespace or set a namespace in the kubeconfig context")
+		}
+	}
+	return namespace, checkNamespaceAllowed(namespace)
+}
+
+// namespaceRequired returns true if the "default" namespace must not be used implicitly,
+// either because of --namespace-required or because of the kn configuration
+func (params *KnParams) namespaceRequired() bool {
+	return params.NamespaceRequired || config.GlobalConfig.NamespaceRequired()
+}
+
+// hasContextNamespace returns true if the current namespace has been set explicitly and
+// doesn't come from the fallback to "default"
+func (params *KnParams) hasContextNamespace() bool {
+	if params.fixedCurrentNamespace != "" {
+		return true
+	}
+	if params.ClientConfig == nil {
+		return false
+	}
+	rawConfig, err := params.ClientConfig.RawConfig()
+	if err != nil {
+		return false
+	}
+	contextName := rawConfig.CurrentContext
+	if params.kubeContext != "" {
+		contextName = params.kubeContext
+	}
+	context, ok := rawConfig.Contexts[contextName]
+	return ok && context.Namespace != ""
+}
+
+// checkNamespaceAllowed checks the namespace against the allowed and denied namespaces
+// of the kn configuration. Denied namespaces take precedence. The empty namespace for
+// all namespaces is always allowed.
+func checkNamespaceAllowed(namespace string) error {
+	if namespace == "" {
+		return nil
+	}
+	for _, pattern := range config.GlobalConfig.DeniedNamespaces() {
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return fmt.Errorf("namespace '%s' is denied by the kn configuration (pattern '%s')", namespace, pattern)
+		}
+	}
+	allowed := config.GlobalConfig.AllowedNamespaces()
+	if len(allowed) == 0 {
+		return nil
+	}
+	for _, pattern := range allowed {
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return nil
+		}
 	}
-	return namespace, nil
+	return fmt.Errorf("namespace '%s' is not allowed by the kn configuration, allowed are: %s", namespace, strings.Join(allowed, ", "))
 }
 
 // CurrentNamespace returns the current namespace which is either provided as option or picked up from kubeconfig
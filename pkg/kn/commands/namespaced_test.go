@@ -18,6 +18,10 @@ import (
 	"testing"
 
 	"github.com/spf13/cobra"
+	"gotest.tools/assert"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"knative.dev/client/pkg/kn/config"
 )
 
 // testCommandGenerator generates a test cobra command
@@ -122,3 +126,81 @@ func TestGetNamespaceAllNamespacesNotDefined(t *testing.T) {
 		t.Fatalf("Incorrect namespace retrieved: %v, expected: %v", actualNamespace, expectedNamespace)
 	}
 }
+
+// test that a required namespace doesn't fall back to "default"
+func TestGetNamespaceRequired(t *testing.T) {
+	clientConfig, err := clientcmd.NewClientConfigFromBytes([]byte(BASIC_KUBECONFIG))
+	assert.NilError(t, err)
+
+	testCmd := testCommandGenerator(true)
+	testCmd.Execute()
+	kp := &KnParams{ClientConfig: clientConfig, NamespaceRequired: true}
+	_, err = kp.GetNamespace(testCmd)
+	assert.ErrorContains(t, err, "falling back to namespace 'default' is forbidden")
+
+	// An explicit namespace is fine
+	testCmd.SetArgs([]string{"--namespace", "test1"})
+	testCmd.Execute()
+	namespace, err := kp.GetNamespace(testCmd)
+	assert.NilError(t, err)
+	assert.Equal(t, namespace, "test1")
+
+	// The namespace of the kubeconfig context is explicit, too
+	testCmd = testCommandGenerator(true)
+	testCmd.Execute()
+	kp = &KnParams{fixedCurrentNamespace: FakeNamespace, NamespaceRequired: true}
+	namespace, err = kp.GetNamespace(testCmd)
+	assert.NilError(t, err)
+	assert.Equal(t, namespace, FakeNamespace)
+}
+
+// test that the configuration can require a namespace
+func TestGetNamespaceRequiredByConfig(t *testing.T) {
+	defer setGlobalConfig(config.TestConfig{TestNamespaceRequired: true})()
+	clientConfig, err := clientcmd.NewClientConfigFromBytes([]byte(BASIC_KUBECONFIG))
+	assert.NilError(t, err)
+
+	testCmd := testCommandGenerator(true)
+	testCmd.Execute()
+	kp := &KnParams{ClientConfig: clientConfig}
+	_, err = kp.GetNamespace(testCmd)
+	assert.ErrorContains(t, err, "use --namespace or set a namespace in the kubeconfig context")
+}
+
+// test the allowed and denied namespaces of the configuration
+func TestGetNamespaceAllowedAndDenied(t *testing.T) {
+	defer setGlobalConfig(config.TestConfig{
+		TestAllowedNamespaces: []string{"team-*", "current"},
+		TestDeniedNamespaces:  []string{"team-secret"},
+	})()
+
+	for _, tc := range []struct {
+		args        []string
+		errContents string
+	}{
+		{[]string{"--namespace", "team-a"}, ""},
+		{[]string{}, ""},
+		{[]string{"--all-namespaces"}, ""},
+		{[]string{"--namespace", "team-secret"}, "namespace 'team-secret' is denied by the kn configuration (pattern 'team-secret')"},
+		{[]string{"--namespace", "default"}, "namespace 'default' is not allowed by the kn configuration, allowed are: team-*, current"},
+	} {
+		testCmd := testCommandGenerator(true)
+		testCmd.SetArgs(tc.args)
+		testCmd.Execute()
+		kp := &KnParams{fixedCurrentNamespace: FakeNamespace}
+		_, err := kp.GetNamespace(testCmd)
+		if tc.errContents == "" {
+			assert.NilError(t, err)
+		} else {
+			assert.Error(t, err, tc.errContents)
+		}
+	}
+}
+
+func setGlobalConfig(cfg config.Config) func() {
+	oldConfig := config.GlobalConfig
+	config.GlobalConfig = cfg
+	return func() {
+		config.GlobalConfig = oldConfig
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/uuid"
+
+	"knative.dev/client/pkg/kn/config"
+)
+
+const (
+	// Prefix of the CloudEvent types, like "dev.knative.client.service.created"
+	notificationTypePrefix = "dev.knative.client."
+
+	// Source of all CloudEvents sent by kn
+	notificationSource = "/apis/kn"
+
+	// A slow or unreachable sink must not block kn for long
+	notificationTimeout = 5 * time.Second
+)
+
+// Past tense of the operations which are notified, used in the CloudEvent types
+var notifiedOperations = map[string]string{
+	"create": "created",
+	"update": "updated",
+	"delete": "deleted",
+}
+
+// OperationNotification is the data of the CloudEvent sent for a completed operation
+type OperationNotification struct {
+	// Operation is "create", "update" or "delete"
+	Operation string `json:"operation"`
+
+	// Resource is the kind of resource, like "service" or "source.ping"
+	Resource string `json:"resource"`
+
+	// Names are the names of the resources which the command changed
+	Names []string `json:"names"`
+
+	// Namespace of the resources
+	Namespace string `json:"namespace,omitempty"`
+
+	// Command is the kn command without its flags, like "kn service create"
+	Command string `json:"command"`
+}
+
+// RecordChanged records the names of the resources which a command created, updated or
+// deleted, for the notification about the operation. Commands which change other resources
+// than the ones named by their arguments, like with --all, a selector or a generated name,
+// must record them. Without a recorded name the operation is not notified.
+func (params *KnParams) RecordChanged(names ...string) {
+	params.changedRecorded = true
+	params.changedNames = append(params.changedNames, names...)
+}
+
+// NotifyOperation sends a CloudEvent describing the operation of cmd to the
+// sink configured with 'notifications.sink', if cmd is a create, update or
+// delete command. It must only be called after cmd has run successfully.
+// The names are the ones recorded with RecordChanged, or the arguments of cmd
+// if it didn't record any.
+// A failing notification is reported as a warning, but doesn't fail cmd.
+func (params *KnParams) NotifyOperation(cmd *cobra.Command, args []string) {
+	sink := config.GlobalConfig.NotificationSink()
+	_, ok := notifiedOperations[cmd.Name()]
+	if sink == "" || !ok || !cmd.HasParent() {
+		return
+	}
+	if flag := cmd.Flag("dry-run"); flag != nil && flag.Value.String() != "false" && flag.Value.String() != "none" {
+		return
+	}
+	names := args
+	if params.changedRecorded {
+		if len(params.changedNames) == 0 {
+			return
+		}
+		names = params.changedNames
+	}
+
+	notification := OperationNotification{
+		Operation: cmd.Name(),
+		Resource:  strings.Join(strings.Fields(cmd.Parent().CommandPath())[1:], "."),
+		Names:     names,
+		Command:   cmd.CommandPath(),
+	}
+	if cmd.Flag("namespace") != nil {
+		// Errors have been reported by the command already
+		notification.Namespace, _ = params.GetNamespace(cmd)
+	}
+	if err := sendNotification(sink, notification); err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "WARNING: cannot send notification to %s: %v\n", sink, err)
+	}
+}
+
+// sendNotification posts the notification as a CloudEvent in binary content mode
+func sendNotification(sink string, notification OperationNotification) error {
+	if notification.Names == nil {
+		notification.Names = []string{}
+	}
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, sink, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Ce-Specversion", "1.0")
+	req.Header.Set("Ce-Id", string(uuid.NewUUID()))
+	req.Header.Set("Ce-Source", notificationSource)
+	req.Header.Set("Ce-Type", notificationTypePrefix+notification.Resource+"."+notifiedOperations[notification.Operation])
+	req.Header.Set("Ce-Time", time.Now().UTC().Format(time.RFC3339))
+	if len(notification.Names) > 0 {
+		req.Header.Set("Ce-Subject", strings.Join(notification.Names, ","))
+	}
+
+	client := http.Client{Timeout: notificationTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sink responded with %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/assert"
+
+	"knative.dev/client/pkg/kn/config"
+)
+
+func TestNotifyOperation(t *testing.T) {
+	var headers []http.Header
+	var notifications []OperationNotification
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NilError(t, err)
+		var notification OperationNotification
+		assert.NilError(t, json.Unmarshal(body, &notification))
+		headers = append(headers, r.Header)
+		notifications = append(notifications, notification)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer sink.Close()
+
+	p := &KnParams{}
+	newCmd := func(name string) *cobra.Command {
+		root := &cobra.Command{Use: "kn"}
+		group := &cobra.Command{Use: "source"}
+		ping := &cobra.Command{Use: "ping"}
+		cmd := &cobra.Command{Use: name}
+		AddNamespaceFlags(cmd.Flags(), false)
+		root.AddCommand(group)
+		group.AddCommand(ping)
+		ping.AddCommand(cmd)
+		return cmd
+	}
+
+	// Nothing is sent without a sink
+	defer setGlobalConfig(config.TestConfig{})()
+	p.NotifyOperation(newCmd("create"), []string{"foo"})
+	assert.Equal(t, len(notifications), 0)
+
+	setGlobalConfig(config.TestConfig{TestNotificationSink: sink.URL})
+	cmd := newCmd("create")
+	assert.NilError(t, cmd.ParseFlags([]string{"--namespace", "bar"}))
+	p.NotifyOperation(cmd, []string{"foo"})
+	assert.Equal(t, len(notifications), 1)
+	assert.DeepEqual(t, notifications[0], OperationNotification{
+		Operation: "create",
+		Resource:  "source.ping",
+		Names:     []string{"foo"},
+		Namespace: "bar",
+		Command:   "kn source ping create",
+	})
+	assert.Equal(t, headers[0].Get("Ce-Specversion"), "1.0")
+	assert.Equal(t, headers[0].Get("Ce-Type"), "dev.knative.client.source.ping.created")
+	assert.Equal(t, headers[0].Get("Ce-Source"), "/apis/kn")
+	assert.Equal(t, headers[0].Get("Ce-Subject"), "foo")
+	assert.Assert(t, headers[0].Get("Ce-Id") != "")
+	assert.Equal(t, headers[0].Get("Content-Type"), "application/json")
+
+	// Other commands and dry runs are not notified
+	p.NotifyOperation(newCmd("describe"), []string{"foo"})
+	cmd = newCmd("delete")
+	cmd.Flags().Bool("dry-run", false, "")
+	assert.NilError(t, cmd.ParseFlags([]string{"--dry-run"}))
+	p.NotifyOperation(cmd, []string{"foo"})
+	assert.Equal(t, len(notifications), 1)
+
+	// The recorded names are notified instead of the arguments
+	p = &KnParams{}
+	p.RecordChanged("foo-x7k2p")
+	p.NotifyOperation(newCmd("create"), nil)
+	assert.Equal(t, len(notifications), 2)
+	assert.DeepEqual(t, notifications[1].Names, []string{"foo-x7k2p"})
+	assert.Equal(t, headers[1].Get("Ce-Subject"), "foo-x7k2p")
+	p = &KnParams{}
+	p.RecordChanged()
+	p.NotifyOperation(newCmd("delete"), []string{"foo"})
+	assert.Equal(t, len(notifications), 2)
+}
+
+func TestNotifyOperationFailure(t *testing.T) {
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer sink.Close()
+	defer setGlobalConfig(config.TestConfig{TestNotificationSink: sink.URL})()
+
+	root := &cobra.Command{Use: "kn"}
+	cmd := &cobra.Command{Use: "update"}
+	root.AddCommand(&cobra.Command{Use: "service"})
+	root.Commands()[0].AddCommand(cmd)
+	errOut := &bytes.Buffer{}
+	cmd.SetErr(errOut)
+
+	(&KnParams{}).NotifyOperation(cmd, []string{"foo"})
+	assert.Equal(t, errOut.String(), "WARNING: cannot send notification to "+sink.URL+": sink responded with 500 Internal Server Error\n")
+}
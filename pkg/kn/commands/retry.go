@@ -0,0 +1,54 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"knative.dev/client/pkg/kn/config"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+)
+
+// RetryPolicy returns the policy for retrying updates which conflict with
+// concurrent changes. The configuration overrides the defaults, and an
+// explicitly given --retries overrides the configured number of retries.
+func (params *KnParams) RetryPolicy(cmd *cobra.Command) (clientservingv1.RetryPolicy, error) {
+	policy := clientservingv1.DefaultRetryPolicy
+	retry := config.GlobalConfig.Retry()
+	if retry.MaxRetries != nil {
+		policy.MaxRetries = *retry.MaxRetries
+	}
+	if retry.Backoff != nil {
+		policy.Backoff = *retry.Backoff
+	}
+	if retry.Factor != nil {
+		policy.Factor = *retry.Factor
+	}
+	if retry.Jitter != nil {
+		policy.Jitter = *retry.Jitter
+	}
+	if err := policy.Validate(); err != nil {
+		return policy, fmt.Errorf("invalid retry policy in configuration file %s: %v", config.GlobalConfig.ConfigFile(), err)
+	}
+	if flag := cmd.Flag("retries"); flag != nil && flag.Changed {
+		if params.Retries < 0 {
+			return policy, fmt.Errorf("--retries must not be negative, got %d", params.Retries)
+		}
+		policy.MaxRetries = params.Retries
+	}
+	return policy, nil
+}
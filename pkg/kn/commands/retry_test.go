@@ -0,0 +1,66 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/assert"
+
+	"knative.dev/client/pkg/kn/config"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	p := &KnParams{}
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{Use: "kn"}
+		cmd.Flags().IntVar(&p.Retries, "retries", clientservingv1.DefaultRetryPolicy.MaxRetries, "")
+		return cmd
+	}
+
+	// Defaults without configuration
+	defer setGlobalConfig(config.TestConfig{})()
+	policy, err := p.RetryPolicy(newCmd())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, policy, clientservingv1.DefaultRetryPolicy)
+
+	// Configuration overrides the defaults
+	maxRetries, backoff := 5, time.Second
+	setGlobalConfig(config.TestConfig{TestRetry: config.Retry{MaxRetries: &maxRetries, Backoff: &backoff}})
+	cmd := newCmd()
+	policy, err = p.RetryPolicy(cmd)
+	assert.NilError(t, err)
+	assert.Equal(t, policy.MaxRetries, 5)
+	assert.Equal(t, policy.Backoff, time.Second)
+	assert.Equal(t, policy.Factor, clientservingv1.DefaultRetryPolicy.Factor)
+
+	// --retries overrides the configuration
+	assert.NilError(t, cmd.ParseFlags([]string{"--retries", "0"}))
+	policy, err = p.RetryPolicy(cmd)
+	assert.NilError(t, err)
+	assert.Equal(t, policy.MaxRetries, 0)
+
+	assert.NilError(t, cmd.ParseFlags([]string{"--retries", "-1"}))
+	_, err = p.RetryPolicy(cmd)
+	assert.ErrorContains(t, err, "--retries must not be negative")
+
+	factor := 0.5
+	setGlobalConfig(config.TestConfig{TestConfigFile: "/tmp/config.yaml", TestRetry: config.Retry{Factor: &factor}})
+	_, err = p.RetryPolicy(newCmd())
+	assert.ErrorContains(t, err, "invalid retry policy in configuration file /tmp/config.yaml")
+}
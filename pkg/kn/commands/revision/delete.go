@@ -28,17 +28,24 @@ import (
 // NewRevisionDeleteCommand represent 'revision delete' command
 func NewRevisionDeleteCommand(p *commands.KnParams) *cobra.Command {
 	var waitFlags commands.WaitFlags
+	var prune string
 
 	RevisionDeleteCommand := &cobra.Command{
 		Use:   "delete NAME [NAME ...]",
 		Short: "Delete revisions",
 		Example: `
   # Delete a revision 'svc1-abcde' in default namespace
-  kn revision delete svc1-abcde`,
+  kn revision delete svc1-abcde
+
+  # Delete all revisions of service 'svc1' which are not referenced by any route
+  kn revision delete --prune svc1`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) < 1 {
+			if len(args) < 1 && prune == "" {
 				return errors.New("'kn revision delete' requires one or more revision name")
 			}
+			if len(args) > 0 && prune != "" {
+				return errors.New("'kn revision delete' with --prune requires no arguments")
+			}
 			namespace, err := p.GetNamespace(cmd)
 			if err != nil {
 				return err
@@ -48,6 +55,20 @@ func NewRevisionDeleteCommand(p *commands.KnParams) *cobra.Command {
 				return err
 			}
 
+			if prune != "" {
+				revisionList, err := client.ListUnroutedRevisions(prune)
+				if err != nil {
+					return err
+				}
+				if len(revisionList.Items) == 0 {
+					fmt.Fprintf(cmd.OutOrStdout(), "No unreferenced revisions of service '%s' found.\n", prune)
+					return nil
+				}
+				for _, revision := range revisionList.Items {
+					args = append(args, revision.Name)
+				}
+			}
+
 			errs := []string{}
 			for _, name := range args {
 				timeout := time.Duration(0)
@@ -68,6 +89,8 @@ func NewRevisionDeleteCommand(p *commands.KnParams) *cobra.Command {
 		},
 	}
 	commands.AddNamespaceFlags(RevisionDeleteCommand.Flags(), false)
+	RevisionDeleteCommand.Flags().StringVar(&prune, "prune", "", "Delete all revisions of the given service which are not referenced by any route, "+
+		"except the service's latest revisions.")
 	waitFlags.AddConditionWaitFlags(RevisionDeleteCommand, commands.WaitDefaultTimeout, "delete", "revision", "deleted")
 	return RevisionDeleteCommand
 }
@@ -98,6 +98,33 @@ func TestMultipleRevisionDelete(t *testing.T) {
 	assert.Check(t, util.ContainsAll(output, "Revision", revName3, "deleted", "namespace", commands.FakeNamespace))
 }
 
+func TestRevisionDeletePruneMock(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+
+	r.ListUnroutedRevisions("foo", &servingv1.RevisionList{Items: []servingv1.Revision{
+		{ObjectMeta: metav1.ObjectMeta{Name: "foo-00001"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "foo-00002"}},
+	}}, nil)
+	for _, name := range []string{"foo-00001", "foo-00002"} {
+		r.DeleteRevision(name, mock.Any(), nil)
+	}
+	r.ListUnroutedRevisions("foo", &servingv1.RevisionList{}, nil)
+
+	output, err := executeRevisionCommand(client, "delete", "--prune", "foo")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "'foo-00001' deleted", "'foo-00002' deleted"))
+
+	output, err = executeRevisionCommand(client, "delete", "--prune", "foo")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "No unreferenced revisions of service 'foo' found."))
+
+	_, err = executeRevisionCommand(client, "delete", "foo-00001", "--prune", "foo")
+	assert.Error(t, err, "'kn revision delete' with --prune requires no arguments")
+
+	r.Validate()
+}
+
 func getRevisionDeleteEvents(name string) []watch.Event {
 	return []watch.Event{
 		{Type: watch.Added, Object: &servingv1.Revision{ObjectMeta: metav1.ObjectMeta{Name: name}}},
@@ -62,7 +62,15 @@ func NewRevisionDescribeCommand(p *commands.KnParams) *cobra.Command {
 				return err
 			}
 
+			printSecurity, err := cmd.Flags().GetBool("security")
+			if err != nil {
+				return err
+			}
+
 			if machineReadablePrintFlags.OutputFlagSpecified() {
+				if printSecurity {
+					return errors.New("--security can't be used together with --output")
+				}
 				printer, err := machineReadablePrintFlags.ToPrinter()
 				if err != nil {
 					return err
@@ -82,17 +90,19 @@ func NewRevisionDescribeCommand(p *commands.KnParams) *cobra.Command {
 				}
 			}
 			// Do the human-readable printing thing.
-			return describe(cmd.OutOrStdout(), revision, service, printDetails)
+			return describe(cmd.OutOrStdout(), revision, service, printDetails, printSecurity)
 		},
 	}
 	flags := command.Flags()
 	commands.AddNamespaceFlags(flags, false)
 	machineReadablePrintFlags.AddFlags(command)
 	flags.BoolP("verbose", "v", false, "More output.")
+	flags.Bool("security", false, "Fetch the SBOM and provenance attestations attached to the revision's image "+
+		"and print a summary. Requires cosign to be installed. The attestations are not verified.")
 	return command
 }
 
-func describe(w io.Writer, revision *servingv1.Revision, service *servingv1.Service, printDetails bool) error {
+func describe(w io.Writer, revision *servingv1.Revision, service *servingv1.Service, printDetails bool, printSecurity bool) error {
 	dw := printers.NewPrefixWriter(w)
 	commands.WriteMetadata(dw, &revision.ObjectMeta, printDetails)
 	WriteImage(dw, revision)
@@ -102,6 +112,9 @@ func describe(w io.Writer, revision *servingv1.Revision, service *servingv1.Serv
 	WriteScale(dw, revision)
 	WriteConcurrencyOptions(dw, revision)
 	WriteResources(dw, revision)
+	if printSecurity {
+		WriteSecurity(dw, revision)
+	}
 	serviceName, ok := revision.Labels[serving.ServiceLabelKey]
 	if ok {
 		serviceSection := dw.WriteAttribute("Service", serviceName)
@@ -15,6 +15,9 @@
 package revision
 
 import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
 	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"knative.dev/serving/pkg/apis/serving"
@@ -22,6 +25,7 @@ import (
 
 	"knative.dev/client/pkg/kn/commands"
 	hprinters "knative.dev/client/pkg/printers"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
 )
 
 const (
@@ -32,6 +36,16 @@ const (
 // Max column size
 const ListColumnMaxLength = 50
 
+// Routing and activation states of a revision
+const (
+	// Revision is referenced by a route and has running pods
+	RevisionStateActive = "active"
+	// Revision is referenced by a route but scaled to zero
+	RevisionStateRoutable = "routable"
+	// Revision is not referenced by any route
+	RevisionStateReserve = "reserve"
+)
+
 // RevisionListHandlers adds print handlers for revision list command
 func RevisionListHandlers(h hprinters.PrintHandler) {
 	RevisionColumnDefinitions := []metav1beta1.TableColumnDefinition{
@@ -45,11 +59,26 @@ func RevisionListHandlers(h hprinters.PrintHandler) {
 		{Name: "Conditions", Type: "string", Description: "Conditions describing statuses of the revision.", Priority: 1},
 		{Name: "Ready", Type: "string", Description: "Ready condition status of the revision.", Priority: 1},
 		{Name: "Reason", Type: "string", Description: "Reason for non-ready condition of the revision.", Priority: 1},
+		{Name: "State", Type: "string", Description: "Whether the revision is routed and has pods (active, routable or reserve).", Priority: 1},
 	}
 	h.TableHandler(RevisionColumnDefinitions, printRevision)
 	h.TableHandler(RevisionColumnDefinitions, printRevisionList)
 }
 
+// PodListHandlers adds print handlers for revision pods command
+func PodListHandlers(h hprinters.PrintHandler) {
+	PodColumnDefinitions := []metav1beta1.TableColumnDefinition{
+		{Name: "Name", Type: "string", Description: "Name of the pod.", Priority: 1},
+		{Name: "Ready", Type: "string", Description: "Number of ready containers of the pod.", Priority: 1},
+		{Name: "Status", Type: "string", Description: "Phase of the pod or reason why a container is waiting.", Priority: 1},
+		{Name: "Restarts", Type: "string", Description: "Number of container restarts of the pod.", Priority: 1},
+		{Name: "Node", Type: "string", Description: "Node on which the pod is scheduled.", Priority: 1},
+		{Name: "Age", Type: "string", Description: "Age of the pod.", Priority: 1},
+	}
+	h.TableHandler(PodColumnDefinitions, printPod)
+	h.TableHandler(PodColumnDefinitions, printPodList)
+}
+
 // Private functions
 
 // printRevisionList populates the Knative revision list table rows
@@ -73,6 +102,7 @@ func printRevision(revision *servingv1.Revision, options hprinters.PrintOptions)
 	traffic := revision.Annotations[RevisionTrafficAnnotation]
 	tags := revision.Annotations[RevisionTagsAnnotation]
 	generation := revision.Labels[serving.ConfigurationGenerationLabelKey]
+	state := RevisionState(revision)
 	age := commands.TranslateTimestampSince(revision.CreationTimestamp)
 	conditions := commands.ConditionsValue(revision.Status.Conditions)
 	ready := commands.ReadyCondition(revision.Status.Conditions)
@@ -95,13 +125,68 @@ func printRevision(revision *servingv1.Revision, options hprinters.PrintOptions)
 		trunc(age),
 		trunc(conditions),
 		trunc(ready),
-		trunc(reason))
+		trunc(reason),
+		trunc(state))
 	return []metav1beta1.TableRow{row}, nil
 }
 
+// RevisionState returns whether the revision is routed and has pods. The latter is
+// taken from the revision's Active condition which reflects the state of its PodAutoscaler.
+func RevisionState(revision *servingv1.Revision) string {
+	if !clientservingv1.IsRevisionRouted(revision) {
+		return RevisionStateReserve
+	}
+	if active := revision.Status.GetCondition(servingv1.RevisionConditionActive); active != nil && active.IsTrue() {
+		return RevisionStateActive
+	}
+	return RevisionStateRoutable
+}
+
 func trunc(txt string) string {
 	if len(txt) <= ListColumnMaxLength {
 		return txt
 	}
 	return txt[:ListColumnMaxLength-4] + " ..."
 }
+
+// printPodList populates the pod list table rows
+func printPodList(podList *corev1.PodList, options hprinters.PrintOptions) ([]metav1beta1.TableRow, error) {
+	rows := make([]metav1beta1.TableRow, 0, len(podList.Items))
+	for _, pod := range podList.Items {
+		r, err := printPod(&pod, options)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, r...)
+	}
+	return rows, nil
+}
+
+// printPod populates the pod table rows
+func printPod(pod *corev1.Pod, options hprinters.PrintOptions) ([]metav1beta1.TableRow, error) {
+	var ready, restarts int32
+	status := string(pod.Status.Phase)
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Ready {
+			ready++
+		}
+		restarts += containerStatus.RestartCount
+		if containerStatus.State.Waiting != nil && containerStatus.State.Waiting.Reason != "" {
+			status = containerStatus.State.Waiting.Reason
+		}
+	}
+	if pod.DeletionTimestamp != nil {
+		status = "Terminating"
+	}
+	row := metav1beta1.TableRow{
+		Object: runtime.RawExtension{Object: pod},
+	}
+	row.Cells = append(row.Cells,
+		trunc(pod.Name),
+		fmt.Sprintf("%d/%d", ready, len(pod.Spec.Containers)),
+		trunc(status),
+		fmt.Sprintf("%d", restarts),
+		trunc(pod.Spec.NodeName),
+		commands.TranslateTimestampSince(pod.CreationTimestamp))
+	return []metav1beta1.TableRow{row}, nil
+}
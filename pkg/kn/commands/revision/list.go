@@ -36,6 +36,7 @@ var serviceNameFilter string
 // NewRevisionListCommand represents 'kn revision list' command
 func NewRevisionListCommand(p *commands.KnParams) *cobra.Command {
 	revisionListFlags := flags.NewListPrintFlags(RevisionListHandlers)
+	var inactiveFilter bool
 
 	revisionListCommand := &cobra.Command{
 		Use:     "list",
@@ -53,7 +54,10 @@ func NewRevisionListCommand(p *commands.KnParams) *cobra.Command {
   kn revision list -o json
 
   # List revision 'web'
-  kn revision list web`,
+  kn revision list web
+
+  # List revisions of service 'svc1' which are scaled to zero or not routed at all
+  kn revision list -s svc1 --inactive`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			namespace, err := p.GetNamespace(cmd)
 			if err != nil {
@@ -81,6 +85,10 @@ func NewRevisionListCommand(p *commands.KnParams) *cobra.Command {
 				return err
 			}
 
+			if inactiveFilter {
+				filterInactiveRevisions(revisionList)
+			}
+
 			// Stop if nothing found
 			if len(revisionList.Items) == 0 {
 				fmt.Fprintf(cmd.OutOrStdout(), "No revisions found.\n")
@@ -93,7 +101,7 @@ func NewRevisionListCommand(p *commands.KnParams) *cobra.Command {
 			}
 
 			// Only add temporary annotations if human readable output is requested
-			if !revisionListFlags.GenericPrintFlags.OutputFlagSpecified() {
+			if revisionListFlags.HumanReadable() {
 				err = enrichRevisionAnnotationsWithServiceData(p.NewServingClient, revisionList)
 				if err != nil {
 					return err
@@ -110,10 +118,24 @@ func NewRevisionListCommand(p *commands.KnParams) *cobra.Command {
 	commands.AddNamespaceFlags(revisionListCommand.Flags(), true)
 	revisionListFlags.AddFlags(revisionListCommand)
 	revisionListCommand.Flags().StringVarP(&serviceNameFilter, "service", "s", "", "Service name")
+	revisionListCommand.Flags().BoolVar(&inactiveFilter, "inactive", false,
+		"Only list revisions without running pods, i.e. which are scaled to zero (state 'routable') "+
+			"or not referenced by any route (state 'reserve'). Revisions in state 'reserve' are candidates for deletion.")
 
 	return revisionListCommand
 }
 
+// filterInactiveRevisions removes all revisions in state 'active' from the list
+func filterInactiveRevisions(revisionList *servingv1.RevisionList) {
+	inactive := revisionList.Items[:0]
+	for _, revision := range revisionList.Items {
+		if RevisionState(&revision) != RevisionStateActive {
+			inactive = append(inactive, revision)
+		}
+	}
+	revisionList.Items = inactive
+}
+
 // If a service option is given append a filter to the list of filters
 func appendServiceFilter(lConfig []clientservingv1.ListConfig, client clientservingv1.KnServingClient, cmd *cobra.Command) ([]clientservingv1.ListConfig, error) {
 	if !cmd.Flags().Changed("service") {
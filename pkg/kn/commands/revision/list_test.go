@@ -19,9 +19,11 @@ import (
 	"testing"
 
 	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clienttesting "k8s.io/client-go/testing"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/serving/pkg/apis/serving"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
@@ -29,7 +31,7 @@ import (
 	"knative.dev/client/pkg/util"
 )
 
-var revisionListHeader = []string{"NAME", "SERVICE", "TRAFFIC", "TAGS", "GENERATION", "AGE", "CONDITIONS", "READY", "REASON"}
+var revisionListHeader = []string{"NAME", "SERVICE", "TRAFFIC", "TAGS", "GENERATION", "AGE", "CONDITIONS", "READY", "REASON", "STATE"}
 
 func fakeRevisionList(args []string, response *servingv1.RevisionList) (action clienttesting.Action, output []string, err error) {
 	knParams := &commands.KnParams{}
@@ -89,6 +91,9 @@ func TestRevisionListDefaultOutput(t *testing.T) {
 		t.Errorf("Bad action %v", action)
 	}
 	assert.Check(t, util.ContainsAll(output[0], revisionListHeader...))
+	// STATE is appended so that the existing column layout is kept
+	headerFields := strings.Fields(output[0])
+	assert.DeepEqual(t, headerFields[len(headerFields)-2:], []string{"REASON", "STATE"})
 	var expectedOutput [][]string = [][]string{
 		{"bar-wxyz", "bar", "10"},
 		{"bar-wxyz", "bar", "2"},
@@ -181,6 +186,36 @@ func TestRevisionListOutputWithTwoRevName(t *testing.T) {
 	assert.ErrorContains(t, err, "'kn revision list' accepts maximum 1 argument")
 }
 
+func TestRevisionListInactive(t *testing.T) {
+	active := createMockRevisionWithParams("foo-abcd", "svc1", "3", "100", "")
+	active.Labels[serving.RoutingStateLabelKey] = "active"
+	active.Status.Conditions = duckv1.Conditions{{Type: servingv1.RevisionConditionActive, Status: corev1.ConditionTrue}}
+	routable := createMockRevisionWithParams("foo-efgh", "svc1", "2", "0", "v2")
+	routable.Labels[serving.RoutingStateLabelKey] = "active"
+	routable.Status.Conditions = duckv1.Conditions{{Type: servingv1.RevisionConditionActive, Status: corev1.ConditionFalse, Reason: "NoTraffic"}}
+	reserve := createMockRevisionWithParams("foo-ijkl", "svc1", "1", "", "")
+	reserve.Labels[serving.RoutingStateLabelKey] = "reserve"
+	revisionList := &servingv1.RevisionList{Items: []servingv1.Revision{*active, *routable, *reserve}}
+
+	_, output, err := fakeRevisionList([]string{"revision", "list"}, revisionList)
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsAll(output[0], revisionListHeader...))
+	assert.Check(t, util.ContainsAll(output[1], "foo-abcd", "active"))
+	assert.Check(t, util.ContainsAll(output[2], "foo-efgh", "routable"))
+	assert.Check(t, util.ContainsAll(output[3], "foo-ijkl", "reserve"))
+
+	_, output, err = fakeRevisionList([]string{"revision", "list", "--inactive"}, revisionList)
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsAll(output[1], "foo-efgh", "routable"))
+	assert.Check(t, util.ContainsAll(output[2], "foo-ijkl", "reserve"))
+	assert.Equal(t, output[3], "")
+
+	// Without routing state label, the routes annotation is checked
+	legacy := createMockRevisionWithParams("foo-mnop", "svc1", "1", "", "")
+	legacy.Annotations[serving.RoutesAnnotationKey] = "svc1"
+	assert.Equal(t, RevisionState(legacy), RevisionStateRoutable)
+}
+
 func createMockRevisionWithParams(name, svcName, generation, traffic, tags string) *servingv1.Revision {
 	revision := &servingv1.Revision{
 		TypeMeta: metav1.TypeMeta{
@@ -0,0 +1,216 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revision
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/serving/pkg/apis/config"
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+)
+
+// queueProxyContainerName is the name of the sidecar container injected into every revision pod
+const queueProxyContainerName = "queue-proxy"
+
+type logsFlags struct {
+	container string
+	previous  bool
+	tail      int64
+}
+
+// NewRevisionLogsCommand represents 'kn revision logs' command
+func NewRevisionLogsCommand(p *commands.KnParams) *cobra.Command {
+	var logsFlags logsFlags
+
+	revisionLogsCommand := &cobra.Command{
+		Use:   "logs NAME",
+		Short: "Print the logs of a revision",
+		Long: `Print the logs of a container in all pods of a revision.
+
+Use --previous for inspecting a crash looping container, which prints the logs
+of the container instance that was terminated last. The logs are prefixed with
+the name of the pod when the revision has more than one pod.`,
+		Example: `
+  # Print the logs of the user container of revision 'svc1-abcde-1'
+  kn revision logs svc1-abcde-1
+
+  # Print the logs of the last terminated instance of a crash looping user container
+  kn revision logs svc1-abcde-1 --previous
+
+  # Print the last 20 lines of the queue-proxy sidecar logs
+  kn revision logs svc1-abcde-1 --container queue-proxy --tail 20`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("'kn revision logs' requires name of the revision as single argument")
+			}
+			name := args[0]
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := p.NewServingClient(namespace)
+			if err != nil {
+				return err
+			}
+			revision, err := client.GetRevision(name)
+			if err != nil {
+				return err
+			}
+
+			container := logsFlags.container
+			if container == "" {
+				container = userContainerName(revision)
+			}
+
+			kubeClient, err := p.NewKubeClient()
+			if err != nil {
+				return err
+			}
+			podList, err := kubeClient.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
+				LabelSelector: labels.Set{serving.RevisionLabelKey: name}.String(),
+			})
+			if err != nil {
+				return err
+			}
+			pods, err := podsForLogs(podList.Items, container, logsFlags.previous)
+			if err != nil {
+				return fmt.Errorf("cannot print logs of revision '%s': %v", name, err)
+			}
+			if len(pods) == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "No pods found for revision '%s'.\n", name)
+				return nil
+			}
+
+			opts := &corev1.PodLogOptions{
+				Container: container,
+				Previous:  logsFlags.previous,
+			}
+			if logsFlags.tail >= 0 {
+				opts.TailLines = &logsFlags.tail
+			}
+			for _, pod := range pods {
+				prefix := ""
+				if len(pods) > 1 {
+					prefix = "[" + pod.Name + "] "
+				}
+				err := printPodLogs(kubeClient, namespace, pod.Name, opts, prefix, cmd.OutOrStdout())
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	flags := revisionLogsCommand.Flags()
+	commands.AddNamespaceFlags(flags, false)
+	flags.StringVarP(&logsFlags.container, "container", "c", "",
+		"Name of the container to print the logs for, e.g. '"+queueProxyContainerName+"' for the sidecar. "+
+			"Defaults to the user container of the revision.")
+	flags.BoolVarP(&logsFlags.previous, "previous", "p", false,
+		"Print the logs of the previous, terminated instance of the container in each pod which has been restarted.")
+	flags.Int64Var(&logsFlags.tail, "tail", -1,
+		"Number of most recent log lines to print per pod. Defaults to all lines.")
+	return revisionLogsCommand
+}
+
+// userContainerName returns the name of the (single) user container of a revision,
+// falling back to the name given by Knative Serving to unnamed containers
+func userContainerName(revision *servingv1.Revision) string {
+	if container := revision.Spec.GetContainer(); container != nil && container.Name != "" {
+		return container.Name
+	}
+	return config.DefaultUserContainerName
+}
+
+// podsForLogs returns the pods which provide logs for the given container, sorted by name.
+// For previous logs only pods in which the container has been restarted are returned.
+func podsForLogs(pods []corev1.Pod, container string, previous bool) ([]corev1.Pod, error) {
+	if len(pods) == 0 {
+		return nil, nil
+	}
+	sort.SliceStable(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+	var ret []corev1.Pod
+	for _, pod := range pods {
+		if !hasContainer(pod, container) {
+			return nil, fmt.Errorf("no container '%s' in pod '%s', available containers: %s",
+				container, pod.Name, strings.Join(containerNames(pod), ", "))
+		}
+		if previous && !hasPreviousInstance(pod, container) {
+			continue
+		}
+		ret = append(ret, pod)
+	}
+	if previous && len(ret) == 0 {
+		return nil, fmt.Errorf("container '%s' has not been restarted in any pod, no previous logs available", container)
+	}
+	return ret, nil
+}
+
+func hasContainer(pod corev1.Pod, name string) bool {
+	for _, container := range containerNames(pod) {
+		if container == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containerNames(pod corev1.Pod) []string {
+	names := make([]string, 0, len(pod.Spec.Containers))
+	for _, container := range pod.Spec.Containers {
+		names = append(names, container.Name)
+	}
+	return names
+}
+
+func hasPreviousInstance(pod corev1.Pod, container string) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == container {
+			return status.RestartCount > 0 || status.LastTerminationState.Terminated != nil
+		}
+	}
+	return false
+}
+
+func printPodLogs(client kubernetes.Interface, namespace string, pod string, opts *corev1.PodLogOptions, prefix string, out io.Writer) error {
+	stream, err := client.CoreV1().Pods(namespace).GetLogs(pod, opts).Stream(context.TODO())
+	if err != nil {
+		return fmt.Errorf("cannot get logs of container '%s' in pod '%s': %v", opts.Container, pod, err)
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		fmt.Fprintln(out, prefix+scanner.Text())
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,159 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revision
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+)
+
+func executeLogsCommand(client clientservingv1.KnServingClient, pods []runtime.Object, args ...string) (string, []*corev1.PodLogOptions, error) {
+	knParams := &commands.KnParams{}
+	knParams.ClientConfig = blankConfig
+
+	output := new(bytes.Buffer)
+	knParams.Output = output
+	knParams.NewServingClient = func(namespace string) (clientservingv1.KnServingClient, error) {
+		return client, nil
+	}
+	var requested []*corev1.PodLogOptions
+	knParams.NewKubeClient = func() (kubernetes.Interface, error) {
+		kubeClient := commands.NewFakeKubeClient(pods...)
+		kubeClient.PodLogs = func(namespace string, pod string, opts *corev1.PodLogOptions) string {
+			requested = append(requested, opts)
+			return fmt.Sprintf("%s %s previous=%t\nsecond line\n", pod, opts.Container, opts.Previous)
+		}
+		return kubeClient, nil
+	}
+	cmd := NewRevisionCommand(knParams)
+	cmd.SetArgs(append([]string{"logs"}, args...))
+	cmd.SetOutput(output)
+	err := cmd.Execute()
+	return output.String(), requested, err
+}
+
+func TestRevisionLogsNoName(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	_, _, err := executeLogsCommand(client, nil)
+	assert.ErrorContains(t, err, "requires name of the revision")
+}
+
+func TestRevisionLogsNoPods(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetRevision("foo-abcde-1", &servingv1.Revision{}, nil)
+
+	output, _, err := executeLogsCommand(client, nil, "foo-abcde-1")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "No pods found", "foo-abcde-1"))
+	r.Validate()
+}
+
+func TestRevisionLogsSinglePod(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetRevision("foo-abcde-1", &servingv1.Revision{}, nil)
+
+	pods := []runtime.Object{
+		createTestPod("foo-pod-1", "foo-abcde-1", "node1", corev1.PodRunning),
+		createTestPod("other-pod", "foo-xyzab-2", "node1", corev1.PodRunning),
+	}
+	output, requested, err := executeLogsCommand(client, pods, "foo-abcde-1", "--tail", "5")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "foo-pod-1 user-container previous=false\nsecond line\n")
+	assert.Equal(t, len(requested), 1)
+	assert.Equal(t, *requested[0].TailLines, int64(5))
+	r.Validate()
+}
+
+func TestRevisionLogsQueueProxy(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetRevision("foo-abcde-1", &servingv1.Revision{}, nil)
+
+	pods := []runtime.Object{
+		withQueueProxy(createTestPod("foo-pod-2", "foo-abcde-1", "node1", corev1.PodRunning)),
+		withQueueProxy(createTestPod("foo-pod-1", "foo-abcde-1", "node2", corev1.PodRunning)),
+	}
+	output, requested, err := executeLogsCommand(client, pods, "foo-abcde-1", "--container", "queue-proxy")
+	assert.NilError(t, err)
+	lines := strings.Split(output, "\n")
+	assert.DeepEqual(t, lines, []string{
+		"[foo-pod-1] foo-pod-1 queue-proxy previous=false",
+		"[foo-pod-1] second line",
+		"[foo-pod-2] foo-pod-2 queue-proxy previous=false",
+		"[foo-pod-2] second line",
+		"",
+	})
+	assert.Assert(t, requested[0].TailLines == nil)
+	r.Validate()
+}
+
+func TestRevisionLogsUnknownContainer(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetRevision("foo-abcde-1", &servingv1.Revision{}, nil)
+
+	pods := []runtime.Object{createTestPod("foo-pod-1", "foo-abcde-1", "node1", corev1.PodRunning)}
+	_, _, err := executeLogsCommand(client, pods, "foo-abcde-1", "-c", "queue-proxy")
+	assert.Assert(t, util.ContainsAll(err.Error(), "no container 'queue-proxy'", "foo-pod-1", "user-container"))
+	r.Validate()
+}
+
+func TestRevisionLogsPrevious(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	revision := &servingv1.Revision{}
+	revision.Spec.Containers = []corev1.Container{{Name: "app"}}
+	r.GetRevision("foo-abcde-1", revision, nil)
+	r.GetRevision("foo-abcde-1", revision, nil)
+
+	crashing := withContainerName(createTestPod("foo-pod-2", "foo-abcde-1", "node2", corev1.PodRunning), "app")
+	crashing.Status.ContainerStatuses[0].RestartCount = 3
+	healthy := withContainerName(createTestPod("foo-pod-1", "foo-abcde-1", "node1", corev1.PodRunning), "app")
+
+	output, requested, err := executeLogsCommand(client, []runtime.Object{crashing, healthy}, "foo-abcde-1", "--previous")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "foo-pod-2 app previous=true\nsecond line\n")
+	assert.Equal(t, len(requested), 1)
+
+	_, _, err = executeLogsCommand(client, []runtime.Object{healthy}, "foo-abcde-1", "-p")
+	assert.Assert(t, util.ContainsAll(err.Error(), "foo-abcde-1", "'app' has not been restarted", "no previous logs"))
+	r.Validate()
+}
+
+func withQueueProxy(pod *corev1.Pod) *corev1.Pod {
+	pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: "queue-proxy"})
+	pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{Name: "queue-proxy", Ready: true})
+	return pod
+}
+
+func withContainerName(pod *corev1.Pod, name string) *corev1.Pod {
+	pod.Spec.Containers[0].Name = name
+	pod.Status.ContainerStatuses[0].Name = name
+	return pod
+}
@@ -0,0 +1,116 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revision
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/scheme"
+	"knative.dev/serving/pkg/apis/serving"
+
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/kn/commands/flags"
+	"knative.dev/client/pkg/util"
+)
+
+// NewRevisionPodsCommand represents 'kn revision pods' command
+func NewRevisionPodsCommand(p *commands.KnParams) *cobra.Command {
+	podListFlags := flags.NewListPrintFlags(PodListHandlers)
+
+	revisionPodsCommand := &cobra.Command{
+		Use:   "pods NAME",
+		Short: "List pods of a revision",
+		Long:  "List the pods which are currently running for a given revision.",
+		Example: `
+  # List all pods of revision 'svc1-abcde-1'
+  kn revision pods svc1-abcde-1
+
+  # List all pods of revision 'svc1-abcde-1' in YAML format
+  kn revision pods svc1-abcde-1 -o yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("'kn revision pods' requires name of the revision as single argument")
+			}
+			name := args[0]
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := p.NewServingClient(namespace)
+			if err != nil {
+				return err
+			}
+
+			// Verify that revision exists first
+			_, err = client.GetRevision(name)
+			if err != nil {
+				return err
+			}
+
+			kubeClient, err := p.NewKubeClient()
+			if err != nil {
+				return err
+			}
+			podList, err := kubeClient.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
+				LabelSelector: labels.Set{serving.RevisionLabelKey: name}.String(),
+			})
+			if err != nil {
+				return err
+			}
+
+			if len(podList.Items) == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "No pods found for revision '%s'.\n", name)
+				return nil
+			}
+
+			err = updateCoreGvkForPodList(podList)
+			if err != nil {
+				return err
+			}
+
+			sort.SliceStable(podList.Items, func(i, j int) bool {
+				return podList.Items[i].Name < podList.Items[j].Name
+			})
+			return podListFlags.Print(podList, cmd.OutOrStdout())
+		},
+	}
+	commands.AddNamespaceFlags(revisionPodsCommand.Flags(), false)
+	podListFlags.AddFlags(revisionPodsCommand)
+	return revisionPodsCommand
+}
+
+// update the list and all contained pods with the core GroupVersionKind
+// which is not set by the typed client
+func updateCoreGvkForPodList(podList *corev1.PodList) error {
+	err := util.UpdateGroupVersionKindWithScheme(podList, corev1.SchemeGroupVersion, scheme.Scheme)
+	if err != nil {
+		return err
+	}
+	for idx := range podList.Items {
+		err := util.UpdateGroupVersionKindWithScheme(&podList.Items[idx], corev1.SchemeGroupVersion, scheme.Scheme)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
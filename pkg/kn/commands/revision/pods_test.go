@@ -0,0 +1,138 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revision
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+)
+
+func executePodsCommand(client clientservingv1.KnServingClient, pods []runtime.Object, args ...string) (string, error) {
+	knParams := &commands.KnParams{}
+	knParams.ClientConfig = blankConfig
+
+	output := new(bytes.Buffer)
+	knParams.Output = output
+	knParams.NewServingClient = func(namespace string) (clientservingv1.KnServingClient, error) {
+		return client, nil
+	}
+	knParams.NewKubeClient = func() (kubernetes.Interface, error) {
+		return commands.NewFakeKubeClient(pods...), nil
+	}
+	cmd := NewRevisionCommand(knParams)
+	cmd.SetArgs(append([]string{"pods"}, args...))
+	cmd.SetOutput(output)
+	err := cmd.Execute()
+	return output.String(), err
+}
+
+func TestRevisionPodsNoName(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	_, err := executePodsCommand(client, nil)
+	assert.ErrorContains(t, err, "requires name of the revision")
+}
+
+func TestRevisionPodsRevisionNotFound(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetRevision("foo-abcde-1", nil, apierrors.NewNotFound(servingv1.Resource("revision"), "foo-abcde-1"))
+
+	_, err := executePodsCommand(client, nil, "foo-abcde-1")
+	assert.ErrorContains(t, err, "not found")
+	r.Validate()
+}
+
+func TestRevisionPodsEmpty(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetRevision("foo-abcde-1", &servingv1.Revision{}, nil)
+
+	output, err := executePodsCommand(client, []runtime.Object{createTestPod("other-pod", "foo-xyzab-2", "", corev1.PodRunning)}, "foo-abcde-1")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "No pods found", "foo-abcde-1"))
+	r.Validate()
+}
+
+func TestRevisionPods(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetRevision("foo-abcde-1", &servingv1.Revision{}, nil)
+
+	crashing := createTestPod("foo-pod-2", "foo-abcde-1", "node2", corev1.PodRunning)
+	crashing.Status.ContainerStatuses[0].Ready = false
+	crashing.Status.ContainerStatuses[0].RestartCount = 4
+	crashing.Status.ContainerStatuses[0].State.Waiting = &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}
+	pods := []runtime.Object{
+		crashing,
+		createTestPod("foo-pod-1", "foo-abcde-1", "node1", corev1.PodRunning),
+		createTestPod("other-pod", "foo-xyzab-2", "node1", corev1.PodRunning),
+	}
+
+	output, err := executePodsCommand(client, pods, "foo-abcde-1")
+	assert.NilError(t, err)
+	lines := strings.Split(output, "\n")
+	assert.Equal(t, len(lines), 4)
+	assert.Check(t, util.ContainsAll(lines[0], "NAME", "READY", "STATUS", "RESTARTS", "NODE", "AGE"))
+	assert.Check(t, util.ContainsAll(lines[1], "foo-pod-1", "1/1", "Running", "0", "node1"))
+	assert.Check(t, util.ContainsAll(lines[2], "foo-pod-2", "0/1", "CrashLoopBackOff", "4", "node2"))
+	r.Validate()
+}
+
+func TestRevisionPodsYaml(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetRevision("foo-abcde-1", &servingv1.Revision{}, nil)
+
+	pods := []runtime.Object{createTestPod("foo-pod-1", "foo-abcde-1", "node1", corev1.PodRunning)}
+	output, err := executePodsCommand(client, pods, "foo-abcde-1", "-o", "yaml")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "kind: Pod", "name: foo-pod-1", "nodeName: node1"))
+	r.Validate()
+}
+
+func createTestPod(name, revision, node string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{serving.RevisionLabelKey: revision},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:   node,
+			Containers: []corev1.Container{{Name: "user-container"}},
+		},
+		Status: corev1.PodStatus{
+			Phase: phase,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "user-container",
+				Ready: true,
+			}},
+		},
+	}
+}
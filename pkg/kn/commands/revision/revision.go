@@ -30,6 +30,9 @@ func NewRevisionCommand(p *commands.KnParams) *cobra.Command {
 	revisionCmd.AddCommand(NewRevisionListCommand(p))
 	revisionCmd.AddCommand(NewRevisionDescribeCommand(p))
 	revisionCmd.AddCommand(NewRevisionDeleteCommand(p))
+	revisionCmd.AddCommand(NewRevisionPodsCommand(p))
+	revisionCmd.AddCommand(NewRevisionLogsCommand(p))
+	revisionCmd.AddCommand(NewRevisionWaitCommand(p))
 	return revisionCmd
 }
 
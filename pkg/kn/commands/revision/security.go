@@ -0,0 +1,166 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revision
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/cosign"
+	"knative.dev/client/pkg/printers"
+	clientserving "knative.dev/client/pkg/serving"
+)
+
+// SPDX documents stored in a predicate
+type spdxPredicate struct {
+	SPDXVersion string            `json:"spdxVersion"`
+	Packages    []json.RawMessage `json:"packages"`
+}
+
+// CycloneDX BOMs stored in a predicate
+type cycloneDXPredicate struct {
+	SpecVersion string            `json:"specVersion"`
+	Components  []json.RawMessage `json:"components"`
+}
+
+// SLSA provenance v0.2 and v1 predicate, reduced to the fields which
+// are summarized
+type slsaPredicate struct {
+	// v0.2
+	Builder    slsaBuilder `json:"builder"`
+	BuildType  string      `json:"buildType"`
+	Invocation struct {
+		ConfigSource struct {
+			URI string `json:"uri"`
+		} `json:"configSource"`
+	} `json:"invocation"`
+	Materials []slsaResource `json:"materials"`
+
+	// v1
+	BuildDefinition struct {
+		BuildType            string         `json:"buildType"`
+		ResolvedDependencies []slsaResource `json:"resolvedDependencies"`
+	} `json:"buildDefinition"`
+	RunDetails struct {
+		Builder slsaBuilder `json:"builder"`
+	} `json:"runDetails"`
+}
+
+type slsaBuilder struct {
+	ID string `json:"id"`
+}
+
+type slsaResource struct {
+	URI string `json:"uri"`
+}
+
+// Digest of the image of the revision, as resolved by Knative
+// or as given by the user
+func revisionImageDigest(revision *servingv1.Revision) (string, error) {
+	if revision.Status.DeprecatedImageDigest != "" {
+		return revision.Status.DeprecatedImageDigest, nil
+	}
+	c, err := clientserving.ContainerOfRevisionSpec(&revision.Spec)
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(c.Image, "@sha256:") {
+		return c.Image, nil
+	}
+	return "", fmt.Errorf("image digest of revision %s has not been resolved yet", revision.Name)
+}
+
+// Write a summary of the SBOM and provenance attestations attached to the
+// image of the revision. The attestations are fetched from the registry
+// with cosign, but not verified.
+func WriteSecurity(dw printers.PrefixWriter, revision *servingv1.Revision) {
+	image, err := revisionImageDigest(revision)
+	if err != nil {
+		dw.WriteAttribute("Security", fmt.Sprintf("Unknown (%v)", err))
+		return
+	}
+	statements, err := cosign.DownloadAttestations(image)
+	if err != nil {
+		dw.WriteAttribute("Security", fmt.Sprintf("Unknown (cannot fetch attestations: %v)", err))
+		return
+	}
+	if len(statements) == 0 {
+		dw.WriteAttribute("Security", "No attestations")
+		return
+	}
+	section := dw.WriteAttribute("Security", "Attestations (not verified)")
+	for _, statement := range statements {
+		label, summary := summarizeAttestation(statement)
+		section.WriteAttribute(label, summary)
+	}
+}
+
+// Return label and a one line summary of an attestation
+func summarizeAttestation(statement cosign.Statement) (string, string) {
+	predicateType := statement.PredicateType
+	switch {
+	case strings.HasPrefix(predicateType, "https://spdx.dev/"):
+		var spdx spdxPredicate
+		if err := json.Unmarshal(statement.Predicate, &spdx); err != nil {
+			return "SBOM", fmt.Sprintf("SPDX, misformatted: %v", err)
+		}
+		return "SBOM", fmt.Sprintf("%s, %d packages", withDefault(spdx.SPDXVersion, "SPDX"), len(spdx.Packages))
+	case strings.HasPrefix(predicateType, "https://cyclonedx.org/"):
+		var bom cycloneDXPredicate
+		if err := json.Unmarshal(statement.Predicate, &bom); err != nil {
+			return "SBOM", fmt.Sprintf("CycloneDX, misformatted: %v", err)
+		}
+		return "SBOM", fmt.Sprintf("CycloneDX %s, %d components", bom.SpecVersion, len(bom.Components))
+	case strings.HasPrefix(predicateType, "https://slsa.dev/provenance/"):
+		var slsa slsaPredicate
+		if err := json.Unmarshal(statement.Predicate, &slsa); err != nil {
+			return "Provenance", fmt.Sprintf("SLSA, misformatted: %v", err)
+		}
+		return "Provenance", summarizeProvenance(&slsa)
+	default:
+		return "Other", predicateType
+	}
+}
+
+func summarizeProvenance(slsa *slsaPredicate) string {
+	builder := withDefault(slsa.Builder.ID, slsa.RunDetails.Builder.ID)
+	buildType := withDefault(slsa.BuildType, slsa.BuildDefinition.BuildType)
+	source := slsa.Invocation.ConfigSource.URI
+	if source == "" && len(slsa.Materials) > 0 {
+		source = slsa.Materials[0].URI
+	}
+	if source == "" && len(slsa.BuildDefinition.ResolvedDependencies) > 0 {
+		source = slsa.BuildDefinition.ResolvedDependencies[0].URI
+	}
+
+	parts := []string{"builder " + withDefault(builder, "unknown")}
+	if buildType != "" {
+		parts = append(parts, "build type "+buildType)
+	}
+	if source != "" {
+		parts = append(parts, "source "+source)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func withDefault(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,121 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revision
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"knative.dev/client/pkg/cosign"
+	"knative.dev/client/pkg/util"
+)
+
+func replaceCosign(run func(args ...string) ([]byte, error)) func() {
+	oldRun := cosign.Run
+	cosign.Run = run
+	return func() {
+		cosign.Run = oldRun
+	}
+}
+
+func attestation(t *testing.T, predicateType string, predicate string) string {
+	statement := `{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"` + predicateType + `","predicate":` + predicate + `}`
+	envelope, err := json.Marshal(map[string]string{
+		"payloadType": "application/vnd.in-toto+json",
+		"payload":     base64.StdEncoding.EncodeToString([]byte(statement)),
+	})
+	assert.NilError(t, err)
+	return string(envelope)
+}
+
+func TestDescribeRevisionSecurity(t *testing.T) {
+	var cosignArgs []string
+	attestations := []string{
+		attestation(t, "https://spdx.dev/Document", `{"spdxVersion":"SPDX-2.3","packages":[{"name":"a"},{"name":"b"}]}`),
+		attestation(t, "https://slsa.dev/provenance/v0.2", `{"builder":{"id":"https://tekton.dev/chains/v2"},"buildType":"tekton.dev/v1beta1/TaskRun",`+
+			`"materials":[{"uri":"git+https://github.com/example/app.git"}]}`),
+		attestation(t, "https://cosign.sigstore.dev/attestation/vuln/v1", `{}`),
+	}
+	defer replaceCosign(func(args ...string) ([]byte, error) {
+		cosignArgs = args
+		return []byte(strings.Join(attestations, "\n") + "\n"), nil
+	})()
+
+	expectedRevision := createTestRevision("test-rev", 3)
+	_, data, err := fakeRevision([]string{"revision", "describe", "test-rev", "--security"}, &expectedRevision)
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, cosignArgs, []string{"download", "attestation", "gcr.io/test/image@" + imageDigest})
+	assert.Assert(t, util.ContainsAll(data, "Security:", "Attestations (not verified)",
+		"SBOM:", "SPDX-2.3, 2 packages",
+		"Provenance:", "builder https://tekton.dev/chains/v2", "build type tekton.dev/v1beta1/TaskRun", "source git+https://github.com/example/app.git",
+		"Other:", "https://cosign.sigstore.dev/attestation/vuln/v1"))
+}
+
+func TestDescribeRevisionSecurityErrors(t *testing.T) {
+	defer replaceCosign(func(args ...string) ([]byte, error) {
+		return nil, errors.New("no such image")
+	})()
+
+	expectedRevision := createTestRevision("test-rev", 3)
+	_, data, err := fakeRevision([]string{"revision", "describe", "test-rev", "--security"}, &expectedRevision)
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(data, "Security:", "cannot fetch attestations", "no such image"))
+
+	expectedRevision.Status.DeprecatedImageDigest = ""
+	_, data, err = fakeRevision([]string{"revision", "describe", "test-rev", "--security"}, &expectedRevision)
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(data, "Security:", "has not been resolved yet"))
+
+	_, _, err = fakeRevision([]string{"revision", "describe", "test-rev", "--security", "-o", "yaml"}, &expectedRevision)
+	assert.ErrorContains(t, err, "--security")
+}
+
+func TestDescribeRevisionNoSecurity(t *testing.T) {
+	defer replaceCosign(func(args ...string) ([]byte, error) {
+		t.Fatal("cosign should not be called without --security")
+		return nil, nil
+	})()
+
+	expectedRevision := createTestRevision("test-rev", 3)
+	_, data, err := fakeRevision([]string{"revision", "describe", "test-rev"}, &expectedRevision)
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsNone(data, "Security:"))
+}
+
+func TestSummarizeAttestation(t *testing.T) {
+	for _, tc := range []struct {
+		predicateType string
+		predicate     string
+		label         string
+		summary       string
+	}{
+		{"https://cyclonedx.org/bom", `{"bomFormat":"CycloneDX","specVersion":"1.4","components":[{}]}`, "SBOM", "CycloneDX 1.4, 1 components"},
+		{"https://slsa.dev/provenance/v1", `{"buildDefinition":{"buildType":"https://example.com/build",` +
+			`"resolvedDependencies":[{"uri":"git+https://github.com/example/app"}]},"runDetails":{"builder":{"id":"https://example.com/builder"}}}`,
+			"Provenance", "builder https://example.com/builder, build type https://example.com/build, source git+https://github.com/example/app"},
+		{"https://slsa.dev/provenance/v0.2", `{}`, "Provenance", "builder unknown"},
+		{"https://spdx.dev/Document", `"broken"`, "SBOM", "SPDX, misformatted"},
+	} {
+		label, summary := summarizeAttestation(cosign.Statement{PredicateType: tc.predicateType, Predicate: json.RawMessage(tc.predicate)})
+		assert.Equal(t, label, tc.label)
+		assert.Assert(t, strings.HasPrefix(summary, tc.summary), summary)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+
+package revision
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+)
+
+// NewRevisionWaitCommand represents 'kn revision wait' command
+func NewRevisionWaitCommand(p *commands.KnParams) *cobra.Command {
+	var waitFlags commands.ResourceWaitFlags
+
+	command := &cobra.Command{
+		Use:   "wait NAME",
+		Short: "Wait until a revision is ready",
+		Long:  "Block until a condition of a revision becomes True. Fail if the condition becomes False or the timeout is reached.",
+		Example: `
+  # Wait until revision 'svc1-abcde' is ready
+  kn revision wait svc1-abcde
+
+  # Wait at most two minutes until the containers of revision 'svc1-abcde' are healthy
+  kn revision wait svc1-abcde --for=condition=ContainerHealthy --timeout=2m`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("'kn revision wait' requires the revision name given as single argument")
+			}
+			conditionType, err := waitFlags.ConditionType()
+			if err != nil {
+				return err
+			}
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := p.NewDynamicClient(namespace)
+			if err != nil {
+				return err
+			}
+			return commands.WaitForResource(client, servingv1.SchemeGroupVersion.WithResource("revisions"), "revision",
+				args[0], conditionType, waitFlags.Timeout, cmd.OutOrStdout())
+		},
+	}
+	commands.AddNamespaceFlags(command.Flags(), false)
+	waitFlags.Add(command)
+	return command
+}
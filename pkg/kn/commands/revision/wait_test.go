@@ -0,0 +1,140 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+
+package revision
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	clientdynamic "knative.dev/client/pkg/dynamic"
+	dynamicfakeclient "knative.dev/client/pkg/dynamic/fake"
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/util"
+	"knative.dev/client/pkg/wait"
+)
+
+func TestRevisionWait(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		args []string
+		// Initial state of the revision, nil if it doesn't exist
+		revision *unstructured.Unstructured
+		// Events sent by the watch
+		events   []watch.Event
+		expected []string
+		err      string
+	}{{
+		name:     "already ready",
+		args:     []string{"foo-abcde"},
+		revision: newWaitRevision(corev1.ConditionTrue, corev1.ConditionTrue),
+		expected: []string{"Revision 'foo-abcde'", "namespace 'default'", "Ready=True"},
+	}, {
+		name:     "becomes ready",
+		args:     []string{"foo-abcde", "--timeout", "10s"},
+		revision: newWaitRevision(corev1.ConditionUnknown, corev1.ConditionUnknown),
+		events: []watch.Event{
+			{Type: watch.Modified, Object: newWaitRevision(corev1.ConditionTrue, corev1.ConditionTrue)},
+		},
+		expected: []string{"Revision 'foo-abcde'", "Ready=True"},
+	}, {
+		name:     "other condition",
+		args:     []string{"foo-abcde", "--for=condition=ContainerHealthy"},
+		revision: newWaitRevision(corev1.ConditionUnknown, corev1.ConditionTrue),
+		expected: []string{"ContainerHealthy=True"},
+	}, {
+		name:     "becomes failed",
+		args:     []string{"foo-abcde"},
+		revision: newWaitRevision(corev1.ConditionUnknown, corev1.ConditionUnknown),
+		events: []watch.Event{
+			{Type: watch.Modified, Object: newWaitRevision(corev1.ConditionFalse, corev1.ConditionFalse)},
+		},
+		err: "ContainerMissing: image not found",
+	}, {
+		name: "not found",
+		args: []string{"foo-abcde"},
+		err:  "not found",
+	}, {
+		name: "invalid for",
+		args: []string{"foo-abcde", "--for", "delete"},
+		err:  "invalid value 'delete' for --for",
+	}, {
+		name: "no name",
+		args: []string{},
+		err:  "requires the revision name",
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			var objects []runtime.Object
+			if tc.revision != nil {
+				objects = append(objects, tc.revision)
+			}
+			dynamicClient := dynamicfakeclient.CreateFakeKnDynamicClient("default", objects...)
+			dynamicClient.RawClient().(*dynamicfake.FakeDynamicClient).PrependWatchReactor("revisions",
+				func(a clienttesting.Action) (bool, watch.Interface, error) {
+					w := wait.NewFakeWatch(tc.events)
+					w.Start()
+					return true, w, nil
+				})
+			p := &commands.KnParams{
+				NewDynamicClient: func(namespace string) (clientdynamic.KnDynamicClient, error) {
+					return dynamicClient, nil
+				},
+			}
+			cmd, _, output := commands.CreateTestKnCommand(NewRevisionCommand(p), p)
+			cmd.SetArgs(append([]string{"revision", "wait"}, tc.args...))
+			err := cmd.Execute()
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Assert(t, util.ContainsAll(output.String(), tc.expected...))
+		})
+	}
+}
+
+// newWaitRevision returns a reconciled revision 'foo-abcde' with the given Ready and ContainerHealthy conditions
+func newWaitRevision(ready corev1.ConditionStatus, containerHealthy corev1.ConditionStatus) *unstructured.Unstructured {
+	revision := &servingv1.Revision{
+		TypeMeta: metav1.TypeMeta{APIVersion: "serving.knative.dev/v1", Kind: "Revision"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "foo-abcde",
+			Namespace:  "default",
+			Generation: 1,
+		},
+	}
+	revision.Status.ObservedGeneration = 1
+	revision.Status.Conditions = []apis.Condition{
+		{Type: apis.ConditionReady, Status: ready},
+		{Type: servingv1.RevisionConditionContainerHealthy, Status: containerHealthy},
+	}
+	if ready == corev1.ConditionFalse {
+		revision.Status.Conditions[0].Reason = "ContainerMissing"
+		revision.Status.Conditions[0].Message = "image not found"
+	}
+	content, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(revision)
+	return &unstructured.Unstructured{Object: content}
+}
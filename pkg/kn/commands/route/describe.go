@@ -107,7 +107,7 @@ func writeTraffic(dw printers.PrefixWriter, route *servingv1.Route) {
 	trafficSection := dw.WriteAttribute("Traffic Targets", "")
 	dw.Flush()
 	for _, target := range route.Status.Traffic {
-		section := trafficSection.WriteColsLn(fmt.Sprintf("%3d%%", *target.Percent), formatTarget(target))
+		section := trafficSection.WriteColsLn(fmt.Sprintf("%3d%%", trafficPercent(target)), formatTarget(target))
 		if target.Tag != "" {
 			section.WriteAttribute("URL", target.URL.String())
 		}
@@ -29,6 +29,7 @@ import (
 
 	"knative.dev/client/pkg/kn/commands"
 	"knative.dev/client/pkg/util"
+	"knative.dev/pkg/apis"
 	"knative.dev/pkg/ptr"
 )
 
@@ -108,6 +109,19 @@ func TestCompletion(t *testing.T) {
 			"foo", "default", "90%", "foo-v2", "#v2", "10%", "@latest", "foo-v3"))
 	})
 
+	t.Run("describe a route with a tagged target without traffic", func(t *testing.T) {
+		setup(t)
+		expectedRoute.Status.Traffic = append(expectedRoute.Status.Traffic, servingv1.TrafficTarget{
+			RevisionName: "foo-v1",
+			Tag:          "v1",
+			URL:          &apis.URL{Scheme: "http", Host: "v1-foo.default.example.com"},
+		})
+
+		_, output, err := fakeRouteDescribe([]string{"route", "describe", "foo"}, &expectedRoute)
+		assert.NilError(t, err)
+		assert.Check(t, util.ContainsAll(output, "0%", "foo-v1", "#v1", "http://v1-foo.default.example.com"))
+	})
+
 	t.Run("describe a route with verbose output", func(t *testing.T) {
 		_, output, err := fakeRouteDescribe([]string{"route", "describe", "foo", "-v"}, &expectedRoute)
 		assert.Assert(t, err == nil)
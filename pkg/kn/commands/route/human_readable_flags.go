@@ -15,6 +15,9 @@
 package route
 
 import (
+	"fmt"
+	"strings"
+
 	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
@@ -28,6 +31,7 @@ func RouteListHandlers(h hprinters.PrintHandler) {
 	kRouteColumnDefinitions := []metav1beta1.TableColumnDefinition{
 		{Name: "Name", Type: "string", Description: "Name of the Knative route.", Priority: 1},
 		{Name: "URL", Type: "string", Description: "URL of the Knative route.", Priority: 1},
+		{Name: "Traffic", Type: "string", Description: "Revisions receiving traffic and their share of it.", Priority: 1},
 		{Name: "READY", Type: "string", Description: "Ready condition status of the Knative route.", Priority: 1},
 	}
 	h.TableHandler(kRouteColumnDefinitions, printRoute)
@@ -58,6 +62,28 @@ func printRoute(route *servingv1.Route, options hprinters.PrintOptions) ([]metav
 	row.Cells = append(row.Cells,
 		name,
 		url,
+		trafficDistribution(route),
 		ready)
 	return []metav1beta1.TableRow{row}, nil
 }
+
+// trafficDistribution summarizes the traffic of the route like "90% foo-00001, 10% foo-00002",
+// targets without traffic, which are only addressed by their tag, are left out
+func trafficDistribution(route *servingv1.Route) string {
+	var targets []string
+	for _, target := range route.Status.Traffic {
+		if percent := trafficPercent(target); percent > 0 {
+			targets = append(targets, fmt.Sprintf("%d%% %s", percent, target.RevisionName))
+		}
+	}
+	return strings.Join(targets, ", ")
+}
+
+// trafficPercent returns the percent of the traffic target, which is not set for targets
+// which are only addressed by their tag
+func trafficPercent(target servingv1.TrafficTarget) int64 {
+	if target.Percent == nil {
+		return 0
+	}
+	return *target.Percent
+}
@@ -68,9 +68,9 @@ func TestRouteListDefaultOutput(t *testing.T) {
 	} else if !action.Matches("list", "routes") {
 		t.Errorf("Bad action %v", action)
 	}
-	assert.Check(t, util.ContainsAll(output[0], "NAME", "URL", "READY"))
-	assert.Check(t, util.ContainsAll(output[1], "foo"))
-	assert.Check(t, util.ContainsAll(output[2], "bar"))
+	assert.Check(t, util.ContainsAll(output[0], "NAME", "URL", "TRAFFIC", "READY"))
+	assert.Check(t, util.ContainsAll(output[1], "foo", "100% foo-01234"))
+	assert.Check(t, util.ContainsAll(output[2], "bar", "100% bar-98765"))
 }
 
 func TestRouteListDefaultOutputNoHeaders(t *testing.T) {
@@ -102,7 +102,17 @@ func TestRouteListWithTwoTargetsOutput(t *testing.T) {
 		t.Errorf("Bad action %v", action)
 	}
 	assert.Check(t, util.ContainsAll(output[0], "NAME", "URL", "READY"))
-	assert.Check(t, util.ContainsAll(output[1], "foo"))
+	assert.Check(t, util.ContainsAll(output[1], "foo", "20% foo-01234, 80% foo-98765"))
+}
+
+func TestRouteListTaggedTargetWithoutTraffic(t *testing.T) {
+	route := createMockRouteSingleTarget("foo", "foo-01234", 100)
+	route.Status.Traffic = append(route.Status.Traffic, servingv1.TrafficTarget{RevisionName: "foo-98765", Tag: "next"})
+	routeList := &servingv1.RouteList{Items: []servingv1.Route{*route}}
+	_, output, err := fakeRouteList([]string{"route", "list"}, routeList)
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsAll(output[1], "100% foo-01234"))
+	assert.Check(t, util.ContainsNone(output[1], "foo-98765"))
 }
 
 func createMockRouteMeta(name string) *servingv1.Route {
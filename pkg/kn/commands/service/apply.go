@@ -41,20 +41,34 @@ kn service apply s0 --image knativesamples/helloworld --env foo=bar
 
 # Read the service declaration from a file
 kn service apply s0 --filename my-svc.yml
+
+# Apply the staging variant of a service, declared as overlay of a base manifest
+kn service apply --extends base.yml --filename staging.yml
 `
 
 func NewServiceApplyCommand(p *commands.KnParams) *cobra.Command {
 	var applyFlags ConfigurationEditFlags
 	var waitFlags commands.WaitFlags
+	var signature signatureFlags
+	var policy policyFlags
+	var dryRun dryRunFlags
 
 	serviceApplyCommand := &cobra.Command{
 		Use:     "apply NAME",
 		Short:   "Apply a service declaration",
 		Example: applyExample,
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
-			if len(args) != 1 && applyFlags.Filename == "" {
+			if len(args) != 1 && !applyFlags.fromManifest() {
 				return errors.New("'service apply' requires the service name given as single argument")
 			}
+			err = signature.validate()
+			if err != nil {
+				return err
+			}
+			err = dryRun.validate(p)
+			if err != nil {
+				return err
+			}
 			name := ""
 			if len(args) == 1 {
 				name = args[0]
@@ -67,7 +81,7 @@ func NewServiceApplyCommand(p *commands.KnParams) *cobra.Command {
 
 			var service *servingv1.Service
 			applyFlags.RevisionName = ""
-			if applyFlags.Filename == "" {
+			if !applyFlags.fromManifest() {
 				service, err = constructService(cmd, applyFlags, name, namespace)
 			} else {
 				service, err = constructServiceFromFile(cmd, applyFlags, name, namespace)
@@ -75,32 +89,50 @@ func NewServiceApplyCommand(p *commands.KnParams) *cobra.Command {
 			if err != nil {
 				return err
 			}
-
-			client, err := p.NewServingClient(namespace)
+			err = resolveImageStreamTags(p, namespace, &service.Spec.Template)
 			if err != nil {
 				return err
 			}
-
-			waitDoing, waitVerb, err := examineServiceForApply(cmd, client, service.Name)
+			err = signature.verifyImages(&service.Spec.Template)
 			if err != nil {
 				return err
 			}
-
-			hasChanged, err := client.ApplyService(service)
+			err = pinImageDigests(&service.Spec.Template, applyFlags.PinImageDigest)
+			if err != nil {
+				return err
+			}
+			err = pinImagesToPlatform(p, &service.Spec.Template, applyFlags.Platform, cmd.OutOrStdout())
 			if err != nil {
 				return err
 			}
-			if !hasChanged {
-				fmt.Fprintf(cmd.OutOrStdout(), "No changes to apply to service '%s'.\n", service.Name)
+			err = policy.check(p, service, cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+			if dryRun.client() {
+				return dryRun.print(service, &outputFlags{}, cmd.OutOrStdout())
+			}
 
-				return showUrl(client, service.Name, "unchanged", "", cmd.OutOrStdout())
+			client, err := p.NewServingClient(namespace)
+			if err != nil {
+				return err
 			}
-			return waitIfRequested(client, service.Name, waitFlags, waitDoing, waitVerb, cmd.OutOrStdout())
+			if dryRun.server() {
+				return dryRun.run(applyOperation("", ""), client, service, &outputFlags{}, cmd.OutOrStdout())
+			}
+
+			return ApplyDeclaration(cmd, client, service, waitFlags)
 		},
 	}
 	commands.AddNamespaceFlags(serviceApplyCommand.Flags(), false)
 	applyFlags.AddCreateFlags(serviceApplyCommand)
+	signature.add(serviceApplyCommand)
+	policy.add(serviceApplyCommand)
+	dryRun.add(serviceApplyCommand)
 	waitFlags.AddConditionWaitFlags(serviceApplyCommand, commands.WaitDefaultTimeout, "apply", "service", "ready")
+	waitFlags.AddRoutePropagationFlags(serviceApplyCommand)
+	waitFlags.AddProgressFlags(serviceApplyCommand)
+	waitFlags.AddVerboseErrorsFlag(serviceApplyCommand, p)
 	return serviceApplyCommand
 }
 
@@ -121,3 +153,13 @@ func examineServiceForApply(cmd *cobra.Command, client clientservingv1.KnServing
 	}
 	return "Applying", "applied", nil
 }
+
+// ApplyDeclaration applies the service declaration like 'kn service apply' and waits for the
+// service if requested. It's used by 'kn apply' for the services of a kustomization.
+func ApplyDeclaration(cmd *cobra.Command, client clientservingv1.KnServingClient, service *servingv1.Service, waitFlags commands.WaitFlags) error {
+	waitDoing, waitVerb, err := examineServiceForApply(cmd, client, service.Name)
+	if err != nil {
+		return err
+	}
+	return applyOperation(waitDoing, waitVerb).run(client, service, waitFlags, cmd.OutOrStdout())
+}
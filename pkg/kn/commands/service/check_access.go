@@ -0,0 +1,210 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/serving/pkg/apis/serving"
+
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/printers"
+)
+
+// accessCheck is a single permission required by a command
+type accessCheck struct {
+	verb     string
+	resource string
+}
+
+func (c accessCheck) String() string {
+	return fmt.Sprintf("%s %s.%s", c.verb, c.resource, serving.GroupName)
+}
+
+// Permissions needed by the service commands
+var (
+	createAccessChecks = []accessCheck{
+		{"get", "services"},
+		{"create", "services"},
+		{"update", "services"},
+	}
+	updateAccessChecks = []accessCheck{
+		{"get", "services"},
+		{"update", "services"},
+		{"get", "revisions"},
+	}
+	serviceAccessChecks = map[string][]accessCheck{
+		"create": createAccessChecks,
+		"update": updateAccessChecks,
+		"apply": {
+			{"get", "services"},
+			{"create", "services"},
+			{"patch", "services"},
+		},
+		"delete": {
+			{"delete", "services"},
+		},
+		"describe": {
+			{"get", "services"},
+			{"get", "revisions"},
+		},
+		"list": {
+			{"list", "services"},
+		},
+	}
+)
+
+// accessResult is the outcome of an accessCheck
+type accessResult struct {
+	check   accessCheck
+	allowed bool
+}
+
+var checkAccessExample = `
+  # Check the permissions needed by all service commands in the current namespace
+  kn service check-access
+
+  # Check the permissions needed for creating and updating services in namespace 'myns'
+  kn service check-access create update -n myns`
+
+// NewServiceCheckAccessCommand returns a new command for checking the permissions of the current user
+func NewServiceCheckAccessCommand(p *commands.KnParams) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "check-access [COMMAND...]",
+		Short: "Check the permissions required by service commands",
+		Long: "Check whether the current user has all permissions required by the given service commands " +
+			"(" + strings.Join(accessCheckCommandNames(), ", ") + "). All commands are checked if none is given.",
+		Example: checkAccessExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			names := args
+			if len(names) == 0 {
+				names = accessCheckCommandNames()
+			}
+			var checks []accessCheck
+			for _, name := range names {
+				commandChecks, ok := serviceAccessChecks[name]
+				if !ok {
+					return fmt.Errorf("unknown command '%s' for checking access, must be one of %s", name, strings.Join(accessCheckCommandNames(), ", "))
+				}
+				checks = appendAccessChecks(checks, commandChecks)
+			}
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			results, err := reviewAccess(p, namespace, checks)
+			if err != nil {
+				return err
+			}
+			err = printAccessResults(cmd.OutOrStdout(), results)
+			if err != nil {
+				return err
+			}
+			return missingAccessError(results, fmt.Sprintf("'kn service %s'", strings.Join(names, "/")), namespace)
+		},
+	}
+	commands.AddNamespaceFlags(command.Flags(), false)
+	return command
+}
+
+// preflightAccessCheck returns an error listing all missing permissions of the given checks
+func preflightAccessCheck(p *commands.KnParams, namespace string, what string, checks []accessCheck) error {
+	results, err := reviewAccess(p, namespace, checks)
+	if err != nil {
+		return err
+	}
+	return missingAccessError(results, what, namespace)
+}
+
+// reviewAccess performs a SelfSubjectAccessReview for each check
+func reviewAccess(p *commands.KnParams, namespace string, checks []accessCheck) ([]accessResult, error) {
+	kubeClient, err := p.NewKubeClient()
+	if err != nil {
+		return nil, err
+	}
+	results := make([]accessResult, 0, len(checks))
+	for _, check := range checks {
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: namespace,
+					Verb:      check.verb,
+					Group:     serving.GroupName,
+					Resource:  check.resource,
+				},
+			},
+		}
+		response, err := kubeClient.AuthorizationV1().SelfSubjectAccessReviews().Create(context.TODO(), review, metav1.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("cannot check permission to %s: %v", check, err)
+		}
+		results = append(results, accessResult{check: check, allowed: response.Status.Allowed})
+	}
+	return results, nil
+}
+
+func missingAccessError(results []accessResult, what string, namespace string) error {
+	var missing []string
+	for _, result := range results {
+		if !result.allowed {
+			missing = append(missing, result.check.String())
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("missing permissions for %s in namespace '%s': %s", what, namespace, strings.Join(missing, ", "))
+}
+
+func printAccessResults(out io.Writer, results []accessResult) error {
+	tw := printers.NewTabWriter(out)
+	fmt.Fprintln(tw, "VERB\tRESOURCE\tALLOWED")
+	for _, result := range results {
+		allowed := "no"
+		if result.allowed {
+			allowed = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s.%s\t%s\n", result.check.verb, result.check.resource, serving.GroupName, allowed)
+	}
+	return tw.Flush()
+}
+
+// appendAccessChecks adds all checks which are not already contained
+func appendAccessChecks(checks []accessCheck, toAdd []accessCheck) []accessCheck {
+	for _, check := range toAdd {
+		found := false
+		for _, existing := range checks {
+			if existing == check {
+				found = true
+				break
+			}
+		}
+		if !found {
+			checks = append(checks, check)
+		}
+	}
+	return checks
+}
+
+func accessCheckCommandNames() []string {
+	return []string{"create", "update", "apply", "delete", "describe", "list"}
+}
@@ -0,0 +1,108 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	clienttesting "k8s.io/client-go/testing"
+
+	"knative.dev/client/pkg/kn/commands"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+)
+
+// executeServiceAccessCommand runs a service command against a fake access review API
+// which denies the given "verb resource" permissions
+func executeServiceAccessCommand(client clientservingv1.KnServingClient, denied []string, args ...string) (string, error) {
+	knParams := &commands.KnParams{}
+	knParams.ClientConfig = blankConfig
+
+	output := new(bytes.Buffer)
+	knParams.Output = output
+	knParams.NewServingClient = func(namespace string) (clientservingv1.KnServingClient, error) {
+		return client, nil
+	}
+	kubeClient := commands.NewFakeKubeClient()
+	kubeClient.PrependReactor("create", "selfsubjectaccessreviews", func(a clienttesting.Action) (bool, runtime.Object, error) {
+		review := a.(clienttesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		review.Status.Allowed = !util.SliceContainsIgnoreCase(denied, attributes.Verb+" "+attributes.Resource)
+		return true, review, nil
+	})
+	knParams.NewKubeClient = func() (kubernetes.Interface, error) {
+		return kubeClient, nil
+	}
+	cmd := NewServiceCommand(knParams)
+	cmd.SetArgs(args)
+	cmd.SetOutput(output)
+	err := cmd.Execute()
+	return output.String(), err
+}
+
+func TestServiceCheckAccess(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	output, err := executeServiceAccessCommand(client, nil, "check-access", "create", "describe")
+	assert.NilError(t, err)
+	lines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(lines[0], "VERB", "RESOURCE", "ALLOWED"))
+	assert.Check(t, util.ContainsAll(lines[1], "get", "services.serving.knative.dev", "yes"))
+	assert.Check(t, util.ContainsAll(lines[2], "create", "services.serving.knative.dev", "yes"))
+	assert.Check(t, util.ContainsAll(lines[3], "update", "services.serving.knative.dev", "yes"))
+	// "get services" is only checked once
+	assert.Check(t, util.ContainsAll(lines[4], "get", "revisions.serving.knative.dev", "yes"))
+	assert.Equal(t, lines[5], "")
+}
+
+func TestServiceCheckAccessMissing(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	output, err := executeServiceAccessCommand(client, []string{"delete services"}, "check-access")
+	assert.ErrorContains(t, err, "missing permissions")
+	assert.ErrorContains(t, err, "delete services.serving.knative.dev")
+	assert.Assert(t, util.ContainsAll(output, "delete", "no"))
+}
+
+func TestServiceCheckAccessUnknownCommand(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	_, err := executeServiceAccessCommand(client, nil, "check-access", "blub")
+	assert.ErrorContains(t, err, "unknown command 'blub'")
+}
+
+func TestServiceCreatePreflight(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+
+	_, err := executeServiceAccessCommand(client, []string{"create services"},
+		"create", "foo", "--image", "gcr.io/foo/bar:baz", "--preflight")
+	assert.ErrorContains(t, err, "missing permissions for 'kn service create' in namespace 'default': create services.serving.knative.dev")
+	// Nothing has been called on the serving API
+	r.Validate()
+}
+
+func TestServiceUpdatePreflight(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+
+	_, err := executeServiceAccessCommand(client, []string{"update services", "get revisions"},
+		"update", "foo", "--env", "A=b", "--preflight")
+	assert.ErrorContains(t, err, "update services.serving.knative.dev, get revisions.serving.knative.dev")
+	r.Validate()
+}
@@ -0,0 +1,104 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"io"
+
+	"knative.dev/pkg/apis"
+
+	"knative.dev/client/pkg/i18n"
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/printers"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+)
+
+// Reason of the revision conditions when its deployment didn't become available in time
+const progressDeadlineExceededReason = "ProgressDeadlineExceeded"
+
+// resourceConditions are the conditions of a single resource, as shown in the conditions table
+type resourceConditions struct {
+	resource   string
+	conditions []apis.Condition
+}
+
+// printConditionsTable prints the conditions of the service, its configuration, its route and its
+// latest revision as one table, so that users can see why the service didn't become ready without
+// describing each of them. It is best effort: resources which can't be fetched are left out.
+func printConditionsTable(client clientservingv1.KnServingClient, serviceName string, out io.Writer) {
+	all := gatherConditions(client, serviceName)
+	if len(all) == 0 {
+		return
+	}
+	i18n.Fprintf(out, "\nConditions of service '%s' in namespace '%s':\n", serviceName, client.Namespace())
+	w := printers.NewTabWriter(out)
+	fmt.Fprintln(w, "RESOURCE\tTYPE\tSTATUS\tREASON\tAGE\tMESSAGE")
+	for _, rc := range all {
+		for _, cond := range rc.conditions {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", rc.resource, cond.Type, cond.Status, cond.Reason,
+				commands.Age(cond.LastTransitionTime.Inner.Time), cond.Message)
+		}
+	}
+	w.Flush()
+	if resource := progressDeadlineExceeded(all); resource != "" {
+		fmt.Fprintf(out, "\nThe pods of %s didn't become available within the progress deadline. "+
+			"Check the pods and events of the revision, or give it more time with --progress-deadline.\n", resource)
+	}
+}
+
+// progressDeadlineExceeded returns the resource whose deployment exceeded its progress
+// deadline, or an empty string. The revision is preferred over the resources
+// propagating its condition, so the resources are checked in reverse order.
+func progressDeadlineExceeded(all []resourceConditions) string {
+	for i := len(all) - 1; i >= 0; i-- {
+		rc := all[i]
+		for _, cond := range rc.conditions {
+			if cond.Reason == progressDeadlineExceededReason {
+				return rc.resource
+			}
+		}
+	}
+	return ""
+}
+
+// gatherConditions fetches the service and the resources created for it in one pass
+func gatherConditions(client clientservingv1.KnServingClient, serviceName string) []resourceConditions {
+	service, err := client.GetService(serviceName)
+	if err != nil {
+		return nil
+	}
+	all := []resourceConditions{{"service/" + serviceName, service.Status.Conditions}}
+
+	revisionName := service.Status.LatestCreatedRevisionName
+	configuration, err := client.GetConfiguration(serviceName)
+	if err == nil {
+		all = append(all, resourceConditions{"configuration/" + serviceName, configuration.Status.Conditions})
+		if revisionName == "" {
+			revisionName = configuration.Status.LatestCreatedRevisionName
+		}
+	}
+	route, err := client.GetRoute(serviceName)
+	if err == nil {
+		all = append(all, resourceConditions{"route/" + serviceName, route.Status.Conditions})
+	}
+	if revisionName != "" {
+		revision, err := client.GetRevision(revisionName)
+		if err == nil {
+			all = append(all, resourceConditions{"revision/" + revisionName, revision.Status.Conditions})
+		}
+	}
+	return all
+}
@@ -0,0 +1,180 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+	"knative.dev/client/pkg/util/mock"
+	"knative.dev/client/pkg/wait"
+)
+
+func TestServiceCreateFailedConditions(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", nil, apierrors.NewNotFound(servingv1.Resource("service"), "foo"))
+	r.CreateService(mock.Any(), nil)
+	r.WaitForService("foo", mock.Any(), wait.NoopMessageCallback(), errors.New("RevisionFailed: image pull failed"), time.Second)
+
+	service := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"}}
+	service.Status.LatestCreatedRevisionName = "foo-00001"
+	service.Status.Conditions = []apis.Condition{
+		{Type: apis.ConditionReady, Status: corev1.ConditionFalse, Reason: "RevisionFailed", Message: "Revision \"foo-00001\" failed"},
+	}
+	r.GetService("foo", service, nil)
+	configuration := &servingv1.Configuration{}
+	configuration.Status.Conditions = []apis.Condition{
+		{Type: apis.ConditionReady, Status: corev1.ConditionFalse, Reason: "RevisionFailed"},
+	}
+	r.GetConfiguration("foo", configuration, nil)
+	// A missing route is left out
+	r.GetRoute("foo", nil, apierrors.NewNotFound(servingv1.Resource("route"), "foo"))
+	revision := &servingv1.Revision{}
+	revision.Status.Conditions = []apis.Condition{
+		{Type: apis.ConditionReady, Status: corev1.ConditionFalse, Reason: "ImagePullBackOff", Message: "image pull failed"},
+		{Type: servingv1.RevisionConditionContainerHealthy, Status: corev1.ConditionUnknown},
+	}
+	r.GetRevision("foo-00001", revision, nil)
+
+	output, err := executeServiceCommand(client, "create", "foo", "--image", "gcr.io/foo/bar:baz")
+	assert.ErrorContains(t, err, "image pull failed")
+	assert.Assert(t, util.ContainsAll(output,
+		"Conditions of service 'foo' in namespace 'default':",
+		"RESOURCE", "TYPE", "STATUS", "REASON", "AGE", "MESSAGE",
+		"service/foo", "RevisionFailed", "Revision \"foo-00001\" failed",
+		"configuration/foo",
+		"revision/foo-00001", "ImagePullBackOff", "image pull failed", "ContainerHealthy", "Unknown"))
+	assert.Assert(t, util.ContainsNone(output, "route/foo", "progress deadline"))
+	r.Validate()
+}
+
+func TestProgressDeadlineExceeded(t *testing.T) {
+	all := []resourceConditions{
+		{"service/foo", []apis.Condition{{Type: apis.ConditionReady, Status: corev1.ConditionFalse, Reason: progressDeadlineExceededReason}}},
+		{"revision/foo-00002", []apis.Condition{
+			{Type: apis.ConditionReady, Status: corev1.ConditionFalse, Reason: progressDeadlineExceededReason},
+			{Type: servingv1.RevisionConditionResourcesAvailable, Status: corev1.ConditionFalse, Reason: progressDeadlineExceededReason},
+		}},
+	}
+	assert.Equal(t, progressDeadlineExceeded(all), "revision/foo-00002")
+	assert.Equal(t, progressDeadlineExceeded(all[:1]), "service/foo")
+	assert.Equal(t, progressDeadlineExceeded(nil), "")
+}
+
+func TestServiceUpdateProgressDeadlineExceeded(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	service := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"}}
+	service.Spec.Template.Spec.Containers = []corev1.Container{{Image: "gcr.io/foo/bar:v1"}}
+	r.GetService("foo", service, nil)
+	r.UpdateService(func(t *testing.T, updated *servingv1.Service) {
+		assert.Equal(t, updated.Spec.Template.Annotations["serving.knative.dev/progress-deadline"], "5m")
+	}, nil)
+	r.WaitForService("foo", mock.Any(), mock.Any(), errors.New("ProgressDeadlineExceeded"), time.Second)
+
+	failed := service.DeepCopy()
+	failed.Status.LatestCreatedRevisionName = "foo-00002"
+	r.GetService("foo", failed, nil)
+	r.GetConfiguration("foo", nil, apierrors.NewNotFound(servingv1.Resource("configuration"), "foo"))
+	r.GetRoute("foo", nil, apierrors.NewNotFound(servingv1.Resource("route"), "foo"))
+	revision := &servingv1.Revision{}
+	revision.Status.Conditions = []apis.Condition{
+		{Type: servingv1.RevisionConditionResourcesAvailable, Status: corev1.ConditionFalse, Reason: progressDeadlineExceededReason,
+			Message: "Initial scale was never achieved"},
+	}
+	r.GetRevision("foo-00002", revision, nil)
+
+	output, err := executeServiceCommand(client, "update", "foo", "--image", "gcr.io/foo/bar:v2", "--progress-deadline", "5m")
+	assert.ErrorContains(t, err, "ProgressDeadlineExceeded")
+	assert.Assert(t, util.ContainsAll(output, "revision/foo-00002", "didn't become available within the progress deadline", "--progress-deadline"))
+	r.Validate()
+}
+
+func TestServiceCreateVerboseErrors(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", nil, apierrors.NewNotFound(servingv1.Resource("service"), "foo"))
+	r.CreateService(mock.Any(), nil)
+	r.WaitForService("foo", mock.Any(), mock.Any(), errors.New("timeout: service 'foo' not ready after 600 seconds"), time.Second)
+
+	service := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"}}
+	service.Status.LatestCreatedRevisionName = "foo-00001"
+	revision := &servingv1.Revision{}
+	revision.Status.Conditions = []apis.Condition{
+		{Type: servingv1.RevisionConditionContainerHealthy, Status: corev1.ConditionFalse, Reason: "ExitCode1", Message: "Container failed with: boom"},
+	}
+	// Conditions table
+	r.GetService("foo", service, nil)
+	r.GetConfiguration("foo", nil, apierrors.NewNotFound(servingv1.Resource("configuration"), "foo"))
+	r.GetRoute("foo", nil, apierrors.NewNotFound(servingv1.Resource("route"), "foo"))
+	r.GetRevision("foo-00001", revision, nil)
+	// Diagnostics
+	r.GetService("foo", service, nil)
+	r.GetRevision("foo-00001", revision, nil)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo-00001-deployment-5d7b-x2lq", Namespace: "default",
+			Labels: map[string]string{"serving.knative.dev/revision": "foo-00001"}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{
+			Name: "user-container", RestartCount: 4,
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		}}},
+	}
+	event := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "foo-00001-deployment-5d7b-x2lq.1", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "foo-00001-deployment-5d7b-x2lq"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container",
+		LastTimestamp:  metav1.NewTime(time.Now().Add(-time.Minute)),
+	}
+
+	output, err := executeServiceQuotaCommand(client, []runtime.Object{pod, event},
+		"create", "foo", "--image", "gcr.io/foo/bar:baz", "--verbose-errors")
+	assert.ErrorContains(t, err, "not ready")
+	assert.Assert(t, util.ContainsAll(output,
+		"Conditions of service 'foo' in namespace 'default':",
+		"Diagnostics of revision 'foo-00001' of service 'foo' in namespace 'default':",
+		"ExitCode1", "Container failed with: boom",
+		"foo-00001-deployment-5d7b-x2lq", "CrashLoopBackOff",
+		"BackOff", "Back-off restarting failed container"))
+	r.Validate()
+}
+
+func TestServiceCreateFailedWithoutVerboseErrors(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", nil, apierrors.NewNotFound(servingv1.Resource("service"), "foo"))
+	r.CreateService(mock.Any(), nil)
+	r.WaitForService("foo", mock.Any(), mock.Any(), errors.New("RevisionFailed"), time.Second)
+	r.GetService("foo", nil, apierrors.NewNotFound(servingv1.Resource("service"), "foo"))
+
+	output, err := executeServiceCommand(client, "create", "foo", "--image", "gcr.io/foo/bar:baz")
+	assert.ErrorContains(t, err, "RevisionFailed")
+	assert.Assert(t, util.ContainsNone(output, "Diagnostics"))
+	r.Validate()
+}
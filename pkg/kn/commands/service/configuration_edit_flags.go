@@ -16,6 +16,7 @@ package service
 
 import (
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 
@@ -23,6 +24,7 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"knative.dev/client/pkg/kn/commands"
 	knflags "knative.dev/client/pkg/kn/flags"
 	servinglib "knative.dev/client/pkg/serving"
 	"knative.dev/client/pkg/util"
@@ -44,6 +46,8 @@ type ConfigurationEditFlags struct {
 	ConcurrencyLimit       int
 	ConcurrencyUtilization int
 	AutoscaleWindow        string
+	RolloutDuration        string
+	ProgressDeadline       string
 	Labels                 []string
 	LabelsService          []string
 	LabelsRevision         []string
@@ -52,14 +56,24 @@ type ConfigurationEditFlags struct {
 	AnnotationsService     []string
 	AnnotationsRevision    []string
 	ClusterLocal           bool
+	AsyncIngress           bool
+	Description            string
+	Owners                 []string
 	ScaleInit              int
+	RequestTimeout         int64
+	QueueProxyResources    []string
 
 	// Preferences about how to do the action.
 	LockToDigest         bool
+	PinImageDigest       bool
+	Platform             string
 	GenerateRevisionName bool
 	ForceCreate          bool
+	// GenerateName is the prefix of a name which the server generates for a new service
+	GenerateName string
 
 	Filename string
+	Extends  []string
 
 	// Bookkeeping
 	flags []string
@@ -95,15 +109,49 @@ func (p *ConfigurationEditFlags) addSharedFlags(command *cobra.Command) {
 	command.Flags().IntVar(&p.MaxScale, "scale-max", 0, "Maximum number of replicas.")
 	p.markFlagMakesRevision("scale-max")
 
-	command.Flags().StringVar(&p.AutoscaleWindow, "autoscale-window", "", "Duration to look back for making auto-scaling decisions. The service is scaled to zero if no request was received in during that time. (eg: 10s)")
+	command.Flags().StringVar(&p.AutoscaleWindow, "autoscale-window", "", "Duration to look back for making auto-scaling decisions.")
+	command.Flags().MarkHidden("autoscale-window")
 	p.markFlagMakesRevision("autoscale-window")
 
+	command.Flags().StringVar(&p.AutoscaleWindow, "scale-window", "",
+		"Duration to look back for making auto-scaling decisions. The service is scaled to zero if no request was received in during that time. "+
+			"Must be between 6s and 1h (eg: 10s)")
+	p.markFlagMakesRevision("scale-window")
+
+	command.Flags().StringVar(&p.RolloutDuration, "rollout-duration", "",
+		"Duration over which traffic is gradually shifted to the latest revision if supported by the cluster (eg: 380s). "+
+			"Use 0s for switching traffic at once.")
+	commands.MarkFlagRequiresVersion(command.Flags(), "rollout-duration", "serving", "v0.20.0")
+	// Don't mark as changing the revision, it's a service level setting
+
+	command.Flags().StringVar(&p.ProgressDeadline, "progress-deadline", "",
+		"Maximum time the pods of a new revision may take to become available before the revision is marked as failed, "+
+			"if supported by the cluster (eg: 10m). The previous revision keeps serving until then.")
+	p.markFlagMakesRevision("progress-deadline")
+
 	knflags.AddBothBoolFlagsUnhidden(command.Flags(), &p.ClusterLocal, "cluster-local", "", false,
 		"Specify that the service be private. (--no-cluster-local will make the service publicly available)")
 	//TODO: Need to also not change revision when already set (solution to issue #646)
 	p.markFlagMakesRevision("cluster-local")
 	p.markFlagMakesRevision("no-cluster-local")
 
+	knflags.AddBothBoolFlagsUnhidden(command.Flags(), &p.AsyncIngress, "async-ingress", "", false,
+		"Route requests through the Knative async component if it is installed. Requests with the header "+
+			"'Prefer: respond-async' are then answered immediately with '202 Accepted' and processed in the background. "+
+			"(--no-async-ingress restores the default ingress)")
+	// Don't mark as changing the revision, it's a service level setting
+
+	command.Flags().StringVar(&p.Description, "description", "",
+		"Human readable description of the service, shown by 'kn service describe'. "+
+			"Use an empty string to remove the description.")
+	// Don't mark as changing the revision, it's a service level setting
+
+	command.Flags().StringArrayVar(&p.Owners, "owner", []string{},
+		"Owner of the service stored as label with the prefix '"+servinglib.OwnerLabelPrefix+"'. key=value (e.g. team=payments); "+
+			"you may provide this flag any number of times to set multiple owners. "+
+			"To unset, specify the owner key followed by a \"-\" (e.g., team-).")
+	// Don't mark as changing the revision, it's a service level setting
+
 	command.Flags().IntVar(&p.ConcurrencyTarget, "concurrency-target", 0,
 		"Recommendation for when to scale up based on the concurrent number of incoming request. "+
 			"Defaults to --concurrency-limit when given.")
@@ -136,7 +184,7 @@ func (p *ConfigurationEditFlags) addSharedFlags(command *cobra.Command) {
 			"precedence over the \"label\" flag.")
 	p.markFlagMakesRevision("label-revision")
 
-	command.Flags().StringVar(&p.RevisionName, "revision-name", "{{.Service}}-{{.Random 5}}-{{.Generation}}",
+	command.Flags().StringVar(&p.RevisionName, "revision-name", defaultRevisionName,
 		"The revision name to set. Must start with the service name and a dash as a prefix. "+
 			"Empty revision name will result in the server generating a name for the revision. "+
 			"Accepts golang templates, allowing {{.Service}} for the service name, "+
@@ -148,15 +196,29 @@ func (p *ConfigurationEditFlags) addSharedFlags(command *cobra.Command) {
 			"the image. (--no-lock-to-digest pulls the image tag afresh with each new revision)")
 	// Don't mark as changing the revision.
 
+	command.Flags().BoolVar(&p.PinImageDigest, "pin-image-digest", false,
+		"Write the digests of the images into the service instead of their tags, so that exported services and rollbacks "+
+			"are reproducible. Tags are resolved with the registry, authenticated with the credentials of the Docker configuration, "+
+			"an unchanged image is pinned to the digest of the latest revision.")
+	p.markFlagMakesRevision("pin-image-digest")
+
+	command.Flags().StringVar(&p.Platform, "platform", "",
+		"Lock the images to the digest of the manifest for this platform (OS/ARCH[/VARIANT], e.g. linux/arm64) "+
+			"when they are multi-platform image indexes. Warns if the cluster's nodes don't run on the platform. "+
+			"The registry is accessed with the credentials of the Docker configuration.")
+	p.markFlagMakesRevision("platform")
+
 	command.Flags().StringArrayVarP(&p.Annotations, "annotation", "a", []string{},
 		"Annotations to set for both Service and Revision. name=value; you may provide this flag "+
 			"any number of times to set multiple annotations. "+
+			"Use name=@path for reading the value from a file, name=@- for reading it from stdin and name=@@value for a value starting with '@'. "+
 			"To unset, specify the annotation name followed by a \"-\" (e.g., name-).")
 	p.markFlagMakesRevision("annotation")
 
 	command.Flags().StringArrayVarP(&p.AnnotationsService, "annotation-service", "", []string{},
 		"Service annotation to set. name=value; you may provide this flag "+
 			"any number of times to set multiple annotations. "+
+			"Use name=@path for reading the value from a file, name=@- for reading it from stdin and name=@@value for a value starting with '@'. "+
 			"To unset, specify the annotation name followed by a \"-\" (e.g., name-). This flag takes "+
 			"precedence over the \"annotation\" flag.")
 	p.markFlagMakesRevision("annotation-service")
@@ -164,12 +226,24 @@ func (p *ConfigurationEditFlags) addSharedFlags(command *cobra.Command) {
 	command.Flags().StringArrayVarP(&p.AnnotationsRevision, "annotation-revision", "", []string{},
 		"Revision annotation to set. name=value; you may provide this flag "+
 			"any number of times to set multiple annotations. "+
+			"Use name=@path for reading the value from a file, name=@- for reading it from stdin and name=@@value for a value starting with '@'. "+
 			"To unset, specify the annotation name followed by a \"-\" (e.g., name-). This flag takes "+
 			"precedence over the \"annotation\" flag.")
 	p.markFlagMakesRevision("annotation-revision")
 
 	command.Flags().IntVar(&p.ScaleInit, "scale-init", 0, "Initial number of replicas with which a service starts. Can be 0 or a positive integer.")
+	commands.MarkFlagRequiresVersion(command.Flags(), "scale-init", "serving", "v0.17.0")
 	p.markFlagMakesRevision("scale-init")
+
+	command.Flags().Int64Var(&p.RequestTimeout, "request-timeout", 0,
+		"Duration in seconds that the request routing layer will wait for a request delivered to a container to begin replying.")
+	p.markFlagMakesRevision("request-timeout")
+
+	command.Flags().StringArrayVar(&p.QueueProxyResources, "queue-proxy-resources", []string{},
+		"Resources of the queue-proxy sidecar. key=value; supported keys: percentage "+
+			"(percentage of the user container's resources, between 0.1 and 100). "+
+			"To unset, specify the key followed by a \"-\" (e.g., percentage-).")
+	p.markFlagMakesRevision("queue-proxy-resources")
 }
 
 // AddUpdateFlags adds the flags specific to update.
@@ -182,11 +256,22 @@ func (p *ConfigurationEditFlags) AddCreateFlags(command *cobra.Command) {
 	p.addSharedFlags(command)
 	command.Flags().BoolVar(&p.ForceCreate, "force", false,
 		"Create service forcefully, replaces existing service if any.")
-	command.Flags().StringVarP(&p.Filename, "filename", "f", "", "Create a service from file. "+
+	command.Flags().StringVarP(&p.Filename, "filename", "f", "", "Create a service from a YAML or JSON file, or from stdin with '-f -'. "+
 		"The created service can be further modified by combining with other options. "+
 		"For example, -f /path/to/file --env NAME=value adds also an environment variable.")
 	command.MarkFlagFilename("filename")
 	p.markFlagMakesRevision("filename")
+	command.Flags().StringArrayVar(&p.Extends, "extends", nil, "Base manifest (YAML or JSON) which the service extends. "+
+		"The option can be given multiple times, each manifest is merged on top of the ones before, then the --filename manifest "+
+		"and finally the other options. Maps are merged, containers, env vars and volumes are merged by name, "+
+		"other lists are replaced.")
+	command.MarkFlagFilename("extends")
+	p.markFlagMakesRevision("extends")
+}
+
+// fromManifest returns true if the service is read from --filename or --extends manifests
+func (p *ConfigurationEditFlags) fromManifest() bool {
+	return p.Filename != "" || len(p.Extends) > 0
 }
 
 // Apply mutates the given service according to the flags in the command.
@@ -197,12 +282,35 @@ func (p *ConfigurationEditFlags) Apply(
 
 	template := &service.Spec.Template
 
-	err := p.PodSpecFlags.ResolvePodSpec(&template.Spec.PodSpec, cmd.Flags())
+	// Values of --env and --annotation can be read from files, and from stdin unless it must not be
+	// read or the service itself has been read from it
+	var stdin io.Reader
+	if !commands.IsNonInteractive(cmd.Flags()) && p.Filename != "-" {
+		stdin = cmd.InOrStdin()
+	}
+	fileValues := util.NewFileValueResolver(stdin)
+
+	if p.PinImageDigest && !p.LockToDigest {
+		return fmt.Errorf("--pin-image-digest can't be used with --no-lock-to-digest")
+	}
+	if p.Platform != "" {
+		if !p.LockToDigest {
+			return fmt.Errorf("--platform can't be used with --no-lock-to-digest")
+		}
+		err := validatePlatform(p.Platform)
+		if err != nil {
+			return err
+		}
+	}
+
+	name, err := servinglib.GenerateRevisionName(p.RevisionName, service)
 	if err != nil {
 		return err
 	}
 
-	name, err := servinglib.GenerateRevisionName(p.RevisionName, service)
+	// Templates in --env values are expanded before files are read, so that paths can contain templates
+	envValues := util.ChainValueResolvers(util.NewTemplateValueResolver(newEnvTemplateData(service, name)), fileValues)
+	err = p.PodSpecFlags.ResolvePodSpec(&template.Spec.PodSpec, cmd.Flags(), envValues)
 	if err != nil {
 		return err
 	}
@@ -212,11 +320,11 @@ func (p *ConfigurationEditFlags) Apply(
 	}
 
 	imageSet := false
-	if cmd.Flags().Changed("image") {
+	if p.PodSpecFlags.Changed(cmd.Flags(), "image") {
 		imageSet = true
 	}
 	_, userImagePresent := template.Annotations[servinglib.UserImageAnnotationKey]
-	freezeMode := userImagePresent || cmd.Flags().Changed("lock-to-digest")
+	freezeMode := userImagePresent || cmd.Flags().Changed("lock-to-digest") || p.PinImageDigest
 	if p.LockToDigest && p.AnyMutation(cmd) && freezeMode {
 		servinglib.SetUserImageAnnot(template)
 		if !imageSet {
@@ -244,40 +352,51 @@ func (p *ConfigurationEditFlags) Apply(
 	}
 
 	// Deprecated "min-scale" in 0.19, updated to "scale-min"
-	if cmd.Flags().Changed("scale-min") || cmd.Flags().Changed("min-scale") {
-		err = servinglib.UpdateMinScale(template, p.MinScale)
+	minScaleChanged := cmd.Flags().Changed("scale-min") || cmd.Flags().Changed("min-scale")
+	// Deprecated "max-scale" in 0.19, updated to "scale-max"
+	maxScaleChanged := cmd.Flags().Changed("scale-max") || cmd.Flags().Changed("max-scale")
+
+	if cmd.Flags().Changed("scale") {
+		if maxScaleChanged {
+			return fmt.Errorf("only --scale or --scale-max can be specified")
+		} else if minScaleChanged {
+			return fmt.Errorf("only --scale or --scale-min can be specified")
+		}
+		err = servinglib.UpdateScaleBounds(template, &p.Scale, &p.Scale)
+		if err != nil {
+			return err
+		}
+	} else if minScaleChanged || maxScaleChanged {
+		var min, max *int
+		if minScaleChanged {
+			min = &p.MinScale
+		}
+		if maxScaleChanged {
+			max = &p.MaxScale
+		}
+		err = servinglib.UpdateScaleBounds(template, min, max)
 		if err != nil {
 			return err
 		}
 	}
 
-	// Deprecated "max-scale" in 0.19, updated to "scale-max"
-	if cmd.Flags().Changed("scale-max") || cmd.Flags().Changed("max-scale") {
-		err = servinglib.UpdateMaxScale(template, p.MaxScale)
+	// Deprecated "autoscale-window", updated to "scale-window"
+	if cmd.Flags().Changed("scale-window") || cmd.Flags().Changed("autoscale-window") {
+		err = servinglib.UpdateAutoscaleWindow(template, p.AutoscaleWindow)
 		if err != nil {
 			return err
 		}
 	}
 
-	if cmd.Flags().Changed("scale") {
-		if cmd.Flags().Changed("scale-max") {
-			return fmt.Errorf("only --scale or --scale-max can be specified")
-		} else if cmd.Flags().Changed("scale-min") {
-			return fmt.Errorf("only --scale or --scale-min can be specified")
-		} else {
-			err = servinglib.UpdateMaxScale(template, p.Scale)
-			if err != nil {
-				return err
-			}
-			err = servinglib.UpdateMinScale(template, p.Scale)
-			if err != nil {
-				return err
-			}
+	if cmd.Flags().Changed("progress-deadline") {
+		err = servinglib.UpdateProgressDeadline(template, p.ProgressDeadline)
+		if err != nil {
+			return err
 		}
 	}
 
-	if cmd.Flags().Changed("autoscale-window") {
-		err = servinglib.UpdateAutoscaleWindow(template, p.AutoscaleWindow)
+	if cmd.Flags().Changed("rollout-duration") {
+		err = servinglib.UpdateRolloutDuration(service, p.RolloutDuration)
 		if err != nil {
 			return err
 		}
@@ -314,6 +433,20 @@ func (p *ConfigurationEditFlags) Apply(
 		}
 	}
 
+	if cmd.Flags().Changed("async-ingress") || cmd.Flags().Changed("no-async-ingress") {
+		err = servinglib.UpdateAsyncIngress(service, p.AsyncIngress)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmd.Flags().Changed("description") {
+		err = servinglib.UpdateServiceDescription(service, p.Description)
+		if err != nil {
+			return err
+		}
+	}
+
 	if cmd.Flags().Changed("label") || cmd.Flags().Changed("label-service") || cmd.Flags().Changed("label-revision") {
 		labelsAllMap, err := util.MapFromArrayAllowingSingles(p.Labels, "=")
 		if err != nil {
@@ -331,6 +464,18 @@ func (p *ConfigurationEditFlags) Apply(
 		}
 	}
 
+	if cmd.Flags().Changed("owner") {
+		ownersMap, err := util.MapFromArrayAllowingSingles(p.Owners, "=")
+		if err != nil {
+			return fmt.Errorf("Invalid --owner: %w", err)
+		}
+		ownersToRemove := util.ParseMinusSuffix(ownersMap)
+		err = servinglib.UpdateServiceOwners(service, ownersMap, ownersToRemove)
+		if err != nil {
+			return err
+		}
+	}
+
 	if cmd.Flags().Changed("annotation") || cmd.Flags().Changed("annotation-service") || cmd.Flags().Changed("annotation-revision") {
 		annotationsAllMap, err := util.MapFromArrayAllowingSingles(p.Annotations, "=")
 		if err != nil {
@@ -346,6 +491,18 @@ func (p *ConfigurationEditFlags) Apply(
 		}
 
 		annotationsToRemove := util.ParseMinusSuffix(annotationsAllMap)
+		err = fileValues.Resolve("annotation", annotationsAllMap)
+		if err != nil {
+			return err
+		}
+		err = fileValues.Resolve("annotation-revision", annotationRevisionFlagMap)
+		if err != nil {
+			return err
+		}
+		err = fileValues.Resolve("annotation-service", annotationServiceFlagMap)
+		if err != nil {
+			return err
+		}
 
 		revisionAnnotations := make(util.StringMap)
 		revisionAnnotations.Merge(annotationsAllMap)
@@ -379,6 +536,25 @@ func (p *ConfigurationEditFlags) Apply(
 
 	}
 
+	if cmd.Flags().Changed("queue-proxy-resources") {
+		resourcesMap, err := util.MapFromArrayAllowingSingles(p.QueueProxyResources, "=")
+		if err != nil {
+			return fmt.Errorf("Invalid --queue-proxy-resources: %w", err)
+		}
+		resourcesToRemove := util.ParseMinusSuffix(resourcesMap)
+		err = servinglib.UpdateQueueProxyResources(template, resourcesMap, resourcesToRemove)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmd.Flags().Changed("request-timeout") {
+		err = servinglib.UpdateRequestTimeout(template, p.RequestTimeout)
+		if err != nil {
+			return err
+		}
+	}
+
 	if cmd.Flags().Changed("scale-init") {
 		containsAnnotation := func(annotationList []string, annotation string) bool {
 			for _, element := range annotationList {
@@ -426,3 +602,26 @@ func (p *ConfigurationEditFlags) AnyMutation(cmd *cobra.Command) bool {
 	}
 	return false
 }
+
+// envTemplateData are the variables which can be used in templates in --env values,
+// e.g. NAME={{.Service}}
+type envTemplateData struct {
+	// Name of the service, also available as .ServiceName
+	Service     string
+	ServiceName string
+	Namespace   string
+	// Generation of the service after the change, as for --revision-name
+	Generation int64
+	// Name of the revision to be created, empty if the server generates the name
+	RevisionName string
+}
+
+func newEnvTemplateData(service *servingv1.Service, revisionName string) envTemplateData {
+	return envTemplateData{
+		Service:      service.Name,
+		ServiceName:  service.Name,
+		Namespace:    service.Namespace,
+		Generation:   service.Generation + 1,
+		RevisionName: revisionName,
+	}
+}
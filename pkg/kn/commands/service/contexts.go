@@ -0,0 +1,166 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/i18n"
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/printers"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/wait"
+)
+
+// servicePreparer constructs a service for the cluster and namespace of the given
+// parameters and returns the client for creating it and whether it already exists
+type servicePreparer func(p *commands.KnParams, out io.Writer) (*servingv1.Service, clientservingv1.KnServingClient, bool, error)
+
+// newParamsForContext returns the parameters for accessing the cluster of a kubeconfig
+// context, can be replaced in tests
+var newParamsForContext = func(p *commands.KnParams, context string) (*commands.KnParams, error) {
+	return p.ForContext(context)
+}
+
+// contextResult is the outcome of creating a service in the cluster of a context
+type contextResult struct {
+	context   string
+	namespace string
+	client    clientservingv1.KnServingClient
+	service   *servingv1.Service
+	status    string
+	url       string
+	duration  time.Duration
+	err       error
+}
+
+// createServiceInContexts creates the service in the clusters of all given contexts,
+// waits for them in parallel and prints a result table. It fails if the service
+// could not be created or did not become ready in any of the clusters.
+func createServiceInContexts(p *commands.KnParams, contexts []string, prepare servicePreparer, retryPolicy clientservingv1.RetryPolicy, waitFlags commands.WaitFlags, out io.Writer) error {
+	results := make([]*contextResult, len(contexts))
+	for i, context := range contexts {
+		result := &contextResult{context: context}
+		results[i] = result
+		result.err = createServiceInContext(p, result, prepare, retryPolicy, out)
+		if result.err != nil {
+			result.status = "Failed"
+		}
+	}
+
+	var pending []*contextResult
+	for _, result := range results {
+		if result.err == nil {
+			pending = append(pending, result)
+		}
+	}
+	if waitFlags.Wait && len(pending) > 0 {
+		fmt.Fprintf(out, "Waiting for the service to become ready in %d contexts.\n", len(pending))
+		var wg sync.WaitGroup
+		for _, result := range pending {
+			wg.Add(1)
+			go func(result *contextResult) {
+				defer wg.Done()
+				waitForServiceInContext(result, waitFlags)
+			}(result)
+		}
+		wg.Wait()
+	}
+	fmt.Fprintln(out, "")
+
+	failed := 0
+	tw := printers.NewTabWriter(out)
+	fmt.Fprintln(tw, "CONTEXT\tNAMESPACE\tSTATUS\tDURATION\tURL")
+	for _, result := range results {
+		if result.err != nil {
+			failed++
+		}
+		duration := printers.TimestampPlaceholder
+		if result.duration > 0 && !printers.OmitTimestamps() {
+			duration = result.duration.Round(time.Millisecond).String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", result.context, stringOrDash(result.namespace), result.status, duration, stringOrDash(result.url))
+	}
+	err := tw.Flush()
+	if err != nil {
+		return err
+	}
+	if failed == 0 {
+		return nil
+	}
+	fmt.Fprintln(out, "")
+	for _, result := range results {
+		if result.err != nil {
+			fmt.Fprintf(out, "Context '%s': %v\n", result.context, result.err)
+		}
+	}
+	return fmt.Errorf("service failed in %d of %d contexts", failed, len(contexts))
+}
+
+func createServiceInContext(p *commands.KnParams, result *contextResult, prepare servicePreparer, retryPolicy clientservingv1.RetryPolicy, out io.Writer) error {
+	contextParams, err := newParamsForContext(p, result.context)
+	if err != nil {
+		return err
+	}
+	service, client, serviceExists, err := prepare(contextParams, out)
+	if err != nil {
+		return err
+	}
+	result.namespace = service.Namespace
+	result.client = client
+	result.service = service
+	// Waiting is done for all contexts in parallel afterwards
+	operation := createOrReplaceOperation(serviceExists, retryPolicy)
+	_, err = operation.mutate(client, service)
+	if err != nil {
+		return err
+	}
+	result.status = strings.ToUpper(operation.done[:1]) + operation.done[1:]
+	i18n.Fprintf(out, "Service '%s' %s in namespace '%s' of context '%s'.\n", service.Name, i18n.T(operation.done), service.Namespace, result.context)
+	return nil
+}
+
+func waitForServiceInContext(result *contextResult, waitFlags commands.WaitFlags) {
+	name := result.service.Name
+	err, duration := result.client.WaitForService(name, time.Duration(waitFlags.TimeoutInSeconds)*time.Second, wait.NoopMessageCallback())
+	result.duration = duration
+	if err == nil && waitFlags.WaitForRoutePropagation {
+		err = waitForRoutePropagation(result.client, name, waitFlags.RoutePropagationStatus, ioutil.Discard)
+	}
+	if err != nil {
+		result.status = "Failed"
+		result.err = err
+		return
+	}
+	result.status = "Ready"
+	service, err := result.client.GetService(name)
+	if err == nil && service.Status.URL != nil {
+		result.url = service.Status.URL.String()
+	}
+}
+
+func stringOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,114 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+	"knative.dev/client/pkg/util/mock"
+	"knative.dev/client/pkg/wait"
+)
+
+// replaceContexts lets newParamsForContext return parameters with the given
+// clients, using the context name as namespace. Unknown contexts are an error.
+func replaceContexts(t *testing.T, clients map[string]clientservingv1.KnServingClient) {
+	oldNewParamsForContext := newParamsForContext
+	newParamsForContext = func(p *commands.KnParams, context string) (*commands.KnParams, error) {
+		client, ok := clients[context]
+		if !ok {
+			return nil, fmt.Errorf("context '%s' not found in the kubeconfig", context)
+		}
+		clientConfig, err := clientcmd.NewClientConfigFromBytes([]byte(fmt.Sprintf(`kind: Config
+version: v1
+clusters:
+- name: c
+  cluster:
+    server: %s.example.com
+contexts:
+- name: %s
+  context:
+    cluster: c
+    namespace: ns-%s
+current-context: %s
+`, context, context, context, context)))
+		assert.NilError(t, err)
+		return &commands.KnParams{
+			ClientConfig: clientConfig,
+			NewServingClient: func(namespace string) (clientservingv1.KnServingClient, error) {
+				return client, nil
+			},
+		}, nil
+	}
+	t.Cleanup(func() { newParamsForContext = oldNewParamsForContext })
+}
+
+func TestServiceCreateInContexts(t *testing.T) {
+	clientA := clientservingv1.NewMockKnServiceClient(t)
+	rA := clientA.Recorder()
+	rA.GetService("foo", nil, apierrors.NewNotFound(servingv1.Resource("service"), "foo"))
+	rA.CreateService(func(t *testing.T, service *servingv1.Service) {
+		assert.Equal(t, service.Namespace, "ns-a")
+		assert.Equal(t, service.Spec.Template.Spec.Containers[0].Image, "gcr.io/foo/bar:baz")
+	}, nil)
+	rA.WaitForService("foo", mock.Any(), wait.NoopMessageCallback(), nil, time.Second)
+	rA.GetService("foo", getServiceWithUrl("foo", "http://foo.ns-a.a.example.com"), nil)
+
+	clientB := clientservingv1.NewMockKnServiceClient(t)
+	rB := clientB.Recorder()
+	rB.GetService("foo", nil, apierrors.NewNotFound(servingv1.Resource("service"), "foo"))
+	rB.CreateService(func(t *testing.T, service *servingv1.Service) {
+		assert.Equal(t, service.Namespace, "ns-b")
+	}, nil)
+	rB.WaitForService("foo", mock.Any(), wait.NoopMessageCallback(), errors.New("RevisionFailed: image pull failed"), time.Second)
+
+	replaceContexts(t, map[string]clientservingv1.KnServingClient{"a": clientA, "b": clientB})
+
+	output, err := executeServiceCommand(nil, "create", "foo", "--image", "gcr.io/foo/bar:baz", "--contexts", "a,b,c")
+	assert.ErrorContains(t, err, "service failed in 2 of 3 contexts")
+	assert.Assert(t, util.ContainsAll(output,
+		"Service 'foo' created in namespace 'ns-a' of context 'a'",
+		"Service 'foo' created in namespace 'ns-b' of context 'b'",
+		"ready in 2 contexts",
+		"CONTEXT", "NAMESPACE", "STATUS", "DURATION", "URL",
+		"Ready", "http://foo.ns-a.a.example.com",
+		"Context 'b': RevisionFailed: image pull failed",
+		"Context 'c': context 'c' not found"))
+	rA.Validate()
+	rB.Validate()
+}
+
+func TestServiceCreateInContextsNoWait(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", nil, apierrors.NewNotFound(servingv1.Resource("service"), "foo"))
+	r.CreateService(mock.Any(), nil)
+	replaceContexts(t, map[string]clientservingv1.KnServingClient{"edge": client})
+
+	output, err := executeServiceCommand(nil, "create", "foo", "--image", "gcr.io/foo/bar:baz", "--contexts", "edge", "--namespace", "prod", "--no-wait")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Service 'foo' created in namespace 'prod' of context 'edge'", "Created"))
+	r.Validate()
+}
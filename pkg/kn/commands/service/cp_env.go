@@ -0,0 +1,294 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/i18n"
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/util"
+)
+
+// Settings which 'service cp-env' copies, selected with --only
+const (
+	copyEnv       = "env"
+	copyVolumes   = "volumes"
+	copyResources = "resources"
+)
+
+var copyKinds = []string{copyEnv, copyVolumes, copyResources}
+
+var cpEnvExample = `
+  # Copy env vars, volumes and resources of service 'api' in namespace 'staging' to service 'api' in namespace 'prod'
+  kn service cp-env api api --source-namespace staging -n prod
+
+  # Copy only the env vars and resources from service 'api' of the 'staging' cluster
+  kn service cp-env api api --source-context staging --only env,resources`
+
+type cpEnvFlags struct {
+	only            []string
+	sourceNamespace string
+	sourceContext   string
+}
+
+// NewServiceCpEnvCommand returns a new command for copying the configuration of one service to another
+func NewServiceCpEnvCommand(p *commands.KnParams) *cobra.Command {
+	var flags cpEnvFlags
+	var waitFlags commands.WaitFlags
+
+	command := &cobra.Command{
+		Use:   "cp-env SRC DST",
+		Short: "Copy env vars, volumes and resources from one service to another",
+		Long: "Copy the env vars, volumes and resource requests and limits from the containers of service SRC to the containers " +
+			"of service DST, e.g. for promoting a configuration from staging to production. The settings of DST are replaced. " +
+			"Containers are matched by name, or by position if both services have a single container. " +
+			"SRC can be in another namespace with --source-namespace, or in another cluster with --source-context.",
+		Example: cpEnvExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("'service cp-env' requires the source and the target service given as two arguments")
+			}
+			kinds, err := parseCopyKinds(flags.only)
+			if err != nil {
+				return err
+			}
+			sourceName, targetName := args[0], args[1]
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			retryPolicy, err := p.RetryPolicy(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := p.NewServingClient(namespace)
+			if err != nil {
+				return err
+			}
+
+			sourceParams := p
+			if flags.sourceContext != "" {
+				sourceParams, err = newParamsForContext(p, flags.sourceContext)
+				if err != nil {
+					return err
+				}
+			}
+			sourceNamespace := flags.sourceNamespace
+			if sourceNamespace == "" {
+				sourceNamespace = namespace
+				if flags.sourceContext != "" {
+					sourceNamespace, err = sourceParams.CurrentNamespace()
+					if err != nil {
+						return err
+					}
+				}
+			}
+			if sourceName == targetName && sourceNamespace == namespace && flags.sourceContext == "" {
+				return fmt.Errorf("source and target are the same service '%s' in namespace '%s'", sourceName, namespace)
+			}
+			sourceClient, err := sourceParams.NewServingClient(sourceNamespace)
+			if err != nil {
+				return err
+			}
+			source, err := sourceClient.GetService(sourceName)
+			if err != nil {
+				return err
+			}
+
+			var latestRevisionBeforeUpdate string
+			err = client.UpdateServiceWithRetry(targetName, func(service *servingv1.Service) (*servingv1.Service, error) {
+				latestRevisionBeforeUpdate = service.Status.LatestReadyRevisionName
+				err := copyConfiguration(&source.Spec.Template, &service.Spec.Template, kinds)
+				if err != nil {
+					return nil, err
+				}
+				return service, renameRevisionTemplate(service)
+			}, retryPolicy)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Copied %s of service '%s' in namespace '%s' to service '%s'.\n",
+				strings.Join(kinds, ", "), sourceName, sourceNamespace, targetName)
+			if sourceNamespace != namespace || flags.sourceContext != "" {
+				printReferencedObjects(&source.Spec.Template, kinds, namespace, out)
+			}
+			if !waitFlags.Wait {
+				i18n.Fprintf(out, "Service '%s' updated in namespace '%s'.\n", targetName, namespace)
+				return nil
+			}
+			i18n.Fprintf(out, "Updating Service '%s' in namespace '%s':\n", targetName, namespace)
+			fmt.Fprintln(out, "")
+			err = waitForService(client, targetName, out, waitFlags)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(out, "")
+			return showUrl(client, targetName, latestRevisionBeforeUpdate, "updated", out)
+		},
+	}
+	commands.AddNamespaceFlags(command.Flags(), false)
+	command.Flags().StringSliceVar(&flags.only, "only", copyKinds,
+		"Settings to copy as comma separated list of "+strings.Join(copyKinds, ", ")+".")
+	command.Flags().StringVar(&flags.sourceNamespace, "source-namespace", "",
+		"Namespace of the source service. Defaults to the namespace of the target service, "+
+			"or to the namespace of --source-context.")
+	command.Flags().StringVar(&flags.sourceContext, "source-context", "",
+		"Kubeconfig context of the cluster of the source service. Defaults to the current context.")
+	waitFlags.AddConditionWaitFlags(command, commands.WaitDefaultTimeout, "cp-env", "service", "ready")
+	return command
+}
+
+// parseCopyKinds checks the settings given with --only and returns them in their canonical order
+func parseCopyKinds(only []string) ([]string, error) {
+	selected := map[string]bool{}
+	for _, kind := range only {
+		kind = strings.ToLower(strings.TrimSpace(kind))
+		if !util.SliceContainsIgnoreCase(copyKinds, kind) {
+			return nil, fmt.Errorf("invalid value '%s' for --only, must be one of %s", kind, strings.Join(copyKinds, ", "))
+		}
+		selected[kind] = true
+	}
+	var kinds []string
+	for _, kind := range copyKinds {
+		if selected[kind] {
+			kinds = append(kinds, kind)
+		}
+	}
+	if len(kinds) == 0 {
+		return nil, errors.New("--only requires at least one of " + strings.Join(copyKinds, ", "))
+	}
+	return kinds, nil
+}
+
+// copyConfiguration replaces the selected settings of the target template with the ones of the source
+func copyConfiguration(source *servingv1.RevisionTemplateSpec, target *servingv1.RevisionTemplateSpec, kinds []string) error {
+	sourceContainers := source.Spec.Containers
+	targetContainers := target.Spec.Containers
+	for i := range targetContainers {
+		targetContainer := &targetContainers[i]
+		sourceContainer := matchingContainer(sourceContainers, targetContainer.Name, len(targetContainers) == 1)
+		if sourceContainer == nil {
+			return fmt.Errorf("no container of the source service matches container '%s' of the target service", targetContainer.Name)
+		}
+		for _, kind := range kinds {
+			switch kind {
+			case copyEnv:
+				targetContainer.Env = sourceContainer.Env
+				targetContainer.EnvFrom = sourceContainer.EnvFrom
+			case copyVolumes:
+				targetContainer.VolumeMounts = sourceContainer.VolumeMounts
+			case copyResources:
+				targetContainer.Resources = sourceContainer.Resources
+			}
+		}
+	}
+	if util.SliceContainsIgnoreCase(kinds, copyVolumes) {
+		target.Spec.Volumes = source.Spec.Volumes
+	}
+	return nil
+}
+
+// matchingContainer returns the source container with the given name. If there is none and both
+// sides have a single container, these are matched.
+func matchingContainer(containers []corev1.Container, name string, single bool) *corev1.Container {
+	for i := range containers {
+		if containers[i].Name == name {
+			return &containers[i]
+		}
+	}
+	if single && len(containers) == 1 {
+		return &containers[0]
+	}
+	return nil
+}
+
+// printReferencedObjects reminds that the secrets and config maps used by the copied settings
+// have to exist in the namespace of the target service
+func printReferencedObjects(template *servingv1.RevisionTemplateSpec, kinds []string, namespace string, out io.Writer) {
+	secrets, configMaps := referencedObjects(template, kinds)
+	if len(secrets) == 0 && len(configMaps) == 0 {
+		return
+	}
+	var refs []string
+	for _, name := range secrets {
+		refs = append(refs, fmt.Sprintf("secret '%s'", name))
+	}
+	for _, name := range configMaps {
+		refs = append(refs, fmt.Sprintf("config map '%s'", name))
+	}
+	fmt.Fprintf(out, "The copied settings reference %s, which must exist in namespace '%s' of the target service.\n",
+		strings.Join(refs, ", "), namespace)
+}
+
+// referencedObjects returns the sorted names of the secrets and config maps used by the selected settings
+func referencedObjects(template *servingv1.RevisionTemplateSpec, kinds []string) ([]string, []string) {
+	secrets := map[string]bool{}
+	configMaps := map[string]bool{}
+	if util.SliceContainsIgnoreCase(kinds, copyEnv) {
+		for _, container := range template.Spec.Containers {
+			for _, env := range container.Env {
+				if env.ValueFrom == nil {
+					continue
+				}
+				if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+					secrets[ref.Name] = true
+				}
+				if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+					configMaps[ref.Name] = true
+				}
+			}
+			for _, envFrom := range container.EnvFrom {
+				if envFrom.SecretRef != nil {
+					secrets[envFrom.SecretRef.Name] = true
+				}
+				if envFrom.ConfigMapRef != nil {
+					configMaps[envFrom.ConfigMapRef.Name] = true
+				}
+			}
+		}
+	}
+	if util.SliceContainsIgnoreCase(kinds, copyVolumes) {
+		for _, volume := range template.Spec.Volumes {
+			if volume.Secret != nil {
+				secrets[volume.Secret.SecretName] = true
+			}
+			if volume.ConfigMap != nil {
+				configMaps[volume.ConfigMap.Name] = true
+			}
+		}
+	}
+	return sortedKeys(secrets), sortedKeys(configMaps)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
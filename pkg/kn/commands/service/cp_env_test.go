@@ -0,0 +1,159 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+)
+
+func TestServiceCpEnv(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("staging", cpEnvSourceService("staging"), nil)
+	target := cpEnvTargetService("prod")
+	target.Spec.Template.Name = "prod-abcde-1"
+	target.Generation = 1
+	r.GetService("prod", target, nil)
+	r.UpdateService(func(t *testing.T, service *servingv1.Service) {
+		// A new revision needs a new name
+		assert.Assert(t, strings.HasPrefix(service.Spec.Template.Name, "prod-"))
+		assert.Assert(t, strings.HasSuffix(service.Spec.Template.Name, "-2"))
+		container := service.Spec.Template.Spec.Containers[0]
+		assert.Equal(t, container.Image, "registry/prod:v1")
+		assert.DeepEqual(t, container.Env, []corev1.EnvVar{
+			{Name: "LEVEL", Value: "debug"},
+			{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "api-token"}, Key: "token"}}},
+		})
+		assert.Equal(t, container.Resources.Limits.Memory().String(), "512Mi")
+		// Volumes are not selected
+		assert.Equal(t, len(container.VolumeMounts), 0)
+		assert.Equal(t, len(service.Spec.Template.Spec.Volumes), 0)
+	}, nil)
+
+	output, err := executeServiceCommand(client, "cp-env", "staging", "prod", "--only", "resources,env", "--no-wait")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Copied env, resources of service 'staging' in namespace 'default' to service 'prod'",
+		"Service 'prod' updated in namespace 'default'"))
+	// Source and target are in the same namespace, so the referenced secret exists already
+	assert.Assert(t, util.ContainsNone(output, "must exist"))
+	r.Validate()
+}
+
+func TestServiceCpEnvOtherNamespace(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("api", cpEnvSourceService("api"), nil)
+	r.GetService("api", cpEnvTargetService("api"), nil)
+	r.UpdateService(func(t *testing.T, service *servingv1.Service) {
+		template := service.Spec.Template
+		assert.Equal(t, len(template.Spec.Containers[0].Env), 2)
+		assert.Equal(t, template.Spec.Containers[0].VolumeMounts[0].Name, "config")
+		assert.Equal(t, template.Spec.Volumes[0].ConfigMap.Name, "api-config")
+	}, nil)
+
+	output, err := executeServiceCommand(client, "cp-env", "api", "api", "--source-namespace", "staging", "-n", "prod", "--no-wait")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Copied env, volumes, resources of service 'api' in namespace 'staging'",
+		"reference secret 'api-token', config map 'api-config', which must exist in namespace 'prod'"))
+	r.Validate()
+}
+
+func TestServiceCpEnvOtherContext(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	sourceClient := clientservingv1.NewMockKnServiceClient(t)
+	replaceContexts(t, map[string]clientservingv1.KnServingClient{"staging": sourceClient})
+	sourceClient.Recorder().GetService("api", cpEnvSourceService("api"), nil)
+	r := client.Recorder()
+	r.GetService("api", cpEnvTargetService("api"), nil)
+	r.UpdateService(func(t *testing.T, service *servingv1.Service) {
+		assert.Equal(t, service.Spec.Template.Spec.Containers[0].Env[0].Value, "debug")
+	}, nil)
+
+	output, err := executeServiceCommand(client, "cp-env", "api", "api", "--source-context", "staging", "--only", "env", "--no-wait")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Copied env of service 'api' in namespace 'ns-staging'",
+		"reference secret 'api-token', which must exist in namespace 'default'"))
+	sourceClient.Recorder().Validate()
+	r.Validate()
+}
+
+func TestServiceCpEnvErrors(t *testing.T) {
+	for _, tc := range []struct {
+		args        []string
+		errContents string
+	}{
+		{[]string{"cp-env", "api"}, "requires the source and the target service"},
+		{[]string{"cp-env", "api", "api"}, "source and target are the same service 'api'"},
+		{[]string{"cp-env", "a", "b", "--only", "env,labels"}, "invalid value 'labels' for --only"},
+		{[]string{"cp-env", "a", "b", "--only", ""}, "--only requires at least one of env, volumes, resources"},
+	} {
+		client := clientservingv1.NewMockKnServiceClient(t)
+		_, err := executeServiceCommand(client, tc.args...)
+		assert.ErrorContains(t, err, tc.errContents)
+		client.Recorder().Validate()
+	}
+}
+
+func TestCopyConfigurationContainerMismatch(t *testing.T) {
+	source := cpEnvSourceService("a").Spec.Template
+	target := cpEnvTargetService("b").Spec.Template
+	target.Spec.Containers[0].Name = "user-container"
+	target.Spec.Containers = append(target.Spec.Containers, corev1.Container{Name: "sidecar"})
+	err := copyConfiguration(&source, &target, copyKinds)
+	assert.ErrorContains(t, err, "no container of the source service matches container 'sidecar'")
+}
+
+func cpEnvSourceService(name string) *servingv1.Service {
+	service := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	service.Spec.Template.Spec.Containers = []corev1.Container{{
+		Name:  "user-container",
+		Image: "registry/staging:v2",
+		Env: []corev1.EnvVar{
+			{Name: "LEVEL", Value: "debug"},
+			{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "api-token"}, Key: "token"}}},
+		},
+		VolumeMounts: []corev1.VolumeMount{{Name: "config", MountPath: "/etc/config"}},
+		Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+		},
+	}}
+	service.Spec.Template.Spec.Volumes = []corev1.Volume{{
+		Name: "config",
+		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "api-config"}}},
+	}}
+	return service
+}
+
+func cpEnvTargetService(name string) *servingv1.Service {
+	service := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	service.Spec.Template.Spec.Containers = []corev1.Container{{
+		Image: "registry/prod:v1",
+		Env:   []corev1.EnvVar{{Name: "LEVEL", Value: "info"}},
+	}}
+	return service
+}
@@ -18,8 +18,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"os"
 
+	"knative.dev/client/pkg/i18n"
 	"knative.dev/client/pkg/kn/commands"
 	servinglib "knative.dev/client/pkg/serving"
 
@@ -29,7 +29,6 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/yaml"
 
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
@@ -64,111 +63,327 @@ var create_example = `
   # Create a service with annotation
   kn service create s3 --image knativesamples/helloworld --annotation sidecar.istio.io/inject=false
 
+  # Create a service with a CA certificate read from a file and an environment variable holding the service name
+  kn service create s3 --image knativesamples/helloworld --env CA_CERT=@ca.pem --env SERVICE={{.Service}}
+
   # Create a private service (that is a service with no external endpoint)
   kn service create s1 --image knativesamples/helloworld --cluster-local
 
   # Create a service with 250MB memory, 200m CPU requests and a GPU resource limit
   # [https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/]
   # [https://kubernetes.io/docs/tasks/manage-gpus/scheduling-gpus/]
-  kn service create s4gpu --image knativesamples/hellocuda-go --request memory=250Mi,cpu=200m --limit nvidia.com/gpu=1`
+  kn service create s4gpu --image knativesamples/hellocuda-go --request memory=250Mi,cpu=200m --limit nvidia.com/gpu=1
+
+  # Create a service from the image of the OpenShift image stream tag 'helloworld:latest'
+  kn service create s5 --image istag:helloworld:latest
+
+  # Create a service only if its image is signed with the given cosign key
+  kn service create s6 --image knativesamples/helloworld --verify-signature --key cosign.pub
+
+  # Deploy the Knative function in the current directory, named as in its func.yaml
+  kn service create --from-knative-func
+
+  # Create a service in the clusters of the kubeconfig contexts 'eu' and 'us'
+  kn service create s7 --image knativesamples/helloworld --contexts eu,us
+
+  # Create a service from a manifest rendered by another tool, with the image set on top
+  kustomize build overlays/prod | kn service create -f - --image knativesamples/helloworld:v2
+
+  # Create the production variant of a service from a base manifest, a production overlay and the image on top
+  kn service create --extends base.yaml -f prod.yaml --image knativesamples/helloworld:v2
+
+  # Create a service and capture only its URL, the progress messages go to stderr
+  URL=$(kn service create s8 --image knativesamples/helloworld -o url)
+
+  # Create a preview service which 'kn gc --expired' deletes after two days
+  kn service create pr-1234 --image knativesamples/helloworld --ephemeral --ttl 48h
+
+  # Explain step by step what creating a service does, without creating it
+  kn service create s9 --image knativesamples/helloworld --env TARGET=v1 --explain
+
+  # Replace service 's1' from its manifest after reviewing and confirming the changes
+  kn service create --force -f s1.yaml --confirm
+
+  # Create a service with a name generated by the server, like 'ci-test-x7k2p', and capture the name
+  NAME=$(kn service create --generate-name ci-test- --image knativesamples/helloworld -o jsonpath='{.metadata.name}')`
 
 func NewServiceCreateCommand(p *commands.KnParams) *cobra.Command {
 	var editFlags ConfigurationEditFlags
 	var waitFlags commands.WaitFlags
+	var signature signatureFlags
+	var policy policyFlags
+	var preflight bool
+	var quotaCheck bool
+	var fromFunc bool
+	var kubeContexts []string
+	var output outputFlags
+	var ephemeral ephemeralFlags
+	var explain bool
+	var dryRun dryRunFlags
+	var diff diffFlags
 
 	serviceCreateCommand := &cobra.Command{
 		Use:     "create NAME --image IMAGE",
 		Short:   "Create a service",
 		Example: create_example,
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
-			if len(args) != 1 && editFlags.Filename == "" {
-				return errors.New("'service create' requires the service name given as single argument")
-			}
 			name := ""
-			if len(args) == 1 {
-				name = args[0]
+			if editFlags.GenerateName != "" {
+				if len(args) > 0 || fromFunc {
+					return errors.New("'service create --generate-name' can't be combined with a service name or --from-knative-func")
+				}
+				if editFlags.ForceCreate || len(kubeContexts) > 0 {
+					return errors.New("--generate-name can't be combined with --force or --contexts")
+				}
+				if cmd.Flags().Changed("revision-name") {
+					return errors.New("--revision-name can't be used with --generate-name, the server names the revisions")
+				}
 			}
-			if editFlags.PodSpecFlags.Image == "" && editFlags.Filename == "" {
-				return errors.New("'service create' requires the image name to run provided with the --image option")
+			if fromFunc {
+				if len(args) > 1 || editFlags.fromManifest() {
+					return errors.New("'service create --from-knative-func' accepts only an optional service name and no --filename or --extends")
+				}
+				fn, err := readKnativeFunc(".")
+				if err != nil {
+					return err
+				}
+				funcPlugin, err := lookupFuncPlugin()
+				if err != nil {
+					return err
+				}
+				if funcPlugin != nil {
+					if explain {
+						return errors.New("--explain can't be used when the function is deployed by the kn-func plugin")
+					}
+					return deployWithFuncPlugin(funcPlugin, cmd, ".")
+				}
+				name, err = applyKnativeFunc(fn, cmd, args, &editFlags)
+				if err != nil {
+					return err
+				}
+			} else {
+				if len(args) != 1 && !editFlags.fromManifest() && editFlags.GenerateName == "" {
+					return errors.New("'service create' requires the service name given as single argument" + funcFileHint("."))
+				}
+				if len(args) == 1 {
+					name = args[0]
+				}
+				if editFlags.PodSpecFlags.Image == "" && !editFlags.fromManifest() {
+					return errors.New("'service create' requires the image name to run provided with the --image option" + funcFileHint("."))
+				}
 			}
-
-			namespace, err := p.GetNamespace(cmd)
+			err = signature.validate()
 			if err != nil {
 				return err
 			}
-
-			var service *servingv1.Service
-			if editFlags.Filename == "" {
-				service, err = constructService(cmd, editFlags, name, namespace)
-			} else {
-				service, err = constructServiceFromFile(cmd, editFlags, name, namespace)
+			err = output.validate()
+			if err != nil {
+				return err
 			}
+			err = ephemeral.validate(cmd)
 			if err != nil {
 				return err
 			}
-
-			client, err := p.NewServingClient(namespace)
+			err = dryRun.validate(p)
 			if err != nil {
 				return err
 			}
-			serviceExists, err := serviceExists(client, service.Name)
+
+			retryPolicy, err := p.RetryPolicy(cmd)
 			if err != nil {
 				return err
 			}
 
-			out := cmd.OutOrStdout()
-			if serviceExists {
-				if !editFlags.ForceCreate {
-					return fmt.Errorf(
-						"cannot create service '%s' in namespace '%s' "+
-							"because the service already exists and no --force option was given", service.Name, namespace)
+			out := output.progressOut(cmd)
+			explainer := newExplainer(explain, out)
+			prepare := func(p *commands.KnParams, out io.Writer) (*servingv1.Service, clientservingv1.KnServingClient, bool, error) {
+				return prepareServiceCreation(p, cmd, &editFlags, &signature, &policy, &ephemeral, explainer, &dryRun, name, preflight, quotaCheck, out)
+			}
+			if explain && (len(kubeContexts) > 0 || output.enabled() || dryRun.enabled()) {
+				return errors.New("--explain can't be combined with --contexts, --output or --dry-run")
+			}
+			if dryRun.enabled() && len(kubeContexts) > 0 {
+				return errors.New("--dry-run can't be combined with --contexts")
+			}
+			if diff.enabled() && len(kubeContexts) > 0 {
+				return errors.New("--diff and --confirm can't be combined with --contexts")
+			}
+			if len(kubeContexts) > 0 {
+				if editFlags.Filename == "-" {
+					return errors.New("'--filename -' can't be combined with --contexts, as stdin can be read only once")
 				}
-				err = replaceService(client, service, waitFlags, out)
-			} else {
-				err = createService(client, service, waitFlags, out)
+				if output.enabled() {
+					return errors.New("--output can't be combined with --contexts")
+				}
+				return createServiceInContexts(p, kubeContexts, prepare, retryPolicy, waitFlags, out)
 			}
+
+			service, client, serviceExists, err := prepare(p, out)
 			if err != nil {
 				return err
 			}
+			if explain {
+				explainer.creation(service, serviceExists, waitFlags)
+				return nil
+			}
+			if serviceExists && diff.enabled() {
+				live, err := client.GetService(service.Name)
+				if err != nil {
+					return err
+				}
+				replace, err := diff.preview(cmd, p, live, service, out)
+				if err != nil {
+					return err
+				}
+				if !replace {
+					fmt.Fprintf(out, "Service '%s' not replaced.\n", service.Name)
+					return nil
+				}
+			}
+			if dryRun.enabled() {
+				return dryRun.run(createOrReplaceOperation(serviceExists, retryPolicy), client, service, &output, cmd.OutOrStdout())
+			}
+			err = createOrReplaceOperation(serviceExists, retryPolicy).run(client, service, waitFlags, out)
+			if err != nil {
+				return err
+			}
+			// The name can be generated or come from a file or function
+			p.RecordChanged(service.Name)
+			if output.enabled() {
+				return printService(client, service.Name, &output, cmd.OutOrStdout())
+			}
 			return nil
 		},
 	}
 	commands.AddNamespaceFlags(serviceCreateCommand.Flags(), false)
 	editFlags.AddCreateFlags(serviceCreateCommand)
+	serviceCreateCommand.Flags().StringVar(&editFlags.GenerateName, "generate-name", "",
+		"Let the server generate a unique name for the service from the given prefix, e.g. 'ci-test-', instead of giving "+
+			"the name as argument. The created name is printed and waited for. Revisions are named by the server, too.")
+	serviceCreateCommand.Flags().BoolVar(&preflight, "preflight", false,
+		"Check that all permissions required for creating the service are granted before doing any change.")
+	serviceCreateCommand.Flags().BoolVar(&fromFunc, "from-knative-func", false,
+		"Deploy the Knative function in the current directory (func.yaml). The function is built and deployed by the kn-func "+
+			"plugin if it is installed, otherwise the image already built for the function is deployed.")
+	serviceCreateCommand.Flags().BoolVar(&quotaCheck, "check-quota", false,
+		"Warn before creating the service if its pods don't fit into the resource quotas or limit ranges of the namespace. "+
+			"The estimate includes the queue-proxy sidecar and the pods started for --scale-min.")
+	serviceCreateCommand.Flags().StringSliceVar(&kubeContexts, "contexts", nil,
+		"Create the service in the clusters of the given kubeconfig contexts (comma separated) instead of the current context. "+
+			"The services are created one after the other and then waited for in parallel. "+
+			"Without --namespace, the namespace of each context is used.")
+	serviceCreateCommand.Flags().BoolVar(&explain, "explain", false,
+		"Explain step by step what the command would do, with the intermediate service objects, without changing anything. "+
+			"Images are resolved and the checks are run as without --explain.")
+	dryRun.add(serviceCreateCommand)
+	diff.add(serviceCreateCommand)
+	signature.add(serviceCreateCommand)
+	policy.add(serviceCreateCommand)
+	ephemeral.add(serviceCreateCommand)
+	output.add(serviceCreateCommand, "created")
 	waitFlags.AddConditionWaitFlags(serviceCreateCommand, commands.WaitDefaultTimeout, "create", "service", "ready")
+	waitFlags.AddRoutePropagationFlags(serviceCreateCommand)
+	waitFlags.AddProgressFlags(serviceCreateCommand)
+	waitFlags.AddVerboseErrorsFlag(serviceCreateCommand, p)
 	return serviceCreateCommand
 }
 
-func createService(client clientservingv1.KnServingClient, service *servingv1.Service, waitFlags commands.WaitFlags, out io.Writer) error {
-	err := client.CreateService(service)
+// prepareServiceCreation constructs the service for the namespace of the given
+// parameters and runs the requested checks, explaining each step with a non-nil explainer.
+// It returns the client for creating the service and whether the service already exists,
+// which is an error without --force. With a client-side dry-run, the checks which need the
+// cluster are skipped and no client is returned.
+func prepareServiceCreation(p *commands.KnParams, cmd *cobra.Command, editFlags *ConfigurationEditFlags, signature *signatureFlags, policy *policyFlags,
+	ephemeral *ephemeralFlags, explainer *explainer, dryRun *dryRunFlags, name string, preflight bool, quotaCheck bool, out io.Writer) (*servingv1.Service, clientservingv1.KnServingClient, bool, error) {
+
+	namespace, err := p.GetNamespace(cmd)
 	if err != nil {
-		return err
+		return nil, nil, false, err
+	}
+	if preflight && !dryRun.client() {
+		err = preflightAccessCheck(p, namespace, "'kn service create'", createAccessChecks)
+		if err != nil {
+			return nil, nil, false, err
+		}
 	}
 
-	return waitIfRequested(client, service.Name, waitFlags, "Creating", "created", out)
-}
+	var service *servingv1.Service
+	if !editFlags.fromManifest() {
+		service, err = constructService(cmd, *editFlags, name, namespace)
+	} else {
+		service, err = constructServiceFromFile(cmd, *editFlags, name, namespace)
+	}
+	if err != nil {
+		return nil, nil, false, err
+	}
+	ephemeral.mark(service)
+	explainer.construction(cmd, editFlags, service)
+	images := containerImages(&service.Spec.Template)
+	err = resolveImageStreamTags(p, namespace, &service.Spec.Template)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	err = signature.verifyImages(&service.Spec.Template)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	err = pinImageDigests(&service.Spec.Template, editFlags.PinImageDigest)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	err = pinImagesToPlatform(p, &service.Spec.Template, editFlags.Platform, out)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	explainer.imageResolution(images, &service.Spec.Template)
+	err = explainer.validation(service)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	err = policy.check(p, service, out)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if dryRun.client() {
+		return service, nil, false, nil
+	}
 
-func replaceService(client clientservingv1.KnServingClient, service *servingv1.Service, waitFlags commands.WaitFlags, out io.Writer) error {
-	err := prepareAndUpdateService(client, service)
+	if quotaCheck {
+		printQuotaWarnings(p, namespace, &service.Spec.Template, out)
+	}
+
+	client, err := p.NewServingClient(namespace)
 	if err != nil {
-		return err
+		return nil, nil, false, err
 	}
-	return waitIfRequested(client, service.Name, waitFlags, "Replacing", "replaced", out)
+	if service.Name == "" {
+		// The server generates a name which doesn't exist yet
+		return service, client, false, nil
+	}
+	serviceExists, err := serviceExists(client, service.Name)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if serviceExists && !editFlags.ForceCreate {
+		return nil, nil, false, fmt.Errorf(
+			"cannot create service '%s' in namespace '%s' "+
+				"because the service already exists and no --force option was given", service.Name, namespace)
+	}
+	return service, client, serviceExists, nil
 }
 
 func waitIfRequested(client clientservingv1.KnServingClient, serviceName string, waitFlags commands.WaitFlags, verbDoing string, verbDone string, out io.Writer) error {
 	if !waitFlags.Wait {
-		fmt.Fprintf(out, "Service '%s' %s in namespace '%s'.\n", serviceName, verbDone, client.Namespace())
+		i18n.Fprintf(out, "Service '%s' %s in namespace '%s'.\n", serviceName, i18n.T(verbDone), client.Namespace())
 		return nil
 	}
 
-	fmt.Fprintf(out, "%s service '%s' in namespace '%s':\n", verbDoing, serviceName, client.Namespace())
-	return waitForServiceToGetReady(client, serviceName, waitFlags.TimeoutInSeconds, verbDone, out)
+	i18n.Fprintf(out, "%s service '%s' in namespace '%s':\n", i18n.T(verbDoing), serviceName, client.Namespace())
+	return waitForServiceToGetReady(client, serviceName, waitFlags, verbDone, out)
 }
 
-func prepareAndUpdateService(client clientservingv1.KnServingClient, service *servingv1.Service) error {
-	var retries = 0
-	for {
+func prepareAndUpdateService(client clientservingv1.KnServingClient, service *servingv1.Service, retryPolicy clientservingv1.RetryPolicy) error {
+	return clientservingv1.RetryOnConflict(retryPolicy, "service", service.Name, func() error {
 		existingService, err := client.GetService(service.Name)
 		if err != nil {
 			return err
@@ -195,22 +410,13 @@ func prepareAndUpdateService(client clientservingv1.KnServingClient, service *se
 		}
 
 		service.ResourceVersion = existingService.ResourceVersion
-		err = client.UpdateService(service)
-		if err != nil {
-			// Retry to update when a resource version conflict exists
-			if apierrors.IsConflict(err) && retries < MaxUpdateRetries {
-				retries++
-				continue
-			}
-			return err
-		}
-		return nil
-	}
+		return client.UpdateService(service)
+	})
 }
 
-func waitForServiceToGetReady(client clientservingv1.KnServingClient, name string, timeout int, verbDone string, out io.Writer) error {
+func waitForServiceToGetReady(client clientservingv1.KnServingClient, name string, waitFlags commands.WaitFlags, verbDone string, out io.Writer) error {
 	fmt.Fprintln(out, "")
-	err := waitForService(client, name, out, timeout)
+	err := waitForService(client, name, out, waitFlags)
 	if err != nil {
 		return err
 	}
@@ -233,14 +439,15 @@ func serviceExists(client clientservingv1.KnServingClient, name string) (bool, e
 func constructService(cmd *cobra.Command, editFlags ConfigurationEditFlags, name string, namespace string) (*servingv1.Service,
 	error) {
 
-	if name == "" || namespace == "" {
+	if (name == "" && editFlags.GenerateName == "") || namespace == "" {
 		return nil, errors.New("internal: no name or namespace provided when constructing a service")
 	}
 
 	service := servingv1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: namespace,
+			Name:         name,
+			GenerateName: editFlags.GenerateName,
+			Namespace:    namespace,
 		},
 	}
 
@@ -261,21 +468,19 @@ func constructService(cmd *cobra.Command, editFlags ConfigurationEditFlags, name
 	return &service, nil
 }
 
-// constructServiceFromFile creates struct from provided file
+// constructServiceFromFile creates struct from provided --filename and --extends manifests
 func constructServiceFromFile(cmd *cobra.Command, editFlags ConfigurationEditFlags, name, namespace string) (*servingv1.Service, error) {
-	var service servingv1.Service
-	file, err := os.Open(editFlags.Filename)
+	service, err := readServiceManifest(cmd, editFlags)
 	if err != nil {
 		return nil, err
 	}
-	decoder := yaml.NewYAMLOrJSONDecoder(file, 512)
-
-	err = decoder.Decode(&service)
-	if err != nil {
-		return nil, err
-	}
-	if name == "" && service.Name != "" {
-		// keep provided service.Name if name param is empty
+	if editFlags.GenerateName != "" {
+		if service.Name != "" {
+			return nil, fmt.Errorf("--generate-name can't be used with the service name '%s' from file", service.Name)
+		}
+		service.GenerateName = editFlags.GenerateName
+	} else if name == "" && (service.Name != "" || service.GenerateName != "") {
+		// keep provided service.Name or generateName if name param is empty
 	} else if name != "" && service.Name == "" {
 		service.Name = name
 	} else if name != "" && service.Name != "" {
@@ -291,10 +496,10 @@ func constructServiceFromFile(cmd *cobra.Command, editFlags ConfigurationEditFla
 	service.ObjectMeta.Namespace = namespace
 
 	// Apply options provided from cmdline
-	err = editFlags.Apply(&service, nil, cmd)
+	err = editFlags.Apply(service, nil, cmd)
 	if err != nil {
 		return nil, err
 	}
 
-	return &service, nil
+	return service, nil
 }
@@ -15,6 +15,10 @@
 package service
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -28,6 +32,8 @@ import (
 
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
+	"knative.dev/client/pkg/i18n"
+	"knative.dev/client/pkg/kn/commands"
 	servinglib "knative.dev/client/pkg/serving"
 	knclient "knative.dev/client/pkg/serving/v1"
 	"knative.dev/client/pkg/util/mock"
@@ -62,6 +68,40 @@ func TestServiceCreateImageMock(t *testing.T) {
 	r.Validate()
 }
 
+func TestServiceCreateWaitProgressJSONMock(t *testing.T) {
+	client := knclient.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", nil, errors.NewNotFound(servingv1.Resource("service"), "foo"))
+	r.CreateService(mock.Any(), nil)
+	r.WaitForService("foo", mock.Any(), mock.Any(), nil, 1500*time.Millisecond)
+	r.GetService("foo", getServiceWithUrl("foo", "http://foo.example.com"), nil)
+
+	output, err := executeServiceCommand(client, "create", "foo", "--image", "gcr.io/foo/bar:baz", "--wait-progress", "json")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, `{"phase":"ready","kind":"service","name":"foo","condition":"Ready","elapsedSeconds":1.5}`))
+	assert.Assert(t, util.ContainsNone(output, "Ready to serve"))
+	r.Validate()
+}
+
+func TestServiceCreateImageGermanMock(t *testing.T) {
+	assert.NilError(t, i18n.SetLanguage(i18n.German))
+	defer i18n.SetLanguage(i18n.English)
+
+	client := knclient.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", nil, errors.NewNotFound(servingv1.Resource("service"), "foo"))
+	r.CreateService(mock.Any(), nil)
+	r.WaitForService("foo", mock.Any(), mock.Any(), nil, time.Second)
+	r.GetService("foo", getServiceWithUrl("foo", "http://foo.example.com"), nil)
+
+	output, err := executeServiceCommand(client, "create", "foo", "--image", "gcr.io/foo/bar:baz")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Erstelle Service 'foo' im Namespace 'default':", "Bereit.",
+		"wurde auf die neueste Revision", "erstellt", "http://foo.example.com"))
+	assert.Assert(t, util.ContainsNone(output, "Creating", "Ready to serve"))
+	r.Validate()
+}
+
 func TestServiceCreateEnvMock(t *testing.T) {
 	client := knclient.NewMockKnServiceClient(t)
 
@@ -646,6 +686,48 @@ func TestServiceCreateWithAutoScaleServiceAnnotationsError(t *testing.T) {
 	r.Validate()
 }
 
+func TestServiceCreateWithRolloutDuration(t *testing.T) {
+	client := knclient.NewMockKnServiceClient(t)
+
+	r := client.Recorder()
+	r.GetService("foo", nil, errors.NewNotFound(servingv1.Resource("service"), "foo"))
+
+	service := getService("foo")
+	template := &service.Spec.Template
+
+	service.ObjectMeta.Annotations = map[string]string{
+		servinglib.RolloutDurationAnnotationKey: "380s",
+	}
+
+	template.Spec.Containers[0].Image = "gcr.io/foo/bar:baz"
+	template.ObjectMeta.Annotations = map[string]string{
+		servinglib.UserImageAnnotationKey: "gcr.io/foo/bar:baz",
+	}
+
+	r.CreateService(service, nil)
+
+	output, err := executeServiceCommand(client, "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--rollout-duration", "380s",
+		"--no-wait", "--revision-name=")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "created", "foo", "default"))
+
+	r.Validate()
+}
+
+func TestServiceCreateWithInvalidRolloutDuration(t *testing.T) {
+	client := knclient.NewMockKnServiceClient(t)
+
+	r := client.Recorder()
+
+	output, err := executeServiceCommand(client, "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--rollout-duration", "380", "--no-wait")
+	assert.Assert(t, err != nil)
+	assert.Assert(t, util.ContainsAll(output, "invalid duration", "rollout-duration"))
+
+	r.Validate()
+}
+
 func getServiceWithUrl(name string, urlName string) *servingv1.Service {
 	service := servingv1.Service{}
 	service.Name = name
@@ -657,3 +739,147 @@ func setUrl(service *servingv1.Service, urlName string) {
 	url, _ := apis.ParseURL(urlName)
 	service.Status.URL = url
 }
+
+func TestServiceCreateValuesFromFileMock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kn-create-file-values")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	policy := "{\n  \"allow\": true\n}\n"
+	policyFile := filepath.Join(dir, "policy.json")
+	assert.NilError(t, ioutil.WriteFile(policyFile, []byte(policy), 0600))
+
+	client := knclient.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", nil, errors.NewNotFound(servingv1.Resource("service"), "foo"))
+	r.CreateService(func(t *testing.T, service *servingv1.Service) {
+		assert.Equal(t, service.Annotations["example.com/policy"], policy)
+		assert.Equal(t, service.Spec.Template.Annotations["example.com/policy"], policy)
+		assert.DeepEqual(t, service.Spec.Template.Spec.Containers[0].Env, []corev1.EnvVar{
+			{Name: "HANDLE", Value: "@foo"},
+			{Name: "POLICY", Value: policy},
+		})
+	}, nil)
+	r.WaitForService("foo", mock.Any(), wait.NoopMessageCallback(), nil, time.Second)
+	r.GetService("foo", getServiceWithUrl("foo", "http://foo.example.com"), nil)
+
+	output, err := executeServiceCommand(client, "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--env", "POLICY=@"+policyFile, "--env", "HANDLE=@@foo", "--annotation", "example.com/policy=@"+policyFile)
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Creating", "foo", "Ready"))
+	r.Validate()
+}
+
+func TestServiceCreateEnvTemplatesAndBase64Mock(t *testing.T) {
+	client := knclient.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", nil, errors.NewNotFound(servingv1.Resource("service"), "foo"))
+	r.CreateService(func(t *testing.T, service *servingv1.Service) {
+		assert.DeepEqual(t, service.Spec.Template.Spec.Containers[0].Env, []corev1.EnvVar{
+			{Name: "KEY", Value: "s3cr3t\n"},
+			{Name: "ORIGIN", Value: "foo.default"},
+			{Name: "REVISION", Value: "foo-v1"},
+		})
+	}, nil)
+	r.WaitForService("foo", mock.Any(), wait.NoopMessageCallback(), nil, time.Second)
+	r.GetService("foo", getServiceWithUrl("foo", "http://foo.example.com"), nil)
+
+	output, err := executeServiceCommand(client, "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--revision-name", "v{{.Generation}}", "--env", "ORIGIN={{.ServiceName}}.{{.Namespace}}",
+		"--env", "REVISION={{.RevisionName}}", "--env-b64", "KEY=czNjcjN0Cg==")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Creating", "foo", "Ready"))
+	r.Validate()
+}
+
+func TestServiceCreateEnvBase64ErrorsMock(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"--env-b64", "KEY=not base64"}, "Invalid --env-b64 KEY: value is not base64 encoded"},
+		{[]string{"--env-b64", "KEY=czNjcjN0", "--env", "KEY=plain"}, "environment variable KEY is given with both --env and --env-b64"},
+		{[]string{"--env", "ORIGIN={{.Unknown}}"}, "cannot expand template in --env ORIGIN"},
+	} {
+		// The service is constructed before any API call
+		client := knclient.NewMockKnServiceClient(t)
+		r := client.Recorder()
+
+		_, err := executeServiceCommand(client, append([]string{"create", "foo", "--image", "gcr.io/foo/bar:baz"}, tc.args...)...)
+		assert.ErrorContains(t, err, tc.err)
+		r.Validate()
+	}
+}
+
+func TestServiceCreateFromStdinMock(t *testing.T) {
+	client := knclient.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", nil, errors.NewNotFound(servingv1.Resource("service"), "foo"))
+	r.CreateService(func(t *testing.T, service *servingv1.Service) {
+		assert.Equal(t, service.Name, "foo")
+		// --image overrides the image of the manifest
+		assert.Equal(t, service.Spec.Template.Spec.Containers[0].Image, "gcr.io/foo/bar:v2")
+		assert.DeepEqual(t, service.Spec.Template.Spec.Containers[0].Env, []corev1.EnvVar{{Name: "TARGET", Value: "Go Sample v1"}})
+	}, nil)
+
+	output, err := executeServiceCommandWithInput(client, strings.NewReader(serviceYAML),
+		"create", "-f", "-", "--image", "gcr.io/foo/bar:v2", "--no-wait")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Service", "foo", "created"))
+	r.Validate()
+}
+
+func TestServiceCreateFromStdinErrorsMock(t *testing.T) {
+	client := knclient.NewMockKnServiceClient(t)
+
+	_, err := executeServiceCommandWithInput(client, strings.NewReader(serviceYAML),
+		"create", "-f", "-", "--env", "KEY=@-")
+	assert.ErrorContains(t, err, "invalid --env KEY=@-: reading from stdin is not allowed here")
+
+	_, err = executeServiceCommandWithInput(client, strings.NewReader(serviceYAML),
+		"create", "-f", "-", "--contexts", "eu,us")
+	assert.ErrorContains(t, err, "'--filename -' can't be combined with --contexts")
+
+	os.Setenv(commands.NonInteractiveEnvVar, "true")
+	defer os.Unsetenv(commands.NonInteractiveEnvVar)
+	_, err = executeServiceCommandWithInput(client, strings.NewReader(serviceYAML), "create", "-f", "-")
+	assert.ErrorContains(t, err, "cannot read the service from stdin with '--filename -' in non-interactive mode")
+
+	client.Recorder().Validate()
+}
+
+func TestServiceCreateGenerateNameMock(t *testing.T) {
+	client := knclient.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	// No check for an existing service, the name is generated by the server
+	r.CreateService(func(t *testing.T, a interface{}) {
+		service := a.(*servingv1.Service)
+		assert.Equal(t, service.Name, "")
+		assert.Equal(t, service.GenerateName, "ci-test-")
+		// The server names the revision
+		assert.Equal(t, service.Spec.Template.Name, "")
+		service.Name = "ci-test-x7k2p"
+	}, nil)
+	r.WaitForService("ci-test-x7k2p", mock.Any(), wait.NoopMessageCallback(), nil, time.Second)
+	r.GetService("ci-test-x7k2p", getServiceWithUrl("ci-test-x7k2p", "http://ci-test-x7k2p.example.com"), nil)
+
+	output, err := executeServiceCommand(client, "create", "--generate-name", "ci-test-", "--image", "gcr.io/foo/bar:baz")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Creating service 'ci-test-x7k2p'", "http://ci-test-x7k2p.example.com"))
+
+	r.Validate()
+}
+
+func TestServiceCreateGenerateNameInvalidMock(t *testing.T) {
+	client := knclient.NewMockKnServiceClient(t)
+	for _, c := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"foo", "--generate-name", "ci-"}, "can't be combined with a service name"},
+		{[]string{"--generate-name", "ci-", "--force"}, "can't be combined with --force"},
+		{[]string{"--generate-name", "ci-", "--revision-name", "ci-v1"}, "--revision-name can't be used"},
+	} {
+		_, err := executeServiceCommand(client, append([]string{"create", "--image", "gcr.io/foo/bar:baz"}, c.args...)...)
+		assert.ErrorContains(t, err, c.err)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"gotest.tools/assert"
 	api_errors "k8s.io/apimachinery/pkg/api/errors"
@@ -30,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 
 	"knative.dev/client/pkg/kn/commands"
+	servinglib "knative.dev/client/pkg/serving"
 	"knative.dev/client/pkg/util"
 	"knative.dev/client/pkg/wait"
 	network "knative.dev/networking/pkg"
@@ -139,6 +141,23 @@ func TestServiceCreateImage(t *testing.T) {
 	}
 }
 
+func TestServiceCreateWithSidecar(t *testing.T) {
+	action, created, _, err := fakeServiceCreate([]string{
+		"service", "create", "foo", "--image", "gcr.io/foo/bar:baz", "--port", "8080",
+		"--container", "proxy", "--image", "gcr.io/foo/proxy:v1", "--env", "UPSTREAM=localhost:8080", "--no-wait"}, false)
+	assert.NilError(t, err)
+	assert.Assert(t, action.Matches("create", "services"))
+
+	containers := created.Spec.Template.Spec.Containers
+	assert.Equal(t, len(containers), 2)
+	assert.Equal(t, containers[0].Image, "gcr.io/foo/bar:baz")
+	assert.Equal(t, containers[0].Ports[0].ContainerPort, int32(8080))
+	assert.Assert(t, containers[0].Env == nil)
+	assert.Equal(t, containers[1].Name, "proxy")
+	assert.Equal(t, containers[1].Image, "gcr.io/foo/proxy:v1")
+	assert.DeepEqual(t, containers[1].Env, []corev1.EnvVar{{Name: "UPSTREAM", Value: "localhost:8080"}})
+}
+
 func TestServiceCreateWithMultipleImages(t *testing.T) {
 	_, _, _, err := fakeServiceCreate([]string{
 		"service", "create", "foo", "--image", "gcr.io/foo/bar:baz", "--image", "gcr.io/bar/foo:baz", "--no-wait"}, false)
@@ -646,6 +665,77 @@ func TestServiceCreateWithClusterLocal(t *testing.T) {
 	}
 }
 
+func TestServiceCreateWithAsyncIngress(t *testing.T) {
+	action, created, _, err := fakeServiceCreate([]string{
+		"service", "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--async-ingress", "--no-wait"}, false)
+	assert.NilError(t, err)
+	assert.Assert(t, action.Matches("create", "services"))
+	assert.Equal(t, created.Annotations["networking.knative.dev/ingress.class"], "async.ingress.networking.knative.dev")
+}
+
+func TestServiceCreateWithDescription(t *testing.T) {
+	action, created, _, err := fakeServiceCreate([]string{
+		"service", "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--description", "Serves foo", "--no-wait"}, false)
+	assert.NilError(t, err)
+	assert.Assert(t, action.Matches("create", "services"))
+	assert.Equal(t, created.Annotations["client.knative.dev/description"], "Serves foo")
+	// A description doesn't belong to a revision
+	_, found := created.Spec.Template.Annotations["client.knative.dev/description"]
+	assert.Assert(t, !found)
+}
+
+func TestServiceCreateWithOwner(t *testing.T) {
+	action, created, _, err := fakeServiceCreate([]string{
+		"service", "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--owner", "team=payments", "--owner", "contact=alice", "--no-wait"}, false)
+	assert.NilError(t, err)
+	assert.Assert(t, action.Matches("create", "services"))
+	assert.Equal(t, created.Labels["owner.client.knative.dev/team"], "payments")
+	assert.Equal(t, created.Labels["owner.client.knative.dev/contact"], "alice")
+	_, found := created.Spec.Template.Labels["owner.client.knative.dev/team"]
+	assert.Assert(t, !found)
+
+	_, _, _, err = fakeServiceCreate([]string{
+		"service", "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--owner", "team=pay ments", "--no-wait"}, false)
+	assert.ErrorContains(t, err, "invalid owner value")
+
+	_, _, _, err = fakeServiceCreate([]string{
+		"service", "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--owner", "team", "--no-wait"}, false)
+	assert.ErrorContains(t, err, "owner 'team' requires a value")
+}
+
+func TestServiceCreateWithQueueProxyResources(t *testing.T) {
+	action, created, _, err := fakeServiceCreate([]string{
+		"service", "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--queue-proxy-resources", "percentage=15", "--no-wait"}, false)
+	assert.NilError(t, err)
+	assert.Assert(t, action.Matches("create", "services"))
+	assert.Equal(t, created.Spec.Template.Annotations["queue.sidecar.serving.knative.dev/resourcePercentage"], "15")
+
+	_, _, _, err = fakeServiceCreate([]string{
+		"service", "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--queue-proxy-resources", "memory=1Gi", "--no-wait"}, false)
+	assert.ErrorContains(t, err, "invalid queue-proxy resource key 'memory'")
+}
+
+func TestServiceCreateWithRequestTimeout(t *testing.T) {
+	action, created, _, err := fakeServiceCreate([]string{
+		"service", "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--request-timeout", "5", "--no-wait"}, false)
+	assert.NilError(t, err)
+	assert.Assert(t, action.Matches("create", "services"))
+	assert.Equal(t, *created.Spec.Template.Spec.TimeoutSeconds, int64(5))
+
+	_, _, _, err = fakeServiceCreate([]string{
+		"service", "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--request-timeout", "-1", "--no-wait"}, false)
+	assert.ErrorContains(t, err, "invalid request timeout")
+}
+
 var serviceYAML = `
 apiVersion: serving.knative.dev/v1
 kind: Service
@@ -888,3 +978,21 @@ func TestServiceCreateFromYAMLWithOverrideError(t *testing.T) {
 	assert.Assert(t, err != nil)
 	assert.Assert(t, util.ContainsAll(err.Error(), "expected", "0", "<=", "2147483647", "autoscaling.knative.dev/maxScale"))
 }
+
+func TestServiceCreateEphemeral(t *testing.T) {
+	_, created, _, err := fakeServiceCreate([]string{
+		"service", "create", "foo", "--image", "gcr.io/foo/bar:baz", "--ephemeral", "--ttl", "1h", "--no-wait"}, false)
+	assert.NilError(t, err)
+	expiry, ephemeral, err := servinglib.EphemeralExpiry(created)
+	assert.NilError(t, err)
+	assert.Assert(t, ephemeral)
+	assert.Assert(t, expiry.After(time.Now().Add(59*time.Minute)) && expiry.Before(time.Now().Add(61*time.Minute)))
+
+	_, _, _, err = fakeServiceCreate([]string{
+		"service", "create", "foo", "--image", "gcr.io/foo/bar:baz", "--ttl", "1h"}, false)
+	assert.ErrorContains(t, err, "--ttl can only be used with --ephemeral")
+
+	_, _, _, err = fakeServiceCreate([]string{
+		"service", "create", "foo", "--image", "gcr.io/foo/bar:baz", "--ephemeral", "--ttl", "-1h"}, false)
+	assert.ErrorContains(t, err, "positive duration")
+}
@@ -17,11 +17,14 @@ package service
 import (
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
 
+	"knative.dev/client/pkg/i18n"
 	"knative.dev/client/pkg/kn/commands"
 	clientservingv1 "knative.dev/client/pkg/serving/v1"
 )
@@ -29,6 +32,9 @@ import (
 // NewServiceDeleteCommand represent 'service delete' command
 func NewServiceDeleteCommand(p *commands.KnParams) *cobra.Command {
 	var waitFlags commands.WaitFlags
+	var output outputFlags
+	var selector string
+	var yes bool
 
 	serviceDeleteCommand := &cobra.Command{
 		Use:   "delete NAME [NAME ...]",
@@ -41,7 +47,16 @@ func NewServiceDeleteCommand(p *commands.KnParams) *cobra.Command {
   kn service delete svc2 -n ns1
 
   # Delete all services in 'ns1' namespace
-  kn service delete --all -n ns1`,
+  kn service delete --all -n ns1
+
+  # Delete all services labeled 'team=payments' without asking for confirmation
+  kn service delete -l team=payments --yes
+
+  # Delete a service 'svc4' and wait until its revisions and routes are gone too
+  kn service delete svc4 --wait
+
+  # Delete a service 'svc3' and print the names of the deleted objects
+  kn service delete svc3 -o name`,
 
 		RunE: func(cmd *cobra.Command, args []string) error {
 			all, err := cmd.Flags().GetBool("all")
@@ -50,7 +65,7 @@ func NewServiceDeleteCommand(p *commands.KnParams) *cobra.Command {
 			}
 			argsLen := len(args)
 
-			if argsLen < 1 && !all {
+			if argsLen < 1 && !all && selector == "" {
 				return errors.New("'service delete' requires the service name(s)")
 			}
 
@@ -58,6 +73,25 @@ func NewServiceDeleteCommand(p *commands.KnParams) *cobra.Command {
 				return errors.New("'service delete' with --all flag requires no arguments")
 			}
 
+			if argsLen > 0 && selector != "" {
+				return errors.New("'service delete' with --selector flag requires no arguments")
+			}
+
+			if all && selector != "" {
+				return errors.New("'service delete' accepts either --all or --selector, not both")
+			}
+
+			listConfigs, err := selectorListConfigs(selector)
+			if err != nil {
+				return err
+			}
+
+			err = output.validate()
+			if err != nil {
+				return err
+			}
+			out := output.progressOut(cmd)
+
 			namespace, err := p.GetNamespace(cmd)
 			if err != nil {
 				return err
@@ -67,15 +101,27 @@ func NewServiceDeleteCommand(p *commands.KnParams) *cobra.Command {
 				return err
 			}
 
-			if all {
-				args, err = getServiceNames(client)
+			if all || selector != "" {
+				args, err = getServiceNames(client, listConfigs...)
 				if err != nil {
 					return err
 				}
+				// Only the services found are notified as deleted, not the arguments
+				p.RecordChanged()
 				if len(args) == 0 {
-					fmt.Fprintf(cmd.OutOrStdout(), "No services found.\n")
+					fmt.Fprintf(out, "No services found.\n")
 					return nil
 				}
+				if !yes {
+					confirmed, err := confirmDeletion(cmd, p, out, args, namespace)
+					if err != nil {
+						return err
+					}
+					if !confirmed {
+						fmt.Fprintf(out, "No services deleted.\n")
+						return nil
+					}
+				}
 			}
 
 			errs := []string{}
@@ -84,11 +130,12 @@ func NewServiceDeleteCommand(p *commands.KnParams) *cobra.Command {
 				if waitFlags.Wait {
 					timeout = time.Duration(waitFlags.TimeoutInSeconds) * time.Second
 				}
-				err = client.DeleteService(name, timeout)
+				err = deleteService(client, name, timeout, &output, cmd.OutOrStdout())
 				if err != nil {
 					errs = append(errs, err.Error())
 				} else {
-					fmt.Fprintf(cmd.OutOrStdout(), "Service '%s' successfully deleted in namespace '%s'.\n", name, namespace)
+					p.RecordChanged(name)
+					i18n.Fprintf(out, "Service '%s' successfully deleted in namespace '%s'.\n", name, namespace)
 				}
 			}
 			if len(errs) > 0 {
@@ -99,13 +146,63 @@ func NewServiceDeleteCommand(p *commands.KnParams) *cobra.Command {
 	}
 	flags := serviceDeleteCommand.Flags()
 	flags.Bool("all", false, "Delete all services in a namespace.")
+	flags.StringVarP(&selector, "selector", "l", "", "Delete the services with labels matching the selector, like 'key=value' or 'key1=value1,key2=value2'.")
+	flags.BoolVarP(&yes, "yes", "y", false, "Don't ask for confirmation before deleting all services or the services matching --selector.")
 	commands.AddNamespaceFlags(serviceDeleteCommand.Flags(), false)
 	waitFlags.AddConditionWaitFlags(serviceDeleteCommand, commands.WaitDefaultTimeout, "delete", "service", "deleted")
+	output.add(serviceDeleteCommand, "deleted")
 	return serviceDeleteCommand
 }
 
-func getServiceNames(client clientservingv1.KnServingClient) ([]string, error) {
-	serviceList, err := client.ListServices()
+// deleteService deletes the service and prints it as it was before the
+// deletion if an output format is requested
+func deleteService(client clientservingv1.KnServingClient, name string, timeout time.Duration, output *outputFlags, out io.Writer) error {
+	if !output.enabled() {
+		return client.DeleteService(name, timeout)
+	}
+	service, err := client.GetService(name)
+	if err != nil {
+		return err
+	}
+	err = client.DeleteService(name, timeout)
+	if err != nil {
+		return err
+	}
+	return output.print(service, out)
+}
+
+// confirmDeletion lists the services and asks whether to delete them. It fails
+// in non-interactive mode, as nobody can answer the question.
+func confirmDeletion(cmd *cobra.Command, p *commands.KnParams, out io.Writer, names []string, namespace string) (bool, error) {
+	if p.NonInteractive {
+		return false, fmt.Errorf("deleting %d service(s) requires a confirmation, use --yes in non-interactive mode", len(names))
+	}
+	fmt.Fprintf(out, "Services to delete in namespace '%s':\n", namespace)
+	for _, name := range names {
+		fmt.Fprintf(out, "  %s\n", name)
+	}
+	return confirm(cmd, p, out, fmt.Sprintf("Delete %d service(s)?", len(names)))
+}
+
+// selectorListConfigs converts a label selector like "key1=value1,key2=value2"
+// to the list configs filtering on these labels
+func selectorListConfigs(selector string) ([]clientservingv1.ListConfig, error) {
+	if selector == "" {
+		return nil, nil
+	}
+	labelMap, err := labels.ConvertSelectorToLabelsMap(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector '%s', only 'key=value' pairs separated by commas are supported: %v", selector, err)
+	}
+	var listConfigs []clientservingv1.ListConfig
+	for key, value := range labelMap {
+		listConfigs = append(listConfigs, clientservingv1.WithLabel(key, value))
+	}
+	return listConfigs, nil
+}
+
+func getServiceNames(client clientservingv1.KnServingClient, listConfigs ...clientservingv1.ListConfig) ([]string, error) {
+	serviceList, err := client.ListServices(listConfigs...)
 	if err != nil {
 		return []string{}, err
 	}
@@ -15,11 +15,19 @@
 package service
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/spf13/cobra"
 	"gotest.tools/assert"
 
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/kn/config"
 	clientservingv1 "knative.dev/client/pkg/serving/v1"
 	"knative.dev/client/pkg/util"
 	"knative.dev/client/pkg/util/mock"
@@ -97,13 +105,134 @@ func TestServiceDeleteAllMock(t *testing.T) {
 	serviceList := &servingv1.ServiceList{Items: []servingv1.Service{*service1, *service2, *service3}}
 	r.ListServices(mock.Any(), serviceList, nil)
 
-	output, err := executeServiceCommand(client, "delete", "--all")
+	output, err := executeServiceCommand(client, "delete", "--all", "--yes")
 	assert.NilError(t, err)
 	assert.Assert(t, util.ContainsAll(output, "deleted", "foo", "bar", "baz", "default"))
+	assert.Assert(t, util.ContainsNone(output, "[y/N]"))
+
+	r.Validate()
+}
+
+func TestServiceDeleteAllNotification(t *testing.T) {
+	var notifications []commands.OperationNotification
+	var subjects []string
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notification commands.OperationNotification
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&notification))
+		notifications = append(notifications, notification)
+		subjects = append(subjects, r.Header.Get("Ce-Subject"))
+	}))
+	defer sink.Close()
+	oldConfig := config.GlobalConfig
+	config.GlobalConfig = config.TestConfig{TestNotificationSink: sink.URL}
+	defer func() { config.GlobalConfig = oldConfig }()
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	service1 := createMockServiceWithParams("foo", "default", "http://foo.default.example.com", "foo-xyz")
+	service2 := createMockServiceWithParams("bar", "default", "http://bar.default.example.com", "bar-xyz")
+	r.ListServices(mock.Any(), &servingv1.ServiceList{Items: []servingv1.Service{*service1, *service2}}, nil)
+	r.DeleteService("foo", mock.Any(), nil)
+	r.DeleteService("bar", mock.Any(), nil)
+	r.ListServices(mock.Any(), &servingv1.ServiceList{}, nil)
+
+	execute := func(args ...string) {
+		knParams := &commands.KnParams{ClientConfig: blankConfig, Output: &bytes.Buffer{}}
+		knParams.NewServingClient = func(namespace string) (clientservingv1.KnServingClient, error) {
+			return client, nil
+		}
+		cmd := NewServiceCommand(knParams)
+		cmd.SetArgs(args)
+		cmd.SetOutput(knParams.Output)
+		// Like the root command
+		cmd.PersistentPostRun = func(cmd *cobra.Command, args []string) {
+			knParams.NotifyOperation(cmd, args)
+		}
+		assert.NilError(t, cmd.Execute())
+	}
+	execute("delete", "--all", "--yes")
+	assert.Equal(t, len(notifications), 1)
+	assert.DeepEqual(t, notifications[0].Names, []string{"foo", "bar"})
+	assert.Equal(t, subjects[0], "foo,bar")
+
+	// Nothing deleted, nothing notified
+	execute("delete", "--all", "--yes")
+	assert.Equal(t, len(notifications), 1)
+	r.Validate()
+}
+
+func TestServiceDeleteAllConfirmedMock(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+
+	service1 := createMockServiceWithParams("foo", "default", "http://foo.default.example.com", "foo-xyz")
+	service2 := createMockServiceWithParams("bar", "default", "http://bar.default.example.com", "bar-xyz")
+	r.ListServices(mock.Any(), &servingv1.ServiceList{Items: []servingv1.Service{*service1, *service2}}, nil)
+	r.DeleteService("foo", mock.Any(), nil)
+	r.DeleteService("bar", mock.Any(), nil)
+
+	output, err := executeServiceCommandWithInput(client, strings.NewReader("y\n"), "delete", "--all")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Services to delete in namespace 'default'", "  foo\n  bar\n", "Delete 2 service(s)? [y/N]"))
+	assert.Assert(t, util.ContainsAll(output, "'foo' successfully deleted", "'bar' successfully deleted"))
+
+	r.Validate()
+}
+
+func TestServiceDeleteAllDeclinedMock(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+
+	service1 := createMockServiceWithParams("foo", "default", "http://foo.default.example.com", "foo-xyz")
+	serviceList := &servingv1.ServiceList{Items: []servingv1.Service{*service1}}
+	r.ListServices(mock.Any(), serviceList, nil)
+	r.ListServices(mock.Any(), serviceList, nil)
+
+	for _, answer := range []string{"n\n", ""} {
+		output, err := executeServiceCommandWithInput(client, strings.NewReader(answer), "delete", "--all")
+		assert.NilError(t, err)
+		assert.Assert(t, util.ContainsAll(output, "Delete 1 service(s)? [y/N]", "No services deleted."))
+	}
+
+	r.Validate()
+}
+
+func TestServiceDeleteSelectorMock(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+
+	service1 := createMockServiceWithParams("foo", "default", "http://foo.default.example.com", "foo-xyz")
+	r.ListServices(func(t *testing.T, a interface{}) {
+		assert.Equal(t, len(a.([]clientservingv1.ListConfig)), 2)
+	}, &servingv1.ServiceList{Items: []servingv1.Service{*service1}}, nil)
+	r.DeleteService("foo", mock.Any(), nil)
+
+	output, err := executeServiceCommand(client, "delete", "-l", "team=payments,tier=backend", "-y")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "'foo' successfully deleted"))
 
 	r.Validate()
 }
 
+func TestServiceDeleteSelectorErrorsMock(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+
+	_, err := executeServiceCommand(client, "delete", "foo", "-l", "team=payments")
+	assert.Error(t, err, "'service delete' with --selector flag requires no arguments")
+
+	_, err = executeServiceCommand(client, "delete", "--all", "-l", "team=payments")
+	assert.Error(t, err, "'service delete' accepts either --all or --selector, not both")
+
+	_, err = executeServiceCommand(client, "delete", "-l", "team!=payments")
+	assert.ErrorContains(t, err, "invalid selector 'team!=payments'")
+}
+
+func TestServiceDeleteConfirmationNonInteractive(t *testing.T) {
+	p := &commands.KnParams{NonInteractive: true}
+	_, err := confirmDeletion(&cobra.Command{}, p, &bytes.Buffer{}, []string{"foo", "bar"}, "default")
+	assert.Error(t, err, "deleting 2 service(s) requires a confirmation, use --yes in non-interactive mode")
+}
+
 func TestServiceDeleteAllErrorFromArgMock(t *testing.T) {
 	// New mock client
 	client := clientservingv1.NewMockKnServiceClient(t)
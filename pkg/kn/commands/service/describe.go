@@ -24,10 +24,12 @@ import (
 
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	network "knative.dev/networking/pkg"
 	"knative.dev/serving/pkg/apis/serving"
 
 	"knative.dev/client/pkg/kn/commands/revision"
 	"knative.dev/client/pkg/printers"
+	servinglib "knative.dev/client/pkg/serving"
 	clientservingv1 "knative.dev/client/pkg/serving/v1"
 
 	"github.com/spf13/cobra"
@@ -177,6 +179,9 @@ func describe(w io.Writer, service *servingv1.Service, revisions []*revisionDesc
 // Write out main service information. Use colors for major items.
 func writeService(dw printers.PrefixWriter, service *servingv1.Service) {
 	commands.WriteMetadata(dw, &service.ObjectMeta, printDetails)
+	if description := service.Annotations[servinglib.DescriptionAnnotationKey]; description != "" {
+		dw.WriteAttribute("Description", description)
+	}
 	dw.WriteAttribute("URL", extractURL(service))
 	if printDetails {
 		if service.Status.Address != nil {
@@ -184,6 +189,9 @@ func writeService(dw printers.PrefixWriter, service *servingv1.Service) {
 			dw.WriteAttribute("Cluster", url.String())
 		}
 	}
+	if servinglib.IsAsyncIngress(service) {
+		dw.WriteAttribute("Async", "send header 'Prefer: respond-async' for a '202 Accepted' response")
+	}
 	if service.Spec.Template.Spec.ServiceAccountName != "" {
 		dw.WriteAttribute("Service Account", service.Spec.Template.Spec.ServiceAccountName)
 	}
@@ -213,6 +221,9 @@ func writeRevisions(dw printers.PrefixWriter, revisions []*revisionDesc, printDe
 		if ready.Status == corev1.ConditionFalse {
 			section.WriteAttribute("Error", ready.Reason)
 		}
+		if revisionDesc.tag != "" {
+			section.WriteAttribute("Tag Header", fmt.Sprintf("%s: %s", network.TagHeaderName, revisionDesc.tag))
+		}
 		revision.WriteImage(section, revisionDesc.revision)
 		if printDetails {
 			revision.WritePort(section, revisionDesc.revision)
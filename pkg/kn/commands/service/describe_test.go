@@ -190,6 +190,46 @@ func TestServiceDescribeEachNamedOnce(t *testing.T) {
 	r.Validate()
 }
 
+func TestServiceDescribeAsyncIngress(t *testing.T) {
+	client := knclient.NewMockKnServiceClient(t)
+
+	r := client.Recorder()
+	expectedService := createTestService("foo", []string{"rev1"}, goodConditions())
+	expectedService.Annotations["networking.knative.dev/ingress.class"] = "async.ingress.networking.knative.dev"
+
+	r.GetService("foo", &expectedService, nil)
+	rev1 := createTestRevision("rev1", 1, goodConditions())
+	r.GetRevision("rev1", &rev1, nil)
+
+	output, err := executeServiceCommand(client, "describe", "foo")
+	assert.NilError(t, err)
+
+	validateServiceOutput(t, "foo", output)
+	assert.Assert(t, cmp.Regexp("Async:\\s+send header 'Prefer: respond-async'", output))
+
+	r.Validate()
+}
+
+func TestServiceDescribeDescription(t *testing.T) {
+	client := knclient.NewMockKnServiceClient(t)
+
+	r := client.Recorder()
+	expectedService := createTestService("foo", []string{"rev1"}, goodConditions())
+	expectedService.Annotations["client.knative.dev/description"] = "Serves foo"
+
+	r.GetService("foo", &expectedService, nil)
+	rev1 := createTestRevision("rev1", 1, goodConditions())
+	r.GetRevision("rev1", &rev1, nil)
+
+	output, err := executeServiceCommand(client, "describe", "foo")
+	assert.NilError(t, err)
+
+	validateServiceOutput(t, "foo", output)
+	assert.Assert(t, cmp.Regexp("Description:\\s+Serves foo", output))
+
+	r.Validate()
+}
+
 func TestServiceDescribeLatestAndCurrentBothHaveTrafficEntries(t *testing.T) {
 	// New mock client
 	client := knclient.NewMockKnServiceClient(t)
@@ -214,6 +254,7 @@ func TestServiceDescribeLatestAndCurrentBothHaveTrafficEntries(t *testing.T) {
 
 	validateServiceOutput(t, "foo", output)
 	assert.Assert(t, util.ContainsAll(output, "@latest (rev1) #latest", "rev1 (current @latest) #current", "50%"))
+	assert.Assert(t, util.ContainsAll(output, "Tag Header", "Knative-Serving-Tag: latest", "Knative-Serving-Tag: current"))
 
 	// Validate that all recorded API methods have been called
 	r.Validate()
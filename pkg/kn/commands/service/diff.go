@@ -0,0 +1,211 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+)
+
+const (
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorReset = "\x1b[0m"
+)
+
+var diffExample = `
+  # Show how the service 'svc' would change when replaced by the manifest in svc.yaml
+  kn service diff svc -f svc.yaml
+
+  # Check in a pipeline whether the live service still matches its manifest
+  kn service diff -f svc.yaml --exit-code`
+
+// diffFlags holds the flags for previewing the changes made by replacing a service
+type diffFlags struct {
+	diff    bool
+	confirm bool
+}
+
+// add registers the diff flags
+func (f *diffFlags) add(command *cobra.Command) {
+	command.Flags().BoolVar(&f.diff, "diff", false,
+		"Show the changes between the existing service and the service replacing it with --force before replacing it.")
+	command.Flags().BoolVar(&f.confirm, "confirm", false,
+		"Show the changes like --diff and ask for confirmation before replacing an existing service.")
+}
+
+// enabled returns true if the changes should be shown
+func (f *diffFlags) enabled() bool {
+	return f.diff || f.confirm
+}
+
+// preview shows the changes between the live and the desired service and asks for
+// confirmation with --confirm. It returns whether the service should be replaced.
+func (f *diffFlags) preview(cmd *cobra.Command, p *commands.KnParams, live, desired *servingv1.Service, out io.Writer) (bool, error) {
+	if !f.enabled() {
+		return true, nil
+	}
+	diff, err := serviceDiff(live, desired)
+	if err != nil {
+		return false, err
+	}
+	if diff == "" {
+		fmt.Fprintf(out, "No changes to service '%s'.\n", desired.Name)
+		return true, nil
+	}
+	fmt.Fprintf(out, "Changes to service '%s':\n%s\n", desired.Name, colorDiff(diff, useColor(out)))
+	if !f.confirm {
+		return true, nil
+	}
+	if p.NonInteractive {
+		return false, fmt.Errorf("replacing service '%s' requires a confirmation, use --diff instead of --confirm in non-interactive mode", desired.Name)
+	}
+	return confirm(cmd, p, out, fmt.Sprintf("Replace service '%s'?", desired.Name))
+}
+
+// NewServiceDiffCommand returns a new command for comparing a live service with a manifest
+func NewServiceDiffCommand(p *commands.KnParams) *cobra.Command {
+	var filename string
+	var exitCode bool
+
+	command := &cobra.Command{
+		Use:   "diff [NAME] -f FILE",
+		Short: "Show the differences between a service and a manifest",
+		Long: "Show the differences between the service in the cluster and the service in a YAML or JSON manifest, " +
+			"as they would be applied by 'kn service create --force -f FILE'. Read-only fields and the defaults " +
+			"set by Knative Serving are not shown as differences. Nothing is changed.",
+		Example: diffExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return errors.New("'service diff' accepts only an optional service name")
+			}
+			if filename == "" {
+				return errors.New("'service diff' requires the manifest given with --filename")
+			}
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			desired, err := readServiceManifest(cmd, ConfigurationEditFlags{Filename: filename})
+			if err != nil {
+				return err
+			}
+			if len(args) == 1 {
+				if desired.Name != "" && desired.Name != args[0] {
+					return fmt.Errorf("provided service name '%s' doesn't match name from file '%s'", args[0], desired.Name)
+				}
+				desired.Name = args[0]
+			}
+			if desired.Name == "" {
+				return errors.New("no service name provided in command parameter or file")
+			}
+			desired.Namespace = namespace
+
+			client, err := p.NewServingClient(namespace)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			live, err := client.GetService(desired.Name)
+			if apierrors.IsNotFound(err) {
+				fmt.Fprintf(out, "Service '%s' doesn't exist in namespace '%s' and would be created.\n", desired.Name, namespace)
+				return diffExitError(exitCode, desired.Name)
+			}
+			if err != nil {
+				return err
+			}
+			diff, err := serviceDiff(live, desired)
+			if err != nil {
+				return err
+			}
+			if diff == "" {
+				fmt.Fprintf(out, "No changes to service '%s'.\n", desired.Name)
+				return nil
+			}
+			fmt.Fprint(out, colorDiff(diff, useColor(out)))
+			return diffExitError(exitCode, desired.Name)
+		},
+	}
+	commands.AddNamespaceFlags(command.Flags(), false)
+	command.Flags().StringVarP(&filename, "filename", "f", "", "YAML or JSON manifest of the service, or '-' for stdin.")
+	command.MarkFlagFilename("filename")
+	command.Flags().BoolVar(&exitCode, "exit-code", false, "Fail if the service differs from the manifest or doesn't exist.")
+	return command
+}
+
+// diffExitError returns the error for --exit-code when the service differs from the manifest
+func diffExitError(exitCode bool, name string) error {
+	if !exitCode {
+		return nil
+	}
+	return fmt.Errorf("service '%s' differs from the manifest", name)
+}
+
+// serviceDiff returns the changes between the live service and the service which would
+// replace it. The desired service gets the defaults set by Knative Serving first, so that
+// only real changes are shown. It is empty if both are equal.
+func serviceDiff(live, desired *servingv1.Service) (string, error) {
+	liveYAML, err := marshalEditable(editableService(live))
+	if err != nil {
+		return "", err
+	}
+	defaulted := desired.DeepCopy()
+	defaulted.SetDefaults(context.Background())
+	desiredYAML, err := marshalEditable(editableService(defaulted))
+	if err != nil {
+		return "", err
+	}
+	if string(liveYAML) == string(desiredYAML) {
+		return "", nil
+	}
+	return lineDiff(string(liveYAML), string(desiredYAML)), nil
+}
+
+// colorDiff colors removed lines red and added lines green
+func colorDiff(diff string, color bool) string {
+	if !color {
+		return diff
+	}
+	lines := strings.SplitAfter(diff, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "-"):
+			lines[i] = colorRed + strings.TrimSuffix(line, "\n") + colorReset + "\n"
+		case strings.HasPrefix(line, "+"):
+			lines[i] = colorGreen + strings.TrimSuffix(line, "\n") + colorReset + "\n"
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// useColor returns true if the output is a terminal and colors haven't been turned off with NO_COLOR
+func useColor(out io.Writer) bool {
+	file, ok := out.(*os.File)
+	if !ok || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return terminal.IsTerminal(int(file.Fd()))
+}
@@ -0,0 +1,169 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+)
+
+const diffManifest = `apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: foo
+spec:
+  template:
+    spec:
+      containers:
+      - image: gcr.io/foo/bar:v2
+`
+
+func TestServiceDiff(t *testing.T) {
+	file := writeDiffManifest(t, diffManifest)
+	defer os.RemoveAll(filepath.Dir(file))
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", liveService("gcr.io/foo/bar:v1"), nil)
+	r.GetService("foo", liveService("gcr.io/foo/bar:v2"), nil)
+	r.GetService("foo", liveService("gcr.io/foo/bar:v1"), nil)
+
+	output, err := executeServiceCommand(client, "diff", "-f", file)
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "-       - image: gcr.io/foo/bar:v1", "+       - image: gcr.io/foo/bar:v2"))
+	assert.Assert(t, util.ContainsNone(output, "\x1b[", "timeoutSeconds", "latestRevision", "status"))
+
+	output, err = executeServiceCommand(client, "diff", "foo", "-f", file, "--exit-code")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "No changes to service 'foo'.\n")
+
+	_, err = executeServiceCommand(client, "diff", "-f", file, "--exit-code")
+	assert.ErrorContains(t, err, "service 'foo' differs from the manifest")
+	r.Validate()
+}
+
+func TestServiceDiffNotExisting(t *testing.T) {
+	file := writeDiffManifest(t, diffManifest)
+	defer os.RemoveAll(filepath.Dir(file))
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	notFound := apierrors.NewNotFound(schema.GroupResource{Group: "serving.knative.dev", Resource: "service"}, "foo")
+	r.GetService("foo", nil, notFound)
+	r.GetService("foo", nil, notFound)
+
+	output, err := executeServiceCommand(client, "diff", "-f", file)
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Service 'foo' doesn't exist in namespace 'default' and would be created"))
+
+	_, err = executeServiceCommand(client, "diff", "-f", file, "--exit-code")
+	assert.ErrorContains(t, err, "differs from the manifest")
+	r.Validate()
+}
+
+func TestServiceDiffErrors(t *testing.T) {
+	file := writeDiffManifest(t, diffManifest)
+	defer os.RemoveAll(filepath.Dir(file))
+	client := clientservingv1.NewMockKnServiceClient(t)
+
+	_, err := executeServiceCommand(client, "diff", "foo")
+	assert.ErrorContains(t, err, "requires the manifest given with --filename")
+	_, err = executeServiceCommand(client, "diff", "bar", "-f", file)
+	assert.ErrorContains(t, err, "provided service name 'bar' doesn't match name from file 'foo'")
+	_, err = executeServiceCommand(client, "diff", "foo", "bar", "-f", file)
+	assert.ErrorContains(t, err, "accepts only an optional service name")
+}
+
+func TestServiceCreateForceConfirm(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	// Declined
+	r.GetService("foo", liveService("gcr.io/foo/bar:v1"), nil)
+	r.GetService("foo", liveService("gcr.io/foo/bar:v1"), nil)
+	// Confirmed
+	r.GetService("foo", liveService("gcr.io/foo/bar:v1"), nil)
+	r.GetService("foo", liveService("gcr.io/foo/bar:v1"), nil)
+	r.GetService("foo", liveService("gcr.io/foo/bar:v1"), nil)
+	r.UpdateService(func(t *testing.T, a interface{}) {
+		assert.Equal(t, a.(*servingv1.Service).Spec.Template.Spec.Containers[0].Image, "gcr.io/foo/bar:v2")
+	}, nil)
+
+	args := []string{"create", "foo", "--image", "gcr.io/foo/bar:v2", "--force", "--no-wait", "--confirm"}
+	output, err := executeServiceCommandWithInput(client, strings.NewReader("n\n"), args...)
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Changes to service 'foo':", "+       - image: gcr.io/foo/bar:v2",
+		"Replace service 'foo'? [y/N]: ", "Service 'foo' not replaced."))
+
+	output, err = executeServiceCommandWithInput(client, strings.NewReader("y\n"), args...)
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Replace service 'foo'? [y/N]: ", "Service 'foo' replaced"))
+	r.Validate()
+}
+
+func TestServiceCreateForceDiff(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", liveService("gcr.io/foo/bar:v1"), nil)
+	r.GetService("foo", liveService("gcr.io/foo/bar:v1"), nil)
+	r.GetService("foo", liveService("gcr.io/foo/bar:v1"), nil)
+	r.UpdateService(func(t *testing.T, a interface{}) {}, nil)
+
+	output, err := executeServiceCommand(client, "create", "foo", "--image", "gcr.io/foo/bar:v2", "--force", "--no-wait", "--diff")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Changes to service 'foo':", "-       - image: gcr.io/foo/bar:v1", "Service 'foo' replaced"))
+	assert.Assert(t, util.ContainsNone(output, "[y/N]"))
+
+	_, err = executeServiceCommand(client, "create", "foo", "--image", "gcr.io/foo/bar:v2", "--diff", "--contexts", "eu,us")
+	assert.ErrorContains(t, err, "--diff and --confirm can't be combined with --contexts")
+	r.Validate()
+}
+
+func TestColorDiff(t *testing.T) {
+	diff := "  a\n- b\n+ c\n"
+	assert.Equal(t, colorDiff(diff, false), diff)
+	assert.Equal(t, colorDiff(diff, true), "  a\n\x1b[31m- b\x1b[0m\n\x1b[32m+ c\x1b[0m\n")
+}
+
+// liveService returns a service with the given image as returned by the API server
+func liveService(image string) *servingv1.Service {
+	service := &servingv1.Service{}
+	service.Name = "foo"
+	service.Namespace = "default"
+	service.ResourceVersion = "42"
+	service.Spec.Template.Spec.Containers = []corev1.Container{{Image: image}}
+	service.SetDefaults(context.Background())
+	return service
+}
+
+func writeDiffManifest(t *testing.T, content string) string {
+	dir, err := ioutil.TempDir("", "kn-service-diff")
+	assert.NilError(t, err)
+	file := filepath.Join(dir, "service.yaml")
+	assert.NilError(t, ioutil.WriteFile(file, []byte(content), 0644))
+	return file
+}
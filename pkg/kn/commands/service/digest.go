@@ -0,0 +1,45 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/registry"
+)
+
+// pinImageDigests replaces the tags of all images in the template with the digests the
+// registry resolves them to if requested. Images which already have a digest, like images
+// locked to the digest of the previous revision, are left unchanged.
+func pinImageDigests(template *servingv1.RevisionTemplateSpec, pin bool) error {
+	if !pin {
+		return nil
+	}
+	for i := range template.Spec.Containers {
+		container := &template.Spec.Containers[i]
+		if strings.Contains(container.Image, "@") {
+			continue
+		}
+		digest, err := registry.Digest(container.Image)
+		if err != nil {
+			return fmt.Errorf("cannot pin image '%s' to its digest: %v", container.Image, err)
+		}
+		container.Image = imageRepository(container.Image) + "@" + digest
+	}
+	return nil
+}
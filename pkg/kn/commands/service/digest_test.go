@@ -0,0 +1,100 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/registry"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+)
+
+const pinnedDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func mockRegistryDigest(digests map[string]string) func() {
+	oldDigest := registry.Digest
+	registry.Digest = func(image string) (string, error) {
+		digest, ok := digests[image]
+		if !ok {
+			return "", errors.New("404 Not Found")
+		}
+		return digest, nil
+	}
+	return func() { registry.Digest = oldDigest }
+}
+
+func TestServiceCreatePinImageDigest(t *testing.T) {
+	defer mockRegistryDigest(map[string]string{"gcr.io/foo/bar:baz": pinnedDigest, "gcr.io/foo/sidecar": pinnedDigest})()
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", nil, apierrors.NewNotFound(servingv1.Resource("service"), "foo"))
+	r.CreateService(func(t *testing.T, a interface{}) {
+		template := a.(*servingv1.Service).Spec.Template
+		assert.Equal(t, template.Spec.Containers[0].Image, "gcr.io/foo/bar@"+pinnedDigest)
+		assert.Equal(t, template.Spec.Containers[1].Image, "gcr.io/foo/sidecar@"+pinnedDigest)
+		assert.Equal(t, template.Annotations["client.knative.dev/user-image"], "gcr.io/foo/bar:baz")
+	}, nil)
+
+	_, err := executeServiceQuotaCommand(client, nil, "create", "foo", "--image", "gcr.io/foo/bar:baz", "--port", "8080",
+		"--container", "sidecar", "--image", "gcr.io/foo/sidecar", "--pin-image-digest", "--no-wait")
+	assert.NilError(t, err)
+	r.Validate()
+}
+
+func TestServiceUpdatePinImageDigestFromRevision(t *testing.T) {
+	defer mockRegistryDigest(map[string]string{})()
+
+	service := newEmptyService()
+	service.Spec.Template.Spec.Containers = []corev1.Container{{Image: "gcr.io/foo/bar:baz"}}
+	service.Status.LatestCreatedRevisionName = "foo-00001"
+	revision := &servingv1.Revision{ObjectMeta: metav1.ObjectMeta{Name: "foo-00001", Namespace: "default"}}
+	revision.Spec.Containers = []corev1.Container{{Image: "gcr.io/foo/bar:baz"}}
+	revision.Status.DeprecatedImageDigest = "gcr.io/foo/bar@" + pinnedDigest
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", service, nil)
+	r.GetRevision("foo-00001", revision, nil)
+	r.UpdateService(func(t *testing.T, a interface{}) {
+		template := a.(*servingv1.Service).Spec.Template
+		assert.Equal(t, template.Spec.Containers[0].Image, "gcr.io/foo/bar@"+pinnedDigest)
+		assert.Equal(t, template.Annotations["client.knative.dev/user-image"], "gcr.io/foo/bar:baz")
+	}, nil)
+
+	_, err := executeServiceQuotaCommand(client, nil, "update", "foo", "--pin-image-digest", "--no-wait")
+	assert.NilError(t, err)
+	r.Validate()
+}
+
+func TestServiceCreatePinImageDigestErrors(t *testing.T) {
+	defer mockRegistryDigest(map[string]string{})()
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	_, err := executeServiceQuotaCommand(client, nil, "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--pin-image-digest", "--no-lock-to-digest", "--no-wait")
+	assert.ErrorContains(t, err, "--pin-image-digest can't be used with --no-lock-to-digest")
+
+	_, err = executeServiceQuotaCommand(client, nil, "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--pin-image-digest", "--no-wait")
+	assert.ErrorContains(t, err, "cannot pin image 'gcr.io/foo/bar:baz' to its digest: 404 Not Found")
+}
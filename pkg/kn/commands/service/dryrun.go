@@ -0,0 +1,100 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+)
+
+const (
+	dryRunNone   = "none"
+	dryRunClient = "client"
+	dryRunServer = "server"
+)
+
+// dryRunFlags holds the --dry-run flag of the commands changing a service. Instead of
+// changing the service, the service which would be created or updated is printed, as
+// YAML by default.
+type dryRunFlags struct {
+	mode string
+}
+
+func (d *dryRunFlags) add(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&d.mode, "dry-run", dryRunNone,
+		"Print the resulting service instead of changing it. With 'client', the service is only constructed locally, "+
+			"the cluster is contacted only where it can't be avoided, like for reading the service to update. "+
+			"With 'server', the change is sent to the API server with dry-run, which surfaces the errors of validation "+
+			"and admission webhooks, and prints the service as the server would persist it. One of: none|client|server.")
+}
+
+// validate checks the mode and configures the serving client of the parameters for server-side dry-run
+func (d *dryRunFlags) validate(p *commands.KnParams) error {
+	switch d.mode {
+	case dryRunNone, dryRunClient:
+	case dryRunServer:
+		p.DryRunServer = true
+	default:
+		return fmt.Errorf("invalid --dry-run '%s', must be one of: none|client|server", d.mode)
+	}
+	return nil
+}
+
+// enabled returns true if a dry-run was requested
+func (d *dryRunFlags) enabled() bool {
+	return d != nil && d.mode != "" && d.mode != dryRunNone
+}
+
+// client returns true if the service must not be sent to the cluster
+func (d *dryRunFlags) client() bool {
+	return d != nil && d.mode == dryRunClient
+}
+
+// server returns true if the service is sent to the API server with dry-run
+func (d *dryRunFlags) server() bool {
+	return d != nil && d.mode == dryRunServer
+}
+
+// print writes the service which would be created or updated in the format of --output, or as YAML
+func (d *dryRunFlags) print(service *servingv1.Service, output *outputFlags, out io.Writer) error {
+	service = editableService(service)
+	if output.enabled() {
+		return output.print(service, out)
+	}
+	content, err := marshalEditable(service)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(content)
+	return err
+}
+
+// run prints the service which the operation would create or update. With server-side dry-run
+// the change is sent to the API server first, which returns the service as it would persist it.
+func (d *dryRunFlags) run(o serviceOperation, client clientservingv1.KnServingClient, service *servingv1.Service, output *outputFlags, out io.Writer) error {
+	if d.server() {
+		_, err := o.mutate(client, service)
+		if err != nil {
+			return err
+		}
+	}
+	return d.print(service, output, out)
+}
@@ -0,0 +1,124 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	knflags "knative.dev/client/pkg/kn/flags"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+	"knative.dev/client/pkg/util/mock"
+)
+
+// executeDryRunCommand runs the command and checks that the serving client is created
+// for server-side dry-run only if expected
+func executeDryRunCommand(t *testing.T, client clientservingv1.KnServingClient, server bool, args ...string) (string, error) {
+	knParams := &commands.KnParams{}
+	knParams.ClientConfig = blankConfig
+	output := new(bytes.Buffer)
+	knParams.Output = output
+	knParams.NewServingClient = func(namespace string) (clientservingv1.KnServingClient, error) {
+		assert.Equal(t, knParams.DryRunServer, server)
+		return client, nil
+	}
+	cmd := NewServiceCommand(knParams)
+	cmd.SetArgs(args)
+	cmd.SetOutput(output)
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return knflags.ReconcileBoolFlags(cmd.Flags())
+	}
+	err := cmd.Execute()
+	return output.String(), err
+}
+
+func TestServiceCreateDryRunClient(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+
+	output, err := executeDryRunCommand(t, client, false, "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--env", "TARGET=v1", "--check-quota", "--preflight", "--dry-run", "client")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "kind: Service", "name: foo", "image: gcr.io/foo/bar:baz", "name: TARGET"))
+	assert.Assert(t, util.ContainsNone(output, "created", "Creating"))
+	r.Validate()
+}
+
+func TestServiceCreateDryRunServer(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", nil, apierrors.NewNotFound(servingv1.Resource("service"), "foo"))
+	r.CreateService(mock.Any(), nil)
+
+	output, err := executeDryRunCommand(t, client, true, "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--dry-run", "server", "-o", "json")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, `"kind": "Service"`, `"image": "gcr.io/foo/bar:baz"`))
+	assert.Assert(t, util.ContainsNone(output, "created", "Creating"))
+	r.Validate()
+}
+
+func TestServiceUpdateDryRun(t *testing.T) {
+	service := getService("foo")
+	service.Spec.Template.Spec.Containers[0].Image = "gcr.io/foo/bar:baz"
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", service, nil)
+
+	output, err := executeDryRunCommand(t, client, false, "update", "foo", "--env", "TARGET=v2", "--dry-run", "client")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "image: gcr.io/foo/bar:baz", "name: TARGET", "value: v2"))
+	assert.Assert(t, util.ContainsNone(output, "updated"))
+
+	r.GetService("foo", service, nil)
+	r.UpdateService(func(t *testing.T, a interface{}) {
+		env := a.(*servingv1.Service).Spec.Template.Spec.Containers[0].Env
+		assert.DeepEqual(t, env, []corev1.EnvVar{{Name: "TARGET", Value: "v3"}})
+	}, nil)
+	output, err = executeDryRunCommand(t, client, true, "update", "foo", "--env", "TARGET=v3", "--dry-run", "server")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "name: TARGET", "value: v3"))
+	assert.Assert(t, util.ContainsNone(output, "updated"))
+	r.Validate()
+}
+
+func TestServiceApplyDryRunClient(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+
+	output, err := executeDryRunCommand(t, client, false, "apply", "foo", "--image", "gcr.io/foo/bar:baz", "--dry-run", "client")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "kind: Service", "image: gcr.io/foo/bar:baz"))
+	r.Validate()
+}
+
+func TestServiceCreateDryRunInvalid(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+
+	_, err := executeDryRunCommand(t, client, false, "create", "foo", "--image", "gcr.io/foo/bar:baz", "--dry-run", "true")
+	assert.ErrorContains(t, err, "invalid --dry-run 'true', must be one of: none|client|server")
+
+	_, err = executeDryRunCommand(t, client, false, "create", "foo", "--image", "gcr.io/foo/bar:baz", "--dry-run", "client", "--explain")
+	assert.ErrorContains(t, err, "--explain can't be combined with --contexts, --output or --dry-run")
+}
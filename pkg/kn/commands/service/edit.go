@@ -0,0 +1,433 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/yaml"
+
+	"knative.dev/client/pkg/i18n"
+	"knative.dev/client/pkg/kn/commands"
+)
+
+// Header of the file opened in the editor
+const editHeader = `# Please edit the service below. Lines beginning with a '#' will be ignored,
+# and an empty file will abort the edit. Read-only fields have been removed.
+# Changes to spec.template will create a new revision.
+#
+`
+
+// Number of unchanged lines shown around a change in the diff
+const diffContextLines = 2
+
+var editExample = `
+  # Edit service 'svc' in the default editor
+  kn service edit svc
+
+  # Edit service 'svc' with a specific editor and don't wait for it to become ready
+  KUBE_EDITOR="code --wait" kn service edit svc --no-wait`
+
+// runEditor opens the given file in the user's editor, can be replaced in tests
+var runEditor = func(path string) error {
+	editor := strings.Fields(editorCommand())
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// NewServiceEditCommand returns a new command for editing a service in an editor
+func NewServiceEditCommand(p *commands.KnParams) *cobra.Command {
+	var waitFlags commands.WaitFlags
+
+	command := &cobra.Command{
+		Use:   "edit NAME",
+		Short: "Edit a service in an editor",
+		Long: "Edit a service in the editor given by KN_EDITOR, KUBE_EDITOR or EDITOR (default: vi, notepad on Windows). " +
+			"Read-only fields are removed before editing. The edited service is validated, the changes are shown as a diff " +
+			"and then applied. If the service has been changed concurrently in a part that has been edited too, the edit is rejected.",
+		Example: editExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("'service edit' requires the service name given as single argument")
+			}
+			if p.NonInteractive {
+				return errors.New("'service edit' can't open an editor in non-interactive mode, use 'service update' or 'service apply' instead")
+			}
+			name := args[0]
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			retryPolicy, err := p.RetryPolicy(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := p.NewServingClient(namespace)
+			if err != nil {
+				return err
+			}
+			service, err := client.GetService(name)
+			if err != nil {
+				return err
+			}
+
+			original := editableService(service)
+			originalYAML, err := marshalEditable(original)
+			if err != nil {
+				return err
+			}
+			editedYAML, file, err := editInEditor(originalYAML)
+			if err != nil {
+				return err
+			}
+			// Keep the file with the changes in case of errors
+			keepFile := func(err error) error {
+				return fmt.Errorf("%v\nA copy of your changes has been stored to '%s'", err, file)
+			}
+
+			out := cmd.OutOrStdout()
+			if len(editedYAML) == 0 {
+				os.Remove(file)
+				fmt.Fprintln(out, "Edit cancelled, empty file.")
+				return nil
+			}
+			edited, err := parseEditedService(editedYAML, original)
+			if err != nil {
+				return keepFile(err)
+			}
+			if equality.Semantic.DeepEqual(original, edited) {
+				os.Remove(file)
+				fmt.Fprintln(out, "Edit cancelled, no changes made.")
+				return nil
+			}
+
+			// Show the changes in the normalized form
+			editedYAML, err = marshalEditable(edited)
+			if err != nil {
+				return keepFile(err)
+			}
+			fmt.Fprintf(out, "Changes to service '%s':\n%s\n", name, lineDiff(string(originalYAML), string(editedYAML)))
+			if !equality.Semantic.DeepEqual(original.Spec.Template, edited.Spec.Template) {
+				fmt.Fprintf(out, "Warning: The revision template has been changed, which will create a new revision.\n\n")
+			}
+
+			err = client.UpdateServiceWithRetry(name, func(latest *servingv1.Service) (*servingv1.Service, error) {
+				return mergeEditedService(latest, original, edited)
+			}, retryPolicy)
+			if err != nil {
+				return keepFile(err)
+			}
+			os.Remove(file)
+
+			if !waitFlags.Wait {
+				i18n.Fprintf(out, "Service '%s' updated in namespace '%s'.\n", name, namespace)
+				return nil
+			}
+			i18n.Fprintf(out, "Updating Service '%s' in namespace '%s':\n", name, namespace)
+			fmt.Fprintln(out, "")
+			err = waitForService(client, name, out, waitFlags)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(out, "")
+			return showUrl(client, name, service.Status.LatestReadyRevisionName, "updated", out)
+		},
+	}
+	commands.AddNamespaceFlags(command.Flags(), false)
+	waitFlags.AddConditionWaitFlags(command, commands.WaitDefaultTimeout, "update", "service", "ready")
+	waitFlags.AddRoutePropagationFlags(command)
+	waitFlags.AddProgressFlags(command)
+	waitFlags.AddVerboseErrorsFlag(command, p)
+	return command
+}
+
+// editorCommand returns the editor to use, possibly with arguments
+func editorCommand() string {
+	for _, env := range []string{"KN_EDITOR", "KUBE_EDITOR", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(env)); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// editInEditor writes the given content to a temporary file, opens it in the editor
+// and returns the edited content without comment lines, together with the file's path
+func editInEditor(content []byte) ([]byte, string, error) {
+	file, err := ioutil.TempFile("", "kn-service-edit-*.yaml")
+	if err != nil {
+		return nil, "", err
+	}
+	_, err = file.Write(append([]byte(editHeader), content...))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return nil, "", err
+	}
+
+	err = runEditor(file.Name())
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot run editor '%s': %v", editorCommand(), err)
+	}
+	edited, err := ioutil.ReadFile(file.Name())
+	if err != nil {
+		return nil, "", err
+	}
+	return stripComments(edited), file.Name(), nil
+}
+
+// stripComments removes all lines starting with a '#' and returns nil if nothing else is left
+func stripComments(content []byte) []byte {
+	var result []byte
+	for _, line := range bytes.SplitAfter(content, []byte("\n")) {
+		if bytes.HasPrefix(bytes.TrimSpace(line), []byte("#")) {
+			continue
+		}
+		result = append(result, line...)
+	}
+	if len(bytes.TrimSpace(result)) == 0 {
+		return nil
+	}
+	return result
+}
+
+// editableService returns a copy of the given service without the fields which are
+// managed by Kubernetes or Knative serving
+func editableService(service *servingv1.Service) *servingv1.Service {
+	editable := service.DeepCopy()
+	editable.APIVersion = servingv1.SchemeGroupVersion.String()
+	editable.Kind = "Service"
+	editable.UID = ""
+	editable.ResourceVersion = ""
+	editable.Generation = 0
+	editable.CreationTimestamp.Reset()
+	editable.SelfLink = ""
+	editable.ManagedFields = nil
+	editable.Status = servingv1.ServiceStatus{}
+	delete(editable.Annotations, serving.CreatorAnnotation)
+	delete(editable.Annotations, serving.UpdaterAnnotation)
+	if len(editable.Annotations) == 0 {
+		editable.Annotations = nil
+	}
+	return editable
+}
+
+// marshalEditable returns the service as YAML without empty fields, which are
+// only caused by Go's zero values
+func marshalEditable(service *servingv1.Service) ([]byte, error) {
+	content, err := json.Marshal(service)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]interface{}
+	err = json.Unmarshal(content, &object)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(pruneEmpty(object))
+}
+
+// pruneEmpty removes nil values, empty strings and empty maps and lists
+func pruneEmpty(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if pruned := pruneEmpty(child); pruned != nil {
+				v[key] = pruned
+			} else {
+				delete(v, key)
+			}
+		}
+		if len(v) == 0 {
+			return nil
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+		for i, child := range v {
+			// Keep the list elements as their position matters
+			if pruned := pruneEmpty(child); pruned != nil {
+				v[i] = pruned
+			} else {
+				v[i] = map[string]interface{}{}
+			}
+		}
+	case string:
+		if v == "" {
+			return nil
+		}
+	}
+	return value
+}
+
+// parseEditedService decodes the edited service strictly and validates it
+func parseEditedService(content []byte, original *servingv1.Service) (*servingv1.Service, error) {
+	jsonContent, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid YAML: %v", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(jsonContent))
+	decoder.DisallowUnknownFields()
+	edited := &servingv1.Service{}
+	err = decoder.Decode(edited)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service: %v", err)
+	}
+
+	if edited.APIVersion != original.APIVersion || edited.Kind != original.Kind {
+		return nil, fmt.Errorf("apiVersion and kind can't be changed (must be %s %s)", original.APIVersion, original.Kind)
+	}
+	if edited.Name != original.Name {
+		return nil, fmt.Errorf("the name of service '%s' can't be changed", original.Name)
+	}
+	if edited.Namespace != original.Namespace {
+		return nil, fmt.Errorf("the namespace of service '%s' can't be changed", original.Name)
+	}
+	if edited.Status.ObservedGeneration != 0 || len(edited.Status.Conditions) > 0 {
+		return nil, fmt.Errorf("the status of service '%s' can't be changed", original.Name)
+	}
+	if err := edited.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid service: %v", err)
+	}
+	return edited, nil
+}
+
+// mergeEditedService applies the edits to the latest version of the service. The latest
+// version must not have been changed in the edited parts since the service was opened for editing.
+func mergeEditedService(latest, original, edited *servingv1.Service) (*servingv1.Service, error) {
+	current := editableService(latest)
+	for _, part := range []struct {
+		name              string
+		current, original interface{}
+	}{
+		{"spec", current.Spec, original.Spec},
+		{"labels", current.Labels, original.Labels},
+		{"annotations", current.Annotations, original.Annotations},
+	} {
+		if !equality.Semantic.DeepEqual(part.current, part.original) {
+			return nil, fmt.Errorf("the %s of service '%s' have been changed since it was opened in the editor, please edit again", part.name, latest.Name)
+		}
+	}
+
+	latest.Spec = edited.Spec
+	latest.Labels = edited.Labels
+	annotations := edited.Annotations
+	// Keep the annotations managed by Knative serving
+	for _, key := range []string{serving.CreatorAnnotation, serving.UpdaterAnnotation} {
+		if value, ok := latest.Annotations[key]; ok {
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[key] = value
+		}
+	}
+	latest.Annotations = annotations
+	return latest, nil
+}
+
+// lineDiff returns the lines which differ between a and b, prefixed with '-' and '+'
+// and surrounded by some unchanged lines
+func lineDiff(a, b string) string {
+	oldLines := strings.Split(strings.TrimSuffix(a, "\n"), "\n")
+	newLines := strings.Split(strings.TrimSuffix(b, "\n"), "\n")
+
+	// Longest common subsequence of lines, lcs[i][j] is for oldLines[i:] and newLines[j:]
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	type diffLine struct {
+		prefix string
+		text   string
+	}
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			lines = append(lines, diffLine{" ", oldLines[i]})
+			i++
+			j++
+		case i < len(oldLines) && (j == len(newLines) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffLine{"-", oldLines[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{"+", newLines[j]})
+			j++
+		}
+	}
+
+	// Only show unchanged lines close to a change
+	show := make([]bool, len(lines))
+	for k, line := range lines {
+		if line.prefix == " " {
+			continue
+		}
+		for c := k - diffContextLines; c <= k+diffContextLines; c++ {
+			if c >= 0 && c < len(lines) {
+				show[c] = true
+			}
+		}
+	}
+	var result strings.Builder
+	skipped := false
+	for k, line := range lines {
+		if !show[k] {
+			skipped = true
+			continue
+		}
+		if skipped && result.Len() > 0 {
+			result.WriteString("  ...\n")
+		}
+		skipped = false
+		fmt.Fprintf(&result, "%s %s\n", line.prefix, line.text)
+	}
+	return result.String()
+}
@@ -0,0 +1,240 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/ptr"
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+)
+
+func TestServiceEdit(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", getEditService(), nil)
+	r.GetService("foo", getEditService(), nil)
+	r.UpdateService(func(t *testing.T, updated *servingv1.Service) {
+		assert.Equal(t, updated.Spec.Template.Spec.Containers[0].Image, "gcr.io/foo/bar:v2")
+		assert.Equal(t, updated.Annotations[serving.CreatorAnnotation], "alice")
+		assert.Equal(t, updated.ResourceVersion, "42")
+	}, nil)
+
+	var edited string
+	defer replaceEditor(t, func(content string) string {
+		edited = content
+		return strings.Replace(content, "gcr.io/foo/bar:v1", "gcr.io/foo/bar:v2", 1)
+	})()
+
+	output, err := executeServiceCommand(client, "edit", "foo", "--no-wait")
+	assert.NilError(t, err)
+	// Read-only fields are not shown in the editor
+	assert.Assert(t, util.ContainsAll(edited, "# Please edit the service", "name: foo", "gcr.io/foo/bar:v1"))
+	assert.Assert(t, util.ContainsNone(edited, "resourceVersion", "uid:", "generation", serving.CreatorAnnotation, "latestReadyRevisionName"))
+	assert.Assert(t, util.ContainsAll(output, "- ", "image: gcr.io/foo/bar:v1", "+ ", "image: gcr.io/foo/bar:v2",
+		"create a new revision", "Service 'foo' updated"))
+	r.Validate()
+}
+
+func TestServiceEditLabelsOnly(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", getEditService(), nil)
+	r.GetService("foo", getEditService(), nil)
+	// Changes of labels only are patched, so they can't conflict
+	r.PatchService("foo", []byte(`{"metadata":{"labels":{"team":"payments"}}}`), nil)
+
+	defer replaceEditor(t, func(content string) string {
+		return strings.Replace(content, "  name: foo\n", "  labels:\n    team: payments\n  name: foo\n", 1)
+	})()
+
+	output, err := executeServiceCommand(client, "edit", "foo", "--no-wait")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "+ ", "team: payments", "Service 'foo' updated"))
+	assert.Assert(t, util.ContainsNone(output, "new revision"))
+	r.Validate()
+}
+
+func TestServiceEditNoChanges(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", getEditService(), nil)
+
+	defer replaceEditor(t, func(content string) string {
+		return content
+	})()
+
+	output, err := executeServiceCommand(client, "edit", "foo")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "no changes made"))
+	r.Validate()
+}
+
+func TestServiceEditEmptyFile(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", getEditService(), nil)
+
+	defer replaceEditor(t, func(content string) string {
+		return "# nothing left\n"
+	})()
+
+	output, err := executeServiceCommand(client, "edit", "foo")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Edit cancelled"))
+	r.Validate()
+}
+
+func TestServiceEditInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		replace []string
+		err     string
+	}{
+		{"unknown field", []string{"  name: foo\n", "  name: foo\n  nmae: bar\n"}, "unknown field"},
+		{"renamed", []string{"  name: foo\n", "  name: bar\n"}, "can't be changed"},
+		{"no image", []string{"image: gcr.io/foo/bar:v1", "image: \"\""}, "missing field(s)"},
+		{"invalid yaml", []string{"kind: Service", "kind: [Service"}, "invalid YAML"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := clientservingv1.NewMockKnServiceClient(t)
+			r := client.Recorder()
+			r.GetService("foo", getEditService(), nil)
+
+			defer replaceEditor(t, func(content string) string {
+				return strings.Replace(content, tc.replace[0], tc.replace[1], 1)
+			})()
+
+			_, err := executeServiceCommand(client, "edit", "foo")
+			assert.ErrorContains(t, err, tc.err)
+			assert.ErrorContains(t, err, "A copy of your changes has been stored to")
+			r.Validate()
+		})
+	}
+}
+
+func TestServiceEditConcurrentChange(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", getEditService(), nil)
+	changed := getEditService()
+	changed.Spec.Template.Spec.Containers[0].Image = "gcr.io/foo/bar:other"
+	r.GetService("foo", changed, nil)
+
+	defer replaceEditor(t, func(content string) string {
+		return strings.Replace(content, "gcr.io/foo/bar:v1", "gcr.io/foo/bar:v2", 1)
+	})()
+
+	_, err := executeServiceCommand(client, "edit", "foo")
+	assert.ErrorContains(t, err, "spec of service 'foo' have been changed since it was opened in the editor")
+	r.Validate()
+}
+
+func TestLineDiff(t *testing.T) {
+	a := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	b := "a\nb\nc\nD\ne\nf\ng\nh\ni\nj\nk\n"
+	assert.Equal(t, lineDiff(a, b), "  b\n  c\n- d\n+ D\n  e\n  f\n  ...\n  i\n  j\n+ k\n")
+	assert.Equal(t, lineDiff(a, a), "")
+}
+
+func TestEditorCommand(t *testing.T) {
+	for _, env := range []string{"KN_EDITOR", "KUBE_EDITOR", "EDITOR"} {
+		defer setEnvForTest(env, "")()
+	}
+	assert.Assert(t, editorCommand() != "")
+	defer setEnvForTest("EDITOR", "nano")()
+	assert.Equal(t, editorCommand(), "nano")
+	defer setEnvForTest("KN_EDITOR", "code --wait")()
+	assert.Equal(t, editorCommand(), "code --wait")
+}
+
+// replaceEditor replaces the editor with a function that changes the file's content
+func replaceEditor(t *testing.T, edit func(content string) string) func() {
+	oldRunEditor := runEditor
+	var editedFile string
+	runEditor = func(path string) error {
+		editedFile = path
+		content, err := ioutil.ReadFile(path)
+		assert.NilError(t, err)
+		return ioutil.WriteFile(path, []byte(edit(string(content))), 0600)
+	}
+	return func() {
+		runEditor = oldRunEditor
+		// Files are kept when the edit fails
+		os.Remove(editedFile)
+	}
+}
+
+func getEditService() *servingv1.Service {
+	service := &servingv1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "foo",
+			Namespace:       "default",
+			UID:             "1234",
+			ResourceVersion: "42",
+			Generation:      3,
+			Annotations: map[string]string{
+				serving.CreatorAnnotation: "alice",
+				serving.UpdaterAnnotation: "alice",
+			},
+		},
+	}
+	service.Spec.Template.Spec.Containers = []corev1.Container{{Image: "gcr.io/foo/bar:v1"}}
+	service.Spec.Traffic = []servingv1.TrafficTarget{{LatestRevision: ptr.Bool(true), Percent: ptr.Int64(100)}}
+	service.Status.LatestReadyRevisionName = "foo-00003"
+	return service
+}
+
+func setEnvForTest(key, value string) func() {
+	oldValue, isSet := os.LookupEnv(key)
+	os.Setenv(key, value)
+	return func() {
+		if isSet {
+			os.Setenv(key, oldValue)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
+
+func TestServiceEditNonInteractive(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	defer replaceEditor(t, func(content string) string {
+		t.Fatal("editor must not be opened in non-interactive mode")
+		return content
+	})()
+
+	knParams := &commands.KnParams{NonInteractive: true}
+	knParams.NewServingClient = func(namespace string) (clientservingv1.KnServingClient, error) {
+		return client, nil
+	}
+	cmd := NewServiceEditCommand(knParams)
+	cmd.SetArgs([]string{"foo"})
+	cmd.SetOutput(ioutil.Discard)
+	err := cmd.Execute()
+	assert.ErrorContains(t, err, "non-interactive mode")
+	client.Recorder().Validate()
+}
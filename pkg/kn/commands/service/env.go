@@ -0,0 +1,322 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"knative.dev/client/pkg/kn/commands"
+	clientserving "knative.dev/client/pkg/serving"
+)
+
+var envExample = `
+  # Print the env vars of service 'api'
+  kn service env api
+
+  # Export the env vars of service 'api' into the current shell, including the values of secrets
+  eval "$(kn service env api --export --reveal-secrets)"`
+
+type envFlags struct {
+	export        bool
+	revealSecrets bool
+}
+
+// Characters which don't need to be quoted for a POSIX shell
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// NewServiceEnvCommand returns a new command for printing the env vars of a service
+func NewServiceEnvCommand(p *commands.KnParams) *cobra.Command {
+	var flags envFlags
+
+	command := &cobra.Command{
+		Use:   "env NAME",
+		Short: "Print the env vars of a service in shell syntax",
+		Long: "Print the env vars of the container of a service as KEY=value lines which can be sourced by a shell, " +
+			"e.g. for running the image locally with the same environment. Values from config maps are resolved. " +
+			"Values from secrets are only resolved with --reveal-secrets, and only where reading the secret is allowed. " +
+			"Env vars which can't be resolved are printed as comments.",
+		Example: envExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("'service env' requires the service name given as single argument")
+			}
+			name := args[0]
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := p.NewServingClient(namespace)
+			if err != nil {
+				return err
+			}
+			service, err := client.GetService(name)
+			if err != nil {
+				return err
+			}
+			container, err := clientserving.ContainerOfRevisionSpec(&service.Spec.Template.Spec)
+			if err != nil {
+				return err
+			}
+			kubeClient, err := p.NewKubeClient()
+			if err != nil {
+				return err
+			}
+			resolver := &envResolver{
+				kubeClient:    kubeClient,
+				namespace:     namespace,
+				revealSecrets: flags.revealSecrets,
+				configMaps:    map[string]*corev1.ConfigMap{},
+				secrets:       map[string]*corev1.Secret{},
+				errors:        map[string]error{},
+			}
+			return printEnv(cmd.OutOrStdout(), resolver.resolve(container), flags.export)
+		},
+	}
+	commands.AddNamespaceFlags(command.Flags(), false)
+	command.Flags().BoolVar(&flags.export, "export", false, "Prefix every line with 'export'.")
+	command.Flags().BoolVar(&flags.revealSecrets, "reveal-secrets", false, "Print the values of env vars which are taken from secrets.")
+	return command
+}
+
+// A resolved env var. Env vars without a value are printed as comment
+// which tells why the value is missing.
+type envEntry struct {
+	name    string
+	value   string
+	comment string
+}
+
+// envResolver looks up the values of env vars taken from config maps and secrets.
+// Every config map and secret is fetched only once.
+type envResolver struct {
+	kubeClient    kubernetes.Interface
+	namespace     string
+	revealSecrets bool
+
+	configMaps map[string]*corev1.ConfigMap
+	secrets    map[string]*corev1.Secret
+	// Errors of fetching, keyed by "configmap/NAME" or "secret/NAME"
+	errors map[string]error
+}
+
+// resolve returns the env vars of the container in the order the container runtime
+// sets them: the env vars of envFrom first, overridden by the ones of env
+func (r *envResolver) resolve(container *corev1.Container) []envEntry {
+	var entries []envEntry
+	index := map[string]int{}
+	add := func(entry envEntry) {
+		if i, ok := index[entry.name]; ok && entry.name != "" {
+			entries[i] = entry
+			return
+		}
+		if entry.name != "" {
+			index[entry.name] = len(entries)
+		}
+		entries = append(entries, entry)
+	}
+
+	for _, source := range container.EnvFrom {
+		for _, entry := range r.resolveEnvFrom(source) {
+			add(entry)
+		}
+	}
+	for _, env := range container.Env {
+		add(r.resolveEnv(env))
+	}
+	return entries
+}
+
+func (r *envResolver) resolveEnvFrom(source corev1.EnvFromSource) []envEntry {
+	var data map[string]string
+	switch {
+	case source.ConfigMapRef != nil:
+		name := source.ConfigMapRef.Name
+		configMap, err := r.configMap(name)
+		if err != nil {
+			if isOptional(source.ConfigMapRef.Optional) && apierrors.IsNotFound(err) {
+				return nil
+			}
+			return []envEntry{{comment: fmt.Sprintf("env vars of config map '%s' not resolved: %s", name, lookupError(err))}}
+		}
+		data = configMap.Data
+	case source.SecretRef != nil:
+		name := source.SecretRef.Name
+		if !r.revealSecrets {
+			return []envEntry{{comment: fmt.Sprintf("env vars of secret '%s' not revealed", name)}}
+		}
+		secret, err := r.secret(name)
+		if err != nil {
+			if isOptional(source.SecretRef.Optional) && apierrors.IsNotFound(err) {
+				return nil
+			}
+			return []envEntry{{comment: fmt.Sprintf("env vars of secret '%s' not resolved: %s", name, lookupError(err))}}
+		}
+		data = map[string]string{}
+		for key, value := range secret.Data {
+			data[key] = string(value)
+		}
+	default:
+		return nil
+	}
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	entries := make([]envEntry, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, envEntry{name: source.Prefix + key, value: data[key]})
+	}
+	return entries
+}
+
+func (r *envResolver) resolveEnv(env corev1.EnvVar) envEntry {
+	entry := envEntry{name: env.Name}
+	from := env.ValueFrom
+	switch {
+	case from == nil:
+		entry.value = env.Value
+	case from.ConfigMapKeyRef != nil:
+		ref := from.ConfigMapKeyRef
+		configMap, err := r.configMap(ref.Name)
+		if err != nil {
+			entry.comment = fmt.Sprintf("key '%s' of config map '%s' not resolved: %s", ref.Key, ref.Name, lookupError(err))
+			break
+		}
+		value, ok := configMap.Data[ref.Key]
+		if !ok {
+			entry.comment = fmt.Sprintf("key '%s' not found in config map '%s'", ref.Key, ref.Name)
+			break
+		}
+		entry.value = value
+	case from.SecretKeyRef != nil:
+		ref := from.SecretKeyRef
+		if !r.revealSecrets {
+			entry.comment = fmt.Sprintf("key '%s' of secret '%s' not revealed", ref.Key, ref.Name)
+			break
+		}
+		secret, err := r.secret(ref.Name)
+		if err != nil {
+			entry.comment = fmt.Sprintf("key '%s' of secret '%s' not resolved: %s", ref.Key, ref.Name, lookupError(err))
+			break
+		}
+		value, ok := secret.Data[ref.Key]
+		if !ok {
+			entry.comment = fmt.Sprintf("key '%s' not found in secret '%s'", ref.Key, ref.Name)
+			break
+		}
+		entry.value = string(value)
+	case from.FieldRef != nil:
+		entry.comment = fmt.Sprintf("set from field '%s' of the pod at runtime", from.FieldRef.FieldPath)
+	case from.ResourceFieldRef != nil:
+		entry.comment = fmt.Sprintf("set from resource '%s' of the container at runtime", from.ResourceFieldRef.Resource)
+	}
+	return entry
+}
+
+func (r *envResolver) configMap(name string) (*corev1.ConfigMap, error) {
+	key := "configmap/" + name
+	if err, ok := r.errors[key]; ok {
+		return nil, err
+	}
+	if configMap, ok := r.configMaps[name]; ok {
+		return configMap, nil
+	}
+	configMap, err := r.kubeClient.CoreV1().ConfigMaps(r.namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		r.errors[key] = err
+		return nil, err
+	}
+	r.configMaps[name] = configMap
+	return configMap, nil
+}
+
+func (r *envResolver) secret(name string) (*corev1.Secret, error) {
+	key := "secret/" + name
+	if err, ok := r.errors[key]; ok {
+		return nil, err
+	}
+	if secret, ok := r.secrets[name]; ok {
+		return secret, nil
+	}
+	secret, err := r.kubeClient.CoreV1().Secrets(r.namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		r.errors[key] = err
+		return nil, err
+	}
+	r.secrets[name] = secret
+	return secret, nil
+}
+
+// Short description of the API errors which are expected when resolving env vars
+func lookupError(err error) string {
+	switch {
+	case apierrors.IsNotFound(err):
+		return "not found"
+	case apierrors.IsForbidden(err):
+		return "access denied"
+	}
+	return err.Error()
+}
+
+func isOptional(optional *bool) bool {
+	return optional != nil && *optional
+}
+
+// printEnv prints the env vars as lines which a POSIX shell can source
+func printEnv(out io.Writer, entries []envEntry, export bool) error {
+	prefix := ""
+	if export {
+		prefix = "export "
+	}
+	for _, entry := range entries {
+		var err error
+		switch {
+		case entry.comment != "" && entry.name != "":
+			_, err = fmt.Fprintf(out, "# %s: %s\n", entry.name, entry.comment)
+		case entry.comment != "":
+			_, err = fmt.Fprintf(out, "# %s\n", entry.comment)
+		default:
+			_, err = fmt.Fprintf(out, "%s%s=%s\n", prefix, entry.name, shellQuote(entry.value))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shellQuote quotes a value with single quotes if it contains characters
+// which are special for a shell
+func shellQuote(value string) string {
+	if shellSafe.MatchString(value) {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
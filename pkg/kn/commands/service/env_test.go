@@ -0,0 +1,145 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	clienttesting "k8s.io/client-go/testing"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+)
+
+func envService(env []corev1.EnvVar, envFrom []corev1.EnvFromSource) *servingv1.Service {
+	service := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"}}
+	service.Spec.Template.Spec.Containers = []corev1.Container{{Image: "registry/api:v1", Env: env, EnvFrom: envFrom}}
+	return service
+}
+
+func envObjects() []runtime.Object {
+	return []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"},
+			Data:       map[string]string{"level": "debug", "greeting": "it's me"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-token", Namespace: "default"},
+			Data:       map[string][]byte{"token": []byte("s3cr3t")},
+		},
+	}
+}
+
+func TestServiceEnv(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("api", envService([]corev1.EnvVar{
+		{Name: "TARGET", Value: "world wide"},
+		{Name: "LEVEL", ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "settings"}, Key: "level"}}},
+		{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "api-token"}, Key: "token"}}},
+		{Name: "POD", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+	}, nil), nil)
+
+	output, err := executeServiceQuotaCommand(client, envObjects(), "env", "api")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "TARGET='world wide'\n"+
+		"LEVEL=debug\n"+
+		"# TOKEN: key 'token' of secret 'api-token' not revealed\n"+
+		"# POD: set from field 'metadata.name' of the pod at runtime\n")
+	r.Validate()
+}
+
+func TestServiceEnvRevealSecrets(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("api", envService([]corev1.EnvVar{
+		{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "api-token"}, Key: "token"}}},
+		{Name: "MISSING", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "other"}, Key: "token"}}},
+	}, nil), nil)
+
+	output, err := executeServiceQuotaCommand(client, envObjects(), "env", "api", "--reveal-secrets", "--export")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "export TOKEN=s3cr3t\n"+
+		"# MISSING: key 'token' of secret 'other' not resolved: not found\n")
+	r.Validate()
+}
+
+func TestServiceEnvFrom(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("api", envService(
+		[]corev1.EnvVar{{Name: "APP_level", Value: "info"}},
+		[]corev1.EnvFromSource{
+			{Prefix: "APP_", ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "settings"}}},
+			{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "api-token"}}},
+		}), nil)
+
+	output, err := executeServiceQuotaCommand(client, envObjects(), "env", "api")
+	assert.NilError(t, err)
+	// env overrides the env vars of envFrom
+	assert.Equal(t, output, "APP_greeting='it'\\''s me'\n"+
+		"APP_level=info\n"+
+		"# env vars of secret 'api-token' not revealed\n")
+	r.Validate()
+}
+
+func TestServiceEnvForbidden(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("api", envService([]corev1.EnvVar{
+		{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "api-token"}, Key: "token"}}},
+	}, nil), nil)
+
+	knParams := &commands.KnParams{}
+	knParams.ClientConfig = blankConfig
+	output := new(bytes.Buffer)
+	knParams.Output = output
+	knParams.NewServingClient = func(namespace string) (clientservingv1.KnServingClient, error) {
+		return client, nil
+	}
+	kubeClient := commands.NewFakeKubeClient(envObjects()...)
+	kubeClient.PrependReactor("get", "secrets", func(a clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(corev1.Resource("secrets"), "api-token", nil)
+	})
+	knParams.NewKubeClient = func() (kubernetes.Interface, error) {
+		return kubeClient, nil
+	}
+	cmd := NewServiceCommand(knParams)
+	cmd.SetArgs([]string{"env", "api", "--reveal-secrets"})
+	cmd.SetOutput(output)
+	err := cmd.Execute()
+	assert.NilError(t, err)
+	assert.Equal(t, output.String(), "# TOKEN: key 'token' of secret 'api-token' not resolved: access denied\n")
+	r.Validate()
+}
+
+func TestServiceEnvNoName(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	_, err := executeServiceQuotaCommand(client, nil, "env")
+	assert.ErrorContains(t, err, "requires the service name")
+}
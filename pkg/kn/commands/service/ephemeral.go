@@ -0,0 +1,60 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/spf13/cobra"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	servinglib "knative.dev/client/pkg/serving"
+)
+
+// ephemeralFlags holds the options for creating services which expire, e.g. for previews
+type ephemeralFlags struct {
+	enabled bool
+	ttl     time.Duration
+}
+
+// add adds the ephemeral flags to the given command
+func (f *ephemeralFlags) add(command *cobra.Command) {
+	command.Flags().BoolVar(&f.enabled, "ephemeral", false,
+		"Label the service as ephemeral with an expiry time, so that 'kn gc --expired' deletes it after --ttl. "+
+			"Meant for short-lived services like preview environments.")
+	command.Flags().DurationVar(&f.ttl, "ttl", 2*time.Hour, "Time to live of an --ephemeral service, e.g. 30m or 48h.")
+}
+
+// validate checks that the flags are given in a consistent combination
+func (f *ephemeralFlags) validate(cmd *cobra.Command) error {
+	if !f.enabled {
+		if cmd.Flags().Changed("ttl") {
+			return errors.New("--ttl can only be used with --ephemeral")
+		}
+		return nil
+	}
+	if f.ttl <= 0 {
+		return errors.New("--ttl requires a positive duration")
+	}
+	return nil
+}
+
+// mark labels the service with its expiry time if requested
+func (f *ephemeralFlags) mark(service *servingv1.Service) {
+	if f.enabled {
+		servinglib.MarkEphemeral(service, time.Now().Add(f.ttl))
+	}
+}
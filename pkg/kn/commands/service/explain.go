@@ -0,0 +1,184 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+)
+
+// explainer prints the steps of 'kn service create --explain' with the intermediate
+// service objects instead of creating the service. Its methods do nothing on a nil
+// explainer, so that the steps can be explained along the regular code path.
+type explainer struct {
+	out  io.Writer
+	step int
+}
+
+// newExplainer returns an explainer writing to out if explaining was requested, nil otherwise
+func newExplainer(explain bool, out io.Writer) *explainer {
+	if !explain {
+		return nil
+	}
+	return &explainer{out: out}
+}
+
+// explain starts the next step
+func (e *explainer) explain(format string, args ...interface{}) {
+	if e == nil {
+		return
+	}
+	e.step++
+	if e.step > 1 {
+		fmt.Fprintln(e.out)
+	}
+	fmt.Fprintf(e.out, "Step %d: %s\n", e.step, fmt.Sprintf(format, args...))
+}
+
+// detail prints an indented line of the current step
+func (e *explainer) detail(format string, args ...interface{}) {
+	if e == nil {
+		return
+	}
+	fmt.Fprintf(e.out, "  %s\n", fmt.Sprintf(format, args...))
+}
+
+// service prints the service as indented YAML
+func (e *explainer) service(service *servingv1.Service) {
+	if e == nil {
+		return
+	}
+	content, err := marshalEditable(editableService(service))
+	if err != nil {
+		e.detail("(cannot print the service: %v)", err)
+		return
+	}
+	for _, line := range strings.Split(strings.TrimRight(string(content), "\n"), "\n") {
+		fmt.Fprintf(e.out, "    %s\n", line)
+	}
+}
+
+// construction explains from which sources the service has been constructed
+func (e *explainer) construction(cmd *cobra.Command, editFlags *ConfigurationEditFlags, service *servingv1.Service) {
+	if e == nil {
+		return
+	}
+	e.explain("Construct service '%s' in namespace '%s'", service.Name, service.Namespace)
+	for _, manifest := range editFlags.Extends {
+		e.detail("start with the base manifest %s", manifest)
+	}
+	if editFlags.Filename != "" {
+		e.detail("merge the manifest %s", editFlags.Filename)
+	}
+	var options []string
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		for _, name := range editFlags.flags {
+			if flag.Name == name && name != "filename" && name != "extends" {
+				options = append(options, "--"+name)
+			}
+		}
+	})
+	sort.Strings(options)
+	if len(options) > 0 {
+		e.detail("apply the options %s", strings.Join(options, ", "))
+	}
+	e.detail("resulting service:")
+	e.service(service)
+}
+
+// imageResolution explains how the images have been changed by resolving image stream tags,
+// verifying signatures and pinning digests. Nothing is printed if no image has been changed.
+func (e *explainer) imageResolution(before []string, template *servingv1.RevisionTemplateSpec) {
+	if e == nil {
+		return
+	}
+	explained := false
+	for i, container := range template.Spec.Containers {
+		if i >= len(before) || before[i] == container.Image {
+			continue
+		}
+		if !explained {
+			e.explain("Resolve the images")
+			explained = true
+		}
+		e.detail("%s -> %s", before[i], container.Image)
+	}
+}
+
+// validation explains the validation of the service, which is run locally with Knative Serving's
+// defaults. The cluster validates the service again with its own configuration when it is created.
+func (e *explainer) validation(service *servingv1.Service) error {
+	if e == nil {
+		return nil
+	}
+	e.explain("Validate the service")
+	// Validated like by the admission webhook, after setting the defaults
+	defaulted := service.DeepCopy()
+	defaulted.SetDefaults(context.Background())
+	err := defaulted.Validate(context.Background())
+	if err != nil {
+		e.detail("the service is invalid: %v", err)
+		return fmt.Errorf("invalid service: %v", err)
+	}
+	e.detail("the service is valid with Knative Serving's default configuration,")
+	e.detail("the cluster validates it again with its own configuration when it is created")
+	return nil
+}
+
+// creation explains how the service would be created and waited for, and that nothing has been changed
+func (e *explainer) creation(service *servingv1.Service, serviceExists bool, waitFlags commands.WaitFlags) {
+	if e == nil {
+		return
+	}
+	if serviceExists {
+		e.explain("Replace the existing service '%s' (--force), keeping its creator annotation", service.Name)
+	} else {
+		e.explain("Create service '%s' in namespace '%s'", service.Name, service.Namespace)
+	}
+	e.detail("Knative Serving creates a configuration, which creates a new revision from the template,")
+	e.detail("and a route, which sends the traffic to the revisions")
+
+	if !waitFlags.Wait {
+		e.explain("Don't wait for the service to become ready (--no-wait)")
+	} else {
+		e.explain("Wait up to %d seconds for the service to become ready", waitFlags.TimeoutInSeconds)
+		e.detail("the service is ready when its conditions %s, %s and %s are True",
+			servingv1.ServiceConditionConfigurationsReady, servingv1.ServiceConditionRoutesReady, servingv1.ServiceConditionReady)
+		if waitFlags.WaitForRoutePropagation {
+			e.detail("then check that the service's URL serves requests")
+		}
+	}
+
+	fmt.Fprintln(e.out)
+	fmt.Fprintln(e.out, "Nothing has been changed, remove --explain to run these steps.")
+}
+
+// containerImages returns the images of the template's containers
+func containerImages(template *servingv1.RevisionTemplateSpec) []string {
+	images := make([]string, len(template.Spec.Containers))
+	for i, container := range template.Spec.Containers {
+		images[i] = container.Image
+	}
+	return images
+}
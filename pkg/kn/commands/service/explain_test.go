@@ -0,0 +1,82 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+)
+
+func TestServiceCreateExplain(t *testing.T) {
+	defer mockRegistryDigest(map[string]string{"gcr.io/foo/bar:baz": pinnedDigest})()
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", nil, apierrors.NewNotFound(servingv1.Resource("service"), "foo"))
+
+	output, err := executeServiceQuotaCommand(client, nil, "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--env", "TARGET=v1", "--pin-image-digest", "--explain")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output,
+		"Step 1: Construct service 'foo' in namespace 'default'",
+		"apply the options --env, --image, --pin-image-digest",
+		"    kind: Service",
+		"        image: gcr.io/foo/bar:baz",
+		"Step 2: Resolve the images",
+		"gcr.io/foo/bar:baz -> gcr.io/foo/bar@"+pinnedDigest,
+		"Step 3: Validate the service",
+		"Step 4: Create service 'foo' in namespace 'default'",
+		"Step 5: Wait up to 600 seconds for the service to become ready",
+		"ConfigurationsReady, RoutesReady and Ready",
+		"Nothing has been changed"))
+	assert.Assert(t, util.ContainsNone(output, "Service 'foo' created"))
+	r.Validate()
+}
+
+func TestServiceCreateExplainForceNoWait(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", &servingv1.Service{}, nil)
+
+	output, err := executeServiceQuotaCommand(client, nil, "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--force", "--no-wait", "--explain")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output,
+		"Step 1: Construct service",
+		"Step 2: Validate the service",
+		"Step 3: Replace the existing service 'foo' (--force)",
+		"Step 4: Don't wait for the service to become ready (--no-wait)"))
+	assert.Assert(t, util.ContainsNone(output, "Resolve the images"))
+	r.Validate()
+}
+
+func TestServiceCreateExplainInvalid(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+
+	output, err := executeServiceQuotaCommand(client, nil, "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--request-timeout", "100000", "--explain")
+	assert.ErrorContains(t, err, "invalid service")
+	assert.Assert(t, util.ContainsAll(output, "Step 2: Validate the service", "the service is invalid"))
+
+	_, err = executeServiceQuotaCommand(client, nil, "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--explain", "--output", "yaml")
+	assert.ErrorContains(t, err, "--explain can't be combined with --contexts, --output or --dry-run")
+}
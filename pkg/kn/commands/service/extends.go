@@ -0,0 +1,152 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+)
+
+// unnamedContainer temporarily names the single unnamed container of a manifest, so that
+// the containers of the layers can be merged by their name
+const unnamedContainer = "kn-unnamed-container"
+
+// readServiceManifest reads the service from the manifests given with --extends and --filename.
+// The manifests are merged in the order of the --extends options with the --filename manifest on top,
+// the options given on the command line are applied to the merged service afterwards.
+func readServiceManifest(cmd *cobra.Command, editFlags ConfigurationEditFlags) (*servingv1.Service, error) {
+	layers := append([]string{}, editFlags.Extends...)
+	if editFlags.Filename != "" {
+		layers = append(layers, editFlags.Filename)
+	}
+
+	var merged map[string]interface{}
+	for _, layer := range layers {
+		manifest, err := readManifestLayer(cmd, layer, editFlags.Filename)
+		if err != nil {
+			return nil, err
+		}
+		if merged == nil {
+			merged = manifest
+			continue
+		}
+		merged, err = mergeServiceManifests(merged, manifest)
+		if err != nil {
+			return nil, fmt.Errorf("cannot merge '%s' into the service: %v", layer, err)
+		}
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	var service servingv1.Service
+	err = json.Unmarshal(data, &service)
+	if err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// readManifestLayer reads a YAML or JSON manifest, stdin is read for the --filename '-'
+func readManifestLayer(cmd *cobra.Command, path string, filename string) (map[string]interface{}, error) {
+	var in io.Reader
+	if path == "-" {
+		if path != filename {
+			return nil, errors.New("--extends can't be read from stdin, use '--filename -' for the manifest on top")
+		}
+		if commands.IsNonInteractive(cmd.Flags()) {
+			return nil, errors.New("cannot read the service from stdin with '--filename -' in non-interactive mode")
+		}
+		in = cmd.InOrStdin()
+	} else {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		in = file
+	}
+
+	manifest := map[string]interface{}{}
+	err := yaml.NewYAMLOrJSONDecoder(in, 512).Decode(&manifest)
+	if err != nil {
+		return nil, err
+	}
+	// Fail early for manifests which are not a service
+	var service servingv1.Service
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, &service)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' is not a service manifest: %v", path, err)
+	}
+	return manifest, nil
+}
+
+// mergeServiceManifests merges the overlay into the base manifest like 'kubectl patch --type strategic':
+// maps are merged recursively, containers, environment variables and volumes are merged by their name
+// and other lists are replaced. A single unnamed container is merged with the single container of
+// the other manifest.
+func mergeServiceManifests(base, overlay map[string]interface{}) (map[string]interface{}, error) {
+	baseContainer := singleContainer(base)
+	overlayContainer := singleContainer(overlay)
+	if baseContainer != nil && overlayContainer != nil {
+		baseName, _ := baseContainer["name"].(string)
+		overlayName, _ := overlayContainer["name"].(string)
+		switch {
+		case baseName == "" && overlayName == "":
+			baseContainer["name"] = unnamedContainer
+			overlayContainer["name"] = unnamedContainer
+		case baseName == "":
+			baseContainer["name"] = overlayName
+		case overlayName == "":
+			overlayContainer["name"] = baseName
+		}
+	}
+
+	merged, err := strategicpatch.StrategicMergeMapPatch(base, overlay, servingv1.Service{})
+	if err != nil {
+		return nil, err
+	}
+	if container := singleContainer(merged); container != nil && container["name"] == unnamedContainer {
+		delete(container, "name")
+	}
+	return merged, nil
+}
+
+// singleContainer returns the container of a manifest with exactly one container
+func singleContainer(manifest map[string]interface{}) map[string]interface{} {
+	value, _, _ := unstructured.NestedFieldNoCopy(manifest, "spec", "template", "spec", "containers")
+	containers, ok := value.([]interface{})
+	if !ok || len(containers) != 1 {
+		return nil
+	}
+	container, _ := containers[0].(map[string]interface{})
+	return container
+}
@@ -0,0 +1,165 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var baseServiceYAML = `
+apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: foo
+  labels:
+    team: blue
+spec:
+  template:
+    metadata:
+      annotations:
+        autoscaling.knative.dev/minScale: "1"
+    spec:
+      containers:
+        - image: gcr.io/foo/bar:baz
+          args: ["--verbose", "--port=8080"]
+          env:
+            - name: TARGET
+              value: base
+            - name: LOG_LEVEL
+              value: info
+`
+
+var devOverlayYAML = `
+metadata:
+  labels:
+    env: dev
+spec:
+  template:
+    metadata:
+      annotations:
+        autoscaling.knative.dev/maxScale: "2"
+    spec:
+      containers:
+        - args: ["--debug"]
+          env:
+            - name: LOG_LEVEL
+              value: debug
+            - name: DEV_MODE
+              value: "true"
+`
+
+func writeManifests(t *testing.T, manifests map[string]string) string {
+	tempDir, err := ioutil.TempDir("", "kn-extends")
+	assert.NilError(t, err)
+	for name, content := range manifests {
+		err = ioutil.WriteFile(filepath.Join(tempDir, name), []byte(content), os.FileMode(0666))
+		assert.NilError(t, err)
+	}
+	return tempDir
+}
+
+func TestServiceCreateExtends(t *testing.T) {
+	dir := writeManifests(t, map[string]string{"base.yaml": baseServiceYAML, "dev.yaml": devOverlayYAML})
+	defer os.RemoveAll(dir)
+
+	// base.yaml, then dev.yaml, then the options
+	action, created, _, err := fakeServiceCreate([]string{
+		"service", "create", "--extends", filepath.Join(dir, "base.yaml"), "--filename", filepath.Join(dir, "dev.yaml"),
+		"--env", "DEV_MODE=false", "--no-wait"}, false)
+	assert.NilError(t, err)
+	assert.Assert(t, action.Matches("create", "services"))
+
+	assert.Equal(t, created.Name, "foo")
+	assert.DeepEqual(t, created.Labels, map[string]string{"team": "blue", "env": "dev"})
+	assert.Equal(t, created.Spec.Template.Annotations["autoscaling.knative.dev/minScale"], "1")
+	assert.Equal(t, created.Spec.Template.Annotations["autoscaling.knative.dev/maxScale"], "2")
+
+	container := created.Spec.Template.Spec.GetContainer()
+	assert.Equal(t, container.Name, "")
+	assert.Equal(t, container.Image, "gcr.io/foo/bar:baz")
+	assert.DeepEqual(t, container.Args, []string{"--debug"})
+	assert.DeepEqual(t, container.Env, []corev1.EnvVar{
+		{Name: "DEV_MODE", Value: "false"},
+		{Name: "LOG_LEVEL", Value: "debug"},
+		{Name: "TARGET", Value: "base"},
+	})
+}
+
+func TestServiceCreateExtendsMultipleWithoutFilename(t *testing.T) {
+	namedBase := `
+apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: foo
+spec:
+  template:
+    spec:
+      containers:
+        - name: app
+          image: gcr.io/foo/bar:baz
+          env:
+            - name: TARGET
+              value: base
+`
+	prodOverlay := `
+spec:
+  template:
+    spec:
+      containers:
+        - env:
+            - name: TARGET
+              $patch: delete
+`
+	dir := writeManifests(t, map[string]string{"base.yaml": namedBase, "dev.yaml": devOverlayYAML, "prod.yaml": prodOverlay})
+	defer os.RemoveAll(dir)
+
+	_, created, _, err := fakeServiceCreate([]string{
+		"service", "create", "foo",
+		"--extends", filepath.Join(dir, "base.yaml"), "--extends", filepath.Join(dir, "dev.yaml"), "--extends", filepath.Join(dir, "prod.yaml"),
+		"--image", "gcr.io/foo/bar:v2", "--no-wait"}, false)
+	assert.NilError(t, err)
+
+	assert.Equal(t, created.Name, "foo")
+	container := created.Spec.Template.Spec.GetContainer()
+	assert.Equal(t, container.Name, "app")
+	assert.Equal(t, container.Image, "gcr.io/foo/bar:v2")
+	assert.DeepEqual(t, container.Env, []corev1.EnvVar{
+		{Name: "LOG_LEVEL", Value: "debug"},
+		{Name: "DEV_MODE", Value: "true"},
+	})
+}
+
+func TestServiceCreateExtendsErrors(t *testing.T) {
+	dir := writeManifests(t, map[string]string{"base.yaml": baseServiceYAML, "invalid.yaml": "spec: [1, 2]\n"})
+	defer os.RemoveAll(dir)
+
+	_, _, _, err := fakeServiceCreate([]string{
+		"service", "create", "foo", "--extends", "-", "--no-wait"}, false)
+	assert.ErrorContains(t, err, "--extends can't be read from stdin")
+
+	_, _, _, err = fakeServiceCreate([]string{
+		"service", "create", "foo", "--extends", filepath.Join(dir, "base.yaml"), "--extends", filepath.Join(dir, "invalid.yaml"), "--no-wait"}, false)
+	assert.ErrorContains(t, err, "invalid.yaml' is not a service manifest")
+
+	_, _, _, err = fakeServiceCreate([]string{
+		"service", "create", "foo", "--extends", filepath.Join(dir, "missing.yaml"), "--no-wait"}, false)
+	assert.ErrorContains(t, err, "missing.yaml")
+}
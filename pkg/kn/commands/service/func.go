@@ -0,0 +1,143 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+
+	"knative.dev/client/pkg/kn/config"
+	"knative.dev/client/pkg/kn/plugin"
+)
+
+// Name of the file describing a Knative function project
+const funcFileName = "func.yaml"
+
+// knativeFunc holds the fields of a func.yaml which are needed for deploying
+// a function which has already been built
+type knativeFunc struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	Image       string `json:"image"`
+	ImageDigest string `json:"imageDigest"`
+}
+
+// lookupFuncPlugin returns the kn-func plugin or nil if it's not installed, can be
+// replaced in tests
+var lookupFuncPlugin = func() (plugin.Plugin, error) {
+	manager := plugin.NewManager(config.GlobalConfig.PluginsDir(), config.GlobalConfig.LookupPluginsInPath())
+	return manager.FindPlugin([]string{"func"})
+}
+
+// funcFileHint returns a hint to use --from-knative-func when the given directory
+// contains a function project, or an empty string otherwise
+func funcFileHint(dir string) string {
+	if _, err := os.Stat(filepath.Join(dir, funcFileName)); err != nil {
+		return ""
+	}
+	return fmt.Sprintf("\nThe current directory contains a Knative function (%s), use --from-knative-func to deploy it", funcFileName)
+}
+
+func readKnativeFunc(dir string) (*knativeFunc, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, funcFileName))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("--from-knative-func requires a %s in the current directory", funcFileName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var fn knativeFunc
+	err = yaml.Unmarshal(data, &fn)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %v", funcFileName, err)
+	}
+	return &fn, nil
+}
+
+// imageReference returns the image of the function, pinned to its digest if the
+// digest is known
+func (fn *knativeFunc) imageReference() string {
+	if fn.ImageDigest == "" || strings.Contains(fn.Image, "@") {
+		return fn.Image
+	}
+	repository := fn.Image
+	// Strip a tag, but not the port of a registry
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository = repository[:i]
+	}
+	return repository + "@" + fn.ImageDigest
+}
+
+// deployWithFuncPlugin builds and deploys the function in the given directory with
+// the kn-func plugin. Only the namespace is passed on, as the plugin takes all other
+// settings from the func.yaml.
+func deployWithFuncPlugin(funcPlugin plugin.Plugin, cmd *cobra.Command, dir string) error {
+	var unsupported []string
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if flag.Name != "namespace" && flag.Name != "from-knative-func" {
+			unsupported = append(unsupported, "--"+flag.Name)
+		}
+	})
+	if len(unsupported) > 0 {
+		return fmt.Errorf("cannot use %s with --from-knative-func when deploying with the kn-func plugin '%s', "+
+			"configure the function in its %s instead", strings.Join(unsupported, ", "), funcPlugin.Path(), funcFileName)
+	}
+
+	args := []string{"deploy", "--path", dir}
+	namespace, _ := cmd.Flags().GetString("namespace")
+	if namespace != "" {
+		args = append(args, "--namespace", namespace)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Deploying function with 'kn %s'\n", strings.Join(append(funcPlugin.CommandParts(), args...), " "))
+	return funcPlugin.Execute(args)
+}
+
+// applyKnativeFunc takes the service name, image and namespace from the function
+// when they are not given on the command line, for deploying an already built
+// function without the kn-func plugin. The service name is returned.
+func applyKnativeFunc(fn *knativeFunc, cmd *cobra.Command, args []string, editFlags *ConfigurationEditFlags) (string, error) {
+	name := fn.Name
+	if len(args) == 1 {
+		name = args[0]
+	}
+	if name == "" {
+		return "", fmt.Errorf("'service create' requires the service name given as argument or as name in %s", funcFileName)
+	}
+	if editFlags.PodSpecFlags.Image == "" {
+		if fn.Image == "" {
+			return "", fmt.Errorf("function '%s' has not been built yet as %s has no image. "+
+				"Build it with the kn-func plugin ('kn func build') or give the image with --image", name, funcFileName)
+		}
+		err := cmd.Flags().Set("image", fn.imageReference())
+		if err != nil {
+			return "", err
+		}
+	}
+	namespace, _ := cmd.Flags().GetString("namespace")
+	if namespace == "" && fn.Namespace != "" {
+		err := cmd.Flags().Set("namespace", fn.Namespace)
+		if err != nil {
+			return "", err
+		}
+	}
+	return name, nil
+}
@@ -0,0 +1,136 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/plugin"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+	"knative.dev/client/pkg/util/mock"
+	"knative.dev/client/pkg/wait"
+)
+
+// fakeFuncPlugin records the arguments it has been executed with
+type fakeFuncPlugin struct {
+	args []string
+}
+
+func (f *fakeFuncPlugin) Name() string                 { return "kn-func" }
+func (f *fakeFuncPlugin) Description() (string, error) { return "", nil }
+func (f *fakeFuncPlugin) CommandParts() []string       { return []string{"func"} }
+func (f *fakeFuncPlugin) Path() string                 { return "/plugins/kn-func" }
+func (f *fakeFuncPlugin) Execute(args []string) error {
+	f.args = args
+	return nil
+}
+
+// setupFuncProject changes into a temporary directory with the given func.yaml
+// and replaces the lookup of the kn-func plugin
+func setupFuncProject(t *testing.T, funcYAML string, funcPlugin plugin.Plugin) {
+	dir, err := ioutil.TempDir("", "kn-func")
+	assert.NilError(t, err)
+	if funcYAML != "" {
+		assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, funcFileName), []byte(funcYAML), 0644))
+	}
+	oldDir, err := os.Getwd()
+	assert.NilError(t, err)
+	assert.NilError(t, os.Chdir(dir))
+
+	oldLookup := lookupFuncPlugin
+	lookupFuncPlugin = func() (plugin.Plugin, error) {
+		if funcPlugin == nil {
+			return nil, nil
+		}
+		return funcPlugin, nil
+	}
+	t.Cleanup(func() {
+		lookupFuncPlugin = oldLookup
+		os.Chdir(oldDir)
+		os.RemoveAll(dir)
+	})
+}
+
+func TestServiceCreateFromKnativeFunc(t *testing.T) {
+	setupFuncProject(t, `
+name: hello
+namespace: functions
+runtime: go
+image: registry.example.com:5000/hello:latest
+imageDigest: sha256:0123456789abcdef
+`, nil)
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("hello", nil, errors.NewNotFound(servingv1.Resource("service"), "hello"))
+	r.CreateService(func(t *testing.T, service *servingv1.Service) {
+		assert.Equal(t, service.Name, "hello")
+		assert.Equal(t, service.Namespace, "functions")
+		assert.Equal(t, service.Spec.Template.Spec.Containers[0].Image, "registry.example.com:5000/hello@sha256:0123456789abcdef")
+	}, nil)
+	r.WaitForService("hello", mock.Any(), wait.NoopMessageCallback(), nil, time.Second)
+	r.GetService("hello", getServiceWithUrl("hello", "http://hello.functions.example.com"), nil)
+
+	output, err := executeServiceCommand(client, "create", "--from-knative-func")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Creating service 'hello'", "http://hello.functions.example.com"))
+	r.Validate()
+}
+
+func TestServiceCreateFromKnativeFuncNotBuilt(t *testing.T) {
+	setupFuncProject(t, "name: hello\nruntime: go\n", nil)
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	_, err := executeServiceCommand(client, "create", "--from-knative-func")
+	assert.ErrorContains(t, err, "has not been built yet")
+	client.Recorder().Validate()
+}
+
+func TestServiceCreateFromKnativeFuncWithPlugin(t *testing.T) {
+	funcPlugin := &fakeFuncPlugin{}
+	setupFuncProject(t, "name: hello\nruntime: go\n", funcPlugin)
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	output, err := executeServiceCommand(client, "create", "--from-knative-func", "--namespace", "functions")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, funcPlugin.args, []string{"deploy", "--path", ".", "--namespace", "functions"})
+	assert.Assert(t, util.ContainsAll(output, "kn func deploy"))
+
+	_, err = executeServiceCommand(client, "create", "--from-knative-func", "--env", "FOO=bar")
+	assert.ErrorContains(t, err, "cannot use --env")
+	client.Recorder().Validate()
+}
+
+func TestServiceCreateFuncFileHint(t *testing.T) {
+	setupFuncProject(t, "name: hello\n", nil)
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	_, err := executeServiceCommand(client, "create", "hello")
+	assert.ErrorContains(t, err, "--image")
+	assert.ErrorContains(t, err, "use --from-knative-func")
+
+	setupFuncProject(t, "", nil)
+	_, err = executeServiceCommand(client, "create", "--from-knative-func")
+	assert.ErrorContains(t, err, "requires a func.yaml")
+}
@@ -34,6 +34,8 @@ func ServiceListHandlers(h hprinters.PrintHandler) {
 		{Name: "Conditions", Type: "string", Description: "Conditions describing statuses of service components.", Priority: 1},
 		{Name: "Ready", Type: "string", Description: "Ready condition status of the service.", Priority: 1},
 		{Name: "Reason", Type: "string", Description: "Reason for non-ready condition of the service.", Priority: 1},
+		{Name: "Latest Created", Type: "string", Description: "Name of the latest created revision.", Priority: hprinters.WidePriority},
+		{Name: "Image", Type: "string", Description: "Image of the revision template.", Priority: hprinters.WidePriority},
 	}
 
 	h.TableHandler(kServiceColumnDefinitions, printKService)
@@ -82,5 +84,12 @@ func printKService(kService *servingv1.Service, options hprinters.PrintOptions)
 		conditions,
 		ready,
 		reason)
+	if options.Wide {
+		image := ""
+		if containers := kService.Spec.Template.Spec.Containers; len(containers) > 0 {
+			image = containers[0].Image
+		}
+		row.Cells = append(row.Cells, kService.Status.LatestCreatedRevisionName, image)
+	}
 	return []metav1beta1.TableRow{row}, nil
 }
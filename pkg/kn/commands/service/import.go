@@ -100,34 +100,39 @@ func importWithOwnerRef(client clientservingv1.KnServingClient, filename string,
 			serviceName, client.Namespace())
 	}
 
-	err = client.CreateService(&export.Spec.Service)
-	if err != nil {
-		return err
-	}
+	return importOperation(export.Spec.Revisions).run(client, &export.Spec.Service, waitFlags, out)
+}
 
-	// Retrieve current Configuration to be use in OwnerReference
-	currentConf, err := getConfigurationWithRetry(client, serviceName)
-	if err != nil {
-		return err
-	}
+// importOperation returns the operation for creating a service together with
+// the given revisions of its export
+func importOperation(revisions []servingv1.Revision) serviceOperation {
+	return serviceOperation{
+		doing: "Importing",
+		done:  "imported",
+		mutate: func(client clientservingv1.KnServingClient, service *servingv1.Service) (bool, error) {
+			err := client.CreateService(service)
+			if err != nil {
+				return false, err
+			}
 
-	// Create revision with current Configuration's OwnerReference
-	if len(export.Spec.Revisions) > 0 {
-		for _, r := range export.Spec.Revisions {
-			tmp := r.DeepCopy()
-			// OwnerRef ensures that Revisions are recognized by controller
-			tmp.OwnerReferences = []metav1.OwnerReference{*kmeta.NewControllerRef(currentConf)}
-			if err = client.CreateRevision(tmp); err != nil {
-				return err
+			// Retrieve current Configuration to be use in OwnerReference
+			currentConf, err := getConfigurationWithRetry(client, service.Name)
+			if err != nil {
+				return false, err
 			}
-		}
-	}
 
-	err = waitIfRequested(client, serviceName, waitFlags, "Importing", "imported", out)
-	if err != nil {
-		return err
+			// Create revision with current Configuration's OwnerReference
+			for _, r := range revisions {
+				tmp := r.DeepCopy()
+				// OwnerRef ensures that Revisions are recognized by controller
+				tmp.OwnerReferences = []metav1.OwnerReference{*kmeta.NewControllerRef(currentConf)}
+				if err = client.CreateRevision(tmp); err != nil {
+					return false, err
+				}
+			}
+			return true, nil
+		},
 	}
-	return err
 }
 
 func getConfigurationWithRetry(client clientservingv1.KnServingClient, name string) (*servingv1.Configuration, error) {
@@ -0,0 +1,252 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/registry"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+)
+
+// Label of namespaces which enforce a pod security level
+const podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+
+// Port the user container has to listen on if the service doesn't declare one
+const defaultContainerPort = 8080
+
+// NewServiceInspectImageCommand represents 'kn service inspect-image' command
+func NewServiceInspectImageCommand(p *commands.KnParams) *cobra.Command {
+	var platform string
+
+	command := &cobra.Command{
+		Use:   "inspect-image NAME",
+		Short: "Check the images of a service against the runtime contract",
+		Long: `Check the images of a service against the runtime contract
+
+The configuration of every image is read from its registry and compared with the service:
+whether the image exposes the port the service declares, whether it runs as non-root user
+when this is required, and whether it has a command which keeps running. These mismatches
+commonly let a revision fail with 'container failed to start' only after a long wait.
+The registry is accessed with the credentials of the Docker configuration.`,
+		Example: `
+  # Check the images of service 'mysvc'
+  kn service inspect-image mysvc
+
+  # Check the arm64 variant of the images
+  kn service inspect-image mysvc --platform linux/arm64`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("'service inspect-image' requires the service name given as single argument")
+			}
+			name := args[0]
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := p.NewServingClient(namespace)
+			if err != nil {
+				return err
+			}
+			service, err := client.GetService(name)
+			if err != nil {
+				return err
+			}
+
+			report := &commands.CheckReport{}
+			nonRootRequired := namespaceRequiresNonRoot(p, namespace)
+			containers := service.Spec.Template.Spec.Containers
+			digests := revisionImageDigests(client, service)
+			for i := range containers {
+				container := &containers[i]
+				prefix := ""
+				if len(containers) > 1 {
+					prefix = container.Name + "/"
+				}
+				image := container.Image
+				if digest, ok := digests[container.Name]; ok {
+					image = digest
+				}
+				config, err := registry.Config(image, platform)
+				if err != nil {
+					report.Fail(prefix+"image", fmt.Errorf("cannot read configuration of image %s: %v", image, err))
+					continue
+				}
+				report.Pass(prefix+"image", image)
+				if len(container.Ports) > 0 || len(containers) == 1 {
+					checkImagePort(container, config, prefix, report)
+				}
+				checkImageUser(&service.Spec.Template.Spec.PodSpec, container, config, nonRootRequired, prefix, report)
+				checkImageEntrypoint(container, config, prefix, report)
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Runtime contract of service '%s' in namespace '%s':\n\n", name, namespace)
+			err = report.Print(out)
+			if err != nil {
+				return err
+			}
+			if !report.Passed() {
+				return fmt.Errorf("images of service '%s' don't fulfill the runtime contract", name)
+			}
+			return nil
+		},
+	}
+	commands.AddNamespaceFlags(command.Flags(), false)
+	command.Flags().StringVar(&platform, "platform", "linux/amd64", "Platform of the images to check, for images built for multiple platforms.")
+	return command
+}
+
+// revisionImageDigests returns the digests the latest revision resolved for its containers, so that
+// the images which actually run are checked. Without a revision, the images of the service are used.
+func revisionImageDigests(client clientservingv1.KnServingClient, service *servingv1.Service) map[string]string {
+	digests := map[string]string{}
+	if service.Status.LatestCreatedRevisionName == "" {
+		return digests
+	}
+	revision, err := client.GetRevision(service.Status.LatestCreatedRevisionName)
+	if err != nil {
+		return digests
+	}
+	for _, status := range revision.Status.ContainerStatuses {
+		if status.ImageDigest != "" {
+			digests[status.Name] = status.ImageDigest
+		}
+	}
+	return digests
+}
+
+// namespaceRequiresNonRoot returns true if the namespace enforces the 'restricted' pod security
+// standard. Namespaces which can't be read are considered not to require it.
+func namespaceRequiresNonRoot(p *commands.KnParams, namespace string) bool {
+	kubeClient, err := p.NewKubeClient()
+	if err != nil {
+		return false
+	}
+	ns, err := kubeClient.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+	if err != nil {
+		return false
+	}
+	return ns.Labels[podSecurityEnforceLabel] == "restricted"
+}
+
+// checkImagePort checks that the image exposes the port the service sends requests to
+func checkImagePort(container *corev1.Container, config *registry.ImageConfig, prefix string, report *commands.CheckReport) {
+	port := int32(defaultContainerPort)
+	if len(container.Ports) > 0 && container.Ports[0].ContainerPort != 0 {
+		port = container.Ports[0].ContainerPort
+	}
+	if len(config.ExposedPorts) == 0 {
+		report.Pass(prefix+"port", fmt.Sprintf("image exposes no port, the application has to listen on $PORT (%d)", port))
+		return
+	}
+	var exposed []string
+	for exposedPort := range config.ExposedPorts {
+		if strings.SplitN(exposedPort, "/", 2)[0] == strconv.Itoa(int(port)) {
+			report.Pass(prefix+"port", fmt.Sprintf("image exposes port %d", port))
+			return
+		}
+		exposed = append(exposed, exposedPort)
+	}
+	sort.Strings(exposed)
+	report.Fail(prefix+"port", fmt.Errorf("image exposes %s, but the service sends requests to port %d: "+
+		"use --port or let the application listen on $PORT", strings.Join(exposed, ", "), port))
+}
+
+// checkImageUser checks that the container runs as non-root user if the pod or the namespace requires it
+func checkImageUser(podSpec *corev1.PodSpec, container *corev1.Container, config *registry.ImageConfig, nonRootRequired bool,
+	prefix string, report *commands.CheckReport) {
+	var runAsUser *int64
+	if podSpec.SecurityContext != nil {
+		if podSpec.SecurityContext.RunAsNonRoot != nil && *podSpec.SecurityContext.RunAsNonRoot {
+			nonRootRequired = true
+		}
+		runAsUser = podSpec.SecurityContext.RunAsUser
+	}
+	if container.SecurityContext != nil {
+		if container.SecurityContext.RunAsNonRoot != nil && *container.SecurityContext.RunAsNonRoot {
+			nonRootRequired = true
+		}
+		if container.SecurityContext.RunAsUser != nil {
+			runAsUser = container.SecurityContext.RunAsUser
+		}
+	}
+
+	var user string
+	if runAsUser != nil {
+		user = strconv.FormatInt(*runAsUser, 10)
+	} else {
+		// The group after the colon doesn't matter
+		user = strings.SplitN(config.User, ":", 2)[0]
+	}
+	if !nonRootRequired {
+		if user == "" {
+			user = "root"
+		}
+		report.Pass(prefix+"user", fmt.Sprintf("runs as user %s, non-root is not required", user))
+		return
+	}
+
+	uid, err := strconv.ParseInt(user, 10, 64)
+	switch {
+	case user == "" || user == "root" || (err == nil && uid == 0):
+		report.Fail(prefix+"user", errors.New("runs as root, but a non-root user is required: "+
+			"set a numeric USER in the image or runAsUser in the security context"))
+	case err != nil:
+		report.Fail(prefix+"user", fmt.Errorf("runs as user '%s', but the kubelet can verify only numeric users to be non-root: "+
+			"set a numeric USER in the image or runAsUser in the security context", user))
+	default:
+		report.Pass(prefix+"user", fmt.Sprintf("runs as non-root user %d", uid))
+	}
+}
+
+// checkImageEntrypoint checks that the container has a command which doesn't exit immediately.
+// The command and arguments of the container override the ones of the image as in Kubernetes.
+func checkImageEntrypoint(container *corev1.Container, config *registry.ImageConfig, prefix string, report *commands.CheckReport) {
+	command := config.Entrypoint
+	args := config.Cmd
+	if len(container.Command) > 0 {
+		command = container.Command
+		args = nil
+	}
+	if len(container.Args) > 0 {
+		args = container.Args
+	}
+	argv := append(append([]string{}, command...), args...)
+	if len(argv) == 0 {
+		report.Fail(prefix+"entrypoint", errors.New("image has neither an entrypoint nor a command: set --cmd"))
+		return
+	}
+	switch path.Base(argv[0]) {
+	case "sh", "bash", "ash", "zsh":
+		if len(argv) == 1 {
+			report.Fail(prefix+"entrypoint", fmt.Errorf("runs only the shell %s, which exits immediately without a terminal: set --cmd", argv[0]))
+			return
+		}
+	}
+	report.Pass(prefix+"entrypoint", "runs "+strings.Join(argv, " "))
+}
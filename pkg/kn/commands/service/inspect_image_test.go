@@ -0,0 +1,152 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/ptr"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	knflags "knative.dev/client/pkg/kn/flags"
+	"knative.dev/client/pkg/registry"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+)
+
+func executeInspectImageCommand(client clientservingv1.KnServingClient, imageConfigs map[string]string, namespaces []runtime.Object, args ...string) (string, error) {
+	oldConfig := registry.Config
+	defer func() { registry.Config = oldConfig }()
+	registry.Config = func(image string, platform string) (*registry.ImageConfig, error) {
+		config, ok := imageConfigs[image]
+		if !ok {
+			return nil, errors.New("MANIFEST_UNKNOWN")
+		}
+		var configFile struct {
+			Config registry.ImageConfig `json:"config"`
+		}
+		err := json.Unmarshal([]byte(config), &configFile)
+		return &configFile.Config, err
+	}
+
+	knParams := &commands.KnParams{}
+	knParams.ClientConfig = blankConfig
+	output := new(bytes.Buffer)
+	knParams.Output = output
+	knParams.NewServingClient = func(namespace string) (clientservingv1.KnServingClient, error) {
+		return client, nil
+	}
+	knParams.NewKubeClient = func() (kubernetes.Interface, error) {
+		return commands.NewFakeKubeClient(namespaces...), nil
+	}
+	cmd := NewServiceCommand(knParams)
+	cmd.SetArgs(append([]string{"inspect-image"}, args...))
+	cmd.SetOutput(output)
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return knflags.ReconcileBoolFlags(cmd.Flags())
+	}
+	err := cmd.Execute()
+	return output.String(), err
+}
+
+func TestServiceInspectImage(t *testing.T) {
+	service := newInspectImageService("gcr.io/foo/bar:v1")
+	service.Status.LatestCreatedRevisionName = "foo-00001"
+	revision := &servingv1.Revision{ObjectMeta: metav1.ObjectMeta{Name: "foo-00001", Namespace: "default"}}
+	revision.Status.ContainerStatuses = []servingv1.ContainerStatus{{Name: "user-container", ImageDigest: "gcr.io/foo/bar@sha256:abc"}}
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", service, nil)
+	r.GetRevision("foo-00001", revision, nil)
+
+	output, err := executeInspectImageCommand(client, map[string]string{
+		"gcr.io/foo/bar@sha256:abc": `{"config": {"User": "1000", "ExposedPorts": {"8080/tcp": {}}, "Entrypoint": ["/app"]}}`,
+	}, nil, "foo")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Runtime contract of service 'foo' in namespace 'default'"))
+	assert.Assert(t, util.ContainsAll(output, "image", "PASS", "gcr.io/foo/bar@sha256:abc"))
+	assert.Assert(t, util.ContainsAll(output, "port", "PASS", "image exposes port 8080"))
+	assert.Assert(t, util.ContainsAll(output, "user", "PASS", "runs as user 1000, non-root is not required"))
+	assert.Assert(t, util.ContainsAll(output, "entrypoint", "PASS", "runs /app"))
+	assert.Assert(t, util.ContainsNone(output, "FAIL"))
+	r.Validate()
+}
+
+func TestServiceInspectImageMismatches(t *testing.T) {
+	service := newInspectImageService("gcr.io/foo/bar:v1")
+	service.Spec.Template.Spec.SecurityContext = &corev1.PodSecurityContext{RunAsNonRoot: ptr.Bool(true)}
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", service, nil)
+
+	output, err := executeInspectImageCommand(client, map[string]string{
+		"gcr.io/foo/bar:v1": `{"config": {"ExposedPorts": {"80/tcp": {}}, "Cmd": ["/bin/sh"]}}`,
+	}, nil, "foo")
+	assert.Error(t, err, "images of service 'foo' don't fulfill the runtime contract")
+	assert.Assert(t, util.ContainsAll(output, "port", "FAIL", "image exposes 80/tcp, but the service sends requests to port 8080"))
+	assert.Assert(t, util.ContainsAll(output, "user", "FAIL", "runs as root, but a non-root user is required"))
+	assert.Assert(t, util.ContainsAll(output, "entrypoint", "FAIL", "runs only the shell /bin/sh"))
+	r.Validate()
+}
+
+func TestServiceInspectImageRestrictedNamespace(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", newInspectImageService("gcr.io/foo/bar:v1"), nil)
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "default",
+		Labels: map[string]string{podSecurityEnforceLabel: "restricted"},
+	}}
+	output, err := executeInspectImageCommand(client, map[string]string{
+		"gcr.io/foo/bar:v1": `{"config": {"User": "nobody", "Entrypoint": ["/app"]}}`,
+	}, []runtime.Object{namespace}, "foo")
+	assert.ErrorContains(t, err, "don't fulfill the runtime contract")
+	assert.Assert(t, util.ContainsAll(output, "user", "FAIL", "runs as user 'nobody', but the kubelet can verify only numeric users"))
+	assert.Assert(t, util.ContainsAll(output, "port", "PASS", "image exposes no port"))
+	r.Validate()
+}
+
+func TestServiceInspectImageErrors(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", newInspectImageService("gcr.io/foo/missing:v1"), nil)
+
+	output, err := executeInspectImageCommand(client, nil, nil, "foo")
+	assert.ErrorContains(t, err, "don't fulfill the runtime contract")
+	assert.Assert(t, util.ContainsAll(output, "image", "FAIL", "cannot read configuration of image gcr.io/foo/missing:v1", "MANIFEST_UNKNOWN"))
+
+	_, err = executeInspectImageCommand(client, nil, nil)
+	assert.Error(t, err, "'service inspect-image' requires the service name given as single argument")
+	r.Validate()
+}
+
+func newInspectImageService(image string) *servingv1.Service {
+	service := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"}}
+	service.Spec.Template.Spec.Containers = []corev1.Container{{Name: "user-container", Image: image}}
+	return service
+}
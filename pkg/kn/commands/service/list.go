@@ -15,20 +15,32 @@
 package service
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"sort"
 
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
 	"knative.dev/client/pkg/kn/commands"
 	"knative.dev/client/pkg/kn/commands/flags"
+	hprinters "knative.dev/client/pkg/printers"
+	servinglib "knative.dev/client/pkg/serving"
 	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
 )
 
 // NewServiceListCommand represents 'kn service list' command
 func NewServiceListCommand(p *commands.KnParams) *cobra.Command {
 	serviceListFlags := flags.NewListPrintFlags(ServiceListHandlers)
+	var owners []string
+	var selector string
+	var showAllRevisions bool
+	var watchChanges bool
 
 	serviceListCommand := &cobra.Command{
 		Use:     "list",
@@ -42,8 +54,32 @@ func NewServiceListCommand(p *commands.KnParams) *cobra.Command {
   kn service list -o json
 
   # List service 'web'
-  kn service list web`,
+  kn service list web
+
+  # List all services owned by team 'payments'
+  kn service list --owner team=payments
+
+  # List all services with their revisions, traffic and readiness
+  kn service list --show-all-revisions
+
+  # List the services labeled 'tier=frontend' in all namespaces, with their latest created revision and image
+  kn service list -l tier=frontend --all-namespaces -o wide
+
+  # List the names and URLs of all services, the oldest first
+  kn service list -o custom-columns=NAME:.metadata.name,URL:.status.url --sort-by .metadata.creationTimestamp
+
+  # List all services and print a new row whenever a service changes, e.g. becomes ready
+  kn service list --watch`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if showAllRevisions && serviceListFlags.GenericPrintFlags.OutputFlagSpecified() {
+				return fmt.Errorf("--show-all-revisions can't be used together with --output")
+			}
+			if watchChanges && showAllRevisions {
+				return fmt.Errorf("--watch can't be used together with --show-all-revisions")
+			}
+			if watchChanges && !serviceListFlags.HumanReadable() {
+				return fmt.Errorf("--watch can only be used with the default or the wide output format")
+			}
 			namespace, err := p.GetNamespace(cmd)
 			if err != nil {
 				return err
@@ -52,13 +88,18 @@ func NewServiceListCommand(p *commands.KnParams) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			serviceList, err := getServiceInfo(args, client)
+			ownerConfig, err := ownerListConfig(owners)
 			if err != nil {
 				return err
 			}
-			if len(serviceList.Items) == 0 {
-				fmt.Fprintf(cmd.OutOrStdout(), "No services found.\n")
-				return nil
+			selectorConfig, err := selectorListConfigs(selector)
+			if err != nil {
+				return err
+			}
+			listConfigs := append(ownerConfig, selectorConfig...)
+			serviceList, err := getServiceInfo(args, client, listConfigs...)
+			if err != nil {
+				return err
 			}
 
 			// empty namespace indicates all-namespaces flag is specified
@@ -66,6 +107,13 @@ func NewServiceListCommand(p *commands.KnParams) *cobra.Command {
 				serviceListFlags.EnsureWithNamespace()
 			}
 
+			if len(serviceList.Items) == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "No services found.\n")
+				if !watchChanges {
+					return nil
+				}
+			}
+
 			// Sort serviceList by namespace and name (in this order)
 			sort.SliceStable(serviceList.Items, func(i, j int) bool {
 				a := serviceList.Items[i]
@@ -77,26 +125,144 @@ func NewServiceListCommand(p *commands.KnParams) *cobra.Command {
 				return a.ObjectMeta.Name < b.ObjectMeta.Name
 			})
 
-			return serviceListFlags.Print(serviceList, cmd.OutOrStdout())
+			if showAllRevisions {
+				if serviceListFlags.SortBy != "" {
+					err = hprinters.SortObjects(serviceList, serviceListFlags.SortBy)
+					if err != nil {
+						return err
+					}
+				}
+				return printServiceRevisionTree(client, serviceList, serviceListFlags.HumanReadableFlags, cmd.OutOrStdout())
+			}
+			if len(serviceList.Items) > 0 {
+				err = serviceListFlags.Print(serviceList, cmd.OutOrStdout())
+				if err != nil {
+					return err
+				}
+			}
+			if watchChanges {
+				if len(args) == 1 {
+					listConfigs = append(listConfigs, clientservingv1.WithName(args[0]))
+				}
+				return watchServiceList(client, serviceList, listConfigs, serviceListFlags, cmd.OutOrStdout())
+			}
+			return nil
 		},
 	}
 	commands.AddNamespaceFlags(serviceListCommand.Flags(), true)
 	serviceListFlags.AddFlags(serviceListCommand)
+	serviceListFlags.AddSortFlag(serviceListCommand)
+	serviceListCommand.Flags().StringVarP(&selector, "selector", "l", "",
+		"Only list services with labels matching the selector, like 'key=value' or 'key1=value1,key2=value2'.")
+	serviceListCommand.Flags().StringArrayVar(&owners, "owner", []string{},
+		"Only list services with the given owner. key=value (e.g. team=payments); "+
+			"you may provide this flag any number of times to filter on multiple owners.")
+	serviceListCommand.Flags().BoolVar(&showAllRevisions, "show-all-revisions", false,
+		"Show the revisions of each service nested below it, with their traffic, tags and readiness.")
+	serviceListCommand.Flags().BoolVarP(&watchChanges, "watch", "w", false,
+		"After listing the services, watch them and print a row for every service which is added or changes, "+
+			"e.g. when it becomes ready or gets a URL, and a line for every service which is deleted.")
 	return serviceListCommand
 }
 
-func getServiceInfo(args []string, client clientservingv1.KnServingClient) (*servingv1.ServiceList, error) {
+func getServiceInfo(args []string, client clientservingv1.KnServingClient, config ...clientservingv1.ListConfig) (*servingv1.ServiceList, error) {
 	var (
 		serviceList *servingv1.ServiceList
 		err         error
 	)
 	switch len(args) {
 	case 0:
-		serviceList, err = client.ListServices()
+		serviceList, err = client.ListServices(config...)
 	case 1:
-		serviceList, err = client.ListServices(clientservingv1.WithName(args[0]))
+		serviceList, err = client.ListServices(append(config, clientservingv1.WithName(args[0]))...)
 	default:
 		return nil, fmt.Errorf("'kn service list' accepts maximum 1 argument")
 	}
 	return serviceList, err
 }
+
+// ownerListConfig creates the label filters for the given owners
+func ownerListConfig(owners []string) ([]clientservingv1.ListConfig, error) {
+	ownersMap, err := util.MapFromArray(owners, "=")
+	if err != nil {
+		return nil, fmt.Errorf("Invalid --owner: %w", err)
+	}
+	config := make([]clientservingv1.ListConfig, 0, len(ownersMap))
+	for key, value := range ownersMap {
+		config = append(config, clientservingv1.WithLabel(servinglib.OwnerLabelKey(key), value))
+	}
+	return config, nil
+}
+
+// watchServiceList watches the services of the list and prints a row whenever a service
+// is added or the columns of a service change, until the watch is closed by the server
+func watchServiceList(client clientservingv1.KnServingClient, serviceList *servingv1.ServiceList, config []clientservingv1.ListConfig, listFlags *flags.ListPrintFlags, out io.Writer) error {
+	watcher, err := client.WatchServices(append(config, clientservingv1.WithResourceVersion(serviceList.ResourceVersion))...)
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+
+	// Headers are printed with the first row if no service has been listed
+	withHeaders := len(serviceList.Items) == 0 && !listFlags.HumanReadableFlags.NoHeaders
+	listFlags.HumanReadableFlags.NoHeaders = true
+	rowPrinter, err := listFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+	printRow := func(service *servingv1.Service) (string, error) {
+		var buf bytes.Buffer
+		err := rowPrinter.PrintObj(service, &buf)
+		return buf.String(), err
+	}
+
+	// Last printed row of each service, so that events which don't change any column are skipped
+	printed := map[types.NamespacedName]string{}
+	for i := range serviceList.Items {
+		service := &serviceList.Items[i]
+		row, err := printRow(service)
+		if err != nil {
+			return err
+		}
+		printed[types.NamespacedName{Namespace: service.Namespace, Name: service.Name}] = row
+	}
+
+	for event := range watcher.ResultChan() {
+		if event.Type == watch.Error {
+			return apierrors.FromObject(event.Object)
+		}
+		service, ok := event.Object.(*servingv1.Service)
+		if !ok {
+			continue
+		}
+		key := types.NamespacedName{Namespace: service.Namespace, Name: service.Name}
+		if event.Type == watch.Deleted {
+			delete(printed, key)
+			fmt.Fprintf(out, "Service '%s' deleted in namespace '%s'.\n", service.Name, service.Namespace)
+			continue
+		}
+		row, err := printRow(service)
+		if err != nil {
+			return err
+		}
+		if printed[key] == row {
+			continue
+		}
+		printed[key] = row
+		if withHeaders {
+			withHeaders = false
+			listFlags.HumanReadableFlags.NoHeaders = false
+			printer, err := listFlags.ToPrinter()
+			if err != nil {
+				return err
+			}
+			err = printer.PrintObj(service, out)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		fmt.Fprint(out, row)
+	}
+	return nil
+}
@@ -19,6 +19,9 @@ import (
 	"testing"
 
 	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/watch"
+	"knative.dev/pkg/ptr"
+	"knative.dev/serving/pkg/apis/serving"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
 	clientservingv1 "knative.dev/client/pkg/serving/v1"
@@ -187,3 +190,112 @@ func getServiceWithNamespace(name, namespace string) *servingv1.Service {
 	service.Namespace = namespace
 	return &service
 }
+
+func TestServiceListRevisionsMock(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+
+	service1 := createMockServiceWithParams("foo", "default", "http://foo.default.example.com", "foo-00002")
+	service1.Status.Traffic = []servingv1.TrafficTarget{
+		{RevisionName: "foo-00002", Percent: ptr.Int64(80)},
+		{RevisionName: "foo-00001", Percent: ptr.Int64(20), Tag: "old"},
+	}
+	service2 := createMockServiceWithParams("bar", "default", "http://bar.default.example.com", "bar-00001")
+	r.ListServices(mock.Any(), &servingv1.ServiceList{Items: []servingv1.Service{*service1, *service2}}, nil)
+	r.ListRevisions(mock.Any(), &servingv1.RevisionList{Items: []servingv1.Revision{
+		createMockRevisionForService("foo-00001", "foo", "1"),
+		createMockRevisionForService("bar-00001", "bar", "1"),
+		createMockRevisionForService("foo-00002", "foo", "2"),
+	}}, nil)
+
+	output, err := executeServiceCommand(client, "list", "--show-all-revisions")
+	assert.NilError(t, err)
+
+	outputLines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(outputLines[0], "NAME", "TRAFFIC", "TAGS", "READY", "AGE"))
+	assert.Check(t, util.ContainsAll(outputLines[1], "bar"))
+	assert.Check(t, util.ContainsAll(outputLines[2], "└── bar-00001"))
+	assert.Check(t, util.ContainsAll(outputLines[3], "foo"))
+	assert.Check(t, util.ContainsAll(outputLines[4], "├── foo-00002", "80%"))
+	assert.Check(t, util.ContainsAll(outputLines[5], "└── foo-00001", "20%", "old"))
+
+	r.Validate()
+}
+
+func TestServiceListRevisionsWithOutputMock(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	_, err := executeServiceCommand(client, "list", "--show-all-revisions", "-o", "yaml")
+	assert.ErrorContains(t, err, "--show-all-revisions")
+}
+
+func createMockRevisionForService(name, service, generation string) servingv1.Revision {
+	revision := servingv1.Revision{}
+	revision.Name = name
+	revision.Namespace = "default"
+	revision.Labels = map[string]string{
+		serving.ServiceLabelKey:                 service,
+		serving.ConfigurationGenerationLabelKey: generation,
+	}
+	return revision
+}
+
+func TestServiceListWatchMock(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+
+	service1 := createMockServiceWithParams("foo", "default", "", "")
+	serviceList := &servingv1.ServiceList{Items: []servingv1.Service{*service1}}
+	serviceList.ResourceVersion = "42"
+	r.ListServices(mock.Any(), serviceList, nil)
+
+	ready := createMockServiceWithParams("foo", "default", "http://foo.default.example.com", "foo-00001")
+	service2 := createMockServiceWithParams("bar", "default", "", "")
+	watcher := watch.NewFakeWithChanSize(5, false)
+	watcher.Modify(ready)
+	// Not printed again, no column changed
+	watcher.Modify(ready.DeepCopy())
+	watcher.Add(service2)
+	watcher.Delete(service2)
+	watcher.Stop()
+	r.WatchServices(mock.Any(), watcher, nil)
+
+	output, err := executeServiceCommand(client, "list", "--watch")
+	assert.NilError(t, err)
+	outputLines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(outputLines[0], "NAME", "URL", "LATEST", "READY"))
+	assert.Check(t, util.ContainsAll(outputLines[1], "foo"))
+	assert.Check(t, util.ContainsAll(outputLines[2], "foo", "http://foo.default.example.com", "foo-00001"))
+	assert.Check(t, util.ContainsAll(outputLines[3], "bar"))
+	assert.Equal(t, outputLines[4], "Service 'bar' deleted in namespace 'default'.")
+	assert.Equal(t, outputLines[5], "")
+
+	r.Validate()
+}
+
+func TestServiceListWatchEmptyMock(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.ListServices(mock.Any(), &servingv1.ServiceList{}, nil)
+	watcher := watch.NewFakeWithChanSize(1, false)
+	watcher.Add(createMockServiceWithParams("foo", "default", "http://foo.default.example.com", "foo-00001"))
+	watcher.Stop()
+	r.WatchServices(mock.Any(), watcher, nil)
+
+	output, err := executeServiceCommand(client, "list", "foo", "-w")
+	assert.NilError(t, err)
+	outputLines := strings.Split(output, "\n")
+	assert.Equal(t, outputLines[0], "No services found.")
+	// Headers come with the first row
+	assert.Check(t, util.ContainsAll(outputLines[1], "NAME", "URL", "LATEST", "READY"))
+	assert.Check(t, util.ContainsAll(outputLines[2], "foo", "http://foo.default.example.com"))
+
+	r.Validate()
+}
+
+func TestServiceListWatchInvalidFlagsMock(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	_, err := executeServiceCommand(client, "list", "--watch", "--show-all-revisions")
+	assert.ErrorContains(t, err, "--watch can't be used together with --show-all-revisions")
+	_, err = executeServiceCommand(client, "list", "--watch", "-o", "json")
+	assert.ErrorContains(t, err, "--watch can only be used")
+}
@@ -0,0 +1,106 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/printers"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+)
+
+// printServiceRevisionTree prints the services as a tree with the revisions of each service nested below it,
+// newest revision first. Services need to be sorted already.
+func printServiceRevisionTree(client clientservingv1.KnServingClient, serviceList *servingv1.ServiceList, printFlags *commands.HumanPrintFlags, out io.Writer) error {
+	var config []clientservingv1.ListConfig
+	if len(serviceList.Items) == 1 {
+		config = append(config, clientservingv1.WithService(serviceList.Items[0].Name))
+	}
+	revisionList, err := client.ListRevisions(config...)
+	if err != nil {
+		return err
+	}
+	// revisionListSortFunc sorts by ascending generation
+	less := revisionListSortFunc(revisionList)
+	sort.SliceStable(revisionList.Items, func(i, j int) bool {
+		return less(j, i)
+	})
+	revisionsByService := map[string][]servingv1.Revision{}
+	for _, revision := range revisionList.Items {
+		key := revision.Namespace + "/" + revision.Labels[serving.ServiceLabelKey]
+		revisionsByService[key] = append(revisionsByService[key], revision)
+	}
+
+	w := printers.NewTabWriter(out)
+	if !printFlags.NoHeaders {
+		if printFlags.WithNamespace {
+			fmt.Fprint(w, "NAMESPACE\t")
+		}
+		fmt.Fprintln(w, "NAME\tTRAFFIC\tTAGS\tREADY\tAGE")
+	}
+	for _, service := range serviceList.Items {
+		if printFlags.WithNamespace {
+			fmt.Fprintf(w, "%s\t", service.Namespace)
+		}
+		fmt.Fprintf(w, "%s\t\t\t%s\t%s\n", service.Name,
+			commands.ReadyCondition(service.Status.Conditions),
+			commands.TranslateTimestampSince(service.CreationTimestamp))
+
+		revisions := revisionsByService[service.Namespace+"/"+service.Name]
+		for i, revision := range revisions {
+			branch := "├── "
+			if i == len(revisions)-1 {
+				branch = "└── "
+			}
+			traffic := ""
+			percent, tags := revisionTrafficAndTags(revision.Name, &service)
+			if percent > 0 {
+				traffic = fmt.Sprintf("%d%%", percent)
+			}
+			if printFlags.WithNamespace {
+				fmt.Fprintf(w, "%s\t", service.Namespace)
+			}
+			fmt.Fprintf(w, "%s%s\t%s\t%s\t%s\t%s\n", branch, revision.Name, traffic, strings.Join(tags, ","),
+				commands.ReadyCondition(revision.Status.Conditions),
+				commands.TranslateTimestampSince(revision.CreationTimestamp))
+		}
+	}
+	return w.Flush()
+}
+
+// revisionTrafficAndTags sums up the traffic routed to a revision and collects its tags
+func revisionTrafficAndTags(revision string, service *servingv1.Service) (int64, []string) {
+	var percent int64
+	var tags []string
+	for _, target := range service.Status.Traffic {
+		if target.RevisionName != revision {
+			continue
+		}
+		if target.Percent != nil {
+			percent += *target.Percent
+		}
+		if target.Tag != "" {
+			tags = append(tags, target.Tag)
+		}
+	}
+	return percent, tags
+}
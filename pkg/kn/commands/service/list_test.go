@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clienttesting "k8s.io/client-go/testing"
@@ -165,6 +166,72 @@ func TestServiceListWithAlias(t *testing.T) {
 	assert.Check(t, util.ContainsAll(output[3], "sss", "sss.default.example.com", "sss-xyz"))
 }
 
+func TestServiceListWithOwner(t *testing.T) {
+	service := createMockServiceWithParams("foo", "default", "http://foo.default.example.com", "foo-xyz")
+	service.Labels = map[string]string{"owner.client.knative.dev/team": "payments"}
+	serviceList := &servingv1.ServiceList{Items: []servingv1.Service{*service}}
+	action, output, err := fakeServiceList([]string{"service", "list", "--owner", "team=payments"}, serviceList)
+	assert.NilError(t, err)
+	assert.Assert(t, action.Matches("list", "services"))
+	listAction := action.(clienttesting.ListAction)
+	assert.Equal(t, listAction.GetListRestrictions().Labels.String(), "owner.client.knative.dev/team=payments")
+	assert.Check(t, util.ContainsAll(output[1], "foo"))
+
+	_, _, err = fakeServiceList([]string{"service", "list", "--owner", "team"}, serviceList)
+	assert.ErrorContains(t, err, "Invalid --owner")
+}
+
+func TestServiceListWithSelector(t *testing.T) {
+	service := createMockServiceWithParams("foo", "default", "http://foo.default.example.com", "foo-xyz")
+	serviceList := &servingv1.ServiceList{Items: []servingv1.Service{*service}}
+	action, _, err := fakeServiceList([]string{"service", "list", "-l", "tier=frontend"}, serviceList)
+	assert.NilError(t, err)
+	listAction := action.(clienttesting.ListAction)
+	assert.Equal(t, listAction.GetListRestrictions().Labels.String(), "tier=frontend")
+
+	_, _, err = fakeServiceList([]string{"service", "list", "-l", "tier in (a,b)"}, serviceList)
+	assert.ErrorContains(t, err, "invalid selector")
+}
+
+func TestServiceListWide(t *testing.T) {
+	service := createMockServiceWithParams("foo", "default", "http://foo.default.example.com", "foo-xyz")
+	service.Status.LatestCreatedRevisionName = "foo-abc"
+	service.Spec.Template.Spec.Containers = []corev1.Container{{Image: "gcr.io/foo/bar:v2"}}
+	serviceList := &servingv1.ServiceList{Items: []servingv1.Service{*service}}
+	_, output, err := fakeServiceList([]string{"service", "list", "-o", "wide"}, serviceList)
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsAll(output[0], "NAME", "URL", "LATEST", "READY", "LATEST CREATED", "IMAGE"))
+	assert.Check(t, util.ContainsAll(output[1], "foo", "foo-xyz", "foo-abc", "gcr.io/foo/bar:v2"))
+
+	_, output, err = fakeServiceList([]string{"service", "list"}, serviceList)
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsNone(output[0], "LATEST CREATED", "IMAGE"))
+}
+
+func TestServiceListCustomColumnsSorted(t *testing.T) {
+	serviceList := &servingv1.ServiceList{}
+	for _, name := range []string{"a", "b", "c"} {
+		service := createMockServiceWithParams(name, "default", "http://"+name+".default.example.com", name+"-xyz")
+		serviceList.Items = append(serviceList.Items, *service)
+	}
+	serviceList.Items[0].Generation = 3
+	serviceList.Items[1].Generation = 10
+	serviceList.Items[2].Generation = 2
+
+	_, output, err := fakeServiceList([]string{"service", "list", "-o", "custom-columns=NAME:.metadata.name,GEN:.metadata.generation,URL:.status.url,TAGS:.metadata.labels",
+		"--sort-by", ".metadata.generation"}, serviceList)
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsAll(output[0], "NAME", "GEN", "URL", "TAGS"))
+	assert.Check(t, util.ContainsAll(output[1], "c", "2", "http://c.default.example.com", "<none>"))
+	assert.Check(t, util.ContainsAll(output[2], "a", "3"))
+	assert.Check(t, util.ContainsAll(output[3], "b", "10"))
+
+	_, _, err = fakeServiceList([]string{"service", "list", "-o", "custom-columns=NAME"}, serviceList)
+	assert.ErrorContains(t, err, "invalid custom column 'NAME'")
+	_, _, err = fakeServiceList([]string{"service", "list", "--sort-by", "{.metadata"}, serviceList)
+	assert.ErrorContains(t, err, "invalid JSONPath expression")
+}
+
 func createMockServiceWithParams(name, namespace, urlS string, revision string) *servingv1.Service {
 	url, _ := apis.ParseURL(urlS)
 	service := &servingv1.Service{
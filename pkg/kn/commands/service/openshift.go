@@ -0,0 +1,86 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+)
+
+// Prefix for image references which point to an OpenShift ImageStreamTag
+const imageStreamTagPrefix = "istag:"
+
+var imageStreamTagGVR = schema.GroupVersionResource{
+	Group:    "image.openshift.io",
+	Version:  "v1",
+	Resource: "imagestreamtags",
+}
+
+// resolveImageStreamTags replaces image references of the form "istag:[namespace/]name:tag"
+// in the given template with the image reference of the OpenShift ImageStreamTag.
+// The cluster is only contacted if such a reference is used.
+func resolveImageStreamTags(p *commands.KnParams, namespace string, template *servingv1.RevisionTemplateSpec) error {
+	for i := range template.Spec.Containers {
+		container := &template.Spec.Containers[i]
+		if !strings.HasPrefix(container.Image, imageStreamTagPrefix) {
+			continue
+		}
+		tagNamespace, tagName, err := parseImageStreamTag(container.Image, namespace)
+		if err != nil {
+			return err
+		}
+		dynamicClient, err := p.NewDynamicClient(namespace)
+		if err != nil {
+			return err
+		}
+		imageStreamTag, err := dynamicClient.RawClient().Resource(imageStreamTagGVR).Namespace(tagNamespace).Get(context.TODO(), tagName, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return fmt.Errorf("cannot find image stream tag '%s' in namespace '%s' "+
+					"(image stream tags are only available on OpenShift): %v", tagName, tagNamespace, err)
+			}
+			return err
+		}
+		reference, found, err := unstructured.NestedString(imageStreamTag.Object, "image", "dockerImageReference")
+		if err != nil || !found || reference == "" {
+			return fmt.Errorf("image stream tag '%s' in namespace '%s' has no image reference", tagName, tagNamespace)
+		}
+		container.Image = reference
+	}
+	return nil
+}
+
+// parseImageStreamTag splits an "istag:" reference into namespace and "name:tag"
+func parseImageStreamTag(image string, defaultNamespace string) (string, string, error) {
+	ref := strings.TrimPrefix(image, imageStreamTagPrefix)
+	namespace := defaultNamespace
+	if parts := strings.SplitN(ref, "/", 2); len(parts) == 2 {
+		namespace, ref = parts[0], parts[1]
+	}
+	parts := strings.Split(ref, ":")
+	if namespace == "" || len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid image stream tag reference '%s', expected '%s[namespace/]name:tag'", image, imageStreamTagPrefix)
+	}
+	return namespace, ref, nil
+}
@@ -0,0 +1,153 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	clientdynamic "knative.dev/client/pkg/dynamic"
+	"knative.dev/client/pkg/kn/commands"
+	knflags "knative.dev/client/pkg/kn/flags"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+)
+
+func TestParseImageStreamTag(t *testing.T) {
+	for _, tc := range []struct {
+		image     string
+		namespace string
+		name      string
+		err       string
+	}{
+		{image: "istag:hello:latest", namespace: "default", name: "hello:latest"},
+		{image: "istag:images/hello:v1", namespace: "images", name: "hello:v1"},
+		{image: "istag:hello", err: "invalid image stream tag reference"},
+		{image: "istag:hello:", err: "invalid image stream tag reference"},
+		{image: "istag:/hello:v1", err: "invalid image stream tag reference"},
+	} {
+		namespace, name, err := parseImageStreamTag(tc.image, "default")
+		if tc.err != "" {
+			assert.ErrorContains(t, err, tc.err)
+			continue
+		}
+		assert.NilError(t, err)
+		assert.Equal(t, namespace, tc.namespace)
+		assert.Equal(t, name, tc.name)
+	}
+}
+
+func TestResolveImageStreamTags(t *testing.T) {
+	reference := "image-registry.openshift-image-registry.svc:5000/images/hello@sha256:deadbeef"
+	var requested string
+	p := paramsWithImageStreamTag(func(action clienttesting.Action) (bool, runtime.Object, error) {
+		get := action.(clienttesting.GetAction)
+		requested = get.GetNamespace() + "/" + get.GetName()
+		return true, &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "image.openshift.io/v1",
+			"kind":       "ImageStreamTag",
+			"image": map[string]interface{}{
+				"dockerImageReference": reference,
+			},
+		}}, nil
+	})
+
+	template := templateWithImage("istag:images/hello:v1")
+	err := resolveImageStreamTags(p, "default", template)
+	assert.NilError(t, err)
+	assert.Equal(t, requested, "images/hello:v1")
+	assert.Equal(t, template.Spec.Containers[0].Image, reference)
+}
+
+func TestResolveImageStreamTagsNotFound(t *testing.T) {
+	p := paramsWithImageStreamTag(func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewNotFound(imageStreamTagGVR.GroupResource(), "hello:v1")
+	})
+
+	err := resolveImageStreamTags(p, "default", templateWithImage("istag:hello:v1"))
+	assert.ErrorContains(t, err, "only available on OpenShift")
+}
+
+func TestResolveImageStreamTagsPlainImage(t *testing.T) {
+	// No dynamic client must be needed when no image stream tag is referenced
+	p := &commands.KnParams{}
+	template := templateWithImage("gcr.io/foo/bar:baz")
+	err := resolveImageStreamTags(p, "default", template)
+	assert.NilError(t, err)
+	assert.Equal(t, template.Spec.Containers[0].Image, "gcr.io/foo/bar:baz")
+}
+
+func TestServiceApplyResolvesImageStreamTag(t *testing.T) {
+	reference := "image-registry.openshift-image-registry.svc:5000/default/hello@sha256:deadbeef"
+	p := paramsWithImageStreamTag(func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "image.openshift.io/v1",
+			"kind":       "ImageStreamTag",
+			"image": map[string]interface{}{
+				"dockerImageReference": reference,
+			},
+		}}, nil
+	})
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", nil, apierrors.NewNotFound(servingv1.Resource("service"), "foo"))
+	r.ApplyService(func(t *testing.T, a interface{}) {
+		svc := a.(*servingv1.Service)
+		assert.Equal(t, svc.Spec.Template.Spec.Containers[0].Image, reference)
+	}, true, nil)
+
+	output := new(bytes.Buffer)
+	p.Output = output
+	p.ClientConfig = blankConfig
+	p.NewServingClient = func(namespace string) (clientservingv1.KnServingClient, error) {
+		return client, nil
+	}
+	cmd := NewServiceCommand(p)
+	cmd.SetArgs([]string{"apply", "foo", "--image", "istag:hello:v1", "--no-wait"})
+	cmd.SetOutput(output)
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return knflags.ReconcileBoolFlags(cmd.Flags())
+	}
+	assert.NilError(t, cmd.Execute())
+
+	r.Validate()
+}
+
+func paramsWithImageStreamTag(reactor clienttesting.ReactionFunc) *commands.KnParams {
+	fakeDynamic := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	fakeDynamic.PrependReactor("get", "imagestreamtags", reactor)
+	return &commands.KnParams{
+		NewDynamicClient: func(namespace string) (clientdynamic.KnDynamicClient, error) {
+			return clientdynamic.NewKnDynamicClient(fakeDynamic, namespace), nil
+		},
+	}
+}
+
+func templateWithImage(image string) *servingv1.RevisionTemplateSpec {
+	template := &servingv1.RevisionTemplateSpec{}
+	template.Spec.Containers = []corev1.Container{{Image: image}}
+	return template
+}
@@ -0,0 +1,94 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"io"
+
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+)
+
+// serviceMutator changes a service in the cluster and returns false if there
+// was nothing to change
+type serviceMutator func(client clientservingv1.KnServingClient, service *servingv1.Service) (bool, error)
+
+// serviceOperation is a verb which changes a service, followed by the common
+// steps of waiting for the service and printing the outcome. New verbs are
+// added by defining another operation.
+type serviceOperation struct {
+	// Verbs for the progress messages, like "Creating" and "created"
+	doing string
+	done  string
+
+	mutate serviceMutator
+}
+
+var createOperation = serviceOperation{
+	doing: "Creating",
+	done:  "created",
+	mutate: func(client clientservingv1.KnServingClient, service *servingv1.Service) (bool, error) {
+		return true, client.CreateService(service)
+	},
+}
+
+// replaceOperation returns the operation which replaces an existing service,
+// retrying on conflicts according to the policy
+func replaceOperation(retryPolicy clientservingv1.RetryPolicy) serviceOperation {
+	return serviceOperation{
+		doing: "Replacing",
+		done:  "replaced",
+		mutate: func(client clientservingv1.KnServingClient, service *servingv1.Service) (bool, error) {
+			return true, prepareAndUpdateService(client, service, retryPolicy)
+		},
+	}
+}
+
+// createOrReplaceOperation returns the operation for 'service create', which
+// replaces the service if it exists already
+func createOrReplaceOperation(serviceExists bool, retryPolicy clientservingv1.RetryPolicy) serviceOperation {
+	if serviceExists {
+		return replaceOperation(retryPolicy)
+	}
+	return createOperation
+}
+
+// applyOperation returns the operation for 'service apply' with the verbs
+// describing whether the service is created or updated
+func applyOperation(doing string, done string) serviceOperation {
+	return serviceOperation{
+		doing: doing,
+		done:  done,
+		mutate: func(client clientservingv1.KnServingClient, service *servingv1.Service) (bool, error) {
+			return client.ApplyService(service)
+		},
+	}
+}
+
+// run changes the service and waits for it to become ready if requested
+func (o serviceOperation) run(client clientservingv1.KnServingClient, service *servingv1.Service, waitFlags commands.WaitFlags, out io.Writer) error {
+	changed, err := o.mutate(client, service)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		fmt.Fprintf(out, "No changes to apply to service '%s'.\n", service.Name)
+		return showUrl(client, service.Name, "unchanged", "", out)
+	}
+	return waitIfRequested(client, service.Name, waitFlags, o.doing, o.done, out)
+}
@@ -0,0 +1,63 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"gotest.tools/assert"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+)
+
+func TestServiceOperationRun(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	noWait := commands.WaitFlags{Wait: false}
+
+	scale := serviceOperation{
+		doing: "Scaling",
+		done:  "scaled",
+		mutate: func(client clientservingv1.KnServingClient, service *servingv1.Service) (bool, error) {
+			return service.Name != "unchanged", nil
+		},
+	}
+	out := new(bytes.Buffer)
+	assert.NilError(t, scale.run(client, getService("foo"), noWait, out))
+	assert.Equal(t, out.String(), "Service 'foo' scaled in namespace 'default'.\n")
+
+	r.GetService("unchanged", getServiceWithUrl("unchanged", "http://unchanged.example.com"), nil)
+	out.Reset()
+	assert.NilError(t, scale.run(client, getService("unchanged"), noWait, out))
+	assert.Assert(t, util.ContainsAll(out.String(), "No changes to apply", "http://unchanged.example.com"))
+
+	failing := serviceOperation{
+		mutate: func(client clientservingv1.KnServingClient, service *servingv1.Service) (bool, error) {
+			return false, errors.New("boom")
+		},
+	}
+	assert.ErrorContains(t, failing.run(client, getService("foo"), noWait, out), "boom")
+	r.Validate()
+}
+
+func TestCreateOrReplaceOperation(t *testing.T) {
+	assert.Equal(t, createOrReplaceOperation(false, clientservingv1.DefaultRetryPolicy).done, "created")
+	assert.Equal(t, createOrReplaceOperation(true, clientservingv1.DefaultRetryPolicy).done, "replaced")
+}
@@ -0,0 +1,87 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+)
+
+// outputFlags holds the --output flag of the commands changing a service.
+// When given, the resulting service is printed to stdout in the requested
+// format and all progress messages go to stderr, so that automation doesn't
+// have to parse them.
+type outputFlags struct {
+	printFlags *genericclioptions.PrintFlags
+}
+
+func (o *outputFlags) add(cmd *cobra.Command, what string) {
+	o.printFlags = genericclioptions.NewPrintFlags("")
+	o.printFlags.AddFlags(cmd)
+	cmd.Flag("output").Usage = fmt.Sprintf("Print the %s service in the given format instead of progress messages, which are written to stderr then. One of: %s.",
+		what, strings.Join(append(o.printFlags.AllowedFormats(), "url"), "|"))
+}
+
+// enabled returns true if an output format was requested
+func (o *outputFlags) enabled() bool {
+	return o.printFlags != nil && o.printFlags.OutputFlagSpecified()
+}
+
+// validate checks the requested format before the service is changed
+func (o *outputFlags) validate() error {
+	if !o.enabled() || strings.ToLower(*o.printFlags.OutputFormat) == "url" {
+		return nil
+	}
+	_, err := o.printFlags.ToPrinter()
+	return err
+}
+
+// progressOut returns the writer for human readable progress messages
+func (o *outputFlags) progressOut(cmd *cobra.Command) io.Writer {
+	if o.enabled() {
+		return cmd.ErrOrStderr()
+	}
+	return cmd.OutOrStdout()
+}
+
+// print writes the service in the requested format
+func (o *outputFlags) print(service *servingv1.Service, out io.Writer) error {
+	if strings.ToLower(*o.printFlags.OutputFormat) == "url" {
+		fmt.Fprintf(out, "%s\n", extractURL(service))
+		return nil
+	}
+	printer, err := o.printFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+	return printer.PrintObj(service, out)
+}
+
+// printService fetches the service after it has been changed and prints it
+func printService(client clientservingv1.KnServingClient, name string, o *outputFlags, out io.Writer) error {
+	service, err := client.GetService(name)
+	if err != nil {
+		return err
+	}
+	return o.print(service, out)
+}
@@ -0,0 +1,142 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	knflags "knative.dev/client/pkg/kn/flags"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+	"knative.dev/client/pkg/util/mock"
+	"knative.dev/client/pkg/wait"
+)
+
+// executeServiceCommandSplitOutput returns stdout and stderr separately
+func executeServiceCommandSplitOutput(client clientservingv1.KnServingClient, args ...string) (string, string, error) {
+	knParams := &commands.KnParams{}
+	knParams.ClientConfig = blankConfig
+	knParams.NewServingClient = func(namespace string) (clientservingv1.KnServingClient, error) {
+		return client, nil
+	}
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	cmd := NewServiceCommand(knParams)
+	cmd.SetArgs(args)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	// Errors and usage are printed by the root command
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return knflags.ReconcileBoolFlags(cmd.Flags())
+	}
+	err := cmd.Execute()
+	return stdout.String(), stderr.String(), err
+}
+
+func serviceWithGvk(name string, url string) *servingv1.Service {
+	service := getServiceWithUrl(name, url)
+	service.APIVersion = "serving.knative.dev/v1"
+	service.Kind = "Service"
+	return service
+}
+
+func TestServiceCreateOutputMock(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", nil, apierrors.NewNotFound(servingv1.Resource("service"), "foo"))
+	r.CreateService(mock.Any(), nil)
+	r.WaitForService("foo", mock.Any(), wait.NoopMessageCallback(), nil, time.Second)
+	r.GetService("foo", serviceWithGvk("foo", "http://foo.example.com"), nil)
+	r.GetService("foo", serviceWithGvk("foo", "http://foo.example.com"), nil)
+
+	stdout, stderr, err := executeServiceCommandSplitOutput(client, "create", "foo", "--image", "gcr.io/foo/bar:baz", "-o", "yaml")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(stdout, "kind: Service", "name: foo", "url: http://foo.example.com"))
+	assert.Assert(t, util.ContainsNone(stdout, "Creating"))
+	assert.Assert(t, util.ContainsAll(stderr, "Creating", "foo"))
+	r.Validate()
+}
+
+func TestServiceCreateOutputURLMock(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", nil, apierrors.NewNotFound(servingv1.Resource("service"), "foo"))
+	r.CreateService(mock.Any(), nil)
+	r.GetService("foo", serviceWithGvk("foo", "http://foo.example.com"), nil)
+
+	stdout, _, err := executeServiceCommandSplitOutput(client, "create", "foo", "--image", "gcr.io/foo/bar:baz", "--no-wait", "-o", "url")
+	assert.NilError(t, err)
+	assert.Equal(t, stdout, "http://foo.example.com\n")
+	r.Validate()
+}
+
+func TestServiceCreateOutputInvalidFormatMock(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+
+	_, _, err := executeServiceCommandSplitOutput(client, "create", "foo", "--image", "gcr.io/foo/bar:baz", "-o", "table")
+	assert.ErrorContains(t, err, "table")
+	r.Validate()
+}
+
+func TestServiceUpdateOutputMock(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", serviceWithGvk("foo", "http://foo.example.com"), nil)
+	r.UpdateService(mock.Any(), nil)
+	r.GetService("foo", serviceWithGvk("foo", "http://foo.example.com"), nil)
+
+	stdout, stderr, err := executeServiceCommandSplitOutput(client, "update", "foo", "--env", "a=b", "--no-wait", "-o", "name")
+	assert.NilError(t, err)
+	assert.Equal(t, stdout, "service.serving.knative.dev/foo\n")
+	assert.Assert(t, util.ContainsAll(stderr, "updated", "foo"))
+	r.Validate()
+}
+
+func TestServiceDeleteOutputMock(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", serviceWithGvk("foo", "http://foo.example.com"), nil)
+	r.DeleteService("foo", mock.Any(), nil)
+	r.GetService("bar", nil, apierrors.NewNotFound(servingv1.Resource("service"), "bar"))
+
+	stdout, stderr, err := executeServiceCommandSplitOutput(client, "delete", "foo", "bar", "-o", "name")
+	assert.ErrorContains(t, err, "bar")
+	assert.Equal(t, stdout, "service.serving.knative.dev/foo\n")
+	assert.Assert(t, util.ContainsAll(stderr, "foo", "deleted"))
+	r.Validate()
+}
+
+func TestServiceDeleteOutputErrorMock(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", serviceWithGvk("foo", ""), nil)
+	r.DeleteService("foo", mock.Any(), errors.New("forbidden"))
+
+	stdout, _, err := executeServiceCommandSplitOutput(client, "delete", "foo", "-o", "json")
+	assert.ErrorContains(t, err, "forbidden")
+	assert.Equal(t, stdout, "")
+	r.Validate()
+}
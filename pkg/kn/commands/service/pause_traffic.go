@@ -0,0 +1,177 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"knative.dev/pkg/ptr"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+)
+
+// PausedTrafficAnnotationKey holds the traffic block of a service while its traffic is paused
+const PausedTrafficAnnotationKey = "client.knative.dev/paused-traffic"
+
+var pauseTrafficExample = `
+  # Route all traffic of service 'svc' to the maintenance revision 'svc-maintenance'
+  kn service pause-traffic svc --to svc-maintenance
+
+  # Restore the traffic of service 'svc' as it was before pausing
+  kn service resume-traffic svc`
+
+// NewServicePauseTrafficCommand returns a new command for routing all traffic to a maintenance revision
+func NewServicePauseTrafficCommand(p *commands.KnParams) *cobra.Command {
+	var maintenanceRevision string
+
+	command := &cobra.Command{
+		Use:   "pause-traffic NAME --to REVISION",
+		Short: "Route all traffic of a service to a maintenance revision",
+		Long: "Route all traffic of a service to a maintenance revision, e.g. one serving a static maintenance page. " +
+			"The current traffic split is saved in the annotation '" + PausedTrafficAnnotationKey + "' " +
+			"and can be restored with 'kn service resume-traffic'. Tags of the current traffic targets are kept.",
+		Example: pauseTrafficExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("'service pause-traffic' requires the service name given as single argument")
+			}
+			if maintenanceRevision == "" {
+				return errors.New("'service pause-traffic' requires the maintenance revision given with --to")
+			}
+			name := args[0]
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			retryPolicy, err := p.RetryPolicy(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := p.NewServingClient(namespace)
+			if err != nil {
+				return err
+			}
+			_, err = client.GetRevision(maintenanceRevision)
+			if err != nil {
+				return err
+			}
+
+			err = client.UpdateServiceWithRetry(name, func(service *servingv1.Service) (*servingv1.Service, error) {
+				return service, pauseTraffic(service, maintenanceRevision)
+			}, retryPolicy)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Traffic of service '%s' in namespace '%s' paused, all requests are routed to revision '%s'.\n",
+				name, namespace, maintenanceRevision)
+			return nil
+		},
+	}
+	commands.AddNamespaceFlags(command.Flags(), false)
+	command.Flags().StringVar(&maintenanceRevision, "to", "", "Revision which receives all traffic while the service is paused.")
+	return command
+}
+
+// NewServiceResumeTrafficCommand returns a new command for restoring the traffic saved by pause-traffic
+func NewServiceResumeTrafficCommand(p *commands.KnParams) *cobra.Command {
+	command := &cobra.Command{
+		Use:     "resume-traffic NAME",
+		Aliases: []string{"resume"},
+		Short:   "Restore the traffic of a service paused with 'kn service pause-traffic'",
+		Example: pauseTrafficExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("'service resume-traffic' requires the service name given as single argument")
+			}
+			name := args[0]
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			retryPolicy, err := p.RetryPolicy(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := p.NewServingClient(namespace)
+			if err != nil {
+				return err
+			}
+
+			err = client.UpdateServiceWithRetry(name, func(service *servingv1.Service) (*servingv1.Service, error) {
+				return service, resumeTraffic(service)
+			}, retryPolicy)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Traffic of service '%s' in namespace '%s' resumed.\n", name, namespace)
+			return nil
+		},
+	}
+	commands.AddNamespaceFlags(command.Flags(), false)
+	return command
+}
+
+// pauseTraffic saves the traffic block of the service and routes all traffic to the given revision
+func pauseTraffic(service *servingv1.Service, maintenanceRevision string) error {
+	if _, paused := service.Annotations[PausedTrafficAnnotationKey]; paused {
+		return fmt.Errorf("traffic of service '%s' is already paused, use 'kn service resume-traffic' first", service.Name)
+	}
+	saved, err := json.Marshal(service.Spec.Traffic)
+	if err != nil {
+		return err
+	}
+
+	traffic := []servingv1.TrafficTarget{{
+		RevisionName:   maintenanceRevision,
+		LatestRevision: ptr.Bool(false),
+		Percent:        ptr.Int64(100),
+	}}
+	// Keep tagged targets so that their URLs stay available
+	for _, target := range service.Spec.Traffic {
+		if target.Tag != "" {
+			target.Percent = ptr.Int64(0)
+			traffic = append(traffic, target)
+		}
+	}
+
+	if service.Annotations == nil {
+		service.Annotations = map[string]string{}
+	}
+	service.Annotations[PausedTrafficAnnotationKey] = string(saved)
+	service.Spec.Traffic = traffic
+	return nil
+}
+
+// resumeTraffic restores the traffic block saved by pauseTraffic
+func resumeTraffic(service *servingv1.Service) error {
+	saved, paused := service.Annotations[PausedTrafficAnnotationKey]
+	if !paused {
+		return fmt.Errorf("traffic of service '%s' is not paused", service.Name)
+	}
+	var traffic []servingv1.TrafficTarget
+	err := json.Unmarshal([]byte(saved), &traffic)
+	if err != nil {
+		return fmt.Errorf("cannot restore traffic of service '%s' from annotation '%s': %v", service.Name, PausedTrafficAnnotationKey, err)
+	}
+	service.Spec.Traffic = traffic
+	delete(service.Annotations, PausedTrafficAnnotationKey)
+	return nil
+}
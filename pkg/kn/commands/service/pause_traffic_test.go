@@ -0,0 +1,81 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	"knative.dev/pkg/ptr"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+)
+
+func TestServicePauseAndResumeTraffic(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+
+	service := getService("foo")
+	service.Spec.Traffic = []servingv1.TrafficTarget{
+		{RevisionName: "foo-v1", Percent: ptr.Int64(80)},
+		{RevisionName: "foo-v2", Percent: ptr.Int64(20), Tag: "candidate"},
+	}
+	r.GetRevision("foo-maintenance", &servingv1.Revision{}, nil)
+	r.GetService("foo", service, nil)
+	r.UpdateService(func(t *testing.T, paused *servingv1.Service) {
+		assert.DeepEqual(t, paused.Spec.Traffic, []servingv1.TrafficTarget{
+			{RevisionName: "foo-maintenance", LatestRevision: ptr.Bool(false), Percent: ptr.Int64(100)},
+			{RevisionName: "foo-v2", Percent: ptr.Int64(0), Tag: "candidate"},
+		})
+		assert.Assert(t, paused.Annotations[PausedTrafficAnnotationKey] != "")
+		service = paused
+	}, nil)
+
+	output, err := executeServiceCommand(client, "pause-traffic", "foo", "--to", "foo-maintenance")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "paused", "foo-maintenance"))
+	r.Validate()
+
+	r.GetService("foo", service, nil)
+	r.UpdateService(func(t *testing.T, resumed *servingv1.Service) {
+		assert.DeepEqual(t, resumed.Spec.Traffic, []servingv1.TrafficTarget{
+			{RevisionName: "foo-v1", Percent: ptr.Int64(80)},
+			{RevisionName: "foo-v2", Percent: ptr.Int64(20), Tag: "candidate"},
+		})
+		_, found := resumed.Annotations[PausedTrafficAnnotationKey]
+		assert.Assert(t, !found)
+	}, nil)
+
+	output, err = executeServiceCommand(client, "resume", "foo")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "resumed"))
+	r.Validate()
+}
+
+func TestServicePauseTrafficErrors(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	_, err := executeServiceCommand(client, "pause-traffic", "foo")
+	assert.ErrorContains(t, err, "requires the maintenance revision")
+
+	service := getService("foo")
+	assert.NilError(t, pauseTraffic(service, "foo-maintenance"))
+	err = pauseTraffic(service, "foo-maintenance")
+	assert.ErrorContains(t, err, "already paused")
+
+	err = resumeTraffic(getService("foo"))
+	assert.ErrorContains(t, err, "is not paused")
+}
@@ -0,0 +1,99 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/registry"
+)
+
+// validatePlatform checks that the platform is given as OS/ARCH[/VARIANT] like "linux/arm64"
+func validatePlatform(platform string) error {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return fmt.Errorf("invalid --platform '%s', expected OS/ARCH[/VARIANT] like 'linux/arm64'", platform)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return fmt.Errorf("invalid --platform '%s', expected OS/ARCH[/VARIANT] like 'linux/arm64'", platform)
+		}
+	}
+	return nil
+}
+
+// pinImagesToPlatform pins the images of all containers to the digest of the platform's
+// manifest if a platform is given. For multi-platform image indexes this is the digest of
+// the image built for the platform instead of the digest of the index, so that no other
+// platform's image can be pulled. Images which are already pinned are resolved by their digest.
+func pinImagesToPlatform(p *commands.KnParams, template *servingv1.RevisionTemplateSpec, platform string, out io.Writer) error {
+	if platform == "" {
+		return nil
+	}
+	for i := range template.Spec.Containers {
+		container := &template.Spec.Containers[i]
+		digest, err := registry.PlatformDigest(container.Image, platform)
+		if err != nil {
+			return fmt.Errorf("cannot get the digest of image '%s' for platform %s: %v", container.Image, platform, err)
+		}
+		container.Image = imageRepository(container.Image) + "@" + digest
+	}
+	printPlatformWarnings(p, platform, out)
+	return nil
+}
+
+// printPlatformWarnings warns if none or not all of the cluster's nodes run on the platform
+// the images are pinned to, because pods scheduled to those nodes fail with an
+// 'exec format error'. Nothing is printed when the nodes cannot be listed, as listing them
+// requires cluster-wide permissions which many users don't have.
+func printPlatformWarnings(p *commands.KnParams, platform string, out io.Writer) {
+	kubeClient, err := p.NewKubeClient()
+	if err != nil {
+		return
+	}
+	nodes, err := kubeClient.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil || len(nodes.Items) == 0 {
+		return
+	}
+	matching := 0
+	for _, node := range nodes.Items {
+		if nodeMatchesPlatform(&node, platform) {
+			matching++
+		}
+	}
+	switch {
+	case matching == 0:
+		fmt.Fprintf(out, "Warning: None of the cluster's nodes runs on platform %s, "+
+			"the containers will fail with an 'exec format error'.\n", platform)
+	case matching < len(nodes.Items):
+		fmt.Fprintf(out, "Warning: Only %d of %d nodes of the cluster run on platform %s, "+
+			"containers scheduled to the other nodes will fail with an 'exec format error'.\n", matching, len(nodes.Items), platform)
+	}
+}
+
+// nodeMatchesPlatform compares the OS and architecture of the node with the platform,
+// the variant of the platform is not reported by nodes and is ignored
+func nodeMatchesPlatform(node *corev1.Node, platform string) bool {
+	parts := strings.Split(platform, "/")
+	return node.Status.NodeInfo.OperatingSystem == parts[0] && node.Status.NodeInfo.Architecture == parts[1]
+}
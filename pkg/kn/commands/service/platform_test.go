@@ -0,0 +1,144 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/registry"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+)
+
+const (
+	arm64Digest = "sha256:a64a64a64a64a64a64a64a64a64a64a64a64a64a64a64a64a64a64a64a64a64a"
+	indexDigest = "sha256:1d21d21d21d21d21d21d21d21d21d21d21d21d21d21d21d21d21d21d21d21d2"
+)
+
+func mockPlatformDigest(t *testing.T, digests map[string]string) func() {
+	oldPlatformDigest := registry.PlatformDigest
+	registry.PlatformDigest = func(image string, platform string) (string, error) {
+		assert.Equal(t, platform, "linux/arm64")
+		digest, ok := digests[image]
+		if !ok {
+			return "", errors.New("MANIFEST_UNKNOWN")
+		}
+		return digest, nil
+	}
+	return func() { registry.PlatformDigest = oldPlatformDigest }
+}
+
+func newNode(name string, os string, arch string) *corev1.Node {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	node.Status.NodeInfo.OperatingSystem = os
+	node.Status.NodeInfo.Architecture = arch
+	return node
+}
+
+func TestServiceCreatePlatform(t *testing.T) {
+	defer mockPlatformDigest(t, map[string]string{"gcr.io/foo/bar:baz": arm64Digest})()
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", nil, apierrors.NewNotFound(servingv1.Resource("service"), "foo"))
+	r.CreateService(func(t *testing.T, a interface{}) {
+		template := a.(*servingv1.Service).Spec.Template
+		assert.Equal(t, template.Spec.Containers[0].Image, "gcr.io/foo/bar@"+arm64Digest)
+		assert.Equal(t, template.Annotations["client.knative.dev/user-image"], "gcr.io/foo/bar:baz")
+	}, nil)
+
+	nodes := []runtime.Object{newNode("n1", "linux", "arm64"), newNode("n2", "linux", "arm64")}
+	output, err := executeServiceQuotaCommand(client, nodes, "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--platform", "linux/arm64", "--no-wait")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsNone(output, "Warning:"))
+	assert.Assert(t, util.ContainsAll(output, "Service 'foo' created"))
+	r.Validate()
+}
+
+func TestServiceCreatePlatformNodeMismatch(t *testing.T) {
+	defer mockPlatformDigest(t, map[string]string{"gcr.io/foo/bar:baz": arm64Digest})()
+
+	for _, tc := range []struct {
+		nodes    []runtime.Object
+		expected string
+	}{{
+		nodes:    []runtime.Object{newNode("n1", "linux", "amd64")},
+		expected: "Warning: None of the cluster's nodes runs on platform linux/arm64",
+	}, {
+		nodes:    []runtime.Object{newNode("n1", "linux", "amd64"), newNode("n2", "linux", "arm64")},
+		expected: "Warning: Only 1 of 2 nodes of the cluster run on platform linux/arm64",
+	}} {
+		client := clientservingv1.NewMockKnServiceClient(t)
+		r := client.Recorder()
+		r.GetService("foo", nil, apierrors.NewNotFound(servingv1.Resource("service"), "foo"))
+		r.CreateService(func(t *testing.T, a interface{}) {}, nil)
+
+		output, err := executeServiceQuotaCommand(client, tc.nodes, "create", "foo", "--image", "gcr.io/foo/bar:baz",
+			"--platform", "linux/arm64", "--no-wait")
+		assert.NilError(t, err)
+		assert.Assert(t, util.ContainsAll(output, tc.expected, "exec format error", "Service 'foo' created"))
+		r.Validate()
+	}
+}
+
+func TestServiceUpdatePlatformPinsLockedImage(t *testing.T) {
+	defer mockPlatformDigest(t, map[string]string{"gcr.io/foo/bar@" + indexDigest: arm64Digest})()
+
+	service := newEmptyService()
+	service.Spec.Template.Spec.Containers = []corev1.Container{{Image: "gcr.io/foo/bar@" + indexDigest}}
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", service, nil)
+	r.UpdateService(func(t *testing.T, a interface{}) {
+		image := a.(*servingv1.Service).Spec.Template.Spec.Containers[0].Image
+		assert.Equal(t, image, "gcr.io/foo/bar@"+arm64Digest)
+	}, nil)
+
+	_, err := executeServiceQuotaCommand(client, nil, "update", "foo", "--platform", "linux/arm64", "--no-wait")
+	assert.NilError(t, err)
+	r.Validate()
+}
+
+func TestServiceCreatePlatformErrors(t *testing.T) {
+	defer mockPlatformDigest(t, map[string]string{})()
+
+	for _, tc := range []struct {
+		args     []string
+		expected string
+	}{{
+		args:     []string{"--platform", "linux/arm64", "--no-lock-to-digest"},
+		expected: "--platform can't be used with --no-lock-to-digest",
+	}, {
+		args:     []string{"--platform", "arm64"},
+		expected: "invalid --platform 'arm64'",
+	}, {
+		args:     []string{"--platform", "linux/arm64"},
+		expected: "cannot get the digest of image 'gcr.io/foo/bar:baz' for platform linux/arm64: MANIFEST_UNKNOWN",
+	}} {
+		client := clientservingv1.NewMockKnServiceClient(t)
+		_, err := executeServiceQuotaCommand(client, nil, append([]string{"create", "foo", "--image", "gcr.io/foo/bar:baz", "--no-wait"}, tc.args...)...)
+		assert.ErrorContains(t, err, tc.expected)
+	}
+}
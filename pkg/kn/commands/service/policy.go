@@ -0,0 +1,168 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/yaml"
+
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/kn/config"
+)
+
+// policyConfigMapKey is the data key of the policy ConfigMap holding the rules
+const policyConfigMapKey = "rules"
+
+// policyFlags holds the options for checking services against the guardrail policies
+type policyFlags struct {
+	skip bool
+}
+
+// add adds the policy flags to the given command
+func (f *policyFlags) add(command *cobra.Command) {
+	command.Flags().BoolVar(&f.skip, "policy-skip", false,
+		"Don't check the service against the policies of the kn configuration. "+
+			"Only possible if the configuration allows to skip the policies.")
+}
+
+// check checks the service against the rules of the kn configuration and of the
+// policy ConfigMap. All violations are reported together in the returned error.
+func (f *policyFlags) check(p *commands.KnParams, service *servingv1.Service, out io.Writer) error {
+	policies := config.GlobalConfig.Policies()
+	if len(policies.Rules) == 0 && policies.ConfigMap == "" {
+		return nil
+	}
+	if f.skip {
+		if !policies.AllowSkip {
+			return errors.New("--policy-skip is not allowed by the kn configuration")
+		}
+		fmt.Fprintf(out, "WARNING: Policies are not checked for service '%s'.\n", service.Name)
+		return nil
+	}
+
+	rules := policies.Rules
+	if policies.ConfigMap != "" {
+		configMapRules, err := loadPolicyConfigMap(p, policies.ConfigMap)
+		if err != nil {
+			return err
+		}
+		rules = append(append([]config.PolicyRule{}, rules...), configMapRules...)
+	}
+
+	violations, err := policyViolations(service, rules)
+	if err != nil {
+		return err
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return fmt.Errorf("service '%s' violates %d policy rule(s):\n  %s", service.Name, len(violations), strings.Join(violations, "\n  "))
+}
+
+// loadPolicyConfigMap reads the rules from the ConfigMap given as "namespace/name"
+func loadPolicyConfigMap(p *commands.KnParams, ref string) ([]config.PolicyRule, error) {
+	parts := strings.SplitN(ref, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid policy ConfigMap '%s' in the kn configuration, expected 'namespace/name'", ref)
+	}
+	kubeClient, err := p.NewKubeClient()
+	if err != nil {
+		return nil, err
+	}
+	configMap, err := kubeClient.CoreV1().ConfigMaps(parts[0]).Get(context.TODO(), parts[1], metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot read policies from ConfigMap '%s': %v", ref, err)
+	}
+	var rules []config.PolicyRule
+	err = yaml.Unmarshal([]byte(configMap.Data[policyConfigMapKey]), &rules)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse policies in ConfigMap '%s': %v", ref, err)
+	}
+	err = config.ValidatePolicyRules(rules)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policies in ConfigMap '%s': %v", ref, err)
+	}
+	return rules, nil
+}
+
+// policyViolations evaluates the rules against the service and describes every violation
+func policyViolations(service *servingv1.Service, rules []config.PolicyRule) ([]string, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(service)
+	if err != nil {
+		return nil, err
+	}
+	var violations []string
+	for _, rule := range rules {
+		values, err := policyValues(obj, rule)
+		if err != nil {
+			return nil, err
+		}
+		message := rule.Message
+		if message == "" {
+			message = "path " + rule.Path
+		}
+		if rule.Required && len(values) == 0 {
+			violations = append(violations, fmt.Sprintf("%s: %s (no value)", rule.Name, message))
+			continue
+		}
+		if rule.Pattern == "" {
+			continue
+		}
+		// Patterns have been validated when loading the rules
+		pattern := regexp.MustCompile(rule.Pattern)
+		for _, value := range values {
+			if !pattern.MatchString(value) {
+				violations = append(violations, fmt.Sprintf("%s: %s (value '%s')", rule.Name, message, value))
+			}
+		}
+	}
+	return violations, nil
+}
+
+// policyValues returns the non-empty values the path of the rule selects
+func policyValues(obj map[string]interface{}, rule config.PolicyRule) ([]string, error) {
+	jp := jsonpath.New(rule.Name).AllowMissingKeys(true)
+	err := jp.Parse(rule.Path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path of policy rule '%s': %v", rule.Name, err)
+	}
+	results, err := jp.FindResults(obj)
+	if err != nil {
+		return nil, fmt.Errorf("cannot evaluate policy rule '%s': %v", rule.Name, err)
+	}
+	var values []string
+	for _, result := range results {
+		for _, value := range result {
+			if !value.IsValid() || (value.CanInterface() && value.Interface() == nil) {
+				continue
+			}
+			if s := fmt.Sprint(value.Interface()); s != "" {
+				values = append(values, s)
+			}
+		}
+	}
+	return values, nil
+}
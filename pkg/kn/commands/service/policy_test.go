@@ -0,0 +1,136 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/kn/config"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+)
+
+var testPolicyRules = []config.PolicyRule{
+	{
+		Name:    "corp-registry",
+		Path:    "{.spec.template.spec.containers[*].image}",
+		Pattern: `^registry\.corp/`,
+		Message: "images must come from registry.corp",
+	},
+	{
+		Name:     "memory-limit",
+		Path:     "{.spec.template.spec.containers[*].resources.limits.memory}",
+		Required: true,
+		Message:  "memory limit required",
+	},
+}
+
+func TestPolicyViolations(t *testing.T) {
+	service := newPolicyTestService("docker.io/foo", "")
+	violations, err := policyViolations(service, testPolicyRules)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, violations, []string{
+		"corp-registry: images must come from registry.corp (value 'docker.io/foo')",
+		"memory-limit: memory limit required (no value)",
+	})
+
+	service = newPolicyTestService("registry.corp/foo", "256Mi")
+	violations, err = policyViolations(service, testPolicyRules)
+	assert.NilError(t, err)
+	assert.Equal(t, len(violations), 0)
+}
+
+func TestPolicyCheck(t *testing.T) {
+	defer setPolicies(config.Policies{Rules: testPolicyRules})()
+
+	out := &bytes.Buffer{}
+	err := (&policyFlags{}).check(&commands.KnParams{}, newPolicyTestService("docker.io/foo", "256Mi"), out)
+	assert.Error(t, err, "service 'foo' violates 1 policy rule(s):\n"+
+		"  corp-registry: images must come from registry.corp (value 'docker.io/foo')")
+
+	err = (&policyFlags{skip: true}).check(&commands.KnParams{}, newPolicyTestService("docker.io/foo", "256Mi"), out)
+	assert.Error(t, err, "--policy-skip is not allowed by the kn configuration")
+}
+
+func TestPolicyCheckSkip(t *testing.T) {
+	defer setPolicies(config.Policies{Rules: testPolicyRules, AllowSkip: true})()
+
+	out := &bytes.Buffer{}
+	err := (&policyFlags{skip: true}).check(&commands.KnParams{}, newPolicyTestService("docker.io/foo", ""), out)
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(out.String(), "WARNING", "Policies are not checked", "'foo'"))
+}
+
+func TestPolicyCheckConfigMap(t *testing.T) {
+	defer setPolicies(config.Policies{ConfigMap: "kn-admin/kn-policies"})()
+
+	kubeClient := commands.NewFakeKubeClient(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "kn-policies", Namespace: "kn-admin"},
+		Data: map[string]string{"rules": `
+- name: corp-registry
+  path: "{.spec.template.spec.containers[*].image}"
+  pattern: ^registry\.corp/
+`},
+	})
+	p := &commands.KnParams{NewKubeClient: func() (kubernetes.Interface, error) { return kubeClient, nil }}
+
+	err := (&policyFlags{}).check(p, newPolicyTestService("registry.corp/foo", ""), &bytes.Buffer{})
+	assert.NilError(t, err)
+	err = (&policyFlags{}).check(p, newPolicyTestService("docker.io/foo", ""), &bytes.Buffer{})
+	assert.ErrorContains(t, err, "corp-registry: path {.spec.template.spec.containers[*].image} (value 'docker.io/foo')")
+
+	defer setPolicies(config.Policies{ConfigMap: "kn-admin/missing"})()
+	err = (&policyFlags{}).check(p, newPolicyTestService("registry.corp/foo", ""), &bytes.Buffer{})
+	assert.ErrorContains(t, err, "cannot read policies from ConfigMap 'kn-admin/missing'")
+}
+
+func TestServiceCreatePolicyViolation(t *testing.T) {
+	defer setPolicies(config.Policies{Rules: testPolicyRules})()
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	// No service must be looked up or created
+	output, err := executeServiceCommand(client, "create", "foo", "--image", "docker.io/foo", "--limit", "memory=256Mi")
+	assert.ErrorContains(t, err, "violates 1 policy rule(s)")
+	assert.Assert(t, util.ContainsAll(output, "corp-registry", "docker.io/foo"))
+
+	client.Recorder().Validate()
+}
+
+func newPolicyTestService(image string, memoryLimit string) *servingv1.Service {
+	service := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"}}
+	container := corev1.Container{Image: image}
+	if memoryLimit != "" {
+		container.Resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memoryLimit)}
+	}
+	service.Spec.Template.Spec.Containers = []corev1.Container{container}
+	return service
+}
+
+func setPolicies(policies config.Policies) func() {
+	oldConfig := config.GlobalConfig
+	config.GlobalConfig = config.TestConfig{TestPolicies: policies}
+	return func() {
+		config.GlobalConfig = oldConfig
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	clientserving "knative.dev/client/pkg/serving"
+)
+
+// Requests of the queue-proxy sidecar which Knative Serving adds to every pod
+// (queueSidecarCPURequest of config-deployment, with its default value)
+var queueProxyRequests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("25m")}
+
+// Resources which are checked against limit ranges and quotas
+var quotaResourceNames = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+// Effective resources of a container or a pod after Kubernetes applied the defaults
+type podResources struct {
+	requests corev1.ResourceList
+	limits   corev1.ResourceList
+
+	// Resources for which at least one container has no request or no limit
+	missingRequests map[corev1.ResourceName]bool
+	missingLimits   map[corev1.ResourceName]bool
+}
+
+// checkQuota returns warnings for each limit range and resource quota of the namespace
+// which the pods of the revision template won't fit in. The queue-proxy sidecar and
+// the pods started for the min-scale of the revision are taken into account.
+func checkQuota(p *commands.KnParams, namespace string, template *servingv1.RevisionTemplateSpec) ([]string, error) {
+	kubeClient, err := p.NewKubeClient()
+	if err != nil {
+		return nil, err
+	}
+	limitRanges, err := kubeClient.CoreV1().LimitRanges(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	quotas, err := kubeClient.CoreV1().ResourceQuotas(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	container, err := clientserving.ContainerOfRevisionSpec(&template.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	// Knative Serving uses this name if none is given
+	containerName := container.Name
+	if containerName == "" {
+		containerName = "user-container"
+	}
+	userContainer := containerResources(container.Resources, limitRanges.Items)
+	queueProxy := containerResources(corev1.ResourceRequirements{Requests: queueProxyRequests}, limitRanges.Items)
+	var warnings []string
+	warnings = append(warnings, checkLimitRanges(limitRanges.Items, corev1.LimitTypeContainer, "container '"+containerName+"'", userContainer)...)
+	warnings = append(warnings, checkLimitRanges(limitRanges.Items, corev1.LimitTypeContainer, "container '"+queueProxyContainerName+"'", queueProxy)...)
+	pod := addResources(userContainer, queueProxy)
+	warnings = append(warnings, checkLimitRanges(limitRanges.Items, corev1.LimitTypePod, "pod", pod)...)
+
+	replicas := 1
+	scaling, err := clientserving.ScalingInfo(&template.ObjectMeta)
+	if err != nil {
+		return nil, err
+	}
+	if scaling.Min != nil && *scaling.Min > replicas {
+		replicas = *scaling.Min
+	}
+	warnings = append(warnings, checkResourceQuotas(quotas.Items, pod, replicas)...)
+	return warnings, nil
+}
+
+// printQuotaWarnings prints a warning for every quota or limit range the pods of
+// the template don't fit in. Failing to check is only a warning, too.
+func printQuotaWarnings(p *commands.KnParams, namespace string, template *servingv1.RevisionTemplateSpec, out io.Writer) {
+	warnings, err := checkQuota(p, namespace, template)
+	if err != nil {
+		fmt.Fprintf(out, "Warning: Cannot check resource quotas: %v\n", err)
+		return
+	}
+	for _, warning := range warnings {
+		fmt.Fprintf(out, "Warning: %s\n", warning)
+	}
+	if len(warnings) > 0 {
+		fmt.Fprintln(out, "Pods which don't fit will not be scheduled, and the service will not become ready.")
+	}
+}
+
+// Apply the defaults of the limit ranges like the LimitRanger admission plugin, and
+// default missing requests to the limits like the API server
+func containerResources(resources corev1.ResourceRequirements, limitRanges []corev1.LimitRange) podResources {
+	requests := corev1.ResourceList{}
+	for name, quantity := range resources.Requests {
+		requests[name] = quantity.DeepCopy()
+	}
+	limits := corev1.ResourceList{}
+	for name, quantity := range resources.Limits {
+		limits[name] = quantity.DeepCopy()
+	}
+	for _, limitRange := range limitRanges {
+		for _, item := range limitRange.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+			setMissing(limits, item.Default)
+			setMissing(requests, item.DefaultRequest)
+		}
+	}
+	setMissing(requests, limits)
+
+	ret := podResources{requests, limits, map[corev1.ResourceName]bool{}, map[corev1.ResourceName]bool{}}
+	for _, name := range quotaResourceNames {
+		if _, ok := requests[name]; !ok {
+			ret.missingRequests[name] = true
+		}
+		if _, ok := limits[name]; !ok {
+			ret.missingLimits[name] = true
+		}
+	}
+	return ret
+}
+
+func setMissing(resources corev1.ResourceList, defaults corev1.ResourceList) {
+	for name, quantity := range defaults {
+		if _, ok := resources[name]; !ok {
+			resources[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+func addResources(a, b podResources) podResources {
+	ret := podResources{corev1.ResourceList{}, corev1.ResourceList{}, map[corev1.ResourceName]bool{}, map[corev1.ResourceName]bool{}}
+	for _, r := range []podResources{a, b} {
+		for name, quantity := range r.requests {
+			sum := ret.requests[name]
+			sum.Add(quantity)
+			ret.requests[name] = sum
+		}
+		for name, quantity := range r.limits {
+			sum := ret.limits[name]
+			sum.Add(quantity)
+			ret.limits[name] = sum
+		}
+		for name := range r.missingRequests {
+			ret.missingRequests[name] = true
+		}
+		for name := range r.missingLimits {
+			ret.missingLimits[name] = true
+		}
+	}
+	return ret
+}
+
+// Check the min and max values of the limit ranges of the given type
+func checkLimitRanges(limitRanges []corev1.LimitRange, limitType corev1.LimitType, what string, r podResources) []string {
+	var warnings []string
+	for _, limitRange := range limitRanges {
+		for _, item := range limitRange.Spec.Limits {
+			if item.Type != limitType {
+				continue
+			}
+			for _, name := range sortedResourceNames(item.Max) {
+				max := item.Max[name]
+				limit, ok := r.limits[name]
+				if !ok || r.missingLimits[name] {
+					warnings = append(warnings, fmt.Sprintf("%s has no %s limit, but limit range '%s' requires a limit of at most %s",
+						what, name, limitRange.Name, max.String()))
+				} else if limit.Cmp(max) > 0 {
+					warnings = append(warnings, fmt.Sprintf("%s has a %s limit of %s, but limit range '%s' allows at most %s",
+						what, name, limit.String(), limitRange.Name, max.String()))
+				}
+			}
+			for _, name := range sortedResourceNames(item.Min) {
+				min := item.Min[name]
+				request, ok := r.requests[name]
+				if ok && request.Cmp(min) < 0 {
+					warnings = append(warnings, fmt.Sprintf("%s requests %s %s, but limit range '%s' requires at least %s",
+						what, request.String(), name, limitRange.Name, min.String()))
+				}
+			}
+		}
+	}
+	return warnings
+}
+
+// Check whether the given number of pods fits into the unused part of the quotas
+func checkResourceQuotas(quotas []corev1.ResourceQuota, pod podResources, replicas int) []string {
+	var warnings []string
+	for _, quota := range quotas {
+		// Scoped quotas apply only to some pods, like best effort or terminating ones
+		if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+			continue
+		}
+		for _, name := range sortedResourceNames(quota.Status.Hard) {
+			hard := quota.Status.Hard[name]
+			used := quota.Status.Used[name]
+			left := hard.DeepCopy()
+			left.Sub(used)
+
+			var needed resource.Quantity
+			switch name {
+			case corev1.ResourcePods:
+				needed = *resource.NewQuantity(int64(replicas), resource.DecimalSI)
+			case corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceRequestsCPU, corev1.ResourceRequestsMemory:
+				resourceName := corev1.ResourceCPU
+				if name == corev1.ResourceMemory || name == corev1.ResourceRequestsMemory {
+					resourceName = corev1.ResourceMemory
+				}
+				if pod.missingRequests[resourceName] {
+					warnings = append(warnings, fmt.Sprintf("resource quota '%s' limits %s, but not every container of the pod has a %s request "+
+						"(the queue-proxy sidecar gets one only from a limit range default)", quota.Name, name, resourceName))
+					continue
+				}
+				needed = multiplyQuantity(pod.requests[resourceName], replicas)
+			case corev1.ResourceLimitsCPU, corev1.ResourceLimitsMemory:
+				resourceName := corev1.ResourceCPU
+				if name == corev1.ResourceLimitsMemory {
+					resourceName = corev1.ResourceMemory
+				}
+				if pod.missingLimits[resourceName] {
+					warnings = append(warnings, fmt.Sprintf("resource quota '%s' limits %s, but not every container of the pod has a %s limit "+
+						"(the queue-proxy sidecar gets one only from a limit range default)", quota.Name, name, resourceName))
+					continue
+				}
+				needed = multiplyQuantity(pod.limits[resourceName], replicas)
+			default:
+				continue
+			}
+			if needed.Cmp(left) > 0 {
+				warnings = append(warnings, fmt.Sprintf("resource quota '%s' has %s of %s left, but %d pod(s) need %s",
+					quota.Name, left.String(), name, replicas, needed.String()))
+			}
+		}
+	}
+	return warnings
+}
+
+func multiplyQuantity(quantity resource.Quantity, n int) resource.Quantity {
+	ret := resource.Quantity{Format: quantity.Format}
+	for i := 0; i < n; i++ {
+		ret.Add(quantity)
+	}
+	return ret
+}
+
+func sortedResourceNames(resources corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
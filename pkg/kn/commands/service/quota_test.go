@@ -0,0 +1,143 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	knflags "knative.dev/client/pkg/kn/flags"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+	"knative.dev/client/pkg/util/mock"
+)
+
+func executeServiceQuotaCommand(client clientservingv1.KnServingClient, objects []runtime.Object, args ...string) (string, error) {
+	knParams := &commands.KnParams{}
+	knParams.ClientConfig = blankConfig
+
+	output := new(bytes.Buffer)
+	knParams.Output = output
+	knParams.NewServingClient = func(namespace string) (clientservingv1.KnServingClient, error) {
+		return client, nil
+	}
+	knParams.NewKubeClient = func() (kubernetes.Interface, error) {
+		return commands.NewFakeKubeClient(objects...), nil
+	}
+	cmd := NewServiceCommand(knParams)
+	cmd.SetArgs(args)
+	cmd.SetOutput(output)
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return knflags.ReconcileBoolFlags(cmd.Flags())
+	}
+	err := cmd.Execute()
+	return output.String(), err
+}
+
+func newQuota(name string, hard corev1.ResourceList, used corev1.ResourceList) *corev1.ResourceQuota {
+	return &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Status:     corev1.ResourceQuotaStatus{Hard: hard, Used: used},
+	}
+}
+
+func newLimitRange(name string, items ...corev1.LimitRangeItem) *corev1.LimitRange {
+	return &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       corev1.LimitRangeSpec{Limits: items},
+	}
+}
+
+func TestServiceCreateCheckQuota(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", nil, errors.NewNotFound(servingv1.Resource("service"), "foo"))
+	r.CreateService(mock.Any(), nil)
+
+	objects := []runtime.Object{
+		newQuota("compute",
+			corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1"), corev1.ResourcePods: resource.MustParse("10")},
+			corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("600m"), corev1.ResourcePods: resource.MustParse("4")}),
+	}
+	output, err := executeServiceQuotaCommand(client, objects, "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--request", "cpu=100m", "--scale-min", "2", "--check-quota", "--no-wait")
+	assert.NilError(t, err)
+	// 2 pods with 100m + 25m for the queue-proxy each fit into the 400m left
+	assert.Assert(t, util.ContainsNone(output, "Warning:"))
+	assert.Assert(t, util.ContainsAll(output, "Service 'foo' created"))
+	r.Validate()
+}
+
+func TestServiceCreateCheckQuotaExceeded(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", nil, errors.NewNotFound(servingv1.Resource("service"), "foo"))
+	r.CreateService(mock.Any(), nil)
+
+	objects := []runtime.Object{
+		newQuota("compute",
+			corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1"), corev1.ResourceLimitsMemory: resource.MustParse("1Gi")},
+			corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("800m")}),
+		newLimitRange("limits", corev1.LimitRangeItem{
+			Type: corev1.LimitTypeContainer,
+			Max:  corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+		}),
+	}
+	output, err := executeServiceQuotaCommand(client, objects, "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--request", "cpu=100m", "--limit", "cpu=1", "--scale-min", "2", "--check-quota", "--no-wait")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output,
+		"Warning: container 'user-container' has a cpu limit of 1, but limit range 'limits' allows at most 500m",
+		"Warning: container 'queue-proxy' has no cpu limit, but limit range 'limits' requires a limit of at most 500m",
+		"Warning: resource quota 'compute' limits limits.memory, but not every container of the pod has a memory limit",
+		"Warning: resource quota 'compute' has 200m of requests.cpu left, but 2 pod(s) need 250m",
+		"will not be scheduled",
+		"Service 'foo' created"))
+	r.Validate()
+}
+
+func TestServiceCreateCheckQuotaLimitRangeDefaults(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", nil, errors.NewNotFound(servingv1.Resource("service"), "foo"))
+	r.CreateService(mock.Any(), nil)
+
+	objects := []runtime.Object{
+		newQuota("memory",
+			corev1.ResourceList{corev1.ResourceLimitsMemory: resource.MustParse("1Gi")},
+			corev1.ResourceList{corev1.ResourceLimitsMemory: resource.MustParse("512Mi")}),
+		newLimitRange("defaults", corev1.LimitRangeItem{
+			Type:    corev1.LimitTypeContainer,
+			Default: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+		}),
+	}
+	// Both containers get the default limit of 128Mi
+	output, err := executeServiceQuotaCommand(client, objects, "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--scale-min", "3", "--check-quota", "--no-wait")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Warning: resource quota 'memory' has 512Mi of limits.memory left, but 3 pod(s) need 768Mi"))
+	r.Validate()
+}
@@ -0,0 +1,386 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/kmeta"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/i18n"
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/printers"
+	servinglib "knative.dev/client/pkg/serving"
+)
+
+const (
+	// Headroom added on top of the average usage for resource requests
+	requestHeadroomPercent = 20
+	// Headroom added on top of the highest usage of a pod for the memory limit
+	limitHeadroomPercent = 50
+	// Factor applied to the highest observed number of pods when a revision ran into its scale-max bound
+	maxScaleFactor = 2
+
+	// Autoscaler defaults of Knative serving, used when a revision doesn't set them
+	defaultConcurrencyTarget        = 100
+	defaultTargetUtilizationPercent = 70
+)
+
+// Message of the events emitted by the deployment controller when scaling the pods of a revision
+var scaledReplicaSetPattern = regexp.MustCompile(`^Scaled (up|down) replica set \S+ to (\d+)`)
+
+// recommendation holds the recommended settings for a service
+type recommendation struct {
+	revision          string
+	pods              int64
+	scaling           *scalingHistory
+	concurrencyTarget float64
+
+	cpuRequest    resource.Quantity
+	memoryRequest resource.Quantity
+	memoryLimit   resource.Quantity
+	// nil if no bound is recommended
+	minScale *int
+	maxScale *int
+
+	// reasons for changed scaling bounds
+	notes []string
+}
+
+// scalingHistory holds the number of pods of a revision observed in its scaling events
+type scalingHistory struct {
+	events  int
+	lowest  int
+	highest int
+}
+
+var recommendExample = `
+  # Show recommended resource requests and scaling bounds for service 'svc'
+  kn service recommend svc
+
+  # Update service 'svc' with the recommended values
+  kn service recommend svc --apply`
+
+// NewServiceRecommendCommand returns a new command for recommending resource settings
+func NewServiceRecommendCommand(p *commands.KnParams) *cobra.Command {
+	var apply bool
+	var waitFlags commands.WaitFlags
+
+	command := &cobra.Command{
+		Use:   "recommend NAME",
+		Short: "Recommend resource requests and scaling bounds for a service",
+		Long: "Recommend resource requests and limits as well as the scaling bounds for a service. " +
+			"Resources are based on the current usage of the latest ready revision as reported by the metrics-server, " +
+			"so it should be run while the service is under representative load. " +
+			"Scaling bounds are based on the autoscaling settings of the service and the scaling events of the revision. " +
+			"As Kubernetes keeps events for a limited time only, --apply is refused when no scaling events are available.",
+		Example: recommendExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("'service recommend' requires the service name given as single argument")
+			}
+			serviceName := args[0]
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			retryPolicy, err := p.RetryPolicy(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := p.NewServingClient(namespace)
+			if err != nil {
+				return err
+			}
+			service, err := client.GetService(serviceName)
+			if err != nil {
+				return err
+			}
+			latestRevision := service.Status.LatestReadyRevisionName
+			if latestRevision == "" {
+				return fmt.Errorf("service '%s' has no ready revision to base a recommendation on", serviceName)
+			}
+
+			dynamicClient, err := p.NewDynamicClient(namespace)
+			if err != nil {
+				return err
+			}
+			usages, err := getRevisionUsages(dynamicClient, serviceName)
+			if err != nil {
+				return err
+			}
+			var usage *revisionUsage
+			for _, u := range usages {
+				if u.revision == latestRevision {
+					usage = u
+				}
+			}
+			if usage == nil {
+				return fmt.Errorf("no usage found for revision '%s' of service '%s' (scaled to zero?), "+
+					"please retry while the service is receiving requests", latestRevision, serviceName)
+			}
+
+			kubeClient, err := p.NewKubeClient()
+			if err != nil {
+				return err
+			}
+			history, err := getScalingHistory(kubeClient, namespace, latestRevision, int(usage.pods))
+			if err != nil {
+				return err
+			}
+
+			rec, err := recommend(usage, history, &service.Spec.Template)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			err = printRecommendation(out, service, rec)
+			if err != nil {
+				return err
+			}
+
+			if !apply {
+				fmt.Fprintln(out, "\nUse --apply to update the service with the recommended values.")
+				return nil
+			}
+			if history.events == 0 {
+				return fmt.Errorf("not enough data to apply a recommendation: no scaling events found for revision '%s', "+
+					"please retry while the service is under load", latestRevision)
+			}
+
+			fmt.Fprintln(out, "")
+			err = client.UpdateServiceWithRetry(serviceName, func(service *servingv1.Service) (*servingv1.Service, error) {
+				return service, applyRecommendation(service, rec)
+			}, retryPolicy)
+			if err != nil {
+				return err
+			}
+			if !waitFlags.Wait {
+				i18n.Fprintf(out, "Service '%s' updated in namespace '%s'.\n", serviceName, namespace)
+				return nil
+			}
+			i18n.Fprintf(out, "Updating Service '%s' in namespace '%s':\n", serviceName, namespace)
+			return waitForServiceToGetReady(client, serviceName, waitFlags, "updated", out)
+		},
+	}
+	commands.AddNamespaceFlags(command.Flags(), false)
+	command.Flags().BoolVar(&apply, "apply", false, "Update the service with the recommended values.")
+	waitFlags.AddConditionWaitFlags(command, commands.WaitDefaultTimeout, "update", "service", "ready")
+	return command
+}
+
+// getScalingHistory collects the number of pods a revision was scaled to from the events of its deployment.
+// The current number of pods is included.
+func getScalingHistory(kubeClient kubernetes.Interface, namespace string, revision string, pods int) (*scalingHistory, error) {
+	deployment := kmeta.ChildName(revision, "-deployment")
+	events, err := kubeClient.CoreV1().Events(namespace).List(context.TODO(), metav1.ListOptions{
+		FieldSelector: fields.Set{"involvedObject.kind": "Deployment", "involvedObject.name": deployment}.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	history := &scalingHistory{lowest: pods, highest: pods}
+	for _, event := range events.Items {
+		if event.InvolvedObject.Name != deployment || event.Reason != "ScalingReplicaSet" {
+			continue
+		}
+		match := scaledReplicaSetPattern.FindStringSubmatch(event.Message)
+		if match == nil {
+			continue
+		}
+		replicas, err := strconv.Atoi(match[2])
+		if err != nil {
+			continue
+		}
+		history.events++
+		if replicas < history.lowest {
+			history.lowest = replicas
+		}
+		if replicas > history.highest {
+			history.highest = replicas
+		}
+	}
+	return history, nil
+}
+
+// recommend calculates the recommended settings from the usage and the scaling history of a revision
+// and the autoscaling settings of the given template
+func recommend(usage *revisionUsage, history *scalingHistory, template *servingv1.RevisionTemplateSpec) (*recommendation, error) {
+	cpuRequest := withHeadroom(usage.averageCPU().MilliValue(), requestHeadroomPercent)
+	memoryRequest := withHeadroom(usage.averageMemory().Value(), requestHeadroomPercent)
+	memoryLimit := withHeadroom(usage.maxPodMemory.Value(), limitHeadroomPercent)
+
+	scaling, err := servinglib.ScalingInfo(&template.ObjectMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	const mebibyte = 1024 * 1024
+	rec := &recommendation{
+		revision:          usage.revision,
+		pods:              usage.pods,
+		scaling:           history,
+		concurrencyTarget: concurrencyTarget(template),
+		// Round up to 10m CPU and full mebibytes
+		cpuRequest:    *resource.NewMilliQuantity(roundUp(cpuRequest, 10), resource.DecimalSI),
+		memoryRequest: *resource.NewQuantity(roundUp(memoryRequest, mebibyte), resource.BinarySI),
+		memoryLimit:   *resource.NewQuantity(roundUp(memoryLimit, mebibyte), resource.BinarySI),
+		minScale:      scaling.Min,
+		maxScale:      scaling.Max,
+	}
+
+	// Without scaling events the current bounds are kept
+	if history.events == 0 {
+		return rec, nil
+	}
+	if history.lowest == 0 && (scaling.Min == nil || *scaling.Min == 0) {
+		minScale := 1
+		rec.minScale = &minScale
+		rec.notes = append(rec.notes, "The revision scaled to zero, so requests arriving afterwards had to wait for a cold start. "+
+			"A scale-min of 1 avoids this at the cost of a permanently running pod.")
+	}
+	if scaling.Max != nil && *scaling.Max > 0 && history.highest >= *scaling.Max {
+		maxScale := history.highest * maxScaleFactor
+		rec.maxScale = &maxScale
+		rec.notes = append(rec.notes, fmt.Sprintf("The revision reached its scale-max of %d, so requests beyond about %d concurrent ones were queued. "+
+			"A scale-max of %d allows about %d concurrent requests.",
+			*scaling.Max, capacity(*scaling.Max, rec.concurrencyTarget), maxScale, capacity(maxScale, rec.concurrencyTarget)))
+	}
+	return rec, nil
+}
+
+// concurrencyTarget returns the number of concurrent requests per pod the autoscaler aims for
+func concurrencyTarget(template *servingv1.RevisionTemplateSpec) float64 {
+	target := float64(defaultConcurrencyTarget)
+	containerConcurrency := template.Spec.ContainerConcurrency
+	if containerConcurrency != nil && *containerConcurrency > 0 {
+		target = float64(*containerConcurrency)
+	}
+	if annotated := servinglib.ConcurrencyTarget(&template.ObjectMeta); annotated != nil && float64(*annotated) < target {
+		target = float64(*annotated)
+	}
+	utilization := defaultTargetUtilizationPercent
+	if annotated := servinglib.ConcurrencyTargetUtilization(&template.ObjectMeta); annotated != nil {
+		utilization = *annotated
+	}
+	return target * float64(utilization) / 100
+}
+
+// capacity returns the number of concurrent requests the given number of pods handle
+func capacity(pods int, concurrencyTarget float64) int {
+	return int(float64(pods) * concurrencyTarget)
+}
+
+// applyRecommendation sets the recommended values on the service's revision template
+func applyRecommendation(service *servingv1.Service, rec *recommendation) error {
+	template := &service.Spec.Template
+	container, err := servinglib.ContainerOfRevisionTemplate(template)
+	if err != nil {
+		return err
+	}
+	if container.Resources.Requests == nil {
+		container.Resources.Requests = corev1.ResourceList{}
+	}
+	if container.Resources.Limits == nil {
+		container.Resources.Limits = corev1.ResourceList{}
+	}
+	container.Resources.Requests[corev1.ResourceCPU] = rec.cpuRequest
+	container.Resources.Requests[corev1.ResourceMemory] = rec.memoryRequest
+	container.Resources.Limits[corev1.ResourceMemory] = rec.memoryLimit
+	if rec.minScale == nil && rec.maxScale == nil {
+		return nil
+	}
+	return servinglib.UpdateScaleBounds(template, rec.minScale, rec.maxScale)
+}
+
+func printRecommendation(out io.Writer, service *servingv1.Service, rec *recommendation) error {
+	fmt.Fprintf(out, "Recommendation for service '%s' based on the current usage of revision '%s' (%d pods) "+
+		"and a concurrency target of %g requests per pod:\n\n",
+		service.Name, rec.revision, rec.pods, rec.concurrencyTarget)
+
+	var requests, limits corev1.ResourceList
+	container, err := servinglib.ContainerOfRevisionTemplate(&service.Spec.Template)
+	if err == nil {
+		requests = container.Resources.Requests
+		limits = container.Resources.Limits
+	}
+	scaling, err := servinglib.ScalingInfo(&service.Spec.Template.ObjectMeta)
+	if err != nil {
+		return err
+	}
+
+	tw := printers.NewTabWriter(out)
+	fmt.Fprintln(tw, "SETTING\tCURRENT\tRECOMMENDED")
+	fmt.Fprintf(tw, "request cpu\t%s\t%s\n", quantityOrDash(requests, corev1.ResourceCPU), rec.cpuRequest.String())
+	fmt.Fprintf(tw, "request memory\t%s\t%s\n", quantityOrDash(requests, corev1.ResourceMemory), rec.memoryRequest.String())
+	fmt.Fprintf(tw, "limit memory\t%s\t%s\n", quantityOrDash(limits, corev1.ResourceMemory), rec.memoryLimit.String())
+	fmt.Fprintf(tw, "scale-min\t%s\t%s\n", intOrDash(scaling.Min), intOrDash(rec.minScale))
+	fmt.Fprintf(tw, "scale-max\t%s\t%s\n", intOrDash(scaling.Max), intOrDash(rec.maxScale))
+	err = tw.Flush()
+	if err != nil {
+		return err
+	}
+
+	if rec.scaling.events == 0 {
+		fmt.Fprintf(out, "\nNo scaling events found for revision '%s', the scaling bounds are kept.\n", rec.revision)
+		return nil
+	}
+	fmt.Fprintf(out, "\nObserved %d scaling events, revision '%s' ran with %d to %d pods.\n",
+		rec.scaling.events, rec.revision, rec.scaling.lowest, rec.scaling.highest)
+	for _, note := range rec.notes {
+		fmt.Fprintln(out, note)
+	}
+	return nil
+}
+
+func quantityOrDash(resources corev1.ResourceList, name corev1.ResourceName) string {
+	if q, ok := resources[name]; ok {
+		return q.String()
+	}
+	return "-"
+}
+
+func intOrDash(value *int) string {
+	if value == nil {
+		return "-"
+	}
+	return strconv.Itoa(*value)
+}
+
+func withHeadroom(value int64, percent int64) int64 {
+	return value + value*percent/100
+}
+
+func roundUp(value int64, unit int64) int64 {
+	if value <= 0 {
+		return unit
+	}
+	return ((value + unit - 1) / unit) * unit
+}
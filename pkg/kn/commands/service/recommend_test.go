@@ -0,0 +1,174 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/serving/pkg/apis/autoscaling"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/serving"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+)
+
+func TestServiceRecommend(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	service := getServiceWithLatestRevision("foo", "foo-rev-2")
+	service.Spec.Template.Annotations = map[string]string{
+		autoscaling.MaxScaleAnnotationKey: "3",
+		autoscaling.TargetAnnotationKey:   "10",
+	}
+	r.GetService("foo", service, nil)
+
+	output, err := executeServiceMetricsCommandWithEvents(client, recommendPodMetrics(), nil, recommendScalingEvents(), "recommend", "foo")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Recommendation", "foo", "foo-rev-2", "2 pods", "concurrency target of 7", "--apply"))
+	lines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(lines[2], "SETTING", "CURRENT", "RECOMMENDED"))
+	assert.Check(t, util.ContainsAll(lines[3], "request cpu", "100m", "240m"))
+	assert.Check(t, util.ContainsAll(lines[4], "request memory", "-", "116Mi"))
+	assert.Check(t, util.ContainsAll(lines[5], "limit memory", "-", "192Mi"))
+	assert.Check(t, util.ContainsAll(lines[6], "scale-min", "-", "1"))
+	assert.Check(t, util.ContainsAll(lines[7], "scale-max", "3", "6"))
+	assert.Check(t, util.ContainsAll(output, "Observed 3 scaling events", "0 to 3 pods", "cold start", "reached its scale-max of 3", "about 21 concurrent", "about 42 concurrent"))
+	r.Validate()
+}
+
+func TestServiceRecommendKeepsUnboundedMaxScale(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	service := getServiceWithLatestRevision("foo", "foo-rev-2")
+	service.Spec.Template.Annotations = map[string]string{autoscaling.MinScaleAnnotationKey: "1"}
+	r.GetService("foo", service, nil)
+
+	output, err := executeServiceMetricsCommandWithEvents(client, recommendPodMetrics(), nil, recommendScalingEvents(), "recommend", "foo")
+	assert.NilError(t, err)
+	lines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(lines[6], "scale-min", "1", "1"))
+	assert.Check(t, util.ContainsAll(lines[7], "scale-max", "-", "-"))
+	assert.Check(t, util.ContainsNone(output, "cold start", "reached its scale-max"))
+	r.Validate()
+}
+
+func TestServiceRecommendApply(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	service := getServiceWithLatestRevision("foo", "foo-rev-2")
+	service.Spec.Template.Annotations = map[string]string{autoscaling.MaxScaleAnnotationKey: "3"}
+	r.GetService("foo", service, nil)
+	r.GetService("foo", service, nil)
+	r.UpdateService(func(t *testing.T, updated *servingv1.Service) {
+		container := updated.Spec.Template.Spec.Containers[0]
+		assert.Equal(t, container.Resources.Requests.Cpu().String(), "240m")
+		assert.Equal(t, container.Resources.Requests.Memory().String(), "116Mi")
+		assert.Equal(t, container.Resources.Limits.Memory().String(), "192Mi")
+		scaling, err := serving.ScalingInfo(&updated.Spec.Template.ObjectMeta)
+		assert.NilError(t, err)
+		assert.Equal(t, *scaling.Min, 1)
+		assert.Equal(t, *scaling.Max, 6)
+	}, nil)
+
+	output, err := executeServiceMetricsCommandWithEvents(client, recommendPodMetrics(), nil, recommendScalingEvents(), "recommend", "foo", "--apply", "--no-wait")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Service 'foo' updated"))
+	r.Validate()
+}
+
+func TestServiceRecommendApplyWithoutScalingEvents(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	service := getServiceWithLatestRevision("foo", "foo-rev-2")
+	service.Spec.Template.Annotations = map[string]string{autoscaling.MaxScaleAnnotationKey: "3"}
+	r.GetService("foo", service, nil)
+
+	output, err := executeServiceMetricsCommandWithEvents(client, recommendPodMetrics(), nil, nil, "recommend", "foo", "--apply", "--no-wait")
+	assert.ErrorContains(t, err, "not enough data")
+	assert.ErrorContains(t, err, "foo-rev-2")
+	lines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(lines[7], "scale-max", "3", "3"))
+	assert.Check(t, util.ContainsAll(output, "No scaling events found"))
+	r.Validate()
+}
+
+func TestServiceRecommendNoUsage(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", getServiceWithLatestRevision("foo", "foo-rev-3"), nil)
+
+	_, err := executeServiceMetricsCommand(client, recommendPodMetrics(), nil, "recommend", "foo")
+	assert.ErrorContains(t, err, "no usage found")
+	assert.ErrorContains(t, err, "foo-rev-3")
+	r.Validate()
+}
+
+func TestServiceRecommendNoReadyRevision(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", getService("foo"), nil)
+
+	_, err := executeServiceMetricsCommand(client, recommendPodMetrics(), nil, "recommend", "foo")
+	assert.ErrorContains(t, err, "no ready revision")
+	r.Validate()
+}
+
+func getServiceWithLatestRevision(name, revision string) *servingv1.Service {
+	service := getService(name)
+	service.Spec.Template.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU] = resource.MustParse("100m")
+	service.Status.LatestReadyRevisionName = revision
+	return service
+}
+
+func recommendPodMetrics() *unstructured.UnstructuredList {
+	podMetrics := &unstructured.UnstructuredList{}
+	podMetrics.Items = []unstructured.Unstructured{
+		createPodMetrics("foo-pod-1", "foo", "foo-rev-2", "100m", "64Mi"),
+		createPodMetrics("foo-pod-2", "foo", "foo-rev-2", "300m", "128Mi"),
+		createPodMetrics("foo-pod-3", "foo", "foo-rev-1", "50m", "32Mi"),
+	}
+	return podMetrics
+}
+
+func recommendScalingEvents() []runtime.Object {
+	return []runtime.Object{
+		createScalingEvent("foo-event-1", "foo-rev-2-deployment", "Scaled down replica set foo-rev-2-deployment-5d8f to 0"),
+		createScalingEvent("foo-event-2", "foo-rev-2-deployment", "Scaled up replica set foo-rev-2-deployment-5d8f to 3"),
+		createScalingEvent("foo-event-3", "foo-rev-2-deployment", "Scaled down replica set foo-rev-2-deployment-5d8f to 2"),
+		// Ignored as it belongs to another revision
+		createScalingEvent("foo-event-4", "foo-rev-1-deployment", "Scaled up replica set foo-rev-1-deployment-7c9d to 10"),
+	}
+}
+
+func createScalingEvent(name, deployment, message string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Deployment",
+			Name:      deployment,
+			Namespace: "default",
+		},
+		Reason:  "ScalingReplicaSet",
+		Message: message,
+	}
+}
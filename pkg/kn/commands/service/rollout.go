@@ -0,0 +1,224 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/kn/traffic"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/wait"
+)
+
+var rolloutExample = `
+  # Deploy a new revision of service 'svc' while the traffic stays on the current revision 'svc-00001'
+  kn service update svc --image knativesamples/helloworld:v2 --traffic svc-00001=100
+
+  # Shift the traffic to the new revision in steps of 10% every minute
+  kn service rollout svc
+
+  # Shift the traffic in steps of 25% every 5 minutes and pause at 50%
+  kn service rollout svc --step 25 --interval 5m --pause-at 50
+
+  # Resume the paused rollout
+  kn service rollout svc`
+
+// Sleep between two steps of a rollout, can be replaced in tests
+var rolloutSleep = time.Sleep
+
+type rolloutFlags struct {
+	to       string
+	step     int64
+	interval time.Duration
+	pauseAt  int64
+	timeout  int
+
+	// retryPolicy isn't a flag of its own, it comes from --retries and the configuration
+	retryPolicy clientservingv1.RetryPolicy
+}
+
+// NewServiceRolloutCommand returns a new command for gradually shifting traffic to a new revision
+func NewServiceRolloutCommand(p *commands.KnParams) *cobra.Command {
+	var flags rolloutFlags
+
+	command := &cobra.Command{
+		Use:   "rollout NAME",
+		Short: "Gradually shift the traffic of a service to a new revision",
+		Long: "Gradually shift the traffic of a service from the revision currently receiving it to a new revision. " +
+			"After each step, the service must become ready again and the new revision must stay healthy until the next step. " +
+			"Otherwise the rollout is aborted and the traffic is restored as it was before. " +
+			"A rollout paused with --pause-at, or interrupted, is resumed by running the command again.",
+		Example: rolloutExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("'service rollout' requires the service name given as single argument")
+			}
+			if flags.step < 1 || flags.step > 100 {
+				return errors.New("--step must be between 1 and 100")
+			}
+			if flags.pauseAt < 0 || flags.pauseAt > 99 {
+				return errors.New("--pause-at must be between 1 and 99")
+			}
+			if flags.interval < 0 {
+				return errors.New("--interval must not be negative")
+			}
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			flags.retryPolicy, err = p.RetryPolicy(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := p.NewServingClient(namespace)
+			if err != nil {
+				return err
+			}
+			return rollout(client, args[0], flags, cmd.OutOrStdout())
+		},
+	}
+	commands.AddNamespaceFlags(command.Flags(), false)
+	command.Flags().StringVar(&flags.to, "to", "", "Revision to shift the traffic to. Defaults to the latest ready revision.")
+	command.Flags().Int64Var(&flags.step, "step", 10, "Percentage of the traffic shifted in each step.")
+	command.Flags().DurationVar(&flags.interval, "interval", time.Minute,
+		"Time between two steps, during which the new revision must stay healthy.")
+	command.Flags().Int64Var(&flags.pauseAt, "pause-at", 0,
+		"Pause the rollout once the new revision receives this percentage of the traffic.")
+	command.Flags().IntVar(&flags.timeout, "wait-timeout", commands.WaitDefaultTimeout,
+		"Seconds to wait for the service to become ready after each step.")
+	return command
+}
+
+// rollout shifts the traffic of the service step by step and restores the
+// original traffic if the service or the new revision fails
+func rollout(client clientservingv1.KnServingClient, name string, flags rolloutFlags, out io.Writer) error {
+	service, err := client.GetService(name)
+	if err != nil {
+		return err
+	}
+	target := flags.to
+	if target == "" {
+		target = service.Status.LatestReadyRevisionName
+		if target == "" {
+			return fmt.Errorf("service '%s' has no ready revision to roll out", name)
+		}
+	} else {
+		_, err = client.GetRevision(target)
+		if err != nil {
+			return err
+		}
+	}
+
+	original := service.DeepCopy().Spec.Traffic
+	source, err := traffic.RolloutSource(original, target)
+	if err != nil {
+		return fmt.Errorf("cannot roll out revision '%s' of service '%s': %v", target, name, err)
+	}
+	percent := traffic.RolloutPercent(original, target)
+	if source == "" {
+		fmt.Fprintf(out, "Revision '%s' already receives all traffic of service '%s'.\n", target, name)
+		return nil
+	}
+	if flags.pauseAt > 0 && percent >= flags.pauseAt {
+		flags.pauseAt = 0
+	}
+
+	for percent < 100 {
+		percent = nextRolloutPercent(percent, flags)
+		err = client.UpdateServiceWithRetry(name, func(service *servingv1.Service) (*servingv1.Service, error) {
+			service.Spec.Traffic = traffic.RolloutStep(service.Spec.Traffic, source, target, percent)
+			return service, nil
+		}, flags.retryPolicy)
+		if err == nil {
+			fmt.Fprintf(out, "%3d%% of the traffic routed to revision '%s', %d%% to revision '%s'.\n", percent, target, 100-percent, source)
+			err = checkRolloutStep(client, name, target, percent, flags, percent < 100 && percent != flags.pauseAt)
+		}
+		if err != nil {
+			return abortRollout(client, name, target, original, err, flags.retryPolicy, out)
+		}
+		if percent == flags.pauseAt {
+			fmt.Fprintf(out, "Rollout of revision '%s' paused, run 'kn service rollout %s' to resume.\n", target, name)
+			return nil
+		}
+	}
+	fmt.Fprintf(out, "Rollout of revision '%s' of service '%s' completed.\n", target, name)
+	return nil
+}
+
+// nextRolloutPercent returns the percentage for the next step, which stops at
+// the percentage to pause at
+func nextRolloutPercent(percent int64, flags rolloutFlags) int64 {
+	next := percent + flags.step
+	if flags.pauseAt > percent && next > flags.pauseAt {
+		next = flags.pauseAt
+	}
+	if next > 100 {
+		next = 100
+	}
+	return next
+}
+
+// checkRolloutStep waits for the service to become ready with the traffic of the step
+// and, if more steps follow, watches the new revision for the interval between steps
+func checkRolloutStep(client clientservingv1.KnServingClient, name string, revision string, percent int64, flags rolloutFlags, moreSteps bool) error {
+	stepRouted := clientservingv1.ServiceReadyWith(func(service *servingv1.Service) bool {
+		return traffic.RolloutPercent(service.Status.Traffic, revision) == percent
+	})
+	err, _ := client.WaitForServicePredicate(name, stepRouted, time.Duration(flags.timeout)*time.Second, wait.NoopMessageCallback())
+	if err != nil {
+		return err
+	}
+	if moreSteps {
+		rolloutSleep(flags.interval)
+	}
+	return checkRevisionHealthy(client, revision)
+}
+
+// checkRevisionHealthy returns an error if any condition of the revision is false.
+// An inactive revision is fine, it is just scaled to zero.
+func checkRevisionHealthy(client clientservingv1.KnServingClient, name string) error {
+	revision, err := client.GetRevision(name)
+	if err != nil {
+		return err
+	}
+	for _, cond := range revision.Status.Conditions {
+		if cond.Type != servingv1.RevisionConditionActive && cond.Status == corev1.ConditionFalse {
+			return fmt.Errorf("revision '%s' is not healthy: %s: %s %s", name, cond.Type, cond.Reason, cond.Message)
+		}
+	}
+	return nil
+}
+
+// abortRollout restores the original traffic of the service
+func abortRollout(client clientservingv1.KnServingClient, name string, revision string, original []servingv1.TrafficTarget, cause error, retryPolicy clientservingv1.RetryPolicy, out io.Writer) error {
+	fmt.Fprintf(out, "Rollout of revision '%s' failed, restoring the previous traffic of service '%s'.\n", revision, name)
+	err := client.UpdateServiceWithRetry(name, func(service *servingv1.Service) (*servingv1.Service, error) {
+		service.Spec.Traffic = original
+		return service, nil
+	}, retryPolicy)
+	if err != nil {
+		return fmt.Errorf("rollout of revision '%s' failed: %v, restoring the previous traffic failed too: %v", revision, cause, err)
+	}
+	return fmt.Errorf("rollout of revision '%s' aborted: %v", revision, cause)
+}
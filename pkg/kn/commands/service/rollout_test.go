@@ -0,0 +1,160 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+	"knative.dev/client/pkg/util/mock"
+)
+
+func rolloutService(percentV2 int64) *servingv1.Service {
+	service := getService("foo")
+	service.Status.LatestReadyRevisionName = "foo-v2"
+	service.Spec.Traffic = []servingv1.TrafficTarget{
+		{RevisionName: "foo-v1", LatestRevision: ptr.Bool(false), Percent: ptr.Int64(100 - percentV2)},
+	}
+	if percentV2 > 0 {
+		service.Spec.Traffic = append(service.Spec.Traffic,
+			servingv1.TrafficTarget{RevisionName: "foo-v2", LatestRevision: ptr.Bool(false), Percent: ptr.Int64(percentV2)})
+	}
+	return service
+}
+
+func revisionWithConditions(conditions ...apis.Condition) *servingv1.Revision {
+	revision := &servingv1.Revision{}
+	revision.Status.Conditions = conditions
+	return revision
+}
+
+func recordRolloutStep(r *clientservingv1.ServingRecorder, before int64, after int64, revision *servingv1.Revision) {
+	r.GetService("foo", rolloutService(before), nil)
+	r.UpdateService(func(t *testing.T, service *servingv1.Service) {
+		assert.Equal(t, *service.Spec.Traffic[len(service.Spec.Traffic)-1].Percent, after)
+	}, nil)
+	r.WaitForServicePredicate("foo", func(t *testing.T, a interface{}) {
+		predicate := a.(clientservingv1.ServicePredicate)
+		service := rolloutService(after)
+		service.Status.Conditions = duckv1.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionTrue}}
+		done, err := predicate(service)
+		assert.NilError(t, err)
+		assert.Assert(t, !done, "traffic of the step not yet in the status")
+		service.Status.Traffic = service.Spec.Traffic
+		done, err = predicate(service)
+		assert.NilError(t, err)
+		assert.Assert(t, done)
+	}, mock.Any(), mock.Any(), nil, time.Second)
+	r.GetRevision("foo-v2", revision, nil)
+}
+
+func TestServiceRollout(t *testing.T) {
+	var slept []time.Duration
+	defer func(f func(time.Duration)) { rolloutSleep = f }(rolloutSleep)
+	rolloutSleep = func(d time.Duration) { slept = append(slept, d) }
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	healthy := revisionWithConditions(
+		apis.Condition{Type: apis.ConditionReady, Status: corev1.ConditionTrue},
+		apis.Condition{Type: servingv1.RevisionConditionActive, Status: corev1.ConditionFalse})
+
+	r.GetService("foo", rolloutService(0), nil)
+	recordRolloutStep(r, 0, 40, healthy)
+	recordRolloutStep(r, 40, 80, healthy)
+	recordRolloutStep(r, 80, 100, healthy)
+
+	output, err := executeServiceCommand(client, "rollout", "foo", "--step", "40", "--interval", "30s")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output,
+		" 40% of the traffic routed to revision 'foo-v2', 60% to revision 'foo-v1'",
+		" 80%", "100%", "Rollout of revision 'foo-v2' of service 'foo' completed"))
+	assert.DeepEqual(t, slept, []time.Duration{30 * time.Second, 30 * time.Second})
+	r.Validate()
+}
+
+func TestServiceRolloutPauseAndResume(t *testing.T) {
+	defer func(f func(time.Duration)) { rolloutSleep = f }(rolloutSleep)
+	rolloutSleep = func(time.Duration) {}
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	healthy := revisionWithConditions(apis.Condition{Type: apis.ConditionReady, Status: corev1.ConditionTrue})
+
+	r.GetService("foo", rolloutService(0), nil)
+	recordRolloutStep(r, 0, 40, healthy)
+	recordRolloutStep(r, 40, 50, healthy)
+	output, err := executeServiceCommand(client, "rollout", "foo", "--step", "40", "--pause-at", "50")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "paused", "kn service rollout foo"))
+	r.Validate()
+
+	r.GetService("foo", rolloutService(50), nil)
+	recordRolloutStep(r, 50, 90, healthy)
+	recordRolloutStep(r, 90, 100, healthy)
+	output, err = executeServiceCommand(client, "rollout", "foo", "--step", "40", "--pause-at", "50")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "completed"))
+	r.Validate()
+}
+
+func TestServiceRolloutAbort(t *testing.T) {
+	defer func(f func(time.Duration)) { rolloutSleep = f }(rolloutSleep)
+	rolloutSleep = func(time.Duration) {}
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", rolloutService(0), nil)
+	recordRolloutStep(r, 0, 50, revisionWithConditions(apis.Condition{
+		Type: servingv1.RevisionConditionContainerHealthy, Status: corev1.ConditionFalse, Reason: "ExitCode1", Message: "container crashed"}))
+	r.GetService("foo", rolloutService(50), nil)
+	r.UpdateService(func(t *testing.T, service *servingv1.Service) {
+		assert.DeepEqual(t, service.Spec.Traffic, rolloutService(0).Spec.Traffic)
+	}, nil)
+
+	output, err := executeServiceCommand(client, "rollout", "foo", "--step", "50")
+	assert.ErrorContains(t, err, "rollout of revision 'foo-v2' aborted: revision 'foo-v2' is not healthy: ContainerHealthy: ExitCode1 container crashed")
+	assert.Assert(t, util.ContainsAll(output, "restoring the previous traffic"))
+	r.Validate()
+}
+
+func TestServiceRolloutNothingToDo(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", rolloutService(100), nil)
+	output, err := executeServiceCommand(client, "rollout", "foo")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "already receives all traffic"))
+
+	latest := getService("foo")
+	latest.Status.LatestReadyRevisionName = "foo-v2"
+	latest.Spec.Traffic = []servingv1.TrafficTarget{{LatestRevision: ptr.Bool(true), Percent: ptr.Int64(100)}}
+	r.GetService("foo", latest, nil)
+	_, err = executeServiceCommand(client, "rollout", "foo")
+	assert.ErrorContains(t, err, "pin it to the current revision")
+
+	_, err = executeServiceCommand(client, "rollout", "foo", "--step", "0")
+	assert.ErrorContains(t, err, "--step must be between 1 and 100")
+	r.Validate()
+}
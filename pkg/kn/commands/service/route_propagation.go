@@ -0,0 +1,91 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"knative.dev/client/pkg/printers"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	network "knative.dev/networking/pkg"
+	"knative.dev/serving/pkg/apis/serving"
+)
+
+// Timeout for a single request to the URL of the service
+const routePropagationRequestTimeout = 5 * time.Second
+
+// Budget for the URL of a ready service to serve requests and how often to
+// probe it, can be overridden in tests
+var (
+	routePropagationTimeout      = 60 * time.Second
+	routePropagationPollInterval = time.Second
+)
+
+// waitForRoutePropagation probes the URL of a ready service until it can be
+// resolved and returns the expected status, any 2xx status if expectedStatus is 0.
+// Some ingresses program the route only after the service reports to be ready
+func waitForRoutePropagation(client clientservingv1.KnServingClient, serviceName string, expectedStatus int, out io.Writer) error {
+	service, err := client.GetService(serviceName)
+	if err != nil {
+		return fmt.Errorf("cannot fetch service '%s' in namespace '%s' for verifying the route propagation: %v", serviceName, client.Namespace(), err)
+	}
+	if service.Labels[network.VisibilityLabelKey] == serving.VisibilityClusterLocal {
+		return fmt.Errorf("cannot verify the route propagation of service '%s' because it is only reachable from within the cluster", serviceName)
+	}
+	if service.Status.URL == nil {
+		return fmt.Errorf("cannot verify the route propagation of service '%s' because it has no URL", serviceName)
+	}
+	url := service.Status.URL.String()
+
+	httpClient := &http.Client{Timeout: routePropagationRequestTimeout}
+	start := time.Now()
+	deadline := start.Add(routePropagationTimeout)
+	var lastProblem string
+	for {
+		status, err := probeURL(httpClient, url)
+		switch {
+		case err != nil:
+			lastProblem = err.Error()
+		case isExpectedStatus(status, expectedStatus):
+			printers.WriteElapsed(out, time.Since(start), "Route propagated.")
+			return nil
+		default:
+			lastProblem = fmt.Sprintf("HTTP status %d", status)
+		}
+		if time.Now().Add(routePropagationPollInterval).After(deadline) {
+			return fmt.Errorf("URL %s of service '%s' did not serve requests within %s, last response: %s", url, serviceName, routePropagationTimeout, lastProblem)
+		}
+		time.Sleep(routePropagationPollInterval)
+	}
+}
+
+func probeURL(httpClient *http.Client, url string) (int, error) {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func isExpectedStatus(status int, expectedStatus int) bool {
+	if expectedStatus == 0 {
+		return status >= 200 && status < 300
+	}
+	return status == expectedStatus
+}
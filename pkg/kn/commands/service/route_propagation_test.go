@@ -0,0 +1,120 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	network "knative.dev/networking/pkg"
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+	"knative.dev/client/pkg/util/mock"
+	"knative.dev/client/pkg/wait"
+)
+
+func setupRoutePropagation(t *testing.T) {
+	oldInterval, oldTimeout := routePropagationPollInterval, routePropagationTimeout
+	routePropagationPollInterval = time.Millisecond
+	routePropagationTimeout = 200 * time.Millisecond
+	t.Cleanup(func() {
+		routePropagationPollInterval, routePropagationTimeout = oldInterval, oldTimeout
+	})
+}
+
+func TestServiceCreateWaitForRoutePropagation(t *testing.T) {
+	setupRoutePropagation(t)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// Route not yet programmed for the first requests
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", nil, errors.NewNotFound(servingv1.Resource("service"), "foo"))
+	r.CreateService(mock.Any(), nil)
+	r.WaitForService("foo", mock.Any(), wait.NoopMessageCallback(), nil, time.Second)
+	r.GetService("foo", getServiceWithUrl("foo", server.URL), nil)
+	r.GetService("foo", getServiceWithUrl("foo", server.URL), nil)
+
+	output, err := executeServiceCommand(client, "create", "foo", "--image", "gcr.io/foo/bar:baz", "--wait-for-route-propagation")
+	assert.NilError(t, err)
+	assert.Equal(t, requests, 3)
+	assert.Assert(t, util.ContainsAll(output, "Ready to serve", "Route propagated", server.URL))
+	r.Validate()
+}
+
+func TestWaitForServiceRoutePropagationStatus(t *testing.T) {
+	setupRoutePropagation(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.WaitForService("foo", mock.Any(), wait.NoopMessageCallback(), nil, time.Second)
+	r.GetService("foo", getServiceWithUrl("foo", server.URL), nil)
+
+	err := waitForService(client, "foo", &bytes.Buffer{}, commands.WaitFlags{TimeoutInSeconds: 1, WaitForRoutePropagation: true, RoutePropagationStatus: http.StatusUnauthorized})
+	assert.NilError(t, err)
+	r.Validate()
+}
+
+func TestWaitForRoutePropagationTimeout(t *testing.T) {
+	setupRoutePropagation(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", getServiceWithUrl("foo", server.URL), nil)
+
+	err := waitForRoutePropagation(client, "foo", 0, &bytes.Buffer{})
+	assert.ErrorContains(t, err, "did not serve requests")
+	assert.ErrorContains(t, err, "HTTP status 404")
+	r.Validate()
+}
+
+func TestWaitForRoutePropagationClusterLocal(t *testing.T) {
+	service := getServiceWithUrl("foo", "http://foo.default.svc.cluster.local")
+	service.Labels = map[string]string{network.VisibilityLabelKey: serving.VisibilityClusterLocal}
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", service, nil)
+
+	err := waitForRoutePropagation(client, "foo", 0, &bytes.Buffer{})
+	assert.ErrorContains(t, err, "only reachable from within the cluster")
+	r.Validate()
+}
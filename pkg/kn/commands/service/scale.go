@@ -0,0 +1,195 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"knative.dev/serving/pkg/apis/autoscaling"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/i18n"
+	"knative.dev/client/pkg/kn/commands"
+	servinglib "knative.dev/client/pkg/serving"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+)
+
+var scaleExample = `
+  # Keep between 1 and 10 replicas of service 'svc', which creates a new revision
+  kn service scale svc --min 1 --max 10
+
+  # Allow service 'svc' to scale to zero again
+  kn service scale svc --min 0
+
+  # Raise the maximum of the revisions which receive traffic without creating a new revision
+  kn service scale svc --max 20 --no-new-revision`
+
+// NewServiceScaleCommand returns a new command for changing the scale bounds of a service
+func NewServiceScaleCommand(p *commands.KnParams) *cobra.Command {
+	var minScale, maxScale int
+	var noNewRevision bool
+	var waitFlags commands.WaitFlags
+
+	command := &cobra.Command{
+		Use:   "scale NAME [--min N] [--max N]",
+		Short: "Change the minimum and maximum number of replicas of a service",
+		Long: "Change the minimum and maximum number of replicas of a service. Only the autoscaling annotations " +
+			"of the revision template are changed, which creates one new revision. With --no-new-revision, the " +
+			"revisions which receive traffic are changed in place instead, if the cluster supports it. " +
+			"The revision template stays unchanged then, so that later revisions keep its bounds.",
+		Example: scaleExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("'service scale' requires the service name given as single argument")
+			}
+			name := args[0]
+			annotations, err := scaleAnnotations(cmd, minScale, maxScale)
+			if err != nil {
+				return err
+			}
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			retryPolicy, err := p.RetryPolicy(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := p.NewServingClient(namespace)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if noNewRevision {
+				return scaleRevisionsInPlace(client, name, annotations, retryPolicy, out)
+			}
+
+			var latestRevisionBeforeUpdate string
+			err = client.UpdateServiceWithRetry(name, func(service *servingv1.Service) (*servingv1.Service, error) {
+				latestRevisionBeforeUpdate = service.Status.LatestReadyRevisionName
+				err := servinglib.UpdateRevisionTemplateAnnotations(&service.Spec.Template, annotations, nil)
+				if err != nil {
+					return nil, err
+				}
+				return service, renameRevisionTemplate(service)
+			}, retryPolicy)
+			if err != nil {
+				return err
+			}
+
+			if !waitFlags.Wait {
+				i18n.Fprintf(out, "Service '%s' updated in namespace '%s'.\n", name, namespace)
+				return nil
+			}
+			i18n.Fprintf(out, "Updating Service '%s' in namespace '%s':\n", name, namespace)
+			fmt.Fprintln(out, "")
+			err = waitForService(client, name, out, waitFlags)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(out, "")
+			return showUrl(client, name, latestRevisionBeforeUpdate, "updated", out)
+		},
+	}
+	commands.AddNamespaceFlags(command.Flags(), false)
+	command.Flags().IntVar(&minScale, "min", 0, "Minimum number of replicas, 0 allows scaling to zero.")
+	command.Flags().IntVar(&maxScale, "max", 0, "Maximum number of replicas, 0 means no limit.")
+	command.Flags().BoolVar(&noNewRevision, "no-new-revision", false,
+		"Change the revisions which receive traffic in place instead of creating a new revision.")
+	waitFlags.AddConditionWaitFlags(command, commands.WaitDefaultTimeout, "scale", "service", "ready")
+	return command
+}
+
+// scaleAnnotations returns the autoscaling annotations for the given --min and --max
+func scaleAnnotations(cmd *cobra.Command, minScale int, maxScale int) (map[string]string, error) {
+	annotations := map[string]string{}
+	if cmd.Flags().Changed("min") {
+		annotations[autoscaling.MinScaleAnnotationKey] = strconv.Itoa(minScale)
+	}
+	if cmd.Flags().Changed("max") {
+		annotations[autoscaling.MaxScaleAnnotationKey] = strconv.Itoa(maxScale)
+	}
+	if len(annotations) == 0 {
+		return nil, errors.New("'service scale' requires --min, --max or both")
+	}
+	// Validate the values like for the template, without changing anything
+	err := servinglib.UpdateRevisionTemplateAnnotations(&servingv1.RevisionTemplateSpec{}, annotations, nil)
+	if err != nil {
+		return nil, err
+	}
+	return annotations, nil
+}
+
+// scaleRevisionsInPlace sets the autoscaling annotations on the revisions which receive traffic
+func scaleRevisionsInPlace(client clientservingv1.KnServingClient, name string, annotations map[string]string,
+	retryPolicy clientservingv1.RetryPolicy, out io.Writer) error {
+	service, err := client.GetService(name)
+	if err != nil {
+		return err
+	}
+	revisions := routedRevisionNames(service)
+	if len(revisions) == 0 {
+		return fmt.Errorf("service '%s' has no revision receiving traffic, scale it without --no-new-revision", name)
+	}
+	for _, revisionName := range revisions {
+		err := clientservingv1.RetryOnConflict(retryPolicy, "revision", revisionName, func() error {
+			revision, err := client.GetRevision(revisionName)
+			if err != nil {
+				return err
+			}
+			if revision.Annotations == nil {
+				revision.Annotations = map[string]string{}
+			}
+			for key, value := range annotations {
+				revision.Annotations[key] = value
+			}
+			return client.UpdateRevision(revision)
+		})
+		if apierrors.IsBadRequest(err) || apierrors.IsInvalid(err) || apierrors.IsForbidden(err) {
+			return fmt.Errorf("cannot change revision '%s' in place, which not every Knative version supports: %v. "+
+				"Scale without --no-new-revision instead", revisionName, err)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Revision '%s' scaled in place.\n", revisionName)
+	}
+	fmt.Fprintf(out, "The revision template of service '%s' is unchanged, new revisions keep its scale bounds.\n", name)
+	return nil
+}
+
+// routedRevisionNames returns the revisions in the traffic of the service, or its latest
+// ready revision if the traffic isn't known yet
+func routedRevisionNames(service *servingv1.Service) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, target := range service.Status.Traffic {
+		if target.RevisionName != "" && !seen[target.RevisionName] {
+			seen[target.RevisionName] = true
+			names = append(names, target.RevisionName)
+		}
+	}
+	if len(names) == 0 && service.Status.LatestReadyRevisionName != "" {
+		names = append(names, service.Status.LatestReadyRevisionName)
+	}
+	return names
+}
@@ -0,0 +1,114 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/serving/pkg/apis/autoscaling"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+	"knative.dev/client/pkg/util/mock"
+)
+
+func TestServiceScale(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	service := scaleTestService()
+	r.GetService("foo", service, nil)
+	r.UpdateService(func(t *testing.T, service *servingv1.Service) {
+		annotations := service.Spec.Template.Annotations
+		assert.Equal(t, annotations[autoscaling.MinScaleAnnotationKey], "1")
+		assert.Equal(t, annotations[autoscaling.MaxScaleAnnotationKey], "10")
+		assert.Equal(t, annotations["team"], "payments")
+		assert.Assert(t, strings.HasPrefix(service.Spec.Template.Name, "foo-"))
+		assert.Assert(t, service.Spec.Template.Name != "foo-abcde-1")
+	}, nil)
+
+	output, err := executeServiceCommand(client, "scale", "foo", "--min", "1", "--max", "10", "--no-wait")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Service 'foo' updated in namespace 'default'."))
+	r.Validate()
+}
+
+func TestServiceScaleNoNewRevision(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	service := scaleTestService()
+	service.Status.Traffic = []servingv1.TrafficTarget{
+		{RevisionName: "foo-00001"},
+		{RevisionName: "foo-00002"},
+		{RevisionName: "foo-00002", Tag: "current"},
+	}
+	r.GetService("foo", service, nil)
+	for _, name := range []string{"foo-00001", "foo-00002"} {
+		r.GetRevision(name, &servingv1.Revision{ObjectMeta: metav1.ObjectMeta{Name: name}}, nil)
+		r.UpdateRevision(func(t *testing.T, revision *servingv1.Revision) {
+			assert.Equal(t, revision.Annotations[autoscaling.MaxScaleAnnotationKey], "20")
+			_, hasMin := revision.Annotations[autoscaling.MinScaleAnnotationKey]
+			assert.Assert(t, !hasMin)
+		}, nil)
+	}
+
+	output, err := executeServiceCommand(client, "scale", "foo", "--max", "20", "--no-new-revision")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Revision 'foo-00001' scaled in place.", "Revision 'foo-00002' scaled in place.",
+		"revision template of service 'foo' is unchanged"))
+	r.Validate()
+}
+
+func TestServiceScaleNoNewRevisionUnsupported(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	service := scaleTestService()
+	service.Status.LatestReadyRevisionName = "foo-00001"
+	r.GetService("foo", service, nil)
+	r.GetRevision("foo-00001", &servingv1.Revision{ObjectMeta: metav1.ObjectMeta{Name: "foo-00001"}}, nil)
+	r.UpdateRevision(mock.Any(), apierrors.NewBadRequest("annotations are immutable"))
+
+	_, err := executeServiceCommand(client, "scale", "foo", "--min", "2", "--no-new-revision")
+	assert.ErrorContains(t, err, "cannot change revision 'foo-00001' in place")
+	assert.ErrorContains(t, err, "Scale without --no-new-revision instead")
+	r.Validate()
+}
+
+func TestServiceScaleErrors(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+
+	_, err := executeServiceCommand(client, "scale", "foo")
+	assert.Error(t, err, "'service scale' requires --min, --max or both")
+
+	_, err = executeServiceCommand(client, "scale", "--min", "1")
+	assert.Error(t, err, "'service scale' requires the service name given as single argument")
+
+	_, err = executeServiceCommand(client, "scale", "foo", "--min", "-1")
+	assert.ErrorContains(t, err, autoscaling.MinScaleAnnotationKey)
+
+	_, err = executeServiceCommand(client, "scale", "foo", "--min", "5", "--max", "2")
+	assert.ErrorContains(t, err, autoscaling.MaxScaleAnnotationKey)
+}
+
+func scaleTestService() *servingv1.Service {
+	service := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", Generation: 1}}
+	service.Spec.Template.Name = "foo-abcde-1"
+	service.Spec.Template.Annotations = map[string]string{"team": "payments"}
+	return service
+}
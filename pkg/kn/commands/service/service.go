@@ -15,21 +15,26 @@
 package service
 
 import (
+	"bufio"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
+	"knative.dev/client/pkg/i18n"
 	"knative.dev/client/pkg/kn/commands"
+	servinglib "knative.dev/client/pkg/serving"
+	"knative.dev/client/pkg/serving/diagnostics"
 	clientservingv1 "knative.dev/client/pkg/serving/v1"
 	"knative.dev/client/pkg/wait"
+	network "knative.dev/networking/pkg"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
 	"github.com/spf13/cobra"
 )
 
-const (
-	// How often to retry in case of an optimistic lock error when replacing a service (--force)
-	MaxUpdateRetries = 3
-)
+// defaultRevisionName is the template for the names which kn gives to new revisions
+const defaultRevisionName = "{{.Service}}-{{.Random 5}}-{{.Generation}}"
 
 func NewServiceCommand(p *commands.KnParams) *cobra.Command {
 	serviceCmd := &cobra.Command{
@@ -43,20 +48,65 @@ func NewServiceCommand(p *commands.KnParams) *cobra.Command {
 	serviceCmd.AddCommand(NewServiceDeleteCommand(p))
 	serviceCmd.AddCommand(NewServiceUpdateCommand(p))
 	serviceCmd.AddCommand(NewServiceApplyCommand(p))
+	serviceCmd.AddCommand(NewServiceEditCommand(p))
+	serviceCmd.AddCommand(NewServiceDiffCommand(p))
 	serviceCmd.AddCommand(NewServiceExportCommand(p))
 	serviceCmd.AddCommand(NewServiceImportCommand(p))
+	serviceCmd.AddCommand(NewServiceTopCommand(p))
+	serviceCmd.AddCommand(NewServiceRecommendCommand(p))
+	serviceCmd.AddCommand(NewServiceCheckAccessCommand(p))
+	serviceCmd.AddCommand(NewServiceInspectImageCommand(p))
+	serviceCmd.AddCommand(NewServicePauseTrafficCommand(p))
+	serviceCmd.AddCommand(NewServiceResumeTrafficCommand(p))
+	serviceCmd.AddCommand(NewServiceShareCommand(p))
+	serviceCmd.AddCommand(NewServiceUnshareCommand(p))
+	serviceCmd.AddCommand(NewServiceRolloutCommand(p))
+	serviceCmd.AddCommand(NewServiceWaitCommand(p))
+	serviceCmd.AddCommand(NewServiceCpEnvCommand(p))
+	serviceCmd.AddCommand(NewServiceEnvCommand(p))
+	serviceCmd.AddCommand(NewServiceScaleCommand(p))
 	return serviceCmd
 }
 
-func waitForService(client clientservingv1.KnServingClient, serviceName string, out io.Writer, timeout int) error {
-	err, duration := client.WaitForService(serviceName, time.Duration(timeout)*time.Second, wait.SimpleMessageCallback(out))
+func waitForService(client clientservingv1.KnServingClient, serviceName string, out io.Writer, waitFlags commands.WaitFlags) error {
+	reporter, err := wait.NewProgressReporter(waitFlags.Progress, out)
+	if err != nil {
+		return err
+	}
+	err, duration := client.WaitForService(serviceName, time.Duration(waitFlags.TimeoutInSeconds)*time.Second,
+		wait.ProgressMessageCallback(reporter, "service", serviceName))
 	if err != nil {
+		reporter.Report(wait.ProgressEvent{Phase: wait.PhaseFailed, Kind: "service", Name: serviceName, Condition: "Ready", Message: err.Error(), Elapsed: duration})
+		if waitFlags.Progress == "" || waitFlags.Progress == wait.ProgressFormatText {
+			printConditionsTable(client, serviceName, out)
+			if waitFlags.VerboseErrors {
+				printDiagnostics(client, serviceName, waitFlags, out)
+			}
+		}
 		return err
 	}
-	fmt.Fprintf(out, "%7.3fs Ready to serve.\n", float64(duration.Round(time.Millisecond))/float64(time.Second))
+	reporter.Report(wait.ProgressEvent{Phase: wait.PhaseReady, Kind: "service", Name: serviceName, Condition: "Ready", Elapsed: duration})
+	if waitFlags.WaitForRoutePropagation {
+		return waitForRoutePropagation(client, serviceName, waitFlags.RoutePropagationStatus, out)
+	}
 	return nil
 }
 
+// printDiagnostics prints why the latest revision of the service doesn't become ready.
+// Diagnostics are best effort and don't hide the error of waiting, so problems collecting
+// them are only printed.
+func printDiagnostics(client clientservingv1.KnServingClient, serviceName string, waitFlags commands.WaitFlags, out io.Writer) {
+	kubeClient, err := waitFlags.KubeClient()
+	if err != nil {
+		fmt.Fprintf(out, "\nWarning: cannot collect diagnostics: %v\n", err)
+		return
+	}
+	collector := diagnostics.Collector{Serving: client, Kube: kubeClient}
+	if err := collector.Collect(serviceName).Print(out); err != nil {
+		fmt.Fprintf(out, "\nWarning: cannot print diagnostics: %v\n", err)
+	}
+}
+
 func showUrl(client clientservingv1.KnServingClient, serviceName string, originalRevision string, what string, out io.Writer) error {
 	service, err := client.GetService(serviceName)
 	if err != nil {
@@ -67,10 +117,61 @@ func showUrl(client clientservingv1.KnServingClient, serviceName string, origina
 
 	newRevision := service.Status.LatestReadyRevisionName
 	if (originalRevision != "" && originalRevision == newRevision) || originalRevision == "unchanged" {
-		fmt.Fprintf(out, "Service '%s' with latest revision '%s' (unchanged) is available at URL:\n%s\n", serviceName, newRevision, url)
+		i18n.Fprintf(out, "Service '%s' with latest revision '%s' (unchanged) is available at URL:\n%s\n", serviceName, newRevision, url)
 	} else {
-		fmt.Fprintf(out, "Service '%s' %s to latest revision '%s' is available at URL:\n%s\n", serviceName, what, newRevision, url)
+		i18n.Fprintf(out, "Service '%s' %s to latest revision '%s' is available at URL:\n%s\n", serviceName, i18n.T(what), newRevision, url)
+	}
+
+	return nil
+}
+
+// showTagHeaderHint prints out how the given tags (format: revisionRef=tagName) can be addressed
+// via the service's main URL when tag header based routing is enabled in the cluster
+func showTagHeaderHint(tags []string, out io.Writer) {
+	var headers []string
+	for _, tag := range tags {
+		parts := strings.SplitN(tag, "=", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			continue
+		}
+		headers = append(headers, fmt.Sprintf("'%s: %s'", network.TagHeaderName, parts[1]))
 	}
+	if len(headers) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "\nTagged revisions can also be reached via the service URL with header %s "+
+		"if 'tag-header-based-routing' is enabled in the 'config-features' ConfigMap of Knative Serving.\n",
+		strings.Join(headers, ", "))
+}
 
+// renameRevisionTemplate gives the revision template of a service which names its revisions
+// itself a new name, as a changed template with the name of an existing revision is rejected
+func renameRevisionTemplate(service *servingv1.Service) error {
+	if service.Spec.Template.Name == "" {
+		return nil
+	}
+	name, err := servinglib.GenerateRevisionName(defaultRevisionName, service)
+	if err != nil {
+		return err
+	}
+	service.Spec.Template.Name = name
 	return nil
 }
+
+// confirm asks the question on out and reads the answer from the command's input.
+// Only "y" and "yes" confirm. It fails in non-interactive mode, as nobody can answer.
+func confirm(cmd *cobra.Command, p *commands.KnParams, out io.Writer, question string) (bool, error) {
+	if p.NonInteractive {
+		return false, fmt.Errorf("cannot ask '%s' in non-interactive mode", question)
+	}
+	fmt.Fprintf(out, "%s [y/N]: ", question)
+	answer, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	if err == io.EOF {
+		fmt.Fprintln(out)
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
@@ -16,13 +16,17 @@ package service
 
 import (
 	"bytes"
+	"io"
+	"testing"
 
 	"github.com/spf13/cobra"
+	"gotest.tools/assert"
 	"k8s.io/client-go/tools/clientcmd"
 
 	"knative.dev/client/pkg/kn/commands"
 	knflags "knative.dev/client/pkg/kn/flags"
 	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
 )
 
 // Helper methods
@@ -51,6 +55,11 @@ current-context: x
 }
 
 func executeServiceCommand(client clientservingv1.KnServingClient, args ...string) (string, error) {
+	return executeServiceCommandWithInput(client, nil, args...)
+}
+
+// executeServiceCommandWithInput executes the command with the given stdin, if it's not nil
+func executeServiceCommandWithInput(client clientservingv1.KnServingClient, stdin io.Reader, args ...string) (string, error) {
 	knParams := &commands.KnParams{}
 	knParams.ClientConfig = blankConfig
 
@@ -62,6 +71,9 @@ func executeServiceCommand(client clientservingv1.KnServingClient, args ...strin
 	cmd := NewServiceCommand(knParams)
 	cmd.SetArgs(args)
 	cmd.SetOutput(output)
+	if stdin != nil {
+		cmd.SetIn(stdin)
+	}
 
 	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
 		return knflags.ReconcileBoolFlags(cmd.Flags())
@@ -69,3 +81,13 @@ func executeServiceCommand(client clientservingv1.KnServingClient, args ...strin
 	err := cmd.Execute()
 	return output.String(), err
 }
+
+func TestShowTagHeaderHint(t *testing.T) {
+	out := new(bytes.Buffer)
+	showTagHeaderHint([]string{"echo-v1=stable", "@latest=current"}, out)
+	assert.Assert(t, util.ContainsAll(out.String(), "Knative-Serving-Tag: stable", "Knative-Serving-Tag: current", "tag-header-based-routing"))
+
+	out.Reset()
+	showTagHeaderHint([]string{"echo-v1"}, out)
+	assert.Equal(t, out.String(), "")
+}
@@ -1520,3 +1520,37 @@ func TestServiceUpdateInitialScaleMock(t *testing.T) {
 
 	r.Validate()
 }
+
+func TestServiceUpdateRetryReappliesFlags(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+
+	service := getService("foo")
+	service.Spec.Template.Spec.Containers[0].Image = "gcr.io/foo/bar:baz"
+	service.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "a", Value: "mouse"}}
+
+	// Changed concurrently after kn has read the service
+	changed := service.DeepCopy()
+	changed.Spec.Template.Spec.Containers[0].Env = append(changed.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "c", Value: "cheese"})
+	changed.Spec.Template.Spec.Containers[0].Ports = []corev1.ContainerPort{{ContainerPort: 8080}}
+
+	r := client.Recorder()
+	r.GetService("foo", service, nil)
+	r.UpdateService(func(t *testing.T, a interface{}) {
+		env := a.(*servingv1.Service).Spec.Template.Spec.Containers[0].Env
+		assert.DeepEqual(t, env, []corev1.EnvVar{{Name: "a", Value: "rabbit"}})
+	}, errors.NewConflict(servingv1.Resource("service"), "foo", nil))
+	r.GetService("foo", changed, nil)
+	r.UpdateService(func(t *testing.T, a interface{}) {
+		container := a.(*servingv1.Service).Spec.Template.Spec.Containers[0]
+		// Only the given flags are applied again to the service as read after the conflict
+		assert.DeepEqual(t, container.Env, []corev1.EnvVar{{Name: "a", Value: "rabbit"}, {Name: "c", Value: "cheese"}})
+		assert.DeepEqual(t, container.Ports, []corev1.ContainerPort{{ContainerPort: 8080}})
+		assert.Equal(t, container.Image, "gcr.io/foo/bar:baz")
+	}, nil)
+
+	output, err := executeServiceCommand(client, "update", "foo", "--env", "a=rabbit", "--no-wait", "--revision-name=")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "updated", "foo", "default"))
+
+	r.Validate()
+}
@@ -0,0 +1,336 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"knative.dev/pkg/ptr"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+)
+
+// SharedTagsAnnotationKey holds the traffic tags created by 'kn service share'
+// together with their expiry time
+const SharedTagsAnnotationKey = "client.knative.dev/shared-tags"
+
+const shareTagPrefix = "share-"
+
+var shareExample = `
+  # Share the latest ready revision of service 'svc' for two hours
+  kn service share svc
+
+  # Share revision 'svc-00002' of service 'svc' for 30 minutes
+  kn service share svc --revision svc-00002 --expires 30m
+
+  # Remove the shares of service 'svc' which are expired
+  kn service unshare svc --expired
+
+  # Remove the share 'share-xkcdq' of service 'svc'
+  kn service unshare svc share-xkcdq`
+
+// newShareTag returns a new random tag name, can be replaced in tests
+var newShareTag = func() string {
+	chars := []byte("bcdfghjklmnpqrstvwxz")
+	tag := make([]byte, 5)
+	for i := range tag {
+		tag[i] = chars[rand.Intn(len(chars))]
+	}
+	return shareTagPrefix + string(tag)
+}
+
+// NewServiceShareCommand returns a new command for sharing a revision with a temporary tag URL
+func NewServiceShareCommand(p *commands.KnParams) *cobra.Command {
+	var revision string
+	var expires time.Duration
+	var waitFlags commands.WaitFlags
+
+	command := &cobra.Command{
+		Use:   "share NAME",
+		Short: "Share a revision of a service with a temporary tag URL",
+		Long: "Share a revision of a service with a temporary URL. A uniquely named traffic tag with 0% of the traffic is added " +
+			"for the revision, so that it gets a dedicated URL without receiving any regular traffic. " +
+			"The expiry time of the share is recorded in the annotation '" + SharedTagsAnnotationKey + "'. " +
+			"Expired shares are not removed automatically, use 'kn service unshare --expired' for cleaning them up.",
+		Example: shareExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("'service share' requires the service name given as single argument")
+			}
+			if expires <= 0 {
+				return errors.New("'service share' requires a positive duration given with --expires")
+			}
+			name := args[0]
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			retryPolicy, err := p.RetryPolicy(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := p.NewServingClient(namespace)
+			if err != nil {
+				return err
+			}
+			if revision != "" {
+				_, err = client.GetRevision(revision)
+				if err != nil {
+					return err
+				}
+			}
+
+			tag := newShareTag()
+			expiry := time.Now().Add(expires).UTC().Truncate(time.Second)
+			var expired []string
+			err = client.UpdateServiceWithRetry(name, func(service *servingv1.Service) (*servingv1.Service, error) {
+				expired, err = addShare(service, tag, revision, expiry)
+				return service, err
+			}, retryPolicy)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Share '%s' of service '%s' in namespace '%s' added, it expires at %s.\n",
+				tag, name, namespace, expiry.Local().Format(time.RFC1123))
+			if len(expired) > 0 {
+				fmt.Fprintf(out, "Shares %v are expired, remove them with 'kn service unshare %s --expired'.\n", expired, name)
+			}
+			if !waitFlags.Wait {
+				return nil
+			}
+			fmt.Fprintln(out, "")
+			err = waitForService(client, name, out, waitFlags)
+			if err != nil {
+				return err
+			}
+			return showShareURL(client, name, tag, out)
+		},
+	}
+	commands.AddNamespaceFlags(command.Flags(), false)
+	command.Flags().StringVar(&revision, "revision", "", "Revision to share. Defaults to the latest ready revision.")
+	command.Flags().DurationVar(&expires, "expires", 2*time.Hour, "Duration after which the share expires, e.g. 30m or 2h.")
+	waitFlags.AddConditionWaitFlags(command, commands.WaitDefaultTimeout, "share", "service", "ready")
+	waitFlags.AddProgressFlags(command)
+	waitFlags.AddVerboseErrorsFlag(command, p)
+	return command
+}
+
+// NewServiceUnshareCommand returns a new command for removing tags added by 'kn service share'
+func NewServiceUnshareCommand(p *commands.KnParams) *cobra.Command {
+	var expiredOnly bool
+	var all bool
+
+	command := &cobra.Command{
+		Use:     "unshare NAME [TAG ...]",
+		Short:   "Remove temporary tag URLs added with 'kn service share'",
+		Example: shareExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("'service unshare' requires the service name")
+			}
+			name, tags := args[0], args[1:]
+			selected := 0
+			for _, given := range []bool{len(tags) > 0, expiredOnly, all} {
+				if given {
+					selected++
+				}
+			}
+			if selected != 1 {
+				return errors.New("'service unshare' requires exactly one of tag names, --expired or --all")
+			}
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			retryPolicy, err := p.RetryPolicy(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := p.NewServingClient(namespace)
+			if err != nil {
+				return err
+			}
+
+			var removed []string
+			err = client.UpdateServiceWithRetry(name, func(service *servingv1.Service) (*servingv1.Service, error) {
+				removed, err = removeShares(service, tags, expiredOnly)
+				return service, err
+			}, retryPolicy)
+			if err != nil {
+				return err
+			}
+			if len(removed) == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "No shares of service '%s' in namespace '%s' to remove.\n", name, namespace)
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Shares %v of service '%s' in namespace '%s' removed.\n", removed, name, namespace)
+			return nil
+		},
+	}
+	commands.AddNamespaceFlags(command.Flags(), false)
+	command.Flags().BoolVar(&expiredOnly, "expired", false, "Remove all shares which are expired.")
+	command.Flags().BoolVar(&all, "all", false, "Remove all shares of the service.")
+	return command
+}
+
+// sharedTags returns the shares recorded in the annotation of the service
+func sharedTags(service *servingv1.Service) (map[string]time.Time, error) {
+	shares := map[string]time.Time{}
+	saved, ok := service.Annotations[SharedTagsAnnotationKey]
+	if !ok {
+		return shares, nil
+	}
+	err := json.Unmarshal([]byte(saved), &shares)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the shares of service '%s' from annotation '%s': %v", service.Name, SharedTagsAnnotationKey, err)
+	}
+	return shares, nil
+}
+
+func setSharedTags(service *servingv1.Service, shares map[string]time.Time) error {
+	if len(shares) == 0 {
+		delete(service.Annotations, SharedTagsAnnotationKey)
+		return nil
+	}
+	saved, err := json.Marshal(shares)
+	if err != nil {
+		return err
+	}
+	if service.Annotations == nil {
+		service.Annotations = map[string]string{}
+	}
+	service.Annotations[SharedTagsAnnotationKey] = string(saved)
+	return nil
+}
+
+// addShare adds a traffic target with 0% for the tag and records its expiry.
+// The revision defaults to the latest ready revision. Returns the tags of
+// shares which are already expired.
+func addShare(service *servingv1.Service, tag string, revision string, expiry time.Time) ([]string, error) {
+	if revision == "" {
+		revision = service.Status.LatestReadyRevisionName
+		if revision == "" {
+			return nil, fmt.Errorf("service '%s' has no ready revision to share", service.Name)
+		}
+	}
+	for _, target := range service.Spec.Traffic {
+		if target.Tag == tag {
+			return nil, fmt.Errorf("tag '%s' is already used by service '%s'", tag, service.Name)
+		}
+	}
+	if len(service.Spec.Traffic) == 0 {
+		// Keep the implicit default of routing all traffic to the latest revision
+		service.Spec.Traffic = []servingv1.TrafficTarget{{
+			LatestRevision: ptr.Bool(true),
+			Percent:        ptr.Int64(100),
+		}}
+	}
+
+	shares, err := sharedTags(service)
+	if err != nil {
+		return nil, err
+	}
+	service.Spec.Traffic = append(service.Spec.Traffic, servingv1.TrafficTarget{
+		Tag:            tag,
+		RevisionName:   revision,
+		LatestRevision: ptr.Bool(false),
+		Percent:        ptr.Int64(0),
+	})
+	expired := expiredShares(shares, time.Now())
+	shares[tag] = expiry
+	return expired, setSharedTags(service, shares)
+}
+
+// removeShares removes the given shares, or the expired ones, or all of them if
+// no tags are given and expiredOnly is false. Returns the removed tags.
+func removeShares(service *servingv1.Service, tags []string, expiredOnly bool) ([]string, error) {
+	shares, err := sharedTags(service)
+	if err != nil {
+		return nil, err
+	}
+	var remove []string
+	switch {
+	case len(tags) > 0:
+		for _, tag := range tags {
+			if _, ok := shares[tag]; !ok {
+				return nil, fmt.Errorf("service '%s' has no share '%s'", service.Name, tag)
+			}
+		}
+		remove = tags
+	case expiredOnly:
+		remove = expiredShares(shares, time.Now())
+	default:
+		for tag := range shares {
+			remove = append(remove, tag)
+		}
+		sort.Strings(remove)
+	}
+	if len(remove) == 0 {
+		return nil, nil
+	}
+
+	removeSet := map[string]bool{}
+	for _, tag := range remove {
+		removeSet[tag] = true
+		delete(shares, tag)
+	}
+	traffic := make([]servingv1.TrafficTarget, 0, len(service.Spec.Traffic))
+	for _, target := range service.Spec.Traffic {
+		if !removeSet[target.Tag] {
+			traffic = append(traffic, target)
+		}
+	}
+	service.Spec.Traffic = traffic
+	return remove, setSharedTags(service, shares)
+}
+
+// expiredShares returns the sorted tags of the shares expired at the given time
+func expiredShares(shares map[string]time.Time, now time.Time) []string {
+	var expired []string
+	for tag, expiry := range shares {
+		if !expiry.After(now) {
+			expired = append(expired, tag)
+		}
+	}
+	sort.Strings(expired)
+	return expired
+}
+
+func showShareURL(client clientservingv1.KnServingClient, name string, tag string, out io.Writer) error {
+	service, err := client.GetService(name)
+	if err != nil {
+		return err
+	}
+	for _, target := range service.Status.Traffic {
+		if target.Tag == tag && target.URL != nil {
+			fmt.Fprintf(out, "\nShared revision '%s' at:\n%s\n", target.RevisionName, target.URL.String())
+			return nil
+		}
+	}
+	return fmt.Errorf("no URL found for share '%s' of service '%s'", tag, name)
+}
@@ -0,0 +1,124 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/ptr"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+	"knative.dev/client/pkg/util/mock"
+	"knative.dev/client/pkg/wait"
+)
+
+func TestServiceShareAndUnshare(t *testing.T) {
+	defer func(f func() string) { newShareTag = f }(newShareTag)
+	newShareTag = func() string { return "share-bcdfg" }
+
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+
+	service := getService("foo")
+	service.Status.LatestReadyRevisionName = "foo-v2"
+	service.Annotations = map[string]string{
+		SharedTagsAnnotationKey: `{"share-old":"2020-01-01T00:00:00Z"}`,
+	}
+	service.Spec.Traffic = []servingv1.TrafficTarget{
+		{LatestRevision: ptr.Bool(true), Percent: ptr.Int64(100)},
+		{RevisionName: "foo-v1", LatestRevision: ptr.Bool(false), Percent: ptr.Int64(0), Tag: "share-old"},
+	}
+	r.GetService("foo", service, nil)
+	r.UpdateService(func(t *testing.T, shared *servingv1.Service) {
+		assert.DeepEqual(t, shared.Spec.Traffic[2], servingv1.TrafficTarget{
+			RevisionName: "foo-v2", LatestRevision: ptr.Bool(false), Percent: ptr.Int64(0), Tag: "share-bcdfg",
+		})
+		shares, err := sharedTags(shared)
+		assert.NilError(t, err)
+		assert.Equal(t, len(shares), 2)
+		assert.Assert(t, shares["share-bcdfg"].After(time.Now().Add(29*time.Minute)))
+		service = shared
+	}, nil)
+	r.WaitForService("foo", mock.Any(), wait.NoopMessageCallback(), nil, time.Second)
+	withURL := service.DeepCopy()
+	url, _ := apis.ParseURL("http://share-bcdfg-foo.default.example.com")
+	withURL.Status.Traffic = []servingv1.TrafficTarget{{Tag: "share-bcdfg", RevisionName: "foo-v2", URL: url}}
+	r.GetService("foo", withURL, nil)
+
+	output, err := executeServiceCommand(client, "share", "foo", "--expires", "30m")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "share-bcdfg", "expires", "[share-old] are expired", "http://share-bcdfg-foo.default.example.com"))
+	r.Validate()
+
+	r.GetService("foo", service, nil)
+	r.UpdateService(func(t *testing.T, unshared *servingv1.Service) {
+		assert.Equal(t, len(unshared.Spec.Traffic), 2)
+		assert.Equal(t, unshared.Spec.Traffic[1].Tag, "share-bcdfg")
+		shares, err := sharedTags(unshared)
+		assert.NilError(t, err)
+		_, found := shares["share-old"]
+		assert.Assert(t, !found)
+		service = unshared
+	}, nil)
+
+	output, err = executeServiceCommand(client, "unshare", "foo", "--expired")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "[share-old]", "removed"))
+	r.Validate()
+
+	r.GetService("foo", service, nil)
+	r.UpdateService(func(t *testing.T, unshared *servingv1.Service) {
+		assert.Equal(t, len(unshared.Spec.Traffic), 1)
+		_, found := unshared.Annotations[SharedTagsAnnotationKey]
+		assert.Assert(t, !found)
+	}, nil)
+
+	output, err = executeServiceCommand(client, "unshare", "foo", "share-bcdfg")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "[share-bcdfg]", "removed"))
+	r.Validate()
+}
+
+func TestServiceShareErrors(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	_, err := executeServiceCommand(client, "share", "foo", "--expires", "0s")
+	assert.ErrorContains(t, err, "positive duration")
+
+	_, err = executeServiceCommand(client, "unshare", "foo")
+	assert.ErrorContains(t, err, "exactly one of")
+	_, err = executeServiceCommand(client, "unshare", "foo", "share-x", "--all")
+	assert.ErrorContains(t, err, "exactly one of")
+
+	_, err = addShare(getService("foo"), "share-x", "", time.Now())
+	assert.ErrorContains(t, err, "no ready revision")
+
+	service := getService("foo")
+	_, err = addShare(service, "share-x", "foo-v1", time.Now().Add(time.Hour))
+	assert.NilError(t, err)
+	assert.Equal(t, len(service.Spec.Traffic), 2)
+	_, err = addShare(service, "share-x", "foo-v1", time.Now().Add(time.Hour))
+	assert.ErrorContains(t, err, "already used")
+
+	_, err = removeShares(service, []string{"share-y"}, false)
+	assert.ErrorContains(t, err, "no share 'share-y'")
+	removed, err := removeShares(service, nil, false)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, removed, []string{"share-x"})
+}
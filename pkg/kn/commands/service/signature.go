@@ -0,0 +1,137 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/cosign"
+)
+
+// signatureFlags holds the options for verifying image signatures before deploying
+type signatureFlags struct {
+	verify         bool
+	key            string
+	keyless        bool
+	identity       string
+	identityIssuer string
+}
+
+// add adds the signature verification flags to the given command
+func (f *signatureFlags) add(command *cobra.Command) {
+	command.Flags().BoolVar(&f.verify, "verify-signature", false,
+		"Verify the cosign signatures of the images before deploying and fail if an image isn't signed as expected. "+
+			"Requires cosign to be installed and either --key or --keyless.")
+	command.Flags().StringVar(&f.key, "key", "",
+		"Public key (file, URL or KMS URI) the images must be signed with for --verify-signature.")
+	command.Flags().BoolVar(&f.keyless, "keyless", false,
+		"Verify keyless signatures for --verify-signature. The signing identity is given with --certificate-identity and --certificate-oidc-issuer.")
+	command.Flags().StringVar(&f.identity, "certificate-identity", "",
+		"Identity (e.g. an email address or workflow URL) the images must be signed by for --keyless.")
+	command.Flags().StringVar(&f.identityIssuer, "certificate-oidc-issuer", "",
+		"OIDC issuer of the signing identity for --keyless (e.g. https://token.actions.githubusercontent.com).")
+}
+
+// validate checks that the flags are given in a consistent combination
+func (f *signatureFlags) validate() error {
+	if !f.verify {
+		if f.key != "" || f.keyless || f.identity != "" || f.identityIssuer != "" {
+			return errors.New("--key, --keyless, --certificate-identity and --certificate-oidc-issuer can only be used with --verify-signature")
+		}
+		return nil
+	}
+	if (f.key == "") == !f.keyless {
+		return errors.New("--verify-signature requires either --key or --keyless")
+	}
+	if f.keyless && (f.identity == "" || f.identityIssuer == "") {
+		return errors.New("--keyless requires the expected signer given with --certificate-identity and --certificate-oidc-issuer")
+	}
+	if !f.keyless && (f.identity != "" || f.identityIssuer != "") {
+		return errors.New("--certificate-identity and --certificate-oidc-issuer can only be used with --keyless")
+	}
+	return nil
+}
+
+// verifyImages verifies the signatures of all images in the template if requested.
+// The images are pinned to the verified digests so that no other image can be deployed
+// when a tag is moved after the verification.
+func (f *signatureFlags) verifyImages(template *servingv1.RevisionTemplateSpec) error {
+	if !f.verify {
+		return nil
+	}
+	for i := range template.Spec.Containers {
+		container := &template.Spec.Containers[i]
+		digest, err := f.verifyImage(container.Image)
+		if err != nil {
+			return fmt.Errorf("signature verification failed for image '%s': %v", container.Image, err)
+		}
+		container.Image = imageRepository(container.Image) + "@" + digest
+	}
+	return nil
+}
+
+// verifyImage runs cosign for a single image and returns the digest of the verified image
+func (f *signatureFlags) verifyImage(image string) (string, error) {
+	args := []string{"verify", "--output", "json"}
+	if f.keyless {
+		args = append(args, "--certificate-identity", f.identity, "--certificate-oidc-issuer", f.identityIssuer)
+	} else {
+		args = append(args, "--key", f.key)
+	}
+	output, err := cosign.Run(append(args, image)...)
+	if err != nil {
+		return "", err
+	}
+
+	var payloads []struct {
+		Critical struct {
+			Image struct {
+				Digest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+		} `json:"critical"`
+	}
+	err = json.Unmarshal([]byte(lastLine(output)), &payloads)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse output of %s: %v", cosign.Command, err)
+	}
+	if len(payloads) == 0 || payloads[0].Critical.Image.Digest == "" {
+		return "", fmt.Errorf("%s verified no signature", cosign.Command)
+	}
+	return payloads[0].Critical.Image.Digest, nil
+}
+
+// imageRepository returns the image reference without tag and digest
+func imageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	// A colon before the last slash separates the registry's port
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
+
+// lastLine returns the last non-empty line, as cosign may print additional information before the JSON
+func lastLine(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return lines[len(lines)-1]
+}
@@ -0,0 +1,140 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"knative.dev/client/pkg/cosign"
+)
+
+const verifiedDigest = "sha256:8d2b4bf0d9c4f1b1c8b5b7e0a8a4e1f19e9b4a3f6b9e3e4b1a2c3d4e5f6a7b8c9"
+
+func TestSignatureFlagsValidate(t *testing.T) {
+	for _, tc := range []struct {
+		flags signatureFlags
+		err   string
+	}{
+		{flags: signatureFlags{}},
+		{flags: signatureFlags{verify: true, key: "cosign.pub"}},
+		{flags: signatureFlags{verify: true, keyless: true, identity: "me@example.com", identityIssuer: "https://accounts.google.com"}},
+		{flags: signatureFlags{key: "cosign.pub"}, err: "only be used with --verify-signature"},
+		{flags: signatureFlags{verify: true}, err: "either --key or --keyless"},
+		{flags: signatureFlags{verify: true, key: "cosign.pub", keyless: true}, err: "either --key or --keyless"},
+		{flags: signatureFlags{verify: true, keyless: true, identity: "me@example.com"}, err: "--certificate-oidc-issuer"},
+		{flags: signatureFlags{verify: true, key: "cosign.pub", identity: "me@example.com"}, err: "only be used with --keyless"},
+	} {
+		err := tc.flags.validate()
+		if tc.err == "" {
+			assert.NilError(t, err)
+		} else {
+			assert.ErrorContains(t, err, tc.err)
+		}
+	}
+}
+
+func TestSignatureVerifyImages(t *testing.T) {
+	var cosignArgs []string
+	defer replaceCosign(func(args ...string) ([]byte, error) {
+		cosignArgs = args
+		return []byte("\nVerification for registry.example.com:5000/foo/bar:v1 --\n" +
+			`[{"critical":{"identity":{"docker-reference":"registry.example.com:5000/foo/bar"},` +
+			`"image":{"docker-manifest-digest":"` + verifiedDigest + `"},"type":"cosign container image signature"},"optional":null}]` + "\n"), nil
+	})()
+
+	flags := signatureFlags{verify: true, keyless: true, identity: "me@example.com", identityIssuer: "https://accounts.google.com"}
+	template := templateWithImage("registry.example.com:5000/foo/bar:v1")
+	err := flags.verifyImages(template)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cosignArgs, []string{"verify", "--output", "json",
+		"--certificate-identity", "me@example.com", "--certificate-oidc-issuer", "https://accounts.google.com",
+		"registry.example.com:5000/foo/bar:v1"})
+	// The image is pinned to the verified digest
+	assert.Equal(t, template.Spec.Containers[0].Image, "registry.example.com:5000/foo/bar@"+verifiedDigest)
+}
+
+func TestSignatureVerifyImagesNotSigned(t *testing.T) {
+	defer replaceCosign(func(args ...string) ([]byte, error) {
+		return nil, errors.New("exit status 1: no matching signatures")
+	})()
+
+	flags := signatureFlags{verify: true, key: "cosign.pub"}
+	template := templateWithImage("gcr.io/foo/bar:baz")
+	err := flags.verifyImages(template)
+	assert.ErrorContains(t, err, "signature verification failed for image 'gcr.io/foo/bar:baz'")
+	assert.ErrorContains(t, err, "no matching signatures")
+	assert.Equal(t, template.Spec.Containers[0].Image, "gcr.io/foo/bar:baz")
+}
+
+func TestSignatureVerifyImagesDisabled(t *testing.T) {
+	defer replaceCosign(func(args ...string) ([]byte, error) {
+		t.Fatal("cosign must not be called without --verify-signature")
+		return nil, nil
+	})()
+
+	flags := signatureFlags{}
+	assert.NilError(t, flags.verifyImages(templateWithImage("gcr.io/foo/bar:baz")))
+}
+
+func TestServiceCreateVerifySignatureFails(t *testing.T) {
+	defer replaceCosign(func(args ...string) ([]byte, error) {
+		assert.Assert(t, strings.Contains(strings.Join(args, " "), "--key cosign.pub"))
+		return nil, errors.New("exit status 1: no matching signatures")
+	})()
+
+	action, _, _, err := fakeServiceCreate([]string{
+		"service", "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--verify-signature", "--key", "cosign.pub", "--no-wait"}, false)
+	assert.ErrorContains(t, err, "signature verification failed")
+	// Nothing must have been deployed
+	assert.Assert(t, action == nil)
+}
+
+func TestServiceCreateVerifySignature(t *testing.T) {
+	defer replaceCosign(func(args ...string) ([]byte, error) {
+		return []byte(`[{"critical":{"image":{"docker-manifest-digest":"` + verifiedDigest + `"}}}]`), nil
+	})()
+
+	_, created, _, err := fakeServiceCreate([]string{
+		"service", "create", "foo", "--image", "gcr.io/foo/bar:baz",
+		"--verify-signature", "--key", "cosign.pub", "--no-wait"}, false)
+	assert.NilError(t, err)
+	assert.Equal(t, created.Spec.Template.Spec.Containers[0].Image, "gcr.io/foo/bar@"+verifiedDigest)
+}
+
+func TestImageRepository(t *testing.T) {
+	for image, expected := range map[string]string{
+		"gcr.io/foo/bar":                       "gcr.io/foo/bar",
+		"gcr.io/foo/bar:baz":                   "gcr.io/foo/bar",
+		"gcr.io/foo/bar@" + verifiedDigest:     "gcr.io/foo/bar",
+		"gcr.io/foo/bar:baz@" + verifiedDigest: "gcr.io/foo/bar",
+		"localhost:5000/bar":                   "localhost:5000/bar",
+		"localhost:5000/bar:v1":                "localhost:5000/bar",
+	} {
+		assert.Equal(t, imageRepository(image), expected)
+	}
+}
+
+func replaceCosign(run func(args ...string) ([]byte, error)) func() {
+	oldRun := cosign.Run
+	cosign.Run = run
+	return func() {
+		cosign.Run = oldRun
+	}
+}
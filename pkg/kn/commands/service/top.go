@@ -0,0 +1,212 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/serving/pkg/apis/serving"
+
+	clientdynamic "knative.dev/client/pkg/dynamic"
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/printers"
+)
+
+// Name of the sidecar container injected by Knative serving
+const queueProxyContainerName = "queue-proxy"
+
+// Pod metrics as provided by the metrics-server
+var podMetricsGVR = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+
+// revisionUsage holds the aggregated resource usage of all pods of a revision
+type revisionUsage struct {
+	revision string
+	pods     int64
+	cpu      resource.Quantity
+	memory   resource.Quantity
+
+	// highest memory usage of a single pod
+	maxPodMemory resource.Quantity
+}
+
+var topExample = `
+  # Show CPU and memory usage per revision of service 'svc'
+  kn service top svc`
+
+// NewServiceTopCommand returns a new command for showing the resource usage of a service
+func NewServiceTopCommand(p *commands.KnParams) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "top NAME",
+		Short: "Show resource usage of a service per revision",
+		Long: "Show CPU and memory usage of a service aggregated per revision. " +
+			"The usage is taken from the metrics-server and covers the application containers only (i.e. without the queue-proxy sidecar).",
+		Example: topExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("'service top' requires the service name given as single argument")
+			}
+			serviceName := args[0]
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := p.NewServingClient(namespace)
+			if err != nil {
+				return err
+			}
+			// Verify that the service exists first
+			_, err = client.GetService(serviceName)
+			if err != nil {
+				return err
+			}
+
+			dynamicClient, err := p.NewDynamicClient(namespace)
+			if err != nil {
+				return err
+			}
+			usages, err := getRevisionUsages(dynamicClient, serviceName)
+			if err != nil {
+				return err
+			}
+			if len(usages) == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "No running pods found for service '%s'.\n", serviceName)
+				return nil
+			}
+			return printRevisionUsages(cmd.OutOrStdout(), usages)
+		},
+	}
+	commands.AddNamespaceFlags(command.Flags(), false)
+	return command
+}
+
+// getRevisionUsages fetches the pod metrics of all pods of the given service and aggregates
+// the usage of the application containers per revision. The result is sorted by revision name.
+func getRevisionUsages(dynamicClient clientdynamic.KnDynamicClient, serviceName string) ([]*revisionUsage, error) {
+	podMetricsList, err := dynamicClient.RawClient().Resource(podMetricsGVR).Namespace(dynamicClient.Namespace()).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: labels.Set{serving.ServiceLabelKey: serviceName}.String(),
+	})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("no pod metrics available, please verify that the metrics-server is installed in the cluster: %v", err)
+		}
+		return nil, err
+	}
+
+	usagesByRevision := map[string]*revisionUsage{}
+	for _, podMetrics := range podMetricsList.Items {
+		revision := podMetrics.GetLabels()[serving.RevisionLabelKey]
+		usage, ok := usagesByRevision[revision]
+		if !ok {
+			usage = &revisionUsage{revision: revision}
+			usagesByRevision[revision] = usage
+		}
+		usage.pods++
+		err := addContainerUsages(usage, podMetrics)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	usages := make([]*revisionUsage, 0, len(usagesByRevision))
+	for _, usage := range usagesByRevision {
+		usages = append(usages, usage)
+	}
+	sort.SliceStable(usages, func(i, j int) bool {
+		return usages[i].revision < usages[j].revision
+	})
+	return usages, nil
+}
+
+// addContainerUsages adds the usage of all application containers of the given pod metrics
+func addContainerUsages(usage *revisionUsage, podMetrics unstructured.Unstructured) error {
+	containers, _, err := unstructured.NestedSlice(podMetrics.Object, "containers")
+	if err != nil {
+		return fmt.Errorf("cannot extract containers from pod metrics %s: %v", podMetrics.GetName(), err)
+	}
+	var podCPU, podMemory resource.Quantity
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok || container["name"] == queueProxyContainerName {
+			continue
+		}
+		containerUsage, _, err := unstructured.NestedStringMap(container, "usage")
+		if err != nil {
+			return fmt.Errorf("cannot extract usage from pod metrics %s: %v", podMetrics.GetName(), err)
+		}
+		for name, target := range map[string]*resource.Quantity{"cpu": &podCPU, "memory": &podMemory} {
+			value, ok := containerUsage[name]
+			if !ok {
+				continue
+			}
+			quantity, err := resource.ParseQuantity(value)
+			if err != nil {
+				return fmt.Errorf("invalid %s usage '%s' in pod metrics %s: %v", name, value, podMetrics.GetName(), err)
+			}
+			target.Add(quantity)
+		}
+	}
+	usage.cpu.Add(podCPU)
+	usage.memory.Add(podMemory)
+	if podMemory.Cmp(usage.maxPodMemory) > 0 {
+		usage.maxPodMemory = podMemory
+	}
+	return nil
+}
+
+// averageCPU returns the average CPU usage per pod
+func (u *revisionUsage) averageCPU() *resource.Quantity {
+	return resource.NewMilliQuantity(u.cpu.MilliValue()/u.pods, resource.DecimalSI)
+}
+
+// averageMemory returns the average memory usage per pod
+func (u *revisionUsage) averageMemory() *resource.Quantity {
+	return resource.NewQuantity(u.memory.Value()/u.pods, resource.BinarySI)
+}
+
+func printRevisionUsages(out io.Writer, usages []*revisionUsage) error {
+	tw := printers.NewTabWriter(out)
+	fmt.Fprintln(tw, "REVISION\tPODS\tCPU\tMEMORY\tCPU/POD\tMEMORY/POD")
+	for _, usage := range usages {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\n",
+			usage.revision,
+			usage.pods,
+			formatMilliCPU(&usage.cpu),
+			formatMemory(&usage.memory),
+			formatMilliCPU(usage.averageCPU()),
+			formatMemory(usage.averageMemory()))
+	}
+	return tw.Flush()
+}
+
+func formatMilliCPU(q *resource.Quantity) string {
+	return fmt.Sprintf("%dm", q.MilliValue())
+}
+
+func formatMemory(q *resource.Quantity) string {
+	return fmt.Sprintf("%dMi", q.Value()/(1024*1024))
+}
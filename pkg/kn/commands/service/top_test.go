@@ -0,0 +1,150 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	clienttesting "k8s.io/client-go/testing"
+	"knative.dev/serving/pkg/apis/serving"
+
+	clientdynamic "knative.dev/client/pkg/dynamic"
+	"knative.dev/client/pkg/kn/commands"
+	knflags "knative.dev/client/pkg/kn/flags"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+)
+
+func executeServiceMetricsCommand(client clientservingv1.KnServingClient, podMetrics *unstructured.UnstructuredList, metricsErr error, args ...string) (string, error) {
+	return executeServiceMetricsCommandWithEvents(client, podMetrics, metricsErr, nil, args...)
+}
+
+func executeServiceMetricsCommandWithEvents(client clientservingv1.KnServingClient, podMetrics *unstructured.UnstructuredList, metricsErr error, events []runtime.Object, args ...string) (string, error) {
+	knParams := &commands.KnParams{}
+	knParams.ClientConfig = blankConfig
+
+	output := new(bytes.Buffer)
+	knParams.Output = output
+	knParams.NewServingClient = func(namespace string) (clientservingv1.KnServingClient, error) {
+		return client, nil
+	}
+	fakeDynamic := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	fakeDynamic.PrependReactor("list", "pods", func(a clienttesting.Action) (bool, runtime.Object, error) {
+		if metricsErr != nil {
+			return true, nil, metricsErr
+		}
+		return true, podMetrics, nil
+	})
+	knParams.NewDynamicClient = func(namespace string) (clientdynamic.KnDynamicClient, error) {
+		return clientdynamic.NewKnDynamicClient(fakeDynamic, namespace), nil
+	}
+	knParams.NewKubeClient = func() (kubernetes.Interface, error) {
+		return commands.NewFakeKubeClient(events...), nil
+	}
+	cmd := NewServiceCommand(knParams)
+	cmd.SetArgs(args)
+	cmd.SetOutput(output)
+
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return knflags.ReconcileBoolFlags(cmd.Flags())
+	}
+	err := cmd.Execute()
+	return output.String(), err
+}
+
+func TestServiceTop(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", getService("foo"), nil)
+
+	podMetrics := &unstructured.UnstructuredList{}
+	podMetrics.Items = []unstructured.Unstructured{
+		createPodMetrics("foo-pod-1", "foo", "foo-rev-2", "100m", "64Mi"),
+		createPodMetrics("foo-pod-2", "foo", "foo-rev-2", "300m", "128Mi"),
+		createPodMetrics("foo-pod-3", "foo", "foo-rev-1", "50m", "32Mi"),
+	}
+
+	output, err := executeServiceMetricsCommand(client, podMetrics, nil, "top", "foo")
+	assert.NilError(t, err)
+	lines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(lines[0], "REVISION", "PODS", "CPU", "MEMORY", "CPU/POD", "MEMORY/POD"))
+	assert.Check(t, util.ContainsAll(lines[1], "foo-rev-1", "1", "50m", "32Mi"))
+	assert.Check(t, util.ContainsAll(lines[2], "foo-rev-2", "2", "400m", "192Mi", "200m", "96Mi"))
+	r.Validate()
+}
+
+func TestServiceTopNoPods(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", getService("foo"), nil)
+
+	output, err := executeServiceMetricsCommand(client, &unstructured.UnstructuredList{}, nil, "top", "foo")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "No running pods", "foo"))
+	r.Validate()
+}
+
+func TestServiceTopNoMetricsServer(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", getService("foo"), nil)
+
+	_, err := executeServiceMetricsCommand(client, nil, apierrors.NewNotFound(schema.GroupResource{Group: "metrics.k8s.io", Resource: "pods"}, ""), "top", "foo")
+	assert.ErrorContains(t, err, "metrics-server")
+	r.Validate()
+}
+
+func TestServiceTopNoName(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	_, err := executeServiceMetricsCommand(client, nil, nil, "top")
+	assert.ErrorContains(t, err, "requires the service name")
+}
+
+func createPodMetrics(name, service, revision, cpu, memory string) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "metrics.k8s.io/v1beta1",
+			"kind":       "PodMetrics",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "default",
+				"labels": map[string]interface{}{
+					serving.ServiceLabelKey:  service,
+					serving.RevisionLabelKey: revision,
+				},
+			},
+			"containers": []interface{}{
+				map[string]interface{}{
+					"name":  "user-container",
+					"usage": map[string]interface{}{"cpu": cpu, "memory": memory},
+				},
+				map[string]interface{}{
+					"name":  "queue-proxy",
+					"usage": map[string]interface{}{"cpu": "1000m", "memory": "1Gi"},
+				},
+			},
+		},
+	}
+}
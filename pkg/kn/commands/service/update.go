@@ -20,6 +20,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"knative.dev/client/pkg/i18n"
 	"knative.dev/client/pkg/kn/commands/flags"
 	"knative.dev/client/pkg/kn/traffic"
 
@@ -53,7 +54,12 @@ var updateExample = `
 func NewServiceUpdateCommand(p *commands.KnParams) *cobra.Command {
 	var editFlags ConfigurationEditFlags
 	var waitFlags commands.WaitFlags
+	var signature signatureFlags
+	var policy policyFlags
+	var preflight bool
 	var trafficFlags flags.Traffic
+	var output outputFlags
+	var dryRun dryRunFlags
 	serviceUpdateCommand := &cobra.Command{
 		Use:     "update NAME",
 		Short:   "Update a service",
@@ -62,11 +68,34 @@ func NewServiceUpdateCommand(p *commands.KnParams) *cobra.Command {
 			if len(args) != 1 {
 				return errors.New("'service update' requires the service name given as single argument")
 			}
+			err = signature.validate()
+			if err != nil {
+				return err
+			}
+			err = output.validate()
+			if err != nil {
+				return err
+			}
+			err = dryRun.validate(p)
+			if err != nil {
+				return err
+			}
+			out := output.progressOut(cmd)
 
 			namespace, err := p.GetNamespace(cmd)
 			if err != nil {
 				return err
 			}
+			retryPolicy, err := p.RetryPolicy(cmd)
+			if err != nil {
+				return err
+			}
+			if preflight && !dryRun.client() {
+				err = preflightAccessCheck(p, namespace, "'kn service update'", updateAccessChecks)
+				if err != nil {
+					return err
+				}
+			}
 
 			client, err := p.NewServingClient(namespace)
 			if err != nil {
@@ -75,6 +104,8 @@ func NewServiceUpdateCommand(p *commands.KnParams) *cobra.Command {
 
 			// Use to store the latest revision name
 			var latestRevisionBeforeUpdate string
+			// The service as last sent to the API server
+			var updatedService *servingv1.Service
 			name := args[0]
 
 			updateFunc := func(service *servingv1.Service) (*servingv1.Service, error) {
@@ -83,13 +114,29 @@ func NewServiceUpdateCommand(p *commands.KnParams) *cobra.Command {
 				if !cmd.Flags().Changed("image") && editFlags.LockToDigest {
 					baseRevision, err = client.GetBaseRevision(service)
 					if _, ok := err.(*clientservingv1.NoBaseRevisionError); ok {
-						fmt.Fprintf(cmd.OutOrStdout(), "Warning: No revision found to update image digest")
+						fmt.Fprintf(out, "Warning: No revision found to update image digest")
 					}
 				}
 				err = editFlags.Apply(service, baseRevision, cmd)
 				if err != nil {
 					return nil, err
 				}
+				err = resolveImageStreamTags(p, namespace, &service.Spec.Template)
+				if err != nil {
+					return nil, err
+				}
+				err = signature.verifyImages(&service.Spec.Template)
+				if err != nil {
+					return nil, err
+				}
+				err = pinImageDigests(&service.Spec.Template, editFlags.PinImageDigest)
+				if err != nil {
+					return nil, err
+				}
+				err = pinImagesToPlatform(p, &service.Spec.Template, editFlags.Platform, out)
+				if err != nil {
+					return nil, err
+				}
 
 				if trafficFlags.Changed(cmd) {
 					traffic, err := traffic.Compute(cmd, service.Spec.Traffic, &trafficFlags, service.Name)
@@ -99,29 +146,57 @@ func NewServiceUpdateCommand(p *commands.KnParams) *cobra.Command {
 
 					service.Spec.Traffic = traffic
 				}
+				err = policy.check(p, service, out)
+				if err != nil {
+					return nil, err
+				}
+				updatedService = service
 				return service, nil
 			}
 
+			if dryRun.client() {
+				service, err := client.GetService(name)
+				if err != nil {
+					return err
+				}
+				service, err = updateFunc(service.DeepCopy())
+				if err != nil {
+					return err
+				}
+				return dryRun.print(service, &output, cmd.OutOrStdout())
+			}
+
 			// Do the actual update with retry in case of conflicts
-			err = client.UpdateServiceWithRetry(name, updateFunc, MaxUpdateRetries)
+			err = client.UpdateServiceWithRetry(name, updateFunc, retryPolicy)
 			if err != nil {
 				return err
 			}
+			if dryRun.server() {
+				return dryRun.print(updatedService, &output, cmd.OutOrStdout())
+			}
 
-			out := cmd.OutOrStdout()
 			if waitFlags.Wait {
-				fmt.Fprintf(out, "Updating Service '%s' in namespace '%s':\n", args[0], namespace)
+				i18n.Fprintf(out, "Updating Service '%s' in namespace '%s':\n", args[0], namespace)
 				fmt.Fprintln(out, "")
-				err := waitForService(client, name, out, waitFlags.TimeoutInSeconds)
+				err := waitForService(client, name, out, waitFlags)
 				if err != nil {
 					return err
 				}
 				fmt.Fprintln(out, "")
-				return showUrl(client, name, latestRevisionBeforeUpdate, "updated", out)
+				err = showUrl(client, name, latestRevisionBeforeUpdate, "updated", out)
+				if err != nil {
+					return err
+				}
 			} else {
-				fmt.Fprintf(out, "Service '%s' updated in namespace '%s'.\n", args[0], namespace)
+				i18n.Fprintf(out, "Service '%s' updated in namespace '%s'.\n", args[0], namespace)
 			}
 
+			if cmd.Flags().Changed("tag") {
+				showTagHeaderHint(trafficFlags.RevisionsTags, out)
+			}
+			if output.enabled() {
+				return printService(client, name, &output, cmd.OutOrStdout())
+			}
 			return nil
 
 		},
@@ -132,7 +207,16 @@ func NewServiceUpdateCommand(p *commands.KnParams) *cobra.Command {
 
 	commands.AddNamespaceFlags(serviceUpdateCommand.Flags(), false)
 	editFlags.AddUpdateFlags(serviceUpdateCommand)
+	serviceUpdateCommand.Flags().BoolVar(&preflight, "preflight", false,
+		"Check that all permissions required for updating the service are granted before doing any change.")
+	signature.add(serviceUpdateCommand)
+	policy.add(serviceUpdateCommand)
+	output.add(serviceUpdateCommand, "updated")
+	dryRun.add(serviceUpdateCommand)
 	waitFlags.AddConditionWaitFlags(serviceUpdateCommand, commands.WaitDefaultTimeout, "update", "service", "ready")
+	waitFlags.AddRoutePropagationFlags(serviceUpdateCommand)
+	waitFlags.AddProgressFlags(serviceUpdateCommand)
+	waitFlags.AddVerboseErrorsFlag(serviceUpdateCommand, p)
 	trafficFlags.Add(serviceUpdateCommand)
 	return serviceUpdateCommand
 }
@@ -392,6 +392,43 @@ func TestServiceUpdateScale(t *testing.T) {
 
 }
 
+func TestServiceUpdateScaleMinAboveExistingMax(t *testing.T) {
+	original := newEmptyService()
+	original.Spec.Template.Annotations = map[string]string{
+		"autoscaling.knative.dev/minScale": "1",
+		"autoscaling.knative.dev/maxScale": "3",
+	}
+
+	_, _, _, err := fakeServiceUpdate(original, []string{
+		"service", "update", "foo", "--scale-min", "5", "--no-wait"})
+	assert.ErrorContains(t, err, "maxScale=3 is less than minScale=5")
+
+	action, updated, _, err := fakeServiceUpdate(original, []string{
+		"service", "update", "foo", "--scale-min", "5", "--scale-max", "10", "--no-wait"})
+	assert.NilError(t, err)
+	assert.Assert(t, action.Matches("update", "services"))
+	assert.Equal(t, updated.Spec.Template.Annotations["autoscaling.knative.dev/minScale"], "5")
+	assert.Equal(t, updated.Spec.Template.Annotations["autoscaling.knative.dev/maxScale"], "10")
+}
+
+func TestServiceUpdateScaleWindow(t *testing.T) {
+	original := newEmptyService()
+
+	action, updated, _, err := fakeServiceUpdate(original, []string{
+		"service", "update", "foo", "--scale-window", "30s", "--no-wait"})
+	assert.NilError(t, err)
+	assert.Assert(t, action.Matches("update", "services"))
+	assert.Equal(t, updated.Spec.Template.Annotations["autoscaling.knative.dev/window"], "30s")
+
+	_, _, _, err = fakeServiceUpdate(original, []string{
+		"service", "update", "foo", "--scale-window", "2h", "--no-wait"})
+	assert.ErrorContains(t, err, "autoscaling.knative.dev/window")
+
+	_, _, _, err = fakeServiceUpdate(original, []string{
+		"service", "update", "foo", "--concurrency-utilization", "120", "--no-wait"})
+	assert.ErrorContains(t, err, "autoscaling.knative.dev/targetUtilizationPercentage")
+}
+
 func TestServiceUpdateScaleWithNegativeValue(t *testing.T) {
 	original := newEmptyService()
 
@@ -0,0 +1,136 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"knative.dev/pkg/apis"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/wait"
+)
+
+// serviceWaitConditions are the conditions of a service accepted by --for
+var serviceWaitConditions = []apis.ConditionType{
+	servingv1.ServiceConditionReady,
+	servingv1.ServiceConditionRoutesReady,
+	servingv1.ServiceConditionConfigurationsReady,
+}
+
+var waitExample = `
+  # Wait at most five minutes until service 'mysvc' is ready, e.g. after a GitOps sync
+  kn service wait mysvc --timeout 300
+
+  # Wait until the routes of service 'mysvc' are ready
+  kn service wait mysvc --for RoutesReady`
+
+// NewServiceWaitCommand returns a new command for waiting until a condition of a service is True
+func NewServiceWaitCommand(p *commands.KnParams) *cobra.Command {
+	var waitFlags commands.WaitFlags
+	var forCondition string
+
+	command := &cobra.Command{
+		Use:   "wait NAME",
+		Short: "Wait until a service reaches a condition",
+		Long: "Block until a condition of a service becomes True, e.g. after the service has been changed by " +
+			"other tools than kn. Fail if the condition becomes False or the timeout is reached. " +
+			"Supported conditions: " + conditionTypesString(serviceWaitConditions),
+		Example: waitExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("'service wait' requires the service name given as single argument")
+			}
+			name := args[0]
+			conditionType, err := parseServiceWaitCondition(forCondition)
+			if err != nil {
+				return err
+			}
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := p.NewServingClient(namespace)
+			if err != nil {
+				return err
+			}
+			// Fail fast instead of waiting for a service which doesn't exist
+			_, err = client.GetService(name)
+			if err != nil {
+				return err
+			}
+			return waitForServiceCondition(client, name, conditionType, cmd.OutOrStdout(), waitFlags)
+		},
+	}
+	commands.AddNamespaceFlags(command.Flags(), false)
+	command.Flags().StringVar(&forCondition, "for", string(apis.ConditionReady),
+		"Condition to wait for, one of "+conditionTypesString(serviceWaitConditions)+". It has to become True.")
+	command.Flags().IntVar(&waitFlags.TimeoutInSeconds, "timeout", commands.WaitDefaultTimeout,
+		"Seconds to wait before giving up.")
+	waitFlags.AddProgressFlags(command)
+	waitFlags.AddVerboseErrorsFlag(command, p)
+	return command
+}
+
+// parseServiceWaitCondition returns the condition of --for. Like for 'kn wait', it
+// can be prefixed with "condition=" and case doesn't matter.
+func parseServiceWaitCondition(value string) (apis.ConditionType, error) {
+	name := strings.TrimPrefix(value, "condition=")
+	for _, conditionType := range serviceWaitConditions {
+		if strings.EqualFold(name, string(conditionType)) {
+			return conditionType, nil
+		}
+	}
+	return "", fmt.Errorf("invalid value '%s' for --for, must be one of %s", value, conditionTypesString(serviceWaitConditions))
+}
+
+// waitForServiceCondition waits like waitForService, but for the given condition
+func waitForServiceCondition(client clientservingv1.KnServingClient, serviceName string, conditionType apis.ConditionType, out io.Writer, waitFlags commands.WaitFlags) error {
+	reporter, err := wait.NewProgressReporter(waitFlags.Progress, out)
+	if err != nil {
+		return err
+	}
+	condition := string(conditionType)
+	err, duration := client.WaitForServiceCondition(serviceName, conditionType, time.Duration(waitFlags.TimeoutInSeconds)*time.Second,
+		wait.ProgressConditionMessageCallback(reporter, "service", serviceName, condition))
+	if err != nil {
+		reporter.Report(wait.ProgressEvent{Phase: wait.PhaseFailed, Kind: "service", Name: serviceName, Condition: condition, Message: err.Error(), Elapsed: duration})
+		if waitFlags.Progress == "" || waitFlags.Progress == wait.ProgressFormatText {
+			printConditionsTable(client, serviceName, out)
+			if waitFlags.VerboseErrors {
+				printDiagnostics(client, serviceName, waitFlags, out)
+			}
+		}
+		return err
+	}
+	reporter.Report(wait.ProgressEvent{Phase: wait.PhaseReady, Kind: "service", Name: serviceName, Condition: condition, Elapsed: duration})
+	return nil
+}
+
+func conditionTypesString(conditionTypes []apis.ConditionType) string {
+	names := make([]string, len(conditionTypes))
+	for i, conditionType := range conditionTypes {
+		names[i] = string(conditionType)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,90 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"knative.dev/pkg/apis"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+	"knative.dev/client/pkg/util/mock"
+)
+
+func TestServiceWait(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", getService("foo"), nil)
+	r.WaitForServiceCondition("foo", apis.ConditionType("RoutesReady"), 300*time.Second, mock.Any(), nil, time.Second)
+
+	output, err := executeServiceCommand(client, "wait", "foo", "--for", "condition=routesready", "--timeout", "300")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Condition RoutesReady is True."))
+	r.Validate()
+}
+
+func TestServiceWaitReadyDefault(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", getService("foo"), nil)
+	r.WaitForServiceCondition("foo", apis.ConditionReady, 600*time.Second, mock.Any(), nil, time.Second)
+
+	output, err := executeServiceCommand(client, "wait", "foo")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Ready to serve."))
+	r.Validate()
+}
+
+func TestServiceWaitFailed(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", getService("foo"), nil)
+	r.WaitForServiceCondition("foo", apis.ConditionReady, mock.Any(), mock.Any(), errors.New("timeout: service 'foo' not ready after 10 seconds"), 10*time.Second)
+	// Conditions table
+	r.GetService("foo", nil, errors.New("gone"))
+
+	_, err := executeServiceCommand(client, "wait", "foo", "--timeout", "10")
+	assert.ErrorContains(t, err, "not ready after 10 seconds")
+	r.Validate()
+}
+
+func TestServiceWaitNotFound(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", nil, apierrors.NewNotFound(servingv1.Resource("service"), "foo"))
+
+	_, err := executeServiceCommand(client, "wait", "foo")
+	assert.ErrorContains(t, err, "not found")
+	r.Validate()
+}
+
+func TestServiceWaitInvalid(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+
+	_, err := executeServiceCommand(client, "wait")
+	assert.ErrorContains(t, err, "requires the service name")
+
+	_, err = executeServiceCommand(client, "wait", "foo", "--for", "Deployed")
+	assert.ErrorContains(t, err, "invalid value 'Deployed' for --for, must be one of Ready, RoutesReady, ConfigurationsReady")
+
+	_, err = executeServiceCommand(client, "wait", "foo", "--wait-progress", "xml")
+	assert.ErrorContains(t, err, "must be one of")
+}
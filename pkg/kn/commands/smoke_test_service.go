@@ -0,0 +1,74 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/wait"
+)
+
+// DefaultSmokeTestImage is a tiny image answering HTTP requests, used for smoke testing a namespace
+const DefaultSmokeTestImage = "gcr.io/knative-samples/helloworld-go"
+
+// SmokeTestService deploys a service with the given image, waits for it to become ready and deletes it again.
+// Every step is recorded in the given report. If verify is not nil it is called with the ready service
+// before the service gets deleted, so that callers can add further checks.
+func SmokeTestService(client clientservingv1.KnServingClient, name string, image string, timeout time.Duration,
+	report *CheckReport, verify func(service *servingv1.Service)) {
+
+	service := &servingv1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: client.Namespace(),
+		},
+	}
+	service.Spec.Template.Spec.Containers = []corev1.Container{{Image: image}}
+
+	err := client.CreateService(service)
+	if err != nil {
+		report.Fail("deploy", err)
+		return
+	}
+	report.Pass("deploy", fmt.Sprintf("service '%s' created with image %s", name, image))
+
+	err, duration := client.WaitForService(name, timeout, wait.NoopMessageCallback())
+	if err != nil {
+		report.Fail("ready", err)
+	} else {
+		report.Pass("ready", fmt.Sprintf("service '%s' ready after %s", name, duration.Round(time.Second)))
+		if verify != nil {
+			ready, err := client.GetService(name)
+			if err != nil {
+				report.Fail("verify", err)
+			} else {
+				verify(ready)
+			}
+		}
+	}
+
+	err = client.DeleteService(name, timeout)
+	if err != nil {
+		report.Fail("delete", err)
+		return
+	}
+	report.Pass("delete", fmt.Sprintf("service '%s' deleted", name))
+}
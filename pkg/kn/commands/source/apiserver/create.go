@@ -99,6 +99,7 @@ func NewAPIServerCreateCommand(p *commands.KnParams) *cobra.Command {
 	commands.AddNamespaceFlags(cmd.Flags(), false)
 	updateFlags.Add(cmd)
 	sinkFlags.Add(cmd)
+	sinkFlags.AddValidateFlag(cmd)
 	cmd.MarkFlagRequired("resource")
 	cmd.MarkFlagRequired("sink")
 	return cmd
@@ -95,6 +95,7 @@ func NewBindingCreateCommand(p *commands.KnParams) *cobra.Command {
 	commands.AddNamespaceFlags(cmd.Flags(), false)
 	bindingFlags.addBindingFlags(cmd)
 	sinkFlags.Add(cmd)
+	sinkFlags.AddValidateFlag(cmd)
 	cmd.MarkFlagRequired("subject")
 	cmd.MarkFlagRequired("sink")
 
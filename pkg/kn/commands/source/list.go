@@ -85,7 +85,7 @@ func NewListCommand(p *commands.KnParams) *cobra.Command {
 			if err != nil {
 				return nil
 			}
-			if listFlags.GenericPrintFlags.OutputFlagSpecified() {
+			if !listFlags.HumanReadable() {
 				return printer.PrintObj(sourceList, cmd.OutOrStdout())
 			}
 			// Convert the source list to DuckSourceList only if human readable table printing requested
@@ -86,6 +86,7 @@ func NewPingCreateCommand(p *commands.KnParams) *cobra.Command {
 	commands.AddNamespaceFlags(cmd.Flags(), false)
 	updateFlags.addFlags(cmd)
 	sinkFlags.Add(cmd)
+	sinkFlags.AddValidateFlag(cmd)
 	cmd.MarkFlagRequired("sink")
 
 	return cmd
@@ -0,0 +1,373 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+var (
+	configMapsResource               = corev1.SchemeGroupVersion.WithResource("configmaps")
+	eventsResource                   = corev1.SchemeGroupVersion.WithResource("events")
+	limitRangesResource              = corev1.SchemeGroupVersion.WithResource("limitranges")
+	namespacesResource               = corev1.SchemeGroupVersion.WithResource("namespaces")
+	nodesResource                    = corev1.SchemeGroupVersion.WithResource("nodes")
+	podsResource                     = corev1.SchemeGroupVersion.WithResource("pods")
+	resourceQuotasResource           = corev1.SchemeGroupVersion.WithResource("resourcequotas")
+	secretsResource                  = corev1.SchemeGroupVersion.WithResource("secrets")
+	serviceAccountsResource          = corev1.SchemeGroupVersion.WithResource("serviceaccounts")
+	selfSubjectAccessReviewsResource = authorizationv1.SchemeGroupVersion.WithResource("selfsubjectaccessreviews")
+)
+
+// FakeKubeClient is a fake Kubernetes clientset for tests which supports only the resources used by kn:
+// config maps, events, limit ranges, namespaces, nodes, pods, resource quotas, secrets and service accounts of the core API,
+// and self subject access reviews.
+// Objects are kept in an object tracker, and reactors can be prepended as for the generated fakes.
+// Calling any other API group panics.
+type FakeKubeClient struct {
+	kubernetes.Interface
+	clienttesting.Fake
+
+	// PodLogs returns the logs served for a pod's container, which is empty when not set
+	PodLogs func(namespace string, pod string, opts *corev1.PodLogOptions) string
+}
+
+// NewFakeKubeClient creates a fake Kubernetes clientset which is populated with the given objects
+func NewFakeKubeClient(objects ...runtime.Object) *FakeKubeClient {
+	tracker := clienttesting.NewObjectTracker(scheme.Scheme, scheme.Codecs.UniversalDecoder())
+	for _, obj := range objects {
+		if err := tracker.Add(obj); err != nil {
+			panic(err)
+		}
+	}
+	client := &FakeKubeClient{}
+	client.AddReactor("*", "*", clienttesting.ObjectReaction(tracker))
+	return client
+}
+
+// CoreV1 returns the fake core API client
+func (c *FakeKubeClient) CoreV1() corev1client.CoreV1Interface {
+	return &fakeCoreV1{Fake: &c.Fake, podLogs: c.PodLogs}
+}
+
+// AuthorizationV1 returns the fake authorization API client
+func (c *FakeKubeClient) AuthorizationV1() authorizationv1client.AuthorizationV1Interface {
+	return &fakeAuthorizationV1{Fake: &c.Fake}
+}
+
+type fakeCoreV1 struct {
+	corev1client.CoreV1Interface
+	Fake    *clienttesting.Fake
+	podLogs func(namespace string, pod string, opts *corev1.PodLogOptions) string
+}
+
+func (c *fakeCoreV1) ConfigMaps(namespace string) corev1client.ConfigMapInterface {
+	return &fakeConfigMaps{Fake: c.Fake, ns: namespace}
+}
+
+func (c *fakeCoreV1) Events(namespace string) corev1client.EventInterface {
+	return &fakeEvents{Fake: c.Fake, ns: namespace}
+}
+
+func (c *fakeCoreV1) LimitRanges(namespace string) corev1client.LimitRangeInterface {
+	return &fakeLimitRanges{Fake: c.Fake, ns: namespace}
+}
+
+func (c *fakeCoreV1) Namespaces() corev1client.NamespaceInterface {
+	return &fakeNamespaces{Fake: c.Fake}
+}
+
+func (c *fakeCoreV1) Nodes() corev1client.NodeInterface {
+	return &fakeNodes{Fake: c.Fake}
+}
+
+func (c *fakeCoreV1) Pods(namespace string) corev1client.PodInterface {
+	return &fakePods{Fake: c.Fake, ns: namespace, logs: c.podLogs}
+}
+
+func (c *fakeCoreV1) ResourceQuotas(namespace string) corev1client.ResourceQuotaInterface {
+	return &fakeResourceQuotas{Fake: c.Fake, ns: namespace}
+}
+
+func (c *fakeCoreV1) Secrets(namespace string) corev1client.SecretInterface {
+	return &fakeSecrets{Fake: c.Fake, ns: namespace}
+}
+
+func (c *fakeCoreV1) ServiceAccounts(namespace string) corev1client.ServiceAccountInterface {
+	return &fakeServiceAccounts{Fake: c.Fake, ns: namespace}
+}
+
+type fakeConfigMaps struct {
+	corev1client.ConfigMapInterface
+	Fake *clienttesting.Fake
+	ns   string
+}
+
+func (c *fakeConfigMaps) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.ConfigMap, error) {
+	obj, err := c.Fake.Invokes(clienttesting.NewGetAction(configMapsResource, c.ns, name), &corev1.ConfigMap{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*corev1.ConfigMap), err
+}
+
+func (c *fakeConfigMaps) Create(ctx context.Context, configMap *corev1.ConfigMap, opts metav1.CreateOptions) (*corev1.ConfigMap, error) {
+	obj, err := c.Fake.Invokes(clienttesting.NewCreateAction(configMapsResource, c.ns, configMap), &corev1.ConfigMap{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*corev1.ConfigMap), err
+}
+
+type fakeEvents struct {
+	corev1client.EventInterface
+	Fake *clienttesting.Fake
+	ns   string
+}
+
+func (c *fakeEvents) List(ctx context.Context, opts metav1.ListOptions) (*corev1.EventList, error) {
+	obj, err := c.Fake.Invokes(clienttesting.NewListAction(eventsResource, corev1.SchemeGroupVersion.WithKind("Event"), c.ns, opts), &corev1.EventList{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*corev1.EventList), err
+}
+
+type fakeLimitRanges struct {
+	corev1client.LimitRangeInterface
+	Fake *clienttesting.Fake
+	ns   string
+}
+
+func (c *fakeLimitRanges) List(ctx context.Context, opts metav1.ListOptions) (*corev1.LimitRangeList, error) {
+	obj, err := c.Fake.Invokes(clienttesting.NewListAction(limitRangesResource, corev1.SchemeGroupVersion.WithKind("LimitRange"), c.ns, opts), &corev1.LimitRangeList{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*corev1.LimitRangeList), err
+}
+
+type fakeNamespaces struct {
+	corev1client.NamespaceInterface
+	Fake *clienttesting.Fake
+}
+
+func (c *fakeNamespaces) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.Namespace, error) {
+	obj, err := c.Fake.Invokes(clienttesting.NewRootGetAction(namespacesResource, name), &corev1.Namespace{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*corev1.Namespace), err
+}
+
+func (c *fakeNamespaces) List(ctx context.Context, opts metav1.ListOptions) (*corev1.NamespaceList, error) {
+	obj, err := c.Fake.Invokes(clienttesting.NewRootListAction(namespacesResource, corev1.SchemeGroupVersion.WithKind("Namespace"), opts), &corev1.NamespaceList{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*corev1.NamespaceList), err
+}
+
+func (c *fakeNamespaces) Create(ctx context.Context, namespace *corev1.Namespace, opts metav1.CreateOptions) (*corev1.Namespace, error) {
+	obj, err := c.Fake.Invokes(clienttesting.NewRootCreateAction(namespacesResource, namespace), &corev1.Namespace{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*corev1.Namespace), err
+}
+
+func (c *fakeNamespaces) Update(ctx context.Context, namespace *corev1.Namespace, opts metav1.UpdateOptions) (*corev1.Namespace, error) {
+	obj, err := c.Fake.Invokes(clienttesting.NewRootUpdateAction(namespacesResource, namespace), &corev1.Namespace{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*corev1.Namespace), err
+}
+
+type fakeNodes struct {
+	corev1client.NodeInterface
+	Fake *clienttesting.Fake
+}
+
+func (c *fakeNodes) List(ctx context.Context, opts metav1.ListOptions) (*corev1.NodeList, error) {
+	obj, err := c.Fake.Invokes(clienttesting.NewRootListAction(nodesResource, corev1.SchemeGroupVersion.WithKind("Node"), opts), &corev1.NodeList{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*corev1.NodeList), err
+}
+
+type fakePods struct {
+	corev1client.PodInterface
+	Fake *clienttesting.Fake
+	ns   string
+	logs func(namespace string, pod string, opts *corev1.PodLogOptions) string
+}
+
+func (c *fakePods) List(ctx context.Context, opts metav1.ListOptions) (*corev1.PodList, error) {
+	obj, err := c.Fake.Invokes(clienttesting.NewListAction(podsResource, corev1.SchemeGroupVersion.WithKind("Pod"), c.ns, opts), &corev1.PodList{})
+	if obj == nil {
+		return nil, err
+	}
+	label, _, _ := clienttesting.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &corev1.PodList{ListMeta: obj.(*corev1.PodList).ListMeta}
+	for _, item := range obj.(*corev1.PodList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// GetLogs records a get action for the "log" subresource of the pod, which fails if the pod does not exist
+func (c *fakePods) GetLogs(name string, opts *corev1.PodLogOptions) *rest.Request {
+	_, err := c.Fake.Invokes(clienttesting.NewGetSubresourceAction(podsResource, c.ns, "log", name), &corev1.Pod{})
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if err != nil {
+			return nil, err
+		}
+		logs := ""
+		if c.logs != nil {
+			logs = c.logs(c.ns, name, opts)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader(logs)),
+		}, nil
+	})
+	return rest.NewRequestWithClient(&url.URL{Scheme: "http", Host: "localhost"}, "", rest.ClientContentConfig{}, &http.Client{Transport: transport})
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+type fakeResourceQuotas struct {
+	corev1client.ResourceQuotaInterface
+	Fake *clienttesting.Fake
+	ns   string
+}
+
+func (c *fakeResourceQuotas) List(ctx context.Context, opts metav1.ListOptions) (*corev1.ResourceQuotaList, error) {
+	obj, err := c.Fake.Invokes(clienttesting.NewListAction(resourceQuotasResource, corev1.SchemeGroupVersion.WithKind("ResourceQuota"), c.ns, opts), &corev1.ResourceQuotaList{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*corev1.ResourceQuotaList), err
+}
+
+type fakeSecrets struct {
+	corev1client.SecretInterface
+	Fake *clienttesting.Fake
+	ns   string
+}
+
+func (c *fakeSecrets) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.Secret, error) {
+	obj, err := c.Fake.Invokes(clienttesting.NewGetAction(secretsResource, c.ns, name), &corev1.Secret{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*corev1.Secret), err
+}
+
+func (c *fakeSecrets) Create(ctx context.Context, secret *corev1.Secret, opts metav1.CreateOptions) (*corev1.Secret, error) {
+	obj, err := c.Fake.Invokes(clienttesting.NewCreateAction(secretsResource, c.ns, secret), &corev1.Secret{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*corev1.Secret), err
+}
+
+func (c *fakeSecrets) Update(ctx context.Context, secret *corev1.Secret, opts metav1.UpdateOptions) (*corev1.Secret, error) {
+	obj, err := c.Fake.Invokes(clienttesting.NewUpdateAction(secretsResource, c.ns, secret), &corev1.Secret{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*corev1.Secret), err
+}
+
+type fakeServiceAccounts struct {
+	corev1client.ServiceAccountInterface
+	Fake *clienttesting.Fake
+	ns   string
+}
+
+func (c *fakeServiceAccounts) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.ServiceAccount, error) {
+	obj, err := c.Fake.Invokes(clienttesting.NewGetAction(serviceAccountsResource, c.ns, name), &corev1.ServiceAccount{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*corev1.ServiceAccount), err
+}
+
+func (c *fakeServiceAccounts) Create(ctx context.Context, sa *corev1.ServiceAccount, opts metav1.CreateOptions) (*corev1.ServiceAccount, error) {
+	obj, err := c.Fake.Invokes(clienttesting.NewCreateAction(serviceAccountsResource, c.ns, sa), &corev1.ServiceAccount{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*corev1.ServiceAccount), err
+}
+
+func (c *fakeServiceAccounts) Update(ctx context.Context, sa *corev1.ServiceAccount, opts metav1.UpdateOptions) (*corev1.ServiceAccount, error) {
+	obj, err := c.Fake.Invokes(clienttesting.NewUpdateAction(serviceAccountsResource, c.ns, sa), &corev1.ServiceAccount{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*corev1.ServiceAccount), err
+}
+
+type fakeAuthorizationV1 struct {
+	authorizationv1client.AuthorizationV1Interface
+	Fake *clienttesting.Fake
+}
+
+func (c *fakeAuthorizationV1) SelfSubjectAccessReviews() authorizationv1client.SelfSubjectAccessReviewInterface {
+	return &fakeSelfSubjectAccessReviews{Fake: c.Fake}
+}
+
+type fakeSelfSubjectAccessReviews struct {
+	authorizationv1client.SelfSubjectAccessReviewInterface
+	Fake *clienttesting.Fake
+}
+
+func (c *fakeSelfSubjectAccessReviews) Create(ctx context.Context, review *authorizationv1.SelfSubjectAccessReview, opts metav1.CreateOptions) (*authorizationv1.SelfSubjectAccessReview, error) {
+	obj, err := c.Fake.Invokes(clienttesting.NewRootCreateAction(selfSubjectAccessReviewsResource, review), &authorizationv1.SelfSubjectAccessReview{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*authorizationv1.SelfSubjectAccessReview), err
+}
@@ -0,0 +1,259 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/eventing/pkg/apis/eventing"
+	"knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	"knative.dev/pkg/apis"
+
+	clientv1beta1 "knative.dev/client/pkg/eventing/v1beta1"
+	"knative.dev/client/pkg/kn/commands"
+	clientmessagingv1beta1 "knative.dev/client/pkg/messaging/v1beta1"
+	"knative.dev/client/pkg/printers"
+)
+
+const (
+	// Namespace of the filter and ingress pods of the multi-tenant channel based broker
+	brokerSystemNamespace = "knative-eventing"
+
+	brokerRoleLabelKey = "eventing.knative.dev/brokerRole"
+	brokerRoleIngress  = "ingress"
+	brokerRoleFilter   = "filter"
+
+	// Part of the error message when the CRD of a channel isn't installed
+	noMatchesForKind = "no matches for kind"
+)
+
+// triggerDiagnosis is what is known about a trigger which didn't become ready
+type triggerDiagnosis struct {
+	trigger    *v1beta1.Trigger
+	conditions []resourceConditions
+	events     []corev1.Event
+	// Pods of the broker's filter and ingress which aren't ready
+	pods []corev1.Pod
+	// podsInspected is true if the pods of the broker's filter and ingress have been listed
+	podsInspected bool
+	// Roles of the broker's data plane without any pod
+	missingRoles []string
+}
+
+// resourceConditions are the conditions of a single resource, as shown in the conditions table
+type resourceConditions struct {
+	resource   string
+	conditions []apis.Condition
+}
+
+// printTriggerDiagnosis prints why the trigger didn't become ready: the conditions of the trigger,
+// its broker and its subscription, the warning events of these resources and the broker's filter
+// and ingress pods which aren't ready. It is best effort: what can't be fetched is left out.
+func printTriggerDiagnosis(eventingClient clientv1beta1.KnEventingClient, messagingClient clientmessagingv1beta1.KnMessagingClient,
+	kubeClient kubernetes.Interface, name string, out io.Writer) {
+	diagnosis := diagnoseTrigger(eventingClient, messagingClient, kubeClient, name)
+	if diagnosis == nil {
+		return
+	}
+
+	fmt.Fprintf(out, "\nConditions of trigger '%s' in namespace '%s':\n", name, eventingClient.Namespace())
+	w := printers.NewTabWriter(out)
+	fmt.Fprintln(w, "RESOURCE\tTYPE\tSTATUS\tREASON\tAGE\tMESSAGE")
+	for _, rc := range diagnosis.conditions {
+		for _, cond := range rc.conditions {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", rc.resource, cond.Type, cond.Status, cond.Reason,
+				commands.Age(cond.LastTransitionTime.Inner.Time), cond.Message)
+		}
+	}
+	w.Flush()
+
+	if len(diagnosis.events) > 0 {
+		fmt.Fprintln(out, "\nWarning events:")
+		w = printers.NewTabWriter(out)
+		fmt.Fprintln(w, "RESOURCE\tREASON\tAGE\tMESSAGE")
+		for _, event := range diagnosis.events {
+			fmt.Fprintf(w, "%s/%s\t%s\t%s\t%s\n", strings.ToLower(event.InvolvedObject.Kind), event.InvolvedObject.Name,
+				event.Reason, commands.Age(event.LastTimestamp.Time), event.Message)
+		}
+		w.Flush()
+	}
+
+	if len(diagnosis.pods) > 0 {
+		fmt.Fprintf(out, "\nBroker pods in namespace '%s' which are not ready:\n", brokerSystemNamespace)
+		w = printers.NewTabWriter(out)
+		fmt.Fprintln(w, "POD\tROLE\tSTATUS")
+		for _, pod := range diagnosis.pods {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", pod.Name, pod.Labels[brokerRoleLabelKey], podStatus(&pod))
+		}
+		w.Flush()
+	}
+
+	hints := diagnosis.hints()
+	if len(hints) > 0 {
+		fmt.Fprintln(out, "")
+		for _, hint := range hints {
+			fmt.Fprintln(out, hint)
+		}
+	}
+}
+
+// diagnoseTrigger fetches the trigger and the resources it depends on
+func diagnoseTrigger(eventingClient clientv1beta1.KnEventingClient, messagingClient clientmessagingv1beta1.KnMessagingClient,
+	kubeClient kubernetes.Interface, name string) *triggerDiagnosis {
+	trigger, err := eventingClient.GetTrigger(name)
+	if err != nil {
+		return nil
+	}
+	diagnosis := &triggerDiagnosis{trigger: trigger}
+	diagnosis.conditions = append(diagnosis.conditions, resourceConditions{"trigger/" + name, trigger.Status.Conditions})
+	involved := map[string]bool{"Trigger/" + name: true}
+
+	broker, err := eventingClient.GetBroker(trigger.Spec.Broker)
+	if err == nil {
+		diagnosis.conditions = append(diagnosis.conditions, resourceConditions{"broker/" + broker.Name, broker.Status.Conditions})
+		involved["Broker/"+broker.Name] = true
+	}
+
+	if messagingClient != nil {
+		subscriptions, err := messagingClient.SubscriptionsClient().ListSubscription()
+		if err == nil {
+			for _, subscription := range subscriptions.Items {
+				if metav1.IsControlledBy(&subscription, trigger) {
+					diagnosis.conditions = append(diagnosis.conditions,
+						resourceConditions{"subscription/" + subscription.Name, subscription.Status.Conditions})
+					involved["Subscription/"+subscription.Name] = true
+				}
+			}
+		}
+	}
+
+	if kubeClient == nil {
+		return diagnosis
+	}
+	events, err := kubeClient.CoreV1().Events(eventingClient.Namespace()).List(context.TODO(), metav1.ListOptions{})
+	if err == nil {
+		for _, event := range events.Items {
+			if event.Type == corev1.EventTypeWarning && involved[event.InvolvedObject.Kind+"/"+event.InvolvedObject.Name] {
+				diagnosis.events = append(diagnosis.events, event)
+			}
+		}
+	}
+	// Only the data plane of the multi-tenant channel based broker is known
+	if broker != nil && broker.Annotations[eventing.BrokerClassKey] == eventing.MTChannelBrokerClassValue {
+		diagnosis.inspectBrokerPods(kubeClient)
+	}
+	return diagnosis
+}
+
+// inspectBrokerPods looks for the filter and ingress pods which aren't ready
+func (d *triggerDiagnosis) inspectBrokerPods(kubeClient kubernetes.Interface) {
+	pods, err := kubeClient.CoreV1().Pods(brokerSystemNamespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: brokerRoleLabelKey + " in (" + brokerRoleIngress + "," + brokerRoleFilter + ")",
+	})
+	if err != nil {
+		return
+	}
+	d.podsInspected = true
+	found := map[string]bool{}
+	for _, pod := range pods.Items {
+		found[pod.Labels[brokerRoleLabelKey]] = true
+		if !podReady(&pod) {
+			d.pods = append(d.pods, pod)
+		}
+	}
+	for _, role := range []string{brokerRoleFilter, brokerRoleIngress} {
+		if !found[role] {
+			d.missingRoles = append(d.missingRoles, role)
+		}
+	}
+}
+
+// hints explains the causes of the failure which can be told from the diagnosis
+func (d *triggerDiagnosis) hints() []string {
+	var hints []string
+	if d.mentions(noMatchesForKind) {
+		hints = append(hints, fmt.Sprintf("The CRD of the broker's channel isn't installed. Install the channel implementation, "+
+			"or configure an installed channel in ConfigMap 'config-br-default-channel' of namespace '%s'.", brokerSystemNamespace))
+	}
+	if cond := d.trigger.Status.GetCondition(v1beta1.TriggerConditionBroker); cond != nil && cond.IsFalse() && cond.Reason == "BrokerDoesNotExist" {
+		hints = append(hints, fmt.Sprintf("Broker '%s' doesn't exist, create it with 'kn broker create %s'.",
+			d.trigger.Spec.Broker, d.trigger.Spec.Broker))
+	}
+	if cond := d.trigger.Status.GetCondition(v1beta1.TriggerConditionSubscriberResolved); cond != nil && cond.IsFalse() {
+		hints = append(hints, "The subscriber of the trigger can't be resolved. Check that the sink exists and is addressable.")
+	}
+	if d.podsInspected && len(d.missingRoles) > 0 {
+		hints = append(hints, fmt.Sprintf("No broker %s pods found in namespace '%s'. Check that the multi-tenant channel based broker is installed.",
+			strings.Join(d.missingRoles, " and "), brokerSystemNamespace))
+	}
+	if len(d.pods) > 0 {
+		hints = append(hints, fmt.Sprintf("Events can't be delivered while broker pods aren't ready. "+
+			"Check them with 'kubectl describe pod -n %s %s'.", brokerSystemNamespace, d.pods[0].Name))
+	}
+	return hints
+}
+
+// mentions returns true if a condition or a warning event contains the text
+func (d *triggerDiagnosis) mentions(text string) bool {
+	for _, rc := range d.conditions {
+		for _, cond := range rc.conditions {
+			if strings.Contains(cond.Message, text) {
+				return true
+			}
+		}
+	}
+	for _, event := range d.events {
+		if strings.Contains(event.Message, text) {
+			return true
+		}
+	}
+	return false
+}
+
+// podReady returns true if the pod is running and its Ready condition is True
+func podReady(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// podStatus returns the reason why a container is waiting or terminated, like kubectl,
+// and the phase of the pod otherwise
+func podStatus(pod *corev1.Pod) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting != nil && status.State.Waiting.Reason != "" {
+			return status.State.Waiting.Reason
+		}
+		if status.State.Terminated != nil && status.State.Terminated.Reason != "" {
+			return status.State.Terminated.Reason
+		}
+	}
+	if pod.Status.Reason != "" {
+		return pod.Status.Reason
+	}
+	return string(pod.Status.Phase)
+}
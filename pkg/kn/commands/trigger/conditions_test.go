@@ -0,0 +1,180 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trigger
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/eventing/pkg/apis/eventing"
+	"knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	messagingv1beta1 "knative.dev/eventing/pkg/apis/messaging/v1beta1"
+	"knative.dev/pkg/apis"
+
+	clienteventingv1beta1 "knative.dev/client/pkg/eventing/v1beta1"
+	"knative.dev/client/pkg/kn/commands"
+	clientmessagingv1beta1 "knative.dev/client/pkg/messaging/v1beta1"
+	"knative.dev/client/pkg/util"
+)
+
+type messagingClient struct {
+	subscriptions clientmessagingv1beta1.KnSubscriptionsClient
+}
+
+func (c *messagingClient) ChannelsClient() clientmessagingv1beta1.KnChannelsClient {
+	return nil
+}
+
+func (c *messagingClient) SubscriptionsClient() clientmessagingv1beta1.KnSubscriptionsClient {
+	return c.subscriptions
+}
+
+func TestPrintTriggerDiagnosis(t *testing.T) {
+	trigger := failedTrigger()
+	eventingClient := clienteventingv1beta1.NewMockKnEventingClient(t)
+	eventingRecorder := eventingClient.Recorder()
+	eventingRecorder.GetTrigger(triggerName, trigger, nil)
+	eventingRecorder.GetBroker("mybroker", failedBroker(), nil)
+
+	subscriptionsClient := clientmessagingv1beta1.NewMockKnSubscriptionsClient(t)
+	subscriptionsClient.Recorder().ListSubscription(&messagingv1beta1.SubscriptionList{Items: []messagingv1beta1.Subscription{
+		*triggerSubscription(trigger, "mybroker-foo-123"),
+		{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}},
+	}}, nil)
+
+	kubeClient := commands.NewFakeKubeClient(
+		warningEvent("Broker", "mybroker", "ChannelFailed", `failed to create channel: no matches for kind "InMemoryChannel"`),
+		warningEvent("Service", "mysvc", "Other", "not related"),
+		brokerPod("mt-broker-filter-1", brokerRoleFilter, corev1.ContainerState{
+			Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+		}))
+
+	out := &bytes.Buffer{}
+	printTriggerDiagnosis(eventingClient, &messagingClient{subscriptionsClient}, kubeClient, triggerName, out)
+	output := out.String()
+	assert.Assert(t, util.ContainsAll(output,
+		"Conditions of trigger 'foo' in namespace 'default'",
+		"trigger/foo", "BrokerReady", "broker/mybroker", "TriggerChannelReady", "subscription/mybroker-foo-123",
+		"Warning events", "broker/mybroker", "ChannelFailed",
+		"Broker pods in namespace 'knative-eventing' which are not ready", "mt-broker-filter-1", "filter", "CrashLoopBackOff",
+		"The CRD of the broker's channel isn't installed",
+		"No broker ingress pods found in namespace 'knative-eventing'",
+		"kubectl describe pod -n knative-eventing mt-broker-filter-1"))
+	assert.Assert(t, util.ContainsNone(output, "subscription/other", "not related", "create it with 'kn broker create"))
+
+	eventingRecorder.Validate()
+	subscriptionsClient.Recorder().Validate()
+}
+
+func TestPrintTriggerDiagnosisMissingBroker(t *testing.T) {
+	trigger := createTrigger("default", triggerName, nil, "mybroker", "mysvc")
+	trigger.Status.Conditions = []apis.Condition{{
+		Type:   v1beta1.TriggerConditionBroker,
+		Status: corev1.ConditionFalse,
+		Reason: "BrokerDoesNotExist",
+	}}
+	eventingClient := clienteventingv1beta1.NewMockKnEventingClient(t)
+	eventingRecorder := eventingClient.Recorder()
+	eventingRecorder.GetTrigger(triggerName, trigger, nil)
+	eventingRecorder.GetBroker("mybroker", nil, fmt.Errorf("brokers.eventing.knative.dev \"mybroker\" not found"))
+
+	out := &bytes.Buffer{}
+	printTriggerDiagnosis(eventingClient, nil, nil, triggerName, out)
+	assert.Assert(t, util.ContainsAll(out.String(), "trigger/foo", "BrokerDoesNotExist",
+		"Broker 'mybroker' doesn't exist, create it with 'kn broker create mybroker'."))
+	assert.Assert(t, util.ContainsNone(out.String(), "broker/mybroker", "Broker pods"))
+	eventingRecorder.Validate()
+}
+
+func TestPodStatus(t *testing.T) {
+	pod := brokerPod("p", brokerRoleIngress, corev1.ContainerState{
+		Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"},
+	})
+	assert.Equal(t, podStatus(pod), "OOMKilled")
+	assert.Assert(t, !podReady(pod))
+
+	pod = brokerPod("p", brokerRoleIngress, corev1.ContainerState{})
+	pod.Status.Phase = corev1.PodPending
+	pod.Status.Reason = "Evicted"
+	assert.Equal(t, podStatus(pod), "Evicted")
+
+	pod.Status.Reason = ""
+	assert.Equal(t, podStatus(pod), "Pending")
+
+	pod.Status.Phase = corev1.PodRunning
+	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	assert.Assert(t, podReady(pod))
+}
+
+func failedTrigger() *v1beta1.Trigger {
+	trigger := createTrigger("default", triggerName, nil, "mybroker", "mysvc")
+	trigger.UID = types.UID("trigger-uid")
+	trigger.Status.Conditions = []apis.Condition{{
+		Type:    v1beta1.TriggerConditionBroker,
+		Status:  corev1.ConditionFalse,
+		Reason:  "BrokerNotReady",
+		Message: "Broker is not ready",
+	}}
+	return trigger
+}
+
+func failedBroker() *v1beta1.Broker {
+	broker := clienteventingv1beta1.NewBrokerBuilder("mybroker").Namespace("default").Build()
+	broker.Annotations = map[string]string{eventing.BrokerClassKey: eventing.MTChannelBrokerClassValue}
+	broker.Status.Conditions = []apis.Condition{{
+		Type:    v1beta1.BrokerConditionTriggerChannel,
+		Status:  corev1.ConditionFalse,
+		Reason:  "ChannelFailure",
+		Message: `no matches for kind "InMemoryChannel" in version "messaging.knative.dev/v1"`,
+	}}
+	return broker
+}
+
+func triggerSubscription(trigger *v1beta1.Trigger, name string) *messagingv1beta1.Subscription {
+	subscription := &messagingv1beta1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(trigger, v1beta1.SchemeGroupVersion.WithKind("Trigger"))},
+		},
+	}
+	subscription.Status.Conditions = []apis.Condition{{Type: apis.ConditionReady, Status: corev1.ConditionUnknown}}
+	return subscription
+}
+
+func warningEvent(kind string, name string, reason string, message string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name + "." + reason, Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: kind, Name: name, Namespace: "default"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         reason,
+		Message:        message,
+	}
+}
+
+func brokerPod(name string, role string, state corev1.ContainerState) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: brokerSystemNamespace, Labels: map[string]string{brokerRoleLabelKey: role}},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: role, State: state}},
+		},
+	}
+}
@@ -17,20 +17,32 @@ package trigger
 import (
 	"errors"
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 
+	clientdynamic "knative.dev/client/pkg/dynamic"
 	clientv1beta1 "knative.dev/client/pkg/eventing/v1beta1"
 	"knative.dev/client/pkg/kn/commands"
 	"knative.dev/client/pkg/kn/commands/flags"
 )
 
+// waitForTrigger blocks until the trigger is ready, can be replaced in tests
+var waitForTrigger = func(client clientdynamic.KnDynamicClient, name string, timeout time.Duration, out io.Writer) error {
+	return commands.WaitForResource(client, eventingv1beta1.SchemeGroupVersion.WithResource("triggers"), "trigger", name,
+		apis.ConditionReady, timeout, out)
+}
+
 // NewTriggerCreateCommand to create trigger create command
 func NewTriggerCreateCommand(p *commands.KnParams) *cobra.Command {
 	var triggerUpdateFlags TriggerUpdateFlags
 	var sinkFlags flags.SinkFlags
+	var waitFlags commands.WaitFlags
 
 	cmd := &cobra.Command{
 		Use:   "create NAME --sink SINK",
@@ -40,7 +52,13 @@ func NewTriggerCreateCommand(p *commands.KnParams) *cobra.Command {
   kn trigger create mytrigger --broker default --sink ksvc:mysvc
 
   # Create a trigger to filter events with attribute 'type=dev.knative.foo'
-  kn trigger create mytrigger --broker default --filter type=dev.knative.foo --sink ksvc:mysvc`,
+  kn trigger create mytrigger --broker default --filter type=dev.knative.foo --sink ksvc:mysvc
+
+  # Create a trigger only if its subscriber 'mysvc' exists and is addressable
+  kn trigger create mytrigger --broker default --sink ksvc:mysvc --validate-sink
+
+  # Create a trigger and wait until it's ready, showing the causes if it doesn't become ready
+  kn trigger create mytrigger --broker default --sink ksvc:mysvc --wait`,
 
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			if len(args) != 1 {
@@ -102,14 +120,31 @@ func NewTriggerCreateCommand(p *commands.KnParams) *cobra.Command {
 					"cannot create trigger '%s' in namespace '%s' "+
 						"because: %s", name, namespace, err)
 			}
-			fmt.Fprintf(cmd.OutOrStdout(), "Trigger '%s' successfully created in namespace '%s'.\n", args[0], namespace)
-			return nil
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Trigger '%s' successfully created in namespace '%s'.\n", args[0], namespace)
+			if !waitFlags.Wait {
+				return nil
+			}
+			err = waitForTrigger(dynamicClient, name, time.Duration(waitFlags.TimeoutInSeconds)*time.Second, out)
+			if err != nil {
+				// The diagnosis is best effort, clients which can't be created are skipped
+				messagingClient, _ := p.NewMessagingClient(namespace)
+				kubeClient, _ := p.NewKubeClient()
+				printTriggerDiagnosis(eventingClient, messagingClient, kubeClient, name, out)
+			}
+			return err
 		},
 	}
 	commands.AddNamespaceFlags(cmd.Flags(), false)
 	triggerUpdateFlags.Add(cmd)
 	sinkFlags.Add(cmd)
+	sinkFlags.AddValidateFlag(cmd)
 	cmd.MarkFlagRequired("sink")
+	cmd.Flags().BoolVar(&waitFlags.Wait, "wait", false,
+		"Wait until the trigger is ready. If it doesn't become ready, the conditions and warning events of the trigger, "+
+			"its broker and its subscription are shown, together with the broker pods which aren't ready.")
+	cmd.Flags().IntVar(&waitFlags.TimeoutInSeconds, "wait-timeout", commands.WaitDefaultTimeout,
+		"Seconds to wait before giving up on waiting for the trigger to be ready.")
 
 	return cmd
 }
@@ -15,15 +15,25 @@
 package trigger
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"testing"
+	"time"
 
 	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	messagingv1beta1 "knative.dev/eventing/pkg/apis/messaging/v1beta1"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
+	clientdynamic "knative.dev/client/pkg/dynamic"
 	dynamicfake "knative.dev/client/pkg/dynamic/fake"
 	clienteventingv1beta1 "knative.dev/client/pkg/eventing/v1beta1"
+	"knative.dev/client/pkg/kn/commands"
+	clientmessagingv1beta1 "knative.dev/client/pkg/messaging/v1beta1"
 	"knative.dev/client/pkg/util"
 )
 
@@ -91,6 +101,21 @@ func TestSinkNotFoundError(t *testing.T) {
 	assert.Assert(t, util.ContainsAll(out, errorMsg, "Usage"))
 }
 
+func TestSinkNotAddressableError(t *testing.T) {
+	eventingClient := clienteventingv1beta1.NewMockKnEventingClient(t)
+	dynamicClient := dynamicfake.CreateFakeKnDynamicClient("default", &servingv1.Service{
+		TypeMeta:   metav1.TypeMeta{Kind: "Service", APIVersion: "serving.knative.dev/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "mysvc", Namespace: "default"},
+	})
+
+	_, err := executeTriggerCommand(eventingClient, dynamicClient, "create", triggerName, "--broker", "mybroker",
+		"--sink", "ksvc:mysvc", "--validate-sink")
+	assert.ErrorContains(t, err, "sink ksvc 'mysvc' in namespace 'default' is not addressable")
+
+	// No trigger must have been created
+	eventingClient.Recorder().Validate()
+}
+
 func TestNoSinkError(t *testing.T) {
 	eventingClient := clienteventingv1beta1.NewMockKnEventingClient(t)
 	_, err := executeTriggerCommand(eventingClient, nil, "create", triggerName, "--broker", "mybroker",
@@ -132,3 +157,89 @@ func TestTriggerCreateWithoutFilter(t *testing.T) {
 
 	eventingRecorder.Validate()
 }
+
+func TestTriggerCreateWait(t *testing.T) {
+	eventingClient := clienteventingv1beta1.NewMockKnEventingClient(t)
+	dynamicClient := dynamicfake.CreateFakeKnDynamicClient("default", &servingv1.Service{
+		TypeMeta:   metav1.TypeMeta{Kind: "Service", APIVersion: "serving.knative.dev/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "mysvc", Namespace: "default"},
+	})
+
+	eventingRecorder := eventingClient.Recorder()
+	eventingRecorder.CreateTrigger(createTrigger("default", triggerName, nil, "mybroker", "mysvc"), nil)
+
+	var waitTimeout time.Duration
+	defer replaceWaitForTrigger(func(client clientdynamic.KnDynamicClient, name string, timeout time.Duration, out io.Writer) error {
+		waitTimeout = timeout
+		fmt.Fprintf(out, "Trigger '%s' in namespace 'default' has condition Ready=True.\n", name)
+		return nil
+	})()
+
+	out, err := executeTriggerCommand(eventingClient, dynamicClient, "create", triggerName, "--broker", "mybroker", "--sink", "ksvc:mysvc",
+		"--wait", "--wait-timeout", "30")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(out, "created", "has condition Ready=True"))
+	assert.Equal(t, waitTimeout, 30*time.Second)
+
+	eventingRecorder.Validate()
+}
+
+func TestTriggerCreateWaitFailure(t *testing.T) {
+	trigger := failedTrigger()
+	eventingClient := clienteventingv1beta1.NewMockKnEventingClient(t)
+	dynamicClient := dynamicfake.CreateFakeKnDynamicClient("default", &servingv1.Service{
+		TypeMeta:   metav1.TypeMeta{Kind: "Service", APIVersion: "serving.knative.dev/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "mysvc", Namespace: "default"},
+	})
+
+	eventingRecorder := eventingClient.Recorder()
+	eventingRecorder.CreateTrigger(createTrigger("default", triggerName, nil, "mybroker", "mysvc"), nil)
+	eventingRecorder.GetTrigger(triggerName, trigger, nil)
+	eventingRecorder.GetBroker("mybroker", failedBroker(), nil)
+	subscriptionsClient := clientmessagingv1beta1.NewMockKnSubscriptionsClient(t)
+	subscriptionsClient.Recorder().ListSubscription(&messagingv1beta1.SubscriptionList{}, nil)
+	kubeClient := commands.NewFakeKubeClient(
+		brokerPod("mt-broker-filter-1", brokerRoleFilter, corev1.ContainerState{}),
+		brokerPod("mt-broker-ingress-1", brokerRoleIngress, corev1.ContainerState{}))
+
+	defer replaceWaitForTrigger(func(client clientdynamic.KnDynamicClient, name string, timeout time.Duration, out io.Writer) error {
+		return errors.New("timeout: trigger 'foo' not ready after 600 seconds")
+	})()
+
+	knParams := &commands.KnParams{ClientConfig: blankConfig}
+	knParams.NewDynamicClient = func(namespace string) (clientdynamic.KnDynamicClient, error) {
+		return dynamicClient, nil
+	}
+	knParams.NewEventingClient = func(namespace string) (clienteventingv1beta1.KnEventingClient, error) {
+		return eventingClient, nil
+	}
+	knParams.NewMessagingClient = func(namespace string) (clientmessagingv1beta1.KnMessagingClient, error) {
+		return &messagingClient{subscriptionsClient}, nil
+	}
+	knParams.NewKubeClient = func() (kubernetes.Interface, error) {
+		return kubeClient, nil
+	}
+	output := &bytes.Buffer{}
+	cmd := NewTriggerCommand(knParams)
+	cmd.SetArgs([]string{"create", triggerName, "--broker", "mybroker", "--sink", "ksvc:mysvc", "--wait"})
+	cmd.SetOutput(output)
+
+	err := cmd.Execute()
+	assert.ErrorContains(t, err, "not ready after 600 seconds")
+	assert.Assert(t, util.ContainsAll(output.String(), "created", "Conditions of trigger 'foo'", "broker/mybroker",
+		"TriggerChannelReady", "The CRD of the broker's channel isn't installed",
+		// The pods aren't ready as they have no Ready condition
+		"mt-broker-filter-1", "mt-broker-ingress-1"))
+	assert.Assert(t, util.ContainsNone(output.String(), "No broker"))
+
+	eventingRecorder.Validate()
+	subscriptionsClient.Recorder().Validate()
+}
+
+func replaceWaitForTrigger(wait func(client clientdynamic.KnDynamicClient, name string, timeout time.Duration, out io.Writer) error) func() {
+	old := waitForTrigger
+	waitForTrigger = wait
+	return func() {
+		waitForTrigger = old
+	}
+}
@@ -16,6 +16,7 @@ package trigger
 
 import (
 	"errors"
+	"sort"
 
 	"github.com/spf13/cobra"
 
@@ -100,8 +101,13 @@ func writeTrigger(dw printers.PrefixWriter, trigger *v1beta1.Trigger, printDetai
 	dw.WriteAttribute("Broker", trigger.Spec.Broker)
 	if trigger.Spec.Filter != nil && trigger.Spec.Filter.Attributes != nil {
 		subWriter := dw.WriteAttribute("Filter", "")
-		for key, value := range trigger.Spec.Filter.Attributes {
-			subWriter.WriteAttribute(key, value)
+		keys := make([]string, 0, len(trigger.Spec.Filter.Attributes))
+		for key := range trigger.Spec.Filter.Attributes {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			subWriter.WriteAttribute(key, trigger.Spec.Filter.Attributes[key])
 		}
 	}
 }
@@ -0,0 +1,318 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trigger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	v1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"knative.dev/client/pkg/dynamic"
+	knerrors "knative.dev/client/pkg/errors"
+	"knative.dev/client/pkg/kn/commands"
+	sourcesv1alpha2 "knative.dev/client/pkg/sources/v1alpha2"
+)
+
+// Output formats of 'kn trigger list' for the event topology
+const (
+	graphFormatDOT  = "graph"
+	graphFormatJSON = "graph-json"
+)
+
+// topology is the graph of brokers, triggers, sources and their sinks
+type topology struct {
+	Nodes []topologyNode `json:"nodes"`
+	Edges []topologyEdge `json:"edges"`
+
+	nodeIDs map[string]bool
+}
+
+// topologyNode is a resource or a URI in the event topology
+type topologyNode struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// topologyEdge is the flow of events from one node to another, labeled with
+// the filter of a trigger
+type topologyEdge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Label string `json:"label,omitempty"`
+}
+
+func newTopology() *topology {
+	return &topology{Nodes: []topologyNode{}, Edges: []topologyEdge{}, nodeIDs: map[string]bool{}}
+}
+
+// addNode adds a node for a resource if it's not known yet and returns its ID
+func (t *topology) addNode(kind string, namespace string, name string) string {
+	id := fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+	if !t.nodeIDs[id] {
+		t.nodeIDs[id] = true
+		t.Nodes = append(t.Nodes, topologyNode{ID: id, Kind: kind, Namespace: namespace, Name: name})
+	}
+	return id
+}
+
+// addDestination adds a node for the destination of events and returns its ID
+func (t *topology) addDestination(dest duckv1.Destination, namespace string) string {
+	if dest.Ref != nil {
+		if dest.Ref.Namespace != "" {
+			namespace = dest.Ref.Namespace
+		}
+		return t.addNode(dest.Ref.Kind, namespace, dest.Ref.Name)
+	}
+	if dest.URI != nil {
+		uri := dest.URI.String()
+		id := "URI/" + uri
+		if !t.nodeIDs[id] {
+			t.nodeIDs[id] = true
+			t.Nodes = append(t.Nodes, topologyNode{ID: id, Kind: "URI", Name: uri})
+		}
+		return id
+	}
+	return ""
+}
+
+func (t *topology) addEdge(from string, to string, label string) {
+	if from == "" || to == "" {
+		return
+	}
+	t.Edges = append(t.Edges, topologyEdge{From: from, To: to, Label: label})
+}
+
+// sort orders nodes and edges, so that the output is stable
+func (t *topology) sort() {
+	sort.Slice(t.Nodes, func(i, j int) bool {
+		return t.Nodes[i].ID < t.Nodes[j].ID
+	})
+	sort.SliceStable(t.Edges, func(i, j int) bool {
+		if t.Edges[i].From != t.Edges[j].From {
+			return t.Edges[i].From < t.Edges[j].From
+		}
+		return t.Edges[i].To < t.Edges[j].To
+	})
+}
+
+// collectTopology collects the brokers, triggers and sources of a namespace, or of all namespaces
+// if namespace is empty. When the user can't list them cluster-wide, every namespace is read on its
+// own and namespaces which can't be read are skipped.
+func collectTopology(p *commands.KnParams, namespace string, errOut io.Writer) (*topology, error) {
+	topo := newTopology()
+	err := collectNamespaceTopology(p, namespace, topo)
+	if namespace != "" || !knerrors.IsForbiddenError(err) {
+		return topo, err
+	}
+
+	kubeClient, err := p.NewKubeClient()
+	if err != nil {
+		return nil, err
+	}
+	namespaces, err := kubeClient.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, knerrors.GetError(err)
+	}
+	var skipped []string
+	for _, ns := range namespaces.Items {
+		// Collect into a separate topology first, so that a namespace is either complete or left out
+		nsTopo := newTopology()
+		err = collectNamespaceTopology(p, ns.Name, nsTopo)
+		if knerrors.IsForbiddenError(err) {
+			skipped = append(skipped, ns.Name)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		topo.merge(nsTopo)
+	}
+	if len(skipped) > 0 {
+		fmt.Fprintf(errOut, "Skipped %d namespace(s) without read access: %s\n", len(skipped), strings.Join(skipped, ", "))
+	}
+	return topo, nil
+}
+
+// merge adds the nodes and edges of another topology
+func (t *topology) merge(other *topology) {
+	for _, node := range other.Nodes {
+		if !t.nodeIDs[node.ID] {
+			t.nodeIDs[node.ID] = true
+			t.Nodes = append(t.Nodes, node)
+		}
+	}
+	t.Edges = append(t.Edges, other.Edges...)
+}
+
+// collectNamespaceTopology adds brokers, triggers and sources of the namespace to the topology
+func collectNamespaceTopology(p *commands.KnParams, namespace string, topo *topology) error {
+	eventingClient, err := p.NewEventingClient(namespace)
+	if err != nil {
+		return err
+	}
+	brokerList, err := eventingClient.ListBrokers()
+	if err != nil {
+		return err
+	}
+	for _, broker := range brokerList.Items {
+		topo.addNode("Broker", broker.Namespace, broker.Name)
+	}
+	triggerList, err := eventingClient.ListTriggers()
+	if err != nil {
+		return err
+	}
+	for _, trigger := range triggerList.Items {
+		brokerID := topo.addNode("Broker", trigger.Namespace, trigger.Spec.Broker)
+		triggerID := topo.addNode("Trigger", trigger.Namespace, trigger.Name)
+		topo.addEdge(brokerID, triggerID, filterLabel(trigger.Spec.Filter))
+		topo.addEdge(triggerID, topo.addDestination(trigger.Spec.Subscriber, trigger.Namespace), "")
+	}
+
+	dynamicClient, err := p.NewDynamicClient(namespace)
+	if err != nil {
+		return err
+	}
+	sourceList, err := listSources(dynamicClient)
+	if err != nil {
+		return err
+	}
+	for _, item := range sourceList {
+		source := &duckv1.Source{}
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, source)
+		if err != nil {
+			return err
+		}
+		sourceID := topo.addNode(item.GetKind(), item.GetNamespace(), item.GetName())
+		topo.addEdge(sourceID, topo.addDestination(source.Spec.Sink, item.GetNamespace()), "")
+	}
+	return nil
+}
+
+// listSources lists the sources of all installed source types. If the source types can't be
+// looked up, the built-in sources are listed instead.
+func listSources(dynamicClient dynamic.KnDynamicClient) ([]unstructured.Unstructured, error) {
+	sourceTypes, err := dynamicClient.ListSourcesTypes()
+	var sourceList *unstructured.UnstructuredList
+	switch {
+	case knerrors.IsForbiddenError(err):
+		gvks := sourcesv1alpha2.BuiltInSourcesGVKs()
+		sourceList, err = dynamicClient.ListSourcesUsingGVKs(&gvks)
+	case err != nil:
+		return nil, err
+	case sourceTypes == nil || len(sourceTypes.Items) == 0:
+		// No sources installed
+		return nil, nil
+	default:
+		sourceList, err = dynamicClient.ListSources()
+	}
+	if err != nil || sourceList == nil {
+		return nil, err
+	}
+	return sourceList.Items, nil
+}
+
+// filterLabel returns the attributes filter of a trigger, sorted by attribute name
+func filterLabel(filter *v1beta1.TriggerFilter) string {
+	if filter == nil || len(filter.Attributes) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(filter.Attributes))
+	for key := range filter.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	attrs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		attrs = append(attrs, key+"="+filter.Attributes[key])
+	}
+	return strings.Join(attrs, ",")
+}
+
+// printTopology prints the topology in the given graph format
+func printTopology(topo *topology, format string, out io.Writer) error {
+	topo.sort()
+	if format == graphFormatJSON {
+		data, err := json.MarshalIndent(topo, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(data))
+		return err
+	}
+	printDOT(topo, out)
+	return nil
+}
+
+// printDOT prints the topology as Graphviz digraph with one cluster per namespace
+func printDOT(topo *topology, out io.Writer) {
+	fmt.Fprintln(out, "digraph events {")
+	fmt.Fprintln(out, "  rankdir=LR;")
+
+	byNamespace := map[string][]topologyNode{}
+	var namespaces []string
+	for _, node := range topo.Nodes {
+		if _, ok := byNamespace[node.Namespace]; !ok {
+			namespaces = append(namespaces, node.Namespace)
+		}
+		byNamespace[node.Namespace] = append(byNamespace[node.Namespace], node)
+	}
+	sort.Strings(namespaces)
+	for _, ns := range namespaces {
+		indent := "  "
+		if ns != "" {
+			fmt.Fprintf(out, "  subgraph %q {\n", "cluster_"+ns)
+			fmt.Fprintf(out, "    label=%q;\n", ns)
+			indent = "    "
+		}
+		for _, node := range byNamespace[ns] {
+			fmt.Fprintf(out, "%s%q [label=%q, shape=%s];\n", indent, node.ID, node.Kind+"\n"+node.Name, nodeShape(node.Kind))
+		}
+		if ns != "" {
+			fmt.Fprintln(out, "  }")
+		}
+	}
+	for _, edge := range topo.Edges {
+		if edge.Label != "" {
+			fmt.Fprintf(out, "  %q -> %q [label=%q];\n", edge.From, edge.To, edge.Label)
+		} else {
+			fmt.Fprintf(out, "  %q -> %q;\n", edge.From, edge.To)
+		}
+	}
+	fmt.Fprintln(out, "}")
+}
+
+func nodeShape(kind string) string {
+	switch kind {
+	case "Broker":
+		return "box3d"
+	case "Trigger":
+		return "diamond"
+	case "URI":
+		return "note"
+	default:
+		return "box"
+	}
+}
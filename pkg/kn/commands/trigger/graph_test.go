@@ -0,0 +1,175 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trigger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/eventing/pkg/apis/eventing/v1beta1"
+
+	clientdynamic "knative.dev/client/pkg/dynamic"
+	dynamicfake "knative.dev/client/pkg/dynamic/fake"
+	clienteventingv1beta1 "knative.dev/client/pkg/eventing/v1beta1"
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/util"
+)
+
+func TestTriggerListGraph(t *testing.T) {
+	eventingClient := clienteventingv1beta1.NewMockKnEventingClient(t)
+	eventingRecorder := eventingClient.Recorder()
+	eventingRecorder.ListBrokers(&v1beta1.BrokerList{Items: []v1beta1.Broker{
+		*clienteventingv1beta1.NewBrokerBuilder("default").Namespace("default").Build(),
+	}}, nil)
+	trigger := createTrigger("default", "trigger1", map[string]string{"type": "dev.knative.foo", "source": "ping"}, "default", "mysink")
+	eventingRecorder.ListTriggers(&v1beta1.TriggerList{Items: []v1beta1.Trigger{*trigger}}, nil)
+
+	dynamicClient := dynamicfake.CreateFakeKnDynamicClient("default",
+		newSourceCRD("pingsources", "PingSource"),
+		newSource("default", "ping1", "PingSource", "Broker", "default"))
+
+	output, err := executeTriggerCommand(eventingClient, dynamicClient, "list", "-o", "graph")
+	assert.NilError(t, err)
+	assert.Assert(t, strings.HasPrefix(output, "digraph events {\n"))
+	assert.Assert(t, util.ContainsAll(output,
+		`subgraph "cluster_default" {`,
+		`"Broker/default/default" -> "Trigger/default/trigger1" [label="source=ping,type=dev.knative.foo"];`,
+		`"Trigger/default/trigger1" -> "Service/default/mysink";`,
+		`"PingSource/default/ping1" -> "Broker/default/default";`))
+
+	eventingRecorder.Validate()
+}
+
+func TestTriggerListGraphJSON(t *testing.T) {
+	eventingClient := clienteventingv1beta1.NewMockKnEventingClient(t)
+	eventingRecorder := eventingClient.Recorder()
+	eventingRecorder.ListBrokers(&v1beta1.BrokerList{}, nil)
+	trigger := createTrigger("default", "trigger1", nil, "mybroker", "mysink")
+	eventingRecorder.ListTriggers(&v1beta1.TriggerList{Items: []v1beta1.Trigger{*trigger}}, nil)
+
+	output, err := executeTriggerCommand(eventingClient, dynamicfake.CreateFakeKnDynamicClient("default"), "list", "-o", "graph-json")
+	assert.NilError(t, err)
+
+	topo := topology{}
+	assert.NilError(t, json.Unmarshal([]byte(output), &topo))
+	assert.DeepEqual(t, topo.Nodes, []topologyNode{
+		{ID: "Broker/default/mybroker", Kind: "Broker", Namespace: "default", Name: "mybroker"},
+		{ID: "Service/default/mysink", Kind: "Service", Namespace: "default", Name: "mysink"},
+		{ID: "Trigger/default/trigger1", Kind: "Trigger", Namespace: "default", Name: "trigger1"},
+	})
+	assert.DeepEqual(t, topo.Edges, []topologyEdge{
+		{From: "Broker/default/mybroker", To: "Trigger/default/trigger1"},
+		{From: "Trigger/default/trigger1", To: "Service/default/mysink"},
+	})
+
+	eventingRecorder.Validate()
+}
+
+func TestTriggerListGraphAllNamespacesForbidden(t *testing.T) {
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Group: "eventing.knative.dev", Resource: "brokers"}, "", errors.New("no access"))
+
+	clusterClient := clienteventingv1beta1.NewMockKnEventingClient(t)
+	clusterClient.Recorder().ListBrokers(nil, forbidden)
+	ns1Client := clienteventingv1beta1.NewMockKnEventingClient(t, "ns1")
+	ns1Client.Recorder().ListBrokers(&v1beta1.BrokerList{}, nil)
+	ns1Client.Recorder().ListTriggers(&v1beta1.TriggerList{Items: []v1beta1.Trigger{
+		*createTrigger("ns1", "trigger1", nil, "default", "mysink"),
+	}}, nil)
+	ns2Client := clienteventingv1beta1.NewMockKnEventingClient(t, "ns2")
+	ns2Client.Recorder().ListBrokers(nil, forbidden)
+	eventingClients := map[string]clienteventingv1beta1.KnEventingClient{"": clusterClient, "ns1": ns1Client, "ns2": ns2Client}
+
+	knParams := &commands.KnParams{}
+	output := new(bytes.Buffer)
+	knParams.Output = output
+	knParams.NewKubeClient = func() (kubernetes.Interface, error) {
+		return commands.NewFakeKubeClient(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns2"}}), nil
+	}
+	knParams.NewEventingClient = func(namespace string) (clienteventingv1beta1.KnEventingClient, error) {
+		return eventingClients[namespace], nil
+	}
+	knParams.NewDynamicClient = func(namespace string) (clientdynamic.KnDynamicClient, error) {
+		return dynamicfake.CreateFakeKnDynamicClient(namespace), nil
+	}
+	cmd := NewTriggerCommand(knParams)
+	cmd.SetArgs([]string{"list", "--all-namespaces", "-o", "graph"})
+	cmd.SetOutput(output)
+	assert.NilError(t, cmd.Execute())
+
+	assert.Assert(t, util.ContainsAll(output.String(),
+		"Skipped 1 namespace(s) without read access: ns2",
+		`"Broker/ns1/default" -> "Trigger/ns1/trigger1";`,
+		`"Trigger/ns1/trigger1" -> "Service/default/mysink";`))
+	assert.Assert(t, util.ContainsNone(output.String(), "Trigger/ns2"))
+
+	clusterClient.Recorder().Validate()
+	ns1Client.Recorder().Validate()
+	ns2Client.Recorder().Validate()
+}
+
+func newSourceCRD(name string, kind string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apiextensions.k8s.io/v1beta1",
+			"kind":       "CustomResourceDefinition",
+			"metadata": map[string]interface{}{
+				"name": name + ".sources.knative.dev",
+			},
+			"spec": map[string]interface{}{
+				"group":   "sources.knative.dev",
+				"version": "v1alpha2",
+				"names": map[string]interface{}{
+					"kind":   kind,
+					"plural": name,
+				},
+			},
+		},
+	}
+	obj.SetLabels(map[string]string{"duck.knative.dev/source": "true"})
+	return obj
+}
+
+func newSource(namespace string, name string, kind string, sinkKind string, sinkName string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "sources.knative.dev/v1alpha2",
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"namespace": namespace,
+				"name":      name,
+			},
+			"spec": map[string]interface{}{
+				"sink": map[string]interface{}{
+					"ref": map[string]interface{}{
+						"kind": sinkKind,
+						"name": sinkName,
+					},
+				},
+			},
+		},
+	}
+}
@@ -16,6 +16,7 @@ package trigger
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -36,12 +37,24 @@ func NewTriggerListCommand(p *commands.KnParams) *cobra.Command {
   kn trigger list
 
   # List all triggers in JSON output format
-  kn trigger list -o json`,
+  kn trigger list -o json
+
+  # Print the event topology of all namespaces as Graphviz graph
+  kn trigger list -A -o graph | dot -Tsvg > events.svg`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			namespace, err := p.GetNamespace(cmd)
 			if err != nil {
 				return err
 			}
+
+			if format := *triggerListFlags.GenericPrintFlags.OutputFormat; format == graphFormatDOT || format == graphFormatJSON {
+				topo, err := collectTopology(p, namespace, cmd.ErrOrStderr())
+				if err != nil {
+					return err
+				}
+				return printTopology(topo, format, cmd.OutOrStdout())
+			}
+
 			client, err := p.NewEventingClient(namespace)
 			if err != nil {
 				return err
@@ -69,5 +82,8 @@ func NewTriggerListCommand(p *commands.KnParams) *cobra.Command {
 	}
 	commands.AddNamespaceFlags(triggerListCommand.Flags(), true)
 	triggerListFlags.AddFlags(triggerListCommand)
+	outputFlag := triggerListCommand.Flags().Lookup("output")
+	outputFlag.Usage = strings.TrimSuffix(outputFlag.Usage, ".") +
+		fmt.Sprintf(", or %s (Graphviz DOT) and %s for the topology of brokers, triggers, sources and their sinks.", graphFormatDOT, graphFormatJSON)
 	return triggerListCommand
 }
@@ -20,6 +20,7 @@ import (
 
 	"github.com/spf13/cobra"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
 
 	v1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
@@ -108,6 +109,7 @@ func NewTriggerUpdateCommand(p *commands.KnParams) *cobra.Command {
 				if err != nil {
 					if apierrors.IsConflict(err) && retries < MaxUpdateRetries {
 						retries++
+						klog.V(util.LogLevelRetry).InfoS("Retrying update after conflict", "trigger", name, "retry", retries, "maxRetries", MaxUpdateRetries)
 						continue
 					}
 					return err
@@ -17,12 +17,16 @@ package commands
 import (
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 
+	homedir "github.com/mitchellh/go-homedir"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
 	eventingv1beta1 "knative.dev/eventing/pkg/client/clientset/versioned/typed/eventing/v1beta1"
 	messagingv1beta1 "knative.dev/eventing/pkg/client/clientset/versioned/typed/messaging/v1beta1"
 	sourcesv1alpha2client "knative.dev/eventing/pkg/client/clientset/versioned/typed/sources/v1alpha2"
@@ -34,6 +38,7 @@ import (
 	clientdynamic "knative.dev/client/pkg/dynamic"
 	knerrors "knative.dev/client/pkg/errors"
 	clienteventingv1beta1 "knative.dev/client/pkg/eventing/v1beta1"
+	"knative.dev/client/pkg/kn/config"
 	clientmessagingv1beta1 "knative.dev/client/pkg/messaging/v1beta1"
 	clientservingv1 "knative.dev/client/pkg/serving/v1"
 )
@@ -48,12 +53,55 @@ type KnParams struct {
 	NewEventingClient  func(namespace string) (clienteventingv1beta1.KnEventingClient, error)
 	NewMessagingClient func(namespace string) (clientmessagingv1beta1.KnMessagingClient, error)
 	NewDynamicClient   func(namespace string) (clientdynamic.KnDynamicClient, error)
+	NewKubeClient      func() (kubernetes.Interface, error)
 
 	// General global options
 	LogHTTP bool
 
+	// Never prompt or read from stdin, set with --non-interactive
+	NonInteractive bool
+
+	// Verbosity of the logs about kn internals, set with --v
+	LogVerbosity int
+
+	// Fail instead of warning when flags require newer Knative versions than the cluster's,
+	// set with --strict-compat
+	StrictCompat bool
+
+	// Fail instead of falling back to the "default" namespace, set with --namespace-required
+	NamespaceRequired bool
+
+	// Number of retries of updates which conflict with concurrent changes, set with --retries.
+	// Use RetryPolicy() to take the configuration into account.
+	Retries int
+
+	// Update services with server-side apply, set with --server-side-apply
+	ServerSideApply bool
+	// Take over fields of other managers when applying server-side, set with --force-conflicts
+	ForceConflicts bool
+
+	// Fail after the operation when the API server returned warnings, set with --fail-on-warning
+	FailOnWarning bool
+
+	// Send the changes of services with server-side dry-run, set with --dry-run=server
+	// of the commands changing services
+	DryRunServer bool
+
 	// Set this if you want to nail down the namespace
 	fixedCurrentNamespace string
+
+	// Context of the kubeconfig if it's not the current one, see ForContext()
+	kubeContext string
+
+	// Versions of the Knative components in the cluster, looked up on demand
+	serverVersions map[string]serverVersionLookup
+
+	// Names of the resources changed by the command, see RecordChanged()
+	changedNames    []string
+	changedRecorded bool
+
+	// Warnings returned by the API server, see ReportWarnings()
+	warnings *util.WarningRecorder
 }
 
 func (params *KnParams) Initialize() {
@@ -76,6 +124,10 @@ func (params *KnParams) Initialize() {
 	if params.NewDynamicClient == nil {
 		params.NewDynamicClient = params.newDynamicClient
 	}
+
+	if params.NewKubeClient == nil {
+		params.NewKubeClient = params.newKubeClient
+	}
 }
 
 func (params *KnParams) newServingClient(namespace string) (clientservingv1.KnServingClient, error) {
@@ -85,7 +137,18 @@ func (params *KnParams) newServingClient(namespace string) (clientservingv1.KnSe
 	}
 
 	client, _ := servingv1client.NewForConfig(restConfig)
-	return clientservingv1.NewKnServingClient(client, namespace), nil
+	logClientCreated("serving.knative.dev/v1", namespace, restConfig)
+	var options []clientservingv1.ClientOption
+	if params.ServerSideApply || config.GlobalConfig.ServerSideApply() {
+		options = append(options, clientservingv1.WithServerSideApply())
+		if params.ForceConflicts {
+			options = append(options, clientservingv1.WithForceConflicts())
+		}
+	}
+	if params.DryRunServer {
+		options = append(options, clientservingv1.WithDryRun())
+	}
+	return clientservingv1.NewKnServingClient(client, namespace, options...), nil
 }
 
 func (params *KnParams) newSourcesClient(namespace string) (v1alpha2.KnSourcesClient, error) {
@@ -95,6 +158,7 @@ func (params *KnParams) newSourcesClient(namespace string) (v1alpha2.KnSourcesCl
 	}
 
 	client, _ := sourcesv1alpha2client.NewForConfig(restConfig)
+	logClientCreated("sources.knative.dev/v1alpha2", namespace, restConfig)
 	return v1alpha2.NewKnSourcesClient(client, namespace), nil
 }
 
@@ -105,6 +169,7 @@ func (params *KnParams) newEventingClient(namespace string) (clienteventingv1bet
 	}
 
 	client, _ := eventingv1beta1.NewForConfig(restConfig)
+	logClientCreated("eventing.knative.dev/v1beta1", namespace, restConfig)
 	return clienteventingv1beta1.NewKnEventingClient(client, namespace), nil
 }
 
@@ -115,6 +180,7 @@ func (params *KnParams) newMessagingClient(namespace string) (clientmessagingv1b
 	}
 
 	client, _ := messagingv1beta1.NewForConfig(restConfig)
+	logClientCreated("messaging.knative.dev/v1beta1", namespace, restConfig)
 	return clientmessagingv1beta1.NewKnMessagingClient(client, namespace), nil
 }
 
@@ -125,9 +191,25 @@ func (params *KnParams) newDynamicClient(namespace string) (clientdynamic.KnDyna
 	}
 
 	client, _ := dynamic.NewForConfig(restConfig)
+	logClientCreated("dynamic", namespace, restConfig)
 	return clientdynamic.NewKnDynamicClient(client, namespace), nil
 }
 
+func (params *KnParams) newKubeClient() (kubernetes.Interface, error) {
+	restConfig, err := params.RestConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	logClientCreated("kubernetes", "", restConfig)
+	return kubernetes.NewForConfig(restConfig)
+}
+
+// logClientCreated logs the API and the cluster a client has been created for
+func logClientCreated(api string, namespace string, restConfig *rest.Config) {
+	klog.V(util.LogLevelClient).InfoS("Created client", "api", api, "namespace", namespace, "host", restConfig.Host)
+}
+
 // RestConfig returns REST config, which can be to use to create specific clientset
 func (params *KnParams) RestConfig() (*rest.Config, error) {
 	var err error
@@ -143,10 +225,14 @@ func (params *KnParams) RestConfig() (*rest.Config, error) {
 	if err != nil {
 		return nil, knerrors.GetError(err)
 	}
+	// TODO: When we update to the newer version of client-go, replace with
+	// config.Wrap() for future compat, and record warnings with a rest.WarningHandler.
+	recordWarnings := params.warningRecorder().WrapTransport
+	config.WrapTransport = recordWarnings
 	if params.LogHTTP {
-		// TODO: When we update to the newer version of client-go, replace with
-		// config.Wrap() for future compat.
-		config.WrapTransport = util.NewLoggingTransport
+		config.WrapTransport = func(transport http.RoundTripper) http.RoundTripper {
+			return util.NewLoggingTransport(recordWarnings(transport))
+		}
 	}
 
 	return config, nil
@@ -154,15 +240,60 @@ func (params *KnParams) RestConfig() (*rest.Config, error) {
 
 // GetClientConfig gets ClientConfig from KubeCfgPath
 func (params *KnParams) GetClientConfig() (clientcmd.ClientConfig, error) {
+	return params.getClientConfig(&clientcmd.ConfigOverrides{})
+}
+
+// ForContext returns parameters for accessing the cluster of the given context of the
+// kubeconfig, with the default client factories and the other settings copied over
+func (params *KnParams) ForContext(context string) (*KnParams, error) {
+	clientConfig, err := params.getClientConfig(&clientcmd.ConfigOverrides{CurrentContext: context})
+	if err != nil {
+		return nil, err
+	}
+	rawConfig, err := clientConfig.RawConfig()
+	if err != nil {
+		return nil, knerrors.GetError(err)
+	}
+	if _, ok := rawConfig.Contexts[context]; !ok {
+		return nil, fmt.Errorf("context '%s' not found in the kubeconfig", context)
+	}
+	contextParams := &KnParams{
+		Output:         params.Output,
+		KubeCfgPath:    params.KubeCfgPath,
+		ClientConfig:   clientConfig,
+		LogHTTP:        params.LogHTTP,
+		NonInteractive: params.NonInteractive,
+		LogVerbosity:   params.LogVerbosity,
+		StrictCompat:   params.StrictCompat,
+
+		NamespaceRequired: params.NamespaceRequired,
+		Retries:           params.Retries,
+		ServerSideApply:   params.ServerSideApply,
+		ForceConflicts:    params.ForceConflicts,
+		FailOnWarning:     params.FailOnWarning,
+		DryRunServer:      params.DryRunServer,
+		kubeContext:       context,
+		warnings:          params.warningRecorder(),
+	}
+	contextParams.Initialize()
+	return contextParams, nil
+}
+
+func (params *KnParams) getClientConfig(overrides *clientcmd.ConfigOverrides) (clientcmd.ClientConfig, error) {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 	if len(params.KubeCfgPath) == 0 {
-		return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}), nil
+		return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides), nil
 	}
 
-	_, err := os.Stat(params.KubeCfgPath)
+	// Expand a leading "~" which is not done by the shell on Windows or when the path is quoted
+	kubeCfgPath, err := homedir.Expand(params.KubeCfgPath)
+	if err != nil {
+		return nil, err
+	}
+	_, err = os.Stat(kubeCfgPath)
 	if err == nil {
-		loadingRules.ExplicitPath = params.KubeCfgPath
-		return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}), nil
+		loadingRules.ExplicitPath = kubeCfgPath
+		return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides), nil
 	}
 
 	if !os.IsNotExist(err) {
@@ -16,10 +16,13 @@ package commands
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	homedir "github.com/mitchellh/go-homedir"
 	"gotest.tools/assert"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
@@ -142,6 +145,55 @@ func TestGetClientConfig(t *testing.T) {
 	}
 }
 
+func TestGetClientConfigExpandsHome(t *testing.T) {
+	tmpHome, err := ioutil.TempDir("", "kn-home")
+	assert.NilError(t, err)
+	defer os.RemoveAll(tmpHome)
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", tmpHome)
+	oldDisableCache := homedir.DisableCache
+	defer func() { homedir.DisableCache = oldDisableCache }()
+	homedir.DisableCache = true
+
+	kubeDir := filepath.Join(tmpHome, ".kube")
+	assert.NilError(t, os.Mkdir(kubeDir, 0700))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(kubeDir, "config"), []byte(BASIC_KUBECONFIG), 0600))
+
+	p := &KnParams{KubeCfgPath: "~/.kube/config"}
+	clientConfig, err := p.GetClientConfig()
+	assert.NilError(t, err)
+	assert.Equal(t, clientConfig.ConfigAccess().GetExplicitFile(), filepath.Join(kubeDir, "config"))
+}
+
+func TestForContext(t *testing.T) {
+	kubeConfig := BASIC_KUBECONFIG + `- name: b
+  context:
+    cluster: a
+    user: a
+    namespace: edge
+`
+	kubeConfig = strings.Replace(kubeConfig, "current-context: a\n", "", 1) + "current-context: a\n"
+	file, err := ioutil.TempFile("", "kubeconfig")
+	assert.NilError(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString(kubeConfig)
+	assert.NilError(t, err)
+	file.Close()
+
+	p := &KnParams{KubeCfgPath: file.Name(), LogHTTP: true}
+	contextParams, err := p.ForContext("b")
+	assert.NilError(t, err)
+	namespace, err := contextParams.CurrentNamespace()
+	assert.NilError(t, err)
+	assert.Equal(t, namespace, "edge")
+	assert.Assert(t, contextParams.LogHTTP)
+	assert.Assert(t, contextParams.NewServingClient != nil)
+
+	_, err = p.ForContext("c")
+	assert.ErrorContains(t, err, "context 'c' not found")
+}
+
 func TestNewSourcesClient(t *testing.T) {
 	basic, err := clientcmd.NewClientConfigFromBytes([]byte(BASIC_KUBECONFIG))
 	namespace := "test"
@@ -292,3 +344,49 @@ func TestNewMessagingClient(t *testing.T) {
 		}
 	}
 }
+
+func TestNewKubeClient(t *testing.T) {
+	basic, err := clientcmd.NewClientConfigFromBytes([]byte(BASIC_KUBECONFIG))
+	if err != nil {
+		t.Error(err)
+	}
+	for i, tc := range []configTestCase{
+		{
+			clientcmd.NewDefaultClientConfig(clientcmdapi.Config{}, &clientcmd.ConfigOverrides{}),
+			"no kubeconfig has been provided, please use a valid configuration to connect to the cluster",
+			false,
+		},
+		{
+			basic,
+			"",
+			false,
+		},
+		{ // Test that the cast to wrap the http client in a logger works
+			basic,
+			"",
+			true,
+		},
+	} {
+		p := &KnParams{
+			ClientConfig: tc.clientConfig,
+			LogHTTP:      tc.logHttp,
+		}
+
+		kubeClient, err := p.newKubeClient()
+
+		switch len(tc.expectedErrString) {
+		case 0:
+			if err != nil {
+				t.Errorf("%d: unexpected error: %s", i, err.Error())
+			}
+			assert.Assert(t, kubeClient != nil)
+		default:
+			if err == nil {
+				t.Errorf("%d: wrong error detected: %s (expected) != %s (actual)", i, tc.expectedErrString, err)
+			}
+			if !strings.Contains(err.Error(), tc.expectedErrString) {
+				t.Errorf("%d: wrong error detected: %s (expected) != %s (actual)", i, tc.expectedErrString, err.Error())
+			}
+		}
+	}
+}
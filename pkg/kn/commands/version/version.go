@@ -52,7 +52,7 @@ func NewVersionCommand(p *commands.KnParams) *cobra.Command {
 		Use:   "version",
 		Short: "Show the version of this client",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if cmd.Flags().Changed("output") {
+			if cmd.Flag("output").Value.String() != "" {
 				return printVersionMachineReadable(cmd)
 			}
 			out := cmd.OutOrStdout()
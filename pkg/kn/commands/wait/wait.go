@@ -0,0 +1,142 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+
+package wait
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	messagingv1beta1 "knative.dev/eventing/pkg/apis/messaging/v1beta1"
+	sourcesv1alpha2 "knative.dev/eventing/pkg/apis/sources/v1alpha2"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+)
+
+// waitableResource is a resource type which can be waited for
+type waitableResource struct {
+	gvr  schema.GroupVersionResource
+	kind string
+}
+
+// waitableResources maps the type names accepted by 'kn wait', including short forms,
+// to the resource types
+var waitableResources = map[string]waitableResource{}
+
+// waitableTypes are the main type names accepted by 'kn wait', sorted by name
+var waitableTypes []string
+
+func init() {
+	for _, r := range []struct {
+		names []string
+		gvr   schema.GroupVersionResource
+		kind  string
+	}{
+		{[]string{"ksvc", "service", "services"}, servingv1.SchemeGroupVersion.WithResource("services"), "service"},
+		{[]string{"revision", "revisions", "rev"}, servingv1.SchemeGroupVersion.WithResource("revisions"), "revision"},
+		{[]string{"route", "routes"}, servingv1.SchemeGroupVersion.WithResource("routes"), "route"},
+		{[]string{"configuration", "configurations", "config"}, servingv1.SchemeGroupVersion.WithResource("configurations"), "configuration"},
+		{[]string{"broker", "brokers"}, eventingv1beta1.SchemeGroupVersion.WithResource("brokers"), "broker"},
+		{[]string{"trigger", "triggers"}, eventingv1beta1.SchemeGroupVersion.WithResource("triggers"), "trigger"},
+		{[]string{"channel", "channels"}, messagingv1beta1.SchemeGroupVersion.WithResource("channels"), "channel"},
+		{[]string{"subscription", "subscriptions"}, messagingv1beta1.SchemeGroupVersion.WithResource("subscriptions"), "subscription"},
+		{[]string{"pingsource", "pingsources"}, sourcesv1alpha2.SchemeGroupVersion.WithResource("pingsources"), "ping source"},
+		{[]string{"apiserversource", "apiserversources"}, sourcesv1alpha2.SchemeGroupVersion.WithResource("apiserversources"), "apiserver source"},
+		{[]string{"sinkbinding", "sinkbindings"}, sourcesv1alpha2.SchemeGroupVersion.WithResource("sinkbindings"), "sink binding"},
+		{[]string{"containersource", "containersources"}, sourcesv1alpha2.SchemeGroupVersion.WithResource("containersources"), "container source"},
+	} {
+		for _, name := range r.names {
+			waitableResources[name] = waitableResource{gvr: r.gvr, kind: r.kind}
+		}
+		waitableTypes = append(waitableTypes, r.names[0])
+	}
+	sort.Strings(waitableTypes)
+}
+
+// NewWaitCommand represents 'kn wait' command
+func NewWaitCommand(p *commands.KnParams) *cobra.Command {
+	var waitFlags commands.ResourceWaitFlags
+
+	command := &cobra.Command{
+		Use:   "wait TYPE/NAME [TYPE/NAME ...]",
+		Short: "Wait until Knative resources reach a condition",
+		Long: "Block until a condition of each given resource becomes True, e.g. for scripts which need a resource " +
+			"to be ready before going on. Fail if the condition becomes False or the timeout is reached. " +
+			"The resources are waited for one after another and the timeout applies to each of them.\n\n" +
+			"Supported types: " + strings.Join(waitableTypes, ", "),
+		Example: `
+  # Wait at most two minutes until service 'mysvc' is ready
+  kn wait ksvc/mysvc --for=condition=Ready --timeout=2m
+
+  # Wait until broker 'default' and trigger 'mytrigger' are ready
+  kn wait broker/default trigger/mytrigger`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errors.New("'kn wait' requires one or more resources given as TYPE/NAME, e.g. 'ksvc/mysvc'")
+			}
+			resources := make([]waitableResource, len(args))
+			names := make([]string, len(args))
+			for i, arg := range args {
+				var err error
+				resources[i], names[i], err = parseResource(arg)
+				if err != nil {
+					return err
+				}
+			}
+			conditionType, err := waitFlags.ConditionType()
+			if err != nil {
+				return err
+			}
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := p.NewDynamicClient(namespace)
+			if err != nil {
+				return err
+			}
+			for i, resource := range resources {
+				err = commands.WaitForResource(client, resource.gvr, resource.kind, names[i], conditionType, waitFlags.Timeout, cmd.OutOrStdout())
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	commands.AddNamespaceFlags(command.Flags(), false)
+	waitFlags.Add(command)
+	return command
+}
+
+// parseResource splits an argument of the form TYPE/NAME
+func parseResource(arg string) (waitableResource, string, error) {
+	parts := strings.SplitN(arg, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return waitableResource{}, "", fmt.Errorf("invalid resource '%s', expected TYPE/NAME, e.g. 'ksvc/mysvc'", arg)
+	}
+	resource, ok := waitableResources[strings.ToLower(parts[0])]
+	if !ok {
+		return waitableResource{}, "", fmt.Errorf("unsupported type '%s' in '%s', supported types: %s", parts[0], arg, strings.Join(waitableTypes, ", "))
+	}
+	return resource, parts[1], nil
+}
@@ -0,0 +1,115 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+
+package wait
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	clientdynamic "knative.dev/client/pkg/dynamic"
+	dynamicfake "knative.dev/client/pkg/dynamic/fake"
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/util"
+)
+
+func TestWait(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		args     []string
+		expected []string
+		err      string
+	}{{
+		name:     "service",
+		args:     []string{"ksvc/mysvc"},
+		expected: []string{"Service 'mysvc' in namespace 'default' has condition Ready=True."},
+	}, {
+		name: "several resources",
+		args: []string{"Service/mysvc", "broker/default", "--timeout", "2m"},
+		expected: []string{
+			"Service 'mysvc' in namespace 'default' has condition Ready=True.",
+			"Broker 'default' in namespace 'default' has condition Ready=True.",
+		},
+	}, {
+		name:     "other condition",
+		args:     []string{"trigger/mytrigger", "--for=condition=Subscribed"},
+		expected: []string{"Trigger 'mytrigger' in namespace 'default' has condition Subscribed=True."},
+	}, {
+		name: "missing resource",
+		args: []string{"broker/other"},
+		err:  "not found",
+	}, {
+		name: "no resource",
+		args: []string{},
+		err:  "requires one or more resources",
+	}, {
+		name: "no type",
+		args: []string{"mysvc"},
+		err:  "invalid resource 'mysvc', expected TYPE/NAME",
+	}, {
+		name: "unsupported type",
+		args: []string{"pod/mysvc"},
+		err:  "unsupported type 'pod' in 'pod/mysvc', supported types: apiserversource, broker,",
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			dynamicClient := dynamicfake.CreateFakeKnDynamicClient("default",
+				newReadyObject("serving.knative.dev/v1", "Service", "mysvc"),
+				newReadyObject("eventing.knative.dev/v1beta1", "Broker", "default"),
+				newReadyObject("eventing.knative.dev/v1beta1", "Trigger", "mytrigger"))
+			p := &commands.KnParams{
+				NewDynamicClient: func(namespace string) (clientdynamic.KnDynamicClient, error) {
+					return dynamicClient, nil
+				},
+			}
+			cmd, _, output := commands.CreateTestKnCommand(NewWaitCommand(p), p)
+			cmd.SetArgs(append([]string{"wait"}, tc.args...))
+			err := cmd.Execute()
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Assert(t, util.ContainsAll(output.String(), tc.expected...))
+		})
+	}
+}
+
+// newReadyObject returns a reconciled resource in namespace 'default' with the conditions
+// Ready and Subscribed set to True
+func newReadyObject(apiVersion string, kind string, name string) runtime.Object {
+	resource := &duckv1.KResource{
+		TypeMeta: metav1.TypeMeta{APIVersion: apiVersion, Kind: kind},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Namespace:  "default",
+			Generation: 1,
+		},
+	}
+	resource.Status.ObservedGeneration = 1
+	resource.Status.Conditions = []apis.Condition{
+		{Type: apis.ConditionReady, Status: corev1.ConditionTrue},
+		{Type: "Subscribed", Status: corev1.ConditionTrue},
+	}
+	content, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(resource)
+	return &unstructured.Unstructured{Object: content}
+}
@@ -16,10 +16,13 @@ package commands
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
 
 	knflags "knative.dev/client/pkg/kn/flags"
+	"knative.dev/client/pkg/wait"
 )
 
 // Default time out to use when waiting for reconciliation. It is deliberately very long as it is expected that
@@ -33,6 +36,16 @@ type WaitFlags struct {
 	TimeoutInSeconds int
 	// If set then apply resources and wait for completion
 	Wait bool
+	// If set then verify after the service became ready that its URL serves requests
+	WaitForRoutePropagation bool
+	// HTTP status expected from the URL when verifying the route propagation, any 2xx status if 0
+	RoutePropagationStatus int
+	// Format of the progress events while waiting, one of wait.ProgressFormats
+	Progress string
+	// If set then print diagnostics of the latest revision when waiting fails
+	VerboseErrors bool
+
+	params *KnParams
 }
 
 // Add flags which influence the wait/no-wait behaviour when creating or updating
@@ -50,3 +63,64 @@ func (p *WaitFlags) AddConditionWaitFlags(command *cobra.Command, waitTimeoutDef
 	timeoutUsage := fmt.Sprintf("Seconds to wait before giving up on waiting for %s to be %s.", what, until)
 	command.Flags().IntVar(&p.TimeoutInSeconds, "wait-timeout", waitTimeoutDefault, timeoutUsage)
 }
+
+// AddRoutePropagationFlags adds flags for verifying after a service became ready that its
+// URL serves requests, as the routes of some ingresses are programmed only after the service
+// is ready
+func (p *WaitFlags) AddRoutePropagationFlags(command *cobra.Command) {
+	command.Flags().BoolVar(&p.WaitForRoutePropagation, "wait-for-route-propagation", false,
+		"After the service is ready, wait until its URL can be resolved and returns a successful response. "+
+			"Some ingresses program the route only after the service reports to be ready.")
+	command.Flags().IntVar(&p.RoutePropagationStatus, "route-propagation-status", 0,
+		"HTTP status expected from the URL with --wait-for-route-propagation. Any 2xx status is accepted by default.")
+}
+
+// AddProgressFlags adds the flag selecting how progress is reported while waiting
+func (p *WaitFlags) AddProgressFlags(command *cobra.Command) {
+	p.Progress = wait.ProgressFormatText
+	command.Flags().Var((*progressFormat)(&p.Progress), "wait-progress",
+		fmt.Sprintf("Format of the progress reported while waiting. One of: %s. "+
+			"With json, every event is written as a single line with phase, condition, message and elapsed time.",
+			strings.Join(wait.ProgressFormats, "|")))
+}
+
+// AddVerboseErrorsFlag adds the flag for printing diagnostics of the latest revision, its
+// pods and the events of its deployment when waiting for a service fails
+func (p *WaitFlags) AddVerboseErrorsFlag(command *cobra.Command, params *KnParams) {
+	p.params = params
+	command.Flags().BoolVar(&p.VerboseErrors, "verbose-errors", false,
+		"When waiting fails, print the conditions of the latest revision, the status of its pods and containers "+
+			"and the recent events of its deployment.")
+}
+
+// KubeClient returns the Kubernetes client for collecting the diagnostics with --verbose-errors
+func (p *WaitFlags) KubeClient() (kubernetes.Interface, error) {
+	if p.params == nil {
+		return nil, fmt.Errorf("no client configured for collecting diagnostics")
+	}
+	if p.params.NewKubeClient == nil {
+		p.params.Initialize()
+	}
+	return p.params.NewKubeClient()
+}
+
+// progressFormat is a flag value accepting only the formats of wait.ProgressFormats
+type progressFormat string
+
+func (f *progressFormat) String() string {
+	return string(*f)
+}
+
+func (f *progressFormat) Set(value string) error {
+	for _, format := range wait.ProgressFormats {
+		if value == format {
+			*f = progressFormat(value)
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of %s", strings.Join(wait.ProgressFormats, "|"))
+}
+
+func (f *progressFormat) Type() string {
+	return "string"
+}
@@ -135,3 +135,16 @@ func TestAddWaitUsageDelete(t *testing.T) {
 		t.Error("Delete has wrong default value for --no-wait")
 	}
 }
+
+func TestAddProgressFlags(t *testing.T) {
+	flags := &WaitFlags{}
+	cmd := cobra.Command{}
+	flags.AddProgressFlags(&cmd)
+	assert.Equal(t, flags.Progress, "text")
+
+	assert.NilError(t, cmd.ParseFlags([]string{"--wait-progress", "json"}))
+	assert.Equal(t, flags.Progress, "json")
+
+	err := cmd.ParseFlags([]string{"--wait-progress", "xml"})
+	assert.ErrorContains(t, err, "must be one of text|json|none")
+}
@@ -0,0 +1,92 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+
+	clientdynamic "knative.dev/client/pkg/dynamic"
+	knerrors "knative.dev/client/pkg/errors"
+	"knative.dev/client/pkg/wait"
+)
+
+// Prefix of the value of --for when waiting for a condition
+const forConditionPrefix = "condition="
+
+// ResourceWaitFlags are the flags of commands which block until a resource reaches a condition
+type ResourceWaitFlags struct {
+	For     string
+	Timeout time.Duration
+}
+
+// Add adds --for and --timeout to the command
+func (f *ResourceWaitFlags) Add(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.For, "for", forConditionPrefix+string(apis.ConditionReady),
+		"Condition to wait for as 'condition=<type>', e.g. 'condition=Ready'. The condition has to become True.")
+	cmd.Flags().DurationVar(&f.Timeout, "timeout", WaitDefaultTimeout*time.Second,
+		"Time to wait before giving up, e.g. 2m or 90s.")
+}
+
+// ConditionType returns the type of the condition given with --for
+func (f *ResourceWaitFlags) ConditionType() (apis.ConditionType, error) {
+	conditionType := strings.TrimPrefix(f.For, forConditionPrefix)
+	if conditionType == f.For || conditionType == "" {
+		return "", fmt.Errorf("invalid value '%s' for --for, expected 'condition=<type>', e.g. 'condition=Ready'", f.For)
+	}
+	return apis.ConditionType(conditionType), nil
+}
+
+// WaitForResource blocks until the condition of the named resource is True. Any resource which
+// follows the Knative status duck type is supported. It fails if the resource doesn't exist, if the
+// condition becomes False or if the timeout is reached.
+func WaitForResource(client clientdynamic.KnDynamicClient, gvr schema.GroupVersionResource, kind string, name string,
+	conditionType apis.ConditionType, timeout time.Duration, out io.Writer) error {
+
+	resourceClient := client.RawClient().Resource(gvr).Namespace(client.Namespace())
+	obj, err := resourceClient.Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return knerrors.GetError(err)
+	}
+	done, err := wait.IsConditionTrue(obj, conditionType, wait.DuckConditionsExtractor)
+	if err != nil {
+		return err
+	}
+	if !done {
+		watcher, err := wait.NewDynamicWatcher(resourceClient, name, obj.GetResourceVersion(), timeout)
+		if err != nil {
+			return knerrors.GetError(err)
+		}
+		defer watcher.Stop()
+		waitForCondition := wait.NewWaitForCondition(kind, conditionType, wait.DuckConditionsExtractor)
+		err, _ = waitForCondition.Wait(watcher, name, wait.Options{Timeout: &timeout}, wait.NoopMessageCallback())
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(out, "%s '%s' in namespace '%s' has condition %s=True.\n",
+		strings.Title(kind), name, client.Namespace(), conditionType)
+	return nil
+}
@@ -0,0 +1,48 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"knative.dev/client/pkg/util"
+)
+
+// warningRecorder returns the recorder of the API server's warnings, which is shared by all clients
+func (params *KnParams) warningRecorder() *util.WarningRecorder {
+	if params.warnings == nil {
+		params.warnings = &util.WarningRecorder{}
+	}
+	return params.warnings
+}
+
+// ReportWarnings prints the warnings which the API server returned for the changes of the
+// command, like deprecations or the warnings of admission webhooks and policies. With
+// --fail-on-warning an error is returned if there have been warnings.
+func (params *KnParams) ReportWarnings(out io.Writer) error {
+	warnings := params.warningRecorder().Warnings()
+	if len(warnings) == 0 {
+		return nil
+	}
+	fmt.Fprintln(out)
+	for _, warning := range warnings {
+		fmt.Fprintf(out, "WARNING: the API server returned a warning: %s\n", warning)
+	}
+	if params.FailOnWarning {
+		return fmt.Errorf("the API server returned %d warning(s). Fix the warnings or run without --fail-on-warning", len(warnings))
+	}
+	return nil
+}
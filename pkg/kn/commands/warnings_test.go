@@ -0,0 +1,75 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func newWarningServerParams(t *testing.T) (*KnParams, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.Header().Add("Warning", `299 - "policy: config maps should have an owner label"`)
+		}
+		fmt.Fprint(w, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"foo","namespace":"default"}}`)
+	}))
+	params := &KnParams{ClientConfig: clientcmd.NewDefaultClientConfig(clientcmdapi.Config{},
+		&clientcmd.ConfigOverrides{ClusterInfo: clientcmdapi.Cluster{Server: server.URL}})}
+	params.Initialize()
+	return params, server.Close
+}
+
+func TestReportWarnings(t *testing.T) {
+	params, cleanup := newWarningServerParams(t)
+	defer cleanup()
+	client, err := params.NewKubeClient()
+	assert.NilError(t, err)
+
+	_, err = client.CoreV1().ConfigMaps("default").Get(context.TODO(), "foo", metav1.GetOptions{})
+	assert.NilError(t, err)
+	out := new(bytes.Buffer)
+	assert.NilError(t, params.ReportWarnings(out))
+	assert.Equal(t, out.String(), "")
+
+	_, err = client.CoreV1().ConfigMaps("default").Create(context.TODO(), &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}, metav1.CreateOptions{})
+	assert.NilError(t, err)
+	assert.NilError(t, params.ReportWarnings(out))
+	assert.Equal(t, out.String(), "\nWARNING: the API server returned a warning: policy: config maps should have an owner label\n")
+}
+
+func TestReportWarningsFailOnWarning(t *testing.T) {
+	params, cleanup := newWarningServerParams(t)
+	defer cleanup()
+	params.FailOnWarning = true
+	client, err := params.NewKubeClient()
+	assert.NilError(t, err)
+
+	_, err = client.CoreV1().ConfigMaps("default").Create(context.TODO(), &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}, metav1.CreateOptions{})
+	assert.NilError(t, err)
+	err = params.ReportWarnings(new(bytes.Buffer))
+	assert.ErrorContains(t, err, "the API server returned 1 warning(s)")
+}
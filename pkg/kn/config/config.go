@@ -16,14 +16,19 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"strconv"
 
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/pkg/errors"
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"k8s.io/client-go/util/jsonpath"
 )
 
 // bootstrapDefaults are the defaults values to use
@@ -46,6 +51,12 @@ type config struct {
 
 	// channelTypeMappings is a list of channel type mapping
 	channelTypeMappings []ChannelTypeMapping
+
+	// policies are the guardrails for services
+	policies Policies
+
+	// retry overrides the defaults of the retry policy
+	retry Retry
 }
 
 // ConfigFile returns the config file which is either the default XDG conform
@@ -64,6 +75,8 @@ func (c *config) PluginsDir() string {
 	} else if viper.IsSet(legacyKeyPluginsDirectory) {
 		// Remove that branch if legacy option is switched off
 		return viper.GetString(legacyKeyPluginsDirectory)
+	} else if dir := os.Getenv(envPluginsDir); dir != "" {
+		return dir
 	} else {
 		return bootstrapDefaults.pluginsDir
 	}
@@ -76,6 +89,9 @@ func (c *config) LookupPluginsInPath() bool {
 	} else if viper.IsSet(legacyKeyPluginsLookupInPath) {
 		// Remove that branch if legacy option is switched off
 		return viper.GetBool(legacyKeyPluginsLookupInPath)
+	} else if lookup, err := strconv.ParseBool(os.Getenv(envPluginsLookupInPath)); err == nil {
+		// Invalid values are rejected in BootstrapConfig()
+		return lookup
 	} else {
 		// If legacy branch is removed, switch to setting the default to viper
 		// See TODO comment below.
@@ -91,6 +107,47 @@ func (c *config) ChannelTypeMappings() []ChannelTypeMapping {
 	return c.channelTypeMappings
 }
 
+// NamespaceRequired returns true if the configuration forbids the fallback to the "default" namespace
+func (c *config) NamespaceRequired() bool {
+	return viper.GetBool(keyNamespaceRequired)
+}
+
+// AllowedNamespaces returns the configured patterns of namespaces which may be used
+func (c *config) AllowedNamespaces() []string {
+	return viper.GetStringSlice(keyNamespaceAllowed)
+}
+
+// DeniedNamespaces returns the configured patterns of namespaces which must not be used
+func (c *config) DeniedNamespaces() []string {
+	return viper.GetStringSlice(keyNamespaceDenied)
+}
+
+func (c *config) Policies() Policies {
+	return c.policies
+}
+
+// Language returns the language of the messages
+func (c *config) Language() string {
+	if viper.IsSet(keyLanguage) {
+		return viper.GetString(keyLanguage)
+	}
+	return os.Getenv(envLanguage)
+}
+
+func (c *config) Retry() Retry {
+	return c.retry
+}
+
+// ServerSideApply returns true if the configuration enables server-side apply for updates
+func (c *config) ServerSideApply() bool {
+	return viper.GetBool(keyServerSideApply)
+}
+
+// NotificationSink returns the URL for CloudEvents about completed operations
+func (c *config) NotificationSink() string {
+	return viper.GetString(keyNotificationSink)
+}
+
 // Config used for flag binding
 var globalConfig = config{}
 
@@ -122,6 +179,13 @@ func BootstrapConfig() error {
 		return err
 	}
 
+	// Validate the environment as it's only looked at when the options are read
+	if lookup := os.Getenv(envPluginsLookupInPath); lookup != "" {
+		if _, err := strconv.ParseBool(lookup); err != nil {
+			return fmt.Errorf("invalid value '%s' of environment variable %s (must be true or false)", lookup, envPluginsLookupInPath)
+		}
+	}
+
 	// Check if configfile exists. If not, just return
 	configFile := GlobalConfig.ConfigFile()
 	_, err = os.Lstat(configFile)
@@ -156,7 +220,30 @@ func BootstrapConfig() error {
 
 	// Deserialize channel type mappings if configured
 	err = parseChannelTypeMappings()
-	return err
+	if err != nil {
+		return err
+	}
+
+	// Validate the namespace patterns early, so that a typo doesn't silently allow everything
+	err = validateNamespacePatterns()
+	if err != nil {
+		return err
+	}
+
+	// Deserialize policies if configured
+	err = parsePolicies()
+	if err != nil {
+		return err
+	}
+
+	// Deserialize the retry policy if configured
+	err = parseRetry()
+	if err != nil {
+		return err
+	}
+
+	// Validate the notification sink early, so that notifications don't get lost
+	return validateNotificationSink()
 }
 
 // Add bootstrap flags use in a separate bootstrap proceeds
@@ -175,25 +262,32 @@ func AddBootstrapFlags(flags *flag.FlagSet) {
 // Initialize defaults. This happens lazily go allow to change the
 // home directory for e.g. tests
 func initDefaults() *defaultConfig {
+	configDir, err := defaultConfigDir(runtime.GOOS)
+	if err != nil {
+		// Without a config directory, the config file and plugins directory need to be given explicitly
+		fmt.Fprintf(os.Stderr, "WARNING: %s. Use --config to specify the kn configuration file\n", err.Error())
+		return &defaultConfig{lookupPluginsInPath: false}
+	}
 	return &defaultConfig{
-		configFile:          defaultConfigLocation("config.yaml"),
-		pluginsDir:          defaultConfigLocation("plugins"),
+		configFile:          filepath.Join(configDir, "config.yaml"),
+		pluginsDir:          filepath.Join(configDir, "plugins"),
 		lookupPluginsInPath: false,
 	}
 }
 
-func defaultConfigLocation(subpath string) string {
-	var base string
-	if runtime.GOOS == "windows" {
-		base = defaultConfigDirWindows()
-	} else {
-		base = defaultConfigDirUnix()
+// homeDir looks up the user's home directory, can be replaced in tests
+var homeDir = homedir.Dir
+
+// defaultConfigDir returns the default configuration directory for the given OS
+func defaultConfigDir(goos string) (string, error) {
+	if goos == "windows" {
+		return defaultConfigDirWindows()
 	}
-	return filepath.Join(base, subpath)
+	return defaultConfigDirUnix(), nil
 }
 
 func defaultConfigDirUnix() string {
-	home, err := homedir.Dir()
+	home, err := homeDir()
 	if err != nil {
 		home = "~"
 	}
@@ -214,18 +308,28 @@ func defaultConfigDirUnix() string {
 	return filepath.Join(home, ".config", "kn")
 }
 
-func defaultConfigDirWindows() string {
-	home, err := homedir.Dir()
-	if err != nil {
-		// Check the deprecated path first and fallback to it, add warning to error message
-		if configHome := filepath.Join(home, ".kn"); dirExists(configHome) {
-			migrationPath := filepath.Join(os.Getenv("APPDATA"), "kn")
-			fmt.Fprintf(os.Stderr, "WARNING: deprecated kn config directory '%s' detected. Please move your configuration to '%s'\n", configHome, migrationPath)
-			return configHome
+func defaultConfigDirWindows() (string, error) {
+	// A "~" is not expanded on Windows, so there is no fallback without a home directory
+	home, homeErr := homeDir()
+
+	// Fallback to the default location of %APPDATA% if not set
+	appData := os.Getenv("APPDATA")
+	if appData == "" {
+		if homeErr != nil {
+			return "", fmt.Errorf("cannot determine kn config directory: %%APPDATA%% is not set and %s", homeErr.Error())
 		}
+		appData = filepath.Join(home, "AppData", "Roaming")
 	}
+	configDir := filepath.Join(appData, "kn")
 
-	return filepath.Join(os.Getenv("APPDATA"), "kn")
+	// Check the deprecated path first and fallback to it, add warning to error message
+	if homeErr == nil {
+		if configHome := filepath.Join(home, ".kn"); dirExists(configHome) {
+			fmt.Fprintf(os.Stderr, "WARNING: deprecated kn config directory '%s' detected. Please move your configuration to '%s'\n", configHome, configDir)
+			return configHome, nil
+		}
+	}
+	return configDir, nil
 }
 
 func dirExists(path string) bool {
@@ -268,6 +372,84 @@ func parseChannelTypeMappings() error {
 	return nil
 }
 
+// parse the policies for services and store them in the global configuration
+func parsePolicies() error {
+	if !viper.IsSet(keyPolicies) {
+		return nil
+	}
+	err := viper.UnmarshalKey(keyPolicies, &globalConfig.policies)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error while parsing policies in configuration file %s",
+			viper.ConfigFileUsed()))
+	}
+	err = ValidatePolicyRules(globalConfig.policies.Rules)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("invalid policies in configuration file %s", viper.ConfigFileUsed()))
+	}
+	return nil
+}
+
+// parse the retry policy and store it in the global configuration
+func parseRetry() error {
+	if !viper.IsSet(keyRetry) {
+		return nil
+	}
+	err := viper.UnmarshalKey(keyRetry, &globalConfig.retry)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error while parsing retry policy in configuration file %s",
+			viper.ConfigFileUsed()))
+	}
+	return nil
+}
+
+// validateNotificationSink checks that the notification sink is an absolute HTTP URL
+func validateNotificationSink() error {
+	sink := GlobalConfig.NotificationSink()
+	if sink == "" {
+		return nil
+	}
+	sinkURL, err := url.Parse(sink)
+	if err != nil || (sinkURL.Scheme != "http" && sinkURL.Scheme != "https") || sinkURL.Host == "" {
+		return fmt.Errorf("invalid notification sink '%s' (must be an absolute http or https URL)", sink)
+	}
+	return nil
+}
+
+// ValidatePolicyRules checks that the rules are complete and their paths and patterns can be parsed
+func ValidatePolicyRules(rules []PolicyRule) error {
+	for i, rule := range rules {
+		if rule.Name == "" {
+			return fmt.Errorf("policy rule #%d has no name", i+1)
+		}
+		if rule.Path == "" {
+			return fmt.Errorf("policy rule '%s' has no path", rule.Name)
+		}
+		if !rule.Required && rule.Pattern == "" {
+			return fmt.Errorf("policy rule '%s' needs 'required' or a 'pattern'", rule.Name)
+		}
+		if err := jsonpath.New(rule.Name).Parse(rule.Path); err != nil {
+			return fmt.Errorf("invalid path of policy rule '%s': %v", rule.Name, err)
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern of policy rule '%s': %v", rule.Name, err)
+		}
+	}
+	return nil
+}
+
+// validate the patterns of the allowed and denied namespaces
+func validateNamespacePatterns() error {
+	for _, key := range []string{keyNamespaceAllowed, keyNamespaceDenied} {
+		for _, pattern := range viper.GetStringSlice(key) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid namespace pattern '%s' in '%s' of configuration file %s: %v",
+					pattern, key, viper.ConfigFileUsed(), err)
+			}
+		}
+	}
+	return nil
+}
+
 // Prepare the default config file for the usage message
 func defaultConfigFileForUsageMessage() string {
 	if runtime.GOOS == "windows" {
@@ -15,10 +15,12 @@
 package config
 
 import (
+	"errors"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/viper"
@@ -42,6 +44,20 @@ eventing:
     kind: KafkaChannel
     group: messaging.knative.dev
     version: v1alpha1
+
+namespace:
+  required: true
+  allowed:
+  - team-*
+  denied:
+  - default
+  - kube-system
+
+update:
+  server-side-apply: true
+
+notifications:
+  sink: http://broker-ingress.knative-eventing.svc.cluster.local/team/default
 `
 
 	configFile, cleanup := setupConfig(t, configYaml)
@@ -67,6 +83,106 @@ eventing:
 		Group:   "messaging.knative.dev",
 		Version: "v1alpha1",
 	})
+	assert.Assert(t, GlobalConfig.NamespaceRequired())
+	assert.DeepEqual(t, GlobalConfig.AllowedNamespaces(), []string{"team-*"})
+	assert.DeepEqual(t, GlobalConfig.DeniedNamespaces(), []string{"default", "kube-system"})
+	assert.Assert(t, GlobalConfig.ServerSideApply())
+	assert.Equal(t, GlobalConfig.NotificationSink(), "http://broker-ingress.knative-eventing.svc.cluster.local/team/default")
+}
+
+func TestBootstrapConfigPolicies(t *testing.T) {
+	configYaml := `
+policies:
+  allow-skip: true
+  configmap: kn-admin/kn-policies
+  rules:
+  - name: corp-registry
+    path: "{.spec.template.spec.containers[*].image}"
+    pattern: ^registry\.corp/
+    message: images must come from registry.corp
+`
+	_, cleanup := setupConfig(t, configYaml)
+	defer cleanup()
+
+	err := BootstrapConfig()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, GlobalConfig.Policies(), Policies{
+		AllowSkip: true,
+		ConfigMap: "kn-admin/kn-policies",
+		Rules: []PolicyRule{{
+			Name:    "corp-registry",
+			Path:    "{.spec.template.spec.containers[*].image}",
+			Pattern: `^registry\.corp/`,
+			Message: "images must come from registry.corp",
+		}},
+	})
+}
+
+func TestBootstrapConfigInvalidPolicies(t *testing.T) {
+	for _, tc := range []struct {
+		rule        string
+		errContents string
+	}{
+		{"path: '{.spec}'\n    required: true", "policy rule #1 has no name"},
+		{"name: a\n    required: true", "policy rule 'a' has no path"},
+		{"name: a\n    path: '{.spec}'", "policy rule 'a' needs 'required' or a 'pattern'"},
+		{"name: a\n    path: '{.spec'\n    required: true", "invalid path of policy rule 'a'"},
+		{"name: a\n    path: '{.spec}'\n    pattern: '('", "invalid pattern of policy rule 'a'"},
+	} {
+		_, cleanup := setupConfig(t, "policies:\n  rules:\n  - "+tc.rule+"\n")
+		err := BootstrapConfig()
+		assert.ErrorContains(t, err, tc.errContents)
+		cleanup()
+	}
+}
+
+func TestBootstrapConfigRetry(t *testing.T) {
+	configYaml := `
+retry:
+  max-retries: 5
+  backoff: 1s
+  jitter: 0
+`
+	_, cleanup := setupConfig(t, configYaml)
+	defer cleanup()
+
+	err := BootstrapConfig()
+	assert.NilError(t, err)
+	retry := GlobalConfig.Retry()
+	assert.Equal(t, *retry.MaxRetries, 5)
+	assert.Equal(t, *retry.Backoff, time.Second)
+	assert.Assert(t, retry.Factor == nil)
+	assert.Equal(t, *retry.Jitter, 0.0)
+}
+
+func TestBootstrapConfigInvalidRetry(t *testing.T) {
+	_, cleanup := setupConfig(t, "retry:\n  backoff: soon\n")
+	defer cleanup()
+
+	err := BootstrapConfig()
+	assert.ErrorContains(t, err, "error while parsing retry policy")
+}
+
+func TestBootstrapConfigInvalidNotificationSink(t *testing.T) {
+	for _, sink := range []string{"broker-ingress/team/default", "ftp://example.com", "http://"} {
+		_, cleanup := setupConfig(t, "notifications:\n  sink: "+sink+"\n")
+		err := BootstrapConfig()
+		assert.ErrorContains(t, err, "invalid notification sink '"+sink+"'")
+		cleanup()
+	}
+}
+
+func TestBootstrapConfigInvalidNamespacePattern(t *testing.T) {
+	configYaml := `
+namespace:
+  denied:
+  - "team-["
+`
+	_, cleanup := setupConfig(t, configYaml)
+	defer cleanup()
+
+	err := BootstrapConfig()
+	assert.ErrorContains(t, err, "invalid namespace pattern 'team-['")
 }
 
 func TestBootstrapConfigWithoutConfigFile(t *testing.T) {
@@ -111,6 +227,141 @@ sink:
 	})
 }
 
+func TestBootstrapConfigFromEnvironment(t *testing.T) {
+	defer setEnv(envPluginsDir, "/env-plugins")()
+	defer setEnv(envPluginsLookupInPath, "true")()
+
+	// Environment is used without a config file
+	_, cleanup := setupConfig(t, "")
+	err := BootstrapConfig()
+	assert.NilError(t, err)
+	assert.Equal(t, GlobalConfig.PluginsDir(), "/env-plugins")
+	assert.Equal(t, GlobalConfig.LookupPluginsInPath(), true)
+	cleanup()
+
+	// Config file takes precedence over the environment
+	configYaml := `
+plugins:
+  directory: /config-plugins
+  path-lookup: false
+`
+	_, cleanup = setupConfig(t, configYaml)
+	defer cleanup()
+	err = BootstrapConfig()
+	assert.NilError(t, err)
+	assert.Equal(t, GlobalConfig.PluginsDir(), "/config-plugins")
+	assert.Equal(t, GlobalConfig.LookupPluginsInPath(), false)
+
+	os.Setenv(envPluginsLookupInPath, "maybe")
+	err = BootstrapConfig()
+	assert.ErrorContains(t, err, "KN_LOOKUP_PLUGINS")
+}
+
+func TestBootstrapConfigLanguage(t *testing.T) {
+	defer setEnv(envLanguage, "de_DE.UTF-8")()
+
+	// Environment is used without a config file
+	_, cleanup := setupConfig(t, "")
+	err := BootstrapConfig()
+	assert.NilError(t, err)
+	assert.Equal(t, GlobalConfig.Language(), "de_DE.UTF-8")
+	cleanup()
+
+	// Config file takes precedence over the environment
+	configYaml := `
+output:
+  language: zh
+`
+	_, cleanup = setupConfig(t, configYaml)
+	defer cleanup()
+	err = BootstrapConfig()
+	assert.NilError(t, err)
+	assert.Equal(t, GlobalConfig.Language(), "zh")
+}
+
+func setEnv(key, value string) func() {
+	oldValue, isSet := os.LookupEnv(key)
+	os.Setenv(key, value)
+	return func() {
+		if isSet {
+			os.Setenv(key, oldValue)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
+
+func TestDefaultConfigDir(t *testing.T) {
+	_, cleanup := setupConfig(t, "")
+	defer cleanup()
+	home := os.Getenv("HOME")
+
+	for _, env := range []string{"XDG_CONFIG_HOME", "APPDATA"} {
+		oldValue, wasSet := os.LookupEnv(env)
+		defer func(env string) {
+			if wasSet {
+				os.Setenv(env, oldValue)
+			} else {
+				os.Unsetenv(env)
+			}
+		}(env)
+	}
+
+	configDir := func(goos string) string {
+		dir, err := defaultConfigDir(goos)
+		assert.NilError(t, err)
+		return dir
+	}
+
+	// Unix: XDG default and XDG_CONFIG_HOME
+	os.Unsetenv("XDG_CONFIG_HOME")
+	assert.Equal(t, configDir("linux"), filepath.Join(home, ".config", "kn"))
+	assert.Equal(t, configDir("darwin"), filepath.Join(home, ".config", "kn"))
+	os.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg"))
+	assert.Equal(t, configDir("linux"), filepath.Join(home, "xdg", "kn"))
+
+	// Windows: %APPDATA% and its default location
+	os.Setenv("APPDATA", filepath.Join(home, "appdata"))
+	assert.Equal(t, configDir("windows"), filepath.Join(home, "appdata", "kn"))
+	os.Unsetenv("APPDATA")
+	assert.Equal(t, configDir("windows"), filepath.Join(home, "AppData", "Roaming", "kn"))
+
+	// Deprecated ~/.kn is used on both when present
+	legacyDir := filepath.Join(home, ".kn")
+	assert.NilError(t, os.Mkdir(legacyDir, 0700))
+	assert.Equal(t, configDir("linux"), legacyDir)
+	assert.Equal(t, configDir("windows"), legacyDir)
+}
+
+func TestDefaultConfigDirWindowsWithoutHome(t *testing.T) {
+	_, cleanup := setupConfig(t, "")
+	defer cleanup()
+	oldHomeDir := homeDir
+	defer func() { homeDir = oldHomeDir }()
+	homeDir = func() (string, error) {
+		return "", errors.New("home directory not found")
+	}
+	oldAppData, wasSet := os.LookupEnv("APPDATA")
+	defer func() {
+		if wasSet {
+			os.Setenv("APPDATA", oldAppData)
+		} else {
+			os.Unsetenv("APPDATA")
+		}
+	}()
+
+	// %APPDATA% doesn't need the home directory
+	os.Setenv("APPDATA", filepath.Join("C:", "Users", "foo", "AppData", "Roaming"))
+	dir, err := defaultConfigDir("windows")
+	assert.NilError(t, err)
+	assert.Equal(t, dir, filepath.Join("C:", "Users", "foo", "AppData", "Roaming", "kn"))
+
+	// No relative "~" path as fallback
+	os.Unsetenv("APPDATA")
+	_, err = defaultConfigDir("windows")
+	assert.ErrorContains(t, err, "home directory not found")
+}
+
 func setupConfig(t *testing.T, configContent string) (string, func()) {
 	tmpDir, err := ioutil.TempDir("", "configContent")
 	assert.NilError(t, err)
@@ -23,6 +23,14 @@ type TestConfig struct {
 	TestLookupPluginsInPath bool
 	TestSinkMappings        []SinkMapping
 	TestChannelTypeMappings []ChannelTypeMapping
+	TestNamespaceRequired   bool
+	TestAllowedNamespaces   []string
+	TestDeniedNamespaces    []string
+	TestPolicies            Policies
+	TestLanguage            string
+	TestRetry               Retry
+	TestServerSideApply     bool
+	TestNotificationSink    string
 }
 
 // Ensure that TestConfig implements the configuration interface
@@ -33,3 +41,11 @@ func (t TestConfig) ConfigFile() string                        { return t.TestCo
 func (t TestConfig) LookupPluginsInPath() bool                 { return t.TestLookupPluginsInPath }
 func (t TestConfig) SinkMappings() []SinkMapping               { return t.TestSinkMappings }
 func (t TestConfig) ChannelTypeMappings() []ChannelTypeMapping { return t.TestChannelTypeMappings }
+func (t TestConfig) NamespaceRequired() bool                   { return t.TestNamespaceRequired }
+func (t TestConfig) AllowedNamespaces() []string               { return t.TestAllowedNamespaces }
+func (t TestConfig) DeniedNamespaces() []string                { return t.TestDeniedNamespaces }
+func (t TestConfig) Policies() Policies                        { return t.TestPolicies }
+func (t TestConfig) Language() string                          { return t.TestLanguage }
+func (t TestConfig) Retry() Retry                              { return t.TestRetry }
+func (t TestConfig) ServerSideApply() bool                     { return t.TestServerSideApply }
+func (t TestConfig) NotificationSink() string                  { return t.TestNotificationSink }
@@ -29,6 +29,10 @@ func TestTestConfig(t *testing.T) {
 		TestLookupPluginsInPath: true,
 		TestSinkMappings:        nil,
 		TestChannelTypeMappings: nil,
+		TestNamespaceRequired:   true,
+		TestAllowedNamespaces:   []string{"team-*"},
+		TestDeniedNamespaces:    []string{"default"},
+		TestLanguage:            "de",
 	}
 
 	assert.Equal(t, cfg.PluginsDir(), "pluginsDir")
@@ -36,4 +40,8 @@ func TestTestConfig(t *testing.T) {
 	assert.Assert(t, cfg.LookupPluginsInPath())
 	assert.Assert(t, cfg.SinkMappings() == nil)
 	assert.Assert(t, cfg.ChannelTypeMappings() == nil)
+	assert.Assert(t, cfg.NamespaceRequired())
+	assert.DeepEqual(t, cfg.AllowedNamespaces(), []string{"team-*"})
+	assert.DeepEqual(t, cfg.DeniedNamespaces(), []string{"default"})
+	assert.Equal(t, cfg.Language(), "de")
 }
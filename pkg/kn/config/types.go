@@ -14,6 +14,8 @@
 
 package config
 
+import "time"
+
 // Package for holding configuration types used in bootstrapping
 // and for types in configuration files
 
@@ -34,6 +36,37 @@ type Config interface {
 
 	// ChannelTypeMappings returns additional mappings for channel type aliases
 	ChannelTypeMappings() []ChannelTypeMapping
+
+	// NamespaceRequired returns true if a namespace must be chosen explicitly
+	// instead of falling back to "default"
+	NamespaceRequired() bool
+
+	// AllowedNamespaces returns the patterns of the namespaces kn may operate in.
+	// All namespaces are allowed if it's empty.
+	AllowedNamespaces() []string
+
+	// DeniedNamespaces returns the patterns of the namespaces kn must not operate in
+	DeniedNamespaces() []string
+
+	// Policies returns the rules services must follow before they are submitted
+	Policies() Policies
+
+	// Language returns the language of the messages, like "de". It's empty
+	// if neither the config file nor the environment select one.
+	Language() string
+
+	// Retry returns the configured retry policy for updates which conflict
+	// with concurrent changes. Unset values fall back to the defaults.
+	Retry() Retry
+
+	// ServerSideApply returns true if services should be updated with
+	// server-side apply instead of a full update
+	ServerSideApply() bool
+
+	// NotificationSink returns the URL to which CloudEvents about completed
+	// create, update and delete operations are sent. It's empty if no
+	// notifications should be sent.
+	NotificationSink() string
 }
 
 // SinkMappings is the struct of sink prefix config in kn config
@@ -68,12 +101,71 @@ type ChannelTypeMapping struct {
 	Version string
 }
 
+// Policies is the struct of the guardrail policies for services in kn config
+type Policies struct {
+
+	// Rules are the rules every service must follow
+	Rules []PolicyRule
+
+	// ConfigMap is a cluster ConfigMap given as "namespace/name" with additional
+	// rules in its data key "rules"
+	ConfigMap string
+
+	// AllowSkip allows to bypass the rules with --policy-skip
+	AllowSkip bool `mapstructure:"allow-skip"`
+}
+
+// PolicyRule is a single guardrail for the fields of a service
+type PolicyRule struct {
+
+	// Name identifies the rule in violation messages (like "corp-registry")
+	Name string
+
+	// Path is a JSONPath selecting the checked fields of the service
+	// (like "{.spec.template.spec.containers[*].image}")
+	Path string
+
+	// Required makes the rule fail if the path selects no value
+	Required bool
+
+	// Pattern is a regular expression every selected value has to match
+	Pattern string
+
+	// Message explains the rule to the user when it's violated
+	Message string
+}
+
+// Retry is the struct of the retry policy for conflicting updates in kn config.
+// A nil field keeps the default value.
+type Retry struct {
+
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries *int `mapstructure:"max-retries"`
+
+	// Backoff is the delay before the first retry (like "200ms")
+	Backoff *time.Duration
+
+	// Factor multiplies the delay for every further retry
+	Factor *float64
+
+	// Jitter adds up to this fraction of the delay to every retry
+	Jitter *float64
+}
+
 // config Keys for looking up in viper
 const (
 	keyPluginsDirectory    = "plugins.directory"
 	keyPluginsLookupInPath = "plugins.path-lookup"
 	keySinkMappings        = "eventing.sink-mappings"
 	keyChannelTypeMappings = "eventing.channel-type-mappings"
+	keyNamespaceRequired   = "namespace.required"
+	keyNamespaceAllowed    = "namespace.allowed"
+	keyNamespaceDenied     = "namespace.denied"
+	keyPolicies            = "policies"
+	keyLanguage            = "output.language"
+	keyRetry               = "retry"
+	keyServerSideApply     = "update.server-side-apply"
+	keyNotificationSink    = "notifications.sink"
 )
 
 // legacy config keys, deprecated
@@ -89,3 +181,11 @@ const (
 	flagPluginsDir          = "plugins-dir"
 	flagPluginsLookupInPath = "lookup-plugins"
 )
+
+// Environment variables for the bootstrap options, used when neither
+// the flags nor the config file set them
+const (
+	envPluginsDir          = "KN_PLUGINS_DIR"
+	envPluginsLookupInPath = "KN_LOOKUP_PLUGINS"
+	envLanguage            = "KN_LANG"
+)
@@ -0,0 +1,219 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"knative.dev/client/pkg/util"
+)
+
+// containerFlags are the flags which configure a single container. When given after
+// --container NAME they apply to the container NAME instead of the main container.
+var containerFlags = []string{
+	"image", "env", "env-b64", "env-value-from", "env-from", "mount", "cmd", "arg",
+	"limit", "request", "port", "user",
+	"probe-readiness", "probe-readiness-opts", "probe-liveness", "probe-liveness-opts",
+}
+
+// ContainerArgs are the container flags given for a single container
+type ContainerArgs struct {
+	// Name of the container, empty for the main container
+	Name string
+	// Args are the container flags given for the container, as '--name=value'
+	Args []string
+}
+
+// containerFlagValue records the values of a container flag for the currently selected container.
+// Only values for the main container are set to the flag itself.
+type containerFlagValue struct {
+	pflag.Value
+	name  string
+	flags *PodSpecFlags
+}
+
+// String returns the value of the flag for the main container. Empty slices are
+// returned as empty string, so that the help doesn't show them as default value.
+func (v *containerFlagValue) String() string {
+	value := v.Value.String()
+	if value == "[]" {
+		return ""
+	}
+	return value
+}
+
+func (v *containerFlagValue) Set(value string) error {
+	current := &v.flags.Containers[len(v.flags.Containers)-1]
+	current.Args = append(current.Args, "--"+v.name+"="+value)
+	if current.Name != "" {
+		return nil
+	}
+	return v.Value.Set(value)
+}
+
+// containerSelector is the value of --container, which selects the container for the following flags
+type containerSelector struct {
+	flags *PodSpecFlags
+}
+
+func (s *containerSelector) Set(name string) error {
+	if name == "" || name == "-" {
+		return fmt.Errorf("container name must not be empty")
+	}
+	s.flags.Containers = append(s.flags.Containers, ContainerArgs{Name: name})
+	return nil
+}
+
+func (s *containerSelector) Type() string {
+	return "string"
+}
+
+func (s *containerSelector) String() string {
+	return ""
+}
+
+// addContainerFlags adds --container and makes the container flags record to which container they apply
+func (p *PodSpecFlags) addContainerFlags(flagset *pflag.FlagSet) {
+	p.Containers = []ContainerArgs{{}}
+	for _, name := range containerFlags {
+		flag := flagset.Lookup(name)
+		flag.Value = &containerFlagValue{Value: flag.Value, name: name, flags: p}
+	}
+	flagset.Var(&containerSelector{flags: p}, "container",
+		"Name of a sidecar container which the container flags following this option apply to, "+
+			"like --image, --env, --port or --mount. The container is added if it doesn't exist yet. "+
+			"Example: --image main-image --container sidecar --image sidecar-image. "+
+			"To remove a sidecar container, append \"-\" to its name, e.g. --container sidecar-.")
+}
+
+// Changed returns true if the given flag has been provided for the main container.
+// Container flags which only follow --container don't count.
+func (p *PodSpecFlags) Changed(flags *pflag.FlagSet, name string) bool {
+	if !flags.Changed(name) {
+		return false
+	}
+	if len(p.Containers) == 0 || !sets.NewString(containerFlags...).Has(name) {
+		return true
+	}
+	for _, arg := range p.Containers[0].Args {
+		if strings.HasPrefix(arg, "--"+name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveSidecars applies the flags given after --container to the named containers
+func (p *PodSpecFlags) resolveSidecars(podSpec *corev1.PodSpec, envValues util.ValueResolver) error {
+	if len(p.Containers) <= 1 {
+		return nil
+	}
+	if len(podSpec.Containers) == 0 {
+		// The main container stays first
+		podSpec.Containers = []corev1.Container{{}}
+	}
+	for _, sidecar := range p.Containers[1:] {
+		if strings.HasSuffix(sidecar.Name, "-") {
+			if len(sidecar.Args) > 0 {
+				return fmt.Errorf("no container flags allowed after removing container '%s'", strings.TrimSuffix(sidecar.Name, "-"))
+			}
+			err := removeSidecar(podSpec, strings.TrimSuffix(sidecar.Name, "-"))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		err := p.resolveSidecar(podSpec, sidecar, envValues)
+		if err != nil {
+			return fmt.Errorf("invalid flags for container '%s': %w", sidecar.Name, err)
+		}
+	}
+	return nil
+}
+
+func (p *PodSpecFlags) resolveSidecar(podSpec *corev1.PodSpec, sidecar ContainerArgs, envValues util.ValueResolver) error {
+	idx := containerIndex(podSpec, sidecar.Name)
+	if idx < 0 {
+		podSpec.Containers = append(podSpec.Containers, corev1.Container{Name: sidecar.Name})
+		idx = len(podSpec.Containers) - 1
+	}
+
+	sidecarFlags := &PodSpecFlags{}
+	flagset := pflag.NewFlagSet(sidecar.Name, pflag.ContinueOnError)
+	sidecarFlags.AddFlags(flagset)
+	err := flagset.Parse(sidecar.Args)
+	if err != nil {
+		return err
+	}
+
+	// The pod spec helpers update the first container, so swap the sidecar in
+	podSpec.Containers[0], podSpec.Containers[idx] = podSpec.Containers[idx], podSpec.Containers[0]
+	defer func() {
+		podSpec.Containers[0], podSpec.Containers[idx] = podSpec.Containers[idx], podSpec.Containers[0]
+	}()
+	return sidecarFlags.resolveContainer(podSpec, flagset, envValues)
+}
+
+func removeSidecar(podSpec *corev1.PodSpec, name string) error {
+	idx := containerIndex(podSpec, name)
+	if idx < 0 {
+		return fmt.Errorf("no container '%s' to remove", name)
+	}
+	if idx == 0 {
+		return fmt.Errorf("the main container '%s' can't be removed", name)
+	}
+	podSpec.Containers = append(podSpec.Containers[:idx], podSpec.Containers[idx+1:]...)
+	return nil
+}
+
+func containerIndex(podSpec *corev1.PodSpec, name string) int {
+	for i, container := range podSpec.Containers {
+		if container.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// validateServingPort checks that exactly one container of a multi-container
+// pod declares a port, which is the one receiving the requests
+func validateServingPort(podSpec *corev1.PodSpec) error {
+	if len(podSpec.Containers) <= 1 {
+		return nil
+	}
+	var withPort []string
+	for i, container := range podSpec.Containers {
+		if len(container.Ports) > 0 {
+			name := container.Name
+			if i == 0 && name == "" {
+				name = "main container"
+			}
+			withPort = append(withPort, name)
+		}
+	}
+	switch len(withPort) {
+	case 0:
+		return fmt.Errorf("exactly one container must declare the serving port with --port when using multiple containers, but none does")
+	case 1:
+		return nil
+	}
+	return fmt.Errorf("exactly one container must declare the serving port with --port when using multiple containers, "+
+		"but %s do", strings.Join(withPort, ", "))
+}
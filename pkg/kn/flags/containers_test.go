@@ -0,0 +1,103 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"knative.dev/client/pkg/util"
+)
+
+func resolveWithArgs(t *testing.T, podSpec *corev1.PodSpec, args ...string) error {
+	flags := &PodSpecFlags{}
+	flagset := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.AddFlags(flagset)
+	assert.NilError(t, flagset.Parse(args))
+	return flags.ResolvePodSpec(podSpec, flagset, util.NewFileValueResolver(nil))
+}
+
+func TestResolveSidecars(t *testing.T) {
+	podSpec := &corev1.PodSpec{Containers: []corev1.Container{{}}}
+	err := resolveWithArgs(t, podSpec,
+		"--image", "main", "--env", "A=main",
+		"--container", "sidecar", "--image", "sidecar-image", "--env", "A=sidecar", "--port", "8080",
+		"--container", "logger", "--image", "logger-image", "--mount", "/logs=cm:logs")
+	assert.NilError(t, err)
+
+	assert.Equal(t, len(podSpec.Containers), 3)
+	main, sidecar, logger := podSpec.Containers[0], podSpec.Containers[1], podSpec.Containers[2]
+	assert.Equal(t, main.Name, "")
+	assert.Equal(t, main.Image, "main")
+	assert.DeepEqual(t, main.Env, []corev1.EnvVar{{Name: "A", Value: "main"}})
+	assert.Assert(t, main.Ports == nil)
+
+	assert.Equal(t, sidecar.Name, "sidecar")
+	assert.Equal(t, sidecar.Image, "sidecar-image")
+	assert.DeepEqual(t, sidecar.Env, []corev1.EnvVar{{Name: "A", Value: "sidecar"}})
+	assert.DeepEqual(t, sidecar.Ports, []corev1.ContainerPort{{ContainerPort: 8080}})
+
+	assert.Equal(t, logger.Name, "logger")
+	assert.Equal(t, logger.Image, "logger-image")
+	assert.Equal(t, len(logger.VolumeMounts), 1)
+	assert.Equal(t, len(podSpec.Volumes), 1)
+
+	// Update an existing sidecar and remove another
+	err = resolveWithArgs(t, podSpec, "--container", "sidecar", "--image", "sidecar-image:v2", "--container", "logger-")
+	assert.NilError(t, err)
+	assert.Equal(t, len(podSpec.Containers), 2)
+	assert.Equal(t, podSpec.Containers[0].Image, "main")
+	assert.Equal(t, podSpec.Containers[1].Image, "sidecar-image:v2")
+	assert.DeepEqual(t, podSpec.Containers[1].Env, []corev1.EnvVar{{Name: "A", Value: "sidecar"}})
+}
+
+func TestResolveSidecarsServingPort(t *testing.T) {
+	podSpec := &corev1.PodSpec{Containers: []corev1.Container{{}}}
+	err := resolveWithArgs(t, podSpec, "--image", "main", "--container", "sidecar", "--image", "sidecar-image")
+	assert.ErrorContains(t, err, "but none does")
+
+	podSpec = &corev1.PodSpec{Containers: []corev1.Container{{}}}
+	err = resolveWithArgs(t, podSpec, "--image", "main", "--port", "8080",
+		"--container", "sidecar", "--image", "sidecar-image", "--port", "9090")
+	assert.ErrorContains(t, err, "but main container, sidecar do")
+}
+
+func TestResolveSidecarsErrors(t *testing.T) {
+	podSpec := &corev1.PodSpec{Containers: []corev1.Container{{Image: "main"}}}
+	err := resolveWithArgs(t, podSpec, "--container", "sidecar-")
+	assert.ErrorContains(t, err, "no container 'sidecar' to remove")
+
+	err = resolveWithArgs(t, podSpec, "--container", "sidecar-", "--image", "foo")
+	assert.ErrorContains(t, err, "no container flags allowed after removing container 'sidecar'")
+
+	err = resolveWithArgs(t, podSpec, "--container", "sidecar", "--mount", "invalid")
+	assert.ErrorContains(t, err, "invalid flags for container 'sidecar'")
+}
+
+func TestPodSpecFlagsChanged(t *testing.T) {
+	flags := &PodSpecFlags{}
+	flagset := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.AddFlags(flagset)
+	assert.NilError(t, flagset.Parse([]string{"--env", "A=B", "--container", "sidecar", "--image", "sidecar-image", "--service-account", "sa"}))
+
+	assert.Assert(t, flags.Changed(flagset, "env"))
+	assert.Assert(t, !flags.Changed(flagset, "image"))
+	assert.Assert(t, flags.Changed(flagset, "service-account"))
+	assert.Assert(t, !flags.Changed(flagset, "port"))
+	assert.Equal(t, flags.Image.String(), "")
+}
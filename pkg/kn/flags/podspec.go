@@ -15,6 +15,7 @@
 package flags
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"strings"
@@ -28,11 +29,13 @@ import (
 // PodSpecFlags to hold the container resource requirements values
 type PodSpecFlags struct {
 	// Direct field manipulation
-	Image   uniqueStringArg
-	Env     []string
-	EnvFrom []string
-	Mount   []string
-	Volume  []string
+	Image        uniqueStringArg
+	Env          []string
+	EnvB64       []string
+	EnvFrom      []string
+	EnvValueFrom []string
+	Mount        []string
+	Volume       []string
 
 	Command string
 	Arg     []string
@@ -42,6 +45,15 @@ type PodSpecFlags struct {
 	ServiceAccountName string
 	ImagePullSecrets   string
 	User               int64
+
+	ReadinessProbe     string
+	ReadinessProbeOpts string
+	LivenessProbe      string
+	LivenessProbeOpts  string
+
+	// Containers are the container flags in the order given, grouped by --container.
+	// The first entry is for the main container.
+	Containers []ContainerArgs
 }
 
 type ResourceFlags struct {
@@ -79,9 +91,17 @@ func (p *PodSpecFlags) AddFlags(flagset *pflag.FlagSet) []string {
 	flagset.StringArrayVarP(&p.Env, "env", "e", []string{},
 		"Environment variable to set. NAME=value; you may provide this flag "+
 			"any number of times to set multiple environment variables. "+
+			"Use NAME=@path for reading the value from a file, NAME=@- for reading it from stdin and NAME=@@value for a value starting with '@'. "+
+			"Values can contain the templates {{.Service}} (or {{.ServiceName}}), {{.Namespace}}, {{.Generation}} and {{.RevisionName}}. "+
 			"To unset, specify the environment variable name followed by a \"-\" (e.g., NAME-).")
 	flagNames = append(flagNames, "env")
 
+	flagset.StringArrayVarP(&p.EnvB64, "env-b64", "", []string{},
+		"Environment variable to set with a base64 encoded value, which is decoded by kn, e.g. for binary values. NAME=value; "+
+			"you may provide this flag any number of times to set multiple environment variables. "+
+			"Use --env for unsetting environment variables.")
+	flagNames = append(flagNames, "env-b64")
+
 	flagset.StringArrayVarP(&p.EnvFrom, "env-from", "", []string{},
 		"Add environment variables from a ConfigMap (prefix cm: or config-map:) or a Secret (prefix secret:). "+
 			"Example: --env-from cm:myconfigmap or --env-from secret:mysecret. "+
@@ -89,10 +109,18 @@ func (p *PodSpecFlags) AddFlags(flagset *pflag.FlagSet) []string {
 			"To unset a ConfigMap/Secret reference, append \"-\" to the name, e.g. --env-from cm:myconfigmap-.")
 	flagNames = append(flagNames, "env-from")
 
+	flagset.StringArrayVarP(&p.EnvValueFrom, "env-value-from", "", []string{},
+		"Environment variable to set from a key of a ConfigMap (prefix cm: or config-map:) or a Secret (prefix secret: or sc:). "+
+			"Example: --env-value-from NAME=cm:myconfigmap:key or --env-value-from NAME=secret:mysecret:key. "+
+			"You can use this flag multiple times. "+
+			"To unset, specify the environment variable name followed by a \"-\" (e.g., NAME-).")
+	flagNames = append(flagNames, "env-value-from")
+
 	flagset.StringArrayVarP(&p.Mount, "mount", "", []string{},
 		"Mount a ConfigMap (prefix cm: or config-map:), a Secret (prefix secret: or sc:), or an existing Volume (without any prefix) on the specified directory. "+
 			"Example: --mount /mydir=cm:myconfigmap, --mount /mydir=secret:mysecret, or --mount /mydir=myvolume. "+
 			"When a configmap or a secret is specified, a corresponding volume is automatically generated. "+
+			"A path within the volume can be mounted by appending it to the name, e.g. --mount /mydir/app.conf=cm:myconfigmap/app.conf. "+
 			"You can use this flag multiple times. "+
 			"For unmounting a directory, append \"-\", e.g. --mount /mydir-, which also removes any auto-generated volume.")
 	flagNames = append(flagNames, "mount")
@@ -119,6 +147,7 @@ func (p *PodSpecFlags) AddFlags(flagset *pflag.FlagSet) []string {
 		"limit",
 		nil,
 		"The resource requirement limits for this Service. For example, 'cpu=100m,memory=256Mi'. "+
+			"Extended resources like GPUs are given by their full name, e.g. 'nvidia.com/gpu=1', and are requested with the same amount. "+
 			"You can use this flag multiple times. "+
 			"To unset a resource limit, append \"-\" to the resource name, e.g. '--limit memory-'.")
 	flagNames = append(flagNames, "limit")
@@ -147,27 +176,84 @@ func (p *PodSpecFlags) AddFlags(flagset *pflag.FlagSet) []string {
 	flagNames = append(flagNames, "pull-secret")
 	flagset.Int64VarP(&p.User, "user", "", 0, "The user ID to run the container (e.g., 1001).")
 	flagNames = append(flagNames, "user")
+
+	flagset.StringVar(&p.ReadinessProbe, "probe-readiness", "",
+		"Readiness probe of the container, one of "+ProbeFormat+". "+
+			"Example: --probe-readiness http:/healthz:8080. An empty argument (\"\") clears the probe.")
+	flagNames = append(flagNames, "probe-readiness")
+
+	flagset.StringVar(&p.ReadinessProbeOpts, "probe-readiness-opts", "",
+		"Timing options of the readiness probe as comma separated key=value pairs. Supported keys: "+
+			strings.Join(probeOptionNames(), ", ")+". Example: --probe-readiness-opts initialDelaySeconds=5,periodSeconds=10")
+	flagNames = append(flagNames, "probe-readiness-opts")
+
+	flagset.StringVar(&p.LivenessProbe, "probe-liveness", "",
+		"Liveness probe of the container, one of "+ProbeFormat+". "+
+			"Example: --probe-liveness tcp:8080. An empty argument (\"\") clears the probe.")
+	flagNames = append(flagNames, "probe-liveness")
+
+	flagset.StringVar(&p.LivenessProbeOpts, "probe-liveness-opts", "",
+		"Timing options of the liveness probe as comma separated key=value pairs. Supported keys: "+
+			strings.Join(probeOptionNames(), ", ")+". Example: --probe-liveness-opts failureThreshold=3")
+	flagNames = append(flagNames, "probe-liveness-opts")
+
+	p.addContainerFlags(flagset)
+	flagNames = append(flagNames, "container")
 	return flagNames
 }
 
-// ResolvePodSpec will create corev1.PodSpec based on the flag inputs
-func (p *PodSpecFlags) ResolvePodSpec(podSpec *corev1.PodSpec, flags *pflag.FlagSet) error {
+// ResolvePodSpec will create corev1.PodSpec based on the flag inputs.
+// References in the values of --env, like '@path' for files, are replaced with the given resolver.
+func (p *PodSpecFlags) ResolvePodSpec(podSpec *corev1.PodSpec, flags *pflag.FlagSet, envValues util.ValueResolver) error {
+	err := p.resolveContainer(podSpec, flags, envValues)
+	if err != nil {
+		return err
+	}
+	err = p.resolveSidecars(podSpec, envValues)
+	if err != nil {
+		return err
+	}
+	return validateServingPort(podSpec)
+}
+
+// resolveContainer applies the flags to the pod spec and its main container
+func (p *PodSpecFlags) resolveContainer(podSpec *corev1.PodSpec, flags *pflag.FlagSet, envValues util.ValueResolver) error {
 	var err error
 
-	if flags.Changed("env") {
+	if p.Changed(flags, "env") || p.Changed(flags, "env-b64") {
 		envMap, err := util.MapFromArrayAllowingSingles(p.Env, "=")
 		if err != nil {
 			return fmt.Errorf("Invalid --env: %w", err)
 		}
 
 		envToRemove := util.ParseMinusSuffix(envMap)
+		err = envValues.Resolve("env", envMap)
+		if err != nil {
+			return err
+		}
+		envMap, err = mergeBase64EnvValues(envMap, p.EnvB64)
+		if err != nil {
+			return err
+		}
 		err = UpdateEnvVars(podSpec, envMap, envToRemove)
 		if err != nil {
 			return err
 		}
 	}
 
-	if flags.Changed("env-from") {
+	if p.Changed(flags, "env-value-from") {
+		envValueFromMap, err := util.MapFromArrayAllowingSingles(p.EnvValueFrom, "=")
+		if err != nil {
+			return fmt.Errorf("Invalid --env-value-from: %w", err)
+		}
+		envValueFromToRemove := util.ParseMinusSuffix(envValueFromMap)
+		err = UpdateEnvValueFrom(podSpec, envValueFromMap, envValueFromToRemove)
+		if err != nil {
+			return fmt.Errorf("Invalid --env-value-from: %w", err)
+		}
+	}
+
+	if p.Changed(flags, "env-from") {
 		envFromSourceToUpdate := []string{}
 		envFromSourceToRemove := []string{}
 		for _, name := range p.EnvFrom {
@@ -186,7 +272,7 @@ func (p *PodSpecFlags) ResolvePodSpec(podSpec *corev1.PodSpec, flags *pflag.Flag
 		}
 	}
 
-	if flags.Changed("mount") || flags.Changed("volume") {
+	if p.Changed(flags, "mount") || p.Changed(flags, "volume") {
 		mountsToUpdate, mountsToRemove, err := util.OrderedMapAndRemovalListFromArray(p.Mount, "=")
 		if err != nil {
 			return fmt.Errorf("Invalid --mount: %w", err)
@@ -203,7 +289,7 @@ func (p *PodSpecFlags) ResolvePodSpec(podSpec *corev1.PodSpec, flags *pflag.Flag
 		}
 	}
 
-	if flags.Changed("image") {
+	if p.Changed(flags, "image") {
 		err = UpdateImage(podSpec, p.Image.String())
 		if err != nil {
 			return err
@@ -220,41 +306,91 @@ func (p *PodSpecFlags) ResolvePodSpec(podSpec *corev1.PodSpec, flags *pflag.Flag
 		return err
 	}
 
-	if flags.Changed("cmd") {
+	if p.Changed(flags, "cmd") {
 		err = UpdateContainerCommand(podSpec, p.Command)
 		if err != nil {
 			return err
 		}
 	}
 
-	if flags.Changed("arg") {
+	if p.Changed(flags, "arg") {
 		err = UpdateContainerArg(podSpec, p.Arg)
 		if err != nil {
 			return err
 		}
 	}
 
-	if flags.Changed("port") {
+	if p.Changed(flags, "port") {
 		err = UpdateContainerPort(podSpec, p.Port)
 		if err != nil {
 			return err
 		}
 	}
 
-	if flags.Changed("service-account") {
+	if p.Changed(flags, "service-account") {
 		UpdateServiceAccountName(podSpec, p.ServiceAccountName)
 	}
 
-	if flags.Changed("pull-secret") {
+	if p.Changed(flags, "pull-secret") {
 		UpdateImagePullSecrets(podSpec, p.ImagePullSecrets)
 	}
 
-	if flags.Changed("user") {
+	if p.Changed(flags, "user") {
 		err = UpdateUser(podSpec, p.User)
 		if err != nil {
 			return err
 		}
 	}
 
+	if p.Changed(flags, "probe-readiness") {
+		err = UpdateReadinessProbe(podSpec, p.ReadinessProbe)
+		if err != nil {
+			return fmt.Errorf("Invalid --probe-readiness: %w", err)
+		}
+	}
+
+	if p.Changed(flags, "probe-readiness-opts") {
+		err = UpdateReadinessProbeOpts(podSpec, p.ReadinessProbeOpts)
+		if err != nil {
+			return fmt.Errorf("Invalid --probe-readiness-opts: %w", err)
+		}
+	}
+
+	if p.Changed(flags, "probe-liveness") {
+		err = UpdateLivenessProbe(podSpec, p.LivenessProbe)
+		if err != nil {
+			return fmt.Errorf("Invalid --probe-liveness: %w", err)
+		}
+	}
+
+	if p.Changed(flags, "probe-liveness-opts") {
+		err = UpdateLivenessProbeOpts(podSpec, p.LivenessProbeOpts)
+		if err != nil {
+			return fmt.Errorf("Invalid --probe-liveness-opts: %w", err)
+		}
+	}
+
 	return nil
 }
+
+// mergeBase64EnvValues adds the decoded values of --env-b64 to the values of --env
+func mergeBase64EnvValues(envMap map[string]string, envB64 []string) (map[string]string, error) {
+	b64Map, err := util.MapFromArray(envB64, "=")
+	if err != nil {
+		return nil, fmt.Errorf("Invalid --env-b64: %w", err)
+	}
+	if envMap == nil {
+		envMap = map[string]string{}
+	}
+	for name, value := range b64Map {
+		if _, ok := envMap[name]; ok {
+			return nil, fmt.Errorf("environment variable %s is given with both --env and --env-b64", name)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid --env-b64 %s: value is not base64 encoded: %w", name, err)
+		}
+		envMap[name] = string(decoded)
+	}
+	return envMap, nil
+}
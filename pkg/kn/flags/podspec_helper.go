@@ -70,6 +70,30 @@ func UpdateEnvVars(spec *corev1.PodSpec, toUpdate map[string]string, toRemove []
 	return nil
 }
 
+// UpdateEnvValueFrom sets the env vars in the given map to the value of a key of a ConfigMap or a Secret,
+// given like "cm:myconfigmap:key" or "secret:mysecret:key", and removes the env vars to remove.
+// The env vars are sorted by name afterwards like with UpdateEnvVars.
+func UpdateEnvValueFrom(spec *corev1.PodSpec, toUpdate map[string]string, toRemove []string) error {
+	container, err := containerOfPodSpec(spec)
+	if err != nil {
+		return err
+	}
+	updated := container.Env
+	for name, ref := range toUpdate {
+		source, err := newEnvVarSourceWithSpecString(ref)
+		if err != nil {
+			return err
+		}
+		updated = updateEnvVarSource(updated, name, source)
+	}
+	updated = removeEnvVars(updated, toRemove)
+	sort.SliceStable(updated, func(i, j int) bool {
+		return updated[i].Name < updated[j].Name
+	})
+	container.Env = updated
+	return nil
+}
+
 // UpdateEnvFrom updates envFrom
 func UpdateEnvFrom(spec *corev1.PodSpec, toUpdate []string, toRemove []string) error {
 	container, err := containerOfPodSpec(spec)
@@ -221,7 +245,11 @@ func UpdateResources(spec *corev1.PodSpec, resources corev1.ResourceRequirements
 		delete(container.Resources.Limits, corev1.ResourceName(limToRemove))
 	}
 
-	return nil
+	if len(resources.Requests) == 0 && len(resources.Limits) == 0 {
+		// Nothing added, so don't complain about the existing resources
+		return nil
+	}
+	return validateResourceRequirements(container.Resources)
 }
 
 // UpdateServiceAccountName updates the service account name used for the corresponding knative service
@@ -249,6 +277,7 @@ func updateEnvVarsFromMap(env []corev1.EnvVar, toUpdate map[string]string) []cor
 		envVar := &env[i]
 		if val, ok := toUpdate[envVar.Name]; ok {
 			envVar.Value = val
+			envVar.ValueFrom = nil
 			set.Insert(envVar.Name)
 		}
 	}
@@ -260,6 +289,17 @@ func updateEnvVarsFromMap(env []corev1.EnvVar, toUpdate map[string]string) []cor
 	return env
 }
 
+func updateEnvVarSource(env []corev1.EnvVar, name string, source *corev1.EnvVarSource) []corev1.EnvVar {
+	for i := range env {
+		if env[i].Name == name {
+			env[i].Value = ""
+			env[i].ValueFrom = source
+			return env
+		}
+	}
+	return append(env, corev1.EnvVar{Name: name, ValueFrom: source})
+}
+
 func removeEnvVars(env []corev1.EnvVar, toRemove []string) []corev1.EnvVar {
 	for _, name := range toRemove {
 		for i, envVar := range env {
@@ -337,26 +377,35 @@ func updateVolumeMountsFromMap(volumeMounts []corev1.VolumeMount, toUpdate *util
 
 	for i := range volumeMounts {
 		volumeMount := &volumeMounts[i]
-		name, present := toUpdate.GetString(volumeMount.MountPath)
+		value, present := toUpdate.Get(volumeMount.MountPath)
 
 		if present {
-			if !existsVolumeNameInVolumes(name, volumes) {
-				return nil, fmt.Errorf("There is no volume matched with %q", name)
+			info := value.(*mountInfo)
+			err := checkMountableVolume(info.volumeName, volumes)
+			if err != nil {
+				return nil, err
 			}
 
 			volumeMount.ReadOnly = true
-			volumeMount.Name = name
+			volumeMount.Name = info.volumeName
+			volumeMount.SubPath = info.subPath
 			set[volumeMount.MountPath] = true
 		}
 	}
 
 	it := toUpdate.Iterator()
-	for mountPath, name, ok := it.NextString(); ok; mountPath, name, ok = it.NextString() {
+	for mountPath, value, ok := it.Next(); ok; mountPath, value, ok = it.Next() {
 		if !set[mountPath] {
+			info := value.(*mountInfo)
+			err := checkMountableVolume(info.volumeName, volumes)
+			if err != nil {
+				return nil, err
+			}
 			volumeMounts = append(volumeMounts, corev1.VolumeMount{
-				Name:      name,
+				Name:      info.volumeName,
 				ReadOnly:  true,
 				MountPath: mountPath,
+				SubPath:   info.subPath,
 			})
 		}
 	}
@@ -431,6 +480,22 @@ func removeVolumes(volumes []corev1.Volume, toRemove []string, volumeMounts []co
 
 // =======================================================================================
 
+// mountInfo is the volume and the optional path within the volume to mount
+type mountInfo struct {
+	volumeName string
+	subPath    string
+}
+
+// newMountInfo splits a volume name followed by a slash separated sub path, like "myvolume/sub/path"
+func newMountInfo(volumeName string) *mountInfo {
+	slices := strings.SplitN(volumeName, "/", 2)
+	info := &mountInfo{volumeName: slices[0]}
+	if len(slices) == 2 {
+		info.subPath = slices[1]
+	}
+	return info
+}
+
 type volumeSourceInfo struct {
 	volumeSourceType VolumeSourceType
 	volumeSourceName string
@@ -466,6 +531,33 @@ func newVolumeSourceInfoWithSpecString(spec string) (*volumeSourceInfo, error) {
 	}, nil
 }
 
+// newEnvVarSourceWithSpecString parses a reference to a key of a ConfigMap or a Secret like "cm:myconfigmap:key"
+func newEnvVarSourceWithSpecString(spec string) (*corev1.EnvVarSource, error) {
+	slices := strings.SplitN(spec, ":", 3)
+	if len(slices) != 3 || strings.TrimSpace(slices[2]) == "" {
+		return nil, fmt.Errorf("argument requires a value like secret:name:key or config-map:name:key; got %q", spec)
+	}
+	info, err := newVolumeSourceInfoWithSpecString(slices[0] + ":" + slices[1])
+	if err != nil {
+		return nil, err
+	}
+	key := strings.TrimSpace(slices[2])
+	switch info.volumeSourceType {
+	case ConfigMapVolumeSourceType:
+		return &corev1.EnvVarSource{
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: info.volumeSourceName},
+				Key:                  key,
+			}}, nil
+	default:
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: info.volumeSourceName},
+				Key:                  key,
+			}}, nil
+	}
+}
+
 func (vol *volumeSourceInfo) getCanonicalName() string {
 	return fmt.Sprintf("%s:%s", vol.volumeSourceType, vol.volumeSourceName)
 }
@@ -502,13 +594,18 @@ func (vol *volumeSourceInfo) createEnvFromSource() *corev1.EnvFromSource {
 
 // =======================================================================================
 
-func existsVolumeNameInVolumes(volumeName string, volumes []corev1.Volume) bool {
+// checkMountableVolume returns an error if there is no volume of the given name or if it is of a
+// type which Knative doesn't support, as only ConfigMap, Secret and projected volumes can be mounted
+func checkMountableVolume(volumeName string, volumes []corev1.Volume) error {
 	for _, volume := range volumes {
 		if volume.Name == volumeName {
-			return true
+			if volume.ConfigMap == nil && volume.Secret == nil && volume.Projected == nil {
+				return fmt.Errorf("The volume %q cannot be mounted, Knative supports only ConfigMap, Secret and projected volumes", volumeName)
+			}
+			return nil
 		}
 	}
-	return false
+	return fmt.Errorf("There is no volume matched with %q", volumeName)
 }
 
 func existsVolumeNameInVolumeMounts(volumeName string, volumeMounts []corev1.VolumeMount) bool {
@@ -525,31 +622,35 @@ func existsVolumeNameInVolumeMounts(volumeName string, volumeMounts []corev1.Vol
 func reviseVolumeInfoAndMountsToUpdate(volumes []corev1.Volume, mountsToUpdate *util.OrderedMap,
 	volumesToUpdate *util.OrderedMap) (*util.OrderedMap, *util.OrderedMap, error) {
 	volumeSourceInfoByName := util.NewOrderedMap() //make(map[string]*volumeSourceInfo)
-	mountsToUpdateRevised := util.NewOrderedMap()  //make(map[string]string)
+	mountsToUpdateRevised := util.NewOrderedMap()  //make(map[string]*mountInfo)
 
 	it := mountsToUpdate.Iterator()
 	for path, value, ok := it.NextString(); ok; path, value, ok = it.NextString() {
+		if !strings.HasPrefix(path, "/") {
+			return nil, nil, fmt.Errorf("the mount path %q must be an absolute path", path)
+		}
 		// slices[0] -> config-map, cm, secret, sc, volume, or vo
-		// slices[1] -> secret, config-map, or volume name
+		// slices[1] -> secret, config-map, or volume name, optionally followed by /sub/path
 		slices := strings.SplitN(value, ":", 2)
 		if len(slices) == 1 {
-			mountsToUpdateRevised.Set(path, slices[0])
+			mountsToUpdateRevised.Set(path, newMountInfo(slices[0]))
 		} else {
+			info := newMountInfo(slices[1])
 			switch volumeType := slices[0]; volumeType {
 			case "config-map", "cm":
 				generatedName := util.GenerateVolumeName(path)
 				volumeSourceInfoByName.Set(generatedName, &volumeSourceInfo{
 					volumeSourceType: ConfigMapVolumeSourceType,
-					volumeSourceName: slices[1],
+					volumeSourceName: info.volumeName,
 				})
-				mountsToUpdateRevised.Set(path, generatedName)
+				mountsToUpdateRevised.Set(path, &mountInfo{volumeName: generatedName, subPath: info.subPath})
 			case "secret", "sc":
 				generatedName := util.GenerateVolumeName(path)
 				volumeSourceInfoByName.Set(generatedName, &volumeSourceInfo{
 					volumeSourceType: SecretVolumeSourceType,
-					volumeSourceName: slices[1],
+					volumeSourceName: info.volumeName,
 				})
-				mountsToUpdateRevised.Set(path, generatedName)
+				mountsToUpdateRevised.Set(path, &mountInfo{volumeName: generatedName, subPath: info.subPath})
 
 			default:
 				return nil, nil, fmt.Errorf("unsupported volume type \"%q\"; supported volume types are \"config-map or cm\", \"secret or sc\", and \"volume or vo\"", slices[0])
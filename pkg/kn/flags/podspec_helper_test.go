@@ -68,6 +68,47 @@ func TestUpdateEnvFrom(t *testing.T) {
 	assert.Equal(t, container.EnvFrom[1].SecretRef.Name, "secret-new-name-1")
 }
 
+func TestUpdateEnvValueFrom(t *testing.T) {
+	spec, container := getPodSpec()
+	container.Env = []corev1.EnvVar{
+		{Name: "a", Value: "foo"},
+		{Name: "b", Value: "bar"},
+		{Name: "c", Value: "baz"},
+	}
+	err := UpdateEnvValueFrom(spec,
+		map[string]string{"b": "secret:mysecret:password", "d": "cm:myconfigmap:url"},
+		[]string{"c"})
+	assert.NilError(t, err)
+
+	expected := []corev1.EnvVar{
+		{Name: "a", Value: "foo"},
+		{Name: "b", ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "mysecret"},
+				Key:                  "password",
+			}}},
+		{Name: "d", ValueFrom: &corev1.EnvVarSource{
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "myconfigmap"},
+				Key:                  "url",
+			}}},
+	}
+	assert.DeepEqual(t, expected, container.Env)
+
+	// A plain value replaces the reference
+	err = UpdateEnvVars(spec, map[string]string{"b": "plain"}, []string{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, corev1.EnvVar{Name: "b", Value: "plain"}, container.Env[1])
+}
+
+func TestUpdateEnvValueFromInvalid(t *testing.T) {
+	for _, ref := range []string{"secret:mysecret", "secret:mysecret:", "vault:mysecret:key", "cm::key"} {
+		spec, _ := getPodSpec()
+		err := UpdateEnvValueFrom(spec, map[string]string{"a": ref}, []string{})
+		assert.Assert(t, err != nil, ref)
+	}
+}
+
 func TestUpdateVolumeMountsAndVolumes(t *testing.T) {
 	spec, container := getPodSpec()
 	spec.Volumes = append(spec.Volumes,
@@ -176,6 +217,61 @@ func TestUpdateVolumeMountsAndVolumes(t *testing.T) {
 	assert.Equal(t, container.VolumeMounts[5].MountPath, "/updated-secret/mount/path")
 }
 
+func TestUpdateVolumeMountsWithSubPath(t *testing.T) {
+	spec, container := getPodSpec()
+	spec.Volumes = []corev1.Volume{{
+		Name:         "existing-volume",
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "existing-secret"}},
+	}}
+
+	err := UpdateVolumeMountsAndVolumes(spec,
+		util.NewOrderedMapWithKVStrings([][]string{
+			{"/etc/app/app.conf", "cm:app-config/conf/app.conf"},
+			{"/etc/app/token", "existing-volume/token"},
+		}),
+		[]string{}, util.NewOrderedMap(), []string{})
+	assert.NilError(t, err)
+
+	generatedName := util.GenerateVolumeName("/etc/app/app.conf")
+	assert.DeepEqual(t, container.VolumeMounts, []corev1.VolumeMount{
+		{Name: generatedName, ReadOnly: true, MountPath: "/etc/app/app.conf", SubPath: "conf/app.conf"},
+		{Name: "existing-volume", ReadOnly: true, MountPath: "/etc/app/token", SubPath: "token"},
+	})
+	assert.Equal(t, spec.Volumes[1].Name, generatedName)
+	assert.Equal(t, spec.Volumes[1].ConfigMap.Name, "app-config")
+
+	// Mounting the whole volume again clears the sub path
+	err = UpdateVolumeMountsAndVolumes(spec,
+		util.NewOrderedMapWithKVStrings([][]string{{"/etc/app/token", "existing-volume"}}),
+		[]string{}, util.NewOrderedMap(), []string{})
+	assert.NilError(t, err)
+	assert.Equal(t, container.VolumeMounts[1].SubPath, "")
+}
+
+func TestUpdateVolumeMountsValidation(t *testing.T) {
+	spec, _ := getPodSpec()
+	spec.Volumes = []corev1.Volume{{
+		Name:         "scratch",
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	}}
+
+	for _, tc := range []struct {
+		mountPath string
+		volume    string
+		errorText string
+	}{
+		{"/scratch", "scratch", "Knative supports only ConfigMap, Secret and projected volumes"},
+		{"/missing", "missing", "There is no volume matched with \"missing\""},
+		{"relative/path", "cm:app-config", "must be an absolute path"},
+		{"/etc/app", "vol:app-config", "unsupported volume type"},
+	} {
+		err := UpdateVolumeMountsAndVolumes(spec,
+			util.NewOrderedMapWithKVStrings([][]string{{tc.mountPath, tc.volume}}),
+			[]string{}, util.NewOrderedMap(), []string{})
+		assert.ErrorContains(t, err, tc.errorText)
+	}
+}
+
 func TestUpdateContainerImage(t *testing.T) {
 	spec, _ := getPodSpec()
 	err := UpdateImage(spec, "gcr.io/foo/bar:baz")
@@ -22,6 +22,7 @@ import (
 	"github.com/spf13/cobra"
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"knative.dev/client/pkg/util"
 	"knative.dev/pkg/ptr"
 )
@@ -29,12 +30,15 @@ import (
 func TestPodSpecFlags(t *testing.T) {
 	args := []string{"--image", "repo/user/imageID:tag", "--env", "b=c"}
 	wantedPod := &PodSpecFlags{
-		Image:   "repo/user/imageID:tag",
-		Env:     []string{"b=c"},
-		EnvFrom: []string{},
-		Mount:   []string{},
-		Volume:  []string{},
-		Arg:     []string{},
+		Image:        "repo/user/imageID:tag",
+		Env:          []string{"b=c"},
+		EnvB64:       []string{},
+		EnvFrom:      []string{},
+		EnvValueFrom: []string{},
+		Mount:        []string{},
+		Volume:       []string{},
+		Arg:          []string{},
+		Containers:   []ContainerArgs{{Args: []string{"--image=repo/user/imageID:tag", "--env=b=c"}}},
 	}
 	flags := &PodSpecFlags{}
 	testCmd := &cobra.Command{
@@ -60,7 +64,9 @@ func TestPodSpecResolve(t *testing.T) {
 		"--port", "8080", "--limit", "cpu=1000m", "--limit", "memory=1024Mi",
 		"--cmd", "/app/start", "--arg", "myArg1", "--service-account", "foo-bar-account",
 		"--mount", "/mount/path=volume-name", "--volume", "volume-name=cm:config-map-name",
-		"--env-from", "config-map:config-map-name", "--user", "1001"}
+		"--env-from", "config-map:config-map-name", "--env-value-from", "SECRET=secret:secret-name:key", "--user", "1001",
+		"--probe-readiness", "http:/healthz:8080", "--probe-readiness-opts", "initialDelaySeconds=5,periodSeconds=10",
+		"--probe-liveness", "tcp"}
 	expectedPodSpec := corev1.PodSpec{
 		Containers: []corev1.Container{
 			{
@@ -72,7 +78,18 @@ func TestPodSpecResolve(t *testing.T) {
 						ContainerPort: 8080,
 					},
 				},
-				Env: []corev1.EnvVar{{Name: "b", Value: "c"}},
+				Env: []corev1.EnvVar{
+					{
+						Name: "SECRET",
+						ValueFrom: &corev1.EnvVarSource{
+							SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "secret-name"},
+								Key:                  "key",
+							},
+						},
+					},
+					{Name: "b", Value: "c"},
+				},
 				EnvFrom: []corev1.EnvFromSource{
 					{
 						ConfigMapRef: &corev1.ConfigMapEnvSource{
@@ -93,6 +110,16 @@ func TestPodSpecResolve(t *testing.T) {
 				SecurityContext: &corev1.SecurityContext{
 					RunAsUser: ptr.Int64(int64(1001)),
 				},
+				ReadinessProbe: &corev1.Probe{
+					Handler: corev1.Handler{
+						HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt(8080)},
+					},
+					InitialDelaySeconds: 5,
+					PeriodSeconds:       10,
+				},
+				LivenessProbe: &corev1.Probe{
+					Handler: corev1.Handler{TCPSocket: &corev1.TCPSocketAction{}},
+				},
 			},
 		},
 		ServiceAccountName: "foo-bar-account",
@@ -114,7 +141,7 @@ func TestPodSpecResolve(t *testing.T) {
 		Use: "test",
 		Run: func(cmd *cobra.Command, args []string) {
 			podSpec := &corev1.PodSpec{Containers: []corev1.Container{{}}}
-			err := flags.ResolvePodSpec(podSpec, cmd.Flags())
+			err := flags.ResolvePodSpec(podSpec, cmd.Flags(), util.NewFileValueResolver(nil))
 			assert.NilError(t, err, "PodSpec cannot be resolved.")
 			assert.DeepEqual(t, expectedPodSpec, *podSpec)
 		},
@@ -131,7 +158,7 @@ func TestPodSpecResolveReturnError(t *testing.T) {
 		Use: "test",
 		Run: func(cmd *cobra.Command, args []string) {
 			podSpec := &corev1.PodSpec{Containers: []corev1.Container{{}}}
-			err := flags.ResolvePodSpec(podSpec, cmd.Flags())
+			err := flags.ResolvePodSpec(podSpec, cmd.Flags(), util.NewFileValueResolver(nil))
 			fmt.Fprint(cmd.OutOrStdout(), "Return error: ", err)
 		},
 	}
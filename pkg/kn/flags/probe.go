@@ -0,0 +1,192 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"knative.dev/client/pkg/util"
+)
+
+// ProbeFormat describes the syntax of the --probe-readiness and --probe-liveness flags
+const ProbeFormat = "'http:PATH[:PORT]', 'https:PATH[:PORT]', 'tcp[:PORT]' or 'exec:COMMAND[,ARG...]'"
+
+// probeOptions are the keys accepted by the --probe-*-opts flags with the timing field of a probe they set
+var probeOptions = []struct {
+	name  string
+	field func(probe *corev1.Probe) *int32
+}{
+	{"initialDelaySeconds", func(probe *corev1.Probe) *int32 { return &probe.InitialDelaySeconds }},
+	{"timeoutSeconds", func(probe *corev1.Probe) *int32 { return &probe.TimeoutSeconds }},
+	{"periodSeconds", func(probe *corev1.Probe) *int32 { return &probe.PeriodSeconds }},
+	{"successThreshold", func(probe *corev1.Probe) *int32 { return &probe.SuccessThreshold }},
+	{"failureThreshold", func(probe *corev1.Probe) *int32 { return &probe.FailureThreshold }},
+}
+
+// UpdateReadinessProbe sets the handler of the container's readiness probe, given in ProbeFormat.
+// The timing options of an existing probe are kept. An empty value removes the probe.
+func UpdateReadinessProbe(spec *corev1.PodSpec, probe string) error {
+	container, err := containerOfPodSpec(spec)
+	if err != nil {
+		return err
+	}
+	container.ReadinessProbe, err = updateProbe(container.ReadinessProbe, probe)
+	return err
+}
+
+// UpdateLivenessProbe sets the handler of the container's liveness probe, given in ProbeFormat.
+// The timing options of an existing probe are kept. An empty value removes the probe.
+func UpdateLivenessProbe(spec *corev1.PodSpec, probe string) error {
+	container, err := containerOfPodSpec(spec)
+	if err != nil {
+		return err
+	}
+	container.LivenessProbe, err = updateProbe(container.LivenessProbe, probe)
+	return err
+}
+
+// UpdateReadinessProbeOpts sets the timing options of the container's readiness probe,
+// given as comma separated key=value pairs like 'initialDelaySeconds=5,periodSeconds=10'
+func UpdateReadinessProbeOpts(spec *corev1.PodSpec, opts string) error {
+	container, err := containerOfPodSpec(spec)
+	if err != nil {
+		return err
+	}
+	if container.ReadinessProbe == nil {
+		return fmt.Errorf("no readiness probe configured for the container, set one with --probe-readiness first")
+	}
+	return updateProbeOpts(container.ReadinessProbe, opts)
+}
+
+// UpdateLivenessProbeOpts sets the timing options of the container's liveness probe,
+// given as comma separated key=value pairs like 'initialDelaySeconds=5,periodSeconds=10'
+func UpdateLivenessProbeOpts(spec *corev1.PodSpec, opts string) error {
+	container, err := containerOfPodSpec(spec)
+	if err != nil {
+		return err
+	}
+	if container.LivenessProbe == nil {
+		return fmt.Errorf("no liveness probe configured for the container, set one with --probe-liveness first")
+	}
+	return updateProbeOpts(container.LivenessProbe, opts)
+}
+
+func updateProbe(probe *corev1.Probe, value string) (*corev1.Probe, error) {
+	if value == "" {
+		return nil, nil
+	}
+	handler, err := parseProbeHandler(value)
+	if err != nil {
+		return nil, err
+	}
+	if probe == nil {
+		probe = &corev1.Probe{}
+	}
+	probe.Handler = handler
+	return probe, nil
+}
+
+// parseProbeHandler parses a probe handler given in ProbeFormat
+func parseProbeHandler(value string) (corev1.Handler, error) {
+	probeType, spec := value, ""
+	if idx := strings.Index(value, ":"); idx >= 0 {
+		probeType, spec = value[:idx], value[idx+1:]
+	}
+	switch probeType {
+	case "http", "https":
+		path, port := spec, ""
+		if idx := strings.LastIndex(spec, ":"); idx >= 0 {
+			path, port = spec[:idx], spec[idx+1:]
+		}
+		if !strings.HasPrefix(path, "/") {
+			return corev1.Handler{}, fmt.Errorf("invalid probe '%s': the path must start with '/'", value)
+		}
+		action := &corev1.HTTPGetAction{Path: path}
+		if probeType == "https" {
+			action.Scheme = corev1.URISchemeHTTPS
+		}
+		if port != "" {
+			portNumber, err := parseProbePort(port)
+			if err != nil {
+				return corev1.Handler{}, fmt.Errorf("invalid probe '%s': %v", value, err)
+			}
+			action.Port = intstr.FromInt(portNumber)
+		}
+		return corev1.Handler{HTTPGet: action}, nil
+	case "tcp":
+		action := &corev1.TCPSocketAction{}
+		if spec != "" {
+			portNumber, err := parseProbePort(spec)
+			if err != nil {
+				return corev1.Handler{}, fmt.Errorf("invalid probe '%s': %v", value, err)
+			}
+			action.Port = intstr.FromInt(portNumber)
+		}
+		return corev1.Handler{TCPSocket: action}, nil
+	case "exec":
+		if spec == "" {
+			return corev1.Handler{}, fmt.Errorf("invalid probe '%s': a command is required", value)
+		}
+		return corev1.Handler{Exec: &corev1.ExecAction{Command: strings.Split(spec, ",")}}, nil
+	}
+	return corev1.Handler{}, fmt.Errorf("invalid probe '%s': must be one of %s", value, ProbeFormat)
+}
+
+func parseProbePort(port string) (int, error) {
+	portNumber, err := strconv.Atoi(port)
+	if err != nil || portNumber < 1 || portNumber > 65535 {
+		return 0, fmt.Errorf("port '%s' is not a number between 1 and 65535", port)
+	}
+	return portNumber, nil
+}
+
+func updateProbeOpts(probe *corev1.Probe, opts string) error {
+	optsMap, err := util.MapFromArray(strings.Split(opts, ","), "=")
+	if err != nil {
+		return fmt.Errorf("invalid probe options '%s': %v", opts, err)
+	}
+	known := sets.NewString(probeOptionNames()...)
+	for key := range optsMap {
+		if !known.Has(key) {
+			return fmt.Errorf("invalid probe option '%s', supported options: %s", key, strings.Join(probeOptionNames(), ", "))
+		}
+	}
+	for _, option := range probeOptions {
+		value, ok := optsMap[option.name]
+		if !ok {
+			continue
+		}
+		number, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || number < 0 {
+			return fmt.Errorf("invalid value '%s' for probe option '%s': must be a non negative number", value, option.name)
+		}
+		*option.field(probe) = int32(number)
+	}
+	return nil
+}
+
+func probeOptionNames() []string {
+	names := make([]string, 0, len(probeOptions))
+	for _, option := range probeOptions {
+		names = append(names, option.name)
+	}
+	return names
+}
@@ -0,0 +1,92 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestParseProbeHandler(t *testing.T) {
+	for _, tc := range []struct {
+		value       string
+		expected    corev1.Handler
+		expectedErr string
+	}{
+		{"http:/healthz:8080", corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt(8080)}}, ""},
+		{"http:/healthz", corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: "/healthz"}}, ""},
+		{"https:/:8443", corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: "/", Port: intstr.FromInt(8443), Scheme: corev1.URISchemeHTTPS}}, ""},
+		{"tcp", corev1.Handler{TCPSocket: &corev1.TCPSocketAction{}}, ""},
+		{"tcp:8080", corev1.Handler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(8080)}}, ""},
+		{"exec:cat,/tmp/healthy", corev1.Handler{Exec: &corev1.ExecAction{Command: []string{"cat", "/tmp/healthy"}}}, ""},
+		{"http:healthz", corev1.Handler{}, "path must start with '/'"},
+		{"http:/healthz:http", corev1.Handler{}, "port 'http' is not a number"},
+		{"tcp:0", corev1.Handler{}, "port '0' is not a number between 1 and 65535"},
+		{"exec", corev1.Handler{}, "a command is required"},
+		{"grpc:8080", corev1.Handler{}, "must be one of"},
+	} {
+		handler, err := parseProbeHandler(tc.value)
+		if tc.expectedErr != "" {
+			assert.ErrorContains(t, err, tc.expectedErr)
+			continue
+		}
+		assert.NilError(t, err)
+		assert.DeepEqual(t, handler, tc.expected)
+	}
+}
+
+func TestUpdateProbes(t *testing.T) {
+	spec := &corev1.PodSpec{Containers: []corev1.Container{{}}}
+
+	err := UpdateReadinessProbeOpts(spec, "periodSeconds=10")
+	assert.ErrorContains(t, err, "no readiness probe")
+
+	assert.NilError(t, UpdateReadinessProbe(spec, "http:/healthz:8080"))
+	assert.NilError(t, UpdateReadinessProbeOpts(spec, "initialDelaySeconds=5,periodSeconds=10"))
+	assert.NilError(t, UpdateLivenessProbe(spec, "tcp"))
+	assert.NilError(t, UpdateLivenessProbeOpts(spec, "failureThreshold=3"))
+
+	container := spec.Containers[0]
+	assert.DeepEqual(t, container.ReadinessProbe, &corev1.Probe{
+		Handler:             corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt(8080)}},
+		InitialDelaySeconds: 5,
+		PeriodSeconds:       10,
+	})
+	assert.DeepEqual(t, container.LivenessProbe, &corev1.Probe{
+		Handler:          corev1.Handler{TCPSocket: &corev1.TCPSocketAction{}},
+		FailureThreshold: 3,
+	})
+
+	// Changing the handler keeps the options
+	assert.NilError(t, UpdateReadinessProbe(spec, "exec:true"))
+	assert.DeepEqual(t, spec.Containers[0].ReadinessProbe, &corev1.Probe{
+		Handler:             corev1.Handler{Exec: &corev1.ExecAction{Command: []string{"true"}}},
+		InitialDelaySeconds: 5,
+		PeriodSeconds:       10,
+	})
+
+	err = UpdateReadinessProbeOpts(spec, "delay=5")
+	assert.ErrorContains(t, err, "invalid probe option 'delay', supported options: initialDelaySeconds, timeoutSeconds")
+	err = UpdateReadinessProbeOpts(spec, "periodSeconds=-1")
+	assert.ErrorContains(t, err, "must be a non negative number")
+
+	// Empty value removes the probe
+	assert.NilError(t, UpdateReadinessProbe(spec, ""))
+	assert.Assert(t, spec.Containers[0].ReadinessProbe == nil)
+	assert.Assert(t, spec.Containers[0].LivenessProbe != nil)
+}
@@ -15,8 +15,12 @@
 package flags
 
 import (
+	"fmt"
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"knative.dev/client/pkg/util"
 )
@@ -75,13 +79,46 @@ func populateResourceListV1(resourceStatements []string) (corev1.ResourceList, [
 			continue
 		}
 
+		if errs := validation.IsQualifiedName(res); len(errs) > 0 {
+			return nil, resourcesToRemove, fmt.Errorf("invalid resource name '%s': %s", res, strings.Join(errs, ", "))
+		}
 		resourceQuantity, err := resource.ParseQuantity(value)
 		if err != nil {
 			return nil, resourcesToRemove, err
 		}
+		if isExtendedResourceName(corev1.ResourceName(res)) && resourceQuantity.MilliValue()%1000 != 0 {
+			return nil, resourcesToRemove, fmt.Errorf("invalid quantity '%s' for extended resource '%s': must be a whole number", value, res)
+		}
 
 		result[corev1.ResourceName(res)] = resourceQuantity
 	}
 
 	return result, resourcesToRemove, nil
 }
+
+// isExtendedResourceName returns true for resources which are provided by
+// device plugins or the cluster operator, like 'nvidia.com/gpu'
+func isExtendedResourceName(name corev1.ResourceName) bool {
+	return strings.Contains(string(name), "/") &&
+		!strings.Contains(string(name), corev1.ResourceDefaultNamespacePrefix) &&
+		!strings.HasPrefix(string(name), corev1.DefaultResourceRequestsPrefix)
+}
+
+// validateResourceRequirements checks the resources of a container like Kubernetes does:
+// requests must not exceed limits, and as extended resources can't be overcommitted,
+// their requests must be equal to their limits
+func validateResourceRequirements(resources corev1.ResourceRequirements) error {
+	for name, request := range resources.Requests {
+		limit, hasLimit := resources.Limits[name]
+		if isExtendedResourceName(name) {
+			if !hasLimit || request.Cmp(limit) != 0 {
+				return fmt.Errorf("request of extended resource '%s' must be equal to its limit, use only '--limit %s=%s' for setting both", name, name, request.String())
+			}
+			continue
+		}
+		if hasLimit && request.Cmp(limit) > 0 {
+			return fmt.Errorf("request %s of resource '%s' must be less than or equal to its limit %s", request.String(), name, limit.String())
+		}
+	}
+	return nil
+}
@@ -52,6 +52,18 @@ func TestResourceOptions(t *testing.T) {
 			false,
 		},
 
+		{[]string{},
+			[]string{"nvidia.com/gpu=500m"},
+			nil,
+			nil,
+			true,
+		},
+		{[]string{"my resource=1"},
+			[]string{},
+			nil,
+			nil,
+			true,
+		},
 		{[]string{},
 			[]string{"memory:500Mi"},
 			nil,
@@ -110,3 +122,47 @@ func TestResourceOptions(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateResourceRequirements(t *testing.T) {
+	for _, tc := range []struct {
+		requests    corev1.ResourceList
+		limits      corev1.ResourceList
+		expectedErr string
+	}{
+		{
+			corev1.ResourceList{corev1.ResourceCPU: parseQuantity("100m")},
+			corev1.ResourceList{corev1.ResourceCPU: parseQuantity("1"), "nvidia.com/gpu": parseQuantity("1")},
+			"",
+		},
+		{
+			corev1.ResourceList{corev1.ResourceMemory: parseQuantity("1Gi")},
+			corev1.ResourceList{corev1.ResourceMemory: parseQuantity("256Mi")},
+			"request 1Gi of resource 'memory' must be less than or equal to its limit 256Mi",
+		},
+		{
+			corev1.ResourceList{"nvidia.com/gpu": parseQuantity("1")},
+			corev1.ResourceList{"nvidia.com/gpu": parseQuantity("1")},
+			"",
+		},
+		{
+			corev1.ResourceList{"nvidia.com/gpu": parseQuantity("1")},
+			corev1.ResourceList{"nvidia.com/gpu": parseQuantity("2")},
+			"request of extended resource 'nvidia.com/gpu' must be equal to its limit",
+		},
+		{
+			corev1.ResourceList{"nvidia.com/gpu": parseQuantity("1")},
+			nil,
+			"use only '--limit nvidia.com/gpu=1'",
+		},
+	} {
+		err := validateResourceRequirements(corev1.ResourceRequirements{Requests: tc.requests, Limits: tc.limits})
+		if tc.expectedErr == "" {
+			assert.NilError(t, err)
+		} else {
+			assert.ErrorContains(t, err, tc.expectedErr)
+		}
+	}
+	assert.Assert(t, isExtendedResourceName("nvidia.com/gpu"))
+	assert.Assert(t, !isExtendedResourceName(corev1.ResourceCPU))
+	assert.Assert(t, !isExtendedResourceName("kubernetes.io/something"))
+}
@@ -232,7 +232,7 @@ func (manager *Manager) LookupInPath() bool {
 
 // Execute the plugin with the given arguments
 func (plugin *plugin) Execute(args []string) error {
-	cmd := exec.Command(plugin.path, args...)
+	cmd := pluginCommand(runtime.GOOS, plugin.path, args)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
@@ -240,6 +240,16 @@ func (plugin *plugin) Execute(args []string) error {
 	return cmd.Run()
 }
 
+// pluginCommand creates the command for calling the plugin at the given path.
+// PowerShell scripts can't be started directly on Windows, so they are
+// handed over to powershell.
+func pluginCommand(goos string, path string, args []string) *exec.Cmd {
+	if goos == "windows" && strings.EqualFold(filepath.Ext(path), ".ps1") {
+		return exec.Command("powershell", append([]string{"-NoProfile", "-File", path}, args...)...)
+	}
+	return exec.Command(path, args...)
+}
+
 // Return a description of the plugin (if support by the plugin binary)
 func (plugin *plugin) Description() (string, error) {
 	// TODO: Call out to the plugin to find a description.
@@ -272,7 +282,10 @@ func (manager *Manager) pluginLookupDirectories() ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
-	dirs := []string{pluginPath}
+	var dirs []string
+	if pluginPath != "" {
+		dirs = append(dirs, pluginPath)
+	}
 	if manager.lookupInPath {
 		dirs = append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
 	}
@@ -356,11 +369,17 @@ func extractPluginCommandFromFileName(name string) []string {
 
 // Strip any extension that indicates an EXE on Windows
 func stripWindowsExecExtensions(name string) string {
-	if runtime.GOOS == "windows" {
+	return stripExecExtensions(runtime.GOOS, name)
+}
+
+// Strip any extension that indicates an EXE on the given OS.
+// Extensions are case-insensitive on Windows (e.g. "kn-foo.EXE").
+func stripExecExtensions(goos string, name string) string {
+	if goos == "windows" {
 		ext := filepath.Ext(name)
 		if len(ext) > 0 {
 			for _, e := range windowsExecExtensions {
-				if ext == e {
+				if strings.EqualFold(ext, e) {
 					name = name[:len(name)-len(ext)]
 					break
 				}
@@ -432,20 +451,23 @@ func findInDirOrPath(name string, dir string, lookupInPath bool) (string, error)
 	for _, ext := range exts {
 		nameExt := name + ext
 
-		// Check plugin dir first
-		path := filepath.Join(dir, nameExt)
-		_, err := os.Stat(path)
-		if err == nil {
-			// Found in dir
-			return path, nil
-		}
-		if !os.IsNotExist(err) {
-			return "", errors.Wrap(err, fmt.Sprintf("i/o error while reading %s", path))
+		// Check plugin dir first, if there is one. An empty dir must not lead to a lookup
+		// in the current working directory
+		if dir != "" {
+			path := filepath.Join(dir, nameExt)
+			_, err := os.Stat(path)
+			if err == nil {
+				// Found in dir
+				return path, nil
+			}
+			if !os.IsNotExist(err) {
+				return "", errors.Wrap(err, fmt.Sprintf("i/o error while reading %s", path))
+			}
 		}
 
 		// Check in PATH if requested
 		if lookupInPath {
-			path, err = exec.LookPath(name)
+			path, err := exec.LookPath(name)
 			if err == nil {
 				// Found in path
 				return path, nil
@@ -78,6 +78,25 @@ func TestLookupInPluginsDir(t *testing.T) {
 	assert.Equal(t, out, "OK \n")
 }
 
+func TestNoLookupInWorkingDirWithoutPluginsDir(t *testing.T) {
+	ctx := setup(t)
+	defer cleanup(t, ctx)
+	createTestPlugin(t, "kn-test", ctx)
+
+	wd, err := os.Getwd()
+	assert.NilError(t, err)
+	assert.NilError(t, os.Chdir(ctx.pluginsDir))
+	defer os.Chdir(wd)
+
+	pluginManager := NewManager("", false)
+	plugin, err := pluginManager.FindPlugin([]string{"test"})
+	assert.NilError(t, err)
+	assert.Assert(t, plugin == nil, "no plugin should be found in the working directory")
+	plugins, err := pluginManager.ListPlugins()
+	assert.NilError(t, err)
+	assert.Assert(t, !containsPluginWithName(plugins, "kn-test"))
+}
+
 func TestFindWithNotFoundResult(t *testing.T) {
 	ctx := setup(t)
 	defer cleanup(t, ctx)
@@ -153,6 +172,26 @@ func TestPluginExecute(t *testing.T) {
 	assert.Equal(t, out, "OK arg1 arg2\n")
 }
 
+func TestPluginCommand(t *testing.T) {
+	cmd := pluginCommand("linux", "/plugins/kn-foo.ps1", []string{"bar"})
+	assert.DeepEqual(t, cmd.Args, []string{"/plugins/kn-foo.ps1", "bar"})
+
+	cmd = pluginCommand("windows", `C:\plugins\kn-foo.exe`, []string{"bar"})
+	assert.DeepEqual(t, cmd.Args, []string{`C:\plugins\kn-foo.exe`, "bar"})
+
+	cmd = pluginCommand("windows", `C:\plugins\kn-foo.PS1`, []string{"bar"})
+	assert.DeepEqual(t, cmd.Args, []string{"powershell", "-NoProfile", "-File", `C:\plugins\kn-foo.PS1`, "bar"})
+}
+
+func TestStripExecExtensions(t *testing.T) {
+	for _, name := range []string{"kn-foo.exe", "kn-foo.bat", "kn-foo.EXE", "kn-foo.Cmd", "kn-foo.ps1"} {
+		assert.Equal(t, stripExecExtensions("windows", name), "kn-foo")
+		assert.Equal(t, stripExecExtensions("linux", name), name)
+	}
+	assert.Equal(t, stripExecExtensions("windows", "kn-foo.sh"), "kn-foo.sh")
+	assert.DeepEqual(t, extractPluginCommandFromFileName("kn-foo_bar-baz"), []string{"foo-bar", "baz"})
+}
+
 func TestPluginMixed(t *testing.T) {
 	ctx := setup(t)
 	defer cleanup(t, ctx)
@@ -0,0 +1,130 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package root
+
+import (
+	"github.com/spf13/cobra"
+
+	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/templates"
+)
+
+// Option customizes the command tree created by NewRootCommand, e.g. when kn's
+// commands are embedded into another Go program
+type Option func(*rootOptions)
+
+// CommandFactory creates a command which uses the given parameters for accessing
+// the cluster, like service.NewServiceCommand
+type CommandFactory func(p *commands.KnParams) *cobra.Command
+
+type rootOptions struct {
+	params        *commands.KnParams
+	extraGroups   []extraCommandGroup
+	removedGroups map[string]bool
+	removed       map[string]bool
+}
+
+type extraCommandGroup struct {
+	header    string
+	factories []CommandFactory
+}
+
+// WithParams lets all commands use the given parameters, e.g. with client factories
+// which return fake or preconfigured clients. Factories which are not set are
+// initialized with the defaults.
+func WithParams(p *commands.KnParams) Option {
+	return func(o *rootOptions) {
+		o.params = p
+	}
+}
+
+// WithCommandGroup adds the commands created by the given factories to the command
+// group with the given header (e.g. "Serving Commands:"). The group is appended
+// if there is no group with this header yet.
+func WithCommandGroup(header string, factories ...CommandFactory) Option {
+	return func(o *rootOptions) {
+		o.extraGroups = append(o.extraGroups, extraCommandGroup{header, factories})
+	}
+}
+
+// WithoutCommandGroup removes the command group with the given header and all of
+// its commands
+func WithoutCommandGroup(header string) Option {
+	return func(o *rootOptions) {
+		o.removedGroups[header] = true
+	}
+}
+
+// WithoutCommands removes the top-level commands with the given names, e.g. "plugin"
+// when the embedding program doesn't support kn plugins
+func WithoutCommands(names ...string) Option {
+	return func(o *rootOptions) {
+		for _, name := range names {
+			o.removed[name] = true
+		}
+	}
+}
+
+func newRootOptions(opts []Option) *rootOptions {
+	o := &rootOptions{
+		removedGroups: map[string]bool{},
+		removed:       map[string]bool{},
+	}
+	for _, option := range opts {
+		option(o)
+	}
+	if o.params == nil {
+		o.params = &commands.KnParams{}
+	}
+	return o
+}
+
+// apply adds and removes the command groups and commands requested by the options
+func (o *rootOptions) apply(groups templates.CommandGroups) templates.CommandGroups {
+	for _, extra := range o.extraGroups {
+		var cmds []*cobra.Command
+		for _, factory := range extra.factories {
+			cmds = append(cmds, factory(o.params))
+		}
+		added := false
+		for i := range groups {
+			if groups[i].Header == extra.header {
+				groups[i].Commands = append(groups[i].Commands, cmds...)
+				added = true
+				break
+			}
+		}
+		if !added {
+			groups = append(groups, templates.CommandGroup{Header: extra.header, Commands: cmds})
+		}
+	}
+
+	var result templates.CommandGroups
+	for _, group := range groups {
+		if o.removedGroups[group.Header] {
+			continue
+		}
+		var cmds []*cobra.Command
+		for _, cmd := range group.Commands {
+			if !o.removed[cmd.Name()] {
+				cmds = append(cmds, cmd)
+			}
+		}
+		if len(cmds) > 0 {
+			result = append(result, templates.CommandGroup{Header: group.Header, Commands: cmds})
+		}
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package root
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/assert"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+	"knative.dev/client/pkg/util/mock"
+)
+
+func TestNewRootCommandWithParams(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.ListServices(mock.Any(), &servingv1.ServiceList{}, nil)
+
+	output := new(bytes.Buffer)
+	p := &commands.KnParams{Output: output}
+	p.NewServingClient = func(namespace string) (clientservingv1.KnServingClient, error) {
+		return client, nil
+	}
+	rootCmd, err := NewRootCommand(nil, WithParams(p))
+	assert.NilError(t, err)
+	rootCmd.SetArgs([]string{"service", "list", "--namespace", "default"})
+	assert.NilError(t, rootCmd.Execute())
+	assert.Assert(t, util.ContainsAll(output.String(), "No services found"))
+	// Factories which have not been injected are initialized with the defaults
+	assert.Assert(t, p.NewEventingClient != nil)
+	r.Validate()
+}
+
+func TestNewRootCommandWithCommandGroups(t *testing.T) {
+	var params *commands.KnParams
+	newHelloCommand := func(p *commands.KnParams) *cobra.Command {
+		params = p
+		return &cobra.Command{Use: "hello", Short: "Say hello", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	}
+	newStatusCommand := func(p *commands.KnParams) *cobra.Command {
+		return &cobra.Command{Use: "status", Short: "Show status", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	}
+
+	rootCmd, err := NewRootCommand(&template.FuncMap{
+		"listPlugins": func(c *cobra.Command) string { return "" },
+	},
+		WithCommandGroup("Custom Commands:", newHelloCommand),
+		WithCommandGroup("Other Commands:", newStatusCommand),
+		WithoutCommandGroup("Messaging Commands:"),
+		WithoutCommands("plugin", "completion"))
+	assert.NilError(t, err)
+	assert.Assert(t, params != nil)
+
+	checkLeafCommand(t, "hello", rootCmd)
+	checkLeafCommand(t, "status", rootCmd)
+	for _, name := range []string{"channel", "subscription", "plugin", "completion"} {
+		cmd, _, _ := rootCmd.Find([]string{name})
+		assert.Assert(t, cmd == rootCmd, "command %s has not been removed", name)
+	}
+	checkCommandGroup(t, []string{"service"}, rootCmd)
+
+	usage := rootCmd.UsageString()
+	assert.Assert(t, util.ContainsAll(usage, "Serving Commands:", "Custom Commands:", "hello", "status"))
+	assert.Assert(t, util.ContainsNone(usage, "Messaging Commands:"))
+}
@@ -17,6 +17,7 @@ package root
 import (
 	"flag"
 	"fmt"
+	"os"
 	"strings"
 	"text/template"
 
@@ -26,9 +27,14 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
 
 	"knative.dev/client/pkg/kn/commands"
+	"knative.dev/client/pkg/kn/commands/apply"
 	"knative.dev/client/pkg/kn/commands/broker"
 	"knative.dev/client/pkg/kn/commands/channel"
 	"knative.dev/client/pkg/kn/commands/completion"
+	"knative.dev/client/pkg/kn/commands/diagnose"
+	"knative.dev/client/pkg/kn/commands/eventing"
+	"knative.dev/client/pkg/kn/commands/gc"
+	"knative.dev/client/pkg/kn/commands/namespace"
 	"knative.dev/client/pkg/kn/commands/options"
 	"knative.dev/client/pkg/kn/commands/plugin"
 	"knative.dev/client/pkg/kn/commands/revision"
@@ -38,14 +44,20 @@ import (
 	"knative.dev/client/pkg/kn/commands/subscription"
 	"knative.dev/client/pkg/kn/commands/trigger"
 	"knative.dev/client/pkg/kn/commands/version"
+	"knative.dev/client/pkg/kn/commands/wait"
 	"knative.dev/client/pkg/kn/config"
 	"knative.dev/client/pkg/kn/flags"
+	"knative.dev/client/pkg/printers"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
 	"knative.dev/client/pkg/templates"
+	"knative.dev/client/pkg/util"
 )
 
-// NewRootCommand creates the default `kn` command with a default plugin handler
-func NewRootCommand(helpFuncs *template.FuncMap) (*cobra.Command, error) {
-	p := &commands.KnParams{}
+// NewRootCommand creates the default `kn` command with a default plugin handler.
+// Programs which embed kn can customize the command tree with options.
+func NewRootCommand(helpFuncs *template.FuncMap, opts ...Option) (*cobra.Command, error) {
+	rootOptions := newRootOptions(opts)
+	p := rootOptions.params
 	p.Initialize()
 
 	rootCmd := &cobra.Command{
@@ -63,9 +75,40 @@ func NewRootCommand(helpFuncs *template.FuncMap) (*cobra.Command, error) {
 		SilenceUsage:  true,
 		SilenceErrors: true,
 
-		// Validate our boolean configs
+		// Take defaults from the environment and validate our boolean configs
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			return flags.ReconcileBoolFlags(cmd.Flags())
+			err := p.BindEnvironment(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			if p.NonInteractive {
+				err = commands.DisableStdin()
+				if err != nil {
+					return err
+				}
+				cmd.SetIn(os.Stdin)
+			}
+			if p.LogVerbosity > 0 {
+				err = util.SetLogVerbosity(p.LogVerbosity)
+				if err != nil {
+					return err
+				}
+			}
+			err = flags.ReconcileBoolFlags(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			if p.ForceConflicts && !p.ServerSideApply && !config.GlobalConfig.ServerSideApply() {
+				return fmt.Errorf("--force-conflicts can only be used with --server-side-apply")
+			}
+			return p.CheckCompatibility(cmd)
+		},
+
+		// Notify the configured sink about successful create, update and delete operations.
+		// The API server's warnings are reported by the caller of Execute(), as post-run
+		// hooks don't run for failed commands.
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			p.NotifyOperation(cmd, args)
 		},
 	}
 	if p.Output != nil {
@@ -78,6 +121,17 @@ func NewRootCommand(helpFuncs *template.FuncMap) (*cobra.Command, error) {
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&p.KubeCfgPath, "kubeconfig", "", "kubectl configuration file (default: ~/.kube/config)")
 	flags.AddBothBoolFlags(rootCmd.PersistentFlags(), &p.LogHTTP, "log-http", "", false, "log http traffic")
+	rootCmd.PersistentFlags().BoolVar(&printers.NoTimestamps, "no-timestamps", false, "don't print durations, ages and timestamps, e.g. for comparing output with golden files")
+	rootCmd.PersistentFlags().BoolVar(&printers.StableOutput, "stable-output", false, "print only output which doesn't depend on time or timing (implies --no-timestamps)")
+	rootCmd.PersistentFlags().BoolVar(&p.NonInteractive, "non-interactive", false, "never prompt, open an editor or read from stdin, e.g. for cron jobs and git hooks")
+	rootCmd.PersistentFlags().IntVar(&p.LogVerbosity, "v", 0, fmt.Sprintf("log level of structured logs about kn internals written to stderr: "+
+		"%d client construction, %d retries, %d watch events, 6 and higher HTTP requests", util.LogLevelClient, util.LogLevelRetry, util.LogLevelWatch))
+	rootCmd.PersistentFlags().BoolVar(&p.StrictCompat, "strict-compat", false, "fail instead of warning when a flag requires a newer Knative version than the one in the cluster")
+	rootCmd.PersistentFlags().BoolVar(&p.NamespaceRequired, "namespace-required", false, "fail instead of using the 'default' namespace when neither --namespace nor the kubeconfig context sets one")
+	rootCmd.PersistentFlags().IntVar(&p.Retries, "retries", clientservingv1.DefaultRetryPolicy.MaxRetries, "number of retries of an update which conflicts with a concurrent change, overrides 'retry.max-retries' of the configuration")
+	rootCmd.PersistentFlags().BoolVar(&p.FailOnWarning, "fail-on-warning", false, "fail after the operation when the API server returned warnings, e.g. about deprecations or from admission policies")
+	rootCmd.PersistentFlags().BoolVar(&p.ServerSideApply, "server-side-apply", false, "update services with server-side apply and the field manager 'kn' instead of replacing them, so that updates don't conflict with concurrent changes")
+	rootCmd.PersistentFlags().BoolVar(&p.ForceConflicts, "force-conflicts", false, "with --server-side-apply, take over changed fields which other managers own instead of failing with a conflict")
 
 	// Grouped commands
 	groups := templates.CommandGroups{
@@ -95,6 +149,12 @@ func NewRootCommand(helpFuncs *template.FuncMap) (*cobra.Command, error) {
 				source.NewSourceCommand(p),
 				broker.NewBrokerCommand(p),
 				trigger.NewTriggerCommand(p),
+				eventing.NewEventingCommand(p),
+			},
+		},
+		{
+			Header: "Messaging Commands:",
+			Commands: []*cobra.Command{
 				channel.NewChannelCommand(p),
 				subscription.NewSubscriptionCommand(p),
 			},
@@ -102,12 +162,19 @@ func NewRootCommand(helpFuncs *template.FuncMap) (*cobra.Command, error) {
 		{
 			Header: "Other Commands:",
 			Commands: []*cobra.Command{
+				namespace.NewNamespaceCommand(p),
+				apply.NewApplyCommand(p),
+				diagnose.NewDiagnoseCommand(p),
+				gc.NewGcCommand(p),
+				wait.NewWaitCommand(p),
 				plugin.NewPluginCommand(p),
 				completion.NewCompletionCommand(p),
 				version.NewVersionCommand(p),
 			},
 		},
 	}
+	groups = rootOptions.apply(groups)
+
 	// Add all commands to the root command, flat
 	groups.AddTo(rootCmd)
 
@@ -117,8 +184,14 @@ func NewRootCommand(helpFuncs *template.FuncMap) (*cobra.Command, error) {
 	// Add the "options" commands for showing all global options
 	rootCmd.AddCommand(options.NewOptionsCommand())
 
+	// Complete flag values like namespaces and revisions
+	err := completion.RegisterFlagCompletions(rootCmd, p)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check that command groups can't execute and that leaf commands don't h
-	err := validateCommandStructure(rootCmd)
+	err = validateCommandStructure(rootCmd)
 	if err != nil {
 		return nil, err
 	}
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"text/template"
 
 	"github.com/spf13/cobra"
 	"gotest.tools/assert"
@@ -71,6 +72,17 @@ func TestCommandGroup(t *testing.T) {
 	}
 }
 
+func TestCommandGroupHeaders(t *testing.T) {
+	rootCmd, err := NewRootCommand(&template.FuncMap{
+		"listPlugins": func(c *cobra.Command) string { return "" },
+	})
+	assert.NilError(t, err)
+	usage := rootCmd.UsageString()
+	assert.Assert(t, util.ContainsAll(usage, "Serving Commands:", "Eventing Commands:", "Messaging Commands:", "Other Commands:", "kn options"))
+	assert.Assert(t, strings.Index(usage, "Eventing Commands:") < strings.Index(usage, "Messaging Commands:"))
+	assert.Assert(t, strings.Index(usage, "Messaging Commands:") < strings.Index(usage, "channel"))
+}
+
 func TestEmptyAndUnknownSubCommands(t *testing.T) {
 	rootCmd := &cobra.Command{
 		Use: "root",
@@ -0,0 +1,44 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testing
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+var updateGolden = flag.Bool("update-golden", false, "update golden files with the actual output")
+
+// AssertGolden checks that the actual output equals the content of the given golden
+// file, usually below the "testdata" directory of the package. Run the tests with
+// -update-golden to create or update the golden files from the actual output.
+func AssertGolden(t *testing.T, actual string, goldenFile string) {
+	t.Helper()
+	if *updateGolden {
+		err := os.MkdirAll(filepath.Dir(goldenFile), 0755)
+		assert.NilError(t, err)
+		err = ioutil.WriteFile(goldenFile, []byte(actual), 0644)
+		assert.NilError(t, err)
+		return
+	}
+	expected, err := ioutil.ReadFile(goldenFile)
+	assert.NilError(t, err, "cannot read golden file, run the tests with -update-golden to create it")
+	assert.Equal(t, actual, string(expected), "output differs from %s, run the tests with -update-golden to update it", goldenFile)
+}
@@ -0,0 +1,108 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package testing helps to test kn commands and plugins without a cluster.
+//
+// A Runner executes a command against scripted ("record/replay") serving and
+// eventing clients and captures the output, which can be compared with a golden
+// file with AssertGolden.
+package testing
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	clienteventingv1beta1 "knative.dev/client/pkg/eventing/v1beta1"
+	"knative.dev/client/pkg/kn/commands"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+)
+
+// DefaultNamespace is the current namespace of a Runner if none is given
+const DefaultNamespace = "default"
+
+// Runner runs kn commands against scripted clients. Record the calls which
+// are expected with the recorders of Serving and Eventing before running a command.
+type Runner struct {
+	t *testing.T
+
+	// Params are given to the command under test. They can be adapted,
+	// e.g. to add other clients.
+	Params *commands.KnParams
+
+	// Serving is the scripted serving client
+	Serving *clientservingv1.MockKnServingClient
+
+	// Eventing is the scripted eventing client
+	Eventing *clienteventingv1beta1.MockKnEventingClient
+}
+
+// NewRunner creates a runner whose current namespace is the given namespace
+// or DefaultNamespace
+func NewRunner(t *testing.T, namespace ...string) *Runner {
+	ns := DefaultNamespace
+	if len(namespace) > 0 {
+		ns = namespace[0]
+	}
+	r := &Runner{
+		t:        t,
+		Serving:  clientservingv1.NewMockKnServiceClient(t, ns),
+		Eventing: clienteventingv1beta1.NewMockKnEventingClient(t, ns),
+	}
+	r.Params = &commands.KnParams{
+		ClientConfig: clientConfigWithNamespace(ns),
+		NewServingClient: func(namespace string) (clientservingv1.KnServingClient, error) {
+			return r.Serving, nil
+		},
+		NewEventingClient: func(namespace string) (clienteventingv1beta1.KnEventingClient, error) {
+			return r.Eventing, nil
+		},
+	}
+	return r
+}
+
+// Run creates the command with the runner's params, executes it with the given
+// arguments and returns the captured output. The command is added to a "kn" root
+// command, so the arguments start with the name of the command, like in
+// "service create foo --image bar".
+func (r *Runner) Run(newCommand func(p *commands.KnParams) *cobra.Command, args ...string) (string, error) {
+	output := new(bytes.Buffer)
+	r.Params.Output = output
+	root := commands.NewTestCommand(newCommand(r.Params), r.Params)
+	root.SetArgs(args)
+	root.SetOut(output)
+	root.SetErr(output)
+	err := root.Execute()
+	return output.String(), err
+}
+
+// Validate checks that all recorded calls have been made
+func (r *Runner) Validate() {
+	r.Serving.Recorder().Validate()
+	r.Eventing.Recorder().Validate()
+}
+
+// A kubeconfig which is never used for connecting, but only for picking up
+// the current namespace
+func clientConfigWithNamespace(namespace string) clientcmd.ClientConfig {
+	config := clientcmdapi.NewConfig()
+	config.Clusters["test"] = &clientcmdapi.Cluster{Server: "https://example.com"}
+	config.AuthInfos["test"] = &clientcmdapi.AuthInfo{}
+	config.Contexts["test"] = &clientcmdapi.Context{Cluster: "test", AuthInfo: "test", Namespace: namespace}
+	config.CurrentContext = "test"
+	return clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{})
+}
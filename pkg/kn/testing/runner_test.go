@@ -0,0 +1,70 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/eventing/pkg/apis/eventing/v1beta1"
+
+	"knative.dev/client/pkg/kn/commands/broker"
+	"knative.dev/client/pkg/kn/commands/service"
+	"knative.dev/client/pkg/util"
+)
+
+func TestRunnerServing(t *testing.T) {
+	r := NewRunner(t)
+	r.Serving.Recorder().DeleteService("foo", time.Duration(0), nil)
+	r.Serving.Recorder().DeleteService("bar", time.Duration(0), errors.New("services.serving.knative.dev \"bar\" not found"))
+
+	output, err := r.Run(service.NewServiceCommand, "service", "delete", "foo", "--no-wait")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "Service", "foo", "successfully deleted", "namespace", DefaultNamespace))
+
+	_, err = r.Run(service.NewServiceCommand, "service", "delete", "bar", "--no-wait")
+	assert.ErrorContains(t, err, "not found")
+
+	r.Validate()
+}
+
+func TestRunnerNamespace(t *testing.T) {
+	r := NewRunner(t, "test-ns")
+	r.Eventing.Recorder().ListBrokers(&v1beta1.BrokerList{}, nil)
+
+	output, err := r.Run(broker.NewBrokerCommand, "broker", "list")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "No brokers found"))
+	assert.Equal(t, r.Eventing.Namespace(), "test-ns")
+
+	r.Validate()
+}
+
+func TestRunnerGolden(t *testing.T) {
+	r := NewRunner(t)
+	r.Eventing.Recorder().ListBrokers(&v1beta1.BrokerList{Items: []v1beta1.Broker{
+		{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: DefaultNamespace}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "nest", Namespace: DefaultNamespace}},
+	}}, nil)
+
+	output, err := r.Run(broker.NewBrokerCommand, "broker", "list")
+	assert.NilError(t, err)
+	AssertGolden(t, output, "testdata/broker_list.golden")
+
+	r.Validate()
+}
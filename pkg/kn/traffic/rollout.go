@@ -0,0 +1,92 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traffic
+
+import (
+	"fmt"
+
+	"knative.dev/pkg/ptr"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// RolloutSource returns the single revision which receives the traffic not
+// routed to the given target revision, or an empty string if the target
+// already receives all traffic. Traffic following the latest revision is
+// not supported, as it would move to a new revision on its own.
+func RolloutSource(targets []servingv1.TrafficTarget, target string) (string, error) {
+	source := ""
+	for _, t := range targets {
+		if t.Percent == nil || *t.Percent == 0 {
+			continue
+		}
+		if t.LatestRevision != nil && *t.LatestRevision {
+			return "", fmt.Errorf("traffic is routed to the latest revision, pin it to the current revision first, e.g. with 'kn service update --traffic REVISION=100'")
+		}
+		if t.RevisionName == target {
+			continue
+		}
+		if source != "" && source != t.RevisionName {
+			return "", fmt.Errorf("traffic is split between revisions '%s' and '%s', a rollout can only shift traffic from a single revision", source, t.RevisionName)
+		}
+		source = t.RevisionName
+	}
+	return source, nil
+}
+
+// RolloutPercent returns the percentage of the traffic routed to the revision
+func RolloutPercent(targets []servingv1.TrafficTarget, revision string) int64 {
+	var percent int64
+	for _, t := range targets {
+		if t.RevisionName == revision && t.Percent != nil {
+			percent += *t.Percent
+		}
+	}
+	return percent
+}
+
+// RolloutStep returns the traffic targets with the given percentage routed to the
+// target revision and the rest to the source revision. Other targets, e.g. tagged
+// ones without traffic, are kept. Targets of the source revision without a tag are
+// removed once they don't receive traffic anymore.
+func RolloutStep(targets []servingv1.TrafficTarget, source string, target string, percent int64) []servingv1.TrafficTarget {
+	result := make([]servingv1.TrafficTarget, 0, len(targets)+1)
+	sourceSet, targetSet := false, false
+	for _, t := range targets {
+		switch t.RevisionName {
+		case source:
+			p := int64(0)
+			if !sourceSet {
+				p = 100 - percent
+				sourceSet = true
+			}
+			if p == 0 && t.Tag == "" {
+				continue
+			}
+			t.Percent = ptr.Int64(p)
+		case target:
+			p := int64(0)
+			if !targetSet {
+				p = percent
+				targetSet = true
+			}
+			t.Percent = ptr.Int64(p)
+		}
+		result = append(result, t)
+	}
+	if !targetSet {
+		result = append(result, newTarget("", target, percent, false))
+	}
+	return result
+}
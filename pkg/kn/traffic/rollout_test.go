@@ -0,0 +1,76 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traffic
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	"knative.dev/pkg/ptr"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+func TestRolloutSource(t *testing.T) {
+	source, err := RolloutSource([]servingv1.TrafficTarget{
+		newTarget("", "foo-v1", 90, false),
+		newTarget("", "foo-v2", 10, false),
+		newTarget("old", "foo-v0", 0, false),
+	}, "foo-v2")
+	assert.NilError(t, err)
+	assert.Equal(t, source, "foo-v1")
+
+	source, err = RolloutSource([]servingv1.TrafficTarget{newTarget("", "foo-v2", 100, false)}, "foo-v2")
+	assert.NilError(t, err)
+	assert.Equal(t, source, "")
+
+	_, err = RolloutSource([]servingv1.TrafficTarget{newTarget("", "", 100, true)}, "foo-v2")
+	assert.ErrorContains(t, err, "pin it to the current revision")
+
+	_, err = RolloutSource([]servingv1.TrafficTarget{
+		newTarget("", "foo-v0", 50, false),
+		newTarget("", "foo-v1", 50, false),
+	}, "foo-v2")
+	assert.ErrorContains(t, err, "split between revisions 'foo-v0' and 'foo-v1'")
+}
+
+func TestRolloutStep(t *testing.T) {
+	traffic := []servingv1.TrafficTarget{
+		newTarget("", "foo-v1", 100, false),
+		newTarget("old", "foo-v0", 0, false),
+	}
+	traffic = RolloutStep(traffic, "foo-v1", "foo-v2", 30)
+	assert.DeepEqual(t, traffic, []servingv1.TrafficTarget{
+		newTarget("", "foo-v1", 70, false),
+		newTarget("old", "foo-v0", 0, false),
+		newTarget("", "foo-v2", 30, false),
+	})
+	assert.Equal(t, RolloutPercent(traffic, "foo-v2"), int64(30))
+
+	traffic = RolloutStep(traffic, "foo-v1", "foo-v2", 100)
+	assert.DeepEqual(t, traffic, []servingv1.TrafficTarget{
+		newTarget("old", "foo-v0", 0, false),
+		newTarget("", "foo-v2", 100, false),
+	})
+
+	// Tagged targets of the source are kept
+	traffic = RolloutStep([]servingv1.TrafficTarget{
+		newTarget("stable", "foo-v1", 100, false),
+		newTarget("candidate", "foo-v2", 0, false),
+	}, "foo-v1", "foo-v2", 100)
+	assert.DeepEqual(t, traffic, []servingv1.TrafficTarget{
+		{Tag: "stable", RevisionName: "foo-v1", LatestRevision: ptr.Bool(false), Percent: ptr.Int64(0)},
+		{Tag: "candidate", RevisionName: "foo-v2", LatestRevision: ptr.Bool(false), Percent: ptr.Int64(100)},
+	})
+}
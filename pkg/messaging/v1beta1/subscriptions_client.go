@@ -187,6 +187,26 @@ func (s *SubscriptionBuilder) DeadLetterSink(dls *duckv1.Destination) *Subscript
 	return s
 }
 
+// Retry sets the number of retries and their backoff for the delivery to the subscriber,
+// keeping an already set dead letter sink. Nothing is set for zero retries.
+func (s *SubscriptionBuilder) Retry(retry int32, backoffPolicy eventingduckv1beta1.BackoffPolicyType, backoffDelay string) *SubscriptionBuilder {
+	if retry == 0 {
+		return s
+	}
+
+	if s.subscription.Spec.Delivery == nil {
+		s.subscription.Spec.Delivery = &eventingduckv1beta1.DeliverySpec{}
+	}
+	s.subscription.Spec.Delivery.Retry = &retry
+	if backoffPolicy != "" {
+		s.subscription.Spec.Delivery.BackoffPolicy = &backoffPolicy
+	}
+	if backoffDelay != "" {
+		s.subscription.Spec.Delivery.BackoffDelay = &backoffDelay
+	}
+	return s
+}
+
 // Build returns the Subscription object from the builder
 func (s *SubscriptionBuilder) Build() *v1beta1.Subscription {
 	return s.subscription
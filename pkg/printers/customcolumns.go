@@ -0,0 +1,138 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printers
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// CustomColumnsPrinter prints the values found by JSONPath expressions as columns,
+// like '-o custom-columns=NAME:.metadata.name,URL:.status.url' of kubectl
+type CustomColumnsPrinter struct {
+	headers   []string
+	parsers   []*jsonpath.JSONPath
+	noHeaders bool
+}
+
+var _ ResourcePrinter = &CustomColumnsPrinter{}
+
+// NewCustomColumnsPrinter creates a printer for the comma separated HEADER:JSONPATH columns of spec
+func NewCustomColumnsPrinter(spec string, noHeaders bool) (*CustomColumnsPrinter, error) {
+	if spec == "" {
+		return nil, fmt.Errorf("custom-columns format specified but no custom columns given")
+	}
+	printer := &CustomColumnsPrinter{noHeaders: noHeaders}
+	for _, column := range strings.Split(spec, ",") {
+		parts := strings.SplitN(column, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid custom column '%s', expected HEADER:JSONPATH", column)
+		}
+		parser, err := newJSONPath(parts[0], parts[1])
+		if err != nil {
+			return nil, err
+		}
+		printer.headers = append(printer.headers, parts[0])
+		printer.parsers = append(printer.parsers, parser)
+	}
+	return printer, nil
+}
+
+// PrintObj prints a row for the object, or for each item if it is a list
+func (p *CustomColumnsPrinter) PrintObj(obj runtime.Object, output io.Writer) error {
+	items := []runtime.Object{obj}
+	if meta.IsListType(obj) {
+		var err error
+		items, err = meta.ExtractList(obj)
+		if err != nil {
+			return err
+		}
+	}
+	w := NewTabWriter(output)
+	defer w.Flush()
+	if !p.noHeaders {
+		fmt.Fprintln(w, strings.Join(p.headers, "\t"))
+	}
+	for _, item := range items {
+		content, err := unstructuredContent(item)
+		if err != nil {
+			return err
+		}
+		cells := make([]string, 0, len(p.parsers))
+		for _, parser := range p.parsers {
+			values, err := findValues(parser, content)
+			if err != nil {
+				return err
+			}
+			cell := "<none>"
+			if len(values) > 0 {
+				strs := make([]string, len(values))
+				for i, v := range values {
+					strs[i] = fmt.Sprint(v)
+				}
+				cell = strings.Join(strs, ",")
+			}
+			cells = append(cells, cell)
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	return nil
+}
+
+// newJSONPath parses a JSONPath expression, which may omit the braces and the leading dot
+func newJSONPath(name, expression string) (*jsonpath.JSONPath, error) {
+	if !strings.HasPrefix(expression, "{") {
+		if !strings.HasPrefix(expression, ".") {
+			expression = "." + expression
+		}
+		expression = "{" + expression + "}"
+	}
+	parser := jsonpath.New(name).AllowMissingKeys(true)
+	if err := parser.Parse(expression); err != nil {
+		return nil, fmt.Errorf("invalid JSONPath expression '%s': %v", expression, err)
+	}
+	return parser, nil
+}
+
+// findValues returns the values found by the parser, without nil values
+func findValues(parser *jsonpath.JSONPath, content map[string]interface{}) ([]interface{}, error) {
+	results, err := parser.FindResults(content)
+	if err != nil {
+		return nil, err
+	}
+	var values []interface{}
+	for _, result := range results {
+		for _, value := range result {
+			if !value.IsValid() || (value.Kind() == reflect.Interface || value.Kind() == reflect.Ptr || value.Kind() == reflect.Map || value.Kind() == reflect.Slice) && value.IsNil() {
+				continue
+			}
+			values = append(values, value.Interface())
+		}
+	}
+	return values, nil
+}
+
+func unstructuredContent(obj runtime.Object) (map[string]interface{}, error) {
+	if u, ok := obj.(runtime.Unstructured); ok {
+		return u.UnstructuredContent(), nil
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+}
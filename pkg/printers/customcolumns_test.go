@@ -0,0 +1,56 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printers
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCustomColumnsPrinter(t *testing.T) {
+	list := &corev1.PodList{Items: []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "a", Labels: map[string]string{"app": "web"}},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "user"}, {Name: "queue"}}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+	}}
+
+	printer, err := NewCustomColumnsPrinter("NAME:.metadata.name,APP:metadata.labels.app,CONTAINERS:{.spec.containers[*].name}", false)
+	assert.NilError(t, err)
+	out := new(bytes.Buffer)
+	assert.NilError(t, printer.PrintObj(list, out))
+	assert.Equal(t, out.String(), ""+
+		"NAME   APP      CONTAINERS\n"+
+		"a      web      user,queue\n"+
+		"b      <none>   <none>\n")
+
+	printer, err = NewCustomColumnsPrinter("NAME:.metadata.name", true)
+	assert.NilError(t, err)
+	out.Reset()
+	assert.NilError(t, printer.PrintObj(&list.Items[1], out))
+	assert.Equal(t, out.String(), "b\n")
+}
+
+func TestCustomColumnsPrinterInvalid(t *testing.T) {
+	_, err := NewCustomColumnsPrinter("", false)
+	assert.ErrorContains(t, err, "no custom columns given")
+	_, err = NewCustomColumnsPrinter("NAME:.metadata.name,:.status", false)
+	assert.ErrorContains(t, err, "invalid custom column ':.status'")
+	_, err = NewCustomColumnsPrinter("NAME:{.metadata.name", false)
+	assert.ErrorContains(t, err, "invalid JSONPath expression '{.metadata.name'")
+}
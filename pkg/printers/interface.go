@@ -41,4 +41,15 @@ type PrintOptions struct {
 	NoHeaders bool
 	//TODO: Add options for eg: with-kind, server-printing, wide etc
 	AllNamespaces bool
+	// NoTruncate prints cells in full, even if the table is wider than the terminal
+	NoTruncate bool
+	// MaxWidth is the width to which tables are fit. If 0, the width of the
+	// terminal is used, and output which doesn't go to a terminal is never truncated.
+	MaxWidth int
+	// Wide adds the columns with priority WidePriority
+	Wide bool
 }
+
+// WidePriority is the priority of columns which are only shown with '-o wide'.
+// Columns with priority 0 are only shown for all namespaces, with 1 always.
+const WidePriority = 2
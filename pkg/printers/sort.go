@@ -0,0 +1,88 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printers
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// SortObjects sorts the items of a list by the value found by the JSONPath expression
+// field, e.g. '.metadata.creationTimestamp'. Numbers are compared by value, and items
+// without a value come last. The sort is stable.
+func SortObjects(list runtime.Object, field string) error {
+	parser, err := newJSONPath("sort-by", field)
+	if err != nil {
+		return err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	keys := make([]interface{}, len(items))
+	for i, item := range items {
+		content, err := unstructuredContent(item)
+		if err != nil {
+			return err
+		}
+		values, err := findValues(parser, content)
+		if err != nil {
+			return err
+		}
+		if len(values) > 0 {
+			keys[i] = values[0]
+		}
+	}
+	indexes := make([]int, len(items))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return lessValue(keys[indexes[i]], keys[indexes[j]])
+	})
+	sorted := make([]runtime.Object, len(items))
+	for i, index := range indexes {
+		sorted[i] = items[index]
+	}
+	return meta.SetList(list, sorted)
+}
+
+// lessValue compares numbers by value and everything else by its string representation
+func lessValue(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a != nil
+	}
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			return x < y
+		}
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	}
+	return 0, false
+}
@@ -0,0 +1,48 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printers
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSortObjects(t *testing.T) {
+	newPod := func(name string, priority *int32) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: corev1.PodSpec{Priority: priority}}
+	}
+	priority := func(p int32) *int32 { return &p }
+	list := &corev1.PodList{Items: []corev1.Pod{
+		newPod("c", priority(10)), newPod("none", nil), newPod("a", priority(9)), newPod("b", priority(10)),
+	}}
+	names := func() []string {
+		var names []string
+		for _, pod := range list.Items {
+			names = append(names, pod.Name)
+		}
+		return names
+	}
+
+	assert.NilError(t, SortObjects(list, ".spec.priority"))
+	assert.DeepEqual(t, names(), []string{"a", "c", "b", "none"})
+	assert.NilError(t, SortObjects(list, "metadata.name"))
+	assert.DeepEqual(t, names(), []string{"a", "b", "c", "none"})
+
+	assert.ErrorContains(t, SortObjects(list, "{.spec"), "invalid JSONPath expression")
+	assert.Assert(t, SortObjects(&list.Items[0], ".metadata.name") != nil, "not a list")
+}
@@ -0,0 +1,52 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printers
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Settings for deterministic human readable output, e.g. for golden file tests
+// of scripts and docs. They are set by the global flags --no-timestamps and
+// --stable-output.
+var (
+	// NoTimestamps suppresses durations, ages and timestamps
+	NoTimestamps bool
+
+	// StableOutput additionally suppresses output which depends on timing, like
+	// the progress messages while waiting for a resource to become ready
+	StableOutput bool
+)
+
+// TimestampPlaceholder is printed instead of an age or timestamp when timestamps
+// are suppressed
+const TimestampPlaceholder = "-"
+
+// OmitTimestamps returns true if durations, ages and timestamps must not be printed
+func OmitTimestamps() bool {
+	return NoTimestamps || StableOutput
+}
+
+// WriteElapsed writes a progress message prefixed with the time elapsed since
+// an operation started, or only the message when timestamps are suppressed
+func WriteElapsed(out io.Writer, elapsed time.Duration, message string) {
+	if OmitTimestamps() {
+		fmt.Fprintln(out, message)
+		return
+	}
+	fmt.Fprintf(out, "%7.3fs %s\n", float64(elapsed.Round(time.Millisecond))/float64(time.Second), message)
+}
@@ -0,0 +1,45 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printers
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestWriteElapsed(t *testing.T) {
+	defer func(noTimestamps, stableOutput bool) {
+		NoTimestamps, StableOutput = noTimestamps, stableOutput
+	}(NoTimestamps, StableOutput)
+
+	for _, tc := range []struct {
+		noTimestamps bool
+		stableOutput bool
+		expected     string
+	}{
+		{false, false, "  1.235s Ready to serve.\n"},
+		{true, false, "Ready to serve.\n"},
+		{false, true, "Ready to serve.\n"},
+	} {
+		NoTimestamps, StableOutput = tc.noTimestamps, tc.stableOutput
+		out := new(bytes.Buffer)
+		WriteElapsed(out, 1234567*time.Microsecond, "Ready to serve.")
+		assert.Equal(t, out.String(), tc.expected)
+		assert.Equal(t, OmitTimestamps(), tc.noTimestamps || tc.stableOutput)
+	}
+}
@@ -45,6 +45,14 @@ func (h *HumanReadablePrinter) PrintObj(obj runtime.Object, output io.Writer) er
 		return nil
 	}
 
+	width := 0
+	if !h.options.NoTruncate {
+		width = h.options.MaxWidth
+		if width == 0 {
+			width = TerminalWidth(output)
+		}
+	}
+
 	if _, found := output.(*tabwriter.Writer); !found {
 		w := NewTabWriter(output)
 		output = w
@@ -55,7 +63,7 @@ func (h *HumanReadablePrinter) PrintObj(obj runtime.Object, output io.Writer) er
 	t := reflect.TypeOf(obj)
 	if handler := h.handlerMap[t]; handler != nil {
 
-		if err := printRowsForHandlerEntry(output, handler, obj, h.options); err != nil {
+		if err := printRowsForHandlerEntry(output, handler, obj, h.options, width); err != nil {
 			return err
 		}
 		return nil
@@ -66,9 +74,9 @@ func (h *HumanReadablePrinter) PrintObj(obj runtime.Object, output io.Writer) er
 }
 
 // printRowsForHandlerEntry prints the incremental table output
-// including all the rows in the object. It returns the current type
-// or an error, if any.
-func printRowsForHandlerEntry(output io.Writer, handler *handlerEntry, obj runtime.Object, options PrintOptions) error {
+// including all the rows in the object, with cells truncated to fit
+// into the given width. It returns the current type or an error, if any.
+func printRowsForHandlerEntry(output io.Writer, handler *handlerEntry, obj runtime.Object, options PrintOptions, width int) error {
 	var results []reflect.Value
 
 	args := []reflect.Value{reflect.ValueOf(obj), reflect.ValueOf(options)}
@@ -77,23 +85,24 @@ func printRowsForHandlerEntry(output io.Writer, handler *handlerEntry, obj runti
 		return results[1].Interface().(error)
 	}
 
-	if !options.NoHeaders {
-		var headers []string
-		for _, column := range handler.columnDefinitions {
-			if !options.AllNamespaces && column.Priority == 0 {
-				continue
-			}
-			headers = append(headers, strings.ToUpper(column.Name))
+	var headers []string
+	for _, column := range handler.columnDefinitions {
+		if !options.AllNamespaces && column.Priority == 0 {
+			continue
 		}
-		printHeader(headers, output)
+		if !options.Wide && column.Priority == WidePriority {
+			continue
+		}
+		headers = append(headers, strings.ToUpper(column.Name))
 	}
+	rows := rowsToCells(results[0].Interface().([]metav1beta1.TableRow))
+	fitToWidth(headers, rows, width)
 
-	if results[1].IsNil() {
-		rows := results[0].Interface().([]metav1beta1.TableRow)
-		printRows(output, rows, options)
-		return nil
+	if !options.NoHeaders {
+		printHeader(headers, output)
 	}
-	return results[1].Interface().(error)
+	printRows(output, rows)
+	return nil
 }
 
 func printHeader(columnNames []string, w io.Writer) error {
@@ -103,15 +112,23 @@ func printHeader(columnNames []string, w io.Writer) error {
 	return nil
 }
 
-// printRows writes the provided rows to output.
-func printRows(output io.Writer, rows []metav1beta1.TableRow, options PrintOptions) {
+// rowsToCells converts the cells of all rows to strings
+func rowsToCells(rows []metav1beta1.TableRow) [][]string {
+	ret := make([][]string, 0, len(rows))
 	for _, row := range rows {
-		for i, cell := range row.Cells {
-			if i != 0 {
-				fmt.Fprint(output, "\t")
-			}
-			fmt.Fprint(output, cell)
+		cells := make([]string, 0, len(row.Cells))
+		for _, cell := range row.Cells {
+			cells = append(cells, fmt.Sprint(cell))
 		}
+		ret = append(ret, cells)
+	}
+	return ret
+}
+
+// printRows writes the provided rows to output.
+func printRows(output io.Writer, rows [][]string) {
+	for _, row := range rows {
+		fmt.Fprint(output, strings.Join(row, "\t"))
 		output.Write([]byte("\n"))
 	}
 }
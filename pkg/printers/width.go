@@ -0,0 +1,129 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printers
+
+import (
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const (
+	// truncateMinWidth is the width down to which a column is shrunk
+	// when the table does not fit, unless its header is wider
+	truncateMinWidth = 12
+	ellipsis         = "…"
+)
+
+// TerminalWidth returns the number of columns of the terminal the output is
+// connected to, or 0 when the output is not an interactive terminal
+func TerminalWidth(output io.Writer) int {
+	file, ok := output.(*os.File)
+	if !ok || !terminal.IsTerminal(int(file.Fd())) {
+		return 0
+	}
+	width, _, err := terminal.GetSize(int(file.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// fitToWidth truncates the cells of a table so that the aligned table does
+// not exceed the given width. The widest columns are shrunk first, and
+// headers are never truncated. A width of 0 leaves the table untouched.
+func fitToWidth(headers []string, rows [][]string, width int) {
+	if width <= 0 {
+		return
+	}
+	columns := columnWidths(headers, rows)
+	minimum := make([]int, len(columns))
+	for i, w := range columns {
+		minimum[i] = truncateMinWidth
+		if i < len(headers) && utf8.RuneCountInString(headers[i]) > minimum[i] {
+			minimum[i] = utf8.RuneCountInString(headers[i])
+		}
+		if w < minimum[i] {
+			minimum[i] = w
+		}
+	}
+
+	for tableWidth(columns) > width {
+		widest := -1
+		for i, w := range columns {
+			if w > minimum[i] && (widest < 0 || w > columns[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			// Can't shrink any further, the table stays wider than the terminal
+			break
+		}
+		columns[widest]--
+	}
+
+	for _, row := range rows {
+		for i, cell := range row {
+			row[i] = truncate(cell, columns[i])
+		}
+	}
+}
+
+func columnWidths(headers []string, rows [][]string) []int {
+	var widths []int
+	update := func(i int, cell string) {
+		for len(widths) <= i {
+			widths = append(widths, 0)
+		}
+		if w := utf8.RuneCountInString(cell); w > widths[i] {
+			widths[i] = w
+		}
+	}
+	for i, header := range headers {
+		update(i, header)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			update(i, cell)
+		}
+	}
+	return widths
+}
+
+// tableWidth returns the width of a table as aligned by the tab writer,
+// which pads all but the last column
+func tableWidth(columns []int) int {
+	width := 0
+	for i, w := range columns {
+		if i < len(columns)-1 {
+			width += tabwriterPadding
+			if w+tabwriterPadding < tabwriterMinWidth {
+				w = tabwriterMinWidth - tabwriterPadding
+			}
+		}
+		width += w
+	}
+	return width
+}
+
+func truncate(cell string, width int) string {
+	if utf8.RuneCountInString(cell) <= width {
+		return cell
+	}
+	runes := []rune(cell)
+	return string(runes[:width-1]) + ellipsis
+}
@@ -0,0 +1,101 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printers
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+)
+
+func TestFitToWidth(t *testing.T) {
+	headers := []string{"NAME", "URL", "READY"}
+	newRows := func() [][]string {
+		return [][]string{
+			{"hello", "http://hello.default.example.com", "True"},
+			{"a-very-long-service-name", "http://a-very-long-service-name.default.example.com", "False"},
+		}
+	}
+
+	// Fits, nothing to do
+	rows := newRows()
+	fitToWidth(headers, rows, 200)
+	assert.DeepEqual(t, rows, newRows())
+
+	// No width, never truncate
+	rows = newRows()
+	fitToWidth(headers, rows, 0)
+	assert.DeepEqual(t, rows, newRows())
+
+	// Widest column is shrunk first
+	rows = newRows()
+	fitToWidth(headers, rows, 70)
+	assert.DeepEqual(t, rows, [][]string{
+		{"hello", "http://hello.default.example.com", "True"},
+		{"a-very-long-service-name", "http://a-very-long-service-name.de…", "False"},
+	})
+	assert.Equal(t, tableWidth(columnWidths(headers, rows)), 70)
+
+	// Columns are not shrunk below their minimum width
+	rows = newRows()
+	fitToWidth(headers, rows, 10)
+	assert.DeepEqual(t, rows, [][]string{
+		{"hello", "http://hell…", "True"},
+		{"a-very-long…", "http://a-ve…", "False"},
+	})
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, truncate("hello", 5), "hello")
+	assert.Equal(t, truncate("hello", 4), "hel…")
+	assert.Equal(t, truncate("grüße", 4), "grü…")
+}
+
+func TestPrintObjTruncate(t *testing.T) {
+	columns := []metav1beta1.TableColumnDefinition{
+		{Name: "Name", Type: "string", Priority: 1},
+		{Name: "Data", Type: "string", Priority: 1},
+	}
+	printConfigMap := func(cm *corev1.ConfigMap, options PrintOptions) ([]metav1beta1.TableRow, error) {
+		return []metav1beta1.TableRow{{Cells: []interface{}{cm.Name, cm.Data["value"]}}}, nil
+	}
+	cm := &corev1.ConfigMap{Data: map[string]string{"value": strings.Repeat("x", 40)}}
+	cm.Name = "config"
+
+	for _, tc := range []struct {
+		options  PrintOptions
+		expected string
+	}{
+		{PrintOptions{}, "config   " + strings.Repeat("x", 40)},
+		{PrintOptions{MaxWidth: 30}, "config   " + strings.Repeat("x", 20) + "…"},
+		{PrintOptions{MaxWidth: 30, NoTruncate: true}, "config   " + strings.Repeat("x", 40)},
+	} {
+		printer := NewTablePrinter(tc.options)
+		assert.NilError(t, printer.TableHandler(columns, printConfigMap))
+		out := new(bytes.Buffer)
+		assert.NilError(t, printer.PrintObj(cm, out))
+		lines := strings.Split(out.String(), "\n")
+		assert.Equal(t, strings.TrimSpace(lines[0]), "NAME     DATA")
+		assert.Equal(t, lines[1], tc.expected)
+	}
+}
+
+func TestTerminalWidthNoTerminal(t *testing.T) {
+	assert.Equal(t, TerminalWidth(new(bytes.Buffer)), 0)
+}
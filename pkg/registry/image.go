@@ -0,0 +1,212 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// defaultPlatform is the platform whose image is read from multi-platform images
+// when no platform is given, like the container tools do
+const defaultPlatform = "linux/amd64"
+
+// Manifests larger than this are rejected, real ones are a few KB
+const maxManifestSize = 4 << 20
+
+// ImageConfig is the part of the image configuration which determines how
+// the container is run
+type ImageConfig struct {
+	User         string              `json:"User,omitempty"`
+	ExposedPorts map[string]struct{} `json:"ExposedPorts,omitempty"`
+	Env          []string            `json:"Env,omitempty"`
+	Entrypoint   []string            `json:"Entrypoint,omitempty"`
+	Cmd          []string            `json:"Cmd,omitempty"`
+	WorkingDir   string              `json:"WorkingDir,omitempty"`
+}
+
+// configFile is the configuration blob of an image
+type configFile struct {
+	OS           string      `json:"os"`
+	Architecture string      `json:"architecture"`
+	Variant      string      `json:"variant,omitempty"`
+	Config       ImageConfig `json:"config"`
+}
+
+// manifest is the part of an image index, manifest list or image manifest which is needed
+type manifest struct {
+	Manifests []struct {
+		Digest   string    `json:"digest"`
+		Platform *imagePlatform `json:"platform,omitempty"`
+	} `json:"manifests,omitempty"`
+	Config *struct {
+		Digest string `json:"digest"`
+	} `json:"config,omitempty"`
+}
+
+type imagePlatform struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// matches compares the platform with one given as OS/ARCH[/VARIANT], the variant only if given
+func (p *imagePlatform) matches(want string) bool {
+	parts := strings.Split(want, "/")
+	if len(parts) < 2 || p.OS != parts[0] || p.Architecture != parts[1] {
+		return false
+	}
+	return len(parts) < 3 || p.Variant == parts[2]
+}
+
+func (p *imagePlatform) String() string {
+	if p.Variant != "" {
+		return p.OS + "/" + p.Architecture + "/" + p.Variant
+	}
+	return p.OS + "/" + p.Architecture
+}
+
+// PlatformDigest resolves the image reference to the digest of the image for the platform
+// with the default client, can be replaced in tests
+var PlatformDigest = func(image string, platform string) (string, error) {
+	return NewClient().PlatformDigest(image, platform)
+}
+
+// Config reads the configuration of the image for the platform with the default client,
+// can be replaced in tests
+var Config = func(image string, platform string) (*ImageConfig, error) {
+	return NewClient().Config(image, platform)
+}
+
+// PlatformDigest resolves the image reference to the digest of the image built for the platform,
+// given as OS/ARCH[/VARIANT] like "linux/arm64". For multi-platform images this is the digest of
+// the platform's manifest instead of the digest of the index. Single-platform images are checked
+// to be built for the platform.
+func (c *Client) PlatformDigest(image string, platform string) (string, error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+	digest, _, err := c.platformManifest(ref, platform)
+	return digest, err
+}
+
+// Config reads the configuration of the image built for the platform. The platform is
+// linux/amd64 if not given.
+func (c *Client) Config(image string, platform string) (*ImageConfig, error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return nil, err
+	}
+	_, config, err := c.platformManifest(ref, platform)
+	if err != nil {
+		return nil, err
+	}
+	return &config.Config, nil
+}
+
+// platformManifest returns the digest and configuration of the image for the platform,
+// looked up in the image index if the reference points to one
+func (c *Client) platformManifest(ref *Reference, platform string) (string, *configFile, error) {
+	if platform == "" {
+		platform = defaultPlatform
+	}
+	digest, m, err := c.manifest(ref, ref.reference())
+	if err != nil {
+		return "", nil, err
+	}
+	if len(m.Manifests) > 0 {
+		var available []string
+		digest = ""
+		for _, entry := range m.Manifests {
+			if entry.Platform == nil {
+				continue
+			}
+			if entry.Platform.matches(platform) {
+				digest = entry.Digest
+				break
+			}
+			available = append(available, entry.Platform.String())
+		}
+		if digest == "" {
+			return "", nil, fmt.Errorf("image %s is not built for platform %s, only for %s", ref, platform, strings.Join(available, ", "))
+		}
+		_, m, err = c.manifest(ref, digest)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	if m.Config == nil {
+		return "", nil, fmt.Errorf("manifest of image %s has no configuration", ref)
+	}
+	config, err := c.configFile(ref, m.Config.Digest)
+	if err != nil {
+		return "", nil, err
+	}
+	built := &imagePlatform{OS: config.OS, Architecture: config.Architecture, Variant: config.Variant}
+	if !built.matches(platform) {
+		return "", nil, fmt.Errorf("image %s is built for platform %s, not for %s", ref, built, platform)
+	}
+	return digest, config, nil
+}
+
+// manifest fetches the manifest with the tag or digest and returns its digest
+func (c *Client) manifest(ref *Reference, reference string) (string, *manifest, error) {
+	manifestURL := fmt.Sprintf("%s://%s/v2/%s/manifests/%s", ref.scheme(), ref.host(), ref.Repository, reference)
+	data, err := c.read(ref, manifestURL, "manifest")
+	if err != nil {
+		return "", nil, err
+	}
+	m := &manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return "", nil, fmt.Errorf("cannot parse manifest of image %s: %v", ref, err)
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data)), m, nil
+}
+
+// configFile fetches the configuration blob of the image
+func (c *Client) configFile(ref *Reference, digest string) (*configFile, error) {
+	blobURL := fmt.Sprintf("%s://%s/v2/%s/blobs/%s", ref.scheme(), ref.host(), ref.Repository, digest)
+	data, err := c.read(ref, blobURL, "configuration")
+	if err != nil {
+		return nil, err
+	}
+	config := &configFile{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("cannot parse configuration of image %s: %v", ref, err)
+	}
+	return config, nil
+}
+
+func (c *Client) read(ref *Reference, objectURL string, what string) ([]byte, error) {
+	resp, err := c.get(ref, http.MethodGet, objectURL, what)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s of image %s: %v", what, ref, err)
+	}
+	if len(data) > maxManifestSize {
+		return nil, fmt.Errorf("%s of image %s is larger than %d bytes", what, ref, maxManifestSize)
+	}
+	return data, nil
+}
@@ -0,0 +1,103 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func digestOf(data string) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(data)))
+}
+
+// newMultiPlatformRegistry serves foo/bar:v1 as index of a linux/amd64 and a linux/arm64 image
+// and foo/single:v1 as image for linux/amd64 only
+func newMultiPlatformRegistry(t *testing.T) (*httptest.Server, map[string]string) {
+	amd64Config := `{"os":"linux","architecture":"amd64","config":{"User":"1000","ExposedPorts":{"8080/tcp":{}},"Entrypoint":["/app"]}}`
+	arm64Config := `{"os":"linux","architecture":"arm64","variant":"v8","config":{"Entrypoint":["/app-arm"]}}`
+	amd64Manifest := fmt.Sprintf(`{"schemaVersion":2,"config":{"digest":"%s"}}`, digestOf(amd64Config))
+	arm64Manifest := fmt.Sprintf(`{"schemaVersion":2,"config":{"digest":"%s"}}`, digestOf(arm64Config))
+	index := fmt.Sprintf(`{"schemaVersion":2,"manifests":[`+
+		`{"digest":"%s","platform":{"os":"linux","architecture":"amd64"}},`+
+		`{"digest":"%s","platform":{"os":"linux","architecture":"arm64","variant":"v8"}}]}`,
+		digestOf(amd64Manifest), digestOf(arm64Manifest))
+	objects := map[string]string{
+		"/v2/foo/bar/manifests/v1":                         index,
+		"/v2/foo/bar/manifests/" + digestOf(amd64Manifest): amd64Manifest,
+		"/v2/foo/bar/manifests/" + digestOf(arm64Manifest): arm64Manifest,
+		"/v2/foo/bar/blobs/" + digestOf(amd64Config):       amd64Config,
+		"/v2/foo/bar/blobs/" + digestOf(arm64Config):       arm64Config,
+		"/v2/foo/single/manifests/v1":                      amd64Manifest,
+		"/v2/foo/single/blobs/" + digestOf(amd64Config):    amd64Config,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		object, ok := objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, object)
+	}))
+	return server, map[string]string{"amd64": digestOf(amd64Manifest), "arm64": digestOf(arm64Manifest)}
+}
+
+func TestPlatformDigest(t *testing.T) {
+	server, digests := newMultiPlatformRegistry(t)
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+	client := &Client{HTTPClient: server.Client(), Keychain: staticKeychain{}}
+
+	digest, err := client.PlatformDigest(host+"/foo/bar:v1", "linux/arm64")
+	assert.NilError(t, err)
+	assert.Equal(t, digest, digests["arm64"])
+	digest, err = client.PlatformDigest(host+"/foo/bar:v1", "linux/arm64/v8")
+	assert.NilError(t, err)
+	assert.Equal(t, digest, digests["arm64"])
+
+	digest, err = client.PlatformDigest(host+"/foo/single:v1", "linux/amd64")
+	assert.NilError(t, err)
+	assert.Equal(t, digest, digests["amd64"])
+
+	_, err = client.PlatformDigest(host+"/foo/bar:v1", "linux/s390x")
+	assert.ErrorContains(t, err, "is not built for platform linux/s390x, only for linux/amd64, linux/arm64/v8")
+	_, err = client.PlatformDigest(host+"/foo/single:v1", "linux/arm64")
+	assert.ErrorContains(t, err, "is built for platform linux/amd64, not for linux/arm64")
+}
+
+func TestConfig(t *testing.T) {
+	server, digests := newMultiPlatformRegistry(t)
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+	client := &Client{HTTPClient: server.Client(), Keychain: staticKeychain{}}
+
+	// linux/amd64 by default
+	config, err := client.Config(host+"/foo/bar:v1", "")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, config, &ImageConfig{User: "1000", ExposedPorts: map[string]struct{}{"8080/tcp": {}}, Entrypoint: []string{"/app"}})
+
+	config, err = client.Config(host+"/foo/bar@"+digests["arm64"], "linux/arm64")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, config.Entrypoint, []string{"/app-arm"})
+
+	_, err = client.Config(host+"/foo/baz:v1", "")
+	assert.ErrorContains(t, err, "cannot get manifest of image "+host+"/foo/baz:v1: 404 Not Found")
+}
@@ -0,0 +1,166 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// dockerHubAuthKey is the key of Docker Hub's credentials in the Docker configuration
+const dockerHubAuthKey = "https://index.docker.io/v1/"
+
+// Credentials for authenticating with a registry
+type Credentials struct {
+	Username string
+	Password string
+}
+
+func (c *Credentials) basicAuth() string {
+	return base64.StdEncoding.EncodeToString([]byte(c.Username + ":" + c.Password))
+}
+
+// Keychain looks up the credentials for a registry
+type Keychain interface {
+	// Credentials returns the credentials for the registry host, or nil for anonymous access
+	Credentials(registry string) (*Credentials, error)
+}
+
+// dockerKeychain reads the credentials from the Docker configuration
+type dockerKeychain struct {
+	configFile string
+}
+
+// dockerConfig is the part of the Docker configuration which holds the credentials
+type dockerConfig struct {
+	Auths map[string]struct {
+		Auth     string `json:"auth"`
+		Username string `json:"username"`
+		Password string `json:"password"`
+	} `json:"auths"`
+	CredsStore  string            `json:"credsStore"`
+	CredHelpers map[string]string `json:"credHelpers"`
+}
+
+// NewDockerKeychain returns a keychain which reads config.json of the directory given with
+// $DOCKER_CONFIG, or of ~/.docker. The configuration is read for each lookup, a missing
+// configuration means anonymous access.
+func NewDockerKeychain() Keychain {
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".docker")
+	}
+	return &dockerKeychain{configFile: filepath.Join(dir, "config.json")}
+}
+
+// Credentials looks up the registry in the credential helpers, the inline credentials
+// and finally the credential store
+func (k *dockerKeychain) Credentials(registry string) (*Credentials, error) {
+	content, err := ioutil.ReadFile(k.configFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var config dockerConfig
+	err = json.Unmarshal(content, &config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse Docker configuration %s: %v", k.configFile, err)
+	}
+
+	serverURL := registry
+	if registry == DockerHub || registry == "index.docker.io" {
+		serverURL = dockerHubAuthKey
+	}
+	if helper, ok := config.CredHelpers[registry]; ok {
+		return credentialHelperCredentials(helper, serverURL)
+	}
+	for key, auth := range config.Auths {
+		if key != serverURL && authKeyHost(key) != registry {
+			continue
+		}
+		if auth.Auth == "" {
+			return &Credentials{Username: auth.Username, Password: auth.Password}, nil
+		}
+		decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode credentials for %s in %s: %v", key, k.configFile, err)
+		}
+		parts := strings.SplitN(string(decoded), ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid credentials for %s in %s", key, k.configFile)
+		}
+		return &Credentials{Username: parts[0], Password: parts[1]}, nil
+	}
+	if config.CredsStore != "" {
+		return credentialHelperCredentials(config.CredsStore, serverURL)
+	}
+	return nil, nil
+}
+
+// authKeyHost returns the host of a key of the "auths" section, which can be a URL
+func authKeyHost(key string) string {
+	if i := strings.Index(key, "://"); i >= 0 {
+		key = key[i+3:]
+	}
+	if i := strings.Index(key, "/"); i >= 0 {
+		key = key[:i]
+	}
+	return key
+}
+
+// runCredentialHelper runs docker-credential-HELPER with the given command and input,
+// can be replaced in tests
+var runCredentialHelper = func(helper string, command string, input string) ([]byte, error) {
+	cmd := exec.Command("docker-credential-"+helper, command)
+	cmd.Stdin = strings.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("credential helper docker-credential-%s failed: %v %s", helper, err, strings.TrimSpace(stderr.String()+string(output)))
+	}
+	return output, nil
+}
+
+// credentialHelperCredentials gets the credentials of the server from the credential helper,
+// a server unknown to the helper means anonymous access
+func credentialHelperCredentials(helper string, serverURL string) (*Credentials, error) {
+	output, err := runCredentialHelper(helper, "get", serverURL)
+	if err != nil {
+		if strings.Contains(err.Error(), "credentials not found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var response struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+	err = json.Unmarshal(output, &response)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse output of credential helper docker-credential-%s: %v", helper, err)
+	}
+	return &Credentials{Username: response.Username, Password: response.Secret}, nil
+}
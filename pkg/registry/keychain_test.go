@@ -0,0 +1,86 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func newTestKeychain(t *testing.T, config string) (Keychain, func()) {
+	dir, err := ioutil.TempDir("", "docker-config")
+	assert.NilError(t, err)
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "config.json"), []byte(config), 0600))
+	os.Setenv("DOCKER_CONFIG", dir)
+	keychain := NewDockerKeychain()
+	os.Unsetenv("DOCKER_CONFIG")
+	return keychain, func() { os.RemoveAll(dir) }
+}
+
+func TestDockerKeychain(t *testing.T) {
+	oldRun := runCredentialHelper
+	defer func() { runCredentialHelper = oldRun }()
+	runCredentialHelper = func(helper string, command string, input string) ([]byte, error) {
+		assert.Equal(t, command, "get")
+		if helper == "gcloud" && input == "gcr.io" {
+			return []byte(`{"Username":"_token","Secret":"abc"}`), nil
+		}
+		if helper == "desktop" && input == "https://index.docker.io/v1/" {
+			return []byte(`{"Username":"hub","Secret":"def"}`), nil
+		}
+		return nil, errors.New("credentials not found in native keychain")
+	}
+
+	keychain, cleanup := newTestKeychain(t, `{
+		"auths": {
+			"https://quay.io/v1/": {"auth": "cXVheTpzZWNyZXQ="},
+			"localhost:5000": {"username": "local", "password": "pw"}
+		},
+		"credHelpers": {"gcr.io": "gcloud"},
+		"credsStore": "desktop"
+	}`)
+	defer cleanup()
+
+	for registry, expected := range map[string]*Credentials{
+		"gcr.io":         {Username: "_token", Password: "abc"},
+		"quay.io":        {Username: "quay", Password: "secret"},
+		"localhost:5000": {Username: "local", Password: "pw"},
+		"docker.io":      {Username: "hub", Password: "def"},
+		"example.com":    nil,
+	} {
+		credentials, err := keychain.Credentials(registry)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, credentials, expected)
+	}
+}
+
+func TestDockerKeychainWithoutConfig(t *testing.T) {
+	keychain := &dockerKeychain{configFile: filepath.Join(os.TempDir(), "does-not-exist", "config.json")}
+	credentials, err := keychain.Credentials("gcr.io")
+	assert.NilError(t, err)
+	assert.Assert(t, credentials == nil)
+}
+
+func TestDockerKeychainInvalidConfig(t *testing.T) {
+	keychain, cleanup := newTestKeychain(t, `{"auths": {"gcr.io": {"auth": "bm9jb2xvbg=="}}}`)
+	defer cleanup()
+	_, err := keychain.Credentials("gcr.io")
+	assert.ErrorContains(t, err, "invalid credentials for gcr.io")
+}
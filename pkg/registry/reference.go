@@ -0,0 +1,123 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DockerHub is the registry of images without a registry host
+	DockerHub = "docker.io"
+
+	// dockerHubHost serves the registry API of Docker Hub
+	dockerHubHost = "registry-1.docker.io"
+
+	defaultTag = "latest"
+)
+
+// Reference is a parsed image reference like "gcr.io/foo/bar:v1"
+type Reference struct {
+	// Registry is the host (and port) of the registry, DockerHub by default
+	Registry string
+	// Repository is the path of the image in the registry, with the "library/"
+	// prefix added for Docker Hub's official images
+	Repository string
+	// Tag of the image, "latest" if neither a tag nor a digest is given
+	Tag string
+	// Digest of the image if given
+	Digest string
+}
+
+// ParseReference parses an image reference of the form [REGISTRY/]REPOSITORY[:TAG][@DIGEST].
+// The first path component is the registry if it contains a '.' or ':' or is "localhost".
+func ParseReference(image string) (*Reference, error) {
+	ref := &Reference{}
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, ref.Digest = name[:i], name[i+1:]
+		if !strings.Contains(ref.Digest, ":") {
+			return nil, fmt.Errorf("invalid digest in image reference '%s'", image)
+		}
+	}
+	// A colon before the last slash separates the registry's port
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ref.Tag = name[:i], name[i+1:]
+		if ref.Tag == "" {
+			return nil, fmt.Errorf("empty tag in image reference '%s'", image)
+		}
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = defaultTag
+	}
+
+	ref.Registry = DockerHub
+	if i := strings.Index(name, "/"); i >= 0 {
+		first := name[:i]
+		if strings.ContainsAny(first, ".:") || first == "localhost" {
+			ref.Registry, name = first, name[i+1:]
+		}
+	}
+	if name == "" || name != strings.ToLower(name) || strings.Contains("/"+name+"/", "//") {
+		return nil, fmt.Errorf("invalid repository in image reference '%s'", image)
+	}
+	if ref.Registry == DockerHub && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	ref.Repository = name
+	return ref, nil
+}
+
+// host returns the host serving the registry API
+func (r *Reference) host() string {
+	if r.Registry == DockerHub || r.Registry == "index.docker.io" {
+		return dockerHubHost
+	}
+	return r.Registry
+}
+
+// scheme returns "http" for registries on the local machine, which usually don't have
+// certificates, and "https" for all others
+func (r *Reference) scheme() string {
+	host := r.Registry
+	if i := strings.LastIndex(host, ":"); i >= 0 {
+		host = host[:i]
+	}
+	if host == "localhost" || host == "127.0.0.1" || strings.HasSuffix(host, ".local") {
+		return "http"
+	}
+	return "https"
+}
+
+// String returns the normalized reference
+func (r *Reference) String() string {
+	image := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		image += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		image += "@" + r.Digest
+	}
+	return image
+}
+
+// reference returns the digest if given and the tag otherwise, for fetching the manifest
+func (r *Reference) reference() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+	return r.Tag
+}
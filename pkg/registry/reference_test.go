@@ -0,0 +1,57 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestParseReference(t *testing.T) {
+	for _, tc := range []struct {
+		image    string
+		expected Reference
+	}{
+		{"nginx", Reference{Registry: "docker.io", Repository: "library/nginx", Tag: "latest"}},
+		{"knativesamples/helloworld:v1", Reference{Registry: "docker.io", Repository: "knativesamples/helloworld", Tag: "v1"}},
+		{"gcr.io/foo/bar:baz", Reference{Registry: "gcr.io", Repository: "foo/bar", Tag: "baz"}},
+		{"localhost:5000/bar", Reference{Registry: "localhost:5000", Repository: "bar", Tag: "latest"}},
+		{"localhost/bar@sha256:abc", Reference{Registry: "localhost", Repository: "bar", Digest: "sha256:abc"}},
+		{"gcr.io/foo/bar:baz@sha256:abc", Reference{Registry: "gcr.io", Repository: "foo/bar", Tag: "baz", Digest: "sha256:abc"}},
+	} {
+		ref, err := ParseReference(tc.image)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, *ref, tc.expected)
+	}
+}
+
+func TestParseReferenceInvalid(t *testing.T) {
+	for _, image := range []string{"", "gcr.io/", "gcr.io/Foo", "foo:", "foo@abc", "gcr.io//foo"} {
+		_, err := ParseReference(image)
+		assert.ErrorContains(t, err, "image reference", image)
+	}
+}
+
+func TestReferenceHostAndScheme(t *testing.T) {
+	ref, _ := ParseReference("nginx")
+	assert.Equal(t, ref.host(), "registry-1.docker.io")
+	assert.Equal(t, ref.scheme(), "https")
+	assert.Equal(t, ref.String(), "docker.io/library/nginx:latest")
+
+	ref, _ = ParseReference("127.0.0.1:5000/foo:v1")
+	assert.Equal(t, ref.host(), "127.0.0.1:5000")
+	assert.Equal(t, ref.scheme(), "http")
+}
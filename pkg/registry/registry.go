@@ -0,0 +1,243 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package registry resolves image tags to digests and reads the configuration of
+// images with the HTTP API of container registries. Credentials are read from the
+// Docker configuration like the container tools do, including credential helpers.
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Media types of the manifests which are accepted, image indexes and manifest lists
+// come first so that the digest of a multi-platform image is the digest of its index
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// Client reads manifests from registries
+type Client struct {
+	HTTPClient *http.Client
+	Keychain   Keychain
+
+	// Authorization headers by repository, as registries issue tokens per repository
+	authorizations map[string]string
+}
+
+// NewClient returns a client which authenticates with the credentials of the Docker configuration
+func NewClient() *Client {
+	return &Client{HTTPClient: http.DefaultClient, Keychain: NewDockerKeychain()}
+}
+
+// Digest resolves the image reference to the digest of its manifest with the default client,
+// can be replaced in tests
+var Digest = func(image string) (string, error) {
+	return NewClient().Digest(image)
+}
+
+// Digest resolves the image reference to the digest of its manifest. The registry is asked
+// with a HEAD request, only if it doesn't return the digest the manifest is fetched to
+// compute it. References which contain a digest are returned unchanged.
+func (c *Client) Digest(image string) (string, error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+	if ref.Digest != "" {
+		return ref.Digest, nil
+	}
+	manifestURL := fmt.Sprintf("%s://%s/v2/%s/manifests/%s", ref.scheme(), ref.host(), ref.Repository, ref.Tag)
+
+	resp, err := c.get(ref, http.MethodHead, manifestURL, "manifest")
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	if digest := resp.Header.Get("Docker-Content-Digest"); digest != "" {
+		return digest, nil
+	}
+
+	resp, err = c.get(ref, http.MethodGet, manifestURL, "manifest")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	hash := sha256.New()
+	_, err = io.Copy(hash, resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("cannot read manifest of image %s: %v", image, err)
+	}
+	return fmt.Sprintf("sha256:%x", hash.Sum(nil)), nil
+}
+
+// get requests a manifest or blob, first anonymously and then authenticated as the registry
+// demands. The authorization is reused for further requests of the repository.
+func (c *Client) get(ref *Reference, method string, objectURL string, what string) (*http.Response, error) {
+	authorization := c.authorizations[ref.Registry+"/"+ref.Repository]
+	resp, err := c.do(method, objectURL, authorization)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		resp.Body.Close()
+		authorization, err = c.authorize(ref, resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return nil, err
+		}
+		if c.authorizations == nil {
+			c.authorizations = map[string]string{}
+		}
+		c.authorizations[ref.Registry+"/"+ref.Repository] = authorization
+		resp, err = c.do(method, objectURL, authorization)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("cannot get %s of image %s: %s", what, ref, resp.Status)
+	}
+	return resp, nil
+}
+
+func (c *Client) do(method string, objectURL string, authorization string) (*http.Response, error) {
+	req, err := http.NewRequest(method, objectURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	return c.HTTPClient.Do(req)
+}
+
+// authorize returns the Authorization header for the challenge of the registry,
+// which is either basic authentication or a bearer token of the registry's token service
+func (c *Client) authorize(ref *Reference, challenge string) (string, error) {
+	credentials, err := c.Keychain.Credentials(ref.Registry)
+	if err != nil {
+		return "", err
+	}
+	scheme, params := parseChallenge(challenge)
+	switch scheme {
+	case "basic":
+		if credentials == nil {
+			return "", fmt.Errorf("registry %s requires authentication, but no credentials are configured", ref.Registry)
+		}
+		return "Basic " + credentials.basicAuth(), nil
+	case "bearer":
+		token, err := c.token(ref, params, credentials)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+	default:
+		return "", fmt.Errorf("registry %s requires unsupported authentication '%s'", ref.Registry, challenge)
+	}
+}
+
+// token requests a token for pulling the repository from the token service given in the challenge
+func (c *Client) token(ref *Reference, params map[string]string, credentials *Credentials) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("registry %s returned an invalid token realm '%s'", ref.Registry, params["realm"])
+	}
+	query := realm.Query()
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	query.Set("scope", "repository:"+ref.Repository+":pull")
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if credentials != nil {
+		req.Header.Set("Authorization", "Basic "+credentials.basicAuth())
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("cannot get token for image %s: %s %s", ref, resp.Status, strings.TrimSpace(string(body)))
+	}
+	var tokenResponse struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&tokenResponse)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse token response of %s: %v", realm.Host, err)
+	}
+	if tokenResponse.Token != "" {
+		return tokenResponse.Token, nil
+	}
+	if tokenResponse.AccessToken != "" {
+		return tokenResponse.AccessToken, nil
+	}
+	return "", fmt.Errorf("token response of %s contains no token", realm.Host)
+}
+
+// parseChallenge parses a WWW-Authenticate header like
+// 'Bearer realm="https://auth.docker.io/token",service="registry.docker.io"'
+// into the lower-cased scheme and its parameters
+func parseChallenge(challenge string) (string, map[string]string) {
+	params := map[string]string{}
+	parts := strings.SplitN(strings.TrimSpace(challenge), " ", 2)
+	scheme := strings.ToLower(parts[0])
+	if len(parts) == 1 {
+		return scheme, params
+	}
+	rest := parts[1]
+	for rest != "" {
+		i := strings.Index(rest, "=")
+		if i < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(rest[:i]))
+		rest = strings.TrimSpace(rest[i+1:])
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else if end := strings.Index(rest, ","); end >= 0 {
+			value, rest = rest[:end], rest[end:]
+		} else {
+			value, rest = rest, ""
+		}
+		params[key] = value
+		rest = strings.TrimPrefix(strings.TrimSpace(rest), ",")
+	}
+	return scheme, params
+}
@@ -0,0 +1,121 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+type staticKeychain map[string]*Credentials
+
+func (k staticKeychain) Credentials(registry string) (*Credentials, error) {
+	return k[registry], nil
+}
+
+// newTestRegistry serves the manifest of foo/bar:v1 to clients with a token, which is
+// issued to clients authenticated as user:secret
+func newTestRegistry(t *testing.T, headDigest bool) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			user, password, _ := r.BasicAuth()
+			if user != "user" || password != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			assert.Equal(t, r.URL.Query().Get("scope"), "repository:foo/bar:pull")
+			assert.Equal(t, r.URL.Query().Get("service"), "test")
+			fmt.Fprint(w, `{"token":"t0k3n"}`)
+		case "/v2/foo/bar/manifests/v1":
+			if r.Header.Get("Authorization") != "Bearer t0k3n" {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, server.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			assert.Assert(t, strings.Contains(r.Header.Get("Accept"), "application/vnd.oci.image.index.v1+json"))
+			if headDigest {
+				w.Header().Set("Docker-Content-Digest", testDigest)
+			}
+			fmt.Fprint(w, "{}")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server
+}
+
+func TestDigest(t *testing.T) {
+	server := newTestRegistry(t, true)
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	client := &Client{HTTPClient: server.Client(), Keychain: staticKeychain{host: {Username: "user", Password: "secret"}}}
+	digest, err := client.Digest(host + "/foo/bar:v1")
+	assert.NilError(t, err)
+	assert.Equal(t, digest, testDigest)
+
+	digest, err = client.Digest(host + "/foo/bar@" + testDigest)
+	assert.NilError(t, err)
+	assert.Equal(t, digest, testDigest)
+}
+
+func TestDigestComputedFromManifest(t *testing.T) {
+	server := newTestRegistry(t, false)
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	client := &Client{HTTPClient: server.Client(), Keychain: staticKeychain{host: {Username: "user", Password: "secret"}}}
+	digest, err := client.Digest(host + "/foo/bar:v1")
+	assert.NilError(t, err)
+	// sha256 of "{}"
+	assert.Equal(t, digest, "sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a")
+}
+
+func TestDigestErrors(t *testing.T) {
+	server := newTestRegistry(t, true)
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	client := &Client{HTTPClient: server.Client(), Keychain: staticKeychain{}}
+	_, err := client.Digest(host + "/foo/bar:v1")
+	assert.ErrorContains(t, err, "cannot get token for image "+host+"/foo/bar:v1: 401 Unauthorized")
+
+	client.Keychain = staticKeychain{host: {Username: "user", Password: "secret"}}
+	_, err = client.Digest(host + "/foo/baz:v1")
+	assert.ErrorContains(t, err, "cannot get manifest of image "+host+"/foo/baz:v1: 404 Not Found")
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:a/b:pull,push"`)
+	assert.Equal(t, scheme, "bearer")
+	assert.DeepEqual(t, params, map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "registry.docker.io",
+		"scope":   "repository:a/b:pull,push",
+	})
+
+	scheme, params = parseChallenge(`Basic realm=registry`)
+	assert.Equal(t, scheme, "basic")
+	assert.DeepEqual(t, params, map[string]string{"realm": "registry"})
+}
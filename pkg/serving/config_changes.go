@@ -18,13 +18,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"k8s.io/apimachinery/pkg/util/validation"
+	"knative.dev/networking/pkg/apis/networking"
 	"knative.dev/pkg/ptr"
 	"knative.dev/serving/pkg/apis/autoscaling"
 	servingconfig "knative.dev/serving/pkg/apis/config"
+	"knative.dev/serving/pkg/apis/serving"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
 	"knative.dev/client/pkg/kn/flags"
@@ -42,7 +46,19 @@ const (
 
 var (
 	UserImageAnnotationKey = "client.knative.dev/user-image"
-	ApiTooOldError         = errors.New("the service is using too old of an API format for the operation")
+	// RolloutDurationAnnotationKey is the annotation for the time span over which
+	// traffic is gradually shifted to the latest revision
+	RolloutDurationAnnotationKey = "serving.knative.dev/rollout-duration"
+	// ProgressDeadlineAnnotationKey is the annotation for how long the deployment of a
+	// revision may take to become available before the revision is marked as failed
+	ProgressDeadlineAnnotationKey = "serving.knative.dev/progress-deadline"
+	// AsyncIngressClass is the ingress class provided by the Knative async component
+	AsyncIngressClass = "async.ingress.networking.knative.dev"
+	// DescriptionAnnotationKey is the annotation holding a human readable description of a service
+	DescriptionAnnotationKey = "client.knative.dev/description"
+	// OwnerLabelPrefix is the prefix of the labels attributing a service to an owner (e.g. a team)
+	OwnerLabelPrefix = "owner.client.knative.dev/"
+	ApiTooOldError   = errors.New("the service is using too old of an API format for the operation")
 )
 
 func (vt VolumeSourceType) String() string {
@@ -63,15 +79,108 @@ func UpdateMaxScale(template *servingv1.RevisionTemplateSpec, max int) error {
 	return UpdateRevisionTemplateAnnotation(template, autoscaling.MaxScaleAnnotationKey, strconv.Itoa(max))
 }
 
+// UpdateScaleBounds updates the min and max scale annotations at once, so that
+// both new bounds are validated against each other. A nil bound is left unchanged.
+func UpdateScaleBounds(template *servingv1.RevisionTemplateSpec, min *int, max *int) error {
+	toUpdate := map[string]string{}
+	if min != nil {
+		toUpdate[autoscaling.MinScaleAnnotationKey] = strconv.Itoa(*min)
+	}
+	if max != nil {
+		toUpdate[autoscaling.MaxScaleAnnotationKey] = strconv.Itoa(*max)
+	}
+	return UpdateRevisionTemplateAnnotations(template, toUpdate, []string{})
+}
+
 // UpdateAutoscaleWindow updates the autoscale window annotation
 func UpdateAutoscaleWindow(template *servingv1.RevisionTemplateSpec, window string) error {
 	_, err := time.ParseDuration(window)
 	if err != nil {
-		return fmt.Errorf("invalid duration for 'autoscale-window': %v", err)
+		return fmt.Errorf("invalid duration for 'scale-window': %v", err)
 	}
 	return UpdateRevisionTemplateAnnotation(template, autoscaling.WindowAnnotationKey, window)
 }
 
+// UpdateRolloutDuration updates the rollout duration annotation of the service
+func UpdateRolloutDuration(service *servingv1.Service, duration string) error {
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return fmt.Errorf("invalid duration for 'rollout-duration': %v", err)
+	}
+	if d < 0 {
+		return fmt.Errorf("invalid duration for 'rollout-duration': %s (must not be negative)", duration)
+	}
+	return UpdateServiceAnnotations(service, map[string]string{RolloutDurationAnnotationKey: duration}, []string{})
+}
+
+// UpdateProgressDeadline updates the progress deadline annotation of the revision template
+func UpdateProgressDeadline(template *servingv1.RevisionTemplateSpec, deadline string) error {
+	d, err := time.ParseDuration(deadline)
+	if err != nil {
+		return fmt.Errorf("invalid duration for 'progress-deadline': %v", err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("invalid duration for 'progress-deadline': %s (must be positive)", deadline)
+	}
+	return UpdateRevisionTemplateAnnotation(template, ProgressDeadlineAnnotationKey, deadline)
+}
+
+// UpdateAsyncIngress sets the ingress class annotation of the service to the class of the
+// Knative async component or removes it again if it has been set to this class
+func UpdateAsyncIngress(service *servingv1.Service, enable bool) error {
+	if enable {
+		return UpdateServiceAnnotations(service, map[string]string{networking.IngressClassAnnotationKey: AsyncIngressClass}, []string{})
+	}
+	if !IsAsyncIngress(service) {
+		return nil
+	}
+	return UpdateServiceAnnotations(service, map[string]string{}, []string{networking.IngressClassAnnotationKey})
+}
+
+// UpdateServiceDescription sets the description annotation of the service or removes it
+// if the description is empty
+func UpdateServiceDescription(service *servingv1.Service, description string) error {
+	if description == "" {
+		return UpdateServiceAnnotations(service, map[string]string{}, []string{DescriptionAnnotationKey})
+	}
+	return UpdateServiceAnnotations(service, map[string]string{DescriptionAnnotationKey: description}, []string{})
+}
+
+// UpdateServiceOwners sets and removes owner labels of the service. The keys
+// are given without the OwnerLabelPrefix (e.g. "team" for "team=payments").
+func UpdateServiceOwners(service *servingv1.Service, owners map[string]string, remove []string) error {
+	add := map[string]string{}
+	for key, value := range owners {
+		if value == "" {
+			return fmt.Errorf("owner '%s' requires a value (e.g. %s=payments), use '%s-' to remove it", key, key, key)
+		}
+		labelKey := OwnerLabelKey(key)
+		if errs := validation.IsQualifiedName(labelKey); len(errs) > 0 {
+			return fmt.Errorf("invalid owner key '%s': %s", key, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("invalid owner value '%s' for key '%s': %s", value, key, strings.Join(errs, ", "))
+		}
+		add[labelKey] = value
+	}
+	removeKeys := make([]string, 0, len(remove))
+	for _, key := range remove {
+		removeKeys = append(removeKeys, OwnerLabelKey(key))
+	}
+	service.Labels = UpdateLabels(service.Labels, add, removeKeys)
+	return nil
+}
+
+// OwnerLabelKey returns the label key for the given owner key
+func OwnerLabelKey(key string) string {
+	return OwnerLabelPrefix + key
+}
+
+// IsAsyncIngress returns true if the service's requests are handled by the Knative async component
+func IsAsyncIngress(service *servingv1.Service) bool {
+	return service.Annotations[networking.IngressClassAnnotationKey] == AsyncIngressClass
+}
+
 // UpdateConcurrencyTarget updates container concurrency annotation
 func UpdateConcurrencyTarget(template *servingv1.RevisionTemplateSpec, target int) error {
 	return UpdateRevisionTemplateAnnotation(template, autoscaling.TargetAnnotationKey, strconv.Itoa(target))
@@ -91,6 +200,60 @@ func UpdateConcurrencyLimit(template *servingv1.RevisionTemplateSpec, limit int6
 	return nil
 }
 
+// QueueProxyResourceAnnotations maps the keys accepted for sizing the queue-proxy sidecar
+// to the revision annotations supported by Knative Serving
+var QueueProxyResourceAnnotations = map[string]string{
+	"percentage": serving.QueueSideCarResourcePercentageAnnotation,
+}
+
+// UpdateQueueProxyResources sets the queue-proxy resource annotations of the revision template.
+// Keys must be one of QueueProxyResourceAnnotations, keys in remove unset the annotation.
+func UpdateQueueProxyResources(template *servingv1.RevisionTemplateSpec, resources map[string]string, remove []string) error {
+	toUpdate := map[string]string{}
+	for key, value := range resources {
+		annotation, err := queueProxyResourceAnnotation(key)
+		if err != nil {
+			return err
+		}
+		toUpdate[annotation] = value
+	}
+	toRemove := make([]string, 0, len(remove))
+	for _, key := range remove {
+		annotation, err := queueProxyResourceAnnotation(key)
+		if err != nil {
+			return err
+		}
+		toRemove = append(toRemove, annotation)
+	}
+	if err := serving.ValidateQueueSidecarAnnotation(toUpdate); err != nil {
+		return fmt.Errorf("invalid queue-proxy resources: %v", err)
+	}
+	return UpdateRevisionTemplateAnnotations(template, toUpdate, toRemove)
+}
+
+func queueProxyResourceAnnotation(key string) (string, error) {
+	annotation, ok := QueueProxyResourceAnnotations[key]
+	if !ok {
+		allowed := make([]string, 0, len(QueueProxyResourceAnnotations))
+		for k := range QueueProxyResourceAnnotations {
+			allowed = append(allowed, k)
+		}
+		sort.Strings(allowed)
+		return "", fmt.Errorf("invalid queue-proxy resource key '%s', must be one of %s", key, strings.Join(allowed, ", "))
+	}
+	return annotation, nil
+}
+
+// UpdateRequestTimeout updates the time the routing layer waits for a container
+// to respond to a request
+func UpdateRequestTimeout(template *servingv1.RevisionTemplateSpec, timeoutSeconds int64) error {
+	if timeoutSeconds <= 0 {
+		return fmt.Errorf("invalid request timeout %d (must be greater than 0)", timeoutSeconds)
+	}
+	template.Spec.TimeoutSeconds = ptr.Int64(timeoutSeconds)
+	return nil
+}
+
 // UnsetUserImageAnnot removes the user image annotation
 func UnsetUserImageAnnot(template *servingv1.RevisionTemplateSpec) {
 	delete(template.Annotations, UserImageAnnotationKey)
@@ -166,10 +329,18 @@ func UpdateServiceAnnotations(service *servingv1.Service, toUpdate map[string]st
 // UpdateRevisionTemplateAnnotations updates annotations for the given Revision Template.
 // Also validates the autoscaling annotation values
 func UpdateRevisionTemplateAnnotations(template *servingv1.RevisionTemplateSpec, toUpdate map[string]string, toRemove []string) error {
+	// Validate the resulting annotations and not only the updated ones, so that
+	// e.g. a new min scale is checked against an already existing max scale
+	merged := make(map[string]string, len(template.Annotations)+len(toUpdate))
+	for key, value := range template.Annotations {
+		merged[key] = value
+	}
+	updateAnnotations(merged, toUpdate, toRemove)
+
 	ctx := context.TODO()
 	autoscalerConfig := servingconfig.FromContextOrDefaults(ctx).Autoscaler
 	autoscalerConfig.AllowZeroInitialScale = true
-	if err := autoscaling.ValidateAnnotations(ctx, autoscalerConfig, toUpdate); err != nil {
+	if err := autoscaling.ValidateAnnotations(ctx, autoscalerConfig, merged); err != nil {
 		return err
 	}
 	if template.Annotations == nil {
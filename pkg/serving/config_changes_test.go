@@ -21,6 +21,7 @@ import (
 
 	"gotest.tools/assert"
 
+	"knative.dev/networking/pkg/apis/networking"
 	"knative.dev/pkg/ptr"
 	"knative.dev/serving/pkg/apis/autoscaling"
 
@@ -140,6 +141,28 @@ func TestUpdateMaxScale(t *testing.T) {
 	assert.ErrorContains(t, err, "maxScale")
 }
 
+func TestUpdateScaleBounds(t *testing.T) {
+	template, _ := getRevisionTemplate()
+	min, max := 2, 4
+	err := UpdateScaleBounds(template, &min, &max)
+	assert.NilError(t, err)
+	checkAnnotationValueInt(t, template, autoscaling.MinScaleAnnotationKey, 2)
+	checkAnnotationValueInt(t, template, autoscaling.MaxScaleAnnotationKey, 4)
+
+	// Bounds are validated against the existing annotations
+	min = 5
+	err = UpdateScaleBounds(template, &min, nil)
+	assert.ErrorContains(t, err, "maxScale=4 is less than minScale=5")
+	checkAnnotationValueInt(t, template, autoscaling.MinScaleAnnotationKey, 2)
+
+	// ... and against each other
+	max = 8
+	err = UpdateScaleBounds(template, &min, &max)
+	assert.NilError(t, err)
+	checkAnnotationValueInt(t, template, autoscaling.MinScaleAnnotationKey, 5)
+	checkAnnotationValueInt(t, template, autoscaling.MaxScaleAnnotationKey, 8)
+}
+
 func TestAutoscaleWindow(t *testing.T) {
 	template, _ := getRevisionTemplate()
 	err := UpdateAutoscaleWindow(template, "10s")
@@ -148,7 +171,87 @@ func TestAutoscaleWindow(t *testing.T) {
 	checkAnnotationValue(t, template, autoscaling.WindowAnnotationKey, "10s")
 	// Update with invalid value
 	err = UpdateAutoscaleWindow(template, "blub")
-	assert.Check(t, util.ContainsAll(err.Error(), "invalid duration", "autoscale-window"))
+	assert.Check(t, util.ContainsAll(err.Error(), "invalid duration", "scale-window"))
+}
+
+func TestUpdateRolloutDuration(t *testing.T) {
+	service := &servingv1.Service{}
+	err := UpdateRolloutDuration(service, "380s")
+	assert.NilError(t, err)
+	assert.Equal(t, service.Annotations[RolloutDurationAnnotationKey], "380s")
+	// Update with invalid values
+	err = UpdateRolloutDuration(service, "blub")
+	assert.Check(t, util.ContainsAll(err.Error(), "invalid duration", "rollout-duration"))
+	err = UpdateRolloutDuration(service, "-10s")
+	assert.Check(t, util.ContainsAll(err.Error(), "invalid duration", "rollout-duration", "negative"))
+	assert.Equal(t, service.Annotations[RolloutDurationAnnotationKey], "380s")
+}
+
+func TestUpdateProgressDeadline(t *testing.T) {
+	template := &servingv1.RevisionTemplateSpec{}
+	err := UpdateProgressDeadline(template, "10m")
+	assert.NilError(t, err)
+	assert.Equal(t, template.Annotations[ProgressDeadlineAnnotationKey], "10m")
+	// Update with invalid values
+	err = UpdateProgressDeadline(template, "blub")
+	assert.Check(t, util.ContainsAll(err.Error(), "invalid duration", "progress-deadline"))
+	err = UpdateProgressDeadline(template, "0s")
+	assert.Check(t, util.ContainsAll(err.Error(), "invalid duration", "progress-deadline", "positive"))
+	assert.Equal(t, template.Annotations[ProgressDeadlineAnnotationKey], "10m")
+}
+
+func TestUpdateAsyncIngress(t *testing.T) {
+	service := &servingv1.Service{}
+	err := UpdateAsyncIngress(service, true)
+	assert.NilError(t, err)
+	assert.Assert(t, IsAsyncIngress(service))
+	err = UpdateAsyncIngress(service, false)
+	assert.NilError(t, err)
+	assert.Assert(t, !IsAsyncIngress(service))
+	assert.Equal(t, len(service.Annotations), 0)
+
+	// Don't touch ingress classes not set by us
+	service.Annotations = map[string]string{networking.IngressClassAnnotationKey: "kourier.ingress.networking.knative.dev"}
+	err = UpdateAsyncIngress(service, false)
+	assert.NilError(t, err)
+	assert.Equal(t, service.Annotations[networking.IngressClassAnnotationKey], "kourier.ingress.networking.knative.dev")
+}
+
+func TestUpdateServiceDescription(t *testing.T) {
+	service := &servingv1.Service{}
+	err := UpdateServiceDescription(service, "Payment processing")
+	assert.NilError(t, err)
+	assert.Equal(t, service.Annotations[DescriptionAnnotationKey], "Payment processing")
+	err = UpdateServiceDescription(service, "")
+	assert.NilError(t, err)
+	_, found := service.Annotations[DescriptionAnnotationKey]
+	assert.Assert(t, !found)
+}
+
+func TestUpdateServiceOwners(t *testing.T) {
+	service := &servingv1.Service{}
+	service.Labels = map[string]string{"app": "foo"}
+	err := UpdateServiceOwners(service, map[string]string{"team": "payments", "contact": "alice"}, []string{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, service.Labels, map[string]string{
+		"app":                              "foo",
+		"owner.client.knative.dev/team":    "payments",
+		"owner.client.knative.dev/contact": "alice",
+	})
+
+	err = UpdateServiceOwners(service, map[string]string{}, []string{"contact"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, service.Labels, map[string]string{
+		"app":                           "foo",
+		"owner.client.knative.dev/team": "payments",
+	})
+
+	err = UpdateServiceOwners(service, map[string]string{"my team": "payments"}, []string{})
+	assert.ErrorContains(t, err, "invalid owner key")
+
+	err = UpdateServiceOwners(service, map[string]string{"team": ""}, []string{})
+	assert.ErrorContains(t, err, "requires a value")
+	assert.Equal(t, service.Labels["owner.client.knative.dev/team"], "payments")
 }
 
 func TestUpdateConcurrencyTarget(t *testing.T) {
@@ -162,6 +265,35 @@ func TestUpdateConcurrencyTarget(t *testing.T) {
 	assert.ErrorContains(t, err, "should be at least 0.01")
 }
 
+func TestUpdateQueueProxyResources(t *testing.T) {
+	template, _ := getRevisionTemplate()
+	err := UpdateQueueProxyResources(template, map[string]string{"percentage": "20"}, []string{})
+	assert.NilError(t, err)
+	assert.Equal(t, template.Annotations["queue.sidecar.serving.knative.dev/resourcePercentage"], "20")
+
+	err = UpdateQueueProxyResources(template, map[string]string{"percentage": "200"}, []string{})
+	assert.ErrorContains(t, err, "invalid queue-proxy resources")
+	err = UpdateQueueProxyResources(template, map[string]string{"cpu": "100m"}, []string{})
+	assert.ErrorContains(t, err, "invalid queue-proxy resource key 'cpu', must be one of percentage")
+	assert.Equal(t, template.Annotations["queue.sidecar.serving.knative.dev/resourcePercentage"], "20")
+
+	err = UpdateQueueProxyResources(template, map[string]string{}, []string{"percentage"})
+	assert.NilError(t, err)
+	_, found := template.Annotations["queue.sidecar.serving.knative.dev/resourcePercentage"]
+	assert.Assert(t, !found)
+}
+
+func TestUpdateRequestTimeout(t *testing.T) {
+	template, _ := getRevisionTemplate()
+	err := UpdateRequestTimeout(template, 30)
+	assert.NilError(t, err)
+	assert.Equal(t, *template.Spec.TimeoutSeconds, int64(30))
+	// Update with invalid value
+	err = UpdateRequestTimeout(template, 0)
+	assert.ErrorContains(t, err, "must be greater than 0")
+	assert.Equal(t, *template.Spec.TimeoutSeconds, int64(30))
+}
+
 func TestUpdateConcurrencyLimit(t *testing.T) {
 	template, _ := getRevisionTemplate()
 	err := UpdateConcurrencyLimit(template, 10)
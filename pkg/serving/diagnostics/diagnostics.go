@@ -0,0 +1,295 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package diagnostics collects why the latest revision of a service doesn't become
+// ready: the conditions of the revision, the status of its pods and containers and the
+// recent events of its deployment, replica sets and pods.
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/serving/pkg/apis/serving"
+
+	"knative.dev/client/pkg/printers"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+)
+
+// DefaultMaxEvents is the number of most recent events collected if not configured otherwise
+const DefaultMaxEvents = 10
+
+// Collector gathers the diagnostics of services. Collecting is best effort: what
+// can't be fetched, e.g. because of missing permissions, is recorded as error in the
+// report and the rest is still collected.
+type Collector struct {
+	Serving clientservingv1.KnServingClient
+	Kube    kubernetes.Interface
+
+	// MaxEvents is the number of most recent events collected, DefaultMaxEvents if 0
+	MaxEvents int
+}
+
+// Report is the state of the latest created revision of a service
+type Report struct {
+	Service   string
+	Namespace string
+	Revision  string
+
+	Conditions apis.Conditions
+	Pods       []Pod
+	Events     []Event
+
+	// Errors of the parts which couldn't be collected
+	Errors []error
+}
+
+// Pod is the status of a pod of the revision
+type Pod struct {
+	Name       string
+	Phase      corev1.PodPhase
+	Containers []Container
+}
+
+// Container is the status of a container of a pod, with the reason why it is not
+// running, like ImagePullBackOff or CrashLoopBackOff
+type Container struct {
+	Name     string
+	Ready    bool
+	Restarts int32
+	// State is "waiting", "running" or "terminated"
+	State   string
+	Reason  string
+	Message string
+}
+
+// Event is an event of the deployment of the revision, or of its replica sets or pods
+type Event struct {
+	Type     string
+	Reason   string
+	Object   string
+	Message  string
+	Count    int32
+	LastSeen time.Time
+}
+
+// Collect gathers the diagnostics of the latest created revision of the service
+func (c *Collector) Collect(serviceName string) *Report {
+	report := &Report{Service: serviceName, Namespace: c.Serving.Namespace()}
+	service, err := c.Serving.GetService(serviceName)
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Errorf("cannot get service '%s': %w", serviceName, err))
+		return report
+	}
+	report.Revision = service.Status.LatestCreatedRevisionName
+	if report.Revision == "" {
+		report.Errors = append(report.Errors, fmt.Errorf("service '%s' has no revision yet", serviceName))
+		return report
+	}
+
+	revision, err := c.Serving.GetRevision(report.Revision)
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Errorf("cannot get revision '%s': %w", report.Revision, err))
+	} else {
+		report.Conditions = apis.Conditions(revision.Status.Conditions)
+	}
+
+	pods, err := c.Kube.CoreV1().Pods(report.Namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: serving.RevisionLabelKey + "=" + report.Revision,
+	})
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Errorf("cannot list the pods of revision '%s': %w", report.Revision, err))
+	} else {
+		for _, pod := range pods.Items {
+			if pod.Labels[serving.RevisionLabelKey] != report.Revision {
+				continue
+			}
+			report.Pods = append(report.Pods, podStatus(&pod))
+		}
+		sort.Slice(report.Pods, func(i, j int) bool {
+			return report.Pods[i].Name < report.Pods[j].Name
+		})
+	}
+
+	events, err := c.Kube.CoreV1().Events(report.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Errorf("cannot list the events of revision '%s': %w", report.Revision, err))
+	} else {
+		report.Events = deploymentEvents(events.Items, kmeta.ChildName(report.Revision, "-deployment"), c.maxEvents())
+	}
+	return report
+}
+
+func (c *Collector) maxEvents() int {
+	if c.MaxEvents > 0 {
+		return c.MaxEvents
+	}
+	return DefaultMaxEvents
+}
+
+// podStatus returns the status of the init containers and containers of a pod
+func podStatus(pod *corev1.Pod) Pod {
+	ret := Pod{Name: pod.Name, Phase: pod.Status.Phase}
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		container := Container{Name: status.Name, Ready: status.Ready, Restarts: status.RestartCount}
+		state := status.State
+		switch {
+		case state.Waiting != nil:
+			container.State = "waiting"
+			container.Reason = state.Waiting.Reason
+			container.Message = state.Waiting.Message
+		case state.Terminated != nil:
+			container.State = "terminated"
+			container.Reason = state.Terminated.Reason
+			container.Message = terminatedMessage(state.Terminated)
+		case state.Running != nil:
+			container.State = "running"
+		}
+		// The last termination tells why a container in a crash loop failed
+		if container.Message == "" && status.LastTerminationState.Terminated != nil {
+			container.Message = "last terminated: " + terminatedMessage(status.LastTerminationState.Terminated)
+		}
+		ret.Containers = append(ret.Containers, container)
+	}
+	return ret
+}
+
+func terminatedMessage(terminated *corev1.ContainerStateTerminated) string {
+	message := fmt.Sprintf("exit code %d", terminated.ExitCode)
+	if terminated.Reason != "" {
+		message = terminated.Reason + ", " + message
+	}
+	if terminated.Message != "" {
+		message += ": " + strings.TrimSpace(terminated.Message)
+	}
+	return message
+}
+
+// deploymentEvents returns the most recent events of the deployment and of the replica
+// sets and pods created for it, whose names all start with the name of the deployment
+func deploymentEvents(events []corev1.Event, deployment string, max int) []Event {
+	var ret []Event
+	for _, event := range events {
+		involved := event.InvolvedObject
+		if involved.Name != deployment && !strings.HasPrefix(involved.Name, deployment+"-") {
+			continue
+		}
+		ret = append(ret, Event{
+			Type:     event.Type,
+			Reason:   event.Reason,
+			Object:   strings.ToLower(involved.Kind) + "/" + involved.Name,
+			Message:  strings.TrimSpace(event.Message),
+			Count:    event.Count,
+			LastSeen: lastSeen(&event),
+		})
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].LastSeen.Before(ret[j].LastSeen)
+	})
+	if len(ret) > max {
+		ret = ret[len(ret)-max:]
+	}
+	return ret
+}
+
+func lastSeen(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// Print writes the report as tables of the revision's conditions, pods and events
+func (r *Report) Print(out io.Writer) error {
+	if r.Revision != "" {
+		fmt.Fprintf(out, "\nDiagnostics of revision '%s' of service '%s' in namespace '%s':\n", r.Revision, r.Service, r.Namespace)
+	} else {
+		fmt.Fprintf(out, "\nDiagnostics of service '%s' in namespace '%s':\n", r.Service, r.Namespace)
+	}
+
+	if len(r.Conditions) > 0 {
+		fmt.Fprintln(out, "\nRevision conditions:")
+		w := printers.NewTabWriter(out)
+		fmt.Fprintln(w, "TYPE\tSTATUS\tREASON\tMESSAGE")
+		for _, cond := range r.Conditions {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", cond.Type, cond.Status, cond.Reason, cond.Message)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if r.Revision != "" && len(r.Pods) == 0 {
+		fmt.Fprintln(out, "\nThe revision has no pods.")
+	} else if len(r.Pods) > 0 {
+		fmt.Fprintln(out, "\nPods:")
+		w := printers.NewTabWriter(out)
+		fmt.Fprintln(w, "POD\tPHASE\tCONTAINER\tREADY\tSTATE\tREASON\tRESTARTS\tMESSAGE")
+		for _, pod := range r.Pods {
+			if len(pod.Containers) == 0 {
+				fmt.Fprintf(w, "%s\t%s\t\t\t\t\t\t\n", pod.Name, pod.Phase)
+			}
+			for _, container := range pod.Containers {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\t%s\t%d\t%s\n", pod.Name, pod.Phase, container.Name, container.Ready,
+					container.State, container.Reason, container.Restarts, container.Message)
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(r.Events) > 0 {
+		fmt.Fprintln(out, "\nRecent events:")
+		w := printers.NewTabWriter(out)
+		fmt.Fprintln(w, "LAST SEEN\tTYPE\tREASON\tOBJECT\tCOUNT\tMESSAGE")
+		for _, event := range r.Events {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", age(event.LastSeen), event.Type, event.Reason, event.Object, event.Count, event.Message)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	for _, err := range r.Errors {
+		fmt.Fprintf(out, "\nWarning: %v\n", err)
+	}
+	return nil
+}
+
+func age(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	if printers.OmitTimestamps() {
+		return printers.TimestampPlaceholder
+	}
+	return duration.ShortHumanDuration(time.Since(t))
+}
@@ -0,0 +1,149 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostics
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/kn/commands"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	"knative.dev/client/pkg/util"
+)
+
+func TestCollect(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", newService("foo", "foo-00002"), nil)
+	revision := &servingv1.Revision{}
+	revision.Status.Conditions = []apis.Condition{
+		{Type: apis.ConditionReady, Status: corev1.ConditionFalse, Reason: "ImagePullBackOff", Message: "image pull failed"},
+	}
+	r.GetRevision("foo-00002", revision, nil)
+
+	now := time.Now()
+	var events []runtime.Object
+	for i := 0; i < 12; i++ {
+		events = append(events, newEvent(fmt.Sprintf("e%d", i), "Pod", fmt.Sprintf("foo-00002-deployment-abc-%d", i), fmt.Sprintf("event %d", i), now.Add(time.Duration(i-12)*time.Second)))
+	}
+	objects := append(events,
+		newEvent("other", "Pod", "foo-00001-deployment-abc", "other revision", now),
+		newPod("foo-00002-deployment-abc-2", "foo-00002", corev1.ContainerStatus{
+			Name:         "user-container",
+			RestartCount: 3,
+			State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			LastTerminationState: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1},
+			},
+		}),
+		newPod("foo-00002-deployment-abc-1", "foo-00002", corev1.ContainerStatus{
+			Name:  "user-container",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"}},
+		}),
+		newPod("foo-00001-deployment-abc-1", "foo-00001"))
+
+	collector := Collector{Serving: client, Kube: commands.NewFakeKubeClient(objects...), MaxEvents: 5}
+	report := collector.Collect("foo")
+	assert.Equal(t, report.Revision, "foo-00002")
+	assert.Equal(t, len(report.Errors), 0)
+	assert.Equal(t, len(report.Conditions), 1)
+
+	assert.Equal(t, len(report.Pods), 2)
+	assert.Equal(t, report.Pods[0].Name, "foo-00002-deployment-abc-1")
+	assert.DeepEqual(t, report.Pods[0].Containers, []Container{
+		{Name: "user-container", State: "waiting", Reason: "ImagePullBackOff", Message: "Back-off pulling image"},
+	})
+	assert.DeepEqual(t, report.Pods[1].Containers, []Container{
+		{Name: "user-container", Restarts: 3, State: "waiting", Reason: "CrashLoopBackOff", Message: "last terminated: Error, exit code 1"},
+	})
+
+	assert.Equal(t, len(report.Events), 5)
+	assert.Equal(t, report.Events[0].Message, "event 7")
+	assert.Equal(t, report.Events[4].Message, "event 11")
+	assert.Equal(t, report.Events[4].Object, "pod/foo-00002-deployment-abc-11")
+
+	out := &bytes.Buffer{}
+	assert.NilError(t, report.Print(out))
+	assert.Assert(t, util.ContainsAll(out.String(),
+		"Diagnostics of revision 'foo-00002' of service 'foo' in namespace 'default':",
+		"Revision conditions:", "ImagePullBackOff", "image pull failed",
+		"Pods:", "foo-00002-deployment-abc-1", "CrashLoopBackOff", "last terminated: Error, exit code 1",
+		"Recent events:", "LAST SEEN", "event 11"))
+	assert.Assert(t, util.ContainsNone(out.String(), "other revision", "foo-00001-deployment-abc-1", "event 6", "Warning:", "<invalid>"))
+	r.Validate()
+}
+
+func TestCollectErrors(t *testing.T) {
+	client := clientservingv1.NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", nil, errors.New("no service"))
+	r.GetService("foo", newService("foo", ""), nil)
+	r.GetService("foo", newService("foo", "foo-00001"), nil)
+	r.GetRevision("foo-00001", nil, errors.New("no revision"))
+
+	collector := Collector{Serving: client, Kube: commands.NewFakeKubeClient()}
+	report := collector.Collect("foo")
+	assert.ErrorContains(t, report.Errors[0], "no service")
+
+	report = collector.Collect("foo")
+	assert.ErrorContains(t, report.Errors[0], "has no revision yet")
+
+	report = collector.Collect("foo")
+	assert.Equal(t, len(report.Errors), 1)
+	assert.ErrorContains(t, report.Errors[0], "no revision")
+	out := &bytes.Buffer{}
+	assert.NilError(t, report.Print(out))
+	assert.Assert(t, util.ContainsAll(out.String(), "The revision has no pods.", "Warning: cannot get revision 'foo-00001': no revision"))
+	r.Validate()
+}
+
+func newService(name, latestCreated string) *servingv1.Service {
+	service := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	service.Status.LatestCreatedRevisionName = latestCreated
+	return service
+}
+
+func newPod(name, revision string, statuses ...corev1.ContainerStatus) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{"serving.knative.dev/revision": revision},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodPending, ContainerStatuses: statuses},
+	}
+}
+
+func newEvent(name, kind, object, message string, lastSeen time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: kind, Name: object, Namespace: "default"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "Failed",
+		Message:        message,
+		Count:          1,
+		LastTimestamp:  metav1.NewTime(lastSeen),
+	}
+}
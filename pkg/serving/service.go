@@ -16,13 +16,24 @@ package serving
 
 import (
 	"bytes"
+	"fmt"
 	"math/rand"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 )
 
+const (
+	// EphemeralLabelKey marks services which are deleted by 'kn gc --expired' once expired
+	EphemeralLabelKey = "client.knative.dev/ephemeral"
+	// ExpiresAtLabelKey holds the expiry time of an ephemeral service in seconds since the epoch,
+	// as label values can't hold a formatted timestamp
+	ExpiresAtLabelKey = "client.knative.dev/expires-at"
+)
+
 var charChoices = []string{
 	"b", "c", "d", "f", "g", "h", "j", "k", "l", "m", "n", "p", "q", "r", "s", "t", "v", "w", "x",
 	"y", "z",
@@ -42,8 +53,12 @@ func (c *revisionTemplContext) Random(l int) string {
 }
 
 // GenerateRevisionName returns an automatically-generated name suitable for the
-// next revision of the given service.
+// next revision of the given service. The name is empty for a service whose name
+// is generated by the server, which then names the revision, too.
 func GenerateRevisionName(nameTempl string, service *servingv1.Service) (string, error) {
+	if service.Name == "" && service.GenerateName != "" {
+		return "", nil
+	}
 	templ, err := template.New("revisionName").Parse(nameTempl)
 	if err != nil {
 		return "", err
@@ -68,3 +83,29 @@ func GenerateRevisionName(nameTempl string, service *servingv1.Service) (string,
 	}
 	return res, nil
 }
+
+// MarkEphemeral labels the service as ephemeral, expiring at the given time
+func MarkEphemeral(service *servingv1.Service, expiry time.Time) {
+	if service.Labels == nil {
+		service.Labels = map[string]string{}
+	}
+	service.Labels[EphemeralLabelKey] = "true"
+	service.Labels[ExpiresAtLabelKey] = strconv.FormatInt(expiry.Unix(), 10)
+}
+
+// EphemeralExpiry returns the expiry time of an ephemeral service. The boolean
+// is false if the service isn't marked as ephemeral.
+func EphemeralExpiry(service *servingv1.Service) (time.Time, bool, error) {
+	if service.Labels[EphemeralLabelKey] != "true" {
+		return time.Time{}, false, nil
+	}
+	value, ok := service.Labels[ExpiresAtLabelKey]
+	if !ok {
+		return time.Time{}, false, fmt.Errorf("ephemeral service '%s' has no label '%s'", service.Name, ExpiresAtLabelKey)
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid expiry '%s' in label '%s' of service '%s'", value, ExpiresAtLabelKey, service.Name)
+	}
+	return time.Unix(seconds, 0), true, nil
+}
@@ -17,6 +17,7 @@ package serving
 import (
 	"math/rand"
 	"testing"
+	"time"
 
 	"gotest.tools/assert"
 
@@ -53,4 +54,35 @@ func TestGenerateName(t *testing.T) {
 			assert.Equal(t, name, c.result)
 		}
 	}
+
+	// The server names the revisions of a service with a generated name
+	service = &servingv1.Service{}
+	service.GenerateName = "ci-"
+	name, err := GenerateRevisionName("{{.Service}}-{{.Random 5}}-{{.Generation}}", service)
+	assert.NilError(t, err)
+	assert.Equal(t, name, "")
+}
+
+func TestEphemeralExpiry(t *testing.T) {
+	service := &servingv1.Service{}
+	service.Name = "foo"
+	_, ephemeral, err := EphemeralExpiry(service)
+	assert.NilError(t, err)
+	assert.Assert(t, !ephemeral)
+
+	expiry := time.Unix(1700000000, 0)
+	MarkEphemeral(service, expiry)
+	assert.Equal(t, service.Labels[ExpiresAtLabelKey], "1700000000")
+	got, ephemeral, err := EphemeralExpiry(service)
+	assert.NilError(t, err)
+	assert.Assert(t, ephemeral)
+	assert.Assert(t, got.Equal(expiry))
+
+	service.Labels[ExpiresAtLabelKey] = "tomorrow"
+	_, _, err = EphemeralExpiry(service)
+	assert.ErrorContains(t, err, "invalid expiry 'tomorrow'")
+
+	delete(service.Labels, ExpiresAtLabelKey)
+	_, _, err = EphemeralExpiry(service)
+	assert.ErrorContains(t, err, "has no label")
 }
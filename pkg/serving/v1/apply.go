@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
+	"k8s.io/klog/v2"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
 	"knative.dev/client/pkg/util"
@@ -47,6 +48,7 @@ func (cl *knServingClient) patch(modifiedService *servingv1.Service, currentServ
 	}
 	hasChanged, err := cl.patchSimple(currentService, uModifiedService, uOriginalService)
 	for i := 1; i <= 5 && apierrors.IsConflict(err); i++ {
+		klog.V(util.LogLevelRetry).InfoS("Retrying patch after conflict", "service", currentService.Name, "retry", i, "maxRetries", 5)
 		if i > 1 {
 			time.Sleep(1 * time.Second)
 		}
@@ -85,7 +87,7 @@ func (cl *knServingClient) patchSimple(currentService *servingv1.Service, uModif
 
 // patchService patches the given service
 func (cl *knServingClient) patchService(name string, patchType types.PatchType, patch []byte) (*servingv1.Service, error) {
-	service, err := cl.client.Services(cl.namespace).Patch(context.TODO(), name, patchType, patch, metav1.PatchOptions{})
+	service, err := cl.client.Services(cl.namespace).Patch(context.TODO(), name, patchType, patch, metav1.PatchOptions{DryRun: cl.dryRunOptions()})
 	if err != nil {
 		return nil, err
 	}
@@ -18,10 +18,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/klog/v2"
 	"knative.dev/pkg/apis"
 	"knative.dev/serving/pkg/client/clientset/versioned/scheme"
 
@@ -32,7 +34,11 @@ import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8swait "k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
 	apiserving "knative.dev/serving/pkg/apis/serving"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 	clientv1 "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
@@ -44,6 +50,10 @@ import (
 // or an error
 type ServiceUpdateFunc func(origService *servingv1.Service) (*servingv1.Service, error)
 
+// ServicePredicate checks whether a service has reached the state waited for.
+// An error stops waiting, e.g. when the state can't be reached anymore.
+type ServicePredicate func(service *servingv1.Service) (done bool, err error)
+
 // Kn interface to serving. All methods are relative to the
 // namespace specified during construction
 type KnServingClient interface {
@@ -57,17 +67,26 @@ type KnServingClient interface {
 	// List services
 	ListServices(opts ...ListConfig) (*servingv1.ServiceList, error)
 
-	// Create a new service
+	// Watch services for changes. Start after a list with WithResourceVersion,
+	// so that no change between the list and the watch is lost.
+	WatchServices(opts ...ListConfig) (watch.Interface, error)
+
+	// Create a new service. The name of a service created with metadata.generateName
+	// is set to the name generated by the server.
 	CreateService(service *servingv1.Service) error
 
 	// UpdateService updates the given service. For a more robust variant with automatic
-	// conflict resolution see UpdateServiceWithRetry
+	// conflict resolution see UpdateServiceWithRetry. A client created WithServerSideApply
+	// applies the service server-side instead.
 	UpdateService(service *servingv1.Service) error
 
 	// UpdateServiceWithRetry updates service and retries if there is a version conflict.
 	// The updateFunc receives a deep copy of the existing service and can add update it in
-	// place.
-	UpdateServiceWithRetry(name string, updateFunc ServiceUpdateFunc, nrRetries int) error
+	// place. Changes of labels and annotations only are patched instead of updated.
+	UpdateServiceWithRetry(name string, updateFunc ServiceUpdateFunc, policy RetryPolicy) error
+
+	// PatchService applies a JSON merge patch to the service with the given name
+	PatchService(name string, patch []byte) error
 
 	// Apply a service's definition to the cluster. The full service declaration needs to be provided,
 	// which is different to UpdateService which can also do a partial update. If the given
@@ -87,6 +106,14 @@ type KnServingClient interface {
 	// Return error and how long has been waited
 	WaitForService(name string, timeout time.Duration, msgCallback wait.MessageCallback) (error, time.Duration)
 
+	// Wait for a condition of a service to become True, but not longer than provided timeout.
+	// Return error and how long has been waited
+	WaitForServiceCondition(name string, conditionType apis.ConditionType, timeout time.Duration, msgCallback wait.MessageCallback) (error, time.Duration)
+
+	// Wait for a service to reach the state checked by the predicate, but not longer than provided timeout.
+	// Return error and how long has been waited
+	WaitForServicePredicate(name string, predicate ServicePredicate, timeout time.Duration, msgCallback wait.MessageCallback) (error, time.Duration)
+
 	// Get a configuration by name
 	GetConfiguration(name string) (*servingv1.Configuration, error)
 
@@ -109,6 +136,10 @@ type KnServingClient interface {
 	// Delete a revision
 	DeleteRevision(name string, timeout time.Duration) error
 
+	// List the revisions of a service which no route references and which are
+	// none of the service's latest revisions, i.e. which can be deleted safely
+	ListUnroutedRevisions(serviceName string) (*servingv1.RevisionList, error)
+
 	// Get a route by its unique name
 	GetRoute(name string) (*servingv1.Route, error)
 
@@ -122,6 +153,9 @@ type listConfigCollector struct {
 
 	// Labels to filter on
 	Fields fields.Set
+
+	// Resource version to start a watch from
+	ResourceVersion string
 }
 
 // Config function for builder pattern
@@ -131,7 +165,7 @@ type ListConfigs []ListConfig
 
 // add selectors to a list options
 func (opts ListConfigs) toListOptions() v1.ListOptions {
-	listConfig := listConfigCollector{labels.Set{}, fields.Set{}}
+	listConfig := listConfigCollector{Labels: labels.Set{}, Fields: fields.Set{}}
 	for _, f := range opts {
 		f(&listConfig)
 	}
@@ -142,6 +176,7 @@ func (opts ListConfigs) toListOptions() v1.ListOptions {
 	if len(listConfig.Labels) > 0 {
 		options.LabelSelector = listConfig.Labels.String()
 	}
+	options.ResourceVersion = listConfig.ResourceVersion
 	return options
 }
 
@@ -166,17 +201,100 @@ func WithLabel(labelKey, labelValue string) ListConfig {
 	}
 }
 
+// WithResourceVersion starts a watch after the given resource version,
+// like the one of a list returned before
+func WithResourceVersion(resourceVersion string) ListConfig {
+	return func(lo *listConfigCollector) {
+		lo.ResourceVersion = resourceVersion
+	}
+}
+
 type knServingClient struct {
 	client    clientv1.ServingV1Interface
 	namespace string
+
+	// Shared watch for waiting on services, nil if every wait opens its own watch
+	serviceWatch *wait.SharedWatch
+
+	// Update services with server-side apply instead of a full update
+	serverSideApply bool
+	// Take over the fields of other managers when applying server-side
+	forceConflicts bool
+	// Services as last read, the base for finding out which fields to apply server-side
+	readServices map[string]*servingv1.Service
+	readMutex    sync.Mutex
+
+	// Send the changes of services with server-side dry-run, so that nothing is persisted
+	dryRun bool
+}
+
+// FieldManager is the name of the field manager kn applies services with
+const FieldManager = "kn"
+
+// ClientOption configures the client facade
+type ClientOption func(cl *knServingClient)
+
+// WithSharedWaits lets concurrent WaitForService calls share a single informer on
+// all services in the namespace, instead of opening a watch per call. This reduces the
+// load on the API server when waiting for many services in one process.
+func WithSharedWaits() ClientOption {
+	return func(cl *knServingClient) {
+		cl.serviceWatch = wait.NewSharedWatch(&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				return cl.client.Services(cl.namespace).List(context.TODO(), options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				return cl.client.Services(cl.namespace).Watch(context.TODO(), options)
+			},
+		}, &servingv1.Service{})
+	}
+}
+
+// WithServerSideApply lets UpdateService apply the service server-side with the field
+// manager "kn" instead of replacing it. Only the fields changed since the service has been
+// read and the fields applied before are applied, so that the update doesn't conflict with
+// concurrent changes and fields which other managers own are kept.
+func WithServerSideApply() ClientOption {
+	return func(cl *knServingClient) {
+		cl.serverSideApply = true
+	}
+}
+
+// WithForceConflicts lets a server-side apply take over changed fields which other
+// managers own, instead of failing with a conflict
+func WithForceConflicts() ClientOption {
+	return func(cl *knServingClient) {
+		cl.forceConflicts = true
+	}
+}
+
+// WithDryRun lets the client send the changes of services with server-side dry-run.
+// The API server runs the admission webhooks and validation, but doesn't persist the
+// changes. The created or updated service is replaced with the server's response.
+func WithDryRun() ClientOption {
+	return func(cl *knServingClient) {
+		cl.dryRun = true
+	}
+}
+
+// dryRunOptions returns the value of the dryRun option of create, update and patch requests
+func (cl *knServingClient) dryRunOptions() []string {
+	if cl.dryRun {
+		return []string{v1.DryRunAll}
+	}
+	return nil
 }
 
 // Create a new client facade for the provided namespace
-func NewKnServingClient(client clientv1.ServingV1Interface, namespace string) KnServingClient {
-	return &knServingClient{
+func NewKnServingClient(client clientv1.ServingV1Interface, namespace string, options ...ClientOption) KnServingClient {
+	cl := &knServingClient{
 		client:    client,
 		namespace: namespace,
 	}
+	for _, option := range options {
+		option(cl)
+	}
+	return cl
 }
 
 // Return the client's namespace
@@ -186,7 +304,11 @@ func (cl *knServingClient) Namespace() string {
 
 // Get a service by its unique name
 func (cl *knServingClient) GetService(name string) (*servingv1.Service, error) {
-	service, err := cl.client.Services(cl.namespace).Get(context.TODO(), name, v1.GetOptions{})
+	var service *servingv1.Service
+	err := retryTransientGet(func() (err error) {
+		service, err = cl.client.Services(cl.namespace).Get(context.TODO(), name, v1.GetOptions{})
+		return err
+	})
 	if err != nil {
 		return nil, clienterrors.GetError(err)
 	}
@@ -194,10 +316,16 @@ func (cl *knServingClient) GetService(name string) (*servingv1.Service, error) {
 	if err != nil {
 		return nil, err
 	}
+	if cl.serverSideApply {
+		cl.rememberService(service)
+	}
 	return service, nil
 }
 
 func (cl *knServingClient) WatchService(name string, timeout time.Duration) (watch.Interface, error) {
+	if cl.serviceWatch != nil {
+		return cl.serviceWatch.Watch(name, timeout)
+	}
 	return wait.NewWatcher(cl.client.Services(cl.namespace).Watch,
 		cl.client.RESTClient(), cl.namespace, "services", name, timeout)
 }
@@ -231,33 +359,79 @@ func (cl *knServingClient) ListServices(config ...ListConfig) (*servingv1.Servic
 	return serviceListNew, nil
 }
 
+// Watch the services which match the list configs
+func (cl *knServingClient) WatchServices(config ...ListConfig) (watch.Interface, error) {
+	options := ListConfigs(config).toListOptions()
+	options.Watch = true
+	w, err := cl.client.Services(cl.namespace).Watch(context.TODO(), options)
+	if err != nil {
+		return nil, clienterrors.GetError(err)
+	}
+	return w, nil
+}
+
 // Create a new service
 func (cl *knServingClient) CreateService(service *servingv1.Service) error {
-	_, err := cl.client.Services(cl.namespace).Create(context.TODO(), service, v1.CreateOptions{})
+	result, err := cl.client.Services(cl.namespace).Create(context.TODO(), service, v1.CreateOptions{DryRun: cl.dryRunOptions()})
 	if err != nil {
 		return clienterrors.GetError(err)
 	}
+	if service.Name == "" {
+		// Name generated by the server from metadata.generateName
+		service.Name = result.Name
+	}
+	if cl.dryRun {
+		result.DeepCopyInto(service)
+	}
 	return updateServingGvk(service)
 }
 
 // Update the given service
 func (cl *knServingClient) UpdateService(service *servingv1.Service) error {
-	_, err := cl.client.Services(cl.namespace).Update(context.TODO(), service, v1.UpdateOptions{})
+	if cl.serverSideApply {
+		return cl.applyServiceServerSide(service)
+	}
+	result, err := cl.client.Services(cl.namespace).Update(context.TODO(), service, v1.UpdateOptions{DryRun: cl.dryRunOptions()})
 	if err != nil {
 		return err
 	}
+	if cl.dryRun {
+		result.DeepCopyInto(service)
+	}
 	return updateServingGvk(service)
 }
 
+// Backoff for GETs failing with a transient error like an etcd leader change.
+// Sums up to about 1.5s of waiting in total, can be replaced in tests
+var getRetryBackoff = k8swait.Backoff{
+	Duration: 100 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    5,
+}
+
+// retryTransientGet calls get until it succeeds, fails with a permanent
+// error or getRetryBackoff is exhausted. Used for GETs on the happy path of
+// create and replace, where a single hiccup of the API server shouldn't
+// fail the whole operation.
+func retryTransientGet(get func() error) error {
+	return retry.OnError(getRetryBackoff, func(err error) bool {
+		if wait.ClassifyError(err) != wait.ErrorCategoryTransient {
+			return false
+		}
+		klog.V(util.LogLevelRetry).InfoS("Retrying get after transient error", "error", err)
+		return true
+	}, get)
+}
+
 // Update the given service with a retry in case of a conflict
-func (cl *knServingClient) UpdateServiceWithRetry(name string, updateFunc ServiceUpdateFunc, nrRetries int) error {
-	return updateServiceWithRetry(cl, name, updateFunc, nrRetries)
+func (cl *knServingClient) UpdateServiceWithRetry(name string, updateFunc ServiceUpdateFunc, policy RetryPolicy) error {
+	return updateServiceWithRetry(cl, name, updateFunc, policy)
 }
 
 // Extracted to be usable with the Mocking client
-func updateServiceWithRetry(cl KnServingClient, name string, updateFunc ServiceUpdateFunc, nrRetries int) error {
-	var retries = 0
-	for {
+func updateServiceWithRetry(cl KnServingClient, name string, updateFunc ServiceUpdateFunc, policy RetryPolicy) error {
+	return RetryOnConflict(policy, "service", name, func() error {
 		service, err := cl.GetService(name)
 		if err != nil {
 			return err
@@ -269,20 +443,24 @@ func updateServiceWithRetry(cl KnServingClient, name string, updateFunc ServiceU
 		if err != nil {
 			return err
 		}
-
-		err = cl.UpdateService(updatedService)
+		patch, metadataOnly, err := metadataPatch(service, updatedService)
 		if err != nil {
-			// Retry to update when a resource version conflict exists
-			if apierrors.IsConflict(err) && retries < nrRetries {
-				retries++
-				// Wait a second before doing the retry
-				time.Sleep(time.Second)
-				continue
-			}
-			return fmt.Errorf("giving up after %d retries: %w", nrRetries, err)
+			return err
+		}
+		if metadataOnly {
+			return cl.PatchService(name, patch)
 		}
-		return nil
+		return cl.UpdateService(updatedService)
+	})
+}
+
+// Patch the service with a JSON merge patch
+func (cl *knServingClient) PatchService(name string, patch []byte) error {
+	_, err := cl.client.Services(cl.namespace).Patch(context.TODO(), name, types.MergePatchType, patch, v1.PatchOptions{DryRun: cl.dryRunOptions()})
+	if err != nil {
+		return clienterrors.GetError(err)
 	}
+	return nil
 }
 
 // ApplyService applies a service definition that contains the service's targer state
@@ -318,22 +496,16 @@ func (cl *knServingClient) DeleteService(serviceName string, timeout time.Durati
 	if timeout == 0 {
 		return cl.deleteService(serviceName, v1.DeletePropagationBackground)
 	}
-	waitC := make(chan error)
 	watcher, err := cl.WatchService(serviceName, timeout)
 	if err != nil {
-		return nil
+		return err
 	}
 	defer watcher.Stop()
-	go func() {
-		waitForEvent := wait.NewWaitForEvent("service", func(evt *watch.Event) bool { return evt.Type == watch.Deleted })
-		err, _ := waitForEvent.Wait(watcher, serviceName, wait.Options{Timeout: &timeout}, wait.NoopMessageCallback())
-		waitC <- err
-	}()
 	err = cl.deleteService(serviceName, v1.DeletePropagationForeground)
 	if err != nil {
 		return err
 	}
-	return <-waitC
+	return wait.WaitForDeletion(watcher, "service", serviceName, timeout)
 }
 
 func (cl *knServingClient) deleteService(serviceName string, propagationPolicy v1.DeletionPropagation) error {
@@ -351,13 +523,55 @@ func (cl *knServingClient) deleteService(serviceName string, propagationPolicy v
 
 // Wait for a service to become ready, but not longer than provided timeout
 func (cl *knServingClient) WaitForService(name string, timeout time.Duration, msgCallback wait.MessageCallback) (error, time.Duration) {
-	watcher, err := cl.WatchService(name, timeout)
+	return cl.WaitForServiceCondition(name, apis.ConditionReady, timeout, msgCallback)
+}
+
+// Wait for a condition of a service to become True, but not longer than provided timeout
+func (cl *knServingClient) WaitForServiceCondition(name string, conditionType apis.ConditionType, timeout time.Duration, msgCallback wait.MessageCallback) (error, time.Duration) {
+	var watcher watch.Interface
+	err := wait.RetryTransient(wait.DefaultTransientErrorTolerance, func() (err error) {
+		watcher, err = cl.WatchService(name, timeout)
+		return err
+	})
 	if err != nil {
 		return err, timeout
 	}
 	defer watcher.Stop()
-	waitForReady := wait.NewWaitForReady("service", serviceConditionExtractor)
-	return waitForReady.Wait(watcher, name, wait.Options{Timeout: &timeout}, msgCallback)
+	waitForCondition := wait.NewWaitForCondition("service", conditionType, serviceConditionExtractor)
+	return waitForCondition.Wait(watcher, name, wait.Options{Timeout: &timeout, CauseExtractor: cl.serviceCauses}, msgCallback)
+}
+
+// Wait for a service to reach the state checked by the predicate, but not longer than provided timeout
+func (cl *knServingClient) WaitForServicePredicate(name string, predicate ServicePredicate, timeout time.Duration, msgCallback wait.MessageCallback) (error, time.Duration) {
+	var watcher watch.Interface
+	err := wait.RetryTransient(wait.DefaultTransientErrorTolerance, func() (err error) {
+		watcher, err = cl.WatchService(name, timeout)
+		return err
+	})
+	if err != nil {
+		return err, timeout
+	}
+	defer watcher.Stop()
+	waitForPredicate := wait.NewWaitForPredicate("service", serviceConditionExtractor, func(obj runtime.Object, _ apis.Conditions) (bool, error) {
+		service, ok := obj.(*servingv1.Service)
+		if !ok {
+			return false, fmt.Errorf("%v is not a service", obj)
+		}
+		return predicate(service)
+	})
+	return waitForPredicate.Wait(watcher, name, wait.Options{Timeout: &timeout}, msgCallback)
+}
+
+// ServiceReadyWith returns a predicate which is true when the service is ready and the check is true for it,
+// e.g. for waiting until a specific revision is the latest created revision. Waiting stops with an error
+// when the service is not ready.
+func ServiceReadyWith(check func(service *servingv1.Service) bool) ServicePredicate {
+	ready := wait.ReadyPredicate(func(obj runtime.Object) bool {
+		return check(obj.(*servingv1.Service))
+	})
+	return func(service *servingv1.Service) (bool, error) {
+		return ready(service, apis.Conditions(service.Status.Conditions))
+	}
 }
 
 // Get the configuration for a service
@@ -469,23 +683,16 @@ func (cl *knServingClient) DeleteRevision(name string, timeout time.Duration) er
 	if timeout == 0 {
 		return cl.deleteRevision(name)
 	}
-	waitC := make(chan error)
 	watcher, err := cl.WatchRevision(name, timeout)
 	if err != nil {
 		return err
 	}
 	defer watcher.Stop()
-	go func() {
-		waitForEvent := wait.NewWaitForEvent("revision", func(evt *watch.Event) bool { return evt.Type == watch.Deleted })
-		err, _ := waitForEvent.Wait(watcher, name, wait.Options{Timeout: &timeout}, wait.NoopMessageCallback())
-		waitC <- err
-	}()
 	err = cl.deleteRevision(name)
 	if err != nil {
 		return clienterrors.GetError(err)
 	}
-
-	return <-waitC
+	return wait.WaitForDeletion(watcher, "revision", name, timeout)
 }
 
 func (cl *knServingClient) deleteRevision(name string) error {
@@ -506,6 +713,51 @@ func (cl *knServingClient) ListRevisions(config ...ListConfig) (*servingv1.Revis
 	return updateServingGvkForRevisionList(revisionList)
 }
 
+// List the revisions of a service which can be deleted safely
+func (cl *knServingClient) ListUnroutedRevisions(serviceName string) (*servingv1.RevisionList, error) {
+	service, err := cl.GetService(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	revisionList, err := cl.ListRevisions(WithService(serviceName))
+	if err != nil {
+		return nil, err
+	}
+	referenced := referencedRevisions(service)
+	unrouted := revisionList.Items[:0]
+	for _, revision := range revisionList.Items {
+		if !referenced[revision.Name] && !IsRevisionRouted(&revision) {
+			unrouted = append(unrouted, revision)
+		}
+	}
+	revisionList.Items = unrouted
+	return revisionList, nil
+}
+
+// IsRevisionRouted returns true if a route references the revision, according to its
+// routing state label or, for older Knative versions, its routes annotation
+func IsRevisionRouted(revision *servingv1.Revision) bool {
+	return revision.Labels[apiserving.RoutingStateLabelKey] == string(servingv1.RoutingStateActive) ||
+		revision.Annotations[apiserving.RoutesAnnotationKey] != ""
+}
+
+// referencedRevisions returns the names of the revisions which the traffic of the service
+// references, including the latest revisions which a route may pick up at any time
+func referencedRevisions(service *servingv1.Service) map[string]bool {
+	referenced := map[string]bool{
+		service.Status.LatestCreatedRevisionName: true,
+		service.Status.LatestReadyRevisionName:   true,
+	}
+	for _, targets := range [][]servingv1.TrafficTarget{service.Spec.Traffic, service.Status.Traffic} {
+		for _, target := range targets {
+			if target.RevisionName != "" {
+				referenced[target.RevisionName] = true
+			}
+		}
+	}
+	return referenced
+}
+
 // Get a route by its unique name
 func (cl *knServingClient) GetRoute(name string) (*servingv1.Route, error) {
 	route, err := cl.client.Routes(cl.namespace).Get(context.TODO(), name, v1.GetOptions{})
@@ -582,3 +834,31 @@ func serviceConditionExtractor(obj runtime.Object) (apis.Conditions, error) {
 	}
 	return apis.Conditions(service.Status.Conditions), nil
 }
+
+// serviceCauses returns the failed conditions of the latest created revision of a service,
+// like an image pull backoff, or else the failure of its configuration, which explain
+// why the service doesn't become ready
+func (cl *knServingClient) serviceCauses(obj runtime.Object) ([]wait.Cause, error) {
+	service, ok := obj.(*servingv1.Service)
+	if !ok {
+		return nil, fmt.Errorf("%v is not a service", obj)
+	}
+	if name := service.Status.LatestCreatedRevisionName; name != "" {
+		revision, err := cl.client.Revisions(cl.namespace).Get(context.TODO(), name, v1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		if err == nil {
+			causes := wait.FailedConditions("revision", name, apis.Conditions(revision.Status.Conditions))
+			if len(causes) > 0 {
+				return causes, nil
+			}
+		}
+	}
+	// The Ready condition of the configuration is propagated to the service
+	cond := service.Status.GetCondition(servingv1.ServiceConditionConfigurationsReady)
+	if cond != nil && cond.IsFalse() {
+		return []wait.Cause{{Kind: "configuration", Name: service.Name, Condition: apis.ConditionReady, Reason: cond.Reason, Message: cond.Message}}, nil
+	}
+	return nil, nil
+}
@@ -21,6 +21,8 @@ import (
 	"gotest.tools/assert"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+	"knative.dev/pkg/apis"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
 	"knative.dev/client/pkg/util/mock"
@@ -79,6 +81,19 @@ func (c *MockKnServingClient) ListServices(opts ...ListConfig) (*servingv1.Servi
 	return call.Result[0].(*servingv1.ServiceList), mock.ErrorOrNil(call.Result[1])
 }
 
+// Watch services
+func (sr *ServingRecorder) WatchServices(opts interface{}, watcher watch.Interface, err error) {
+	sr.r.Add("WatchServices", []interface{}{opts}, []interface{}{watcher, err})
+}
+
+func (c *MockKnServingClient) WatchServices(opts ...ListConfig) (watch.Interface, error) {
+	call := c.recorder.r.VerifyCall("WatchServices", opts)
+	if call.Result[0] == nil {
+		return nil, mock.ErrorOrNil(call.Result[1])
+	}
+	return call.Result[0].(watch.Interface), mock.ErrorOrNil(call.Result[1])
+}
+
 // Create a new service
 func (sr *ServingRecorder) CreateService(service interface{}, err error) {
 	sr.r.Add("CreateService", []interface{}{service}, []interface{}{err})
@@ -100,8 +115,18 @@ func (c *MockKnServingClient) UpdateService(service *servingv1.Service) error {
 }
 
 // Delegate to shared retry method
-func (c *MockKnServingClient) UpdateServiceWithRetry(name string, updateFunc ServiceUpdateFunc, maxRetry int) error {
-	return updateServiceWithRetry(c, name, updateFunc, maxRetry)
+func (c *MockKnServingClient) UpdateServiceWithRetry(name string, updateFunc ServiceUpdateFunc, policy RetryPolicy) error {
+	return updateServiceWithRetry(c, name, updateFunc, policy)
+}
+
+// Patch the given service
+func (sr *ServingRecorder) PatchService(name interface{}, patch interface{}, err error) {
+	sr.r.Add("PatchService", []interface{}{name, patch}, []interface{}{err})
+}
+
+func (c *MockKnServingClient) PatchService(name string, patch []byte) error {
+	call := c.recorder.r.VerifyCall("PatchService", name, patch)
+	return mock.ErrorOrNil(call.Result[0])
 }
 
 // Update the given service
@@ -134,6 +159,26 @@ func (c *MockKnServingClient) WaitForService(name string, timeout time.Duration,
 	return mock.ErrorOrNil(call.Result[0]), call.Result[1].(time.Duration)
 }
 
+// Wait for a service to reach the state checked by the predicate
+func (sr *ServingRecorder) WaitForServicePredicate(name interface{}, predicate interface{}, timeout interface{}, callback interface{}, err error, duration time.Duration) {
+	sr.r.Add("WaitForServicePredicate", []interface{}{name, predicate, timeout, callback}, []interface{}{err, duration})
+}
+
+func (c *MockKnServingClient) WaitForServicePredicate(name string, predicate ServicePredicate, timeout time.Duration, msgCallback wait.MessageCallback) (error, time.Duration) {
+	call := c.recorder.r.VerifyCall("WaitForServicePredicate", name, predicate, timeout, msgCallback)
+	return mock.ErrorOrNil(call.Result[0]), call.Result[1].(time.Duration)
+}
+
+// Wait for a condition of a service to become True, but not longer than provided timeout
+func (sr *ServingRecorder) WaitForServiceCondition(name interface{}, conditionType interface{}, timeout interface{}, callback interface{}, err error, duration time.Duration) {
+	sr.r.Add("WaitForServiceCondition", []interface{}{name, conditionType, timeout, callback}, []interface{}{err, duration})
+}
+
+func (c *MockKnServingClient) WaitForServiceCondition(name string, conditionType apis.ConditionType, timeout time.Duration, msgCallback wait.MessageCallback) (error, time.Duration) {
+	call := c.recorder.r.VerifyCall("WaitForServiceCondition", name, conditionType, timeout, msgCallback)
+	return mock.ErrorOrNil(call.Result[0]), call.Result[1].(time.Duration)
+}
+
 // Get a revision by name
 func (sr *ServingRecorder) GetRevision(name interface{}, revision *servingv1.Revision, err error) {
 	sr.r.Add("GetRevision", []interface{}{name}, []interface{}{revision, err})
@@ -164,6 +209,16 @@ func (c *MockKnServingClient) DeleteRevision(name string, timeout time.Duration)
 	return mock.ErrorOrNil(call.Result[0])
 }
 
+// List the revisions of a service which can be deleted safely
+func (sr *ServingRecorder) ListUnroutedRevisions(serviceName interface{}, revisionList *servingv1.RevisionList, err error) {
+	sr.r.Add("ListUnroutedRevisions", []interface{}{serviceName}, []interface{}{revisionList, err})
+}
+
+func (c *MockKnServingClient) ListUnroutedRevisions(serviceName string) (*servingv1.RevisionList, error) {
+	call := c.recorder.r.VerifyCall("ListUnroutedRevisions", serviceName)
+	return call.Result[0].(*servingv1.RevisionList), mock.ErrorOrNil(call.Result[1])
+}
+
 // Get a route by its unique name
 func (sr *ServingRecorder) GetRoute(name interface{}, route *servingv1.Route, err error) {
 	sr.r.Add("GetRoute", []interface{}{name}, []interface{}{route, err})
@@ -18,6 +18,7 @@ import (
 	"testing"
 	"time"
 
+	"knative.dev/pkg/apis"
 	"knative.dev/serving/pkg/apis/serving"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
@@ -35,16 +36,21 @@ func TestMockKnClient(t *testing.T) {
 	recorder.GetService("hello", nil, nil)
 	recorder.ListServices(mock.Any(), nil, nil)
 	recorder.ListServices(mock.Any(), nil, nil)
+	recorder.WatchServices(mock.Any(), nil, nil)
 	recorder.CreateService(&servingv1.Service{}, nil)
 	recorder.UpdateService(&servingv1.Service{}, nil)
+	recorder.PatchService("hello", []byte(`{}`), nil)
 	recorder.ApplyService(&servingv1.Service{}, true, nil)
 	recorder.DeleteService("hello", time.Duration(10)*time.Second, nil)
 	recorder.WaitForService("hello", time.Duration(10)*time.Second, wait.NoopMessageCallback(), nil, 10*time.Second)
+	recorder.WaitForServiceCondition("hello", apis.ConditionType("RoutesReady"), time.Duration(10)*time.Second, wait.NoopMessageCallback(), nil, 10*time.Second)
+	recorder.WaitForServicePredicate("hello", mock.Any(), time.Duration(10)*time.Second, mock.Any(), nil, 10*time.Second)
 	recorder.GetRevision("hello", nil, nil)
 	recorder.ListRevisions(mock.Any(), nil, nil)
 	recorder.CreateRevision(&servingv1.Revision{}, nil)
 	recorder.UpdateRevision(&servingv1.Revision{}, nil)
 	recorder.DeleteRevision("hello", time.Duration(10)*time.Second, nil)
+	recorder.ListUnroutedRevisions("hello", nil, nil)
 	recorder.GetRoute("hello", nil, nil)
 	recorder.ListRoutes(mock.Any(), nil, nil)
 	recorder.GetConfiguration("hello", nil, nil)
@@ -53,16 +59,21 @@ func TestMockKnClient(t *testing.T) {
 	client.GetService("hello")
 	client.ListServices(WithName("blub"))
 	client.ListServices(WithLabel("foo", "bar"))
+	client.WatchServices(WithResourceVersion("1"))
 	client.CreateService(&servingv1.Service{})
 	client.UpdateService(&servingv1.Service{})
+	client.PatchService("hello", []byte(`{}`))
 	client.ApplyService(&servingv1.Service{})
 	client.DeleteService("hello", time.Duration(10)*time.Second)
 	client.WaitForService("hello", time.Duration(10)*time.Second, wait.NoopMessageCallback())
+	client.WaitForServiceCondition("hello", "RoutesReady", time.Duration(10)*time.Second, wait.NoopMessageCallback())
+	client.WaitForServicePredicate("hello", ServiceReadyWith(func(*servingv1.Service) bool { return true }), time.Duration(10)*time.Second, wait.NoopMessageCallback())
 	client.GetRevision("hello")
 	client.ListRevisions(WithName("blub"))
 	client.CreateRevision(&servingv1.Revision{})
 	client.UpdateRevision(&servingv1.Revision{})
 	client.DeleteRevision("hello", time.Duration(10)*time.Second)
+	client.ListUnroutedRevisions("hello")
 	client.GetRoute("hello")
 	client.ListRoutes(WithName("blub"))
 	client.GetConfiguration("hello")
@@ -15,7 +15,12 @@
 package v1
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
@@ -23,6 +28,8 @@ import (
 	"gotest.tools/assert/cmp"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/watch"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/serving/pkg/apis/serving"
 	"knative.dev/serving/pkg/client/clientset/versioned/scheme"
 
@@ -31,9 +38,13 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
 	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8swait "k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
 	clienttesting "k8s.io/client-go/testing"
 
 	"knative.dev/client/pkg/util"
@@ -81,6 +92,54 @@ func TestGetService(t *testing.T) {
 	})
 }
 
+func TestGetServiceRetryTransient(t *testing.T) {
+	defer func(backoff k8swait.Backoff) {
+		getRetryBackoff = backoff
+	}(getRetryBackoff)
+	getRetryBackoff = k8swait.Backoff{Duration: time.Millisecond, Factor: 2, Steps: 3}
+
+	serving, client := setup()
+	serviceName := "test-service"
+	calls := 0
+	failures := 0
+	serving.AddReactor("get", "services",
+		func(a clienttesting.Action) (bool, runtime.Object, error) {
+			calls++
+			if calls <= failures {
+				return true, nil, fmt.Errorf("etcdserver: leader changed")
+			}
+			return true, newService(serviceName), nil
+		})
+
+	t.Run("transient errors are retried", func(t *testing.T) {
+		calls, failures = 0, 2
+		service, err := client.GetService(serviceName)
+		assert.NilError(t, err)
+		assert.Equal(t, serviceName, service.Name)
+		assert.Equal(t, calls, 3)
+	})
+
+	t.Run("retries are bounded", func(t *testing.T) {
+		calls, failures = 0, 10
+		_, err := client.GetService(serviceName)
+		assert.ErrorContains(t, err, "leader changed")
+		assert.Equal(t, calls, 3)
+	})
+
+	t.Run("not found is not retried", func(t *testing.T) {
+		serving, client := setup()
+		calls := 0
+		serving.AddReactor("get", "services",
+			func(a clienttesting.Action) (bool, runtime.Object, error) {
+				calls++
+				return true, nil, errors.NewNotFound(servingv1.Resource("service"), serviceName)
+			})
+		_, err := client.GetService(serviceName)
+		assert.Assert(t, errors.IsNotFound(err))
+		assert.Equal(t, calls, 1)
+	})
+}
+
 func TestListService(t *testing.T) {
 	serving, client := setup()
 
@@ -126,6 +185,28 @@ func TestListService(t *testing.T) {
 
 }
 
+func TestWatchServices(t *testing.T) {
+	serving, client := setup()
+
+	serving.AddWatchReactor("services",
+		func(a clienttesting.Action) (bool, watch.Interface, error) {
+			assert.Equal(t, testNamespace, a.GetNamespace())
+			restrictions := a.(clienttesting.WatchAction).GetWatchRestrictions()
+			assert.Equal(t, restrictions.ResourceVersion, "42")
+			assert.Equal(t, restrictions.Labels.String(), "foo=bar")
+			w := wait.NewFakeWatch(getServiceEvents("test-service"))
+			w.Start()
+			return true, w, nil
+		})
+
+	w, err := client.WatchServices(WithLabel("foo", "bar"), WithResourceVersion("42"))
+	assert.NilError(t, err)
+	event := <-w.ResultChan()
+	assert.Equal(t, event.Type, watch.Added)
+	assert.Equal(t, event.Object.(*servingv1.Service).Name, "test-service")
+	w.Stop()
+}
+
 func TestCreateService(t *testing.T) {
 	serving, client := setup()
 
@@ -133,7 +214,12 @@ func TestCreateService(t *testing.T) {
 	serving.AddReactor("create", "services",
 		func(a clienttesting.Action) (bool, runtime.Object, error) {
 			assert.Equal(t, testNamespace, a.GetNamespace())
-			name := a.(clienttesting.CreateAction).GetObject().(metav1.Object).GetName()
+			object := a.(clienttesting.CreateAction).GetObject().(metav1.Object)
+			name := object.GetName()
+			if name == "" && object.GetGenerateName() == "ci-" {
+				generated := newService("ci-x7k2p")
+				return true, generated, nil
+			}
 			if name == serviceNew.Name {
 				serviceNew.Generation = 2
 				return true, serviceNew, nil
@@ -152,6 +238,14 @@ func TestCreateService(t *testing.T) {
 		err := client.CreateService(newService("unknown"))
 		assert.ErrorContains(t, err, "unknown")
 	})
+
+	t.Run("create service with a generated name sets the name", func(t *testing.T) {
+		service := newService("")
+		service.GenerateName = "ci-"
+		err := client.CreateService(service)
+		assert.NilError(t, err)
+		assert.Equal(t, service.Name, "ci-x7k2p")
+	})
 }
 
 func TestUpdateService(t *testing.T) {
@@ -182,6 +276,160 @@ func TestUpdateService(t *testing.T) {
 	})
 }
 
+func TestUpdateServiceServerSideApply(t *testing.T) {
+	base := newService("apply-service")
+	base.ResourceVersion = "1"
+	base.Labels = map[string]string{"team": "a", "owner": "ops", "tier": "web"}
+	base.Annotations = map[string]string{"example.com/note": "hello"}
+	base.Spec.Template.Spec.Containers = []corev1.Container{{Image: "gcr.io/foo/bar:v1"}}
+	base.ManagedFields = []metav1.ManagedFieldsEntry{
+		{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationUpdate,
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{"f:owner":{},"f:tier":{}}},"f:spec":{"f:template":{"f:spec":{"f:containers":{}}}}}`)}},
+		{Manager: FieldManager, Operation: metav1.ManagedFieldsOperationApply,
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{"f:team":{}},"f:annotations":{"f:example.com/note":{}}}}`)}},
+	}
+
+	t.Run("apply changed and owned fields only", func(t *testing.T) {
+		server := newApplyServer(base)
+		client := NewKnServingClient(server.client(t), testNamespace, WithServerSideApply())
+		service, err := client.GetService("apply-service")
+		assert.NilError(t, err)
+		service.Spec.Template.Spec.Containers[0].Image = "gcr.io/foo/bar:v2"
+		service.Labels["new"] = "label"
+		service.Status.ObservedGeneration = 2
+
+		assert.NilError(t, client.UpdateService(service))
+		// Read once only, the service as read is the base of the apply
+		assert.DeepEqual(t, server.requests, []string{"GET", "PATCH application/apply-patch+yaml"})
+		applied := server.patches[0]
+		assert.Equal(t, applied.query.Get("fieldManager"), FieldManager)
+		assert.Equal(t, applied.query.Get("force"), "")
+		assert.DeepEqual(t, applied.body, map[string]interface{}{
+			"apiVersion": "serving.knative.dev/v1",
+			"kind":       "Service",
+			"metadata": map[string]interface{}{
+				"name":      "apply-service",
+				"namespace": testNamespace,
+				// The unchanged labels of kubectl are left out, so that it keeps owning them
+				"labels":      map[string]interface{}{"team": "a", "new": "label"},
+				"annotations": map[string]interface{}{"example.com/note": "hello"},
+			},
+			"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{"name": "", "image": "gcr.io/foo/bar:v2", "resources": map[string]interface{}{}}},
+			}}},
+		})
+		assert.Equal(t, service.ResourceVersion, "2")
+		assert.Equal(t, service.Generation, int64(3))
+		validateGroupVersionKind(t, service)
+	})
+
+	t.Run("remove fields of other managers", func(t *testing.T) {
+		server := newApplyServer(base)
+		client := NewKnServingClient(server.client(t), testNamespace, WithServerSideApply())
+		service, err := client.GetService("apply-service")
+		assert.NilError(t, err)
+		delete(service.Labels, "owner")
+		delete(service.Labels, "team")
+		service.Annotations = nil
+
+		assert.NilError(t, client.UpdateService(service))
+		assert.DeepEqual(t, server.requests, []string{"GET", "PATCH application/apply-patch+yaml", "PATCH application/json-patch+json"})
+		// kn's label and annotation are removed by leaving them out of the apply
+		assert.DeepEqual(t, server.patches[0].body["metadata"], map[string]interface{}{
+			"name": "apply-service", "namespace": testNamespace,
+		})
+		// The label of kubectl is removed explicitly, the one kept stays with kubectl
+		assert.DeepEqual(t, server.patches[1].operations, []map[string]interface{}{
+			{"op": "remove", "path": "/metadata/labels/owner"},
+		})
+	})
+
+	t.Run("read the base if the service hasn't been read", func(t *testing.T) {
+		server := newApplyServer(base)
+		client := NewKnServingClient(server.client(t), testNamespace, WithServerSideApply())
+		service := base.DeepCopy()
+		service.ResourceVersion = ""
+		service.Labels["tier"] = "backend"
+		assert.NilError(t, client.UpdateService(service))
+		assert.DeepEqual(t, server.requests, []string{"GET", "PATCH application/apply-patch+yaml"})
+		assert.DeepEqual(t, server.patches[0].body["metadata"].(map[string]interface{})["labels"],
+			map[string]interface{}{"team": "a", "tier": "backend"})
+	})
+
+	t.Run("report conflicts", func(t *testing.T) {
+		server := newApplyServer(base)
+		server.conflict = true
+		client := NewKnServingClient(server.client(t), testNamespace, WithServerSideApply())
+		service, err := client.GetService("apply-service")
+		assert.NilError(t, err)
+		service.Spec.Template.Spec.Containers[0].Image = "gcr.io/foo/bar:v2"
+		err = client.UpdateService(service)
+		assert.ErrorContains(t, err, `conflict with "kubectl" using serving.knative.dev/v1`)
+		assert.ErrorContains(t, err, "--force-conflicts")
+		assert.Assert(t, !errors.IsConflict(err))
+	})
+
+	t.Run("force conflicts", func(t *testing.T) {
+		server := newApplyServer(base)
+		client := NewKnServingClient(server.client(t), testNamespace, WithServerSideApply(), WithForceConflicts())
+		assert.NilError(t, client.UpdateService(base.DeepCopy()))
+		assert.Equal(t, server.patches[0].query.Get("force"), "true")
+	})
+}
+
+// applyServer is an API server which returns a service and records the patches sent
+type applyServer struct {
+	service  *servingv1.Service
+	conflict bool
+	requests []string
+	patches  []sentPatch
+}
+
+type sentPatch struct {
+	query      url.Values
+	body       map[string]interface{}
+	operations []map[string]interface{}
+}
+
+func newApplyServer(service *servingv1.Service) *applyServer {
+	return &applyServer{service: service}
+}
+
+func (s *applyServer) client(t *testing.T) servingv1client.ServingV1Interface {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			s.requests = append(s.requests, r.Method)
+			assert.NilError(t, json.NewEncoder(w).Encode(s.service))
+			return
+		}
+		contentType := r.Header.Get("Content-Type")
+		s.requests = append(s.requests, r.Method+" "+contentType)
+		sent := sentPatch{query: r.URL.Query()}
+		if contentType == string(types.JSONPatchType) {
+			assert.NilError(t, json.NewDecoder(r.Body).Decode(&sent.operations))
+		} else {
+			assert.NilError(t, json.NewDecoder(r.Body).Decode(&sent.body))
+		}
+		s.patches = append(s.patches, sent)
+		if s.conflict {
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Conflict","code":409,`+
+				`"message":"Apply failed with 1 conflict: conflict with \"kubectl\" using serving.knative.dev/v1: .spec.template.spec.containers",`+
+				`"details":{"causes":[{"reason":"FieldManagerConflict","message":"conflict with \"kubectl\" using serving.knative.dev/v1","field":".spec.template.spec.containers"}]}}`)
+			return
+		}
+		result := s.service.DeepCopy()
+		result.ResourceVersion = "2"
+		result.Generation = 3
+		assert.NilError(t, json.NewEncoder(w).Encode(result))
+	}))
+	t.Cleanup(server.Close)
+	client, err := servingv1client.NewForConfig(&rest.Config{Host: server.URL})
+	assert.NilError(t, err)
+	return client
+}
+
 func TestDeleteService(t *testing.T) {
 	serving, client := setup()
 	const (
@@ -326,6 +574,40 @@ func TestListRevisionForService(t *testing.T) {
 		})
 }
 
+func TestListUnroutedRevisions(t *testing.T) {
+	fakeServing, client := setup()
+
+	service := newService("foo")
+	percent := int64(100)
+	service.Spec.Traffic = []servingv1.TrafficTarget{{RevisionName: "foo-00002", Percent: &percent}}
+	service.Status.LatestCreatedRevisionName = "foo-00005"
+	service.Status.LatestReadyRevisionName = "foo-00004"
+	fakeServing.AddReactor("get", "services",
+		func(a clienttesting.Action) (bool, runtime.Object, error) {
+			return true, service, nil
+		})
+	fakeServing.AddReactor("list", "revisions",
+		func(a clienttesting.Action) (bool, runtime.Object, error) {
+			routed := newRevision("foo-00003", serving.ServiceLabelKey, "foo")
+			routed.Annotations = map[string]string{serving.RoutesAnnotationKey: "foo"}
+			return true, &servingv1.RevisionList{Items: []servingv1.Revision{
+				*newRevision("foo-00001", serving.ServiceLabelKey, "foo"),
+				*newRevision("foo-00002", serving.ServiceLabelKey, "foo"),
+				*routed,
+				*newRevision("foo-00004", serving.ServiceLabelKey, "foo"),
+				*newRevision("foo-00005", serving.ServiceLabelKey, "foo"),
+				*newRevision("foo-00006", serving.ServiceLabelKey, "foo", serving.RoutingStateLabelKey, string(servingv1.RoutingStateActive)),
+				*newRevision("foo-00007", serving.ServiceLabelKey, "foo", serving.RoutingStateLabelKey, string(servingv1.RoutingStateReserve)),
+			}}, nil
+		})
+
+	revisions, err := client.ListUnroutedRevisions("foo")
+	assert.NilError(t, err)
+	assert.Assert(t, cmp.Len(revisions.Items, 2))
+	assert.Equal(t, revisions.Items[0].Name, "foo-00001")
+	assert.Equal(t, revisions.Items[1].Name, "foo-00007")
+}
+
 func TestGetRoute(t *testing.T) {
 	serving, client := setup()
 	routeName := "test-route"
@@ -434,6 +716,102 @@ func TestWaitForService(t *testing.T) {
 		assert.NilError(t, err)
 		assert.Assert(t, duration > 0)
 	})
+
+	t.Run("wait on the routes of a service to become ready", func(t *testing.T) {
+		var messages []string
+		err, _ := client.WaitForServiceCondition(serviceName, "RoutesReady", 60*time.Second, func(_ time.Duration, message string) {
+			messages = append(messages, message)
+		})
+		assert.NilError(t, err)
+		// Only the messages of RoutesReady are reported, not the ones of Ready
+		assert.Equal(t, len(messages), 0)
+	})
+
+	t.Run("wait on a service to reach a custom state", func(t *testing.T) {
+		var states []string
+		err, _ := client.WaitForServicePredicate(serviceName, func(service *servingv1.Service) (bool, error) {
+			for _, cond := range service.Status.Conditions {
+				if cond.Type == "RoutesReady" {
+					states = append(states, string(cond.Status))
+					return cond.Status == corev1.ConditionTrue, nil
+				}
+			}
+			return false, nil
+		}, 60*time.Second, wait.NoopMessageCallback())
+		assert.NilError(t, err)
+		// The initial state is checked too, waiting ends before the service is ready
+		assert.DeepEqual(t, states, []string{"Unknown", "True"})
+	})
+
+	t.Run("stop waiting with the error of the predicate", func(t *testing.T) {
+		err, _ := client.WaitForServicePredicate(serviceName, func(service *servingv1.Service) (bool, error) {
+			return false, fmt.Errorf("service '%s' is broken", service.Name)
+		}, 60*time.Second, wait.NoopMessageCallback())
+		assert.Error(t, err, "service 'test-service' is broken")
+	})
+}
+
+func TestWaitForServiceCauses(t *testing.T) {
+	serving, client := setup()
+
+	serviceName := "test-service"
+	pending := wait.CreateTestServiceWithConditions(serviceName, corev1.ConditionUnknown, corev1.ConditionUnknown, "",
+		"Configuration \"test-service\" is waiting for a Revision to become ready.").(*servingv1.Service)
+	pending.Status.LatestCreatedRevisionName = "test-service-00001"
+	serving.AddWatchReactor("services",
+		func(a clienttesting.Action) (bool, watch.Interface, error) {
+			w := wait.NewFakeWatch([]watch.Event{{Type: watch.Modified, Object: pending}})
+			w.Start()
+			return true, w, nil
+		})
+	serving.AddReactor("get", "revisions",
+		func(a clienttesting.Action) (bool, runtime.Object, error) {
+			assert.Equal(t, a.(clienttesting.GetAction).GetName(), "test-service-00001")
+			revision := &servingv1.Revision{ObjectMeta: metav1.ObjectMeta{Name: "test-service-00001"}}
+			revision.Status.Conditions = duckv1.Conditions{
+				{Type: apis.ConditionReady, Status: corev1.ConditionUnknown, Reason: "Deploying"},
+				{Type: "ContainerHealthy", Status: corev1.ConditionUnknown, Reason: "ImagePullBackOff",
+					Message: "Back-off pulling image \"registry/app:v1\""},
+			}
+			return true, revision, nil
+		})
+
+	var messages []string
+	err, _ := client.WaitForService(serviceName, time.Second, func(_ time.Duration, message string) {
+		messages = append(messages, message)
+	})
+	// The revision's failure is reported with the messages and the timeout
+	assert.DeepEqual(t, messages, []string{"Configuration \"test-service\" is waiting for a Revision to become ready. " +
+		"(caused by revision 'test-service-00001': ImagePullBackOff: Back-off pulling image \"registry/app:v1\")"})
+	assert.ErrorContains(t, err, "timeout: service 'test-service' not ready after 1 seconds "+
+		"(caused by revision 'test-service-00001': ImagePullBackOff")
+}
+
+func TestWaitForServiceWithSharedWaits(t *testing.T) {
+	serving := servingv1fake.FakeServingV1{Fake: &clienttesting.Fake{}}
+	client := NewKnServingClient(&serving, testNamespace, WithSharedWaits())
+
+	serviceName := "test-service"
+	serving.AddReactor("list", "services",
+		func(a clienttesting.Action) (bool, runtime.Object, error) {
+			service := wait.CreateTestServiceWithConditions(serviceName, corev1.ConditionUnknown, corev1.ConditionUnknown, "", "")
+			return true, &servingv1.ServiceList{Items: []servingv1.Service{*service.(*servingv1.Service)}}, nil
+		})
+	watches := 0
+	serving.AddWatchReactor("services",
+		func(a clienttesting.Action) (bool, watch.Interface, error) {
+			watches++
+			// The shared informer watches all services of the namespace
+			_, found := a.(clienttesting.WatchAction).GetWatchRestrictions().Fields.RequiresExactMatch("metadata.name")
+			assert.Assert(t, !found)
+			w := wait.NewFakeWatch(getServiceEvents(serviceName))
+			w.Start()
+			return true, w, nil
+		})
+
+	err, _ := client.WaitForService(serviceName, 60*time.Second, wait.NoopMessageCallback())
+	assert.NilError(t, err)
+	assert.Equal(t, watches, 1)
 }
 
 type baseRevisionCase struct {
@@ -555,3 +933,36 @@ func getServiceEvents(name string) []watch.Event {
 		{Type: watch.Modified, Object: wait.CreateTestServiceWithConditions(name, corev1.ConditionTrue, corev1.ConditionTrue, "", "")},
 	}
 }
+
+func TestDryRun(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Query().Get("dryRun"))
+		w.Header().Set("Content-Type", "application/json")
+		// The server's response contains the defaults set by the webhooks
+		fmt.Fprint(w, `{"apiVersion":"serving.knative.dev/v1","kind":"Service","metadata":{"name":"foo","namespace":"test-ns","generation":2},`+
+			`"spec":{"template":{"spec":{"containers":[{"image":"nginx"}],"timeoutSeconds":300}}}}`)
+	}))
+	defer server.Close()
+
+	servingClient, err := servingv1client.NewForConfig(&rest.Config{Host: server.URL})
+	assert.NilError(t, err)
+	client := NewKnServingClient(servingClient, testNamespace, WithDryRun())
+
+	service := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: testNamespace}}
+	assert.NilError(t, client.CreateService(service))
+	assert.Equal(t, *service.Spec.Template.Spec.TimeoutSeconds, int64(300))
+
+	service = &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: testNamespace}}
+	assert.NilError(t, client.UpdateService(service))
+	assert.Equal(t, service.Generation, int64(2))
+
+	assert.NilError(t, client.PatchService("foo", []byte(`{"metadata":{"labels":{"a":"b"}}}`)))
+
+	client = NewKnServingClient(servingClient, testNamespace, WithDryRun(), WithServerSideApply())
+	assert.NilError(t, client.UpdateService(service))
+
+	// The server-side apply reads the service first to find out what has been changed
+	assert.DeepEqual(t, requests, []string{"POST All", "PUT All", "PATCH All", "GET ", "PATCH All"})
+}
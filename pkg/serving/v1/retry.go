@@ -0,0 +1,142 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8swait "k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/util"
+)
+
+// RetryPolicy configures how often and when an update is retried after it
+// conflicted with a concurrent change of the same resource
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt, 0 disables retries
+	MaxRetries int
+	// Backoff is the delay before the first retry
+	Backoff time.Duration
+	// Factor multiplies the delay for every further retry, 1 keeps it constant
+	Factor float64
+	// Jitter adds up to this fraction of the delay, so that concurrent
+	// clients don't retry in lockstep
+	Jitter float64
+}
+
+// DefaultRetryPolicy retries three times after 200ms, 400ms and 800ms, each plus up to 10%
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	Backoff:    200 * time.Millisecond,
+	Factor:     2,
+	Jitter:     0.1,
+}
+
+// Validate checks that all values of the policy are in range
+func (p RetryPolicy) Validate() error {
+	switch {
+	case p.MaxRetries < 0:
+		return fmt.Errorf("the number of retries must not be negative, got %d", p.MaxRetries)
+	case p.Backoff < 0:
+		return fmt.Errorf("the retry backoff must not be negative, got %s", p.Backoff)
+	case p.Factor < 1:
+		return fmt.Errorf("the retry backoff factor must be at least 1, got %g", p.Factor)
+	case p.Jitter < 0:
+		return fmt.Errorf("the retry jitter must not be negative, got %g", p.Jitter)
+	}
+	return nil
+}
+
+// Delay returns how long to wait before the given retry, counted from 1
+func (p RetryPolicy) Delay(retry int) time.Duration {
+	delay := time.Duration(float64(p.Backoff) * math.Pow(p.Factor, float64(retry-1)))
+	if p.Jitter > 0 {
+		delay = k8swait.Jitter(delay, p.Jitter)
+	}
+	return delay
+}
+
+// retrySleep waits before a retry, can be replaced in tests
+var retrySleep = time.Sleep
+
+// RetryOnConflict calls update until it doesn't fail with a conflict or the
+// retries of the policy are used up. update has to fetch the resource again,
+// as a conflict means that it has been changed in the meantime.
+func RetryOnConflict(policy RetryPolicy, kind string, name string, update func() error) error {
+	for retries := 0; ; retries++ {
+		err := update()
+		if err == nil || !apierrors.IsConflict(err) {
+			return err
+		}
+		if retries >= policy.MaxRetries {
+			return fmt.Errorf("giving up after %d retries: %w", policy.MaxRetries, err)
+		}
+		klog.V(util.LogLevelRetry).InfoS("Retrying update after conflict", kind, name, "retry", retries+1, "maxRetries", policy.MaxRetries)
+		retrySleep(policy.Delay(retries + 1))
+	}
+}
+
+// metadataPatch returns a JSON merge patch with the labels and annotations
+// which differ between both services, if nothing else differs. The patch
+// doesn't conflict with concurrent changes of other parts of the service.
+func metadataPatch(original *servingv1.Service, updated *servingv1.Service) ([]byte, bool, error) {
+	stripped := func(service *servingv1.Service) *servingv1.Service {
+		c := service.DeepCopy()
+		c.Labels = nil
+		c.Annotations = nil
+		return c
+	}
+	if !equality.Semantic.DeepEqual(stripped(original), stripped(updated)) {
+		return nil, false, nil
+	}
+	metadata := map[string]interface{}{}
+	if labels := mapPatch(original.Labels, updated.Labels); len(labels) > 0 {
+		metadata["labels"] = labels
+	}
+	if annotations := mapPatch(original.Annotations, updated.Annotations); len(annotations) > 0 {
+		metadata["annotations"] = annotations
+	}
+	if len(metadata) == 0 {
+		return nil, false, nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{"metadata": metadata})
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot create metadata patch: %v", err)
+	}
+	return patch, true, nil
+}
+
+// mapPatch returns the changed and added entries, and removed entries with a nil value
+func mapPatch(original map[string]string, updated map[string]string) map[string]interface{} {
+	patch := map[string]interface{}{}
+	for key, value := range updated {
+		if old, ok := original[key]; !ok || old != value {
+			patch[key] = value
+		}
+	}
+	for key := range original {
+		if _, ok := updated[key]; !ok {
+			patch[key] = nil
+		}
+	}
+	return patch
+}
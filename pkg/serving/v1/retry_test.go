@@ -0,0 +1,169 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+func TestRetryPolicyValidate(t *testing.T) {
+	assert.NilError(t, DefaultRetryPolicy.Validate())
+	assert.NilError(t, RetryPolicy{Factor: 1}.Validate())
+	for _, tc := range []struct {
+		policy      RetryPolicy
+		errContents string
+	}{
+		{RetryPolicy{MaxRetries: -1, Factor: 1}, "number of retries"},
+		{RetryPolicy{Backoff: -time.Second, Factor: 1}, "backoff must not be negative"},
+		{RetryPolicy{Factor: 0.5}, "factor must be at least 1"},
+		{RetryPolicy{Factor: 1, Jitter: -0.1}, "jitter"},
+	} {
+		assert.ErrorContains(t, tc.policy.Validate(), tc.errContents)
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 3, Backoff: 100 * time.Millisecond, Factor: 2}
+	assert.Equal(t, policy.Delay(1), 100*time.Millisecond)
+	assert.Equal(t, policy.Delay(2), 200*time.Millisecond)
+	assert.Equal(t, policy.Delay(3), 400*time.Millisecond)
+
+	policy.Jitter = 0.5
+	for i := 0; i < 10; i++ {
+		delay := policy.Delay(2)
+		assert.Assert(t, delay >= 200*time.Millisecond && delay <= 300*time.Millisecond, "delay %s", delay)
+	}
+}
+
+func TestRetryOnConflict(t *testing.T) {
+	var delays []time.Duration
+	defer replaceRetrySleep(func(d time.Duration) { delays = append(delays, d) })()
+	policy := RetryPolicy{MaxRetries: 2, Backoff: time.Second, Factor: 3}
+
+	// Succeeds after a conflict
+	attempts := 0
+	err := RetryOnConflict(policy, "service", "foo", func() error {
+		attempts++
+		if attempts == 1 {
+			return conflictError("foo")
+		}
+		return nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, attempts, 2)
+	assert.DeepEqual(t, delays, []time.Duration{time.Second})
+
+	// Gives up when the retries are used up
+	attempts, delays = 0, nil
+	err = RetryOnConflict(policy, "service", "foo", func() error {
+		attempts++
+		return conflictError("foo")
+	})
+	assert.ErrorContains(t, err, "giving up after 2 retries")
+	assert.Assert(t, apierrors.IsConflict(errors.Unwrap(err)))
+	assert.Equal(t, attempts, 3)
+	assert.DeepEqual(t, delays, []time.Duration{time.Second, 3 * time.Second})
+
+	// Other errors aren't retried
+	attempts, delays = 0, nil
+	err = RetryOnConflict(policy, "service", "foo", func() error {
+		attempts++
+		return errors.New("boom")
+	})
+	assert.Error(t, err, "boom")
+	assert.Equal(t, attempts, 1)
+	assert.Equal(t, len(delays), 0)
+}
+
+func TestMetadataPatch(t *testing.T) {
+	original := &servingv1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "foo",
+			Labels:      map[string]string{"team": "a", "old": "x"},
+			Annotations: map[string]string{"note": "keep"},
+		},
+	}
+
+	updated := original.DeepCopy()
+	updated.Labels["team"] = "b"
+	delete(updated.Labels, "old")
+	patch, metadataOnly, err := metadataPatch(original, updated)
+	assert.NilError(t, err)
+	assert.Assert(t, metadataOnly)
+	assert.Equal(t, string(patch), `{"metadata":{"labels":{"old":null,"team":"b"}}}`)
+
+	updated = original.DeepCopy()
+	updated.Annotations["note"] = "changed"
+	updated.Spec.Template.Spec.Containers = append(updated.Spec.Template.Spec.Containers, corev1.Container{Image: "nginx"})
+	_, metadataOnly, err = metadataPatch(original, updated)
+	assert.NilError(t, err)
+	assert.Assert(t, !metadataOnly)
+
+	_, metadataOnly, err = metadataPatch(original, original.DeepCopy())
+	assert.NilError(t, err)
+	assert.Assert(t, !metadataOnly)
+}
+
+func TestUpdateServiceWithRetry(t *testing.T) {
+	defer replaceRetrySleep(func(time.Duration) {})()
+	service := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}
+
+	client := NewMockKnServiceClient(t)
+	r := client.Recorder()
+	r.GetService("foo", service, nil)
+	r.UpdateService(func(t *testing.T, updated *servingv1.Service) {
+		assert.Equal(t, len(updated.Spec.Template.Spec.Containers), 1)
+	}, conflictError("foo"))
+	r.GetService("foo", service, nil)
+	r.UpdateService(func(t *testing.T, updated *servingv1.Service) {
+		assert.Equal(t, len(updated.Spec.Template.Spec.Containers), 1)
+	}, nil)
+	r.GetService("foo", service, nil)
+	r.PatchService("foo", []byte(`{"metadata":{"annotations":{"note":"hello"}}}`), nil)
+
+	err := client.UpdateServiceWithRetry("foo", func(svc *servingv1.Service) (*servingv1.Service, error) {
+		svc.Spec.Template.Spec.Containers = append(svc.Spec.Template.Spec.Containers, corev1.Container{Image: "nginx"})
+		return svc, nil
+	}, DefaultRetryPolicy)
+	assert.NilError(t, err)
+
+	err = client.UpdateServiceWithRetry("foo", func(svc *servingv1.Service) (*servingv1.Service, error) {
+		svc.Annotations = map[string]string{"note": "hello"}
+		return svc, nil
+	}, DefaultRetryPolicy)
+	assert.NilError(t, err)
+	r.Validate()
+}
+
+func conflictError(name string) error {
+	return apierrors.NewConflict(schema.GroupResource{Group: "serving.knative.dev", Resource: "services"}, name, errors.New("changed in the meantime"))
+}
+
+func replaceRetrySleep(sleep func(time.Duration)) func() {
+	old := retrySleep
+	retrySleep = sleep
+	return func() {
+		retrySleep = old
+	}
+}
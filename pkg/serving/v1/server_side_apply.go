@@ -0,0 +1,258 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	clienterrors "knative.dev/client/pkg/errors"
+)
+
+// Helper methods supporting server-side apply of services
+
+// applyServiceServerSide applies the fields of the service which have been changed since it
+// has been read, together with the fields which kn applied before. Fields of other managers
+// are left out, so that they keep owning them. The resource version is left out as well, so
+// that the apply doesn't conflict with changes made since the service has been read.
+//
+// A field which kn applied before is removed by leaving it out of the apply. Other removed
+// fields are removed with a JSON patch afterwards, as server-side apply only removes the
+// fields of its own manager.
+func (cl *knServingClient) applyServiceServerSide(service *servingv1.Service) error {
+	base, err := cl.serviceBase(service)
+	if err != nil {
+		return err
+	}
+	baseFields, err := serviceFields(base)
+	if err != nil {
+		return err
+	}
+	desiredFields, err := serviceFields(service)
+	if err != nil {
+		return err
+	}
+	owned, err := ownedFields(base.ManagedFields, FieldManager)
+	if err != nil {
+		return err
+	}
+	applied, removed := appliedFields(baseFields, desiredFields, owned, nil)
+
+	metadata, _ := applied["metadata"].(map[string]interface{})
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadata["name"] = service.Name
+	metadata["namespace"] = cl.namespace
+	applied["metadata"] = metadata
+	applied["apiVersion"] = servingv1.SchemeGroupVersion.String()
+	applied["kind"] = "Service"
+	patch, err := json.Marshal(applied)
+	if err != nil {
+		return fmt.Errorf("cannot serialize service '%s' for server-side apply: %v", service.Name, err)
+	}
+
+	options := v1.PatchOptions{FieldManager: FieldManager, DryRun: cl.dryRunOptions()}
+	if cl.forceConflicts {
+		force := true
+		options.Force = &force
+	}
+	result, err := cl.client.Services(cl.namespace).Patch(context.TODO(), service.Name, types.ApplyPatchType, patch, options)
+	if err != nil {
+		return applyError(service.Name, err)
+	}
+	if len(removed) > 0 {
+		result, err = cl.removeServiceFields(service.Name, removed)
+		if err != nil {
+			return err
+		}
+	}
+	if cl.dryRun {
+		result.DeepCopyInto(service)
+		return updateServingGvk(service)
+	}
+	cl.rememberService(result)
+	service.ResourceVersion = result.ResourceVersion
+	service.Generation = result.Generation
+	service.ManagedFields = result.ManagedFields
+	return updateServingGvk(service)
+}
+
+// rememberService keeps a copy of a service read from the cluster as the base for
+// finding out which fields have been changed when applying it
+func (cl *knServingClient) rememberService(service *servingv1.Service) {
+	cl.readMutex.Lock()
+	defer cl.readMutex.Unlock()
+	if cl.readServices == nil {
+		cl.readServices = map[string]*servingv1.Service{}
+	}
+	cl.readServices[service.Name] = service.DeepCopy()
+}
+
+// serviceBase returns the service as it was read before being changed into the given
+// service. If it hasn't been read with this client, like when it has been built from
+// flags or a file, the current service is read instead.
+func (cl *knServingClient) serviceBase(service *servingv1.Service) (*servingv1.Service, error) {
+	cl.readMutex.Lock()
+	base := cl.readServices[service.Name]
+	cl.readMutex.Unlock()
+	if base != nil && service.ResourceVersion != "" && base.ResourceVersion == service.ResourceVersion {
+		return base, nil
+	}
+	return cl.GetService(service.Name)
+}
+
+// serviceFields returns the fields of the service which kn applies, the labels and
+// annotations of its metadata and its spec, as generic JSON values
+func serviceFields(service *servingv1.Service) (map[string]interface{}, error) {
+	metadata := map[string]interface{}{}
+	if len(service.Labels) > 0 {
+		metadata["labels"] = service.Labels
+	}
+	if len(service.Annotations) > 0 {
+		metadata["annotations"] = service.Annotations
+	}
+	data, err := json.Marshal(map[string]interface{}{"metadata": metadata, "spec": service.Spec})
+	if err != nil {
+		return nil, fmt.Errorf("cannot serialize service '%s' for server-side apply: %v", service.Name, err)
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("cannot serialize service '%s' for server-side apply: %v", service.Name, err)
+	}
+	return fields, nil
+}
+
+// ownedFields returns the fields the manager owns by applying, in the "f:<name>" format
+// of managedFields. Lists are atomic for services, so that the entries of lists are not needed.
+func ownedFields(managedFields []v1.ManagedFieldsEntry, manager string) (map[string]interface{}, error) {
+	owned := map[string]interface{}{}
+	for _, entry := range managedFields {
+		if entry.Manager != manager || entry.Operation != v1.ManagedFieldsOperationApply || entry.FieldsV1 == nil {
+			continue
+		}
+		fields := map[string]interface{}{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			return nil, fmt.Errorf("cannot read the managed fields of '%s': %v", manager, err)
+		}
+		mergeFields(owned, fields)
+	}
+	return owned, nil
+}
+
+func mergeFields(into, fields map[string]interface{}) {
+	for key, value := range fields {
+		existing, ok := into[key].(map[string]interface{})
+		child, isMap := value.(map[string]interface{})
+		if ok && isMap {
+			mergeFields(existing, child)
+			continue
+		}
+		into[key] = value
+	}
+}
+
+// appliedFields returns the desired fields which differ from the base or which are already
+// owned, and the paths of the fields which have been removed but are not owned. Objects are
+// compared field by field, other values including lists as a whole.
+func appliedFields(base, desired, owned map[string]interface{}, path []string) (map[string]interface{}, [][]string) {
+	applied := map[string]interface{}{}
+	var removed [][]string
+	for key, value := range desired {
+		baseValue, inBase := base[key]
+		ownedValue, isOwned := owned["f:"+key]
+		desiredObject, isObject := value.(map[string]interface{})
+		baseObject, baseIsObject := baseValue.(map[string]interface{})
+		if isObject && baseIsObject {
+			ownedObject, _ := ownedValue.(map[string]interface{})
+			child, childRemoved := appliedFields(baseObject, desiredObject, ownedObject, appendPath(path, key))
+			removed = append(removed, childRemoved...)
+			if len(child) > 0 {
+				applied[key] = child
+			}
+			continue
+		}
+		if !inBase || isOwned || !reflect.DeepEqual(baseValue, value) {
+			applied[key] = value
+		}
+	}
+	for key := range base {
+		if _, ok := desired[key]; ok {
+			continue
+		}
+		if _, isOwned := owned["f:"+key]; !isOwned {
+			removed = append(removed, appendPath(path, key))
+		}
+	}
+	return applied, removed
+}
+
+func appendPath(path []string, key string) []string {
+	return append(append([]string{}, path...), key)
+}
+
+// removeServiceFields removes the fields with the given paths with a JSON patch
+func (cl *knServingClient) removeServiceFields(name string, paths [][]string) (*servingv1.Service, error) {
+	escape := strings.NewReplacer("~", "~0", "/", "~1")
+	pointers := make([]string, len(paths))
+	for i, path := range paths {
+		for _, key := range path {
+			pointers[i] += "/" + escape.Replace(key)
+		}
+	}
+	sort.Strings(pointers)
+	operations := make([]map[string]string, len(pointers))
+	for i, pointer := range pointers {
+		operations[i] = map[string]string{"op": "remove", "path": pointer}
+	}
+	patch, err := json.Marshal(operations)
+	if err != nil {
+		return nil, err
+	}
+	result, err := cl.client.Services(cl.namespace).Patch(context.TODO(), name, types.JSONPatchType, patch,
+		v1.PatchOptions{DryRun: cl.dryRunOptions()})
+	if err != nil {
+		return nil, fmt.Errorf("cannot remove %s from service '%s': %v", strings.Join(pointers, ", "), name, clienterrors.GetError(err))
+	}
+	return result, nil
+}
+
+// applyError explains a conflict with the fields of other managers. The returned error is
+// no conflict anymore, as retrying the apply would conflict again.
+func applyError(name string, err error) error {
+	if !apierrors.IsConflict(err) {
+		return clienterrors.GetError(err)
+	}
+	var conflicts []string
+	if status, ok := err.(apierrors.APIStatus); ok && status.Status().Details != nil {
+		for _, cause := range status.Status().Details.Causes {
+			conflicts = append(conflicts, cause.Message)
+		}
+	}
+	if len(conflicts) == 0 {
+		conflicts = append(conflicts, err.Error())
+	}
+	return fmt.Errorf("cannot apply service '%s' as other managers own the changed fields:\n  %s\n"+
+		"Use --force-conflicts to take over these fields", name, strings.Join(conflicts, "\n  "))
+}
@@ -0,0 +1,88 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+// FileValueResolver replaces values of key-value flags like --env or --annotation which refer to
+// a file by the content of the file, for multi-line values like certificates or JSON documents.
+// A value '@path' is replaced by the content of the file at path, '@-' by everything read from
+// stdin (only once per resolver) and '@@value' stands for the literal value '@value'.
+type FileValueResolver struct {
+	stdin     io.Reader
+	stdinUsed string
+}
+
+// NewFileValueResolver returns a resolver which reads '@-' values from stdin.
+// Use a nil stdin for rejecting '@-' values, e.g. when kn must not read from stdin.
+func NewFileValueResolver(stdin io.Reader) *FileValueResolver {
+	return &FileValueResolver{stdin: stdin}
+}
+
+// Resolve replaces the values of the map which refer to a file, in place.
+// The flag name is used for error messages.
+func (r *FileValueResolver) Resolve(flag string, m map[string]string) error {
+	for key, value := range m {
+		if !strings.HasPrefix(value, "@") {
+			continue
+		}
+		resolved, err := r.resolveValue(flag, key, value)
+		if err != nil {
+			return err
+		}
+		m[key] = resolved
+	}
+	return nil
+}
+
+func (r *FileValueResolver) resolveValue(flag string, key string, value string) (string, error) {
+	source := value[1:]
+	switch {
+	case strings.HasPrefix(source, "@"):
+		return source, nil
+	case source == "":
+		return "", fmt.Errorf("invalid --%s %s=%s: no file given after '@', use '@@' for a value starting with '@'", flag, key, value)
+	case source == "-":
+		if r.stdin == nil {
+			return "", fmt.Errorf("invalid --%s %s=@-: reading from stdin is not allowed here", flag, key)
+		}
+		if r.stdinUsed != "" {
+			return "", fmt.Errorf("invalid --%s %s=@-: stdin has already been read for %s", flag, key, r.stdinUsed)
+		}
+		r.stdinUsed = fmt.Sprintf("--%s %s", flag, key)
+		content, err := ioutil.ReadAll(r.stdin)
+		if err != nil {
+			return "", fmt.Errorf("cannot read value of --%s %s from stdin: %w", flag, key, err)
+		}
+		return string(content), nil
+	default:
+		path, err := homedir.Expand(source)
+		if err != nil {
+			return "", err
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("cannot read value of --%s %s from file: %w", flag, key, err)
+		}
+		return string(content), nil
+	}
+}
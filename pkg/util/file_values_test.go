@@ -0,0 +1,62 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestFileValueResolver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kn-file-values")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	pem := "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+	pemFile := filepath.Join(dir, "cert.pem")
+	assert.NilError(t, ioutil.WriteFile(pemFile, []byte(pem), 0600))
+
+	resolver := NewFileValueResolver(strings.NewReader(`{"allow": true}`))
+	values := map[string]string{
+		"cert":    "@" + pemFile,
+		"policy":  "@-",
+		"literal": "@@home",
+		"plain":   "value",
+	}
+	assert.NilError(t, resolver.Resolve("env", values))
+	assert.DeepEqual(t, values, map[string]string{
+		"cert":    pem,
+		"policy":  `{"allow": true}`,
+		"literal": "@home",
+		"plain":   "value",
+	})
+
+	// Stdin can be read only once
+	err = resolver.Resolve("annotation", map[string]string{"other": "@-"})
+	assert.ErrorContains(t, err, "invalid --annotation other=@-: stdin has already been read for --env policy")
+
+	err = NewFileValueResolver(nil).Resolve("env", map[string]string{"policy": "@-"})
+	assert.ErrorContains(t, err, "reading from stdin is not allowed")
+
+	err = resolver.Resolve("env", map[string]string{"cert": "@" + filepath.Join(dir, "missing.pem")})
+	assert.ErrorContains(t, err, "cannot read value of --env cert from file")
+
+	err = resolver.Resolve("env", map[string]string{"cert": "@"})
+	assert.ErrorContains(t, err, "no file given after '@'")
+}
@@ -0,0 +1,41 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"flag"
+	"strconv"
+
+	"k8s.io/klog/v2"
+)
+
+// Verbosity levels of the logs about kn internals, enabled with --v. A level
+// includes all lower levels, from level 6 on client-go logs HTTP requests, too.
+const (
+	// LogLevelClient logs the construction of API clients
+	LogLevelClient = 2
+	// LogLevelRetry logs the decisions to retry a failed operation
+	LogLevelRetry = 3
+	// LogLevelWatch logs every event received while watching a resource
+	LogLevelWatch = 4
+)
+
+// SetLogVerbosity sets the verbosity of the structured logs written to stderr by
+// kn and the Kubernetes client libraries
+func SetLogVerbosity(level int) error {
+	klogFlags := flag.NewFlagSet("klog", flag.ContinueOnError)
+	klog.InitFlags(klogFlags)
+	return klogFlags.Set("v", strconv.Itoa(level))
+}
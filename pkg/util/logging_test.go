@@ -0,0 +1,34 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	"k8s.io/klog/v2"
+)
+
+func TestSetLogVerbosity(t *testing.T) {
+	defer SetLogVerbosity(0)
+
+	assert.NilError(t, SetLogVerbosity(LogLevelRetry))
+	assert.Assert(t, klog.V(LogLevelClient).Enabled())
+	assert.Assert(t, klog.V(LogLevelRetry).Enabled())
+	assert.Assert(t, !klog.V(LogLevelWatch).Enabled())
+
+	assert.NilError(t, SetLogVerbosity(0))
+	assert.Assert(t, !klog.V(LogLevelClient).Enabled())
+}
@@ -0,0 +1,77 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// ValueResolver replaces references in the values of key-value flags like --env, in place.
+// The flag name is used for error messages.
+type ValueResolver interface {
+	Resolve(flag string, m map[string]string) error
+}
+
+// valueResolverChain applies resolvers one after another
+type valueResolverChain []ValueResolver
+
+// ChainValueResolvers returns a resolver which applies the given resolvers in order
+func ChainValueResolvers(resolvers ...ValueResolver) ValueResolver {
+	return valueResolverChain(resolvers)
+}
+
+func (c valueResolverChain) Resolve(flag string, m map[string]string) error {
+	for _, resolver := range c {
+		err := resolver.Resolve(flag, m)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TemplateValueResolver expands values which contain Go templates like '{{.Service}}' with
+// the given data
+type TemplateValueResolver struct {
+	data interface{}
+}
+
+// NewTemplateValueResolver returns a resolver expanding templates with the given data
+func NewTemplateValueResolver(data interface{}) *TemplateValueResolver {
+	return &TemplateValueResolver{data: data}
+}
+
+// Resolve expands the templates in the values of the map, in place
+func (r *TemplateValueResolver) Resolve(flag string, m map[string]string) error {
+	for key, value := range m {
+		if !strings.Contains(value, "{{") {
+			continue
+		}
+		templ, err := template.New(key).Option("missingkey=error").Parse(value)
+		if err != nil {
+			return fmt.Errorf("invalid template in --%s %s: %w", flag, key, err)
+		}
+		buf := new(bytes.Buffer)
+		err = templ.Execute(buf, r.data)
+		if err != nil {
+			return fmt.Errorf("cannot expand template in --%s %s: %w", flag, key, err)
+		}
+		m[key] = buf.String()
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestTemplateValueResolver(t *testing.T) {
+	data := struct{ Service string }{"foo"}
+	values := map[string]string{"origin": "https://{{.Service}}.example.com", "plain": "value"}
+	assert.NilError(t, NewTemplateValueResolver(data).Resolve("env", values))
+	assert.DeepEqual(t, values, map[string]string{"origin": "https://foo.example.com", "plain": "value"})
+
+	err := NewTemplateValueResolver(data).Resolve("env", map[string]string{"origin": "{{.Service"})
+	assert.ErrorContains(t, err, "invalid template in --env origin")
+	err = NewTemplateValueResolver(data).Resolve("env", map[string]string{"origin": "{{.Namespace}}"})
+	assert.ErrorContains(t, err, "cannot expand template in --env origin")
+}
+
+func TestChainValueResolvers(t *testing.T) {
+	var calls []string
+	first := resolverFunc(func(flag string, m map[string]string) error {
+		calls = append(calls, "first")
+		return nil
+	})
+	failing := resolverFunc(func(flag string, m map[string]string) error {
+		calls = append(calls, "failing")
+		return errors.New("failed")
+	})
+	err := ChainValueResolvers(first, failing, first).Resolve("env", map[string]string{})
+	assert.ErrorContains(t, err, "failed")
+	assert.DeepEqual(t, calls, []string{"first", "failing"})
+}
+
+type resolverFunc func(flag string, m map[string]string) error
+
+func (f resolverFunc) Resolve(flag string, m map[string]string) error {
+	return f(flag, m)
+}
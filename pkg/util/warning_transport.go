@@ -0,0 +1,119 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// WarningRecorder collects the warnings which the API server returns in "Warning" headers,
+// e.g. for deprecated APIs or from admission webhooks and policies. Only the warnings of
+// requests which change resources are recorded, each warning only once.
+type WarningRecorder struct {
+	mu       sync.Mutex
+	warnings []string
+}
+
+type warningTransport struct {
+	transport http.RoundTripper
+	recorder  *WarningRecorder
+}
+
+// WrapTransport returns a transport which records the warnings of the responses
+func (r *WarningRecorder) WrapTransport(transport http.RoundTripper) http.RoundTripper {
+	return &warningTransport{transport: transport, recorder: r}
+}
+
+func (t *warningTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil || req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return resp, err
+	}
+	for _, header := range resp.Header.Values("Warning") {
+		for _, warning := range ParseWarningHeader(header) {
+			t.recorder.add(warning)
+		}
+	}
+	return resp, err
+}
+
+func (r *WarningRecorder) add(warning string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.warnings {
+		if existing == warning {
+			return
+		}
+	}
+	r.warnings = append(r.warnings, warning)
+}
+
+// Warnings returns the recorded warnings in the order they have been received
+func (r *WarningRecorder) Warnings() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.warnings...)
+}
+
+// ParseWarningHeader returns the texts of a Warning header value of the form
+// 'CODE AGENT "TEXT" ["DATE"]', which can contain several comma separated warnings.
+// Only warnings with code 299 (miscellaneous persistent warning), which is used by
+// Kubernetes, are returned. Malformed values are ignored.
+func ParseWarningHeader(header string) []string {
+	var warnings []string
+	rest := strings.TrimSpace(header)
+	for rest != "" {
+		fields := strings.SplitN(rest, " ", 3)
+		if len(fields) < 3 || !strings.HasPrefix(fields[2], `"`) {
+			return warnings
+		}
+		text, remainder, ok := unquote(fields[2])
+		if !ok {
+			return warnings
+		}
+		if fields[0] == "299" {
+			warnings = append(warnings, text)
+		}
+		// Skip the optional date and continue after the separating comma
+		i := strings.Index(remainder, ",")
+		if i < 0 {
+			break
+		}
+		rest = strings.TrimSpace(remainder[i+1:])
+	}
+	return warnings
+}
+
+// unquote reads the quoted string at the start of s and returns its unescaped
+// content and the remainder after the closing quote
+func unquote(s string) (string, string, bool) {
+	var text strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+			if i < len(s) {
+				text.WriteByte(s[i])
+			}
+		case '"':
+			return text.String(), s[i+1:], true
+		default:
+			text.WriteByte(s[i])
+		}
+	}
+	return "", "", false
+}
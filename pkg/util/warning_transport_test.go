@@ -0,0 +1,59 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestParseWarningHeader(t *testing.T) {
+	for _, tc := range []struct {
+		header   string
+		expected []string
+	}{
+		{`299 - "serving.knative.dev/v1alpha1 is deprecated"`, []string{"serving.knative.dev/v1alpha1 is deprecated"}},
+		{`299 - "a \"quoted\" word" "Wed, 21 Oct 2015 07:28:00 GMT"`, []string{`a "quoted" word`}},
+		{`299 - "first", 299 - "second, with comma"`, []string{"first", "second, with comma"}},
+		{`199 - "not persistent", 299 kn "persistent"`, []string{"persistent"}},
+		{`299 - no quotes`, nil},
+		{`299 - "unterminated`, nil},
+		{``, nil},
+	} {
+		assert.DeepEqual(t, ParseWarningHeader(tc.header), tc.expected)
+	}
+}
+
+func TestWarningRecorder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Warning", `299 - "policy: image is not signed"`)
+		w.Header().Add("Warning", `299 - "`+r.Method+` warning"`)
+	}))
+	defer server.Close()
+
+	recorder := &WarningRecorder{}
+	client := &http.Client{Transport: recorder.WrapTransport(http.DefaultTransport)}
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPost} {
+		req, err := http.NewRequest(method, server.URL, nil)
+		assert.NilError(t, err)
+		resp, err := client.Do(req)
+		assert.NilError(t, err)
+		resp.Body.Close()
+	}
+	assert.DeepEqual(t, recorder.Warnings(), []string{"policy: image is not signed", "POST warning", "PUT warning"})
+}
@@ -0,0 +1,117 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wait
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+)
+
+// Cause is a failed condition of a resource which explains why the resource waited for
+// doesn't become ready, e.g. an image pull backoff of the revision of a service
+type Cause struct {
+	Kind      string
+	Name      string
+	Condition apis.ConditionType
+	Reason    string
+	Message   string
+}
+
+// String returns the cause as "kind 'name': Reason: Message"
+func (c Cause) String() string {
+	var details []string
+	if c.Reason != "" {
+		details = append(details, c.Reason)
+	}
+	if c.Message != "" {
+		details = append(details, c.Message)
+	}
+	if len(details) == 0 {
+		details = append(details, fmt.Sprintf("condition %s failed", c.Condition))
+	}
+	return fmt.Sprintf("%s '%s': %s", c.Kind, c.Name, strings.Join(details, ": "))
+}
+
+// CauseExtractor returns the causes of a resource not being ready, like the failed
+// conditions of the resources it depends on. It's called for every event of the
+// resource waited for until the awaited condition is True.
+type CauseExtractor func(obj runtime.Object) ([]Cause, error)
+
+// ConditionsCallback receives the full set of conditions of the resource waited for
+// with every event, together with the causes found for the resource not being ready
+type ConditionsCallback func(durationSinceState time.Duration, conditions apis.Conditions, causes []Cause)
+
+// Reasons of conditions which are not False yet, but which won't become True
+// without a change, like an image which can't be pulled or a container which crashes
+var failureReasons = []string{
+	"ImagePullBackOff",
+	"ErrImagePull",
+	"InvalidImageName",
+	"CrashLoopBackOff",
+	"CreateContainerConfigError",
+	"CreateContainerError",
+	"FailedCreate",
+	"ExceededQuota",
+}
+
+// FailedConditions returns a cause for each condition of a resource which is False,
+// or which is Unknown with a reason indicating a failure like ImagePullBackOff.
+// The Ready condition is only reported if no other condition failed, as it
+// summarizes the others.
+func FailedConditions(kind string, name string, conditions apis.Conditions) []Cause {
+	var causes []Cause
+	var ready *Cause
+	for _, cond := range conditions {
+		if cond.Status == corev1.ConditionTrue || (cond.Status == corev1.ConditionUnknown && !isFailureReason(cond.Reason)) {
+			continue
+		}
+		cause := Cause{Kind: kind, Name: name, Condition: cond.Type, Reason: cond.Reason, Message: cond.Message}
+		if cond.Type == apis.ConditionReady {
+			ready = &cause
+			continue
+		}
+		causes = append(causes, cause)
+	}
+	if len(causes) == 0 && ready != nil {
+		causes = append(causes, *ready)
+	}
+	return causes
+}
+
+func isFailureReason(reason string) bool {
+	for _, failure := range failureReasons {
+		if reason == failure {
+			return true
+		}
+	}
+	return false
+}
+
+// withCauses appends the causes to a message, skipping the causes whose
+// message is already contained in it
+func withCauses(message string, causes []Cause) string {
+	for _, cause := range causes {
+		if cause.Message != "" && strings.Contains(message, cause.Message) {
+			continue
+		}
+		message = fmt.Sprintf("%s (caused by %s)", message, cause)
+	}
+	return message
+}
@@ -0,0 +1,128 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wait
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"knative.dev/pkg/apis"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+func TestFailedConditions(t *testing.T) {
+	conditions := apis.Conditions{
+		{Type: apis.ConditionReady, Status: corev1.ConditionFalse, Reason: "RevisionFailed", Message: "revision failed"},
+		{Type: "ContainerHealthy", Status: corev1.ConditionUnknown, Reason: "ImagePullBackOff", Message: "Back-off pulling image"},
+		{Type: "ResourcesAvailable", Status: corev1.ConditionUnknown, Reason: "Deploying"},
+		{Type: "Active", Status: corev1.ConditionTrue},
+	}
+	causes := FailedConditions("revision", "foo-00001", conditions)
+	// Ready is left out as another condition failed
+	assert.DeepEqual(t, causes, []Cause{
+		{Kind: "revision", Name: "foo-00001", Condition: "ContainerHealthy", Reason: "ImagePullBackOff", Message: "Back-off pulling image"},
+	})
+	assert.Equal(t, causes[0].String(), "revision 'foo-00001': ImagePullBackOff: Back-off pulling image")
+
+	causes = FailedConditions("revision", "foo-00001", conditions[:1])
+	assert.Equal(t, len(causes), 1)
+	assert.Equal(t, causes[0].String(), "revision 'foo-00001': RevisionFailed: revision failed")
+
+	assert.Equal(t, len(FailedConditions("revision", "foo-00001", conditions[2:])), 0)
+	assert.Equal(t, Cause{Kind: "revision", Name: "foo-00001", Condition: "Ready"}.String(), "revision 'foo-00001': condition Ready failed")
+}
+
+func TestWithCauses(t *testing.T) {
+	causes := []Cause{
+		{Kind: "revision", Name: "foo-00001", Reason: "ExceededQuota", Message: "exceeded quota: compute"},
+		{Kind: "configuration", Name: "foo", Reason: "RevisionFailed", Message: "Revision failed"},
+	}
+	assert.Equal(t, withCauses("Revision failed", causes),
+		"Revision failed (caused by revision 'foo-00001': ExceededQuota: exceeded quota: compute)")
+	assert.Equal(t, withCauses("msg", nil), "msg")
+}
+
+func TestWaitForReadyWithCauses(t *testing.T) {
+	events := []watch.Event{
+		{Type: watch.Modified, Object: CreateTestServiceWithConditions("foo", corev1.ConditionUnknown, corev1.ConditionUnknown, "", "waiting")},
+		{Type: watch.Modified, Object: CreateTestServiceWithConditions("foo", corev1.ConditionFalse, corev1.ConditionFalse, "RevisionFailed", "Revision failed")},
+	}
+	fakeWatchAPI := NewFakeWatch(events)
+	fakeWatchAPI.Start()
+	defer close(fakeWatchAPI.eventChan)
+
+	cause := Cause{Kind: "revision", Name: "foo-00001", Condition: "ContainerHealthy", Reason: "ImagePullBackOff", Message: "Back-off pulling image"}
+	var messages []string
+	var conditionSets []apis.Conditions
+	var causeSets [][]Cause
+	timeout := 5 * time.Second
+	waitForReady := NewWaitForReady("service", func(obj runtime.Object) (apis.Conditions, error) {
+		return apis.Conditions(obj.(*servingv1.Service).Status.Conditions), nil
+	})
+	err, _ := waitForReady.Wait(fakeWatchAPI, "foo", Options{
+		Timeout: &timeout,
+		CauseExtractor: func(obj runtime.Object) ([]Cause, error) {
+			return []Cause{cause}, nil
+		},
+		ConditionsCallback: func(_ time.Duration, conditions apis.Conditions, causes []Cause) {
+			conditionSets = append(conditionSets, conditions)
+			causeSets = append(causeSets, causes)
+		},
+	}, func(_ time.Duration, message string) {
+		messages = append(messages, message)
+	})
+
+	assert.Error(t, err, "RevisionFailed: Revision failed (caused by revision 'foo-00001': ImagePullBackOff: Back-off pulling image)")
+	assert.DeepEqual(t, messages, []string{
+		"waiting (caused by revision 'foo-00001': ImagePullBackOff: Back-off pulling image)",
+		"Revision failed (caused by revision 'foo-00001': ImagePullBackOff: Back-off pulling image)",
+	})
+	// All conditions are passed to the callback
+	assert.Equal(t, len(conditionSets), 2)
+	assert.Equal(t, len(conditionSets[0]), 3)
+	assert.DeepEqual(t, causeSets[1], []Cause{cause})
+}
+
+func TestWaitForReadyCauseExtractorError(t *testing.T) {
+	events := []watch.Event{
+		{Type: watch.Modified, Object: CreateTestServiceWithConditions("foo", corev1.ConditionUnknown, corev1.ConditionUnknown, "", "waiting")},
+		{Type: watch.Modified, Object: CreateTestServiceWithConditions("foo", corev1.ConditionTrue, corev1.ConditionTrue, "", "")},
+	}
+	fakeWatchAPI := NewFakeWatch(events)
+	fakeWatchAPI.Start()
+	defer close(fakeWatchAPI.eventChan)
+
+	var messages []string
+	timeout := 5 * time.Second
+	waitForReady := NewWaitForReady("service", func(obj runtime.Object) (apis.Conditions, error) {
+		return apis.Conditions(obj.(*servingv1.Service).Status.Conditions), nil
+	})
+	err, _ := waitForReady.Wait(fakeWatchAPI, "foo", Options{
+		Timeout: &timeout,
+		CauseExtractor: func(obj runtime.Object) ([]Cause, error) {
+			return nil, errors.New("forbidden")
+		},
+	}, func(_ time.Duration, message string) {
+		messages = append(messages, message)
+	})
+	// Causes which can't be found don't fail waiting
+	assert.NilError(t, err)
+	assert.DeepEqual(t, messages, []string{"waiting"})
+}
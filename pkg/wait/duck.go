@@ -0,0 +1,56 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+
+package wait
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+// DuckConditionsExtractor extracts the conditions of any resource following the Knative
+// status duck type, including unstructured objects returned by the dynamic client
+func DuckConditionsExtractor(obj runtime.Object) (apis.Conditions, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	resource := &duckv1.KResource{}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(content, resource)
+	if err != nil {
+		return nil, err
+	}
+	return apis.Conditions(resource.Status.Conditions), nil
+}
+
+// NewDynamicWatcher makes a watch on the resource with the given name which starts after the
+// given resource version, so that no change is missed after the resource has been read
+func NewDynamicWatcher(client dynamic.ResourceInterface, name string, resourceVersion string, timeout time.Duration) (watch.Interface, error) {
+	opts := metav1.ListOptions{
+		FieldSelector:   fields.OneTermEqualSelector("metadata.name", name).String(),
+		ResourceVersion: resourceVersion,
+	}
+	addWatchTimeout(&opts, timeout)
+	return client.Watch(context.TODO(), opts)
+}
@@ -0,0 +1,76 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+
+package wait
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"knative.dev/pkg/apis"
+)
+
+func TestDuckConditionsExtractor(t *testing.T) {
+	service := CreateTestServiceWithConditions("foo", corev1.ConditionTrue, corev1.ConditionUnknown, "", "")
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(service)
+	assert.NilError(t, err)
+
+	for _, obj := range []runtime.Object{service, &unstructured.Unstructured{Object: content}} {
+		conditions, err := DuckConditionsExtractor(obj)
+		assert.NilError(t, err)
+		assert.Equal(t, len(conditions), 3)
+
+		ready, err := IsConditionTrue(obj, apis.ConditionReady, DuckConditionsExtractor)
+		assert.NilError(t, err)
+		assert.Assert(t, ready)
+		routesReady, err := IsConditionTrue(obj, "RoutesReady", DuckConditionsExtractor)
+		assert.NilError(t, err)
+		assert.Assert(t, !routesReady)
+		missing, err := IsConditionTrue(obj, "Missing", DuckConditionsExtractor)
+		assert.NilError(t, err)
+		assert.Assert(t, !missing)
+	}
+
+	// The condition doesn't count before the latest generation has been reconciled
+	outdated := CreateTestServiceWithConditions("foo", corev1.ConditionTrue, corev1.ConditionTrue, "", "", 2, 1)
+	ready, err := IsConditionTrue(outdated, apis.ConditionReady, DuckConditionsExtractor)
+	assert.NilError(t, err)
+	assert.Assert(t, !ready)
+}
+
+func TestWaitForCondition(t *testing.T) {
+	timeout := 5 * time.Second
+	fakeWatchAPI := NewFakeWatch([]watch.Event{
+		{Type: watch.Modified, Object: CreateTestServiceWithConditions("foo", corev1.ConditionUnknown, corev1.ConditionUnknown, "", "")},
+		{Type: watch.Modified, Object: CreateTestServiceWithConditions("foo", corev1.ConditionUnknown, corev1.ConditionTrue, "", "")},
+	})
+	fakeWatchAPI.Start()
+	err, _ := NewWaitForCondition("service", "RoutesReady", DuckConditionsExtractor).Wait(fakeWatchAPI, "foo", Options{Timeout: &timeout}, NoopMessageCallback())
+	assert.NilError(t, err)
+
+	timeout = 100 * time.Millisecond
+	fakeWatchAPI = NewFakeWatch([]watch.Event{
+		{Type: watch.Modified, Object: CreateTestServiceWithConditions("foo", corev1.ConditionTrue, corev1.ConditionUnknown, "", "")},
+	})
+	fakeWatchAPI.Start()
+	err, _ = NewWaitForCondition("service", "RoutesReady", DuckConditionsExtractor).Wait(fakeWatchAPI, "foo", Options{Timeout: &timeout}, NoopMessageCallback())
+	assert.ErrorContains(t, err, "timeout: condition RoutesReady of service 'foo' not true after 0 seconds")
+}
@@ -0,0 +1,110 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wait
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	api_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+
+	"knative.dev/client/pkg/util"
+)
+
+// DefaultTransientErrorTolerance is how long transient errors are tolerated
+// while waiting, unless configured otherwise in the Options
+const DefaultTransientErrorTolerance = 30 * time.Second
+
+// ErrorCategory classifies errors which occur while waiting
+type ErrorCategory int
+
+const (
+	// ErrorCategoryPermanent errors are caused by the resource waited for,
+	// e.g. an image which can't be pulled. They are reported immediately.
+	ErrorCategoryPermanent ErrorCategory = iota
+
+	// ErrorCategoryTransient errors are caused by the infrastructure, e.g. by
+	// the rotation of a webhook's certificate or by a restarting API server.
+	// They usually go away by themselves and are tolerated for a grace period.
+	ErrorCategoryTransient
+)
+
+func (c ErrorCategory) String() string {
+	if c == ErrorCategoryTransient {
+		return "transient"
+	}
+	return "permanent"
+}
+
+// Messages of errors caused by unavailable or restarting webhooks and API servers
+var transientErrorMessages = []string{
+	"failed calling webhook",
+	"x509: certificate",
+	"tls: ",
+	"connection refused",
+	"connection reset by peer",
+	"i/o timeout",
+	"internal error occurred",
+	"the server is currently unable to handle the request",
+	"http2: server sent goaway",
+	"etcdserver: leader changed",
+	"etcdserver: request timed out",
+}
+
+// ClassifyError returns the category of an error which occurred while waiting
+func ClassifyError(err error) ErrorCategory {
+	if err == nil {
+		return ErrorCategoryPermanent
+	}
+	if api_errors.IsInternalError(err) || api_errors.IsServiceUnavailable(err) ||
+		api_errors.IsServerTimeout(err) || api_errors.IsTimeout(err) || api_errors.IsTooManyRequests(err) {
+		return ErrorCategoryTransient
+	}
+	if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+		return ErrorCategoryTransient
+	}
+	return ClassifyMessage(err.Error())
+}
+
+// ClassifyMessage returns the category of an error given by its message only,
+// like the message of a condition
+func ClassifyMessage(message string) ErrorCategory {
+	lower := strings.ToLower(message)
+	for _, transient := range transientErrorMessages {
+		if strings.Contains(lower, transient) {
+			return ErrorCategoryTransient
+		}
+	}
+	return ErrorCategoryPermanent
+}
+
+// Interval between two tries of RetryTransient, can be replaced in tests
+var transientRetryInterval = time.Second
+
+// RetryTransient calls fn until it succeeds, fails with a permanent error or
+// fails with transient errors for longer than the given tolerance
+func RetryTransient(tolerance time.Duration, fn func() error) error {
+	deadline := time.Now().Add(tolerance)
+	for {
+		err := fn()
+		if err == nil || ClassifyError(err) != ErrorCategoryTransient || time.Now().Add(transientRetryInterval).After(deadline) {
+			return err
+		}
+		klog.V(util.LogLevelRetry).InfoS("Retrying after transient error", "error", err, "interval", transientRetryInterval)
+		time.Sleep(transientRetryInterval)
+	}
+}
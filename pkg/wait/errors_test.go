@@ -0,0 +1,81 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wait
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	api_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestClassifyError(t *testing.T) {
+	services := schema.GroupResource{Group: "serving.knative.dev", Resource: "services"}
+	for _, tc := range []struct {
+		err      error
+		category ErrorCategory
+	}{
+		{nil, ErrorCategoryPermanent},
+		{errors.New("Revision \"foo-00001\" failed with message: Unable to fetch image"), ErrorCategoryPermanent},
+		{api_errors.NewNotFound(services, "foo"), ErrorCategoryPermanent},
+		{api_errors.NewInternalError(errors.New("boom")), ErrorCategoryTransient},
+		{api_errors.NewServiceUnavailable("restarting"), ErrorCategoryTransient},
+		{api_errors.NewTimeoutError("slow", 1), ErrorCategoryTransient},
+		{errors.New(`Internal error occurred: failed calling webhook "webhook.serving.knative.dev": ` +
+			`Post https://webhook.knative-serving.svc:443/defaulting: x509: certificate signed by unknown authority`), ErrorCategoryTransient},
+		{errors.New("Get https://10.0.0.1:6443/apis: dial tcp 10.0.0.1:6443: connect: connection refused"), ErrorCategoryTransient},
+	} {
+		assert.Equal(t, ClassifyError(tc.err), tc.category, "%v", tc.err)
+	}
+	assert.Equal(t, ErrorCategoryTransient.String(), "transient")
+	assert.Equal(t, ErrorCategoryPermanent.String(), "permanent")
+}
+
+func TestRetryTransient(t *testing.T) {
+	defer func(interval time.Duration) {
+		transientRetryInterval = interval
+	}(transientRetryInterval)
+	transientRetryInterval = time.Millisecond
+
+	calls := 0
+	err := RetryTransient(time.Second, func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, calls, 3)
+
+	calls = 0
+	err = RetryTransient(time.Second, func() error {
+		calls++
+		return errors.New("forbidden")
+	})
+	assert.ErrorContains(t, err, "forbidden")
+	assert.Equal(t, calls, 1)
+
+	calls = 0
+	err = RetryTransient(10*time.Millisecond, func() error {
+		calls++
+		return errors.New("connection refused")
+	})
+	assert.ErrorContains(t, err, "connection refused")
+	assert.Assert(t, calls > 1)
+}
@@ -0,0 +1,77 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wait
+
+import (
+	"sync"
+)
+
+// Event is a progress event as delivered by an EventChannel
+type Event = ProgressEvent
+
+// EventChannel is a ProgressReporter which delivers the events on a channel.
+// It allows UIs which embed the client to render the progress natively:
+//
+//	events := wait.NewEventChannel(10)
+//	go render(events.WaitEvents())
+//	err, duration := client.WaitForService(name, timeout, wait.ProgressMessageCallback(events, "service", name))
+//	...
+//	events.Close()
+//
+// Waiting events are dropped if the channel's buffer is full, so that a slow
+// consumer can't delay the wait. Ready and failed events are always delivered.
+type EventChannel struct {
+	events chan Event
+	mutex  sync.Mutex
+	closed bool
+}
+
+// NewEventChannel creates an EventChannel with a buffer for the given number of events
+func NewEventChannel(buffer int) *EventChannel {
+	return &EventChannel{events: make(chan Event, buffer)}
+}
+
+// WaitEvents returns the channel on which the events are delivered.
+// The channel is closed by Close.
+func (c *EventChannel) WaitEvents() <-chan Event {
+	return c.events
+}
+
+// Report sends the event on the channel. Events reported after Close are dropped.
+func (c *EventChannel) Report(event ProgressEvent) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return
+	}
+	if event.Phase != PhaseWaiting {
+		c.events <- event
+		return
+	}
+	select {
+	case c.events <- event:
+	default:
+	}
+}
+
+// Close closes the event channel. It is safe to call Close more than once.
+func (c *EventChannel) Close() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !c.closed {
+		c.closed = true
+		close(c.events)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wait
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestEventChannel(t *testing.T) {
+	events := NewEventChannel(2)
+	var _ ProgressReporter = events
+
+	callback := ProgressMessageCallback(events, "service", "foo")
+	callback(time.Second, "Configuration is waiting")
+	callback(2*time.Second, "Ingress is not configured")
+	// Dropped, as the buffer is full
+	callback(3*time.Second, "Dropped")
+
+	received := []Event{<-events.WaitEvents(), <-events.WaitEvents()}
+	assert.DeepEqual(t, received, []Event{
+		{Phase: PhaseWaiting, Kind: "service", Name: "foo", Condition: "Ready", Message: "Configuration is waiting", Elapsed: time.Second},
+		{Phase: PhaseWaiting, Kind: "service", Name: "foo", Condition: "Ready", Message: "Ingress is not configured", Elapsed: 2 * time.Second},
+	})
+
+	done := make(chan []Event)
+	go func() {
+		var final []Event
+		for event := range events.WaitEvents() {
+			final = append(final, event)
+		}
+		done <- final
+	}()
+	events.Report(Event{Phase: PhaseReady, Kind: "service", Name: "foo", Elapsed: 5 * time.Second})
+	events.Close()
+	events.Close()
+	events.Report(Event{Phase: PhaseFailed, Kind: "service", Name: "foo"})
+
+	final := <-done
+	assert.Equal(t, len(final), 1)
+	assert.Equal(t, final[0].Phase, PhaseReady)
+}
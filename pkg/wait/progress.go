@@ -0,0 +1,155 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wait
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"knative.dev/client/pkg/i18n"
+	"knative.dev/client/pkg/printers"
+)
+
+// Phases of a ProgressEvent
+const (
+	// PhaseWaiting is reported for every message received while the resource is not ready yet
+	PhaseWaiting = "waiting"
+	// PhaseReady is reported once when the resource became ready
+	PhaseReady = "ready"
+	// PhaseFailed is reported once when waiting failed or timed out
+	PhaseFailed = "failed"
+)
+
+// Formats supported by NewProgressReporter
+const (
+	ProgressFormatText  = "text"
+	ProgressFormatJSON  = "json"
+	ProgressFormatQuiet = "none"
+)
+
+// ProgressFormats are all formats supported by NewProgressReporter
+var ProgressFormats = []string{ProgressFormatText, ProgressFormatJSON, ProgressFormatQuiet}
+
+// ProgressEvent is a structured event reported while waiting for a resource
+type ProgressEvent struct {
+	Phase     string
+	Kind      string
+	Name      string
+	Condition string
+	Message   string
+	Elapsed   time.Duration
+}
+
+// ProgressReporter receives the events while waiting for a resource
+type ProgressReporter interface {
+	Report(event ProgressEvent)
+}
+
+// NewProgressReporter returns a reporter writing to out in the given format,
+// which is one of ProgressFormats. An empty format selects text.
+func NewProgressReporter(format string, out io.Writer) (ProgressReporter, error) {
+	switch format {
+	case "", ProgressFormatText:
+		return &textProgressReporter{out: out}, nil
+	case ProgressFormatJSON:
+		return &jsonProgressReporter{encoder: json.NewEncoder(out)}, nil
+	case ProgressFormatQuiet:
+		return quietProgressReporter{}, nil
+	}
+	return nil, fmt.Errorf("invalid progress format '%s', must be one of %v", format, ProgressFormats)
+}
+
+// ProgressMessageCallback returns a callback which reports the messages
+// received while waiting for the Ready condition as PhaseWaiting events
+func ProgressMessageCallback(reporter ProgressReporter, kind string, name string) MessageCallback {
+	return ProgressConditionMessageCallback(reporter, kind, name, "Ready")
+}
+
+// ProgressConditionMessageCallback returns a callback which reports the messages
+// received while waiting for the given condition as PhaseWaiting events
+func ProgressConditionMessageCallback(reporter ProgressReporter, kind string, name string, condition string) MessageCallback {
+	return func(duration time.Duration, message string) {
+		reporter.Report(ProgressEvent{
+			Phase:     PhaseWaiting,
+			Kind:      kind,
+			Name:      name,
+			Condition: condition,
+			Message:   message,
+			Elapsed:   duration,
+		})
+	}
+}
+
+// textProgressReporter prints the messages like SimpleMessageCallback, and a
+// final line when the resource is ready or the awaited condition is True.
+// Failures are left to the caller.
+type textProgressReporter struct {
+	out        io.Writer
+	oldMessage string
+}
+
+func (r *textProgressReporter) Report(event ProgressEvent) {
+	switch event.Phase {
+	case PhaseWaiting:
+		if printers.StableOutput {
+			return
+		}
+		txt := event.Message
+		if event.Message == r.oldMessage {
+			txt = "..."
+		}
+		printers.WriteElapsed(r.out, event.Elapsed, txt)
+		r.oldMessage = event.Message
+	case PhaseReady:
+		if event.Condition != "" && event.Condition != "Ready" {
+			printers.WriteElapsed(r.out, event.Elapsed, i18n.Sprintf("Condition %s is True.", event.Condition))
+			return
+		}
+		printers.WriteElapsed(r.out, event.Elapsed, i18n.T("Ready to serve."))
+	}
+}
+
+// jsonProgressReporter writes each event as a single line of JSON
+type jsonProgressReporter struct {
+	encoder *json.Encoder
+}
+
+type jsonProgressEvent struct {
+	Phase          string  `json:"phase"`
+	Kind           string  `json:"kind"`
+	Name           string  `json:"name"`
+	Condition      string  `json:"condition,omitempty"`
+	Message        string  `json:"message,omitempty"`
+	ElapsedSeconds float64 `json:"elapsedSeconds"`
+}
+
+func (r *jsonProgressReporter) Report(event ProgressEvent) {
+	// Errors of the underlying writer are ignored like for the text output
+	_ = r.encoder.Encode(jsonProgressEvent{
+		Phase:          event.Phase,
+		Kind:           event.Kind,
+		Name:           event.Name,
+		Condition:      event.Condition,
+		Message:        event.Message,
+		ElapsedSeconds: event.Elapsed.Round(time.Millisecond).Seconds(),
+	})
+}
+
+// quietProgressReporter drops all events
+type quietProgressReporter struct{}
+
+func (quietProgressReporter) Report(ProgressEvent) {}
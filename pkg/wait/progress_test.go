@@ -0,0 +1,75 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wait
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+
+	"knative.dev/client/pkg/util"
+)
+
+func TestProgressReporters(t *testing.T) {
+	events := []ProgressEvent{
+		{Phase: PhaseWaiting, Kind: "service", Name: "foo", Condition: "Ready", Message: "Configuration is waiting", Elapsed: time.Second},
+		{Phase: PhaseWaiting, Kind: "service", Name: "foo", Condition: "Ready", Message: "Configuration is waiting", Elapsed: 2 * time.Second},
+		{Phase: PhaseReady, Kind: "service", Name: "foo", Condition: "Ready", Elapsed: 2500 * time.Millisecond},
+	}
+	report := func(format string) string {
+		out := new(bytes.Buffer)
+		reporter, err := NewProgressReporter(format, out)
+		assert.NilError(t, err)
+		for _, event := range events {
+			reporter.Report(event)
+		}
+		return out.String()
+	}
+
+	assert.Equal(t, report("text"), "  1.000s Configuration is waiting\n  2.000s ...\n  2.500s Ready to serve.\n")
+	assert.Equal(t, report(""), report("text"))
+	assert.Equal(t, report("none"), "")
+
+	json := report("json")
+	assert.Assert(t, util.ContainsAll(json,
+		`{"phase":"waiting","kind":"service","name":"foo","condition":"Ready","message":"Configuration is waiting","elapsedSeconds":1}`,
+		`{"phase":"ready","kind":"service","name":"foo","condition":"Ready","elapsedSeconds":2.5}`))
+	assert.Equal(t, bytes.Count([]byte(json), []byte("\n")), 3)
+
+	_, err := NewProgressReporter("xml", nil)
+	assert.ErrorContains(t, err, "invalid progress format 'xml'")
+}
+
+func TestProgressMessageCallback(t *testing.T) {
+	out := new(bytes.Buffer)
+	reporter, err := NewProgressReporter("json", out)
+	assert.NilError(t, err)
+	ProgressMessageCallback(reporter, "service", "foo")(time.Second, "waiting")
+	assert.Equal(t, out.String(), `{"phase":"waiting","kind":"service","name":"foo","condition":"Ready","message":"waiting","elapsedSeconds":1}`+"\n")
+
+	out.Reset()
+	ProgressConditionMessageCallback(reporter, "service", "foo", "RoutesReady")(time.Second, "waiting")
+	assert.Assert(t, util.ContainsAll(out.String(), `"condition":"RoutesReady"`))
+}
+
+func TestTextProgressReporterCondition(t *testing.T) {
+	out := new(bytes.Buffer)
+	reporter, err := NewProgressReporter("text", out)
+	assert.NilError(t, err)
+	reporter.Report(ProgressEvent{Phase: PhaseReady, Kind: "service", Name: "foo", Condition: "RoutesReady", Elapsed: time.Second})
+	assert.Equal(t, out.String(), "  1.000s Condition RoutesReady is True.\n")
+}
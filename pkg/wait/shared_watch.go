@@ -0,0 +1,211 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wait
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"knative.dev/client/pkg/util"
+)
+
+// SharedWatch serves watches on individual resources of one kind from a single
+// shared informer, instead of opening a watch on the API server per resource.
+// Use it when many waits run concurrently in one process, e.g. when creating
+// many services at once. The informer is started with the first watch and
+// stopped again when the last watch has been stopped.
+type SharedWatch struct {
+	listWatch cache.ListerWatcher
+	objType   runtime.Object
+
+	mutex         sync.Mutex
+	informer      cache.SharedInformer
+	stopCh        chan struct{}
+	subscriptions map[string]map[*sharedWatchSubscription]bool
+}
+
+// NewSharedWatch creates a SharedWatch for resources of the given object type,
+// listed and watched with the given ListerWatcher
+func NewSharedWatch(listWatch cache.ListerWatcher, objType runtime.Object) *SharedWatch {
+	return &SharedWatch{
+		listWatch:     listWatch,
+		objType:       objType,
+		subscriptions: map[string]map[*sharedWatchSubscription]bool{},
+	}
+}
+
+// Watch returns a watch on the resource with the given name, which receives the
+// events from the time of the call on. Only the watch which starts the informer
+// receives "Added" events for existing resources. Watch has the signature of a
+// WatchMaker, the timeout limits the time for the initial sync of the informer.
+func (s *SharedWatch) Watch(name string, timeout time.Duration) (watch.Interface, error) {
+	subscription := newSharedWatchSubscription(func(subscription *sharedWatchSubscription) {
+		s.unsubscribe(name, subscription)
+	})
+	informer := s.subscribe(name, subscription)
+
+	if !waitForCacheSync(informer, timeout) {
+		subscription.Stop()
+		return nil, fmt.Errorf("timeout: informer cache not synced after %d seconds", int(timeout/time.Second))
+	}
+	return subscription, nil
+}
+
+func (s *SharedWatch) subscribe(name string, subscription *sharedWatchSubscription) cache.SharedInformer {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.informer == nil {
+		s.informer = cache.NewSharedInformer(s.listWatch, s.objType, 0)
+		s.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				s.dispatch(watch.Added, obj)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				s.dispatch(watch.Modified, newObj)
+			},
+			DeleteFunc: func(obj interface{}) {
+				if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = tombstone.Obj
+				}
+				s.dispatch(watch.Deleted, obj)
+			},
+		})
+		s.stopCh = make(chan struct{})
+		klog.V(util.LogLevelWatch).InfoS("Starting shared informer", "type", fmt.Sprintf("%T", s.objType))
+		go s.informer.Run(s.stopCh)
+	}
+	if s.subscriptions[name] == nil {
+		s.subscriptions[name] = map[*sharedWatchSubscription]bool{}
+	}
+	s.subscriptions[name][subscription] = true
+	return s.informer
+}
+
+func (s *SharedWatch) unsubscribe(name string, subscription *sharedWatchSubscription) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.subscriptions[name], subscription)
+	if len(s.subscriptions[name]) == 0 {
+		delete(s.subscriptions, name)
+	}
+	if len(s.subscriptions) == 0 && s.informer != nil {
+		klog.V(util.LogLevelWatch).InfoS("Stopping shared informer", "type", fmt.Sprintf("%T", s.objType))
+		close(s.stopCh)
+		s.informer = nil
+	}
+}
+
+func (s *SharedWatch) dispatch(eventType watch.EventType, obj interface{}) {
+	object, ok := obj.(runtime.Object)
+	if !ok {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for subscription := range s.subscriptions[objectName(obj)] {
+		subscription.push(watch.Event{Type: eventType, Object: object})
+	}
+}
+
+func waitForCacheSync(informer cache.SharedInformer, timeout time.Duration) bool {
+	stopCh := make(chan struct{})
+	timer := time.AfterFunc(timeout, func() { close(stopCh) })
+	defer timer.Stop()
+	return cache.WaitForCacheSync(stopCh, informer.HasSynced)
+}
+
+func objectName(obj interface{}) string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return ""
+	}
+	return accessor.GetName()
+}
+
+// sharedWatchSubscription is the watch.Interface for a single resource. Events are
+// queued so that a slow consumer never blocks the informer and the other watches.
+type sharedWatchSubscription struct {
+	result      chan watch.Event
+	done        chan struct{}
+	signal      chan struct{}
+	unsubscribe func(*sharedWatchSubscription)
+	stopOnce    sync.Once
+
+	mutex sync.Mutex
+	queue []watch.Event
+}
+
+func newSharedWatchSubscription(unsubscribe func(*sharedWatchSubscription)) *sharedWatchSubscription {
+	subscription := &sharedWatchSubscription{
+		result:      make(chan watch.Event),
+		done:        make(chan struct{}),
+		signal:      make(chan struct{}, 1),
+		unsubscribe: unsubscribe,
+	}
+	go subscription.run()
+	return subscription
+}
+
+func (w *sharedWatchSubscription) push(event watch.Event) {
+	w.mutex.Lock()
+	w.queue = append(w.queue, event)
+	w.mutex.Unlock()
+	select {
+	case w.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (w *sharedWatchSubscription) run() {
+	defer close(w.result)
+	for {
+		w.mutex.Lock()
+		if len(w.queue) == 0 {
+			w.mutex.Unlock()
+			select {
+			case <-w.signal:
+				continue
+			case <-w.done:
+				return
+			}
+		}
+		event := w.queue[0]
+		w.queue = w.queue[1:]
+		w.mutex.Unlock()
+		select {
+		case w.result <- event:
+		case <-w.done:
+			return
+		}
+	}
+}
+
+func (w *sharedWatchSubscription) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+func (w *sharedWatchSubscription) Stop() {
+	w.stopOnce.Do(func() {
+		w.unsubscribe(w)
+		close(w.done)
+	})
+}
@@ -0,0 +1,164 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wait
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/apis"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// fakeListWatch lists the given services and counts the watches opened on the API server
+type fakeListWatch struct {
+	services []servingv1.Service
+	watcher  *watch.FakeWatcher
+	mutex    sync.Mutex
+	watches  int
+}
+
+func (f *fakeListWatch) listWatch() *cache.ListWatch {
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return &servingv1.ServiceList{Items: f.services}, nil
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			f.mutex.Lock()
+			defer f.mutex.Unlock()
+			f.watches++
+			return f.watcher, nil
+		},
+	}
+}
+
+func (f *fakeListWatch) watchCount() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.watches
+}
+
+func (s *SharedWatch) subscriptionCount() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	count := 0
+	for _, subscriptions := range s.subscriptions {
+		count += len(subscriptions)
+	}
+	return count
+}
+
+func newFakeListWatch(names ...string) *fakeListWatch {
+	fake := &fakeListWatch{watcher: watch.NewFakeWithChanSize(10, false)}
+	for _, name := range names {
+		service := CreateTestServiceWithConditions(name, corev1.ConditionUnknown, corev1.ConditionUnknown, "", "")
+		fake.services = append(fake.services, *service.(*servingv1.Service))
+	}
+	return fake
+}
+
+func serviceConditions(obj runtime.Object) (apis.Conditions, error) {
+	return apis.Conditions(obj.(*servingv1.Service).Status.Conditions), nil
+}
+
+// nextChange returns the next event which is not an "Added" event for an existing resource
+func nextChange(t *testing.T, watcher watch.Interface) watch.Event {
+	for {
+		select {
+		case event := <-watcher.ResultChan():
+			if event.Type != watch.Added {
+				return event
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("no event received")
+			return watch.Event{}
+		}
+	}
+}
+
+func TestSharedWatch(t *testing.T) {
+	fake := newFakeListWatch("foo", "bar")
+	sharedWatch := NewSharedWatch(fake.listWatch(), &servingv1.Service{})
+
+	fooWatch, err := sharedWatch.Watch("foo", 5*time.Second)
+	assert.NilError(t, err)
+	barWatch, err := sharedWatch.Watch("bar", 5*time.Second)
+	assert.NilError(t, err)
+	// Both watches are served from a single watch on the API server
+	assert.Equal(t, fake.watchCount(), 1)
+
+	// Events are routed to the watch of the resource only
+	fake.watcher.Modify(CreateTestServiceWithConditions("bar", corev1.ConditionTrue, corev1.ConditionTrue, "", ""))
+	fake.watcher.Modify(CreateTestServiceWithConditions("foo", corev1.ConditionTrue, corev1.ConditionTrue, "", ""))
+	event := nextChange(t, fooWatch)
+	assert.Equal(t, event.Type, watch.Modified)
+	assert.Equal(t, objectName(event.Object), "foo")
+	event = nextChange(t, barWatch)
+	assert.Equal(t, event.Type, watch.Modified)
+	assert.Equal(t, objectName(event.Object), "bar")
+
+	fake.watcher.Delete(CreateTestServiceWithConditions("foo", corev1.ConditionTrue, corev1.ConditionTrue, "", ""))
+	event = nextChange(t, fooWatch)
+	assert.Equal(t, event.Type, watch.Deleted)
+
+	// The informer is stopped with the last watch
+	fooWatch.Stop()
+	assert.Assert(t, !fake.watcher.IsStopped())
+	barWatch.Stop()
+	assert.Equal(t, sharedWatch.subscriptionCount(), 0)
+	assert.Assert(t, sharedWatch.informer == nil)
+	_, ok := <-fooWatch.ResultChan()
+	assert.Assert(t, !ok)
+}
+
+func TestSharedWatchConcurrentWaits(t *testing.T) {
+	names := []string{"s1", "s2", "s3", "s4", "s5"}
+	fake := newFakeListWatch(names...)
+	sharedWatch := NewSharedWatch(fake.listWatch(), &servingv1.Service{})
+	timeout := 10 * time.Second
+
+	errs := make(chan error, len(names))
+	for _, name := range names {
+		go func(name string) {
+			watcher, err := sharedWatch.Watch(name, timeout)
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer watcher.Stop()
+			err, _ = NewWaitForReady("service", serviceConditions).Wait(watcher, name, Options{Timeout: &timeout}, NoopMessageCallback())
+			errs <- err
+		}(name)
+	}
+
+	// Make all services ready once all waits are subscribed
+	for sharedWatch.subscriptionCount() < len(names) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	for _, name := range names {
+		fake.watcher.Modify(CreateTestServiceWithConditions(name, corev1.ConditionTrue, corev1.ConditionTrue, "", ""))
+	}
+	for range names {
+		assert.NilError(t, <-errs)
+	}
+	assert.Equal(t, fake.watchCount(), 1)
+}
@@ -0,0 +1,46 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wait
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/watch"
+
+	"knative.dev/client/pkg/i18n"
+)
+
+// WaitForDeletion waits until the watcher reports the deletion of the resource
+// with the given name, but not longer than the timeout. Start the watch before
+// deleting the resource, so that its deletion can't be missed.
+func WaitForDeletion(watcher watch.Interface, kind string, name string, timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			return i18n.Errorf("timeout: %s '%s' not deleted after %d seconds", kind, name, int(timeout/time.Second))
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return fmt.Errorf("watch of %s '%s' ended before its deletion", kind, name)
+			}
+			logWatchEvent(kind, name, event)
+			if event.Type == watch.Deleted {
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wait
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestWaitForDeletion(t *testing.T) {
+	service := CreateTestServiceWithConditions("foo", corev1.ConditionTrue, corev1.ConditionTrue, "", "")
+	watcher := NewFakeWatch([]watch.Event{
+		{Type: watch.Modified, Object: service},
+		{Type: watch.Deleted, Object: service},
+	})
+	watcher.Start()
+	err := WaitForDeletion(watcher, "service", "foo", time.Minute)
+	assert.NilError(t, err)
+}
+
+func TestWaitForDeletionTimeout(t *testing.T) {
+	service := CreateTestServiceWithConditions("foo", corev1.ConditionTrue, corev1.ConditionTrue, "", "")
+	watcher := NewFakeWatch([]watch.Event{{Type: watch.Modified, Object: service}})
+	watcher.Start()
+	err := WaitForDeletion(watcher, "service", "foo", 10*time.Millisecond)
+	assert.Error(t, err, "timeout: service 'foo' not deleted after 0 seconds")
+}
+
+func TestWaitForDeletionWatchEnded(t *testing.T) {
+	watcher := watch.NewFake()
+	watcher.Stop()
+	err := WaitForDeletion(watcher, "broker", "foo", time.Minute)
+	assert.Error(t, err, "watch of broker 'foo' ended before its deletion")
+}
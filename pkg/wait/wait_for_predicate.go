@@ -0,0 +1,121 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wait
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"knative.dev/pkg/apis"
+
+	"knative.dev/client/pkg/i18n"
+)
+
+// Predicate checks whether a resource has reached the state waited for, given the resource and
+// its conditions. An error stops waiting, e.g. when the state can't be reached anymore.
+type Predicate func(obj runtime.Object, conditions apis.Conditions) (done bool, err error)
+
+// Callbacks and configuration used while waiting for a predicate
+type waitForPredicate struct {
+	conditionsExtractor ConditionsExtractor
+	predicate           Predicate
+	kind                string
+}
+
+// NewWaitForPredicate creates a Wait object which waits until the predicate is true for the resource,
+// e.g. for a service which is ready with a specific latest created revision.
+// Unlike NewWaitForReady, the initial state of the resource is checked too.
+func NewWaitForPredicate(kind string, extractor ConditionsExtractor, predicate Predicate) Wait {
+	return &waitForPredicate{
+		kind:                kind,
+		conditionsExtractor: extractor,
+		predicate:           predicate,
+	}
+}
+
+// Wait until the predicate is true for the resource. Only resources whose status reflects their latest
+// generation are checked. msgCallback gets called with the message of the "Ready" condition.
+func (w *waitForPredicate) Wait(watcher watch.Interface, name string, options Options, msgCallback MessageCallback) (error, time.Duration) {
+	timeout := options.timeoutWithDefault()
+	start := time.Now()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			return i18n.Errorf("timeout: %s '%s' not in the expected state after %d seconds", w.kind, name, int(timeout/time.Second)), time.Since(start)
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return fmt.Errorf("watch of %s '%s' ended before it reached the expected state", w.kind, name), time.Since(start)
+			}
+			logWatchEvent(w.kind, name, event)
+			if event.Object == nil || (event.Type != watch.Added && event.Type != watch.Modified) {
+				continue
+			}
+			inSync, err := generationCheck(event.Object)
+			if err != nil {
+				return err, time.Since(start)
+			}
+			if !inSync {
+				continue
+			}
+			conditions, err := w.conditionsExtractor(event.Object)
+			if err != nil {
+				return err, time.Since(start)
+			}
+			done, err := w.predicate(event.Object, conditions)
+			if err != nil || done {
+				return err, time.Since(start)
+			}
+			if cond := readyCondition(conditions); cond != nil && cond.Message != "" {
+				msgCallback(time.Since(start), cond.Message)
+			}
+		}
+	}
+}
+
+// ReadyPredicate returns a predicate which is true when the "Ready" condition is True and the given
+// check is true for the resource. It stops waiting with an error when the "Ready" condition is False.
+func ReadyPredicate(check func(obj runtime.Object) bool) Predicate {
+	return func(obj runtime.Object, conditions apis.Conditions) (bool, error) {
+		cond := readyCondition(conditions)
+		if cond == nil {
+			return false, nil
+		}
+		switch cond.Status {
+		case corev1.ConditionTrue:
+			return check(obj), nil
+		case corev1.ConditionFalse:
+			return false, fmt.Errorf("%s: %s", cond.Reason, cond.Message)
+		}
+		return false, nil
+	}
+}
+
+func readyCondition(conditions apis.Conditions) *apis.Condition {
+	return findCondition(conditions, apis.ConditionReady)
+}
+
+func findCondition(conditions apis.Conditions, conditionType apis.ConditionType) *apis.Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wait
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+func serviceWithLatestCreated(revision string, readyStatus corev1.ConditionStatus, message string, generations ...int64) runtime.Object {
+	service := CreateTestServiceWithConditions("foo", readyStatus, corev1.ConditionUnknown, "RevisionFailed", message, generations...)
+	service.(*servingv1.Service).Status.LatestCreatedRevisionName = revision
+	return service
+}
+
+func latestCreatedReady(revision string) Predicate {
+	return ReadyPredicate(func(obj runtime.Object) bool {
+		return obj.(*servingv1.Service).Status.LatestCreatedRevisionName == revision
+	})
+}
+
+func TestWaitForPredicate(t *testing.T) {
+	watcher := NewFakeWatch([]watch.Event{
+		// Ready, but with the previous revision
+		{Type: watch.Added, Object: serviceWithLatestCreated("foo-1", corev1.ConditionTrue, "")},
+		{Type: watch.Modified, Object: serviceWithLatestCreated("foo-2", corev1.ConditionUnknown, "Deploying foo-2")},
+		// Status not yet updated for the latest generation
+		{Type: watch.Modified, Object: serviceWithLatestCreated("foo-2", corev1.ConditionTrue, "", 3, 2)},
+		{Type: watch.Modified, Object: serviceWithLatestCreated("foo-2", corev1.ConditionTrue, "")},
+	})
+	watcher.Start()
+
+	var messages []string
+	err, _ := NewWaitForPredicate("service", serviceConditions, latestCreatedReady("foo-2")).Wait(watcher, "foo", Options{},
+		func(_ time.Duration, message string) {
+			messages = append(messages, message)
+		})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, messages, []string{"Deploying foo-2"})
+}
+
+func TestWaitForPredicateInitialState(t *testing.T) {
+	watcher := NewFakeWatch([]watch.Event{
+		{Type: watch.Added, Object: serviceWithLatestCreated("foo-2", corev1.ConditionTrue, "")},
+	})
+	watcher.Start()
+	err, _ := NewWaitForPredicate("service", serviceConditions, latestCreatedReady("foo-2")).Wait(watcher, "foo", Options{}, NoopMessageCallback())
+	assert.NilError(t, err)
+}
+
+func TestWaitForPredicateNotReady(t *testing.T) {
+	watcher := NewFakeWatch([]watch.Event{
+		{Type: watch.Modified, Object: serviceWithLatestCreated("foo-2", corev1.ConditionFalse, "Unable to fetch image")},
+	})
+	watcher.Start()
+	err, _ := NewWaitForPredicate("service", serviceConditions, latestCreatedReady("foo-2")).Wait(watcher, "foo", Options{}, NoopMessageCallback())
+	assert.Error(t, err, "RevisionFailed: Unable to fetch image")
+}
+
+func TestWaitForPredicateTimeout(t *testing.T) {
+	watcher := NewFakeWatch([]watch.Event{
+		{Type: watch.Modified, Object: serviceWithLatestCreated("foo-1", corev1.ConditionTrue, "")},
+	})
+	watcher.Start()
+	timeout := 10 * time.Millisecond
+	err, _ := NewWaitForPredicate("service", serviceConditions, latestCreatedReady("foo-2")).Wait(watcher, "foo", Options{Timeout: &timeout}, NoopMessageCallback())
+	assert.Error(t, err, "timeout: service 'foo' not in the expected state after 0 seconds")
+}
+
+func TestWaitForPredicateWatchEnded(t *testing.T) {
+	watcher := watch.NewFake()
+	watcher.Stop()
+	err, _ := NewWaitForPredicate("service", serviceConditions, latestCreatedReady("foo-2")).Wait(watcher, "foo", Options{}, NoopMessageCallback())
+	assert.Error(t, err, "watch of service 'foo' ended before it reached the expected state")
+}
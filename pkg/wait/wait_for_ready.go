@@ -15,19 +15,26 @@
 package wait
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/klog/v2"
 	"knative.dev/pkg/apis"
+
+	"knative.dev/client/pkg/i18n"
+	"knative.dev/client/pkg/util"
 )
 
 // Callbacks and configuration used while waiting
 type waitForReadyConfig struct {
 	conditionsExtractor ConditionsExtractor
+	conditionType       apis.ConditionType
 	kind                string
 }
 
@@ -57,6 +64,21 @@ type Options struct {
 
 	// Timeout for how long to wait at maximum
 	Timeout *time.Duration
+
+	// Grace period for errors caused by the infrastructure, like a webhook
+	// certificate rotation or an API server restart (see ClassifyError).
+	// Such errors are reported only if they persist longer than this period.
+	TransientErrorTolerance *time.Duration
+
+	// CauseExtractor finds the causes of the resource not being ready, like the
+	// failed conditions of the resources it depends on. The causes are added to the
+	// messages passed to the MessageCallback and to the error, unless the messages
+	// contain them already.
+	CauseExtractor CauseExtractor
+
+	// ConditionsCallback is called with all conditions of the resource and the
+	// causes found for every event while waiting for a condition
+	ConditionsCallback ConditionsCallback
 }
 
 // Create watch which is used when waiting for Ready condition
@@ -70,8 +92,14 @@ type MessageCallback func(durationSinceState time.Duration, message string)
 
 // NewWaitForReady waits until the condition is set to Ready == True
 func NewWaitForReady(kind string, extractor ConditionsExtractor) Wait {
+	return NewWaitForCondition(kind, apis.ConditionReady, extractor)
+}
+
+// NewWaitForCondition waits until the condition of the given type is set to True
+func NewWaitForCondition(kind string, conditionType apis.ConditionType, extractor ConditionsExtractor) Wait {
 	return &waitForReadyConfig{
 		kind:                kind,
+		conditionType:       conditionType,
 		conditionsExtractor: extractor,
 	}
 }
@@ -85,17 +113,10 @@ func NewWaitForEvent(kind string, eventDone EventDone) Wait {
 	}
 }
 
-// SimpleMessageCallback returns a callback which prints out a simple event message to a given writer
+// SimpleMessageCallback returns a callback which prints out a simple event message to a given writer.
+// With stable output, nothing is printed as the messages depend on the timing of the events.
 func SimpleMessageCallback(out io.Writer) MessageCallback {
-	oldMessage := ""
-	return func(duration time.Duration, message string) {
-		txt := message
-		if message == oldMessage {
-			txt = "..."
-		}
-		fmt.Fprintf(out, "%7.3fs %s\n", float64(duration.Round(time.Millisecond))/float64(time.Second), txt)
-		oldMessage = message
-	}
+	return ProgressMessageCallback(&textProgressReporter{out: out}, "", "")
 }
 
 // NoopMessageCallback is callback which does nothing
@@ -112,15 +133,22 @@ func (w *waitForReadyConfig) Wait(watcher watch.Interface, name string, options
 
 	timeout := options.timeoutWithDefault()
 	floatingTimeout := timeout
+	// Causes found with the last event, reported when the timeout is reached
+	var causes []Cause
 	for {
 		start := time.Now()
-		retry, timeoutReached, err := w.waitForReadyCondition(watcher, start, name, floatingTimeout, options.errorWindowWithDefault(), msgCallback)
+		retry, timeoutReached, err := w.waitForReadyCondition(watcher, start, name, floatingTimeout, options, msgCallback, &causes)
 		if err != nil {
 			return err, time.Since(start)
 		}
 		floatingTimeout = floatingTimeout - time.Since(start)
 		if timeoutReached || floatingTimeout < 0 {
-			return fmt.Errorf("timeout: %s '%s' not ready after %d seconds", w.kind, name, int(timeout/time.Second)), time.Since(start)
+			if w.conditionType != apis.ConditionReady {
+				message := i18n.Sprintf("timeout: condition %s of %s '%s' not true after %d seconds", w.conditionType, w.kind, name, int(timeout/time.Second))
+				return errors.New(withCauses(message, causes)), time.Since(start)
+			}
+			message := i18n.Sprintf("timeout: %s '%s' not ready after %d seconds", w.kind, name, int(timeout/time.Second))
+			return errors.New(withCauses(message, causes)), time.Since(start)
 		}
 
 		if retry {
@@ -131,13 +159,14 @@ func (w *waitForReadyConfig) Wait(watcher watch.Interface, name string, options
 	}
 }
 
-// waitForReadyCondition waits until the status condition "Ready" is set to true (good path) or return an error
+// waitForReadyCondition waits until the status condition "Ready" (or the configured condition) is set to true (good path) or return an error
 // when the "Ready" condition is set to false. An error is also returned when the given timeout is reached (plus the
 // return value of timeoutReached is set to true in this case).
 // An errorWindow can be specified which takes into account of intermediate "false" ready conditions. So before returning
 // an error, this methods waits for the errorWindow duration and if an "True" or "Unknown" event arrives in the meantime
-// for the "Ready" condition, then the method continues to wait.
-func (w *waitForReadyConfig) waitForReadyCondition(watcher watch.Interface, start time.Time, name string, timeout time.Duration, errorWindow time.Duration, msgCallback MessageCallback) (retry bool, timeoutReached bool, err error) {
+// for the "Ready" condition, then the method continues to wait. For transient errors, the window is extended to the
+// transient error tolerance.
+func (w *waitForReadyConfig) waitForReadyCondition(watcher watch.Interface, start time.Time, name string, timeout time.Duration, options Options, msgCallback MessageCallback, causes *[]Cause) (retry bool, timeoutReached bool, err error) {
 
 	// channel used to transport the error that has been received
 	errChan := make(chan error)
@@ -163,8 +192,10 @@ func (w *waitForReadyConfig) waitForReadyCondition(watcher watch.Interface, star
 			return false, false, err
 		case event, ok := <-watcher.ResultChan():
 			if !ok || event.Object == nil {
+				klog.V(util.LogLevelWatch).InfoS("Watch closed, restarting", "kind", w.kind, "name", name)
 				return true, false, nil
 			}
+			logWatchEvent(w.kind, name, event)
 
 			// Skip event if its not a MODIFIED event, as only MODIFIED events update the condition
 			// we are looking for.
@@ -193,8 +224,15 @@ func (w *waitForReadyConfig) waitForReadyCondition(watcher watch.Interface, star
 			if err != nil {
 				return false, false, err
 			}
+			*causes = nil
+			if cond := findCondition(conditions, w.conditionType); cond == nil || cond.Status != corev1.ConditionTrue {
+				*causes = options.causes(w.kind, name, event.Object)
+			}
+			if options.ConditionsCallback != nil {
+				options.ConditionsCallback(time.Since(start), conditions, *causes)
+			}
 			for _, cond := range conditions {
-				if cond.Type == apis.ConditionReady {
+				if cond.Type == w.conditionType {
 					switch cond.Status {
 					case corev1.ConditionTrue:
 						// Any error timer running will be cancelled by the defer method that has been set above
@@ -205,8 +243,8 @@ func (w *waitForReadyConfig) waitForReadyCondition(watcher watch.Interface, star
 						// this window, then an error is returned.
 						// If there is already a timer running, we just log.
 						if errorTimer == nil {
-							err := fmt.Errorf("%s: %s", cond.Reason, cond.Message)
-							errorTimer = time.AfterFunc(errorWindow, func() {
+							err := errors.New(withCauses(fmt.Sprintf("%s: %s", cond.Reason, cond.Message), *causes))
+							errorTimer = time.AfterFunc(options.errorWindowFor(cond.Message), func() {
 								errChan <- err
 							})
 						}
@@ -220,7 +258,7 @@ func (w *waitForReadyConfig) waitForReadyCondition(watcher watch.Interface, star
 						}
 					}
 					if cond.Message != "" {
-						msgCallback(time.Since(start), cond.Message)
+						msgCallback(time.Since(start), withCauses(cond.Message, *causes))
 					}
 				}
 			}
@@ -239,10 +277,11 @@ func (w *waitForEvent) Wait(watcher watch.Interface, name string, options Option
 	for {
 		select {
 		case <-timer.C:
-			return fmt.Errorf("timeout: %s '%s' not ready after %d seconds", w.kind, name, int(timeout/time.Second)), time.Since(start)
+			return i18n.Errorf("timeout: %s '%s' not ready after %d seconds", w.kind, name, int(timeout/time.Second)), time.Since(start)
 		case err := <-errChan:
 			return err, time.Since(start)
 		case event := <-watcher.ResultChan():
+			logWatchEvent(w.kind, name, event)
 			if w.eventDone(&event) {
 				return nil, time.Since(start)
 			}
@@ -250,6 +289,40 @@ func (w *waitForEvent) Wait(watcher watch.Interface, name string, options Option
 	}
 }
 
+// logWatchEvent logs the type of a watch event and the generations of the object
+func logWatchEvent(kind string, name string, event watch.Event) {
+	if !klog.V(util.LogLevelWatch).Enabled() {
+		return
+	}
+	keysAndValues := []interface{}{"kind", kind, "name", name, "type", event.Type}
+	if event.Object != nil {
+		if obj, err := meta.Accessor(event.Object); err == nil {
+			keysAndValues = append(keysAndValues, "generation", obj.GetGeneration(), "resourceVersion", obj.GetResourceVersion())
+		}
+	}
+	klog.V(util.LogLevelWatch).InfoS("Received watch event", keysAndValues...)
+}
+
+// IsConditionTrue returns true if the reconciler has observed the latest generation of the
+// object and has set its condition of the given type to True. Use it for checking the state of
+// an object before waiting, as the wait considers only changes.
+func IsConditionTrue(obj runtime.Object, conditionType apis.ConditionType, extractor ConditionsExtractor) (bool, error) {
+	inSync, err := generationCheck(obj)
+	if err != nil || !inSync {
+		return false, err
+	}
+	conditions, err := extractor(obj)
+	if err != nil {
+		return false, err
+	}
+	for _, cond := range conditions {
+		if cond.Type == conditionType {
+			return cond.Status == corev1.ConditionTrue, nil
+		}
+	}
+	return false, nil
+}
+
 func generationCheck(object runtime.Object) (bool, error) {
 	unstructured, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
 	if err != nil {
@@ -275,6 +348,20 @@ func generationCheck(object runtime.Object) (bool, error) {
 	return givenGeneration == observedGeneration, nil
 }
 
+// causes returns the causes of the resource not being ready, found with the CauseExtractor.
+// Failing to find them is only logged, as they only add details to the messages.
+func (o Options) causes(kind string, name string, obj runtime.Object) []Cause {
+	if o.CauseExtractor == nil {
+		return nil
+	}
+	causes, err := o.CauseExtractor(obj)
+	if err != nil {
+		klog.V(util.LogLevelWatch).InfoS("Cannot find the causes of not being ready", "kind", kind, "name", name, "error", err)
+		return nil
+	}
+	return causes
+}
+
 func (o Options) timeoutWithDefault() time.Duration {
 	if o.Timeout != nil {
 		return *o.Timeout
@@ -288,3 +375,20 @@ func (o Options) errorWindowWithDefault() time.Duration {
 	}
 	return 2 * time.Second
 }
+
+func (o Options) transientErrorToleranceWithDefault() time.Duration {
+	if o.TransientErrorTolerance != nil {
+		return *o.TransientErrorTolerance
+	}
+	return DefaultTransientErrorTolerance
+}
+
+// Window for how long a ReadyCondition == false with the given message has to stay
+// for being considered as an error
+func (o Options) errorWindowFor(message string) time.Duration {
+	errorWindow := o.errorWindowWithDefault()
+	if ClassifyMessage(message) == ErrorCategoryTransient && o.transientErrorToleranceWithDefault() > errorWindow {
+		return o.transientErrorToleranceWithDefault()
+	}
+	return errorWindow
+}
@@ -15,6 +15,8 @@
 package wait
 
 import (
+	"bytes"
+	"flag"
 	"testing"
 	"time"
 
@@ -23,8 +25,12 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/klog/v2"
 	"knative.dev/pkg/apis"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/client/pkg/printers"
+	"knative.dev/client/pkg/util"
 )
 
 type waitForReadyTestCase struct {
@@ -194,3 +200,87 @@ func deNormal(name string) ([]watch.Event, int) {
 		{Type: watch.Deleted, Object: CreateTestServiceWithConditions(name, corev1.ConditionTrue, corev1.ConditionTrue, "", "")},
 	}, len(messages)
 }
+
+func TestSimpleMessageCallback(t *testing.T) {
+	defer func(noTimestamps, stableOutput bool) {
+		printers.NoTimestamps, printers.StableOutput = noTimestamps, stableOutput
+	}(printers.NoTimestamps, printers.StableOutput)
+
+	out := new(bytes.Buffer)
+	callback := SimpleMessageCallback(out)
+	callback(time.Second, "Creating revision")
+	callback(2*time.Second, "Creating revision")
+	assert.Equal(t, out.String(), "  1.000s Creating revision\n  2.000s ...\n")
+
+	printers.NoTimestamps = true
+	out.Reset()
+	callback = SimpleMessageCallback(out)
+	callback(time.Second, "Creating revision")
+	assert.Equal(t, out.String(), "Creating revision\n")
+
+	printers.StableOutput = true
+	out.Reset()
+	callback = SimpleMessageCallback(out)
+	callback(time.Second, "Creating revision")
+	assert.Equal(t, out.String(), "")
+}
+
+func TestWaitForReadyTransientErrorTolerance(t *testing.T) {
+	errorWindow := 10 * time.Millisecond
+	tolerance := 5 * time.Second
+	timeout := 10 * time.Second
+	options := Options{Timeout: &timeout, ErrorWindow: &errorWindow, TransientErrorTolerance: &tolerance}
+	extractor := func(obj runtime.Object) (apis.Conditions, error) {
+		return apis.Conditions(obj.(*servingv1.Service).Status.Conditions), nil
+	}
+
+	for _, tc := range []struct {
+		message   string
+		errorText string
+	}{
+		{"Internal error occurred: failed calling webhook \"webhook.serving.knative.dev\": x509: certificate has expired", ""},
+		{"Unable to fetch image", "RevisionFailed"},
+	} {
+		fakeWatchApi := NewFakeWatch(nil)
+		go func() {
+			fakeWatchApi.eventChan <- watch.Event{Type: watch.Modified,
+				Object: CreateTestServiceWithConditions("foo", corev1.ConditionFalse, corev1.ConditionUnknown, "RevisionFailed", tc.message)}
+			// Recover only after the error window
+			time.Sleep(100 * time.Millisecond)
+			fakeWatchApi.eventChan <- watch.Event{Type: watch.Modified,
+				Object: CreateTestServiceWithConditions("foo", corev1.ConditionTrue, corev1.ConditionTrue, "", "")}
+		}()
+		err, _ := NewWaitForReady("service", extractor).Wait(fakeWatchApi, "foo", options, NoopMessageCallback())
+		if tc.errorText == "" {
+			assert.NilError(t, err)
+		} else {
+			assert.ErrorContains(t, err, tc.errorText)
+			// Drain the recovery event
+			<-fakeWatchApi.eventChan
+		}
+	}
+}
+
+func TestLogWatchEvent(t *testing.T) {
+	klogFlags := flag.NewFlagSet("klog", flag.ContinueOnError)
+	klog.InitFlags(klogFlags)
+	assert.NilError(t, klogFlags.Set("logtostderr", "false"))
+	defer klogFlags.Set("logtostderr", "true")
+	defer util.SetLogVerbosity(0)
+	output := new(bytes.Buffer)
+	klog.SetOutput(output)
+
+	service := &servingv1.Service{}
+	service.Generation = 2
+	service.ResourceVersion = "42"
+	event := watch.Event{Type: watch.Modified, Object: service}
+
+	logWatchEvent("service", "foo", event)
+	klog.Flush()
+	assert.Equal(t, output.String(), "")
+
+	assert.NilError(t, util.SetLogVerbosity(util.LogLevelWatch))
+	logWatchEvent("service", "foo", event)
+	klog.Flush()
+	assert.Assert(t, cmp.Contains(output.String(), `"Received watch event" kind="service" name="foo" type="MODIFIED" generation=2 resourceVersion="42"`))
+}
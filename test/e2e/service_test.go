@@ -161,7 +161,7 @@ func serviceDeleteAll(r *test.KnRunResultCollector) {
 	// Check if services created successfully/available for test.
 	assert.Check(r.T(), !strings.Contains(out.Stdout, "No services found."), "No services created for kn service delete --all e2e (but should exist)")
 
-	out = r.KnTest().Kn().Run("services", "delete", "--all")
+	out = r.KnTest().Kn().Run("services", "delete", "--all", "--yes")
 	r.AssertNoError(out)
 	// Check if output contains successfully deleted to verify deletion took place.
 	assert.Check(r.T(), strings.Contains(out.Stdout, "successfully deleted"), "Failed to get 'successfully deleted' message")